@@ -49,7 +49,18 @@ func main() {
 	donationRepo := repository.NewPgDonationRepository(pool)
 	activityRepo := repository.NewPgActivityRepository(pool)
 	costPresetRepo := repository.NewPgCostPresetRepository(pool)
+	projectCategoryRepo := repository.NewPgProjectCategoryRepository(pool)
+	projectMemberRepo := repository.NewPgProjectMemberRepository(pool)
 	sessionRepo := repository.NewPgSessionRepository(pool)
+	webhookEventRepo := repository.NewPgWebhookEventRepository(pool)
+	donationPaymentRepo := repository.NewPgDonationPaymentRepository(pool)
+	checkoutSessionRepo := repository.NewPgCheckoutSessionRepository(pool)
+	manualDonationRepo := repository.NewPgManualDonationRepository(pool)
+	receiptRepo := repository.NewPgReceiptRepository(pool)
+	matchingRepo := repository.NewPgMatchingRepository(pool)
+	financeRepo := repository.NewPgFinanceRepository(pool)
+	donationChangeRepo := repository.NewPgDonationChangeRepository(pool)
+	upstreamRepo := repository.NewPgUpstreamRepository(pool)
 
 	authService := service.NewAuthService(userRepo)
 	projectService := service.NewProjectService(projectRepo)
@@ -64,11 +75,39 @@ func main() {
 		os.Getenv("STRIPE_SECRET_KEY"),
 		os.Getenv("STRIPE_WEBHOOK_SECRET"),
 	)
+	// 寄付者が決済手数料を負担する場合の手数料表（例: "jpy:3.6%,usd:2.9%+30"。未設定なら国内カードの 3.6%）
+	feeSchedule, err := pkgstripe.ParseFeeSchedule(os.Getenv("STRIPE_FEE_SCHEDULE"))
+	if err != nil {
+		logging.Fatal("invalid STRIPE_FEE_SCHEDULE", "error", err)
+	}
 	activityService := service.NewActivityService(activityRepo)
 	milestoneService := service.NewMilestoneService(projectRepo, donationRepo, activityRepo)
-	stripeService := service.NewStripeServiceWithActivity(stripeClient, projectRepo, donationRepo, frontendURL, activityRepo, milestoneService)
-	donationService := service.NewDonationService(donationRepo, stripeClient)
+	matchingService := service.NewMatchingService(matchingRepo, projectRepo, userRepo, activityRepo)
+	// 失敗した上流プロジェクトへの送金は cmd/pass-through-transfers で再送する
+	upstreamService := service.NewUpstreamService(upstreamRepo, projectRepo, stripeClient, activityRepo)
+	stripeService := service.NewStripeServiceWithActivity(stripeClient, projectRepo, donationRepo, frontendURL, activityRepo, milestoneService,
+		service.WithWebhookEventStore(webhookEventRepo),
+		service.WithPaymentLedger(donationPaymentRepo),
+		service.WithMatching(matchingService),
+		service.WithCheckoutSessionStore(checkoutSessionRepo),
+		service.WithFeeSchedule(feeSchedule),
+		service.WithCustomerStore(userRepo),
+		service.WithChangeLog(donationChangeRepo),
+		service.WithUpstreamSplit(upstreamService),
+	)
+	donationService := service.NewDonationService(donationRepo, stripeClient,
+		service.WithBillingReturnURL(frontendURL+"/me"),
+		service.WithDonationFeeSchedule(feeSchedule),
+		service.WithDonationChangeLog(donationChangeRepo),
+		service.WithProjectStripeAccounts(projectRepo),
+	)
 	costPresetService := service.NewCostPresetService(costPresetRepo)
+	projectCategoryService := service.NewProjectCategoryService(projectCategoryRepo)
+	projectMemberService := service.NewProjectMemberService(projectMemberRepo, userRepo)
+	manualDonationService := service.NewManualDonationService(manualDonationRepo, userRepo, activityRepo, milestoneService)
+	receiptService := service.NewReceiptService(receiptRepo, userRepo)
+	// 手数料・入金の同期は cmd/finance-sync で行い、サーバーは集計結果を返すだけ
+	financeService := service.NewFinanceService(stripeClient, projectRepo, donationPaymentRepo, financeRepo)
 
 	authRequired := os.Getenv("AUTH_REQUIRED") == "true"
 	hostEmails := auth.ParseHostEmails(os.Getenv("HOST_EMAILS"))
@@ -104,7 +143,12 @@ func main() {
 		connectAccountFunc = stripeService.CreateAccountAndOnboarding
 	}
 	stripeHandler := handler.NewStripeHandler(stripeService, frontendURL, sessionSvc)
+	stripeHandler.SetProjectService(projectService)
 	projectHandler := handler.NewProjectHandlerWithActivity(projectService, connectAccountFunc, activityService)
+	if connectAccountFunc != nil {
+		projectHandler.SetOnboardingLinkFunc(stripeService.RefreshOnboarding)
+	}
+	projectHandler.SetMatchingLister(matchingService)
 	contactHandler := handler.NewContactHandler(contactService)
 	legalHandler := handler.NewLegalHandler(handler.LegalConfig{DocsDir: legalDocsDir})
 	watchHandler := handler.NewWatchHandler(watchService)
@@ -114,8 +158,17 @@ func main() {
 	donationHandler := handler.NewDonationHandler(donationService)
 	activityHandler := handler.NewActivityHandler(activityService)
 	chartHandler := handler.NewChartHandler(projectService, donationRepo)
+	chartHandler.SetMatchingSource(matchingService)
+	chartHandler.SetPassThroughSource(upstreamService)
+	financeHandler := handler.NewFinanceHandler(projectService, financeService)
 	costPresetHandler := handler.NewCostPresetHandler(costPresetService)
+	projectCategoryHandler := handler.NewProjectCategoryHandler(projectCategoryService)
+	projectMemberHandler := handler.NewProjectMemberHandler(projectMemberService, projectService)
 	messageHandler := handler.NewMessageHandler(donationService, projectService)
+	manualDonationHandler := handler.NewManualDonationHandler(manualDonationService, projectService)
+	receiptHandler := handler.NewReceiptHandler(receiptService, handler.ReceiptConfig{LegalDocsDir: legalDocsDir})
+	matchingHandler := handler.NewMatchingHandler(matchingService, projectService)
+	upstreamHandler := handler.NewUpstreamHandler(upstreamService, projectService)
 
 	uploadsDir := os.Getenv("UPLOADS_DIR")
 	if uploadsDir == "" {
@@ -141,6 +194,9 @@ func main() {
 
 	// プロジェクト API（一覧・詳細は認証不要）
 	mux.Handle("GET /api/projects", http.HandlerFunc(projectHandler.List))
+	mux.Handle("GET /api/projects/search", http.HandlerFunc(projectHandler.Search))
+	mux.Handle("GET /api/projects/facets", http.HandlerFunc(projectHandler.Facets))
+	mux.Handle("GET /api/project-categories", http.HandlerFunc(projectCategoryHandler.List))
 	mux.Handle("GET /api/projects/{id}", http.HandlerFunc(projectHandler.Get))
 
 	// 認証必要エンドポイント
@@ -162,9 +218,21 @@ func main() {
 	mux.Handle("PUT /api/projects/{id}", wrapAuth(http.HandlerFunc(projectHandler.Update)))
 	mux.Handle("DELETE /api/projects/{id}", wrapAuth(http.HandlerFunc(projectHandler.Delete)))
 	mux.Handle("PATCH /api/projects/{id}/status", wrapAuth(http.HandlerFunc(projectHandler.PatchStatus)))
+	mux.HandleFunc("GET /api/projects/{id}/revisions", projectHandler.Revisions)
+	mux.Handle("POST /api/projects/{id}/revisions/{rid}/restore", wrapAuth(http.HandlerFunc(projectHandler.RestoreRevision)))
 	mux.Handle("POST /api/projects/{id}/image", wrapAuth(http.HandlerFunc(imageHandler.Upload)))
 	mux.Handle("DELETE /api/projects/{id}/image", wrapAuth(http.HandlerFunc(imageHandler.Delete)))
 
+	// プロジェクトのメンバーと招待（管理は owner、一覧はメンバーとホスト、応答は招待された本人）
+	mux.Handle("GET /api/projects/{id}/members", wrapAuth(http.HandlerFunc(projectMemberHandler.List)))
+	mux.Handle("PATCH /api/projects/{id}/members/{uid}", wrapAuth(http.HandlerFunc(projectMemberHandler.UpdateRole)))
+	mux.Handle("DELETE /api/projects/{id}/members/{uid}", wrapAuth(http.HandlerFunc(projectMemberHandler.Remove)))
+	mux.Handle("POST /api/projects/{id}/invitations", wrapAuth(http.HandlerFunc(projectMemberHandler.Invite)))
+	mux.Handle("DELETE /api/projects/{id}/invitations/{iid}", wrapAuth(http.HandlerFunc(projectMemberHandler.RevokeInvitation)))
+	mux.Handle("GET /api/me/project-invitations", wrapAuth(http.HandlerFunc(projectMemberHandler.MyInvitations)))
+	mux.Handle("POST /api/me/project-invitations/{iid}/accept", wrapAuth(http.HandlerFunc(projectMemberHandler.Accept)))
+	mux.Handle("POST /api/me/project-invitations/{iid}/decline", wrapAuth(http.HandlerFunc(projectMemberHandler.Decline)))
+
 	// プロジェクト更新 API
 	mux.Handle("GET /api/projects/{id}/updates", http.HandlerFunc(updateHandler.List))
 	mux.Handle("POST /api/projects/{id}/updates", wrapAuth(http.HandlerFunc(updateHandler.Create)))
@@ -180,8 +248,13 @@ func main() {
 	mux.Handle("GET /api/admin/contacts", wrapAuth(http.HandlerFunc(contactHandler.AdminList)))
 	mux.Handle("PATCH /api/admin/contacts/{id}/status", wrapAuth(http.HandlerFunc(contactHandler.UpdateStatus)))
 	mux.Handle("GET /api/admin/users", wrapAuth(http.HandlerFunc(adminUserHandler.List)))
+	mux.Handle("POST /api/admin/project-categories", wrapAuth(http.HandlerFunc(projectCategoryHandler.Create)))
+	mux.Handle("PUT /api/admin/project-categories/{slug}", wrapAuth(http.HandlerFunc(projectCategoryHandler.Update)))
+	mux.Handle("DELETE /api/admin/project-categories/{slug}", wrapAuth(http.HandlerFunc(projectCategoryHandler.Delete)))
 	mux.Handle("PATCH /api/admin/users/{id}/suspend", wrapAuth(http.HandlerFunc(adminUserHandler.Suspend)))
 	mux.Handle("GET /api/admin/disclosure-export", wrapAuth(http.HandlerFunc(adminUserHandler.DisclosureExport)))
+	mux.Handle("GET /api/admin/webhook-events", wrapAuth(http.HandlerFunc(stripeHandler.AdminListWebhookEvents)))
+	mux.Handle("POST /api/admin/webhook-events/{id}/replay", wrapAuth(http.HandlerFunc(stripeHandler.AdminReplayWebhookEvent)))
 
 	// Platform health (no auth required)
 	mux.HandleFunc("GET /api/host", hostHandler.Get)
@@ -190,15 +263,44 @@ func main() {
 	mux.HandleFunc("GET /api/activity", activityHandler.GlobalFeed)
 	mux.HandleFunc("GET /api/projects/{id}/activity", activityHandler.ProjectFeed)
 	mux.HandleFunc("GET /api/projects/{id}/chart", chartHandler.Chart)
+	mux.HandleFunc("GET /api/projects/{id}/finances", financeHandler.Finances)
 
-	// Project messages (owner or host auth required)
+	// Project messages (project maintainer or host auth required)
 	mux.Handle("GET /api/projects/{id}/messages", wrapAuth(http.HandlerFunc(messageHandler.List)))
+	mux.Handle("GET /api/projects/{id}/donation-changes", wrapAuth(http.HandlerFunc(messageHandler.Changes)))
+
+	// Manual donations — bank transfer / cash (project maintainer or host auth required)
+	mux.Handle("GET /api/projects/{id}/manual-donations", wrapAuth(http.HandlerFunc(manualDonationHandler.List)))
+	mux.Handle("POST /api/projects/{id}/manual-donations", wrapAuth(http.HandlerFunc(manualDonationHandler.Create)))
+	mux.Handle("PATCH /api/projects/{id}/manual-donations/{did}", wrapAuth(http.HandlerFunc(manualDonationHandler.Patch)))
+	mux.Handle("POST /api/projects/{id}/manual-donations/{did}/void", wrapAuth(http.HandlerFunc(manualDonationHandler.Void)))
+	mux.Handle("GET /api/projects/{id}/manual-donations/{did}/audit", wrapAuth(http.HandlerFunc(manualDonationHandler.Audit)))
+
+	// Matching-gift campaigns (project maintainer or host auth required; the sponsor summary is public with its token)
+	mux.Handle("GET /api/projects/{id}/matching-campaigns", wrapAuth(http.HandlerFunc(matchingHandler.List)))
+	mux.Handle("POST /api/projects/{id}/matching-campaigns", wrapAuth(http.HandlerFunc(matchingHandler.Create)))
+	mux.Handle("POST /api/projects/{id}/matching-campaigns/{cid}/cancel", wrapAuth(http.HandlerFunc(matchingHandler.Cancel)))
+	mux.Handle("GET /api/projects/{id}/matching-campaigns/{cid}/summary", wrapAuth(http.HandlerFunc(matchingHandler.Summary)))
+	mux.HandleFunc("GET /api/matching-campaigns/{id}/summary", matchingHandler.SponsorSummary)
+
+	// Upstream projects and passed-through support (the list is public; changes and transfers need project maintainer or host auth)
+	mux.HandleFunc("GET /api/projects/{id}/upstreams", upstreamHandler.List)
+	mux.Handle("PUT /api/projects/{id}/upstreams", wrapAuth(http.HandlerFunc(upstreamHandler.Replace)))
+	mux.Handle("GET /api/projects/{id}/pass-throughs", wrapAuth(http.HandlerFunc(upstreamHandler.PassThroughs)))
+
+	// Checkout abandonment stats (project maintainer or host auth required)
+	mux.Handle("GET /api/projects/{id}/checkout-stats", wrapAuth(http.HandlerFunc(stripeHandler.CheckoutStats)))
 
 	// Donation routes (auth required)
 	mux.Handle("GET /api/me/donations", wrapAuth(http.HandlerFunc(donationHandler.List)))
 	mux.Handle("PATCH /api/me/donations/{id}", wrapAuth(http.HandlerFunc(donationHandler.Patch)))
 	mux.Handle("DELETE /api/me/donations/{id}", wrapAuth(http.HandlerFunc(donationHandler.Delete)))
+	mux.Handle("POST /api/me/donations/{id}/payment-method", wrapAuth(http.HandlerFunc(donationHandler.PaymentMethodSession)))
+	mux.Handle("GET /api/me/donations/{id}/history", wrapAuth(http.HandlerFunc(donationHandler.History)))
+	mux.Handle("POST /api/me/billing-portal", wrapAuth(http.HandlerFunc(stripeHandler.BillingPortal)))
 	mux.Handle("POST /api/me/migrate-from-token", wrapAuth(http.HandlerFunc(donationHandler.MigrateFromToken)))
+	mux.Handle("GET /api/me/donations/{id}/receipt", wrapAuth(http.HandlerFunc(receiptHandler.DonationReceipt)))
+	mux.Handle("GET /api/me/statements/{year}", wrapAuth(http.HandlerFunc(receiptHandler.Statement)))
 
 	// Cost preset routes (auth required)
 	mux.Handle("GET /api/me/cost-presets", wrapAuth(http.HandlerFunc(costPresetHandler.List)))
@@ -213,15 +315,17 @@ func main() {
 	// Rate limit on checkout endpoint (10 req/min per IP)
 	checkoutRL := handler.NewRateLimiter(10)
 	mux.Handle("POST /api/donations/checkout", checkoutRL.Middleware(http.HandlerFunc(stripeHandler.Checkout)))
+	// 決済後のリダイレクト先から寄付の記録状況をポーリングする（Session ID を知っている本人のみ）
+	mux.HandleFunc("GET /api/donations/checkout/{session_id}", stripeHandler.CheckoutStatus)
 	mux.HandleFunc("POST /api/webhooks/stripe", stripeHandler.Webhook)
 
 	// 画像ファイルの静的配信
 	mux.Handle("/uploads/", http.StripPrefix("/uploads/", http.FileServer(http.Dir(uploadsDir))))
 
-	// Middleware chain: RequestLogger → SecurityHeaders → CORS → mux
+	// Middleware chain: RequestLogger → SecurityHeaders → CORS → ResolveProjectSlugs → mux
 	server := &http.Server{
 		Addr:         ":8080",
-		Handler:      handler.RequestLogger(handler.SecurityHeaders(h.CORS(mux))),
+		Handler:      handler.RequestLogger(handler.SecurityHeaders(h.CORS(handler.ResolveProjectSlugs(projectRepo, mux)))),
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  120 * time.Second,
@@ -202,15 +202,19 @@ func (h *AdminUserHandler) DisclosureExport(w http.ResponseWriter, r *http.Reque
 		if donations == nil {
 			donations = []*model.Donation{}
 		}
+		// 手動記録の寄付（銀行振込・現金など）を区別できるよう支払い方法ごとの合計も返す
 		var total int
+		totalsByMethod := map[string]int{}
 		for _, d := range donations {
-			total += d.Amount
+			total += d.NetAmount()
+			totalsByMethod[d.PaymentMethod] += d.NetAmount()
 		}
 		_ = json.NewEncoder(w).Encode(map[string]any{
-			"project_id":   id,
-			"project_name": project.Name,
-			"donations":    donations,
-			"total":        total,
+			"project_id":               id,
+			"project_name":             project.Name,
+			"donations":                donations,
+			"total":                    total,
+			"totals_by_payment_method": totalsByMethod,
 		})
 
 	default:
@@ -49,9 +49,15 @@ type mockProjectServiceForAdmin struct {
 	getByIDFunc func(ctx context.Context, id string) (*model.Project, error)
 }
 
-func (m *mockProjectServiceForAdmin) List(ctx context.Context, sort string, limit int, cursor string) (*model.ProjectListResult, error) {
+func (m *mockProjectServiceForAdmin) List(ctx context.Context, sort string, filter model.ProjectFilter, limit int, cursor string) (*model.ProjectListResult, error) {
 	return &model.ProjectListResult{}, nil
 }
+func (m *mockProjectServiceForAdmin) Facets(ctx context.Context, filter model.ProjectFilter) (*model.ProjectFacets, error) {
+	return &model.ProjectFacets{}, nil
+}
+func (m *mockProjectServiceForAdmin) Search(ctx context.Context, query, sort string, limit int, cursor string) (*model.ProjectSearchResult, error) {
+	return &model.ProjectSearchResult{}, nil
+}
 func (m *mockProjectServiceForAdmin) GetByID(ctx context.Context, id string) (*model.Project, error) {
 	if m.getByIDFunc != nil {
 		return m.getByIDFunc(ctx, id)
@@ -65,6 +71,15 @@ func (m *mockProjectServiceForAdmin) Create(ctx context.Context, p *model.Projec
 func (m *mockProjectServiceForAdmin) Update(ctx context.Context, p *model.Project) error {
 	return nil
 }
+func (m *mockProjectServiceForAdmin) UpdateWithRevision(ctx context.Context, actorID string, p *model.Project) error {
+	return nil
+}
+func (m *mockProjectServiceForAdmin) ListRevisions(ctx context.Context, projectID string) ([]*model.ProjectRevision, error) {
+	return nil, nil
+}
+func (m *mockProjectServiceForAdmin) RestoreRevision(ctx context.Context, actorID, projectID, revisionID string) (*model.Project, error) {
+	return nil, nil
+}
 func (m *mockProjectServiceForAdmin) Delete(ctx context.Context, id string) error {
 	return nil
 }
@@ -465,6 +480,51 @@ func TestAdminUserHandler_DisclosureExport_DonationType_Success(t *testing.T) {
 	}
 }
 
+func TestAdminUserHandler_DisclosureExport_DonationType_ManualDonations(t *testing.T) {
+	voidedAt := time.Now()
+	mockProject := &mockProjectServiceForAdmin{
+		getByIDFunc: func(_ context.Context, id string) (*model.Project, error) {
+			return &model.Project{ID: "p1", Name: "Test Project"}, nil
+		},
+	}
+	mockDonation := &mockDonationLister{
+		listByProjectFunc: func(_ context.Context, projectID string, _, _ int) ([]*model.Donation, error) {
+			return []*model.Donation{
+				{ID: "d1", ProjectID: "p1", Amount: 1000, PaymentMethod: "stripe"},
+				{ID: "d2", ProjectID: "p1", Amount: 5000, PaymentMethod: "bank_transfer", Reference: "0012"},
+				{ID: "d3", ProjectID: "p1", Amount: 3000, PaymentMethod: "cash", VoidedAt: &voidedAt},
+			}, nil
+		},
+	}
+	h := NewAdminUserHandler(&mockAdminUserService{}, mockProject, mockDonation)
+
+	req := adminHostRequest(http.MethodGet, "/api/admin/disclosure-export?type=donation&id=p1", "")
+	rec := httptest.NewRecorder()
+	h.DisclosureExport(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Donations      []*model.Donation `json:"donations"`
+		Total          int               `json:"total"`
+		TotalsByMethod map[string]int    `json:"totals_by_payment_method"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	// 取り消した手動記録の寄付は合計に含めない
+	if resp.Total != 6000 {
+		t.Errorf("expected total=6000, got %d", resp.Total)
+	}
+	if resp.TotalsByMethod["stripe"] != 1000 || resp.TotalsByMethod["bank_transfer"] != 5000 || resp.TotalsByMethod["cash"] != 0 {
+		t.Errorf("totals_by_payment_method = %v", resp.TotalsByMethod)
+	}
+	if resp.Donations[1].PaymentMethod != "bank_transfer" || resp.Donations[1].Reference != "0012" || resp.Donations[2].VoidedAt == nil {
+		t.Errorf("manual donations are not labelled: %+v %+v", resp.Donations[1], resp.Donations[2])
+	}
+}
+
 func TestAdminUserHandler_DisclosureExport_DonationType_ProjectNotFound(t *testing.T) {
 	mockProject := &mockProjectServiceForAdmin{
 		getByIDFunc: func(_ context.Context, _ string) (*model.Project, error) {
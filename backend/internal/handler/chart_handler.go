@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"log/slog"
 	"net/http"
+	"slices"
 
 	"github.com/givers/backend/internal/model"
 	"github.com/givers/backend/internal/service"
@@ -15,10 +16,23 @@ type ChartDonationService interface {
 	MonthlySumByProject(ctx context.Context, projectID string) ([]*model.MonthlySum, error)
 }
 
+// ChartMatchingSource is the subset of MatchingService needed for chart data.
+type ChartMatchingSource interface {
+	MonthlyMatchedByProject(ctx context.Context, projectID string) ([]*model.MonthlySum, error)
+}
+
+// ChartPassThroughSource is the subset of UpstreamService needed for chart data.
+type ChartPassThroughSource interface {
+	MonthlyForwardedByProject(ctx context.Context, projectID string) ([]*model.MonthlySum, error)
+	MonthlyReceivedByProject(ctx context.Context, projectID string) ([]*model.MonthlySum, error)
+}
+
 // ChartHandler handles GET /api/projects/{id}/chart.
 type ChartHandler struct {
-	projectSvc service.ProjectService
+	projectSvc  service.ProjectService
 	donationSvc ChartDonationService
+	matching    ChartMatchingSource    // optional, nil = no matched amounts
+	passThrough ChartPassThroughSource // optional, nil = no passed-through support
 }
 
 // NewChartHandler creates a ChartHandler.
@@ -26,6 +40,16 @@ func NewChartHandler(projectSvc service.ProjectService, donationSvc ChartDonatio
 	return &ChartHandler{projectSvc: projectSvc, donationSvc: donationSvc}
 }
 
+// SetMatchingSource adds the amounts matched by matching-gift campaigns to the chart.
+func (h *ChartHandler) SetMatchingSource(src ChartMatchingSource) {
+	h.matching = src
+}
+
+// SetPassThroughSource adds the support forwarded to and received from other projects to the chart.
+func (h *ChartHandler) SetPassThroughSource(src ChartPassThroughSource) {
+	h.passThrough = src
+}
+
 // Chart handles GET /api/projects/{id}/chart.
 func (h *ChartHandler) Chart(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -59,14 +83,63 @@ func (h *ChartHandler) Chart(w http.ResponseWriter, r *http.Request) {
 		sumMap[s.Month] = s.Amount
 	}
 
-	// Build chart data points from sums (only months with data)
-	points := make([]*model.ChartDataPoint, 0, len(sums))
+	// Build lookup from monthly matched sums
+	matchedMap := map[string]int{}
+	if h.matching != nil {
+		matched, err := h.matching.MonthlyMatchedByProject(r.Context(), projectID)
+		if err != nil {
+			slog.Error("chart matching data failed", "error", err, "project_id", projectID)
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "chart_failed"})
+			return
+		}
+		for _, s := range matched {
+			matchedMap[s.Month] = s.Amount
+		}
+	}
+
+	// Build lookups from passed-through support
+	forwardedMap, receivedMap := map[string]int{}, map[string]int{}
+	months := make([]string, 0, len(sums))
 	for _, s := range sums {
+		months = append(months, s.Month)
+	}
+	if h.passThrough != nil {
+		forwarded, err := h.passThrough.MonthlyForwardedByProject(r.Context(), projectID)
+		if err == nil {
+			var received []*model.MonthlySum
+			received, err = h.passThrough.MonthlyReceivedByProject(r.Context(), projectID)
+			for _, s := range received {
+				receivedMap[s.Month] = s.Amount
+				// An upstream project can receive support in months without direct donations
+				if _, ok := sumMap[s.Month]; !ok {
+					months = append(months, s.Month)
+				}
+			}
+		}
+		if err != nil {
+			slog.Error("chart pass-through data failed", "error", err, "project_id", projectID)
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "chart_failed"})
+			return
+		}
+		for _, s := range forwarded {
+			forwardedMap[s.Month] = s.Amount
+		}
+		slices.Sort(months)
+	}
+
+	// Build chart data points (only months with data)
+	points := make([]*model.ChartDataPoint, 0, len(months))
+	for _, month := range months {
 		points = append(points, &model.ChartDataPoint{
-			Month:        s.Month,
-			MinAmount:    minAmount,
-			TargetAmount: targetAmount,
-			ActualAmount: s.Amount,
+			Month:                month,
+			MinAmount:            minAmount,
+			TargetAmount:         targetAmount,
+			ActualAmount:         sumMap[month],
+			MatchedAmount:        matchedMap[month],
+			PassThroughInAmount:  receivedMap[month],
+			PassThroughOutAmount: forwardedMap[month],
 		})
 	}
 
@@ -158,3 +158,96 @@ func TestChartHandler_Chart_DonationError(t *testing.T) {
 		t.Errorf("expected 500, got %d", rec.Code)
 	}
 }
+
+func TestChartHandler_Chart_MatchedAmount(t *testing.T) {
+	projectMock := &mockProjectService{
+		getByIDFunc: func(ctx context.Context, id string) (*model.Project, error) {
+			return &model.Project{ID: id, MonthlyTarget: 10000}, nil
+		},
+	}
+	donationMock := &mockChartDonationService{
+		monthlySumFunc: func(ctx context.Context, projectID string) ([]*model.MonthlySum, error) {
+			return []*model.MonthlySum{{Month: "2026-02", Amount: 3000}, {Month: "2026-03", Amount: 5000}}, nil
+		},
+	}
+	h := NewChartHandler(projectMock, donationMock)
+	h.SetMatchingSource(&mockMatchingService{
+		monthlyFunc: func(ctx context.Context, projectID string) ([]*model.MonthlySum, error) {
+			return []*model.MonthlySum{{Month: "2026-03", Amount: 2500}}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/projects/p1/chart", nil)
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.Chart(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var resp struct {
+		Chart []*model.ChartDataPoint `json:"chart"`
+	}
+	_ = json.NewDecoder(rec.Body).Decode(&resp)
+	if len(resp.Chart) != 2 {
+		t.Fatalf("expected 2 chart points, got %d", len(resp.Chart))
+	}
+	if resp.Chart[0].MatchedAmount != 0 || resp.Chart[1].MatchedAmount != 2500 || resp.Chart[1].ActualAmount != 5000 {
+		t.Errorf("unexpected chart: %+v %+v", resp.Chart[0], resp.Chart[1])
+	}
+}
+
+type mockChartPassThroughSource struct {
+	forwarded []*model.MonthlySum
+	received  []*model.MonthlySum
+}
+
+func (m *mockChartPassThroughSource) MonthlyForwardedByProject(ctx context.Context, projectID string) ([]*model.MonthlySum, error) {
+	return m.forwarded, nil
+}
+
+func (m *mockChartPassThroughSource) MonthlyReceivedByProject(ctx context.Context, projectID string) ([]*model.MonthlySum, error) {
+	return m.received, nil
+}
+
+func TestChartHandler_Chart_PassThrough(t *testing.T) {
+	projectMock := &mockProjectService{
+		getByIDFunc: func(ctx context.Context, id string) (*model.Project, error) {
+			return &model.Project{ID: id, MonthlyTarget: 10000}, nil
+		},
+	}
+	donationMock := &mockChartDonationService{
+		monthlySumFunc: func(ctx context.Context, projectID string) ([]*model.MonthlySum, error) {
+			return []*model.MonthlySum{{Month: "2026-03", Amount: 5000}}, nil
+		},
+	}
+	h := NewChartHandler(projectMock, donationMock)
+	h.SetPassThroughSource(&mockChartPassThroughSource{
+		forwarded: []*model.MonthlySum{{Month: "2026-03", Amount: 500}},
+		received:  []*model.MonthlySum{{Month: "2026-02", Amount: 300}, {Month: "2026-03", Amount: 200}},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/projects/p1/chart", nil)
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.Chart(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var resp struct {
+		Chart []*model.ChartDataPoint `json:"chart"`
+	}
+	_ = json.NewDecoder(rec.Body).Decode(&resp)
+	// 直接の寄付がない月も、受け取った分配があれば含める
+	if len(resp.Chart) != 2 {
+		t.Fatalf("expected 2 chart points, got %d", len(resp.Chart))
+	}
+	feb, mar := resp.Chart[0], resp.Chart[1]
+	if feb.Month != "2026-02" || feb.ActualAmount != 0 || feb.PassThroughInAmount != 300 || feb.TargetAmount != 10000 {
+		t.Errorf("unexpected February: %+v", feb)
+	}
+	if mar.Month != "2026-03" || mar.ActualAmount != 5000 || mar.PassThroughInAmount != 200 || mar.PassThroughOutAmount != 500 {
+		t.Errorf("unexpected March: %+v", mar)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/givers/backend/internal/repository"
 	"github.com/givers/backend/internal/service"
 	"github.com/givers/backend/pkg/auth"
+	pkgstripe "github.com/givers/backend/pkg/stripe"
 )
 
 // DonationHandler handles donation-related endpoints.
@@ -85,6 +86,9 @@ func (h *DonationHandler) Patch(w http.ResponseWriter, r *http.Request) {
 			_ = json.NewEncoder(w).Encode(map[string]string{"error": "not_found"})
 			return
 		}
+		if writeStripeError(w, err) {
+			return
+		}
 		slog.Error("donation patch failed", "error", err, "donation_id", id)
 		w.WriteHeader(http.StatusInternalServerError)
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "patch_failed"})
@@ -118,6 +122,9 @@ func (h *DonationHandler) Delete(w http.ResponseWriter, r *http.Request) {
 			_ = json.NewEncoder(w).Encode(map[string]string{"error": "not_found"})
 			return
 		}
+		if writeStripeError(w, err) {
+			return
+		}
 		slog.Error("donation delete failed", "error", err, "donation_id", id)
 		w.WriteHeader(http.StatusInternalServerError)
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "delete_failed"})
@@ -127,6 +134,93 @@ func (h *DonationHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(map[string]bool{"ok": true})
 }
 
+// PaymentMethodSession handles POST /api/me/donations/:id/payment-method (auth required).
+// Returns a Stripe-hosted URL where the donor can update the card of a recurring donation.
+func (h *DonationHandler) PaymentMethodSession(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "unauthorized"})
+		return
+	}
+
+	id := r.PathValue("id")
+
+	url, err := h.svc.CreatePaymentMethodSession(r.Context(), id, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "forbidden"})
+			return
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "not_found"})
+			return
+		}
+		if errors.Is(err, service.ErrNotRecurring) {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "not_recurring"})
+			return
+		}
+		if errors.Is(err, service.ErrBillingNotConfigured) || errors.Is(err, pkgstripe.ErrNotConfigured) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "billing_not_configured"})
+			return
+		}
+		if writeStripeError(w, err) {
+			return
+		}
+		slog.Error("donation payment method session failed", "error", err, "donation_id", id)
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "session_failed"})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]string{"url": url})
+}
+
+// History handles GET /api/me/donations/:id/history (auth required).
+// Returns when and why the donation's amount, paused state, scheduled cancellation
+// or payment status changed, including changes made on Stripe's side.
+func (h *DonationHandler) History(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "unauthorized"})
+		return
+	}
+
+	id := r.PathValue("id")
+
+	changes, err := h.svc.ListChanges(r.Context(), id, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "forbidden"})
+			return
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "not_found"})
+			return
+		}
+		slog.Error("donation history failed", "error", err, "donation_id", id)
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "list_failed"})
+		return
+	}
+	if changes == nil {
+		changes = []*model.DonationChange{}
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]any{"changes": changes})
+}
+
 // MigrateFromToken handles POST /api/me/migrate-from-token (auth required).
 // Reads donor_token from Cookie and migrates anonymous donations to the current user.
 func (h *DonationHandler) MigrateFromToken(w http.ResponseWriter, r *http.Request) {
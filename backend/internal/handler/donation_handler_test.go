@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	"github.com/givers/backend/internal/repository"
 	"github.com/givers/backend/internal/service"
 	"github.com/givers/backend/pkg/auth"
+	pkgstripe "github.com/givers/backend/pkg/stripe"
 )
 
 // ---------------------------------------------------------------------------
@@ -26,6 +28,10 @@ type mockDonationService struct {
 	deleteFunc           func(ctx context.Context, id, userID string) error
 	migrateFunc          func(ctx context.Context, token, userID string) (*service.MigrateTokenResult, error)
 	listProjectMsgsFunc  func(ctx context.Context, projectID string, limit, offset int, sort, donor string) (*model.DonationMessageResult, error)
+	paymentMethodFunc    func(ctx context.Context, id, userID string) (string, error)
+	tierBreakdownFunc    func(ctx context.Context, project *model.Project) ([]*model.DonationTierStat, error)
+	listChangesFunc      func(ctx context.Context, id, userID string) ([]*model.DonationChange, error)
+	listProjectChgsFunc  func(ctx context.Context, projectID string, limit, offset int) ([]*model.DonationChange, error)
 }
 
 func (m *mockDonationService) ListByUser(ctx context.Context, userID string, limit, offset int) ([]*model.Donation, error) {
@@ -58,6 +64,30 @@ func (m *mockDonationService) ListProjectMessages(ctx context.Context, projectID
 	}
 	return &model.DonationMessageResult{Messages: []*model.DonationMessage{}, Total: 0}, nil
 }
+func (m *mockDonationService) TierBreakdown(ctx context.Context, project *model.Project) ([]*model.DonationTierStat, error) {
+	if m.tierBreakdownFunc != nil {
+		return m.tierBreakdownFunc(ctx, project)
+	}
+	return []*model.DonationTierStat{}, nil
+}
+func (m *mockDonationService) CreatePaymentMethodSession(ctx context.Context, id, userID string) (string, error) {
+	if m.paymentMethodFunc != nil {
+		return m.paymentMethodFunc(ctx, id, userID)
+	}
+	return "", nil
+}
+func (m *mockDonationService) ListChanges(ctx context.Context, id, userID string) ([]*model.DonationChange, error) {
+	if m.listChangesFunc != nil {
+		return m.listChangesFunc(ctx, id, userID)
+	}
+	return nil, nil
+}
+func (m *mockDonationService) ListProjectChanges(ctx context.Context, projectID string, limit, offset int) ([]*model.DonationChange, error) {
+	if m.listProjectChgsFunc != nil {
+		return m.listProjectChgsFunc(ctx, projectID, limit, offset)
+	}
+	return nil, nil
+}
 // helper: auth request for regular user
 func userAuthRequest(method, url, body string) *http.Request {
 	var r *http.Request
@@ -219,6 +249,26 @@ func TestDonationHandler_Patch_Forbidden(t *testing.T) {
 	}
 }
 
+func TestDonationHandler_Patch_StripeRateLimited(t *testing.T) {
+	mock := &mockDonationService{
+		patchFunc: func(ctx context.Context, id, userID string, patch model.DonationPatch) error {
+			return fmt.Errorf("stripe pause: %w", &pkgstripe.Error{HTTPStatus: 429, Type: pkgstripe.ErrorTypeRateLimit})
+		},
+	}
+	h := NewDonationHandler(mock)
+
+	req := userAuthRequest(http.MethodPatch, "/api/me/donations/d1", `{"paused":true}`)
+	req.SetPathValue("id", "d1")
+	rec := httptest.NewRecorder()
+	h.Patch(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("expected Retry-After header")
+	}
+}
+
 func TestDonationHandler_Patch_NotFound(t *testing.T) {
 	mock := &mockDonationService{
 		patchFunc: func(ctx context.Context, id, userID string, patch model.DonationPatch) error {
@@ -436,3 +486,150 @@ func TestDonationHandler_MigrateToken_MissingCookie(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// POST /api/me/donations/:id/payment-method tests
+// ---------------------------------------------------------------------------
+
+func TestDonationHandler_PaymentMethodSession_RequiresAuth(t *testing.T) {
+	h := NewDonationHandler(&mockDonationService{})
+	req := httptest.NewRequest(http.MethodPost, "/api/me/donations/d1/payment-method", nil)
+	req.SetPathValue("id", "d1")
+	rec := httptest.NewRecorder()
+	h.PaymentMethodSession(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestDonationHandler_PaymentMethodSession_Success(t *testing.T) {
+	mock := &mockDonationService{
+		paymentMethodFunc: func(ctx context.Context, id, userID string) (string, error) {
+			if id != "d1" || userID != "user-1" {
+				t.Errorf("unexpected id=%q userID=%q", id, userID)
+			}
+			return "https://billing.stripe.com/session/abc", nil
+		},
+	}
+	h := NewDonationHandler(mock)
+
+	req := userAuthRequest(http.MethodPost, "/api/me/donations/d1/payment-method", "")
+	req.SetPathValue("id", "d1")
+	rec := httptest.NewRecorder()
+	h.PaymentMethodSession(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", rec.Code, rec.Body.String())
+	}
+	var body map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body["url"] != "https://billing.stripe.com/session/abc" {
+		t.Errorf("unexpected url %q", body["url"])
+	}
+}
+
+func TestDonationHandler_PaymentMethodSession_Errors(t *testing.T) {
+	tests := []struct {
+		err      error
+		wantCode int
+		wantErr  string
+	}{
+		{service.ErrForbidden, http.StatusForbidden, "forbidden"},
+		{repository.ErrNotFound, http.StatusNotFound, "not_found"},
+		{service.ErrNotRecurring, http.StatusBadRequest, "not_recurring"},
+		{service.ErrBillingNotConfigured, http.StatusServiceUnavailable, "billing_not_configured"},
+		{errors.New("stripe down"), http.StatusInternalServerError, "session_failed"},
+		{fmt.Errorf("stripe payment method session: %w", &pkgstripe.Error{HTTPStatus: 429, Code: "rate_limit"}), http.StatusServiceUnavailable, "stripe_rate_limited"},
+	}
+	for _, tt := range tests {
+		mock := &mockDonationService{
+			paymentMethodFunc: func(ctx context.Context, id, userID string) (string, error) {
+				return "", tt.err
+			},
+		}
+		h := NewDonationHandler(mock)
+		req := userAuthRequest(http.MethodPost, "/api/me/donations/d1/payment-method", "")
+		req.SetPathValue("id", "d1")
+		rec := httptest.NewRecorder()
+		h.PaymentMethodSession(rec, req)
+
+		if rec.Code != tt.wantCode {
+			t.Errorf("%v: expected %d, got %d", tt.err, tt.wantCode, rec.Code)
+		}
+		var body map[string]string
+		_ = json.NewDecoder(rec.Body).Decode(&body)
+		if body["error"] != tt.wantErr {
+			t.Errorf("%v: expected error=%q, got %q", tt.err, tt.wantErr, body["error"])
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// GET /api/me/donations/:id/history tests
+// ---------------------------------------------------------------------------
+
+func TestDonationHandler_History_Success(t *testing.T) {
+	mock := &mockDonationService{
+		listChangesFunc: func(ctx context.Context, id, userID string) ([]*model.DonationChange, error) {
+			if id != "d1" || userID != "user-1" {
+				t.Errorf("unexpected id=%q userID=%q", id, userID)
+			}
+			return []*model.DonationChange{{
+				ID: "c1", DonationID: "d1", Source: model.DonationChangeSourceStripe, EventType: "customer.subscription.updated",
+				Changes: map[string]model.DonationFieldChange{"amount": {From: 1000, To: 2000}}, CreatedAt: time.Now(),
+			}}, nil
+		},
+	}
+	h := NewDonationHandler(mock)
+
+	req := userAuthRequest(http.MethodGet, "/api/me/donations/d1/history", "")
+	req.SetPathValue("id", "d1")
+	rec := httptest.NewRecorder()
+	h.History(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"source":"stripe"`) || !strings.Contains(rec.Body.String(), `"amount":{"from":1000,"to":2000}`) {
+		t.Errorf("unexpected body %s", rec.Body.String())
+	}
+}
+
+func TestDonationHandler_History_Errors(t *testing.T) {
+	tests := []struct {
+		err      error
+		wantCode int
+		wantErr  string
+	}{
+		{service.ErrForbidden, http.StatusForbidden, "forbidden"},
+		{repository.ErrNotFound, http.StatusNotFound, "not_found"},
+		{errors.New("db down"), http.StatusInternalServerError, "list_failed"},
+	}
+	for _, tt := range tests {
+		mock := &mockDonationService{
+			listChangesFunc: func(ctx context.Context, id, userID string) ([]*model.DonationChange, error) {
+				return nil, tt.err
+			},
+		}
+		h := NewDonationHandler(mock)
+		req := userAuthRequest(http.MethodGet, "/api/me/donations/d1/history", "")
+		req.SetPathValue("id", "d1")
+		rec := httptest.NewRecorder()
+		h.History(rec, req)
+
+		if rec.Code != tt.wantCode || !strings.Contains(rec.Body.String(), tt.wantErr) {
+			t.Errorf("%v: expected %d %s, got %d %s", tt.err, tt.wantCode, tt.wantErr, rec.Code, rec.Body.String())
+		}
+	}
+
+	// 履歴がない場合は空配列
+	h := NewDonationHandler(&mockDonationService{})
+	req := userAuthRequest(http.MethodGet, "/api/me/donations/d1/history", "")
+	req.SetPathValue("id", "d1")
+	rec := httptest.NewRecorder()
+	h.History(rec, req)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"changes":[]`) {
+		t.Errorf("expected an empty list, got %d %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/service"
+)
+
+// FinanceSource is the subset of FinanceService needed to show a project's finances.
+type FinanceSource interface {
+	MonthlyByProject(ctx context.Context, projectID string) ([]*model.MonthlyFinance, error)
+}
+
+// FinanceHandler handles GET /api/projects/{id}/finances.
+type FinanceHandler struct {
+	projectSvc service.ProjectService
+	finances   FinanceSource
+}
+
+// NewFinanceHandler creates a FinanceHandler.
+func NewFinanceHandler(projectSvc service.ProjectService, finances FinanceSource) *FinanceHandler {
+	return &FinanceHandler{projectSvc: projectSvc, finances: finances}
+}
+
+// Finances handles GET /api/projects/{id}/finances.
+// Public like the chart: gross donations, Stripe fees, net and payouts per month.
+func (h *FinanceHandler) Finances(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	projectID := r.PathValue("id")
+	if _, err := h.projectSvc.GetByID(r.Context(), projectID); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "project_not_found"})
+		return
+	}
+
+	months, err := h.finances.MonthlyByProject(r.Context(), projectID)
+	if err != nil {
+		slog.Error("finances failed", "error", err, "project_id", projectID)
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "finances_failed"})
+		return
+	}
+	if months == nil {
+		months = []*model.MonthlyFinance{}
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"finances": months})
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/givers/backend/internal/model"
+)
+
+// ---------------------------------------------------------------------------
+// Mock FinanceSource
+// ---------------------------------------------------------------------------
+
+type mockFinanceSource struct {
+	monthlyFunc func(ctx context.Context, projectID string) ([]*model.MonthlyFinance, error)
+}
+
+func (m *mockFinanceSource) MonthlyByProject(ctx context.Context, projectID string) ([]*model.MonthlyFinance, error) {
+	if m.monthlyFunc != nil {
+		return m.monthlyFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+
+func existingProjectService() *mockProjectService {
+	return &mockProjectService{
+		getByIDFunc: func(_ context.Context, id string) (*model.Project, error) {
+			return &model.Project{ID: id}, nil
+		},
+	}
+}
+
+// ---------------------------------------------------------------------------
+// GET /api/projects/{id}/finances tests
+// ---------------------------------------------------------------------------
+
+func TestFinanceHandler_Finances_Success(t *testing.T) {
+	var gotProject string
+	src := &mockFinanceSource{
+		monthlyFunc: func(_ context.Context, projectID string) ([]*model.MonthlyFinance, error) {
+			gotProject = projectID
+			return []*model.MonthlyFinance{
+				{Month: "2026-01", Currency: "jpy", Gross: 10000, Fees: 360, Net: 9640, Payouts: 9640},
+			}, nil
+		},
+	}
+	h := NewFinanceHandler(existingProjectService(), src)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/projects/p1/finances", nil)
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.Finances(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Finances []*model.MonthlyFinance `json:"finances"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if gotProject != "p1" || len(resp.Finances) != 1 || resp.Finances[0].Fees != 360 || resp.Finances[0].Payouts != 9640 {
+		t.Errorf("unexpected finances for %s: %+v", gotProject, resp.Finances)
+	}
+}
+
+func TestFinanceHandler_Finances_Empty(t *testing.T) {
+	h := NewFinanceHandler(existingProjectService(), &mockFinanceSource{})
+	req := httptest.NewRequest(http.MethodGet, "/api/projects/p1/finances", nil)
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.Finances(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if strings.TrimSpace(rec.Body.String()) != `{"finances":[]}` {
+		t.Errorf("unexpected body: %s", rec.Body.String())
+	}
+}
+
+func TestFinanceHandler_Finances_ProjectNotFound(t *testing.T) {
+	h := NewFinanceHandler(&mockProjectService{}, &mockFinanceSource{})
+	req := httptest.NewRequest(http.MethodGet, "/api/projects/missing/finances", nil)
+	req.SetPathValue("id", "missing")
+	rec := httptest.NewRecorder()
+	h.Finances(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestFinanceHandler_Finances_Error(t *testing.T) {
+	src := &mockFinanceSource{
+		monthlyFunc: func(_ context.Context, _ string) ([]*model.MonthlyFinance, error) {
+			return nil, errors.New("db error")
+		},
+	}
+	h := NewFinanceHandler(existingProjectService(), src)
+	req := httptest.NewRequest(http.MethodGet, "/api/projects/p1/finances", nil)
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.Finances(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "finances_failed") {
+		t.Errorf("unexpected body: %s", rec.Body.String())
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"path"
 	"strings"
 
+	"github.com/givers/backend/internal/model"
 	"github.com/givers/backend/internal/service"
 	"github.com/givers/backend/internal/storage"
 	"github.com/givers/backend/pkg/auth"
@@ -44,7 +45,7 @@ func NewImageHandler(store storage.Storage, ps service.ProjectService, repo Imag
 func (h *ImageHandler) Upload(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	userID, ok := auth.UserIDFromContext(r.Context())
+	_, ok := auth.UserIDFromContext(r.Context())
 	if !ok {
 		w.WriteHeader(http.StatusUnauthorized)
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "unauthorized"})
@@ -64,7 +65,7 @@ func (h *ImageHandler) Upload(w http.ResponseWriter, r *http.Request) {
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "not_found"})
 		return
 	}
-	if project.OwnerID != userID {
+	if !projectAccessAllowed(r.Context(), project, model.ProjectRoleEditor, false) {
 		w.WriteHeader(http.StatusForbidden)
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "forbidden"})
 		return
@@ -130,7 +131,7 @@ func (h *ImageHandler) Upload(w http.ResponseWriter, r *http.Request) {
 func (h *ImageHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	userID, ok := auth.UserIDFromContext(r.Context())
+	_, ok := auth.UserIDFromContext(r.Context())
 	if !ok {
 		w.WriteHeader(http.StatusUnauthorized)
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "unauthorized"})
@@ -144,7 +145,7 @@ func (h *ImageHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "not_found"})
 		return
 	}
-	if project.OwnerID != userID {
+	if !projectAccessAllowed(r.Context(), project, model.ProjectRoleEditor, false) {
 		w.WriteHeader(http.StatusForbidden)
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "forbidden"})
 		return
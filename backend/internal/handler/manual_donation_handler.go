@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+	"github.com/givers/backend/internal/service"
+)
+
+// ManualDonationHandler handles bank transfer / cash donations recorded by the
+// project's maintainers or a host.
+type ManualDonationHandler struct {
+	svc        service.ManualDonationService
+	projectSvc service.ProjectService
+}
+
+// NewManualDonationHandler creates a ManualDonationHandler.
+func NewManualDonationHandler(svc service.ManualDonationService, projectSvc service.ProjectService) *ManualDonationHandler {
+	return &ManualDonationHandler{svc: svc, projectSvc: projectSvc}
+}
+
+// authorize checks that the caller is a maintainer or owner of the {id} project, or a host.
+// It writes the error response and returns false otherwise.
+func (h *ManualDonationHandler) authorize(w http.ResponseWriter, r *http.Request) (userID, projectID string, ok bool) {
+	return authorizeProjectManager(w, r, h.projectSvc)
+}
+
+// writeManualDonationError maps service errors to responses. fallback is the error code for unexpected errors.
+func writeManualDonationError(w http.ResponseWriter, err error, fallback string, logArgs ...any) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "not_found"})
+	case errors.Is(err, service.ErrInvalidManualDonation):
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_manual_donation"})
+	case errors.Is(err, service.ErrDonationVoided):
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "donation_voided"})
+	default:
+		slog.Error("manual donation request failed", append([]any{"error", err, "code", fallback}, logArgs...)...)
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": fallback})
+	}
+}
+
+// List handles GET /api/projects/{id}/manual-donations (project maintainer or host).
+// Voided donations are included with voided_at set.
+func (h *ManualDonationHandler) List(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, projectID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	donations, err := h.svc.ListByProject(r.Context(), projectID)
+	if err != nil {
+		writeManualDonationError(w, err, "list_failed", "project_id", projectID)
+		return
+	}
+	if donations == nil {
+		donations = []*model.Donation{}
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"donations": donations})
+}
+
+type manualDonationCreateRequest struct {
+	Amount        int        `json:"amount"`
+	Currency      string     `json:"currency"`
+	PaymentMethod string     `json:"payment_method"`
+	Reference     string     `json:"reference"`
+	DonorUserID   string     `json:"donor_user_id"`
+	DonorName     string     `json:"donor_name"`
+	Message       string     `json:"message"`
+	ReceivedAt    *time.Time `json:"received_at"`
+}
+
+// Create handles POST /api/projects/{id}/manual-donations (project maintainer or host).
+func (h *ManualDonationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	userID, projectID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	var req manualDonationCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_json"})
+		return
+	}
+
+	d, err := h.svc.Record(r.Context(), userID, service.ManualDonationInput{
+		ProjectID:     projectID,
+		Amount:        req.Amount,
+		Currency:      req.Currency,
+		PaymentMethod: req.PaymentMethod,
+		Reference:     req.Reference,
+		DonorUserID:   req.DonorUserID,
+		DonorName:     req.DonorName,
+		Message:       req.Message,
+		ReceivedAt:    req.ReceivedAt,
+	})
+	if err != nil {
+		writeManualDonationError(w, err, "create_failed", "project_id", projectID)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(d)
+}
+
+type manualDonationPatchRequest struct {
+	Amount        *int       `json:"amount"`
+	PaymentMethod *string    `json:"payment_method"`
+	Reference     *string    `json:"reference"`
+	DonorName     *string    `json:"donor_name"`
+	Message       *string    `json:"message"`
+	ReceivedAt    *time.Time `json:"received_at"`
+}
+
+// Patch handles PATCH /api/projects/{id}/manual-donations/{did} (project maintainer or host).
+func (h *ManualDonationHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	userID, projectID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+	id := r.PathValue("did")
+
+	var req manualDonationPatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_json"})
+		return
+	}
+
+	d, err := h.svc.Update(r.Context(), userID, projectID, id, model.ManualDonationPatch{
+		Amount:        req.Amount,
+		PaymentMethod: req.PaymentMethod,
+		Reference:     req.Reference,
+		DonorName:     req.DonorName,
+		Message:       req.Message,
+		ReceivedAt:    req.ReceivedAt,
+	})
+	if err != nil {
+		writeManualDonationError(w, err, "update_failed", "donation_id", id)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(d)
+}
+
+type manualDonationVoidRequest struct {
+	Reason string `json:"reason"`
+}
+
+// Void handles POST /api/projects/{id}/manual-donations/{did}/void (project maintainer or host).
+// The donation stays in the list with voided_at set but no longer counts toward totals.
+func (h *ManualDonationHandler) Void(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	userID, projectID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+	id := r.PathValue("did")
+
+	var req manualDonationVoidRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_json"})
+		return
+	}
+	if req.Reason == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "reason_required"})
+		return
+	}
+
+	d, err := h.svc.Void(r.Context(), userID, projectID, id, req.Reason)
+	if err != nil {
+		writeManualDonationError(w, err, "void_failed", "donation_id", id)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(d)
+}
+
+// Audit handles GET /api/projects/{id}/manual-donations/{did}/audit (project maintainer or host).
+func (h *ManualDonationHandler) Audit(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, projectID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+	id := r.PathValue("did")
+
+	entries, err := h.svc.ListAudit(r.Context(), projectID, id)
+	if err != nil {
+		writeManualDonationError(w, err, "list_failed", "donation_id", id)
+		return
+	}
+	if entries == nil {
+		entries = []*model.DonationAuditEntry{}
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"entries": entries})
+}
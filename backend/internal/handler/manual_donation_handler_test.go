@@ -0,0 +1,268 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+	"github.com/givers/backend/internal/service"
+	"github.com/givers/backend/pkg/auth"
+)
+
+// ---------------------------------------------------------------------------
+// Mock ManualDonationService
+// ---------------------------------------------------------------------------
+
+type mockManualDonationService struct {
+	recordFunc func(ctx context.Context, actorID string, in service.ManualDonationInput) (*model.Donation, error)
+	updateFunc func(ctx context.Context, actorID, projectID, id string, patch model.ManualDonationPatch) (*model.Donation, error)
+	voidFunc   func(ctx context.Context, actorID, projectID, id, reason string) (*model.Donation, error)
+	listFunc   func(ctx context.Context, projectID string) ([]*model.Donation, error)
+	auditFunc  func(ctx context.Context, projectID, id string) ([]*model.DonationAuditEntry, error)
+}
+
+func (m *mockManualDonationService) Record(ctx context.Context, actorID string, in service.ManualDonationInput) (*model.Donation, error) {
+	if m.recordFunc != nil {
+		return m.recordFunc(ctx, actorID, in)
+	}
+	return &model.Donation{ID: "md-1", ProjectID: in.ProjectID}, nil
+}
+func (m *mockManualDonationService) Update(ctx context.Context, actorID, projectID, id string, patch model.ManualDonationPatch) (*model.Donation, error) {
+	if m.updateFunc != nil {
+		return m.updateFunc(ctx, actorID, projectID, id, patch)
+	}
+	return &model.Donation{ID: id, ProjectID: projectID}, nil
+}
+func (m *mockManualDonationService) Void(ctx context.Context, actorID, projectID, id, reason string) (*model.Donation, error) {
+	if m.voidFunc != nil {
+		return m.voidFunc(ctx, actorID, projectID, id, reason)
+	}
+	return &model.Donation{ID: id, ProjectID: projectID}, nil
+}
+func (m *mockManualDonationService) ListByProject(ctx context.Context, projectID string) ([]*model.Donation, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+func (m *mockManualDonationService) ListAudit(ctx context.Context, projectID, id string) ([]*model.DonationAuditEntry, error) {
+	if m.auditFunc != nil {
+		return m.auditFunc(ctx, projectID, id)
+	}
+	return nil, nil
+}
+
+// ownedProjectService は user-1 がオーナーのプロジェクト p1 を返す
+func ownedProjectService() *mockMessageProjectService {
+	return &mockMessageProjectService{
+		getByIDFunc: func(ctx context.Context, id string) (*model.Project, error) {
+			if id != "p1" {
+				return nil, repository.ErrNotFound
+			}
+			return &model.Project{ID: "p1", OwnerID: "user-1"}, nil
+		},
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Tests
+// ---------------------------------------------------------------------------
+
+func TestManualDonationHandler_Create_RequiresAuth(t *testing.T) {
+	h := NewManualDonationHandler(&mockManualDonationService{}, ownedProjectService())
+	req := httptest.NewRequest(http.MethodPost, "/api/projects/p1/manual-donations", nil)
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.Create(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestManualDonationHandler_Create_ForbiddenForNonOwner(t *testing.T) {
+	projMock := &mockMessageProjectService{
+		getByIDFunc: func(ctx context.Context, id string) (*model.Project, error) {
+			return &model.Project{ID: "p1", OwnerID: "other-user"}, nil
+		},
+	}
+	h := NewManualDonationHandler(&mockManualDonationService{}, projMock)
+	req := userAuthRequest(http.MethodPost, "/api/projects/p1/manual-donations", `{"amount":1000,"payment_method":"cash"}`)
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.Create(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rec.Code)
+	}
+}
+
+func TestManualDonationHandler_Create_ProjectNotFound(t *testing.T) {
+	h := NewManualDonationHandler(&mockManualDonationService{}, ownedProjectService())
+	req := userAuthRequest(http.MethodPost, "/api/projects/missing/manual-donations", `{"amount":1000,"payment_method":"cash"}`)
+	req.SetPathValue("id", "missing")
+	rec := httptest.NewRecorder()
+	h.Create(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestManualDonationHandler_Create_Success(t *testing.T) {
+	var got service.ManualDonationInput
+	var gotActor string
+	svc := &mockManualDonationService{
+		recordFunc: func(ctx context.Context, actorID string, in service.ManualDonationInput) (*model.Donation, error) {
+			gotActor, got = actorID, in
+			return &model.Donation{ID: "md-1", ProjectID: in.ProjectID, Amount: in.Amount, PaymentMethod: in.PaymentMethod}, nil
+		},
+	}
+	h := NewManualDonationHandler(svc, ownedProjectService())
+	body := `{"amount":5000,"payment_method":"bank_transfer","reference":"0012","donor_name":"山田","received_at":"2026-10-01T10:00:00+09:00"}`
+	req := userAuthRequest(http.MethodPost, "/api/projects/p1/manual-donations", body)
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.Create(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if gotActor != "user-1" || got.ProjectID != "p1" || got.Amount != 5000 || got.PaymentMethod != "bank_transfer" ||
+		got.Reference != "0012" || got.DonorName != "山田" {
+		t.Errorf("input = %+v (actor %s)", got, gotActor)
+	}
+	if got.ReceivedAt == nil || !got.ReceivedAt.Equal(time.Date(2026, 10, 1, 1, 0, 0, 0, time.UTC)) {
+		t.Errorf("received_at = %v", got.ReceivedAt)
+	}
+	var resp model.Donation
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || resp.PaymentMethod != "bank_transfer" {
+		t.Errorf("response = %+v, err = %v", resp, err)
+	}
+}
+
+func TestManualDonationHandler_Create_HostCanRecord(t *testing.T) {
+	h := NewManualDonationHandler(&mockManualDonationService{}, &mockMessageProjectService{
+		getByIDFunc: func(ctx context.Context, id string) (*model.Project, error) {
+			return &model.Project{ID: "p1", OwnerID: "other-user"}, nil
+		},
+	})
+	req := userAuthRequest(http.MethodPost, "/api/projects/p1/manual-donations", `{"amount":1000,"payment_method":"cash"}`)
+	req = req.WithContext(auth.WithIsHost(req.Context(), true))
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.Create(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Errorf("expected 201 for host, got %d", rec.Code)
+	}
+}
+
+func TestManualDonationHandler_Create_Invalid(t *testing.T) {
+	svc := &mockManualDonationService{
+		recordFunc: func(ctx context.Context, actorID string, in service.ManualDonationInput) (*model.Donation, error) {
+			return nil, fmt.Errorf("%w: amount must be greater than 0", service.ErrInvalidManualDonation)
+		},
+	}
+	h := NewManualDonationHandler(svc, ownedProjectService())
+	req := userAuthRequest(http.MethodPost, "/api/projects/p1/manual-donations", `{"amount":0,"payment_method":"cash"}`)
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.Create(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+	var resp map[string]string
+	_ = json.NewDecoder(rec.Body).Decode(&resp)
+	if resp["error"] != "invalid_manual_donation" {
+		t.Errorf("error = %q", resp["error"])
+	}
+}
+
+func TestManualDonationHandler_Patch_Voided(t *testing.T) {
+	svc := &mockManualDonationService{
+		updateFunc: func(ctx context.Context, actorID, projectID, id string, patch model.ManualDonationPatch) (*model.Donation, error) {
+			return nil, service.ErrDonationVoided
+		},
+	}
+	h := NewManualDonationHandler(svc, ownedProjectService())
+	req := userAuthRequest(http.MethodPatch, "/api/projects/p1/manual-donations/md-1", `{"amount":2000}`)
+	req.SetPathValue("id", "p1")
+	req.SetPathValue("did", "md-1")
+	rec := httptest.NewRecorder()
+	h.Patch(rec, req)
+	if rec.Code != http.StatusConflict {
+		t.Errorf("expected 409, got %d", rec.Code)
+	}
+}
+
+func TestManualDonationHandler_Patch_NotFound(t *testing.T) {
+	svc := &mockManualDonationService{
+		updateFunc: func(ctx context.Context, actorID, projectID, id string, patch model.ManualDonationPatch) (*model.Donation, error) {
+			return nil, repository.ErrNotFound
+		},
+	}
+	h := NewManualDonationHandler(svc, ownedProjectService())
+	req := userAuthRequest(http.MethodPatch, "/api/projects/p1/manual-donations/md-x", `{"amount":2000}`)
+	req.SetPathValue("id", "p1")
+	req.SetPathValue("did", "md-x")
+	rec := httptest.NewRecorder()
+	h.Patch(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestManualDonationHandler_Void_RequiresReason(t *testing.T) {
+	h := NewManualDonationHandler(&mockManualDonationService{}, ownedProjectService())
+	req := userAuthRequest(http.MethodPost, "/api/projects/p1/manual-donations/md-1/void", `{}`)
+	req.SetPathValue("id", "p1")
+	req.SetPathValue("did", "md-1")
+	rec := httptest.NewRecorder()
+	h.Void(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestManualDonationHandler_Void_Success(t *testing.T) {
+	var gotReason string
+	svc := &mockManualDonationService{
+		voidFunc: func(ctx context.Context, actorID, projectID, id, reason string) (*model.Donation, error) {
+			gotReason = reason
+			now := time.Now()
+			return &model.Donation{ID: id, ProjectID: projectID, VoidedAt: &now, VoidReason: reason}, nil
+		},
+	}
+	h := NewManualDonationHandler(svc, ownedProjectService())
+	req := userAuthRequest(http.MethodPost, "/api/projects/p1/manual-donations/md-1/void", `{"reason":"二重記録"}`)
+	req.SetPathValue("id", "p1")
+	req.SetPathValue("did", "md-1")
+	rec := httptest.NewRecorder()
+	h.Void(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if gotReason != "二重記録" {
+		t.Errorf("reason = %q", gotReason)
+	}
+}
+
+func TestManualDonationHandler_Audit_EmptyList(t *testing.T) {
+	h := NewManualDonationHandler(&mockManualDonationService{}, ownedProjectService())
+	req := userAuthRequest(http.MethodGet, "/api/projects/p1/manual-donations/md-1/audit", "")
+	req.SetPathValue("id", "p1")
+	req.SetPathValue("did", "md-1")
+	rec := httptest.NewRecorder()
+	h.Audit(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var resp map[string][]any
+	_ = json.NewDecoder(rec.Body).Decode(&resp)
+	if resp["entries"] == nil {
+		t.Error("entries should be an empty array, not null")
+	}
+}
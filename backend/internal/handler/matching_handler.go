@@ -0,0 +1,341 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+	"github.com/givers/backend/internal/service"
+	"github.com/givers/backend/pkg/pdf"
+)
+
+// MatchingHandler handles matching-gift campaigns. Campaigns are managed by the
+// project's maintainers or a host; the sponsor reads the summary of what they owe
+// through a link that carries the campaign's summary token.
+type MatchingHandler struct {
+	svc        service.MatchingService
+	projectSvc service.ProjectService
+}
+
+// NewMatchingHandler creates a MatchingHandler.
+func NewMatchingHandler(svc service.MatchingService, projectSvc service.ProjectService) *MatchingHandler {
+	return &MatchingHandler{svc: svc, projectSvc: projectSvc}
+}
+
+// writeMatchingError maps service errors to responses. fallback is the error code for unexpected errors.
+func writeMatchingError(w http.ResponseWriter, err error, fallback string, logArgs ...any) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		writeReceiptJSONError(w, http.StatusNotFound, "not_found")
+	case errors.Is(err, service.ErrInvalidMatchingCampaign):
+		writeReceiptJSONError(w, http.StatusBadRequest, "invalid_matching_campaign")
+	case errors.Is(err, service.ErrMatchingCampaignFinished):
+		writeReceiptJSONError(w, http.StatusConflict, "campaign_finished")
+	default:
+		slog.Error("matching request failed", append([]any{"error", err, "code", fallback}, logArgs...)...)
+		writeReceiptJSONError(w, http.StatusInternalServerError, fallback)
+	}
+}
+
+// List handles GET /api/projects/{id}/matching-campaigns (project maintainer or host).
+// Unlike the project detail, the sponsor's email and summary token are included.
+func (h *MatchingHandler) List(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, projectID, ok := authorizeProjectManager(w, r, h.projectSvc)
+	if !ok {
+		return
+	}
+
+	campaigns, err := h.svc.ListByProject(r.Context(), projectID)
+	if err != nil {
+		writeMatchingError(w, err, "list_failed", "project_id", projectID)
+		return
+	}
+	if campaigns == nil {
+		campaigns = []*model.MatchingCampaign{}
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"campaigns": campaigns})
+}
+
+type matchingCampaignCreateRequest struct {
+	SponsorName  string     `json:"sponsor_name"`
+	SponsorEmail string     `json:"sponsor_email"`
+	RatioPercent int        `json:"ratio_percent"`
+	CapAmount    int        `json:"cap_amount"`
+	Currency     string     `json:"currency"`
+	StartsAt     *time.Time `json:"starts_at"`
+	EndsAt       time.Time  `json:"ends_at"`
+}
+
+// Create handles POST /api/projects/{id}/matching-campaigns (project maintainer or host).
+func (h *MatchingHandler) Create(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	userID, projectID, ok := authorizeProjectManager(w, r, h.projectSvc)
+	if !ok {
+		return
+	}
+
+	var req matchingCampaignCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_json"})
+		return
+	}
+
+	c, err := h.svc.Create(r.Context(), userID, service.MatchingCampaignInput{
+		ProjectID:    projectID,
+		SponsorName:  req.SponsorName,
+		SponsorEmail: req.SponsorEmail,
+		RatioPercent: req.RatioPercent,
+		CapAmount:    req.CapAmount,
+		Currency:     req.Currency,
+		StartsAt:     req.StartsAt,
+		EndsAt:       req.EndsAt,
+	})
+	if err != nil {
+		writeMatchingError(w, err, "create_failed", "project_id", projectID)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(c)
+}
+
+// Cancel handles POST /api/projects/{id}/matching-campaigns/{cid}/cancel (project maintainer or host).
+// Donations matched before the cancellation are still owed by the sponsor.
+func (h *MatchingHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, projectID, ok := authorizeProjectManager(w, r, h.projectSvc)
+	if !ok {
+		return
+	}
+	id := r.PathValue("cid")
+
+	c, err := h.svc.Cancel(r.Context(), projectID, id)
+	if err != nil {
+		writeMatchingError(w, err, "cancel_failed", "campaign_id", id)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(c)
+}
+
+// Summary handles GET /api/projects/{id}/matching-campaigns/{cid}/summary (project maintainer or host)
+// as HTML (default) or PDF (?format=pdf).
+func (h *MatchingHandler) Summary(w http.ResponseWriter, r *http.Request) {
+	_, projectID, ok := authorizeProjectManager(w, r, h.projectSvc)
+	if !ok {
+		return
+	}
+	format, ok := receiptFormat(r)
+	if !ok {
+		writeReceiptJSONError(w, http.StatusBadRequest, "invalid_format")
+		return
+	}
+	id := r.PathValue("cid")
+
+	sum, err := h.svc.Summary(r.Context(), projectID, id)
+	if err != nil {
+		writeMatchingError(w, err, "summary_failed", "campaign_id", id)
+		return
+	}
+	writeMatchingSummary(w, sum, format)
+}
+
+// SponsorSummary handles GET /api/matching-campaigns/{id}/summary?token=... (public).
+// The token is the campaign's summary token, shared with the sponsor by the project owner.
+func (h *MatchingHandler) SponsorSummary(w http.ResponseWriter, r *http.Request) {
+	format, ok := receiptFormat(r)
+	if !ok {
+		writeReceiptJSONError(w, http.StatusBadRequest, "invalid_format")
+		return
+	}
+	id := r.PathValue("id")
+
+	sum, err := h.svc.SponsorSummary(r.Context(), id, r.URL.Query().Get("token"))
+	if err != nil {
+		writeMatchingError(w, err, "summary_failed", "campaign_id", id)
+		return
+	}
+	writeMatchingSummary(w, sum, format)
+}
+
+func writeMatchingSummary(w http.ResponseWriter, sum *model.MatchingSummary, format string) {
+	view := newMatchingSummaryView(sum)
+	// スポンサーの請求情報を含むため共有キャッシュに残さない
+	w.Header().Set("Cache-Control", "private, no-store")
+
+	if format == "pdf" {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `attachment; filename="givers-matching-`+sum.Campaign.ID+`.pdf"`)
+		if _, err := renderMatchingSummaryPDF(view).WriteTo(w); err != nil {
+			slog.Error("matching summary pdf write failed", "error", err, "campaign_id", sum.Campaign.ID)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := matchingSummaryTemplate.Execute(w, view); err != nil {
+		slog.Error("matching summary html render failed", "error", err, "campaign_id", sum.Campaign.ID)
+	}
+}
+
+// matchingSummaryNotes は全てのマッチング明細に印字する注記。
+// 上乗せ分もプロジェクトオーナーへの寄付であり、運営者は請求・受領しない。
+var matchingSummaryNotes = []string{
+	"本書は GIVErS のマッチング寄付キャンペーンで上乗せが確定した金額の明細です。お支払いはプロジェクトのオーナーに直接お願いします。本サービスの運営者は上乗せ分を請求・受領しません。",
+	"各寄付に比率を掛けた額を、上限に達するまで上乗せしています。日付は日本時間です。",
+	"This is a summary of the amounts matched by your campaign. Please pay the project owner directly; the operator of GIVErS does not collect matching gifts.",
+}
+
+// matchingSummaryView は HTML と PDF で共通の表示用データ
+type matchingSummaryView struct {
+	Title       string
+	Fields      [][2]string
+	Rows        []matchingSummaryRow
+	DonationSum string
+	AmountOwed  string
+	Notes       []string
+}
+
+type matchingSummaryRow struct {
+	Date     string
+	Donation string
+	Matched  string
+}
+
+func newMatchingSummaryView(sum *model.MatchingSummary) *matchingSummaryView {
+	c := sum.Campaign
+	date := func(t time.Time) string { return t.In(service.ReceiptTimeZone).Format("2006-01-02") }
+
+	status := "暫定（キャンペーン期間中） / Provisional"
+	switch {
+	case c.CancelledAt != nil:
+		status = "確定（" + date(*c.CancelledAt) + " 終了） / Final (cancelled)"
+	case c.ClosedAt != nil:
+		status = "確定 / Final"
+	}
+
+	v := &matchingSummaryView{
+		Title: "マッチング寄付 明細 / Matching Gift Summary",
+		Fields: [][2]string{
+			{"スポンサー / Sponsor", c.SponsorName},
+			{"プロジェクト / Project", sum.ProjectName},
+			{"お支払い先（オーナー） / Payee", sum.OwnerName},
+			{"期間 / Period", date(c.StartsAt) + " – " + date(c.EndsAt.Add(-time.Second))},
+			{"比率 / Ratio", strconv.Itoa(c.RatioPercent) + "%"},
+			{"上限 / Cap", formatReceiptAmount(c.CapAmount, c.Currency)},
+			{"状態 / Status", status},
+		},
+		DonationSum: formatReceiptAmount(sum.DonationsTotal, c.Currency),
+		AmountOwed:  formatReceiptAmount(sum.AmountOwed, c.Currency),
+		Notes:       matchingSummaryNotes,
+	}
+	for _, mc := range sum.Contributions {
+		v.Rows = append(v.Rows, matchingSummaryRow{
+			Date:     date(mc.PaidAt),
+			Donation: formatReceiptAmount(mc.DonationAmount, c.Currency),
+			Matched:  formatReceiptAmount(mc.MatchedAmount, c.Currency),
+		})
+	}
+	return v
+}
+
+var matchingSummaryTemplate = template.Must(template.New("matching").Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; color: #222; max-width: 800px; margin: 2rem auto; padding: 0 1rem; }
+h1 { font-size: 1.4rem; border-bottom: 2px solid #222; padding-bottom: .4rem; }
+dl { display: grid; grid-template-columns: max-content 1fr; gap: .2rem 1rem; }
+dt { color: #666; }
+table { width: 100%; border-collapse: collapse; margin: 1.5rem 0; }
+th, td { border-bottom: 1px solid #ccc; padding: .4rem; text-align: left; }
+td.amount, th.amount { text-align: right; white-space: nowrap; }
+tfoot td { font-weight: bold; border-bottom: none; }
+.notes { font-size: .85rem; color: #444; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<dl>
+{{- range .Fields}}
+<dt>{{index . 0}}</dt><dd>{{index . 1}}</dd>
+{{- end}}
+</dl>
+<table>
+<thead><tr><th>日付 / Date</th><th class="amount">寄付額 / Donation</th><th class="amount">上乗せ額 / Matched</th></tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr><td>{{.Date}}</td><td class="amount">{{.Donation}}</td><td class="amount">{{.Matched}}</td></tr>
+{{- end}}
+</tbody>
+<tfoot>
+<tr><td>合計 / Total</td><td class="amount">{{.DonationSum}}</td><td class="amount">{{.AmountOwed}}</td></tr>
+<tr><td colspan="2">お支払い額 / Amount owed</td><td class="amount">{{.AmountOwed}}</td></tr>
+</tfoot>
+</table>
+<div class="notes">
+{{- range .Notes}}
+<p>{{.}}</p>
+{{- end}}
+</div>
+</body>
+</html>
+`))
+
+func renderMatchingSummaryPDF(v *matchingSummaryView) *pdf.Document {
+	p := &receiptPDF{doc: pdf.New(v.Title)}
+	right := pdf.PageWidth - receiptMargin
+	donationRight := right - 120
+
+	p.advance(20)
+	p.page.Text(receiptMargin, p.y, 16, v.Title)
+	p.advance(8)
+	p.page.Line(receiptMargin, p.y, right, p.y, 1)
+
+	for _, kv := range v.Fields {
+		p.advance(receiptLeading + 2)
+		p.page.Text(receiptMargin, p.y, receiptBodySize, kv[0])
+		p.page.Text(receiptMargin+150, p.y, receiptBodySize, fitText(kv[1], receiptBodySize, right-receiptMargin-150))
+	}
+
+	p.advance(receiptLeading * 2)
+	p.page.Text(receiptMargin, p.y, receiptBodySize, "日付")
+	p.page.Text(donationRight-pdf.TextWidth("寄付額", receiptBodySize), p.y, receiptBodySize, "寄付額")
+	p.page.Text(right-pdf.TextWidth("上乗せ額", receiptBodySize), p.y, receiptBodySize, "上乗せ額")
+	p.advance(5)
+	p.page.Line(receiptMargin, p.y, right, p.y, 0.5)
+
+	for _, row := range v.Rows {
+		p.advance(receiptLeading)
+		p.page.Text(receiptMargin, p.y, receiptBodySize, row.Date)
+		p.page.Text(donationRight-pdf.TextWidth(row.Donation, receiptBodySize), p.y, receiptBodySize, row.Donation)
+		p.page.Text(right-pdf.TextWidth(row.Matched, receiptBodySize), p.y, receiptBodySize, row.Matched)
+	}
+	p.advance(5)
+	p.page.Line(receiptMargin, p.y, right, p.y, 0.5)
+	p.advance(receiptLeading + 2)
+	p.page.Text(receiptMargin, p.y, receiptBodySize, "合計 / Total")
+	p.page.Text(donationRight-pdf.TextWidth(v.DonationSum, receiptBodySize), p.y, receiptBodySize, v.DonationSum)
+	p.page.Text(right-pdf.TextWidth(v.AmountOwed, receiptBodySize), p.y, receiptBodySize, v.AmountOwed)
+	p.advance(receiptLeading + 4)
+	owed := fmt.Sprintf("お支払い額 / Amount owed  %s", v.AmountOwed)
+	p.page.Text(right-pdf.TextWidth(owed, 11), p.y, 11, owed)
+
+	p.advance(receiptLeading)
+	for _, para := range v.Notes {
+		for _, line := range wrapText(para, 8, right-receiptMargin) {
+			p.advance(12)
+			p.page.Text(receiptMargin, p.y, 8, line)
+		}
+	}
+	return p.doc
+}
@@ -0,0 +1,275 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+	"github.com/givers/backend/internal/service"
+)
+
+// ---------------------------------------------------------------------------
+// Mock MatchingService
+// ---------------------------------------------------------------------------
+
+type mockMatchingService struct {
+	createFunc         func(ctx context.Context, actorID string, in service.MatchingCampaignInput) (*model.MatchingCampaign, error)
+	listFunc           func(ctx context.Context, projectID string) ([]*model.MatchingCampaign, error)
+	cancelFunc         func(ctx context.Context, projectID, id string) (*model.MatchingCampaign, error)
+	activeFunc         func(ctx context.Context, projectID string) ([]*model.MatchingCampaign, error)
+	monthlyFunc        func(ctx context.Context, projectID string) ([]*model.MonthlySum, error)
+	summaryFunc        func(ctx context.Context, projectID, id string) (*model.MatchingSummary, error)
+	sponsorSummaryFunc func(ctx context.Context, id, token string) (*model.MatchingSummary, error)
+}
+
+func (m *mockMatchingService) Create(ctx context.Context, actorID string, in service.MatchingCampaignInput) (*model.MatchingCampaign, error) {
+	if m.createFunc != nil {
+		return m.createFunc(ctx, actorID, in)
+	}
+	return &model.MatchingCampaign{ID: "mc-1", ProjectID: in.ProjectID}, nil
+}
+func (m *mockMatchingService) ListByProject(ctx context.Context, projectID string) ([]*model.MatchingCampaign, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+func (m *mockMatchingService) Cancel(ctx context.Context, projectID, id string) (*model.MatchingCampaign, error) {
+	if m.cancelFunc != nil {
+		return m.cancelFunc(ctx, projectID, id)
+	}
+	return &model.MatchingCampaign{ID: id, ProjectID: projectID}, nil
+}
+func (m *mockMatchingService) ActiveByProject(ctx context.Context, projectID string) ([]*model.MatchingCampaign, error) {
+	if m.activeFunc != nil {
+		return m.activeFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+func (m *mockMatchingService) MonthlyMatchedByProject(ctx context.Context, projectID string) ([]*model.MonthlySum, error) {
+	if m.monthlyFunc != nil {
+		return m.monthlyFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+func (m *mockMatchingService) ApplyPayment(_ context.Context, _ *model.DonationPayment) error {
+	return nil
+}
+func (m *mockMatchingService) ReleasePayment(_ context.Context, _ *model.DonationPayment) error {
+	return nil
+}
+func (m *mockMatchingService) Summary(ctx context.Context, projectID, id string) (*model.MatchingSummary, error) {
+	if m.summaryFunc != nil {
+		return m.summaryFunc(ctx, projectID, id)
+	}
+	return testMatchingSummary(), nil
+}
+func (m *mockMatchingService) SponsorSummary(ctx context.Context, id, token string) (*model.MatchingSummary, error) {
+	if m.sponsorSummaryFunc != nil {
+		return m.sponsorSummaryFunc(ctx, id, token)
+	}
+	return testMatchingSummary(), nil
+}
+func (m *mockMatchingService) CloseEnded(_ context.Context) ([]*model.MatchingSummary, error) {
+	return nil, nil
+}
+
+func testMatchingSummary() *model.MatchingSummary {
+	paidAt := time.Date(2026, 3, 5, 1, 0, 0, 0, time.UTC)
+	return &model.MatchingSummary{
+		Campaign: &model.MatchingCampaign{
+			ID: "mc-1", ProjectID: "p1", SponsorName: "Acme <Corp>", RatioPercent: 100, CapAmount: 100000, Currency: "jpy",
+			StartsAt: paidAt.AddDate(0, 0, -4), EndsAt: paidAt.AddDate(0, 1, 0),
+		},
+		ProjectName: "OSS",
+		OwnerName:   "オーナー",
+		Contributions: []*model.MatchingContribution{
+			{ID: "c1", DonationAmount: 3000, MatchedAmount: 3000, PaidAt: paidAt},
+		},
+		DonationsTotal: 3000,
+		AmountOwed:     3000,
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Tests
+// ---------------------------------------------------------------------------
+
+func TestMatchingHandler_Create_ForbiddenForNonOwner(t *testing.T) {
+	projMock := &mockMessageProjectService{
+		getByIDFunc: func(ctx context.Context, id string) (*model.Project, error) {
+			return &model.Project{ID: "p1", OwnerID: "other-user"}, nil
+		},
+	}
+	h := NewMatchingHandler(&mockMatchingService{}, projMock)
+	req := userAuthRequest(http.MethodPost, "/api/projects/p1/matching-campaigns", `{"sponsor_name":"Acme","cap_amount":1000}`)
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.Create(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rec.Code)
+	}
+}
+
+func TestMatchingHandler_Create_Success(t *testing.T) {
+	var got service.MatchingCampaignInput
+	var gotActor string
+	svc := &mockMatchingService{
+		createFunc: func(_ context.Context, actorID string, in service.MatchingCampaignInput) (*model.MatchingCampaign, error) {
+			gotActor, got = actorID, in
+			return &model.MatchingCampaign{ID: "mc-1", ProjectID: in.ProjectID, SummaryToken: "tok"}, nil
+		},
+	}
+	h := NewMatchingHandler(svc, ownedProjectService())
+	req := userAuthRequest(http.MethodPost, "/api/projects/p1/matching-campaigns",
+		`{"sponsor_name":"Acme","ratio_percent":200,"cap_amount":50000,"ends_at":"2026-05-01T00:00:00Z"}`)
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.Create(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if gotActor != "user-1" || got.ProjectID != "p1" || got.SponsorName != "Acme" || got.RatioPercent != 200 || got.CapAmount != 50000 {
+		t.Errorf("unexpected input: actor=%s %+v", gotActor, got)
+	}
+	if !got.EndsAt.Equal(time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)) || got.StartsAt != nil {
+		t.Errorf("unexpected period: %+v", got)
+	}
+	var body model.MatchingCampaign
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || body.SummaryToken != "tok" {
+		t.Errorf("expected the owner to receive the summary token, got %+v (%v)", body, err)
+	}
+}
+
+func TestMatchingHandler_Create_Invalid(t *testing.T) {
+	svc := &mockMatchingService{
+		createFunc: func(_ context.Context, _ string, _ service.MatchingCampaignInput) (*model.MatchingCampaign, error) {
+			return nil, service.ErrInvalidMatchingCampaign
+		},
+	}
+	h := NewMatchingHandler(svc, ownedProjectService())
+	req := userAuthRequest(http.MethodPost, "/api/projects/p1/matching-campaigns", `{"sponsor_name":""}`)
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.Create(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "invalid_matching_campaign") {
+		t.Errorf("unexpected body: %s", rec.Body.String())
+	}
+}
+
+func TestMatchingHandler_List_Empty(t *testing.T) {
+	h := NewMatchingHandler(&mockMatchingService{}, ownedProjectService())
+	req := userAuthRequest(http.MethodGet, "/api/projects/p1/matching-campaigns", "")
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.List(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if strings.TrimSpace(rec.Body.String()) != `{"campaigns":[]}` {
+		t.Errorf("unexpected body: %s", rec.Body.String())
+	}
+}
+
+func TestMatchingHandler_Cancel_Errors(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{repository.ErrNotFound, http.StatusNotFound},
+		{service.ErrMatchingCampaignFinished, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		svc := &mockMatchingService{
+			cancelFunc: func(_ context.Context, _, _ string) (*model.MatchingCampaign, error) { return nil, tt.err },
+		}
+		h := NewMatchingHandler(svc, ownedProjectService())
+		req := userAuthRequest(http.MethodPost, "/api/projects/p1/matching-campaigns/mc-1/cancel", "")
+		req.SetPathValue("id", "p1")
+		req.SetPathValue("cid", "mc-1")
+		rec := httptest.NewRecorder()
+		h.Cancel(rec, req)
+		if rec.Code != tt.code {
+			t.Errorf("%v: expected %d, got %d", tt.err, tt.code, rec.Code)
+		}
+	}
+}
+
+func TestMatchingHandler_SponsorSummary_HTML(t *testing.T) {
+	var gotID, gotToken string
+	svc := &mockMatchingService{
+		sponsorSummaryFunc: func(_ context.Context, id, token string) (*model.MatchingSummary, error) {
+			gotID, gotToken = id, token
+			return testMatchingSummary(), nil
+		},
+	}
+	h := NewMatchingHandler(svc, ownedProjectService())
+	req := httptest.NewRequest(http.MethodGet, "/api/matching-campaigns/mc-1/summary?token=tok", nil)
+	req.SetPathValue("id", "mc-1")
+	rec := httptest.NewRecorder()
+	h.SponsorSummary(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if gotID != "mc-1" || gotToken != "tok" {
+		t.Errorf("unexpected args: %s %s", gotID, gotToken)
+	}
+	if rec.Header().Get("Cache-Control") != "private, no-store" {
+		t.Errorf("unexpected Cache-Control: %s", rec.Header().Get("Cache-Control"))
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"Acme &lt;Corp&gt;", "OSS", "オーナー", "¥3,000", "2026-03-05", "暫定", "運営者は上乗せ分を請求・受領しません"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected body to contain %q", want)
+		}
+	}
+}
+
+func TestMatchingHandler_SponsorSummary_WrongToken(t *testing.T) {
+	svc := &mockMatchingService{
+		sponsorSummaryFunc: func(_ context.Context, _, _ string) (*model.MatchingSummary, error) {
+			return nil, repository.ErrNotFound
+		},
+	}
+	h := NewMatchingHandler(svc, ownedProjectService())
+	req := httptest.NewRequest(http.MethodGet, "/api/matching-campaigns/mc-1/summary?token=bad", nil)
+	req.SetPathValue("id", "mc-1")
+	rec := httptest.NewRecorder()
+	h.SponsorSummary(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestMatchingHandler_Summary_PDF(t *testing.T) {
+	h := NewMatchingHandler(&mockMatchingService{}, ownedProjectService())
+	req := userAuthRequest(http.MethodGet, "/api/projects/p1/matching-campaigns/mc-1/summary?format=pdf", "")
+	req.SetPathValue("id", "p1")
+	req.SetPathValue("cid", "mc-1")
+	rec := httptest.NewRecorder()
+	h.Summary(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if rec.Header().Get("Content-Type") != "application/pdf" {
+		t.Errorf("unexpected Content-Type: %s", rec.Header().Get("Content-Type"))
+	}
+	if !bytes.HasPrefix(rec.Body.Bytes(), []byte("%PDF-")) {
+		t.Error("expected a PDF body")
+	}
+}
@@ -6,11 +6,11 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/givers/backend/internal/model"
 	"github.com/givers/backend/internal/service"
-	"github.com/givers/backend/pkg/auth"
 )
 
-// MessageHandler handles donation-message endpoints for project owners.
+// MessageHandler handles donation-message endpoints for project maintainers.
 type MessageHandler struct {
 	donationSvc service.DonationService
 	projectSvc  service.ProjectService
@@ -21,32 +21,15 @@ func NewMessageHandler(donationSvc service.DonationService, projectSvc service.P
 	return &MessageHandler{donationSvc: donationSvc, projectSvc: projectSvc}
 }
 
-// List handles GET /api/projects/:id/messages (owner or host auth required).
+// List handles GET /api/projects/:id/messages (project maintainer or host auth required).
 func (h *MessageHandler) List(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	userID, ok := auth.UserIDFromContext(r.Context())
+	_, project, ok := authorizeProject(w, r, h.projectSvc, model.ProjectRoleMaintainer, true)
 	if !ok {
-		w.WriteHeader(http.StatusUnauthorized)
-		_ = json.NewEncoder(w).Encode(map[string]string{"error": "unauthorized"})
-		return
-	}
-
-	projectID := r.PathValue("id")
-
-	project, err := h.projectSvc.GetByID(r.Context(), projectID)
-	if err != nil {
-		w.WriteHeader(http.StatusNotFound)
-		_ = json.NewEncoder(w).Encode(map[string]string{"error": "project_not_found"})
-		return
-	}
-
-	isHost := auth.IsHostFromContext(r.Context())
-	if !isHost && project.OwnerID != userID {
-		w.WriteHeader(http.StatusForbidden)
-		_ = json.NewEncoder(w).Encode(map[string]string{"error": "forbidden"})
 		return
 	}
+	projectID := project.ID
 
 	// Parse query params with defaults
 	limit := 50
@@ -79,8 +62,55 @@ func (h *MessageHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 目安額ごとの寄付数（どの金額が選ばれているか）
+	tiers, err := h.donationSvc.TierBreakdown(r.Context(), project)
+	if err != nil {
+		slog.Error("donation tier breakdown failed", "error", err, "project_id", projectID)
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "list_failed"})
+		return
+	}
+
 	_ = json.NewEncoder(w).Encode(map[string]any{
 		"messages": result.Messages,
 		"total":    result.Total,
+		"tiers":    tiers,
 	})
 }
+
+// Changes handles GET /api/projects/:id/donation-changes (project maintainer or host auth required).
+// Lists changes to the project's recurring donations (amount, pause, scheduled
+// cancellation, payment status), newest first, whether made by donors or on Stripe's side.
+func (h *MessageHandler) Changes(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, projectID, ok := authorizeProjectManager(w, r, h.projectSvc)
+	if !ok {
+		return
+	}
+
+	limit := 50
+	offset := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 100 {
+			limit = n
+		}
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			offset = n
+		}
+	}
+
+	changes, err := h.donationSvc.ListProjectChanges(r.Context(), projectID, limit, offset)
+	if err != nil {
+		slog.Error("list donation changes failed", "error", err, "project_id", projectID)
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "list_failed"})
+		return
+	}
+	if changes == nil {
+		changes = []*model.DonationChange{}
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]any{"changes": changes})
+}
@@ -22,7 +22,13 @@ type mockMessageProjectService struct {
 	getByIDFunc func(ctx context.Context, id string) (*model.Project, error)
 }
 
-func (m *mockMessageProjectService) List(ctx context.Context, sort string, limit int, cursor string) (*model.ProjectListResult, error) {
+func (m *mockMessageProjectService) List(ctx context.Context, sort string, filter model.ProjectFilter, limit int, cursor string) (*model.ProjectListResult, error) {
+	return nil, nil
+}
+func (m *mockMessageProjectService) Facets(ctx context.Context, filter model.ProjectFilter) (*model.ProjectFacets, error) {
+	return nil, nil
+}
+func (m *mockMessageProjectService) Search(ctx context.Context, query, sort string, limit int, cursor string) (*model.ProjectSearchResult, error) {
 	return nil, nil
 }
 func (m *mockMessageProjectService) GetByID(ctx context.Context, id string) (*model.Project, error) {
@@ -40,6 +46,15 @@ func (m *mockMessageProjectService) Create(ctx context.Context, project *model.P
 func (m *mockMessageProjectService) Update(ctx context.Context, project *model.Project) error {
 	return nil
 }
+func (m *mockMessageProjectService) UpdateWithRevision(ctx context.Context, actorID string, project *model.Project) error {
+	return nil
+}
+func (m *mockMessageProjectService) ListRevisions(ctx context.Context, projectID string) ([]*model.ProjectRevision, error) {
+	return nil, nil
+}
+func (m *mockMessageProjectService) RestoreRevision(ctx context.Context, actorID, projectID, revisionID string) (*model.Project, error) {
+	return nil, nil
+}
 func (m *mockMessageProjectService) Delete(ctx context.Context, id string) error {
 	return nil
 }
@@ -243,3 +258,100 @@ func TestMessageHandler_List_ServiceError(t *testing.T) {
 
 // Suppress unused import warning
 var _ = service.ErrForbidden
+
+func TestMessageHandler_List_IncludesTierBreakdown(t *testing.T) {
+	projMock := &mockMessageProjectService{
+		getByIDFunc: func(ctx context.Context, id string) (*model.Project, error) {
+			return &model.Project{ID: "p1", OwnerID: "user-1", DonationTiers: []model.DonationTier{{ID: "t1", Label: "応援", Amount: 500}}}, nil
+		},
+	}
+	var gotProject *model.Project
+	donMock := &mockDonationService{
+		tierBreakdownFunc: func(ctx context.Context, project *model.Project) ([]*model.DonationTierStat, error) {
+			gotProject = project
+			return []*model.DonationTierStat{{TierID: "t1", Label: "応援", Amount: 500, Donations: 4, PaidAmount: 2000}}, nil
+		},
+	}
+	h := NewMessageHandler(donMock, projMock)
+
+	req := userAuthRequest(http.MethodGet, "/api/projects/p1/messages", "")
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.List(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", rec.Code, rec.Body.String())
+	}
+	if gotProject == nil || len(gotProject.DonationTiers) != 1 {
+		t.Errorf("expected the project's tiers to be passed, got %+v", gotProject)
+	}
+	var resp struct {
+		Tiers []*model.DonationTierStat `json:"tiers"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Tiers) != 1 || resp.Tiers[0].TierID != "t1" || resp.Tiers[0].Donations != 4 {
+		t.Errorf("unexpected tiers: %+v", resp.Tiers)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// GET /api/projects/:id/donation-changes tests
+// ---------------------------------------------------------------------------
+
+func TestMessageHandler_Changes_OwnerSeesHistory(t *testing.T) {
+	projMock := &mockMessageProjectService{
+		getByIDFunc: func(ctx context.Context, id string) (*model.Project, error) {
+			return &model.Project{ID: id, OwnerID: "user-1"}, nil
+		},
+	}
+	var gotLimit, gotOffset int
+	donationMock := &mockDonationService{
+		listProjectChgsFunc: func(_ context.Context, projectID string, limit, offset int) ([]*model.DonationChange, error) {
+			gotLimit, gotOffset = limit, offset
+			return []*model.DonationChange{{
+				ID: "c1", DonationID: "d1", Source: model.DonationChangeSourceStripeAutomatic, EventType: "customer.subscription.paused",
+				Changes: map[string]model.DonationFieldChange{"paused": {From: false, To: true}}, CreatedAt: time.Now(),
+			}}, nil
+		},
+	}
+	h := NewMessageHandler(donationMock, projMock)
+
+	req := userAuthRequest(http.MethodGet, "/api/projects/p1/donation-changes?limit=10&offset=20", "")
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.Changes(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if gotLimit != 10 || gotOffset != 20 {
+		t.Errorf("expected limit=10 offset=20, got %d %d", gotLimit, gotOffset)
+	}
+	var body struct {
+		Changes []*model.DonationChange `json:"changes"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(body.Changes) != 1 || body.Changes[0].Source != "stripe_automatic" || body.Changes[0].Changes["paused"].To != true {
+		t.Errorf("unexpected changes %+v", body.Changes)
+	}
+}
+
+func TestMessageHandler_Changes_Forbidden_NotOwner(t *testing.T) {
+	projMock := &mockMessageProjectService{
+		getByIDFunc: func(ctx context.Context, id string) (*model.Project, error) {
+			return &model.Project{ID: id, OwnerID: "someone-else"}, nil
+		},
+	}
+	h := NewMessageHandler(&mockDonationService{}, projMock)
+
+	req := userAuthRequest(http.MethodGet, "/api/projects/p1/donation-changes", "")
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.Changes(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rec.Code)
+	}
+}
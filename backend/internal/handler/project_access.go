@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/service"
+	"github.com/givers/backend/pkg/auth"
+)
+
+// Every permission check on a project goes through projectAccessAllowed, so the member
+// roles are interpreted in one place:
+//
+//	editor      activity updates and images
+//	maintainer  project settings, status, donations, matching, upstreams and messages
+//	owner       members, invitations, restoring revisions and deleting the project
+//
+// The project creator (Project.OwnerID) is always an owner.
+
+// projectAccessAllowed reports whether the caller has at least minRole on project.
+// Hosts are allowed regardless of membership when hostAllowed is true.
+func projectAccessAllowed(ctx context.Context, project *model.Project, minRole string, hostAllowed bool) bool {
+	if hostAllowed && auth.IsHostFromContext(ctx) {
+		return true
+	}
+	userID, ok := auth.UserIDFromContext(ctx)
+	return ok && model.ProjectRoleAtLeast(project.RoleOf(userID), minRole)
+}
+
+// authorizeProject loads the {id} project and checks that the caller has at least minRole
+// on it (or is a host, when hostAllowed). It writes the error response and returns false otherwise.
+func authorizeProject(w http.ResponseWriter, r *http.Request, projectSvc service.ProjectService, minRole string, hostAllowed bool) (userID string, project *model.Project, ok bool) {
+	userID, ok = auth.UserIDFromContext(r.Context())
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "unauthorized"})
+		return "", nil, false
+	}
+
+	project, err := projectSvc.GetByID(r.Context(), r.PathValue("id"))
+	if err != nil || project == nil {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "project_not_found"})
+		return "", nil, false
+	}
+	if !projectAccessAllowed(r.Context(), project, minRole, hostAllowed) {
+		w.WriteHeader(http.StatusForbidden)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "forbidden"})
+		return "", nil, false
+	}
+	return userID, project, true
+}
+
+// authorizeProjectManager checks that the caller is a maintainer or owner of the {id} project, or a host.
+// It writes the error response and returns false otherwise.
+func authorizeProjectManager(w http.ResponseWriter, r *http.Request, projectSvc service.ProjectService) (userID, projectID string, ok bool) {
+	userID, project, ok := authorizeProject(w, r, projectSvc, model.ProjectRoleMaintainer, true)
+	if !ok {
+		return "", "", false
+	}
+	return userID, project.ID, true
+}
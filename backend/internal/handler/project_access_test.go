@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/pkg/auth"
+)
+
+func TestProjectAccessAllowed(t *testing.T) {
+	project := &model.Project{ID: "p1", OwnerID: "creator", Members: []*model.ProjectMember{
+		{UserID: "co-owner", Role: model.ProjectRoleOwner},
+		{UserID: "maintainer", Role: model.ProjectRoleMaintainer},
+		{UserID: "editor", Role: model.ProjectRoleEditor},
+	}}
+	ctxFor := func(userID string, host bool) context.Context {
+		return auth.WithIsHost(auth.WithUserID(context.Background(), userID), host)
+	}
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		minRole     string
+		hostAllowed bool
+		want        bool
+	}{
+		{"creator is an owner without member rows", ctxFor("creator", false), model.ProjectRoleOwner, false, true},
+		{"co-owner", ctxFor("co-owner", false), model.ProjectRoleOwner, false, true},
+		{"maintainer is not an owner", ctxFor("maintainer", false), model.ProjectRoleOwner, false, false},
+		{"maintainer", ctxFor("maintainer", false), model.ProjectRoleMaintainer, false, true},
+		{"editor is not a maintainer", ctxFor("editor", false), model.ProjectRoleMaintainer, false, false},
+		{"editor", ctxFor("editor", false), model.ProjectRoleEditor, false, true},
+		{"outsider", ctxFor("someone", false), model.ProjectRoleEditor, false, false},
+		{"host when allowed", ctxFor("host-id", true), model.ProjectRoleOwner, true, true},
+		{"host when not allowed", ctxFor("host-id", true), model.ProjectRoleEditor, false, false},
+		{"anonymous", context.Background(), model.ProjectRoleEditor, true, false},
+	}
+	for _, tt := range tests {
+		if got := projectAccessAllowed(tt.ctx, project, tt.minRole, tt.hostAllowed); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
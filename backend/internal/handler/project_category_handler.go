@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+	"github.com/givers/backend/internal/service"
+)
+
+// ProjectCategoryHandler はプロジェクトカテゴリの HTTP ハンドラ（一覧は公開、変更はホストのみ）
+type ProjectCategoryHandler struct {
+	svc service.ProjectCategoryService
+}
+
+// NewProjectCategoryHandler は ProjectCategoryHandler を生成する
+func NewProjectCategoryHandler(svc service.ProjectCategoryService) *ProjectCategoryHandler {
+	return &ProjectCategoryHandler{svc: svc}
+}
+
+type projectCategoryRequest struct {
+	Slug      string `json:"slug"`
+	Name      string `json:"name"`
+	SortOrder int    `json:"sort_order"`
+}
+
+// List は GET /api/project-categories を処理する
+func (h *ProjectCategoryHandler) List(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	categories, err := h.svc.List(r.Context())
+	if err != nil {
+		slog.Error("project category list failed", "error", err)
+		writeReceiptJSONError(w, http.StatusInternalServerError, "list_failed")
+		return
+	}
+	if categories == nil {
+		categories = []*model.ProjectCategory{}
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"categories": categories})
+}
+
+// Create は POST /api/admin/project-categories を処理する（ホストのみ）
+func (h *ProjectCategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !requireHost(w, r) {
+		return
+	}
+	var req projectCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeReceiptJSONError(w, http.StatusBadRequest, "invalid_json")
+		return
+	}
+
+	c := &model.ProjectCategory{Slug: req.Slug, Name: req.Name, SortOrder: req.SortOrder}
+	if err := h.svc.Create(r.Context(), c); err != nil {
+		h.writeError(w, err, "create_failed")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(c)
+}
+
+// Update は PUT /api/admin/project-categories/{slug} を処理する（ホストのみ。slug は変更できない）
+func (h *ProjectCategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !requireHost(w, r) {
+		return
+	}
+	var req projectCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeReceiptJSONError(w, http.StatusBadRequest, "invalid_json")
+		return
+	}
+
+	c := &model.ProjectCategory{Slug: r.PathValue("slug"), Name: req.Name, SortOrder: req.SortOrder}
+	if err := h.svc.Update(r.Context(), c); err != nil {
+		h.writeError(w, err, "update_failed")
+		return
+	}
+	_ = json.NewEncoder(w).Encode(c)
+}
+
+// Delete は DELETE /api/admin/project-categories/{slug} を処理する（ホストのみ。付いていたプロジェクトは未分類になる）
+func (h *ProjectCategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !requireHost(w, r) {
+		return
+	}
+	if err := h.svc.Delete(r.Context(), r.PathValue("slug")); err != nil {
+		h.writeError(w, err, "delete_failed")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ProjectCategoryHandler) writeError(w http.ResponseWriter, err error, code string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidProjectCategory):
+		writeReceiptJSONError(w, http.StatusBadRequest, "invalid_category")
+	case errors.Is(err, repository.ErrDuplicate):
+		writeReceiptJSONError(w, http.StatusConflict, "category_exists")
+	case errors.Is(err, repository.ErrNotFound):
+		writeReceiptJSONError(w, http.StatusNotFound, "not_found")
+	default:
+		slog.Error("project category change failed", "error", err)
+		writeReceiptJSONError(w, http.StatusInternalServerError, code)
+	}
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+)
+
+type mockProjectCategoryService struct {
+	createFunc func(ctx context.Context, c *model.ProjectCategory) error
+	deleteFunc func(ctx context.Context, slug string) error
+}
+
+func (m *mockProjectCategoryService) List(_ context.Context) ([]*model.ProjectCategory, error) {
+	return nil, nil
+}
+func (m *mockProjectCategoryService) Create(ctx context.Context, c *model.ProjectCategory) error {
+	if m.createFunc != nil {
+		return m.createFunc(ctx, c)
+	}
+	return nil
+}
+func (m *mockProjectCategoryService) Update(_ context.Context, _ *model.ProjectCategory) error {
+	return nil
+}
+func (m *mockProjectCategoryService) Delete(ctx context.Context, slug string) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(ctx, slug)
+	}
+	return nil
+}
+
+func TestProjectCategoryHandler_List_Empty(t *testing.T) {
+	h := NewProjectCategoryHandler(&mockProjectCategoryService{})
+	rec := httptest.NewRecorder()
+	h.List(rec, httptest.NewRequest(http.MethodGet, "/api/project-categories", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if strings.TrimSpace(rec.Body.String()) != `{"categories":[]}` {
+		t.Errorf("unexpected body: %s", rec.Body.String())
+	}
+}
+
+func TestProjectCategoryHandler_Create_ForbiddenForNonHost(t *testing.T) {
+	called := false
+	h := NewProjectCategoryHandler(&mockProjectCategoryService{
+		createFunc: func(_ context.Context, _ *model.ProjectCategory) error {
+			called = true
+			return nil
+		},
+	})
+	rec := httptest.NewRecorder()
+	h.Create(rec, userAuthRequest(http.MethodPost, "/api/admin/project-categories", `{"slug":"games","name":"ゲーム"}`))
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rec.Code)
+	}
+	if called {
+		t.Error("expected the service not to be called")
+	}
+}
+
+func TestProjectCategoryHandler_Create(t *testing.T) {
+	var got *model.ProjectCategory
+	h := NewProjectCategoryHandler(&mockProjectCategoryService{
+		createFunc: func(_ context.Context, c *model.ProjectCategory) error {
+			got = c
+			return nil
+		},
+	})
+	rec := httptest.NewRecorder()
+	h.Create(rec, hostRequest(http.MethodPost, "/api/admin/project-categories", `{"slug":"games","name":"ゲーム","sort_order":20}`))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got == nil || got.Slug != "games" || got.Name != "ゲーム" || got.SortOrder != 20 {
+		t.Errorf("unexpected category: %+v", got)
+	}
+}
+
+func TestProjectCategoryHandler_Create_Duplicate(t *testing.T) {
+	h := NewProjectCategoryHandler(&mockProjectCategoryService{
+		createFunc: func(_ context.Context, _ *model.ProjectCategory) error { return repository.ErrDuplicate },
+	})
+	rec := httptest.NewRecorder()
+	h.Create(rec, hostRequest(http.MethodPost, "/api/admin/project-categories", `{"slug":"games","name":"ゲーム"}`))
+
+	if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), "category_exists") {
+		t.Errorf("expected 409 category_exists, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestProjectCategoryHandler_Delete_NotFound(t *testing.T) {
+	h := NewProjectCategoryHandler(&mockProjectCategoryService{
+		deleteFunc: func(_ context.Context, _ string) error { return repository.ErrNotFound },
+	})
+	req := hostRequest(http.MethodDelete, "/api/admin/project-categories/games", "")
+	req.SetPathValue("slug", "games")
+	rec := httptest.NewRecorder()
+	h.Delete(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
@@ -54,11 +54,18 @@ func hasJSONKey(raw map[string]json.RawMessage, key string) bool {
 // ConnectAccountFunc は v2 API でアカウント作成+オンボーディング URL を返す関数
 type ConnectAccountFunc func(ctx context.Context, projectID string) (onboardingURL string, err error)
 
+// ProjectMatchingLister は実施中のマッチング寄付キャンペーンを返すミニマムインターフェース
+type ProjectMatchingLister interface {
+	ActiveByProject(ctx context.Context, projectID string) ([]*model.MatchingCampaign, error)
+}
+
 // ProjectHandler はプロジェクト CRUD の HTTP ハンドラ
 type ProjectHandler struct {
 	connectAccountFunc ConnectAccountFunc     // nil = Stripe not configured
+	onboardingLinkFunc ConnectAccountFunc     // optional, nil = no re-onboarding links in MyProjects
 	projectService     service.ProjectService
 	activityService    service.ActivityService // optional, nil = skip
+	matchingLister     ProjectMatchingLister   // optional, nil = no matching campaigns in Get
 }
 
 // NewProjectHandler は ProjectHandler を生成する
@@ -71,6 +78,28 @@ func NewProjectHandlerWithActivity(projectService service.ProjectService, connec
 	return &ProjectHandler{projectService: projectService, connectAccountFunc: connectAccountFunc, activityService: actSvc}
 }
 
+// SetOnboardingLinkFunc は GET /api/me/projects で再オンボーディング用の Account Link を生成する関数を設定する
+func (h *ProjectHandler) SetOnboardingLinkFunc(f ConnectAccountFunc) {
+	h.onboardingLinkFunc = f
+}
+
+// SetMatchingLister は GET /api/projects/{id} で実施中のマッチング寄付キャンペーンを返すための依存を設定する
+func (h *ProjectHandler) SetMatchingLister(l ProjectMatchingLister) {
+	h.matchingLister = l
+}
+
+// needsOnboarding はオーナーが Stripe のオンボーディングをやり直す必要があるかどうかを返す
+// （未完了の draft、または連結アカウントに未提出の要件がある・決済や入金が止まっている）
+func needsOnboarding(p *model.Project) bool {
+	if p.StripeAccountID == "" || p.Status == "deleted" {
+		return false
+	}
+	if p.StripeAccount != nil {
+		return p.StripeAccount.NeedsAction()
+	}
+	return p.Status == "draft"
+}
+
 // List は GET /api/projects を処理する
 func (h *ProjectHandler) List(w http.ResponseWriter, r *http.Request) {
 	sort := r.URL.Query().Get("sort")     // "new" (default) or "hot"
@@ -82,7 +111,7 @@ func (h *ProjectHandler) List(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	result, err := h.projectService.List(r.Context(), sort, limit, cursor)
+	result, err := h.projectService.List(r.Context(), sort, projectFilterFromQuery(r), limit, cursor)
 	if err != nil {
 		slog.Error("project list failed", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -94,6 +123,57 @@ func (h *ProjectHandler) List(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(result)
 }
 
+// Facets は GET /api/projects/facets を処理する（category / tag は一覧と同じ絞り込み条件）
+func (h *ProjectHandler) Facets(w http.ResponseWriter, r *http.Request) {
+	facets, err := h.projectService.Facets(r.Context(), projectFilterFromQuery(r))
+	if err != nil {
+		slog.Error("project facets failed", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "internal_error"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(facets)
+}
+
+// projectFilterFromQuery は ?category=slug&tag=a&tag=b を絞り込み条件にする（タグは AND）
+func projectFilterFromQuery(r *http.Request) model.ProjectFilter {
+	q := r.URL.Query()
+	return model.ProjectFilter{Category: q.Get("category"), Tags: q["tag"]}
+}
+
+// Search は GET /api/projects/search?q= を処理する
+func (h *ProjectHandler) Search(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit := 20
+	if l := q.Get("limit"); l != "" {
+		if n, err := strconv.Atoi(l); err == nil && n > 0 && n <= 100 {
+			limit = n
+		}
+	}
+
+	// sort: "relevance" (default), "new" or "hot"
+	result, err := h.projectService.Search(r.Context(), q.Get("q"), q.Get("sort"), limit, q.Get("cursor"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSearchQuery) {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_query"})
+			return
+		}
+		slog.Error("project search failed", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "internal_error"})
+		return
+	}
+	if result.Projects == nil {
+		result.Projects = []*model.ProjectSearchHit{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
+
 // Get は GET /api/projects/{id} を処理する
 func (h *ProjectHandler) Get(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
@@ -110,11 +190,19 @@ func (h *ProjectHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.matchingLister != nil {
+		campaigns, err := h.matchingLister.ActiveByProject(r.Context(), id)
+		if err != nil {
+			slog.Warn("matching campaigns lookup failed", "project_id", id, "error", err)
+		}
+		project.MatchingCampaigns = campaigns
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(project)
 }
 
-// MyProjects は GET /api/me/projects を処理する（認証必須）
+// MyProjects は GET /api/me/projects を処理する（認証必須）。作成したプロジェクトとメンバーになっているプロジェクトを返す
 func (h *ProjectHandler) MyProjects(w http.ResponseWriter, r *http.Request) {
 	userID, ok := auth.UserIDFromContext(r.Context())
 	if !ok {
@@ -131,6 +219,21 @@ func (h *ProjectHandler) MyProjects(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.onboardingLinkFunc != nil {
+		// オンボーディングは連結アカウントを作った作成者だけがやり直せる（メンバーとして参加したプロジェクトは除く）
+		for _, p := range projects {
+			if p.OwnerID != userID || !needsOnboarding(p) {
+				continue
+			}
+			onboardingURL, err := h.onboardingLinkFunc(r.Context(), p.ID)
+			if err != nil {
+				slog.Warn("stripe onboarding link failed", "project_id", p.ID, "error", err)
+				continue
+			}
+			p.StripeConnectURL = onboardingURL
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(projects)
 }
@@ -153,6 +256,11 @@ func (h *ProjectHandler) Create(w http.ResponseWriter, r *http.Request) {
 		Status           string                       `json:"status"`
 		OwnerWantMonthly *int                         `json:"owner_want_monthly"`
 		CostItems        []model.CostItem             `json:"cost_items"`
+		DonationTiers    []model.DonationTier         `json:"donation_tiers"`
+		PaymentMethods   []string                     `json:"payment_method_types"`
+		Category         string                       `json:"category"`
+		Tags             []string                     `json:"tags"`
+		Slug             string                       `json:"slug"`
 		Alerts           *model.ProjectAlerts         `json:"alerts"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -178,6 +286,11 @@ func (h *ProjectHandler) Create(w http.ResponseWriter, r *http.Request) {
 		Alerts:           req.Alerts,
 	}
 	project.CostItems = req.CostItems
+	project.DonationTiers = req.DonationTiers
+	project.PaymentMethodTypes = req.PaymentMethods
+	project.Category = req.Category
+	project.Tags = req.Tags
+	project.Slug = req.Slug
 	if req.Deadline != nil {
 		project.Deadline = parseDeadline(*req.Deadline)
 	}
@@ -199,6 +312,9 @@ func (h *ProjectHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.projectService.Create(r.Context(), project); err != nil {
+		if writeProjectValidationError(w, err) {
+			return
+		}
 		slog.Error("project create failed", "error", err, "user_id", userID)
 		w.WriteHeader(http.StatusInternalServerError)
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "create_failed"})
@@ -228,7 +344,34 @@ func (h *ProjectHandler) Create(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(project)
 }
 
-// Update は PUT /api/projects/{id} を処理する（認証必須）
+// writeProjectValidationError は作成・更新時の入力エラーを 400（スラッグの重複は 409）で書き込み、書き込んだかどうかを返す
+func writeProjectValidationError(w http.ResponseWriter, err error) bool {
+	var code string
+	switch {
+	case errors.Is(err, service.ErrInvalidDonationTiers):
+		code = "invalid_donation_tiers"
+	case errors.Is(err, service.ErrInvalidPaymentMethodTypes):
+		code = "invalid_payment_method_types"
+	case errors.Is(err, service.ErrInvalidProjectCategory):
+		code = "invalid_category"
+	case errors.Is(err, service.ErrInvalidProjectTags):
+		code = "invalid_tags"
+	case errors.Is(err, service.ErrInvalidProjectSlug):
+		code = "invalid_slug"
+	case errors.Is(err, service.ErrProjectSlugTaken):
+		// 他のプロジェクトが使用中（または改名前のリダイレクトとして保持中）のスラッグ
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "slug_taken"})
+		return true
+	default:
+		return false
+	}
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": code})
+	return true
+}
+
+// Update は PUT /api/projects/{id} を処理する（認証必須・メンテナー以上）
 func (h *ProjectHandler) Update(w http.ResponseWriter, r *http.Request) {
 	userID, ok := auth.UserIDFromContext(r.Context())
 	if !ok {
@@ -250,7 +393,7 @@ func (h *ProjectHandler) Update(w http.ResponseWriter, r *http.Request) {
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "not_found"})
 		return
 	}
-	if existing.OwnerID != userID {
+	if !projectAccessAllowed(r.Context(), existing, model.ProjectRoleMaintainer, false) {
 		w.WriteHeader(http.StatusForbidden)
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "forbidden"})
 		return
@@ -311,6 +454,58 @@ func (h *ProjectHandler) Update(w http.ResponseWriter, r *http.Request) {
 		_ = json.Unmarshal(b, &items)
 		existing.CostItems = items
 	}
+	if b, ok := raw["donation_tiers"]; ok {
+		var tiers []model.DonationTier
+		if err := json.Unmarshal(b, &tiers); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_donation_tiers"})
+			return
+		}
+		existing.DonationTiers = tiers
+	}
+	if b, ok := raw["payment_method_types"]; ok {
+		var types []string
+		if err := json.Unmarshal(b, &types); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_payment_method_types"})
+			return
+		}
+		existing.PaymentMethodTypes = types
+	}
+	if b, ok := raw["category"]; ok {
+		var v *string
+		if err := json.Unmarshal(b, &v); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_category"})
+			return
+		}
+		existing.Category = ""
+		if v != nil {
+			existing.Category = *v
+		}
+	}
+	if b, ok := raw["tags"]; ok {
+		var tags []string
+		if err := json.Unmarshal(b, &tags); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_tags"})
+			return
+		}
+		existing.Tags = tags
+	}
+	if b, ok := raw["slug"]; ok {
+		// null または空文字でスラッグを外す（外したスラッグもリダイレクトとして残る）
+		var v *string
+		if err := json.Unmarshal(b, &v); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_slug"})
+			return
+		}
+		existing.Slug = ""
+		if v != nil {
+			existing.Slug = *v
+		}
+	}
 	if b, ok := raw["alerts"]; ok {
 		var v *model.ProjectAlerts
 		_ = json.Unmarshal(b, &v)
@@ -319,7 +514,10 @@ func (h *ProjectHandler) Update(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	if err := h.projectService.Update(r.Context(), existing); err != nil {
+	if err := h.projectService.UpdateWithRevision(r.Context(), userID, existing); err != nil {
+		if writeProjectValidationError(w, err) {
+			return
+		}
 		slog.Error("project update failed", "error", err, "project_id", id)
 		w.WriteHeader(http.StatusInternalServerError)
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "update_failed"})
@@ -339,12 +537,12 @@ func (h *ProjectHandler) Update(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(existing)
 }
 
-// PatchStatus は PATCH /api/projects/{id}/status を処理する（認証必須・オーナーまたはホスト）。
+// PatchStatus は PATCH /api/projects/{id}/status を処理する（認証必須・メンテナー以上またはホスト）。
 // 許可されるステータス: "active", "frozen"
 func (h *ProjectHandler) PatchStatus(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	userID, ok := auth.UserIDFromContext(r.Context())
+	_, ok := auth.UserIDFromContext(r.Context())
 	if !ok {
 		w.WriteHeader(http.StatusUnauthorized)
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "unauthorized"})
@@ -360,8 +558,7 @@ func (h *ProjectHandler) PatchStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	isHost := auth.IsHostFromContext(r.Context())
-	if !isHost && existing.OwnerID != userID {
+	if !projectAccessAllowed(r.Context(), existing, model.ProjectRoleMaintainer, true) {
 		w.WriteHeader(http.StatusForbidden)
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "forbidden"})
 		return
@@ -398,7 +595,7 @@ func (h *ProjectHandler) PatchStatus(w http.ResponseWriter, r *http.Request) {
 func (h *ProjectHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	userID, ok := auth.UserIDFromContext(r.Context())
+	_, ok := auth.UserIDFromContext(r.Context())
 	if !ok {
 		w.WriteHeader(http.StatusUnauthorized)
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "unauthorized"})
@@ -413,7 +610,7 @@ func (h *ProjectHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "not_found"})
 		return
 	}
-	if existing.OwnerID != userID {
+	if !projectAccessAllowed(r.Context(), existing, model.ProjectRoleOwner, false) {
 		w.WriteHeader(http.StatusForbidden)
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "forbidden"})
 		return
@@ -433,3 +630,63 @@ func (h *ProjectHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	_ = json.NewEncoder(w).Encode(map[string]bool{"ok": true})
 }
+
+// Revisions は GET /api/projects/{id}/revisions を処理する（認証不要）。
+// 予算などの変更の経緯を寄付者が確認できるよう、更新履歴を新しい順に返す
+func (h *ProjectHandler) Revisions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id := r.PathValue("id")
+
+	if _, err := h.projectService.GetByID(r.Context(), id); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "not_found"})
+		return
+	}
+	revisions, err := h.projectService.ListRevisions(r.Context(), id)
+	if err != nil {
+		slog.Error("project revisions list failed", "error", err, "project_id", id)
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "internal_error"})
+		return
+	}
+	if revisions == nil {
+		revisions = []*model.ProjectRevision{}
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"revisions": revisions})
+}
+
+// RestoreRevision は POST /api/projects/{id}/revisions/{rid}/restore を処理する（認証必須・オーナーまたはホスト）。
+// プロジェクトを指定した履歴の更新後の内容に戻し、復元した後のプロジェクトを返す
+func (h *ProjectHandler) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	userID, project, ok := authorizeProject(w, r, h.projectService, model.ProjectRoleOwner, true)
+	if !ok {
+		return
+	}
+	revisionID := r.PathValue("rid")
+
+	restored, err := h.projectService.RestoreRevision(r.Context(), userID, project.ID, revisionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "revision_not_found"})
+			return
+		}
+		if writeProjectValidationError(w, err) {
+			return
+		}
+		slog.Error("project revision restore failed", "error", err, "project_id", project.ID, "revision_id", revisionID)
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "restore_failed"})
+		return
+	}
+
+	if h.activityService != nil {
+		_ = h.activityService.Record(r.Context(), &model.ActivityItem{
+			Type:      "project_updated",
+			ProjectID: project.ID,
+			ActorName: &userID,
+		})
+	}
+	_ = json.NewEncoder(w).Encode(restored)
+}
@@ -7,25 +7,34 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+	"github.com/givers/backend/internal/service"
 	"github.com/givers/backend/pkg/auth"
 )
 
 // mockProjectService は ProjectService のモック
 type mockProjectService struct {
-	listFunc          func(ctx context.Context, sort string, limit int, cursor string) (*model.ProjectListResult, error)
+	listFunc          func(ctx context.Context, sort string, filter model.ProjectFilter, limit int, cursor string) (*model.ProjectListResult, error)
+	facetsFunc        func(ctx context.Context, filter model.ProjectFilter) (*model.ProjectFacets, error)
+	searchFunc        func(ctx context.Context, query, sort string, limit int, cursor string) (*model.ProjectSearchResult, error)
 	getByIDFunc       func(ctx context.Context, id string) (*model.Project, error)
 	listByOwnerIDFunc func(ctx context.Context, ownerID string) ([]*model.Project, error)
 	createFunc        func(ctx context.Context, project *model.Project) error
 	updateFunc        func(ctx context.Context, project *model.Project) error
 	deleteFunc        func(ctx context.Context, id string) error
+
+	updateWithRevisionFunc func(ctx context.Context, actorID string, project *model.Project) error
+	listRevisionsFunc      func(ctx context.Context, projectID string) ([]*model.ProjectRevision, error)
+	restoreRevisionFunc    func(ctx context.Context, actorID, projectID, revisionID string) (*model.Project, error)
 }
 
-func (m *mockProjectService) List(ctx context.Context, sort string, limit int, cursor string) (*model.ProjectListResult, error) {
+func (m *mockProjectService) List(ctx context.Context, sort string, filter model.ProjectFilter, limit int, cursor string) (*model.ProjectListResult, error) {
 	if m.listFunc != nil {
-		return m.listFunc(ctx, sort, limit, cursor)
+		return m.listFunc(ctx, sort, filter, limit, cursor)
 	}
 	return &model.ProjectListResult{}, nil
 }
@@ -37,6 +46,20 @@ func (m *mockProjectService) GetByID(ctx context.Context, id string) (*model.Pro
 	return nil, errors.New("not found")
 }
 
+func (m *mockProjectService) Facets(ctx context.Context, filter model.ProjectFilter) (*model.ProjectFacets, error) {
+	if m.facetsFunc != nil {
+		return m.facetsFunc(ctx, filter)
+	}
+	return &model.ProjectFacets{}, nil
+}
+
+func (m *mockProjectService) Search(ctx context.Context, query, sort string, limit int, cursor string) (*model.ProjectSearchResult, error) {
+	if m.searchFunc != nil {
+		return m.searchFunc(ctx, query, sort, limit, cursor)
+	}
+	return &model.ProjectSearchResult{}, nil
+}
+
 func (m *mockProjectService) ListByOwnerID(ctx context.Context, ownerID string) ([]*model.Project, error) {
 	if m.listByOwnerIDFunc != nil {
 		return m.listByOwnerIDFunc(ctx, ownerID)
@@ -58,6 +81,28 @@ func (m *mockProjectService) Update(ctx context.Context, project *model.Project)
 	return nil
 }
 
+// UpdateWithRevision は updateWithRevisionFunc がなければ updateFunc を使う
+func (m *mockProjectService) UpdateWithRevision(ctx context.Context, actorID string, project *model.Project) error {
+	if m.updateWithRevisionFunc != nil {
+		return m.updateWithRevisionFunc(ctx, actorID, project)
+	}
+	return m.Update(ctx, project)
+}
+
+func (m *mockProjectService) ListRevisions(ctx context.Context, projectID string) ([]*model.ProjectRevision, error) {
+	if m.listRevisionsFunc != nil {
+		return m.listRevisionsFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+
+func (m *mockProjectService) RestoreRevision(ctx context.Context, actorID, projectID, revisionID string) (*model.Project, error) {
+	if m.restoreRevisionFunc != nil {
+		return m.restoreRevisionFunc(ctx, actorID, projectID, revisionID)
+	}
+	return nil, nil
+}
+
 func (m *mockProjectService) Delete(ctx context.Context, id string) error {
 	if m.deleteFunc != nil {
 		return m.deleteFunc(ctx, id)
@@ -70,7 +115,7 @@ func TestProjectHandler_List(t *testing.T) {
 		Projects: []*model.Project{{ID: "1", Name: "P1"}},
 	}
 	mock := &mockProjectService{
-		listFunc: func(ctx context.Context, sort string, limit int, cursor string) (*model.ProjectListResult, error) {
+		listFunc: func(ctx context.Context, sort string, filter model.ProjectFilter, limit int, cursor string) (*model.ProjectListResult, error) {
 			return result, nil
 		},
 	}
@@ -98,7 +143,7 @@ func TestProjectHandler_List(t *testing.T) {
 func TestProjectHandler_List_SortHot(t *testing.T) {
 	var capturedSort string
 	mock := &mockProjectService{
-		listFunc: func(ctx context.Context, sort string, limit int, cursor string) (*model.ProjectListResult, error) {
+		listFunc: func(ctx context.Context, sort string, filter model.ProjectFilter, limit int, cursor string) (*model.ProjectListResult, error) {
 			capturedSort = sort
 			return &model.ProjectListResult{
 				Projects: []*model.Project{{ID: "hot-1", Name: "Hot"}},
@@ -125,7 +170,7 @@ func TestProjectHandler_List_SortHot(t *testing.T) {
 func TestProjectHandler_List_WithCursor(t *testing.T) {
 	var capturedCursor string
 	mock := &mockProjectService{
-		listFunc: func(ctx context.Context, sort string, limit int, cursor string) (*model.ProjectListResult, error) {
+		listFunc: func(ctx context.Context, sort string, filter model.ProjectFilter, limit int, cursor string) (*model.ProjectListResult, error) {
 			capturedCursor = cursor
 			return &model.ProjectListResult{
 				Projects:   []*model.Project{{ID: "2", Name: "P2"}},
@@ -157,6 +202,137 @@ func TestProjectHandler_List_WithCursor(t *testing.T) {
 	}
 }
 
+func TestProjectHandler_List_Filter(t *testing.T) {
+	var got model.ProjectFilter
+	mock := &mockProjectService{
+		listFunc: func(ctx context.Context, sort string, filter model.ProjectFilter, limit int, cursor string) (*model.ProjectListResult, error) {
+			got = filter
+			return &model.ProjectListResult{}, nil
+		},
+	}
+	h := NewProjectHandler(mock, nil)
+	req := httptest.NewRequest("GET", "/api/projects?category=games&tag=go&tag=cli", nil)
+	rec := httptest.NewRecorder()
+	h.List(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if got.Category != "games" || len(got.Tags) != 2 || got.Tags[0] != "go" || got.Tags[1] != "cli" {
+		t.Errorf("unexpected filter: %+v", got)
+	}
+}
+
+func TestProjectHandler_Facets(t *testing.T) {
+	var got model.ProjectFilter
+	mock := &mockProjectService{
+		facetsFunc: func(ctx context.Context, filter model.ProjectFilter) (*model.ProjectFacets, error) {
+			got = filter
+			return &model.ProjectFacets{
+				Categories: []*model.ProjectFacetCount{{Value: "games", Name: "ゲーム", Count: 2}},
+				Tags:       []*model.ProjectFacetCount{{Value: "go", Count: 1}},
+			}, nil
+		},
+	}
+	h := NewProjectHandler(mock, nil)
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /api/projects/facets", http.HandlerFunc(h.Facets))
+	mux.Handle("GET /api/projects/{id}", http.HandlerFunc(h.Get))
+
+	req := httptest.NewRequest("GET", "/api/projects/facets?tag=go", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if got.Category != "" || len(got.Tags) != 1 || got.Tags[0] != "go" {
+		t.Errorf("unexpected filter: %+v", got)
+	}
+	var body model.ProjectFacets
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(body.Categories) != 1 || body.Categories[0].Count != 2 || len(body.Tags) != 1 {
+		t.Errorf("unexpected facets: %+v", body)
+	}
+}
+
+func TestProjectHandler_Search(t *testing.T) {
+	var gotQuery, gotSort, gotCursor string
+	var gotLimit int
+	mock := &mockProjectService{
+		searchFunc: func(ctx context.Context, query, sort string, limit int, cursor string) (*model.ProjectSearchResult, error) {
+			gotQuery, gotSort, gotLimit, gotCursor = query, sort, limit, cursor
+			return &model.ProjectSearchResult{
+				Projects: []*model.ProjectSearchHit{{
+					Project:   &model.Project{ID: "1", Name: "寄付サイト"},
+					Rank:      0.5,
+					Highlight: &model.ProjectSearchHighlight{Name: "<mark>寄付</mark>サイト"},
+				}},
+				NextCursor: "1",
+			}, nil
+		},
+	}
+	h := NewProjectHandler(mock, nil)
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /api/projects/search", http.HandlerFunc(h.Search))
+	mux.Handle("GET /api/projects/{id}", http.HandlerFunc(h.Get))
+
+	req := httptest.NewRequest("GET", "/api/projects/search?q=%E5%AF%84%E4%BB%98&sort=new&limit=5&cursor=prev", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if gotQuery != "寄付" || gotSort != "new" || gotLimit != 5 || gotCursor != "prev" {
+		t.Errorf("unexpected args: q=%q sort=%q limit=%d cursor=%q", gotQuery, gotSort, gotLimit, gotCursor)
+	}
+	var got model.ProjectSearchResult
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(got.Projects) != 1 || got.Projects[0].Name != "寄付サイト" || got.Projects[0].Highlight == nil || got.NextCursor != "1" {
+		t.Errorf("unexpected result: %+v", got)
+	}
+}
+
+func TestProjectHandler_Search_InvalidQuery(t *testing.T) {
+	mock := &mockProjectService{
+		searchFunc: func(ctx context.Context, query, sort string, limit int, cursor string) (*model.ProjectSearchResult, error) {
+			return nil, service.ErrInvalidSearchQuery
+		},
+	}
+	h := NewProjectHandler(mock, nil)
+	req := httptest.NewRequest("GET", "/api/projects/search?q=", nil)
+	rec := httptest.NewRecorder()
+	h.Search(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "invalid_query") {
+		t.Errorf("unexpected body: %s", rec.Body.String())
+	}
+}
+
+func TestProjectHandler_Search_EmptyResult(t *testing.T) {
+	h := NewProjectHandler(&mockProjectService{}, nil)
+	req := httptest.NewRequest("GET", "/api/projects/search?q=go", nil)
+	rec := httptest.NewRecorder()
+	h.Search(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `"projects":[]`) {
+		t.Errorf("expected an empty projects array, got %s", rec.Body.String())
+	}
+}
+
 func TestProjectHandler_Get_NotFound(t *testing.T) {
 	mock := &mockProjectService{
 		getByIDFunc: func(ctx context.Context, id string) (*model.Project, error) {
@@ -208,6 +384,38 @@ func TestProjectHandler_Get_Success(t *testing.T) {
 	}
 }
 
+func TestProjectHandler_Get_MatchingCampaigns(t *testing.T) {
+	mock := &mockProjectService{
+		getByIDFunc: func(ctx context.Context, id string) (*model.Project, error) {
+			return &model.Project{ID: id, Name: "Project 1"}, nil
+		},
+	}
+	h := NewProjectHandler(mock, nil)
+	h.SetMatchingLister(&mockMatchingService{
+		activeFunc: func(ctx context.Context, projectID string) ([]*model.MatchingCampaign, error) {
+			return []*model.MatchingCampaign{{ID: "mc-1", ProjectID: projectID, SponsorName: "Acme", CapAmount: 50000, MatchedAmount: 1200}}, nil
+		},
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /api/projects/{id}", http.HandlerFunc(h.Get))
+
+	req := httptest.NewRequest("GET", "/api/projects/p1", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var got model.Project
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(got.MatchingCampaigns) != 1 || got.MatchingCampaigns[0].SponsorName != "Acme" || got.MatchingCampaigns[0].MatchedAmount != 1200 {
+		t.Errorf("unexpected matching campaigns: %+v", got.MatchingCampaigns)
+	}
+}
+
 func TestProjectHandler_MyProjects_Unauthorized(t *testing.T) {
 	mock := &mockProjectService{}
 	h := NewProjectHandler(mock, nil)
@@ -256,6 +464,49 @@ func TestProjectHandler_MyProjects_WithAuth(t *testing.T) {
 	}
 }
 
+func TestProjectHandler_MyProjects_OnboardingLinks(t *testing.T) {
+	projects := []*model.Project{
+		{ID: "ok", OwnerID: "u1", Status: "active", StripeAccountID: "acct_ok", StripeAccount: &model.StripeAccountStatus{ChargesEnabled: true, PayoutsEnabled: true, Requirements: []string{}}},
+		{ID: "restricted", OwnerID: "u1", Status: "frozen", StripeAccountID: "acct_r", StripeAccount: &model.StripeAccountStatus{ChargesEnabled: false, PayoutsEnabled: true, Requirements: []string{"individual.verification.document"}}},
+		{ID: "draft", OwnerID: "u1", Status: "draft", StripeAccountID: "acct_d"},
+		{ID: "host", OwnerID: "u1", Status: "active"},
+		// a project u1 joined as a member: only its creator can redo the onboarding
+		{ID: "member", OwnerID: "u2", Status: "draft", StripeAccountID: "acct_m"},
+	}
+	mock := &mockProjectService{
+		listByOwnerIDFunc: func(_ context.Context, _ string) ([]*model.Project, error) { return projects, nil },
+	}
+	var linked []string
+	h := NewProjectHandler(mock, nil)
+	h.SetOnboardingLinkFunc(func(_ context.Context, projectID string) (string, error) {
+		linked = append(linked, projectID)
+		return "https://connect.stripe.com/setup/" + projectID, nil
+	})
+
+	req := httptest.NewRequest("GET", "/api/me/projects", nil)
+	req = req.WithContext(auth.WithUserID(req.Context(), "u1"))
+	rec := httptest.NewRecorder()
+	h.MyProjects(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var got []*model.Project
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(linked) != 2 || linked[0] != "restricted" || linked[1] != "draft" {
+		t.Errorf("linked = %v, want [restricted draft]", linked)
+	}
+	if got[0].StripeConnectURL != "" {
+		t.Errorf("capable project should not get a link, got %q", got[0].StripeConnectURL)
+	}
+	if got[1].StripeConnectURL != "https://connect.stripe.com/setup/restricted" || got[1].StripeAccount == nil ||
+		got[1].StripeAccount.Requirements[0] != "individual.verification.document" {
+		t.Errorf("restricted project = %+v", got[1])
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Create: activity recording tests (uses mockActivityService from activity_handler_test.go)
 // ---------------------------------------------------------------------------
@@ -430,6 +681,117 @@ func TestProjectHandler_Create_Success(t *testing.T) {
 	}
 }
 
+func TestProjectHandler_Create_CategoryAndTags(t *testing.T) {
+	var created *model.Project
+	mock := &mockProjectService{
+		createFunc: func(ctx context.Context, project *model.Project) error {
+			created = project
+			return nil
+		},
+	}
+	h := NewProjectHandler(mock, nil)
+	req := userAuthRequest(http.MethodPost, "/api/projects", `{"name":"P","category":"games","tags":["go","cli"]}`)
+	rec := httptest.NewRecorder()
+	h.Create(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if created.Category != "games" || len(created.Tags) != 2 {
+		t.Errorf("unexpected project: category=%q tags=%v", created.Category, created.Tags)
+	}
+}
+
+func TestProjectHandler_Create_InvalidTaxonomy(t *testing.T) {
+	tests := []struct {
+		err  error
+		code string
+	}{
+		{service.ErrInvalidProjectCategory, "invalid_category"},
+		{service.ErrInvalidProjectTags, "invalid_tags"},
+	}
+	for _, tt := range tests {
+		mock := &mockProjectService{
+			createFunc: func(ctx context.Context, project *model.Project) error { return tt.err },
+		}
+		h := NewProjectHandler(mock, nil)
+		req := userAuthRequest(http.MethodPost, "/api/projects", `{"name":"P","category":"nope"}`)
+		rec := httptest.NewRecorder()
+		h.Create(rec, req)
+
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), tt.code) {
+			t.Errorf("%v: expected 400 %s, got %d %s", tt.err, tt.code, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestProjectHandler_Update_Slug(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+		wantSlug   string
+	}{
+		{"renames", `{"slug":"new-name"}`, nil, http.StatusOK, "new-name"},
+		{"null clears", `{"slug":null}`, nil, http.StatusOK, ""},
+		{"invalid", `{"slug":"x"}`, service.ErrInvalidProjectSlug, http.StatusBadRequest, "x"},
+		{"taken", `{"slug":"taken"}`, service.ErrProjectSlugTaken, http.StatusConflict, "taken"},
+	}
+	for _, tt := range tests {
+		var updated *model.Project
+		mock := &mockProjectService{
+			getByIDFunc: func(ctx context.Context, id string) (*model.Project, error) {
+				return &model.Project{ID: id, OwnerID: "user-1", Name: "P", Slug: "old-name"}, nil
+			},
+			updateFunc: func(ctx context.Context, project *model.Project) error {
+				updated = project
+				return tt.err
+			},
+		}
+		h := NewProjectHandler(mock, nil)
+		req := userAuthRequest(http.MethodPut, "/api/projects/p1", tt.body)
+		req.SetPathValue("id", "p1")
+		rec := httptest.NewRecorder()
+		h.Update(rec, req)
+
+		if rec.Code != tt.wantStatus {
+			t.Errorf("%s: expected %d, got %d: %s", tt.name, tt.wantStatus, rec.Code, rec.Body.String())
+		}
+		if tt.err == nil && updated.Slug != tt.wantSlug {
+			t.Errorf("%s: expected slug %q, got %q", tt.name, tt.wantSlug, updated.Slug)
+		}
+		if tt.err == service.ErrProjectSlugTaken && !strings.Contains(rec.Body.String(), "slug_taken") {
+			t.Errorf("%s: expected slug_taken, got %s", tt.name, rec.Body.String())
+		}
+	}
+}
+
+func TestProjectHandler_Update_ClearsCategory(t *testing.T) {
+	var updated *model.Project
+	mock := &mockProjectService{
+		getByIDFunc: func(ctx context.Context, id string) (*model.Project, error) {
+			return &model.Project{ID: id, OwnerID: "user-1", Name: "P", Category: "games", Tags: []string{"go"}}, nil
+		},
+		updateFunc: func(ctx context.Context, project *model.Project) error {
+			updated = project
+			return nil
+		},
+	}
+	h := NewProjectHandler(mock, nil)
+	req := userAuthRequest(http.MethodPut, "/api/projects/p1", `{"category":null,"tags":["rust"]}`)
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.Update(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if updated.Category != "" || len(updated.Tags) != 1 || updated.Tags[0] != "rust" {
+		t.Errorf("unexpected project: category=%q tags=%v", updated.Category, updated.Tags)
+	}
+}
+
 func TestProjectHandler_Create_WithDeadline_YYYYMMDD(t *testing.T) {
 	var created *model.Project
 	mock := &mockProjectService{
@@ -1050,3 +1412,220 @@ func TestProjectHandler_PatchStatus_NotFound(t *testing.T) {
 		t.Errorf("expected 404, got %d", rec.Code)
 	}
 }
+
+func TestProjectHandler_Update_DonationTiers(t *testing.T) {
+	var updated *model.Project
+	mock := &mockProjectService{
+		getByIDFunc: func(ctx context.Context, id string) (*model.Project, error) {
+			return &model.Project{ID: id, OwnerID: "u1", Name: "P1", DonationTiers: []model.DonationTier{{ID: "old", Label: "旧", Amount: 100}}}, nil
+		},
+		updateFunc: func(ctx context.Context, p *model.Project) error {
+			updated = p
+			return nil
+		},
+	}
+	h := NewProjectHandler(mock, nil)
+
+	mux := http.NewServeMux()
+	mux.Handle("PUT /api/projects/{id}", http.HandlerFunc(h.Update))
+
+	body := bytes.NewBufferString(`{"donation_tiers":[{"label":"応援","amount":500},{"label":"月額","amount":3000,"is_recurring":true}]}`)
+	req := httptest.NewRequest("PUT", "/api/projects/p1", body)
+	req = req.WithContext(auth.WithUserID(context.Background(), "u1"))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", rec.Code, rec.Body.String())
+	}
+	if updated == nil || len(updated.DonationTiers) != 2 || updated.DonationTiers[1].Amount != 3000 || !updated.DonationTiers[1].IsRecurring {
+		t.Errorf("expected tiers to be replaced, got %+v", updated)
+	}
+}
+
+func TestProjectHandler_Update_InvalidDonationTiers(t *testing.T) {
+	mock := &mockProjectService{
+		getByIDFunc: func(ctx context.Context, id string) (*model.Project, error) {
+			return &model.Project{ID: id, OwnerID: "u1", Name: "P1"}, nil
+		},
+		updateFunc: func(ctx context.Context, p *model.Project) error {
+			return service.ErrInvalidDonationTiers
+		},
+	}
+	h := NewProjectHandler(mock, nil)
+
+	mux := http.NewServeMux()
+	mux.Handle("PUT /api/projects/{id}", http.HandlerFunc(h.Update))
+
+	body := bytes.NewBufferString(`{"donation_tiers":[{"label":"","amount":0}]}`)
+	req := httptest.NewRequest("PUT", "/api/projects/p1", body)
+	req = req.WithContext(auth.WithUserID(context.Background(), "u1"))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "invalid_donation_tiers") {
+		t.Errorf("expected 400 invalid_donation_tiers, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestProjectHandler_Update_PaymentMethodTypes(t *testing.T) {
+	var updated *model.Project
+	mock := &mockProjectService{
+		getByIDFunc: func(ctx context.Context, id string) (*model.Project, error) {
+			return &model.Project{ID: id, OwnerID: "u1", Name: "P1"}, nil
+		},
+		updateFunc: func(ctx context.Context, p *model.Project) error {
+			updated = p
+			if len(p.PaymentMethodTypes) > 0 && p.PaymentMethodTypes[0] == "paypay" {
+				return service.ErrInvalidPaymentMethodTypes
+			}
+			return nil
+		},
+	}
+	h := NewProjectHandler(mock, nil)
+
+	mux := http.NewServeMux()
+	mux.Handle("PUT /api/projects/{id}", http.HandlerFunc(h.Update))
+
+	tests := []struct {
+		body string
+		code int
+	}{
+		{`{"payment_method_types":["card","konbini"]}`, http.StatusOK},
+		{`{"payment_method_types":["paypay"]}`, http.StatusBadRequest},
+		{`{"payment_method_types":"konbini"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("PUT", "/api/projects/p1", bytes.NewBufferString(tt.body))
+		req = req.WithContext(auth.WithUserID(context.Background(), "u1"))
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != tt.code {
+			t.Errorf("%s: expected %d, got %d — body: %s", tt.body, tt.code, rec.Code, rec.Body.String())
+		}
+		if tt.code == http.StatusOK && (updated == nil || len(updated.PaymentMethodTypes) != 2) {
+			t.Errorf("%s: expected payment methods to be replaced, got %+v", tt.body, updated)
+		}
+		if tt.code == http.StatusBadRequest && !strings.Contains(rec.Body.String(), "invalid_payment_method_types") {
+			t.Errorf("%s: unexpected body: %s", tt.body, rec.Body.String())
+		}
+	}
+}
+
+func TestProjectHandler_Update_RecordsRevisionActor(t *testing.T) {
+	var actor string
+	mock := &mockProjectService{
+		getByIDFunc: func(ctx context.Context, id string) (*model.Project, error) {
+			return &model.Project{ID: id, OwnerID: "user-1", Name: "P"}, nil
+		},
+		updateWithRevisionFunc: func(ctx context.Context, actorID string, project *model.Project) error {
+			actor = actorID
+			return nil
+		},
+	}
+	h := NewProjectHandler(mock, nil)
+	req := userAuthRequest(http.MethodPut, "/api/projects/p1", `{"owner_want_monthly":30000}`)
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.Update(rec, req)
+
+	if rec.Code != http.StatusOK || actor != "user-1" {
+		t.Errorf("expected 200 with actor user-1, got %d actor=%q", rec.Code, actor)
+	}
+}
+
+func TestProjectHandler_Revisions(t *testing.T) {
+	mock := &mockProjectService{
+		getByIDFunc: func(ctx context.Context, id string) (*model.Project, error) {
+			if id != "p1" {
+				return nil, repository.ErrNotFound
+			}
+			return &model.Project{ID: "p1", OwnerID: "user-1"}, nil
+		},
+		listRevisionsFunc: func(ctx context.Context, projectID string) ([]*model.ProjectRevision, error) {
+			return []*model.ProjectRevision{{
+				ID: "r1", ProjectID: projectID, ActorID: "user-1", Action: model.ProjectRevisionUpdate,
+				Changes:  map[string]model.ProjectFieldChange{"monthly_target": {From: json.RawMessage("10000"), To: json.RawMessage("20000")}},
+				Snapshot: model.ProjectSnapshot{Name: "P"},
+			}}, nil
+		},
+	}
+	h := NewProjectHandler(mock, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/projects/p1/revisions", nil)
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.Revisions(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, `"monthly_target":{"from":10000,"to":20000}`) || strings.Contains(body, "snapshot") {
+		t.Errorf("unexpected body: %s", body)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/projects/missing/revisions", nil)
+	req.SetPathValue("id", "missing")
+	rec = httptest.NewRecorder()
+	h.Revisions(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestProjectHandler_RestoreRevision(t *testing.T) {
+	newMock := func(restoreErr error) (*mockProjectService, *string) {
+		var actor string
+		return &mockProjectService{
+			getByIDFunc: func(ctx context.Context, id string) (*model.Project, error) {
+				return &model.Project{ID: id, OwnerID: "owner", Members: []*model.ProjectMember{
+					{UserID: "user-1", Role: model.ProjectRoleMaintainer},
+				}}, nil
+			},
+			restoreRevisionFunc: func(ctx context.Context, actorID, projectID, revisionID string) (*model.Project, error) {
+				actor = actorID
+				if restoreErr != nil {
+					return nil, restoreErr
+				}
+				return &model.Project{ID: projectID, Name: "Restored"}, nil
+			},
+		}, &actor
+	}
+	restore := func(h *ProjectHandler, req *http.Request) *httptest.ResponseRecorder {
+		req.SetPathValue("id", "p1")
+		req.SetPathValue("rid", "r1")
+		rec := httptest.NewRecorder()
+		h.RestoreRevision(rec, req)
+		return rec
+	}
+
+	// メンテナーは復元できない
+	mock, _ := newMock(nil)
+	rec := restore(NewProjectHandler(mock, nil), userAuthRequest(http.MethodPost, "/api/projects/p1/revisions/r1/restore", ""))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("maintainer: expected 403, got %d", rec.Code)
+	}
+
+	mock, actor := newMock(nil)
+	rec = restore(NewProjectHandler(mock, nil), hostRequest(http.MethodPost, "/api/projects/p1/revisions/r1/restore", ""))
+	if rec.Code != http.StatusOK || *actor != "host-id" || !strings.Contains(rec.Body.String(), "Restored") {
+		t.Errorf("host: got %d actor=%q %s", rec.Code, *actor, rec.Body.String())
+	}
+
+	for _, tt := range []struct {
+		err  error
+		code int
+		body string
+	}{
+		{repository.ErrNotFound, http.StatusNotFound, "revision_not_found"},
+		{service.ErrProjectSlugTaken, http.StatusConflict, "slug_taken"},
+		{service.ErrInvalidProjectCategory, http.StatusBadRequest, "invalid_category"},
+	} {
+		mock, _ := newMock(tt.err)
+		rec := restore(NewProjectHandler(mock, nil), hostRequest(http.MethodPost, "/api/projects/p1/revisions/r1/restore", ""))
+		if rec.Code != tt.code || !strings.Contains(rec.Body.String(), tt.body) {
+			t.Errorf("%v: expected %d %s, got %d %s", tt.err, tt.code, tt.body, rec.Code, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+	"github.com/givers/backend/internal/service"
+	"github.com/givers/backend/pkg/auth"
+)
+
+// ProjectMemberHandler はプロジェクトのメンバーと招待の HTTP ハンドラ。
+// メンバーと招待の管理は owner のみ、一覧はメンバーとホスト、招待への応答は招待された本人が行う
+type ProjectMemberHandler struct {
+	svc        service.ProjectMemberService
+	projectSvc service.ProjectService
+}
+
+// NewProjectMemberHandler は ProjectMemberHandler を生成する
+func NewProjectMemberHandler(svc service.ProjectMemberService, projectSvc service.ProjectService) *ProjectMemberHandler {
+	return &ProjectMemberHandler{svc: svc, projectSvc: projectSvc}
+}
+
+// List は GET /api/projects/{id}/members を処理する（メンバーまたはホスト）。
+// 保留中の招待は owner とホストにだけ返す
+func (h *ProjectMemberHandler) List(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, project, ok := authorizeProject(w, r, h.projectSvc, model.ProjectRoleEditor, true)
+	if !ok {
+		return
+	}
+
+	members, err := h.svc.ListMembers(r.Context(), project.ID)
+	if err != nil {
+		slog.Error("project member list failed", "error", err, "project_id", project.ID)
+		writeReceiptJSONError(w, http.StatusInternalServerError, "list_failed")
+		return
+	}
+	if members == nil {
+		members = []*model.ProjectMember{}
+	}
+	resp := map[string]any{"members": members}
+
+	if projectAccessAllowed(r.Context(), project, model.ProjectRoleOwner, true) {
+		invitations, err := h.svc.ListInvitations(r.Context(), project.ID)
+		if err != nil {
+			slog.Error("project invitation list failed", "error", err, "project_id", project.ID)
+			writeReceiptJSONError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		if invitations == nil {
+			invitations = []*model.ProjectInvitation{}
+		}
+		resp["invitations"] = invitations
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// Invite は POST /api/projects/{id}/invitations を処理する（owner のみ）。
+// body: {"email": "...", "role": "maintainer"} または {"user_id": "...", "role": "editor"}
+func (h *ProjectMemberHandler) Invite(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	userID, project, ok := authorizeProject(w, r, h.projectSvc, model.ProjectRoleOwner, false)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Email  string `json:"email"`
+		UserID string `json:"user_id"`
+		Role   string `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeReceiptJSONError(w, http.StatusBadRequest, "invalid_json")
+		return
+	}
+
+	inv, err := h.svc.Invite(r.Context(), project, userID, service.ProjectInvitationInput{Email: req.Email, UserID: req.UserID, Role: req.Role})
+	if err != nil {
+		writeProjectMemberError(w, err, "invite_failed", "project_id", project.ID)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(inv)
+}
+
+// RevokeInvitation は DELETE /api/projects/{id}/invitations/{iid} を処理する（owner のみ）
+func (h *ProjectMemberHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, project, ok := authorizeProject(w, r, h.projectSvc, model.ProjectRoleOwner, false)
+	if !ok {
+		return
+	}
+	if err := h.svc.RevokeInvitation(r.Context(), project.ID, r.PathValue("iid")); err != nil {
+		writeProjectMemberError(w, err, "revoke_failed", "project_id", project.ID)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+}
+
+// UpdateRole は PATCH /api/projects/{id}/members/{uid} を処理する（owner のみ）。body: {"role": "editor"}
+func (h *ProjectMemberHandler) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, project, ok := authorizeProject(w, r, h.projectSvc, model.ProjectRoleOwner, false)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Role string `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeReceiptJSONError(w, http.StatusBadRequest, "invalid_json")
+		return
+	}
+	if err := h.svc.ChangeRole(r.Context(), project, r.PathValue("uid"), req.Role); err != nil {
+		writeProjectMemberError(w, err, "update_failed", "project_id", project.ID)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+}
+
+// Remove は DELETE /api/projects/{id}/members/{uid} を処理する（owner のみ。メンバー本人は自分で抜けられる）
+func (h *ProjectMemberHandler) Remove(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	userID, project, ok := authorizeProject(w, r, h.projectSvc, model.ProjectRoleEditor, false)
+	if !ok {
+		return
+	}
+
+	memberID := r.PathValue("uid")
+	if memberID != userID && !projectAccessAllowed(r.Context(), project, model.ProjectRoleOwner, false) {
+		writeReceiptJSONError(w, http.StatusForbidden, "forbidden")
+		return
+	}
+	if err := h.svc.Remove(r.Context(), project, memberID); err != nil {
+		writeProjectMemberError(w, err, "remove_failed", "project_id", project.ID)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+}
+
+// MyInvitations は GET /api/me/project-invitations を処理する（自分宛ての保留中の招待）
+func (h *ProjectMemberHandler) MyInvitations(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		writeReceiptJSONError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	invitations, err := h.svc.ListMyInvitations(r.Context(), userID)
+	if err != nil {
+		slog.Error("my project invitations failed", "error", err, "user_id", userID)
+		writeReceiptJSONError(w, http.StatusInternalServerError, "list_failed")
+		return
+	}
+	if invitations == nil {
+		invitations = []*model.ProjectInvitation{}
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"invitations": invitations})
+}
+
+// Accept は POST /api/me/project-invitations/{iid}/accept を処理する
+func (h *ProjectMemberHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	h.respond(w, r, h.svc.Accept, "accept_failed")
+}
+
+// Decline は POST /api/me/project-invitations/{iid}/decline を処理する
+func (h *ProjectMemberHandler) Decline(w http.ResponseWriter, r *http.Request) {
+	h.respond(w, r, h.svc.Decline, "decline_failed")
+}
+
+func (h *ProjectMemberHandler) respond(w http.ResponseWriter, r *http.Request, fn func(ctx context.Context, invitationID, userID string) error, fallback string) {
+	w.Header().Set("Content-Type", "application/json")
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		writeReceiptJSONError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if err := fn(r.Context(), r.PathValue("iid"), userID); err != nil {
+		writeProjectMemberError(w, err, fallback, "invitation_id", r.PathValue("iid"))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+}
+
+// writeProjectMemberError はサービスのエラーをレスポンスに変換する。fallback は想定外のエラーのコード
+func writeProjectMemberError(w http.ResponseWriter, err error, fallback string, logArgs ...any) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		writeReceiptJSONError(w, http.StatusNotFound, "not_found")
+	case errors.Is(err, service.ErrInvalidProjectInvitation):
+		writeReceiptJSONError(w, http.StatusBadRequest, "invalid_invitation")
+	case errors.Is(err, service.ErrAlreadyProjectMember):
+		writeReceiptJSONError(w, http.StatusConflict, "already_member")
+	case errors.Is(err, repository.ErrDuplicate):
+		writeReceiptJSONError(w, http.StatusConflict, "invitation_exists")
+	case errors.Is(err, service.ErrProjectCreator):
+		writeReceiptJSONError(w, http.StatusConflict, "project_creator")
+	default:
+		slog.Error("project member request failed", append([]any{"error", err}, logArgs...)...)
+		writeReceiptJSONError(w, http.StatusInternalServerError, fallback)
+	}
+}
@@ -0,0 +1,260 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+	"github.com/givers/backend/internal/service"
+	"github.com/givers/backend/pkg/auth"
+)
+
+// ---------------------------------------------------------------------------
+// Mock ProjectMemberService
+// ---------------------------------------------------------------------------
+
+type mockProjectMemberService struct {
+	inviteFunc func(ctx context.Context, project *model.Project, inviterID string, in service.ProjectInvitationInput) (*model.ProjectInvitation, error)
+	acceptFunc func(ctx context.Context, invitationID, userID string) error
+	removed    string
+}
+
+func (m *mockProjectMemberService) ListMembers(_ context.Context, projectID string) ([]*model.ProjectMember, error) {
+	return []*model.ProjectMember{{ProjectID: projectID, UserID: "user-1", UserName: "Alice", Role: model.ProjectRoleOwner}}, nil
+}
+func (m *mockProjectMemberService) ListInvitations(_ context.Context, projectID string) ([]*model.ProjectInvitation, error) {
+	return []*model.ProjectInvitation{{ID: "inv-1", ProjectID: projectID, Email: "bob@example.com", Role: model.ProjectRoleEditor}}, nil
+}
+func (m *mockProjectMemberService) Invite(ctx context.Context, project *model.Project, inviterID string, in service.ProjectInvitationInput) (*model.ProjectInvitation, error) {
+	if m.inviteFunc != nil {
+		return m.inviteFunc(ctx, project, inviterID, in)
+	}
+	return &model.ProjectInvitation{ID: "inv-1", ProjectID: project.ID, Email: in.Email, Role: in.Role, InvitedBy: inviterID}, nil
+}
+func (m *mockProjectMemberService) RevokeInvitation(_ context.Context, _, _ string) error { return nil }
+func (m *mockProjectMemberService) ChangeRole(_ context.Context, _ *model.Project, _, _ string) error {
+	return nil
+}
+func (m *mockProjectMemberService) Remove(_ context.Context, _ *model.Project, userID string) error {
+	m.removed = userID
+	return nil
+}
+func (m *mockProjectMemberService) ListMyInvitations(_ context.Context, _ string) ([]*model.ProjectInvitation, error) {
+	return nil, nil
+}
+func (m *mockProjectMemberService) Accept(ctx context.Context, invitationID, userID string) error {
+	if m.acceptFunc != nil {
+		return m.acceptFunc(ctx, invitationID, userID)
+	}
+	return nil
+}
+func (m *mockProjectMemberService) Decline(_ context.Context, _, _ string) error { return nil }
+
+// teamProjectService returns project p1 created by user-1 with an editor and a maintainer.
+func teamProjectService() *mockMessageProjectService {
+	return &mockMessageProjectService{
+		getByIDFunc: func(_ context.Context, id string) (*model.Project, error) {
+			if id != "p1" {
+				return nil, repository.ErrNotFound
+			}
+			return &model.Project{ID: "p1", OwnerID: "user-1", Members: []*model.ProjectMember{
+				{UserID: "user-1", Role: model.ProjectRoleOwner},
+				{UserID: "maintainer-1", Role: model.ProjectRoleMaintainer},
+				{UserID: "editor-1", Role: model.ProjectRoleEditor},
+			}}, nil
+		},
+	}
+}
+
+func memberRequest(method, url, body, userID string) *http.Request {
+	var r *http.Request
+	if body != "" {
+		r = httptest.NewRequest(method, url, strings.NewReader(body))
+	} else {
+		r = httptest.NewRequest(method, url, nil)
+	}
+	r.Header.Set("Content-Type", "application/json")
+	return r.WithContext(auth.WithUserID(r.Context(), userID))
+}
+
+// ---------------------------------------------------------------------------
+// Tests
+// ---------------------------------------------------------------------------
+
+func TestProjectMemberHandler_List_InvitationsOnlyForOwners(t *testing.T) {
+	h := NewProjectMemberHandler(&mockProjectMemberService{}, teamProjectService())
+
+	tests := []struct {
+		name            string
+		req             *http.Request
+		wantCode        int
+		wantInvitations bool
+	}{
+		{"owner", memberRequest(http.MethodGet, "/api/projects/p1/members", "", "user-1"), http.StatusOK, true},
+		{"editor", memberRequest(http.MethodGet, "/api/projects/p1/members", "", "editor-1"), http.StatusOK, false},
+		{"host", hostRequest(http.MethodGet, "/api/projects/p1/members", ""), http.StatusOK, true},
+		{"outsider", memberRequest(http.MethodGet, "/api/projects/p1/members", "", "user-2"), http.StatusForbidden, false},
+	}
+	for _, tt := range tests {
+		tt.req.SetPathValue("id", "p1")
+		rec := httptest.NewRecorder()
+		h.List(rec, tt.req)
+
+		if rec.Code != tt.wantCode {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.wantCode, rec.Code)
+			continue
+		}
+		if got := strings.Contains(rec.Body.String(), `"invitations"`); got != tt.wantInvitations {
+			t.Errorf("%s: invitations in body = %v: %s", tt.name, got, rec.Body.String())
+		}
+	}
+}
+
+func TestProjectMemberHandler_Invite(t *testing.T) {
+	var got service.ProjectInvitationInput
+	svc := &mockProjectMemberService{
+		inviteFunc: func(_ context.Context, project *model.Project, inviterID string, in service.ProjectInvitationInput) (*model.ProjectInvitation, error) {
+			got = in
+			return &model.ProjectInvitation{ID: "inv-1", ProjectID: project.ID, Email: in.Email, Role: in.Role, InvitedBy: inviterID}, nil
+		},
+	}
+	h := NewProjectMemberHandler(svc, teamProjectService())
+
+	req := memberRequest(http.MethodPost, "/api/projects/p1/invitations", `{"email":"bob@example.com","role":"maintainer"}`, "user-1")
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.Invite(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got.Email != "bob@example.com" || got.Role != model.ProjectRoleMaintainer {
+		t.Errorf("unexpected input: %+v", got)
+	}
+
+	// maintainers cannot manage members
+	req = memberRequest(http.MethodPost, "/api/projects/p1/invitations", `{"email":"bob@example.com","role":"editor"}`, "maintainer-1")
+	req.SetPathValue("id", "p1")
+	rec = httptest.NewRecorder()
+	h.Invite(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("maintainer: expected 403, got %d", rec.Code)
+	}
+}
+
+func TestProjectMemberHandler_Invite_Errors(t *testing.T) {
+	tests := []struct {
+		err      error
+		wantCode int
+		wantBody string
+	}{
+		{service.ErrInvalidProjectInvitation, http.StatusBadRequest, "invalid_invitation"},
+		{service.ErrAlreadyProjectMember, http.StatusConflict, "already_member"},
+		{repository.ErrDuplicate, http.StatusConflict, "invitation_exists"},
+	}
+	for _, tt := range tests {
+		svc := &mockProjectMemberService{
+			inviteFunc: func(_ context.Context, _ *model.Project, _ string, _ service.ProjectInvitationInput) (*model.ProjectInvitation, error) {
+				return nil, tt.err
+			},
+		}
+		h := NewProjectMemberHandler(svc, teamProjectService())
+		req := memberRequest(http.MethodPost, "/api/projects/p1/invitations", `{"user_id":"user-2","role":"editor"}`, "user-1")
+		req.SetPathValue("id", "p1")
+		rec := httptest.NewRecorder()
+		h.Invite(rec, req)
+
+		if rec.Code != tt.wantCode || !strings.Contains(rec.Body.String(), tt.wantBody) {
+			t.Errorf("%v: got %d %s", tt.err, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestProjectMemberHandler_Remove(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   string
+		memberID string
+		wantCode int
+	}{
+		{"owner removes a member", "user-1", "editor-1", http.StatusOK},
+		{"member leaves", "editor-1", "editor-1", http.StatusOK},
+		{"maintainer cannot remove others", "maintainer-1", "editor-1", http.StatusForbidden},
+		{"outsider", "user-2", "user-2", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		svc := &mockProjectMemberService{}
+		h := NewProjectMemberHandler(svc, teamProjectService())
+		req := memberRequest(http.MethodDelete, "/api/projects/p1/members/"+tt.memberID, "", tt.userID)
+		req.SetPathValue("id", "p1")
+		req.SetPathValue("uid", tt.memberID)
+		rec := httptest.NewRecorder()
+		h.Remove(rec, req)
+
+		if rec.Code != tt.wantCode {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.wantCode, rec.Code)
+		}
+		if tt.wantCode == http.StatusOK && svc.removed != tt.memberID {
+			t.Errorf("%s: expected %s to be removed, got %q", tt.name, tt.memberID, svc.removed)
+		}
+	}
+}
+
+func TestProjectMemberHandler_Accept(t *testing.T) {
+	var gotInvitation, gotUser string
+	svc := &mockProjectMemberService{
+		acceptFunc: func(_ context.Context, invitationID, userID string) error {
+			gotInvitation, gotUser = invitationID, userID
+			if invitationID != "inv-1" {
+				return repository.ErrNotFound
+			}
+			return nil
+		},
+	}
+	h := NewProjectMemberHandler(svc, teamProjectService())
+
+	req := memberRequest(http.MethodPost, "/api/me/project-invitations/inv-1/accept", "", "user-2")
+	req.SetPathValue("iid", "inv-1")
+	rec := httptest.NewRecorder()
+	h.Accept(rec, req)
+	if rec.Code != http.StatusOK || gotInvitation != "inv-1" || gotUser != "user-2" {
+		t.Errorf("expected 200 for inv-1 by user-2, got %d (%s, %s)", rec.Code, gotInvitation, gotUser)
+	}
+
+	req = memberRequest(http.MethodPost, "/api/me/project-invitations/inv-9/accept", "", "user-2")
+	req.SetPathValue("iid", "inv-9")
+	rec = httptest.NewRecorder()
+	h.Accept(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestProjectHandler_Update_MemberRoles(t *testing.T) {
+	tests := []struct {
+		userID   string
+		wantCode int
+	}{
+		{"maintainer-1", http.StatusOK},
+		{"editor-1", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		projectSvc := &mockProjectService{
+			getByIDFunc: teamProjectService().getByIDFunc,
+			updateFunc:  func(_ context.Context, _ *model.Project) error { return nil },
+		}
+		h := NewProjectHandler(projectSvc, nil)
+		req := memberRequest(http.MethodPut, "/api/projects/p1", `{"name":"renamed"}`, tt.userID)
+		req.SetPathValue("id", "p1")
+		rec := httptest.NewRecorder()
+		h.Update(rec, req)
+
+		if rec.Code != tt.wantCode {
+			t.Errorf("%s: expected %d, got %d: %s", tt.userID, tt.wantCode, rec.Code, rec.Body.String())
+		}
+	}
+}
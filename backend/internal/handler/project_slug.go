@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/givers/backend/internal/repository"
+)
+
+// ProjectSlugResolver looks up a project by its current or a previous slug.
+type ProjectSlugResolver interface {
+	ResolveSlug(ctx context.Context, slug string) (projectID, currentSlug string, err error)
+}
+
+const projectsPathPrefix = "/api/projects/"
+
+var reProjectUUID = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// projectRouteLiterals are fixed /api/projects/{name} routes that must not be resolved as slugs.
+var projectRouteLiterals = map[string]bool{"search": true, "facets": true}
+
+// ResolveProjectSlugs lets every /api/projects/{id}/... route take a project slug in
+// place of the UUID. A current slug is rewritten to the project ID before routing; a
+// previous slug (kept after a rename) answers GET and HEAD with a 301 to the current
+// URL and is rewritten like a current slug for other methods. Unknown segments are
+// passed through unchanged so the handlers keep answering 404 as before.
+func ResolveProjectSlugs(resolver ProjectSlugResolver, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest, ok := strings.CutPrefix(r.URL.Path, projectsPathPrefix)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		segment, tail, _ := strings.Cut(rest, "/")
+		if segment == "" || reProjectUUID.MatchString(segment) || projectRouteLiterals[segment] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		projectID, currentSlug, err := resolver.ResolveSlug(r.Context(), strings.ToLower(segment))
+		if err != nil {
+			if !errors.Is(err, repository.ErrNotFound) {
+				slog.Warn("project slug lookup failed", "slug", segment, "error", err)
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+		if tail != "" || strings.HasSuffix(rest, "/") {
+			tail = "/" + tail
+		}
+
+		if currentSlug != strings.ToLower(segment) && (r.Method == http.MethodGet || r.Method == http.MethodHead) {
+			target := currentSlug
+			if target == "" {
+				target = projectID
+			}
+			u := *r.URL
+			u.Path = projectsPathPrefix + target + tail
+			u.RawPath = ""
+			http.Redirect(w, r, u.RequestURI(), http.StatusMovedPermanently)
+			return
+		}
+
+		r2 := r.Clone(r.Context())
+		r2.URL.Path = projectsPathPrefix + projectID + tail
+		r2.URL.RawPath = ""
+		next.ServeHTTP(w, r2)
+	})
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/givers/backend/internal/repository"
+)
+
+type mockSlugResolver struct {
+	projects map[string][2]string // slug → {project ID, current slug}
+	calls    int
+}
+
+func (m *mockSlugResolver) ResolveSlug(_ context.Context, slug string) (string, string, error) {
+	m.calls++
+	if p, ok := m.projects[slug]; ok {
+		return p[0], p[1], nil
+	}
+	return "", "", repository.ErrNotFound
+}
+
+const slugTestProjectID = "0b6f3c52-8f0e-4a4e-9d8a-3f1c2b7e5a10"
+
+func serveWithSlugs(resolver *mockSlugResolver, method, target string) (*httptest.ResponseRecorder, string) {
+	var gotPath string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+	})
+	rec := httptest.NewRecorder()
+	ResolveProjectSlugs(resolver, next).ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	return rec, gotPath
+}
+
+func newMockSlugResolver() *mockSlugResolver {
+	return &mockSlugResolver{projects: map[string][2]string{
+		"givers":     {slugTestProjectID, "givers"},
+		"old-givers": {slugTestProjectID, "givers"},
+	}}
+}
+
+func TestResolveProjectSlugs_RewritesCurrentSlug(t *testing.T) {
+	for _, tt := range []struct{ target, want string }{
+		{"/api/projects/givers", "/api/projects/" + slugTestProjectID},
+		{"/api/projects/Givers/updates", "/api/projects/" + slugTestProjectID + "/updates"},
+		{"/api/projects/givers/members/u1", "/api/projects/" + slugTestProjectID + "/members/u1"},
+	} {
+		rec, got := serveWithSlugs(newMockSlugResolver(), http.MethodGet, tt.target)
+		if rec.Code != http.StatusOK || got != tt.want {
+			t.Errorf("%s: got %d %q, want %q", tt.target, rec.Code, got, tt.want)
+		}
+	}
+}
+
+func TestResolveProjectSlugs_RedirectsOldSlug(t *testing.T) {
+	rec, got := serveWithSlugs(newMockSlugResolver(), http.MethodGet, "/api/projects/old-givers/chart?period=12m")
+	if rec.Code != http.StatusMovedPermanently {
+		t.Fatalf("expected 301, got %d", rec.Code)
+	}
+	if loc := rec.Header().Get("Location"); loc != "/api/projects/givers/chart?period=12m" {
+		t.Errorf("unexpected Location: %q", loc)
+	}
+	if got != "" {
+		t.Error("expected the next handler not to be called")
+	}
+}
+
+func TestResolveProjectSlugs_RedirectsToIDWhenSlugRemoved(t *testing.T) {
+	resolver := &mockSlugResolver{projects: map[string][2]string{"old-givers": {slugTestProjectID, ""}}}
+	rec, _ := serveWithSlugs(resolver, http.MethodGet, "/api/projects/old-givers")
+	if rec.Code != http.StatusMovedPermanently || rec.Header().Get("Location") != "/api/projects/"+slugTestProjectID {
+		t.Errorf("unexpected response: %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+}
+
+func TestResolveProjectSlugs_OldSlugWriteIsRewritten(t *testing.T) {
+	rec, got := serveWithSlugs(newMockSlugResolver(), http.MethodPut, "/api/projects/old-givers")
+	if rec.Code != http.StatusOK || got != "/api/projects/"+slugTestProjectID {
+		t.Errorf("got %d %q", rec.Code, got)
+	}
+}
+
+func TestResolveProjectSlugs_PassesThrough(t *testing.T) {
+	for _, target := range []string{
+		"/api/projects/" + slugTestProjectID + "/updates",
+		"/api/projects/search?q=go",
+		"/api/projects/facets",
+		"/api/projects/unknown",
+		"/api/me/projects",
+	} {
+		resolver := newMockSlugResolver()
+		rec, got := serveWithSlugs(resolver, http.MethodGet, target)
+		want := httptest.NewRequest(http.MethodGet, target, nil).URL.Path
+		if rec.Code != http.StatusOK || got != want {
+			t.Errorf("%s: got %d %q, want %q", target, rec.Code, got, want)
+		}
+		if target != "/api/projects/unknown" && resolver.calls != 0 {
+			t.Errorf("%s: expected no slug lookup", target)
+		}
+	}
+}
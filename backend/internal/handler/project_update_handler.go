@@ -22,7 +22,7 @@ func NewProjectUpdateHandler(svc service.ProjectUpdateService, projectSvc servic
 }
 
 // List は GET /api/projects/{id}/updates を処理する（認証不要・公開）
-// オーナーがアクセスした場合は非表示更新も含む。
+// プロジェクトのメンバーがアクセスした場合は非表示更新も含む。
 func (h *ProjectUpdateHandler) List(w http.ResponseWriter, r *http.Request) {
 	projectID := r.PathValue("id")
 
@@ -33,11 +33,8 @@ func (h *ProjectUpdateHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// オーナーは非表示更新も閲覧できる
-	includeHidden := false
-	if userID, ok := auth.UserIDFromContext(r.Context()); ok && userID == project.OwnerID {
-		includeHidden = true
-	}
+	// メンバー（エディター以上）は非表示更新も閲覧できる
+	includeHidden := projectAccessAllowed(r.Context(), project, model.ProjectRoleEditor, false)
 
 	updates, err := h.svc.ListByProjectID(r.Context(), projectID, includeHidden)
 	if err != nil {
@@ -56,7 +53,7 @@ func (h *ProjectUpdateHandler) List(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(map[string][]*model.ProjectUpdate{"updates": updates})
 }
 
-// Create は POST /api/projects/{id}/updates を処理する（認証必須・プロジェクトオーナーのみ）
+// Create は POST /api/projects/{id}/updates を処理する（認証必須・エディター以上のメンバーのみ）
 func (h *ProjectUpdateHandler) Create(w http.ResponseWriter, r *http.Request) {
 	userID, ok := auth.UserIDFromContext(r.Context())
 	if !ok {
@@ -73,7 +70,7 @@ func (h *ProjectUpdateHandler) Create(w http.ResponseWriter, r *http.Request) {
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "not_found"})
 		return
 	}
-	if project.OwnerID != userID {
+	if !projectAccessAllowed(r.Context(), project, model.ProjectRoleEditor, false) {
 		w.WriteHeader(http.StatusForbidden)
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "forbidden"})
 		return
@@ -176,9 +173,9 @@ func (h *ProjectUpdateHandler) UpdateUpdate(w http.ResponseWriter, r *http.Reque
 }
 
 // Delete は DELETE /api/projects/{id}/updates/{uid} を処理する
-// （認証必須・プロジェクトオーナーまたはホストのみ。ソフトデリート）
+// （認証必須・メンテナー以上のメンバーまたはホストのみ。ソフトデリート）
 func (h *ProjectUpdateHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	userID, ok := auth.UserIDFromContext(r.Context())
+	_, ok := auth.UserIDFromContext(r.Context())
 	if !ok {
 		w.WriteHeader(http.StatusUnauthorized)
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "unauthorized"})
@@ -208,10 +205,8 @@ func (h *ProjectUpdateHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// プロジェクトオーナーまたはホストのみ削除可能
-	isOwner := project.OwnerID == userID
-	isHost := auth.IsHostFromContext(r.Context())
-	if !isOwner && !isHost {
+	// メンテナー以上またはホストのみ削除可能
+	if !projectAccessAllowed(r.Context(), project, model.ProjectRoleMaintainer, true) {
 		w.WriteHeader(http.StatusForbidden)
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "forbidden"})
 		return
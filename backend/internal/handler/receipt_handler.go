@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+	"github.com/givers/backend/internal/service"
+	"github.com/givers/backend/pkg/auth"
+)
+
+// ReceiptConfig holds configuration for the ReceiptHandler.
+type ReceiptConfig struct {
+	// LegalDocsDir is the directory of the legal Markdown files (LEGAL_DOCS_DIR).
+	// The donation section of disclaimer.md is printed on every receipt.
+	LegalDocsDir string
+}
+
+// ReceiptHandler serves donation receipts and annual giving statements to donors
+// as HTML (default) or PDF (?format=pdf).
+type ReceiptHandler struct {
+	svc service.ReceiptService
+	cfg ReceiptConfig
+}
+
+// NewReceiptHandler creates a ReceiptHandler.
+func NewReceiptHandler(svc service.ReceiptService, cfg ReceiptConfig) *ReceiptHandler {
+	return &ReceiptHandler{svc: svc, cfg: cfg}
+}
+
+// DonationReceipt handles GET /api/me/donations/{id}/receipt (auth required).
+func (h *ReceiptHandler) DonationReceipt(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		writeReceiptJSONError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	format, ok := receiptFormat(r)
+	if !ok {
+		writeReceiptJSONError(w, http.StatusBadRequest, "invalid_format")
+		return
+	}
+
+	id := r.PathValue("id")
+	rc, err := h.svc.DonationReceipt(r.Context(), userID, id)
+	if err != nil {
+		writeReceiptError(w, err, "donation_id", id)
+		return
+	}
+	h.write(w, rc, format, "givers-receipt-"+rc.Label())
+}
+
+// Statement handles GET /api/me/statements/{year} (auth required).
+func (h *ReceiptHandler) Statement(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		writeReceiptJSONError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	format, ok := receiptFormat(r)
+	if !ok {
+		writeReceiptJSONError(w, http.StatusBadRequest, "invalid_format")
+		return
+	}
+	year, err := strconv.Atoi(r.PathValue("year"))
+	if err != nil {
+		writeReceiptJSONError(w, http.StatusBadRequest, "invalid_year")
+		return
+	}
+
+	rc, err := h.svc.AnnualStatement(r.Context(), userID, year)
+	if err != nil {
+		writeReceiptError(w, err, "user_id", userID, "year", year)
+		return
+	}
+	h.write(w, rc, format, fmt.Sprintf("givers-statement-%d", year))
+}
+
+// receiptFormat は ?format=html|pdf（省略時は Accept: application/pdf なら pdf、それ以外は html）を返す
+func receiptFormat(r *http.Request) (string, bool) {
+	switch f := r.URL.Query().Get("format"); f {
+	case "html", "pdf":
+		return f, true
+	case "":
+		if strings.Contains(r.Header.Get("Accept"), "application/pdf") {
+			return "pdf", true
+		}
+		return "html", true
+	}
+	return "", false
+}
+
+func (h *ReceiptHandler) write(w http.ResponseWriter, rc *model.Receipt, format, filename string) {
+	view := newReceiptView(rc, loadReceiptDisclaimer(h.cfg.LegalDocsDir))
+	// 寄付者の個人情報を含むため共有キャッシュに残さない
+	w.Header().Set("Cache-Control", "private, no-store")
+
+	if format == "pdf" {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`.pdf"`)
+		if _, err := renderReceiptPDF(view).WriteTo(w); err != nil {
+			slog.Error("receipt pdf write failed", "error", err, "receipt_number", rc.Number)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := receiptTemplate.Execute(w, view); err != nil {
+		slog.Error("receipt html render failed", "error", err, "receipt_number", rc.Number)
+	}
+}
+
+func writeReceiptJSONError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
+
+// writeReceiptError maps service errors to responses.
+func writeReceiptError(w http.ResponseWriter, err error, logArgs ...any) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		writeReceiptJSONError(w, http.StatusNotFound, "not_found")
+	case errors.Is(err, service.ErrForbidden):
+		writeReceiptJSONError(w, http.StatusForbidden, "forbidden")
+	case errors.Is(err, service.ErrDonationVoided):
+		writeReceiptJSONError(w, http.StatusConflict, "donation_voided")
+	case errors.Is(err, service.ErrNoReceiptPayments):
+		writeReceiptJSONError(w, http.StatusNotFound, "no_payments")
+	case errors.Is(err, service.ErrInvalidReceiptYear):
+		writeReceiptJSONError(w, http.StatusBadRequest, "invalid_year")
+	default:
+		slog.Error("receipt request failed", append([]any{"error", err}, logArgs...)...)
+		writeReceiptJSONError(w, http.StatusInternalServerError, "receipt_failed")
+	}
+}
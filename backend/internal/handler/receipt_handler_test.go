@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/service"
+)
+
+// ---------------------------------------------------------------------------
+// Mock ReceiptService
+// ---------------------------------------------------------------------------
+
+type mockReceiptService struct {
+	donationFunc  func(ctx context.Context, userID, donationID string) (*model.Receipt, error)
+	statementFunc func(ctx context.Context, userID string, year int) (*model.Receipt, error)
+}
+
+func (m *mockReceiptService) DonationReceipt(ctx context.Context, userID, donationID string) (*model.Receipt, error) {
+	if m.donationFunc != nil {
+		return m.donationFunc(ctx, userID, donationID)
+	}
+	return testReceipt(), nil
+}
+
+func (m *mockReceiptService) AnnualStatement(ctx context.Context, userID string, year int) (*model.Receipt, error) {
+	if m.statementFunc != nil {
+		return m.statementFunc(ctx, userID, year)
+	}
+	rc := testReceipt()
+	rc.Kind, rc.DonationID, rc.Year = model.ReceiptKindAnnual, "", year
+	return rc, nil
+}
+
+func testReceipt() *model.Receipt {
+	return &model.Receipt{
+		ReceiptNumber: model.ReceiptNumber{Number: 42, IssuedAt: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)},
+		Kind:          model.ReceiptKindDonation,
+		DonationID:    "d1",
+		DonorName:     "<寄付者>",
+		Lines: []*model.ReceiptLine{{
+			ProjectName: "OSS Project", OwnerName: "Owner Name", PaymentMethod: model.PaymentMethodStripe,
+			Amount: 12000, Currency: "jpy", PaidAt: time.Date(2026, 9, 30, 20, 0, 0, 0, time.UTC),
+		}},
+		Totals: []model.CurrencyAmount{{Currency: "jpy", Amount: 12000}},
+	}
+}
+
+// testLegalDir は寄付の効果の条項を含む disclaimer.md を置いたディレクトリを返す
+func testLegalDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	md := "# 免責事項\n\n## 第1条（情報の正確性）\n\n対象外の条項。\n\n## 第2条（寄付の効果）\n\n3. 寄付金控除の可否は税務署にご確認ください。\n\n## 第3条（損害の免責）\n\n対象外。\n"
+	if err := os.WriteFile(filepath.Join(dir, "disclaimer.md"), []byte(md), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// ---------------------------------------------------------------------------
+// Tests
+// ---------------------------------------------------------------------------
+
+func TestReceiptHandler_DonationReceipt_RequiresAuth(t *testing.T) {
+	h := NewReceiptHandler(&mockReceiptService{}, ReceiptConfig{})
+	req := httptest.NewRequest(http.MethodGet, "/api/me/donations/d1/receipt", nil)
+	req.SetPathValue("id", "d1")
+	rec := httptest.NewRecorder()
+	h.DonationReceipt(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestReceiptHandler_DonationReceipt_HTML(t *testing.T) {
+	var gotUser, gotID string
+	svc := &mockReceiptService{donationFunc: func(ctx context.Context, userID, donationID string) (*model.Receipt, error) {
+		gotUser, gotID = userID, donationID
+		return testReceipt(), nil
+	}}
+	h := NewReceiptHandler(svc, ReceiptConfig{LegalDocsDir: testLegalDir(t)})
+	req := userAuthRequest(http.MethodGet, "/api/me/donations/d1/receipt", "")
+	req.SetPathValue("id", "d1")
+	rec := httptest.NewRecorder()
+	h.DonationReceipt(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if gotUser != "user-1" || gotID != "d1" {
+		t.Errorf("called with %s/%s", gotUser, gotID)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("Content-Type = %s", ct)
+	}
+	if rec.Header().Get("Cache-Control") != "private, no-store" {
+		t.Errorf("Cache-Control = %s", rec.Header().Get("Cache-Control"))
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"00000042", "OSS Project", "Owner Name", "¥12,000", "2026-10-01", "&lt;寄付者&gt;", "寄付金控除の可否", "not a tax receipt"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q", want)
+		}
+	}
+	if strings.Contains(body, "対象外") {
+		t.Error("only the donation section of the disclaimer should be included")
+	}
+}
+
+func TestReceiptHandler_DonationReceipt_PDF(t *testing.T) {
+	h := NewReceiptHandler(&mockReceiptService{}, ReceiptConfig{LegalDocsDir: testLegalDir(t)})
+	req := userAuthRequest(http.MethodGet, "/api/me/donations/d1/receipt?format=pdf", "")
+	req.SetPathValue("id", "d1")
+	rec := httptest.NewRecorder()
+	h.DonationReceipt(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if rec.Header().Get("Content-Type") != "application/pdf" {
+		t.Errorf("Content-Type = %s", rec.Header().Get("Content-Type"))
+	}
+	if cd := rec.Header().Get("Content-Disposition"); !strings.Contains(cd, "givers-receipt-00000042.pdf") {
+		t.Errorf("Content-Disposition = %s", cd)
+	}
+	if !bytes.HasPrefix(rec.Body.Bytes(), []byte("%PDF-")) {
+		t.Error("body is not a PDF")
+	}
+}
+
+func TestReceiptHandler_DonationReceipt_Errors(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{service.ErrForbidden, http.StatusForbidden, "forbidden"},
+		{service.ErrDonationVoided, http.StatusConflict, "donation_voided"},
+		{service.ErrNoReceiptPayments, http.StatusNotFound, "no_payments"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			svc := &mockReceiptService{donationFunc: func(ctx context.Context, userID, donationID string) (*model.Receipt, error) {
+				return nil, tt.err
+			}}
+			h := NewReceiptHandler(svc, ReceiptConfig{})
+			req := userAuthRequest(http.MethodGet, "/api/me/donations/d1/receipt", "")
+			req.SetPathValue("id", "d1")
+			rec := httptest.NewRecorder()
+			h.DonationReceipt(rec, req)
+			if rec.Code != tt.status {
+				t.Fatalf("expected %d, got %d", tt.status, rec.Code)
+			}
+			var resp map[string]string
+			_ = json.NewDecoder(rec.Body).Decode(&resp)
+			if resp["error"] != tt.code {
+				t.Errorf("error = %q, want %q", resp["error"], tt.code)
+			}
+		})
+	}
+}
+
+func TestReceiptHandler_DonationReceipt_InvalidFormat(t *testing.T) {
+	h := NewReceiptHandler(&mockReceiptService{}, ReceiptConfig{})
+	req := userAuthRequest(http.MethodGet, "/api/me/donations/d1/receipt?format=docx", "")
+	req.SetPathValue("id", "d1")
+	rec := httptest.NewRecorder()
+	h.DonationReceipt(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestReceiptHandler_Statement(t *testing.T) {
+	var gotYear int
+	svc := &mockReceiptService{statementFunc: func(ctx context.Context, userID string, year int) (*model.Receipt, error) {
+		gotYear = year
+		rc := testReceipt()
+		rc.Kind, rc.Year = model.ReceiptKindAnnual, year
+		return rc, nil
+	}}
+	h := NewReceiptHandler(svc, ReceiptConfig{})
+	req := userAuthRequest(http.MethodGet, "/api/me/statements/2025", "")
+	req.Header.Set("Accept", "application/pdf")
+	req.SetPathValue("year", "2025")
+	rec := httptest.NewRecorder()
+	h.Statement(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if gotYear != 2025 {
+		t.Errorf("year = %d", gotYear)
+	}
+	if rec.Header().Get("Content-Type") != "application/pdf" {
+		t.Errorf("Accept: application/pdf should return a PDF, got %s", rec.Header().Get("Content-Type"))
+	}
+	if cd := rec.Header().Get("Content-Disposition"); !strings.Contains(cd, "givers-statement-2025.pdf") {
+		t.Errorf("Content-Disposition = %s", cd)
+	}
+}
+
+func TestReceiptHandler_Statement_InvalidYear(t *testing.T) {
+	h := NewReceiptHandler(&mockReceiptService{}, ReceiptConfig{})
+	req := userAuthRequest(http.MethodGet, "/api/me/statements/abc", "")
+	req.SetPathValue("year", "abc")
+	rec := httptest.NewRecorder()
+	h.Statement(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestFormatReceiptAmount(t *testing.T) {
+	tests := []struct {
+		amount   int
+		currency string
+		want     string
+	}{
+		{1234567, "jpy", "¥1,234,567"},
+		{500, "jpy", "¥500"},
+		{123456, "usd", "1,234.56 USD"},
+		{5, "eur", "0.05 EUR"},
+	}
+	for _, tt := range tests {
+		if got := formatReceiptAmount(tt.amount, tt.currency); got != tt.want {
+			t.Errorf("formatReceiptAmount(%d, %s) = %s, want %s", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
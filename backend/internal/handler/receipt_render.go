@@ -0,0 +1,317 @@
+package handler
+
+import (
+	"fmt"
+	"html/template"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/service"
+	"github.com/givers/backend/pkg/pdf"
+)
+
+// receiptNotes は全ての寄付記録に印字する注記。
+// 運営者は寄付の受領者ではなく領収書を発行しない（docs/legal/operator-developer-declarations.md）。
+var receiptNotes = []string{
+	"本書は GIVErS で行われた寄付の記録です。寄付金の受取人は各プロジェクトのオーナーであり、本サービスの運営者は寄付金を受領していません。",
+	"税務上の領収書が必要な場合は、各プロジェクトのオーナーにお問い合わせください。",
+	"金額は返金・チャージバック分を差し引いた額です。日付は日本時間です。",
+	"This is a record of donations made through GIVErS, not a tax receipt. Donations are received by each project owner; please contact them for an official receipt.",
+}
+
+var receiptMethodLabels = map[string]string{
+	model.PaymentMethodStripe:       "カード決済",
+	model.PaymentMethodBankTransfer: "銀行振込",
+	model.PaymentMethodCash:         "現金",
+	model.PaymentMethodOther:        "その他",
+}
+
+// receiptView は HTML と PDF で共通の表示用データ
+type receiptView struct {
+	Title      string
+	Number     string
+	IssuedAt   string
+	DonorName  string
+	Rows       []receiptRow
+	Totals     []string
+	Notes      []string
+	Disclaimer []string
+}
+
+type receiptRow struct {
+	Date    string
+	Project string
+	Owner   string
+	Method  string
+	Amount  string
+}
+
+func newReceiptView(rc *model.Receipt, disclaimer []string) *receiptView {
+	v := &receiptView{
+		Title:      "寄付記録 / Donation Receipt",
+		Number:     rc.Label(),
+		IssuedAt:   rc.IssuedAt.In(service.ReceiptTimeZone).Format("2006-01-02"),
+		DonorName:  rc.DonorName,
+		Notes:      receiptNotes,
+		Disclaimer: disclaimer,
+	}
+	if rc.Kind == model.ReceiptKindAnnual {
+		v.Title = fmt.Sprintf("%d年 年間寄付記録 / Annual Giving Statement %d", rc.Year, rc.Year)
+	}
+	for _, l := range rc.Lines {
+		method := receiptMethodLabels[l.PaymentMethod]
+		if method == "" {
+			method = l.PaymentMethod
+		}
+		owner := l.OwnerName
+		if owner == "" {
+			owner = "-"
+		}
+		v.Rows = append(v.Rows, receiptRow{
+			Date:    l.PaidAt.In(service.ReceiptTimeZone).Format("2006-01-02"),
+			Project: l.ProjectName,
+			Owner:   owner,
+			Method:  method,
+			Amount:  formatReceiptAmount(l.Amount, l.Currency),
+		})
+	}
+	for _, t := range rc.Totals {
+		v.Totals = append(v.Totals, formatReceiptAmount(t.Amount, t.Currency))
+	}
+	return v
+}
+
+// formatReceiptAmount は最小通貨単位の金額を表示用にする（円は ¥1,000、それ以外は 12.34 USD）
+func formatReceiptAmount(amount int, currency string) string {
+	switch strings.ToLower(currency) {
+	case "jpy":
+		return "¥" + groupDigits(amount)
+	case "krw":
+		return groupDigits(amount) + " KRW"
+	}
+	return fmt.Sprintf("%s.%02d %s", groupDigits(amount/100), amount%100, strings.ToUpper(currency))
+}
+
+func groupDigits(n int) string {
+	s := strconv.Itoa(n)
+	if n < 0 {
+		return "-" + groupDigits(-n)
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+// loadReceiptDisclaimer は disclaimer.md から寄付に関する条項（第2条「寄付の効果」）を抜き出す。
+// 条項が見つからなければ見出し以外の全文を返す。読めなければ nil（注記だけで発行する）。
+func loadReceiptDisclaimer(dir string) []string {
+	content, err := os.ReadFile(filepath.Join(dir, "disclaimer.md"))
+	if err != nil {
+		slog.Warn("receipt disclaimer not available", "error", err, "dir", dir)
+		return nil
+	}
+
+	var section, all []string
+	inSection := false
+	for _, line := range strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(strings.ReplaceAll(line, "**", ""))
+		if strings.HasPrefix(line, "#") {
+			inSection = strings.HasPrefix(line, "## ") && strings.Contains(line, "寄付の効果")
+			if inSection {
+				section = append(section, strings.TrimSpace(strings.TrimPrefix(line, "## ")))
+			}
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		all = append(all, line)
+		if inSection {
+			section = append(section, line)
+		}
+	}
+	if len(section) > 0 {
+		return section
+	}
+	return all
+}
+
+var receiptTemplate = template.Must(template.New("receipt").Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} {{.Number}}</title>
+<style>
+body { font-family: sans-serif; color: #222; max-width: 800px; margin: 2rem auto; padding: 0 1rem; }
+h1 { font-size: 1.4rem; border-bottom: 2px solid #222; padding-bottom: .4rem; }
+dl { display: grid; grid-template-columns: max-content 1fr; gap: .2rem 1rem; }
+dt { color: #666; }
+table { width: 100%; border-collapse: collapse; margin: 1.5rem 0; }
+th, td { border-bottom: 1px solid #ccc; padding: .4rem; text-align: left; }
+td.amount, th.amount { text-align: right; white-space: nowrap; }
+tfoot td { font-weight: bold; border-bottom: none; }
+.notes { font-size: .85rem; color: #444; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<dl>
+<dt>記録番号 / Receipt No.</dt><dd>{{.Number}}</dd>
+<dt>発行日 / Issued</dt><dd>{{.IssuedAt}}</dd>
+<dt>寄付者 / Donor</dt><dd>{{.DonorName}}</dd>
+</dl>
+<table>
+<thead><tr><th>日付 / Date</th><th>プロジェクト / Project</th><th>受取人（オーナー） / Recipient</th><th>方法 / Method</th><th class="amount">金額 / Amount</th></tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr><td>{{.Date}}</td><td>{{.Project}}</td><td>{{.Owner}}</td><td>{{.Method}}</td><td class="amount">{{.Amount}}</td></tr>
+{{- end}}
+</tbody>
+<tfoot>
+{{- range .Totals}}
+<tr><td colspan="4">合計 / Total</td><td class="amount">{{.}}</td></tr>
+{{- end}}
+</tfoot>
+</table>
+<div class="notes">
+{{- range .Notes}}
+<p>{{.}}</p>
+{{- end}}
+{{- if .Disclaimer}}
+<h2>免責事項（抜粋） / Disclaimer</h2>
+{{- range .Disclaimer}}
+<p>{{.}}</p>
+{{- end}}
+{{- end}}
+</div>
+</body>
+</html>
+`))
+
+// PDF のレイアウト（pt）
+const (
+	receiptMargin   = 50.0
+	receiptBodySize = 9.0
+	receiptLeading  = 14.0
+)
+
+// receiptPDFColumns は明細の金額以外の列の左端と幅（金額列は右余白に揃える）
+var receiptPDFColumns = [...]struct{ x, width float64 }{
+	{receiptMargin, 60}, // 日付
+	{115, 165},          // プロジェクト
+	{285, 130},          // 受取人
+	{420, 50},           // 方法
+}
+
+// receiptPDF は改ページしながら上から順に描画するカーソル
+type receiptPDF struct {
+	doc  *pdf.Document
+	page *pdf.Page
+	y    float64
+}
+
+// advance は行送りし、下余白に入ったら改ページする
+func (p *receiptPDF) advance(h float64) {
+	p.y -= h
+	if p.page == nil || p.y < receiptMargin {
+		p.page = p.doc.AddPage()
+		p.y = pdf.PageHeight - receiptMargin - h
+	}
+}
+
+func renderReceiptPDF(v *receiptView) *pdf.Document {
+	p := &receiptPDF{doc: pdf.New(v.Title + " " + v.Number)}
+	right := pdf.PageWidth - receiptMargin
+
+	p.advance(20)
+	p.page.Text(receiptMargin, p.y, 16, v.Title)
+	p.advance(8)
+	p.page.Line(receiptMargin, p.y, right, p.y, 1)
+
+	for _, kv := range [][2]string{
+		{"記録番号 / Receipt No.", v.Number},
+		{"発行日 / Issued", v.IssuedAt},
+		{"寄付者 / Donor", v.DonorName},
+	} {
+		p.advance(receiptLeading + 2)
+		p.page.Text(receiptMargin, p.y, receiptBodySize, kv[0])
+		p.page.Text(receiptMargin+130, p.y, receiptBodySize, kv[1])
+	}
+
+	p.advance(receiptLeading * 2)
+	headers := []string{"日付", "プロジェクト", "受取人（オーナー）", "方法", "金額"}
+	for i, h := range headers[:4] {
+		p.page.Text(receiptPDFColumns[i].x, p.y, receiptBodySize, h)
+	}
+	p.page.Text(right-pdf.TextWidth(headers[4], receiptBodySize), p.y, receiptBodySize, headers[4])
+	p.advance(5)
+	p.page.Line(receiptMargin, p.y, right, p.y, 0.5)
+
+	for _, row := range v.Rows {
+		p.advance(receiptLeading)
+		for i, cell := range []string{row.Date, row.Project, row.Owner, row.Method} {
+			c := receiptPDFColumns[i]
+			p.page.Text(c.x, p.y, receiptBodySize, fitText(cell, receiptBodySize, c.width-4))
+		}
+		p.page.Text(right-pdf.TextWidth(row.Amount, receiptBodySize), p.y, receiptBodySize, row.Amount)
+	}
+	p.advance(5)
+	p.page.Line(receiptMargin, p.y, right, p.y, 0.5)
+	for _, total := range v.Totals {
+		p.advance(receiptLeading + 2)
+		p.page.Text(receiptMargin, p.y, 11, "合計 / Total")
+		p.page.Text(right-pdf.TextWidth(total, 11), p.y, 11, total)
+	}
+
+	p.advance(receiptLeading)
+	paragraphs := v.Notes
+	if len(v.Disclaimer) > 0 {
+		paragraphs = append(append(append([]string{}, v.Notes...), "", "免責事項（抜粋） / Disclaimer"), v.Disclaimer...)
+	}
+	for _, para := range paragraphs {
+		if para == "" {
+			p.advance(receiptLeading / 2)
+			continue
+		}
+		for _, line := range wrapText(para, 8, right-receiptMargin) {
+			p.advance(12)
+			p.page.Text(receiptMargin, p.y, 8, line)
+		}
+	}
+	return p.doc
+}
+
+// fitText は幅に収まらない文字列を末尾 "…" で切り詰める
+func fitText(s string, size, width float64) string {
+	if pdf.TextWidth(s, size) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && pdf.TextWidth(string(runes)+"…", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// wrapText は文字単位で幅に収まるように折り返す（日本語を前提に単語境界は考慮しない）
+func wrapText(s string, size, width float64) []string {
+	var lines []string
+	var cur []rune
+	for _, r := range s {
+		if len(cur) > 0 && pdf.TextWidth(string(append(cur, r)), size) > width {
+			lines = append(lines, string(cur))
+			cur = cur[:0]
+		}
+		cur = append(cur, r)
+	}
+	if len(cur) > 0 {
+		lines = append(lines, string(cur))
+	}
+	return lines
+}
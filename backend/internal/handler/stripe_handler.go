@@ -4,20 +4,27 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"io"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
 	"github.com/givers/backend/internal/service"
 	"github.com/givers/backend/pkg/auth"
+	pkgstripe "github.com/givers/backend/pkg/stripe"
 )
 
 // StripeHandler は Stripe 関連の HTTP ハンドラ
 type StripeHandler struct {
 	svc         service.StripeService
 	frontendURL string
-	sv          auth.SessionValidator // optional: non-nil enables donor identification via session cookie
+	sv          auth.SessionValidator  // optional: non-nil enables donor identification via session cookie
+	projectSvc  service.ProjectService // optional: required by CheckoutStats (owner check)
 }
 
 // NewStripeHandler は StripeHandler を生成する
@@ -25,6 +32,11 @@ func NewStripeHandler(svc service.StripeService, frontendURL string, sv auth.Ses
 	return &StripeHandler{svc: svc, frontendURL: frontendURL, sv: sv}
 }
 
+// SetProjectService は CheckoutStats でメンテナー以上のメンバー・ホストを確認するための ProjectService を設定する
+func (h *StripeHandler) SetProjectService(projectSvc service.ProjectService) {
+	h.projectSvc = projectSvc
+}
+
 // OnboardingReturn handles GET /api/stripe/onboarding/return
 // Stripe Account Link オンボーディング完了後のリターン URL。
 // アカウントの要件を確認し、完了なら active にしてフロントへリダイレクト。
@@ -78,6 +90,9 @@ func (h *StripeHandler) Checkout(w http.ResponseWriter, r *http.Request) {
 		Amount      int    `json:"amount"`
 		Currency    string `json:"currency"`
 		IsRecurring bool   `json:"is_recurring"`
+		Interval    string `json:"interval"`   // "month"（省略時）, "quarter", "half_year", "year"
+		TierID      string `json:"tier_id"`    // 目安額の ID（指定時は amount / is_recurring / interval の代わりに使う）
+		CoverFees   bool   `json:"cover_fees"` // 寄付者が決済手数料を負担する（請求額を上乗せする）
 		Message     string `json:"message"`
 		Locale      string `json:"locale"`
 		DonorToken  string `json:"donor_token"` // anonymous donor token (optional)
@@ -113,6 +128,9 @@ func (h *StripeHandler) Checkout(w http.ResponseWriter, r *http.Request) {
 		Amount:      req.Amount,
 		Currency:    req.Currency,
 		IsRecurring: req.IsRecurring,
+		Interval:    req.Interval,
+		TierID:      req.TierID,
+		CoverFees:   req.CoverFees,
 		Message:     req.Message,
 		Locale:      req.Locale,
 		FrontendURL: h.frontendURL,
@@ -120,6 +138,34 @@ func (h *StripeHandler) Checkout(w http.ResponseWriter, r *http.Request) {
 		DonorID:     donorID,
 	})
 	if err != nil {
+		if errors.Is(err, service.ErrRecurringRequiresLogin) {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "recurring_requires_login"})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidTier) {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_tier"})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidInterval) {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_interval"})
+			return
+		}
+		if errors.Is(err, service.ErrCoverFeesUnsupported) {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "cover_fees_unsupported"})
+			return
+		}
+		if errors.Is(err, service.ErrProjectNotAcceptingDonations) {
+			w.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "project_not_accepting_donations"})
+			return
+		}
+		if writeStripeError(w, err) {
+			return
+		}
 		w.WriteHeader(http.StatusUnprocessableEntity)
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
 		return
@@ -174,6 +220,234 @@ func (h *StripeHandler) Webhook(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(map[string]bool{"received": true})
 }
 
+// CheckoutStatus handles GET /api/donations/checkout/{session_id}
+// 決済完了後のリダイレクト先（?session_id=）からフロントがポーリングし、寄付が記録されたかを確認する。
+// Session ID は推測できないため、知っている人（決済した本人）にだけ状態を返す。
+func (h *StripeHandler) CheckoutStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	sessionID := r.PathValue("session_id")
+	cs, err := h.svc.GetCheckoutSession(r.Context(), sessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) || errors.Is(err, service.ErrCheckoutSessionsNotConfigured) {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "not_found"})
+			return
+		}
+		slog.Error("get checkout session failed", "error", err, "session_id", sessionID)
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "internal_error"})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(cs)
+}
+
+// CheckoutStats handles GET /api/projects/{id}/checkout-stats (project maintainer or host).
+// Query params: days=1..365 (default: 30)
+// 期間内に開始された Checkout Session の完了・期限切れ（放棄）の件数と放棄率を返す。
+func (h *StripeHandler) CheckoutStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if h.projectSvc == nil {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "not_found"})
+		return
+	}
+	_, projectID, ok := authorizeProjectManager(w, r, h.projectSvc)
+	if !ok {
+		return
+	}
+
+	days := 30
+	if d := r.URL.Query().Get("days"); d != "" {
+		n, err := strconv.Atoi(d)
+		if err != nil || n < 1 || n > 365 {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_days"})
+			return
+		}
+		days = n
+	}
+
+	stats, err := h.svc.CheckoutStats(r.Context(), projectID, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		if errors.Is(err, service.ErrCheckoutSessionsNotConfigured) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "checkout_sessions_not_configured"})
+			return
+		}
+		slog.Error("checkout stats failed", "error", err, "project_id", projectID)
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "internal_error"})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(stats)
+}
+
+// BillingPortal handles POST /api/me/billing-portal (auth required).
+// Returns a Stripe Customer Portal URL where the donor can manage their recurring
+// donations and cards. Customers exist per Stripe account, so ?project_id= opens the
+// portal of that project's connected account; without it, the platform account's
+// portal (host projects). Changes made there come back through webhooks.
+func (h *StripeHandler) BillingPortal(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "unauthorized"})
+		return
+	}
+
+	projectID := r.URL.Query().Get("project_id")
+	url, err := h.svc.CreateBillingPortalSession(r.Context(), userID, projectID)
+	if err != nil {
+		if errors.Is(err, service.ErrNoBillingAccount) {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "no_billing_account"})
+			return
+		}
+		if errors.Is(err, service.ErrCustomersNotConfigured) || errors.Is(err, pkgstripe.ErrNotConfigured) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "billing_not_configured"})
+			return
+		}
+		if writeStripeError(w, err) {
+			return
+		}
+		slog.Error("billing portal session failed", "error", err, "user_id", userID, "project_id", projectID)
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "session_failed"})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]string{"url": url})
+}
+
+// allowedWebhookEventStatuses は GET /api/admin/webhook-events の status フィルタの許可リスト
+var allowedWebhookEventStatuses = map[string]bool{
+	"failed":     true,
+	"processing": true,
+	"processed":  true,
+}
+
+// AdminListWebhookEvents handles GET /api/admin/webhook-events (host-only).
+// Query params: status=failed|processing|processed (default: failed), limit, offset
+func (h *StripeHandler) AdminListWebhookEvents(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !requireHost(w, r) {
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = "failed"
+	}
+	if !allowedWebhookEventStatuses[status] {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_status"})
+		return
+	}
+
+	limit := 50
+	offset := 0
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if n, err := strconv.Atoi(l); err == nil && n > 0 && n <= 200 {
+			limit = n
+		}
+	}
+	if o := r.URL.Query().Get("offset"); o != "" {
+		if n, err := strconv.Atoi(o); err == nil && n >= 0 {
+			offset = n
+		}
+	}
+
+	events, err := h.svc.ListWebhookEvents(r.Context(), status, limit, offset)
+	if err != nil {
+		slog.Error("admin list webhook events failed", "error", err, "status", status)
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "list_failed"})
+		return
+	}
+	if events == nil {
+		events = []*model.WebhookEvent{}
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]any{"events": events})
+}
+
+// AdminReplayWebhookEvent handles POST /api/admin/webhook-events/{id}/replay (host-only).
+// 失敗した Webhook イベントを保存済みペイロードから再実行する。
+func (h *StripeHandler) AdminReplayWebhookEvent(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !requireHost(w, r) {
+		return
+	}
+
+	id := r.PathValue("id")
+
+	if err := h.svc.ReplayWebhookEvent(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "failed_event_not_found"})
+			return
+		}
+		slog.Error("admin replay webhook event failed", "error", err, "event_id", id)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "replay_failed", "detail": err.Error()})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+}
+
+// writeStripeError は Stripe API のエラー種別に応じたステータスと API エラーコードを書き込む。
+// err が Stripe のエラーでなければ何も書き込まずに false を返す。
+func writeStripeError(w http.ResponseWriter, err error) bool {
+	if errors.Is(err, pkgstripe.ErrNotConfigured) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "stripe_not_configured"})
+		return true
+	}
+	se, ok := pkgstripe.AsError(err)
+	if !ok {
+		return false
+	}
+
+	resp := map[string]string{}
+	status := http.StatusBadGateway
+	switch {
+	case se.IsCardError():
+		// カードの問題はユーザーに再試行を促す
+		status, resp["error"] = http.StatusPaymentRequired, "card_declined"
+		if se.DeclineCode != "" {
+			resp["decline_code"] = se.DeclineCode
+		}
+	case se.IsRateLimited():
+		w.Header().Set("Retry-After", "5")
+		status, resp["error"] = http.StatusServiceUnavailable, "stripe_rate_limited"
+	case se.IsAuthentication():
+		slog.Error("stripe authentication failed: check STRIPE_SECRET_KEY", "error", err)
+		status, resp["error"] = http.StatusServiceUnavailable, "stripe_unavailable"
+	case se.IsServerError():
+		slog.Error("stripe api error", "error", err)
+		resp["error"] = "stripe_unavailable"
+	case se.Type == pkgstripe.ErrorTypeIdempotency:
+		status, resp["error"] = http.StatusConflict, "stripe_idempotency_conflict"
+	case se.Code == "amount_too_small" || se.Code == "amount_too_large":
+		status, resp["error"] = http.StatusBadRequest, "invalid_amount"
+	default:
+		// invalid_request_error はリクエストの組み立てミス（開発バグ）
+		slog.Error("stripe rejected request", "error", err, "code", se.Code, "param", se.Param)
+		resp["error"] = "stripe_request_invalid"
+	}
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+	return true
+}
+
 // generateDonorToken は匿名寄付者用のランダムトークンを生成する。
 func generateDonorToken() string {
 	b := make([]byte, 32)
@@ -3,14 +3,20 @@ package handler
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
 	"github.com/givers/backend/internal/service"
 	"github.com/givers/backend/pkg/auth"
+	pkgstripe "github.com/givers/backend/pkg/stripe"
 )
 
 // ---------------------------------------------------------------------------
@@ -23,6 +29,11 @@ type mockStripeService struct {
 	refreshOnboardingFunc          func(ctx context.Context, projectID string) (string, error)
 	createCheckoutFunc             func(ctx context.Context, req service.CheckoutRequest) (string, error)
 	processWebhookFunc             func(ctx context.Context, payload []byte, sigHeader string) error
+	listWebhookEventsFunc          func(ctx context.Context, status string, limit, offset int) ([]*model.WebhookEvent, error)
+	replayWebhookEventFunc         func(ctx context.Context, eventID string) error
+	getCheckoutSessionFunc         func(ctx context.Context, sessionID string) (*model.CheckoutSession, error)
+	checkoutStatsFunc              func(ctx context.Context, projectID string, since time.Time) (*model.CheckoutStats, error)
+	createBillingPortalSessionFunc func(ctx context.Context, userID, projectID string) (string, error)
 }
 
 func (m *mockStripeService) CreateAccountAndOnboarding(ctx context.Context, projectID string) (string, error) {
@@ -56,6 +67,37 @@ func (m *mockStripeService) ProcessWebhook(ctx context.Context, payload []byte,
 	return nil
 }
 
+func (m *mockStripeService) ListWebhookEvents(ctx context.Context, status string, limit, offset int) ([]*model.WebhookEvent, error) {
+	if m.listWebhookEventsFunc != nil {
+		return m.listWebhookEventsFunc(ctx, status, limit, offset)
+	}
+	return nil, nil
+}
+func (m *mockStripeService) ReplayWebhookEvent(ctx context.Context, eventID string) error {
+	if m.replayWebhookEventFunc != nil {
+		return m.replayWebhookEventFunc(ctx, eventID)
+	}
+	return nil
+}
+func (m *mockStripeService) GetCheckoutSession(ctx context.Context, sessionID string) (*model.CheckoutSession, error) {
+	if m.getCheckoutSessionFunc != nil {
+		return m.getCheckoutSessionFunc(ctx, sessionID)
+	}
+	return nil, repository.ErrNotFound
+}
+func (m *mockStripeService) CheckoutStats(ctx context.Context, projectID string, since time.Time) (*model.CheckoutStats, error) {
+	if m.checkoutStatsFunc != nil {
+		return m.checkoutStatsFunc(ctx, projectID, since)
+	}
+	return &model.CheckoutStats{ProjectID: projectID, Since: since}, nil
+}
+func (m *mockStripeService) CreateBillingPortalSession(ctx context.Context, userID, projectID string) (string, error) {
+	if m.createBillingPortalSessionFunc != nil {
+		return m.createBillingPortalSessionFunc(ctx, userID, projectID)
+	}
+	return "https://billing.stripe.com/p/session/mock", nil
+}
+
 // mockStripeSessionValidator implements auth.SessionValidator for StripeHandler tests
 type mockStripeSessionValidator struct {
 	validateFunc func(ctx context.Context, token string) (string, error)
@@ -460,6 +502,29 @@ func TestStripeHandler_Checkout_OneTimeAllowedForAnonymous(t *testing.T) {
 	}
 }
 
+func TestStripeHandler_Checkout_InvalidInterval(t *testing.T) {
+	var gotInterval string
+	mock := &mockStripeService{
+		createCheckoutFunc: func(_ context.Context, req service.CheckoutRequest) (string, error) {
+			gotInterval = req.Interval
+			return "", service.ErrInvalidInterval
+		},
+	}
+	h := NewStripeHandler(mock, "https://example.com", nil)
+
+	body := bytes.NewBufferString(`{"project_id":"proj-1","amount":1000,"interval":"week"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/donations/checkout", body)
+	rec := httptest.NewRecorder()
+	h.Checkout(rec, req)
+
+	if gotInterval != "week" {
+		t.Errorf("expected interval to be passed through, got %q", gotInterval)
+	}
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "invalid_interval") {
+		t.Errorf("expected 400 invalid_interval, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
 func TestStripeHandler_Checkout_InvalidJSON(t *testing.T) {
 	h := NewStripeHandler(&mockStripeService{}, "https://example.com", nil)
 	req := httptest.NewRequest(http.MethodPost, "/api/donations/checkout",
@@ -498,6 +563,85 @@ func TestStripeHandler_Checkout_ServiceError(t *testing.T) {
 	}
 }
 
+func TestStripeHandler_Checkout_ProjectNotAcceptingDonations(t *testing.T) {
+	mock := &mockStripeService{
+		createCheckoutFunc: func(_ context.Context, _ service.CheckoutRequest) (string, error) {
+			return "", service.ErrProjectNotAcceptingDonations
+		},
+	}
+	h := NewStripeHandler(mock, "https://example.com", nil)
+	body := bytes.NewBufferString(`{"project_id":"proj-1","amount":1000,"currency":"jpy"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/donations/checkout", body)
+	rec := httptest.NewRecorder()
+	h.Checkout(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Errorf("status = %d, want 409", rec.Code)
+	}
+	var resp map[string]string
+	_ = json.NewDecoder(rec.Body).Decode(&resp)
+	if resp["error"] != "project_not_accepting_donations" {
+		t.Errorf("error = %q, want project_not_accepting_donations", resp["error"])
+	}
+}
+
+func TestStripeHandler_Checkout_StripeErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{"card declined", &pkgstripe.Error{HTTPStatus: 402, Type: pkgstripe.ErrorTypeCard, Code: "card_declined", DeclineCode: "insufficient_funds"}, http.StatusPaymentRequired, "card_declined"},
+		{"rate limited", &pkgstripe.Error{HTTPStatus: 429, Type: pkgstripe.ErrorTypeInvalidRequest, Code: "rate_limit"}, http.StatusServiceUnavailable, "stripe_rate_limited"},
+		{"bad api key", &pkgstripe.Error{HTTPStatus: 401, Type: pkgstripe.ErrorTypeAuthentication}, http.StatusServiceUnavailable, "stripe_unavailable"},
+		{"stripe outage", &pkgstripe.Error{HTTPStatus: 503, Type: pkgstripe.ErrorTypeAPI}, http.StatusBadGateway, "stripe_unavailable"},
+		{"idempotency", &pkgstripe.Error{HTTPStatus: 400, Type: pkgstripe.ErrorTypeIdempotency}, http.StatusConflict, "stripe_idempotency_conflict"},
+		{"amount too small", &pkgstripe.Error{HTTPStatus: 400, Type: pkgstripe.ErrorTypeInvalidRequest, Code: "amount_too_small"}, http.StatusBadRequest, "invalid_amount"},
+		{"invalid request", &pkgstripe.Error{HTTPStatus: 400, Type: pkgstripe.ErrorTypeInvalidRequest, Param: "mode"}, http.StatusBadGateway, "stripe_request_invalid"},
+		{"not configured", pkgstripe.ErrNotConfigured, http.StatusServiceUnavailable, "stripe_not_configured"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockStripeService{
+				createCheckoutFunc: func(_ context.Context, _ service.CheckoutRequest) (string, error) {
+					return "", fmt.Errorf("stripe checkout error: %w", tt.err)
+				},
+			}
+			h := NewStripeHandler(mock, "https://example.com", nil)
+			body := bytes.NewBufferString(`{"project_id":"proj-1","amount":1000,"currency":"jpy"}`)
+			req := httptest.NewRequest(http.MethodPost, "/api/donations/checkout", body)
+			rec := httptest.NewRecorder()
+			h.Checkout(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			var resp map[string]string
+			_ = json.NewDecoder(rec.Body).Decode(&resp)
+			if resp["error"] != tt.wantCode {
+				t.Errorf("error = %q, want %q", resp["error"], tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestStripeHandler_Checkout_CardDeclineCode(t *testing.T) {
+	mock := &mockStripeService{
+		createCheckoutFunc: func(_ context.Context, _ service.CheckoutRequest) (string, error) {
+			return "", &pkgstripe.Error{HTTPStatus: 402, Type: pkgstripe.ErrorTypeCard, Code: "card_declined", DeclineCode: "expired_card"}
+		},
+	}
+	h := NewStripeHandler(mock, "https://example.com", nil)
+	req := httptest.NewRequest(http.MethodPost, "/api/donations/checkout", bytes.NewBufferString(`{"project_id":"proj-1","amount":1000}`))
+	rec := httptest.NewRecorder()
+	h.Checkout(rec, req)
+
+	if !strings.Contains(rec.Body.String(), `"decline_code":"expired_card"`) {
+		t.Errorf("body = %s, want decline_code", rec.Body.String())
+	}
+}
+
 // ---------------------------------------------------------------------------
 // POST /api/webhooks/stripe
 // ---------------------------------------------------------------------------
@@ -555,3 +699,364 @@ func TestStripeHandler_Webhook_Success(t *testing.T) {
 		t.Errorf("unexpected payload: %q", string(capturedPayload))
 	}
 }
+
+// ---------------------------------------------------------------------------
+// GET /api/admin/webhook-events
+// ---------------------------------------------------------------------------
+
+func TestStripeHandler_AdminListWebhookEvents_RequiresHost(t *testing.T) {
+	h := NewStripeHandler(&mockStripeService{}, "https://example.com", nil)
+	req := userAuthRequest(http.MethodGet, "/api/admin/webhook-events", "")
+	rec := httptest.NewRecorder()
+	h.AdminListWebhookEvents(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for non-host, got %d", rec.Code)
+	}
+}
+
+func TestStripeHandler_AdminListWebhookEvents_DefaultsToFailed(t *testing.T) {
+	var capturedStatus string
+	mock := &mockStripeService{
+		listWebhookEventsFunc: func(_ context.Context, status string, _, _ int) ([]*model.WebhookEvent, error) {
+			capturedStatus = status
+			return []*model.WebhookEvent{{ID: "evt_1", Type: "payment_intent.succeeded", Status: "failed", Attempts: 2}}, nil
+		},
+	}
+	h := NewStripeHandler(mock, "https://example.com", nil)
+	req := adminHostRequest(http.MethodGet, "/api/admin/webhook-events", "")
+	rec := httptest.NewRecorder()
+	h.AdminListWebhookEvents(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", rec.Code, rec.Body.String())
+	}
+	if capturedStatus != "failed" {
+		t.Errorf("expected default status=failed, got %q", capturedStatus)
+	}
+	if !strings.Contains(rec.Body.String(), `"evt_1"`) {
+		t.Errorf("expected evt_1 in response, got %s", rec.Body.String())
+	}
+}
+
+func TestStripeHandler_AdminListWebhookEvents_InvalidStatus(t *testing.T) {
+	h := NewStripeHandler(&mockStripeService{}, "https://example.com", nil)
+	req := adminHostRequest(http.MethodGet, "/api/admin/webhook-events?status=bogus", "")
+	rec := httptest.NewRecorder()
+	h.AdminListWebhookEvents(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid status, got %d", rec.Code)
+	}
+}
+
+func TestStripeHandler_AdminListWebhookEvents_EmptyReturnsEmptyArray(t *testing.T) {
+	h := NewStripeHandler(&mockStripeService{}, "https://example.com", nil)
+	req := adminHostRequest(http.MethodGet, "/api/admin/webhook-events", "")
+	rec := httptest.NewRecorder()
+	h.AdminListWebhookEvents(rec, req)
+	if !strings.Contains(rec.Body.String(), `"events":[]`) {
+		t.Errorf("expected empty events array, got %s", rec.Body.String())
+	}
+}
+
+// ---------------------------------------------------------------------------
+// POST /api/admin/webhook-events/{id}/replay
+// ---------------------------------------------------------------------------
+
+func TestStripeHandler_AdminReplayWebhookEvent_Success(t *testing.T) {
+	var capturedID string
+	mock := &mockStripeService{
+		replayWebhookEventFunc: func(_ context.Context, eventID string) error {
+			capturedID = eventID
+			return nil
+		},
+	}
+	h := NewStripeHandler(mock, "https://example.com", nil)
+	req := adminHostRequest(http.MethodPost, "/api/admin/webhook-events/evt_1/replay", "")
+	req.SetPathValue("id", "evt_1")
+	rec := httptest.NewRecorder()
+	h.AdminReplayWebhookEvent(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", rec.Code, rec.Body.String())
+	}
+	if capturedID != "evt_1" {
+		t.Errorf("expected eventID=evt_1, got %q", capturedID)
+	}
+}
+
+func TestStripeHandler_AdminReplayWebhookEvent_NotFound(t *testing.T) {
+	mock := &mockStripeService{
+		replayWebhookEventFunc: func(_ context.Context, _ string) error {
+			return repository.ErrNotFound
+		},
+	}
+	h := NewStripeHandler(mock, "https://example.com", nil)
+	req := adminHostRequest(http.MethodPost, "/api/admin/webhook-events/evt_x/replay", "")
+	req.SetPathValue("id", "evt_x")
+	rec := httptest.NewRecorder()
+	h.AdminReplayWebhookEvent(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestStripeHandler_AdminReplayWebhookEvent_HandlerError(t *testing.T) {
+	mock := &mockStripeService{
+		replayWebhookEventFunc: func(_ context.Context, _ string) error {
+			return errors.New("db down")
+		},
+	}
+	h := NewStripeHandler(mock, "https://example.com", nil)
+	req := adminHostRequest(http.MethodPost, "/api/admin/webhook-events/evt_1/replay", "")
+	req.SetPathValue("id", "evt_1")
+	rec := httptest.NewRecorder()
+	h.AdminReplayWebhookEvent(rec, req)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422, got %d", rec.Code)
+	}
+}
+
+func TestStripeHandler_AdminReplayWebhookEvent_RequiresAuth(t *testing.T) {
+	h := NewStripeHandler(&mockStripeService{}, "https://example.com", nil)
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/webhook-events/evt_1/replay", nil)
+	rec := httptest.NewRecorder()
+	h.AdminReplayWebhookEvent(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestStripeHandler_Checkout_Tier(t *testing.T) {
+	tests := []struct {
+		err      error
+		wantCode string
+	}{
+		{service.ErrInvalidTier, "invalid_tier"},
+		{service.ErrRecurringRequiresLogin, "recurring_requires_login"},
+	}
+	for _, tt := range tests {
+		var gotTierID string
+		mock := &mockStripeService{
+			createCheckoutFunc: func(_ context.Context, req service.CheckoutRequest) (string, error) {
+				gotTierID = req.TierID
+				return "", tt.err
+			},
+		}
+		h := NewStripeHandler(mock, "https://example.com", nil)
+
+		body := bytes.NewBufferString(`{"project_id":"proj-1","tier_id":"t-1"}`)
+		req := httptest.NewRequest(http.MethodPost, "/api/donations/checkout", body)
+		rec := httptest.NewRecorder()
+		h.Checkout(rec, req)
+
+		if gotTierID != "t-1" {
+			t.Errorf("expected tier_id to be passed through, got %q", gotTierID)
+		}
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), tt.wantCode) {
+			t.Errorf("expected 400 %s, got %d: %s", tt.wantCode, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestStripeHandler_Checkout_CoverFees(t *testing.T) {
+	var gotCoverFees bool
+	mock := &mockStripeService{
+		createCheckoutFunc: func(_ context.Context, req service.CheckoutRequest) (string, error) {
+			gotCoverFees = req.CoverFees
+			if req.Currency == "usd" {
+				return "", service.ErrCoverFeesUnsupported
+			}
+			return "https://checkout.stripe.com/test", nil
+		},
+	}
+	h := NewStripeHandler(mock, "https://example.com", nil)
+
+	body := bytes.NewBufferString(`{"project_id":"proj-1","amount":1000,"currency":"jpy","cover_fees":true}`)
+	rec := httptest.NewRecorder()
+	h.Checkout(rec, httptest.NewRequest(http.MethodPost, "/api/donations/checkout", body))
+	if rec.Code != http.StatusOK || !gotCoverFees {
+		t.Errorf("expected cover_fees to be passed through, got %d (cover_fees=%v)", rec.Code, gotCoverFees)
+	}
+
+	body = bytes.NewBufferString(`{"project_id":"proj-1","amount":1000,"currency":"usd","cover_fees":true}`)
+	rec = httptest.NewRecorder()
+	h.Checkout(rec, httptest.NewRequest(http.MethodPost, "/api/donations/checkout", body))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "cover_fees_unsupported") {
+		t.Errorf("expected 400 cover_fees_unsupported, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+// ---------------------------------------------------------------------------
+// GET /api/donations/checkout/{session_id}, GET /api/projects/{id}/checkout-stats
+// ---------------------------------------------------------------------------
+
+func TestStripeHandler_CheckoutStatus_Success(t *testing.T) {
+	var gotID string
+	mock := &mockStripeService{
+		getCheckoutSessionFunc: func(_ context.Context, sessionID string) (*model.CheckoutSession, error) {
+			gotID = sessionID
+			return &model.CheckoutSession{
+				ID: sessionID, ProjectID: "proj-1", DonorID: "user-1", StripePaymentID: "pi_1",
+				Amount: 2000, Status: model.CheckoutSessionComplete, DonationRecorded: true,
+			}, nil
+		},
+	}
+	h := NewStripeHandler(mock, "https://example.com", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/donations/checkout/cs_1", nil)
+	req.SetPathValue("session_id", "cs_1")
+	rec := httptest.NewRecorder()
+	h.CheckoutStatus(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if gotID != "cs_1" {
+		t.Errorf("expected session_id=cs_1, got %q", gotID)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, `"status":"complete"`) || !strings.Contains(body, `"donation_recorded":true`) {
+		t.Errorf("unexpected body: %s", body)
+	}
+	// 寄付者や決済の ID は返さない
+	if strings.Contains(body, "user-1") || strings.Contains(body, "pi_1") {
+		t.Errorf("expected donor and payment IDs to be hidden: %s", body)
+	}
+}
+
+func TestStripeHandler_CheckoutStatus_NotFound(t *testing.T) {
+	for _, err := range []error{repository.ErrNotFound, service.ErrCheckoutSessionsNotConfigured} {
+		mock := &mockStripeService{
+			getCheckoutSessionFunc: func(_ context.Context, _ string) (*model.CheckoutSession, error) { return nil, err },
+		}
+		h := NewStripeHandler(mock, "https://example.com", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/donations/checkout/cs_x", nil)
+		req.SetPathValue("session_id", "cs_x")
+		rec := httptest.NewRecorder()
+		h.CheckoutStatus(rec, req)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%v: expected 404, got %d", err, rec.Code)
+		}
+	}
+}
+
+func TestStripeHandler_CheckoutStats_Success(t *testing.T) {
+	var gotProject string
+	var gotSince time.Time
+	mock := &mockStripeService{
+		checkoutStatsFunc: func(_ context.Context, projectID string, since time.Time) (*model.CheckoutStats, error) {
+			gotProject, gotSince = projectID, since
+			return &model.CheckoutStats{ProjectID: projectID, Started: 4, Completed: 3, Expired: 1, AbandonmentRate: 0.25}, nil
+		},
+	}
+	h := NewStripeHandler(mock, "https://example.com", nil)
+	h.SetProjectService(ownedProjectService())
+	req := userAuthRequest(http.MethodGet, "/api/projects/p1/checkout-stats?days=7", "")
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.CheckoutStats(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if gotProject != "p1" {
+		t.Errorf("expected project p1, got %q", gotProject)
+	}
+	if d := time.Since(gotSince); d < 7*24*time.Hour-time.Minute || d > 7*24*time.Hour+time.Minute {
+		t.Errorf("expected since to be 7 days ago, got %v", gotSince)
+	}
+	if !strings.Contains(rec.Body.String(), `"abandonment_rate":0.25`) {
+		t.Errorf("unexpected body: %s", rec.Body.String())
+	}
+}
+
+func TestStripeHandler_CheckoutStats_Errors(t *testing.T) {
+	notOwner := &mockMessageProjectService{
+		getByIDFunc: func(_ context.Context, id string) (*model.Project, error) {
+			return &model.Project{ID: id, OwnerID: "other-user"}, nil
+		},
+	}
+	tests := []struct {
+		name     string
+		projects service.ProjectService
+		query    string
+		code     int
+	}{
+		{"not owner", notOwner, "", http.StatusForbidden},
+		{"invalid days", ownedProjectService(), "?days=0", http.StatusBadRequest},
+		{"too many days", ownedProjectService(), "?days=366", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		h := NewStripeHandler(&mockStripeService{}, "https://example.com", nil)
+		h.SetProjectService(tt.projects)
+		req := userAuthRequest(http.MethodGet, "/api/projects/p1/checkout-stats"+tt.query, "")
+		req.SetPathValue("id", "p1")
+		rec := httptest.NewRecorder()
+		h.CheckoutStats(rec, req)
+		if rec.Code != tt.code {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.code, rec.Code)
+		}
+	}
+}
+
+func TestStripeHandler_BillingPortal_Success(t *testing.T) {
+	var gotUser, gotProject string
+	mock := &mockStripeService{
+		createBillingPortalSessionFunc: func(_ context.Context, userID, projectID string) (string, error) {
+			gotUser, gotProject = userID, projectID
+			return "https://billing.stripe.com/p/session/test", nil
+		},
+	}
+	h := NewStripeHandler(mock, "https://example.com", nil)
+	rec := httptest.NewRecorder()
+	h.BillingPortal(rec, userAuthRequest(http.MethodPost, "/api/me/billing-portal?project_id=p1", ""))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if gotUser != "user-1" || gotProject != "p1" {
+		t.Errorf("expected user-1 / p1, got %q / %q", gotUser, gotProject)
+	}
+	var body map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || body["url"] != "https://billing.stripe.com/p/session/test" {
+		t.Errorf("unexpected body: %v (%v)", body, err)
+	}
+}
+
+func TestStripeHandler_BillingPortal_Errors(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+		body string
+	}{
+		{service.ErrNoBillingAccount, http.StatusNotFound, "no_billing_account"},
+		{service.ErrCustomersNotConfigured, http.StatusServiceUnavailable, "billing_not_configured"},
+		{pkgstripe.ErrNotConfigured, http.StatusServiceUnavailable, "billing_not_configured"},
+		{errors.New("db down"), http.StatusInternalServerError, "session_failed"},
+	}
+	for _, tt := range tests {
+		mock := &mockStripeService{
+			createBillingPortalSessionFunc: func(_ context.Context, _, _ string) (string, error) { return "", tt.err },
+		}
+		h := NewStripeHandler(mock, "https://example.com", nil)
+		rec := httptest.NewRecorder()
+		h.BillingPortal(rec, userAuthRequest(http.MethodPost, "/api/me/billing-portal", ""))
+		if rec.Code != tt.code || !strings.Contains(rec.Body.String(), tt.body) {
+			t.Errorf("%v: expected %d %s, got %d %s", tt.err, tt.code, tt.body, rec.Code, rec.Body.String())
+		}
+	}
+
+	h := NewStripeHandler(&mockStripeService{}, "https://example.com", nil)
+	rec := httptest.NewRecorder()
+	h.BillingPortal(rec, httptest.NewRequest(http.MethodPost, "/api/me/billing-portal", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a session, got %d", rec.Code)
+	}
+}
+
+func TestGenerateDonorToken_FitsDonorIDColumns(t *testing.T) {
+	// donations.donor_id・checkout_sessions.donor_id は VARCHAR(64)
+	token := generateDonorToken()
+	if len(token) > 64 {
+		t.Errorf("donor token length = %d, does not fit VARCHAR(64)", len(token))
+	}
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/service"
+)
+
+// UpstreamHandler handles the upstream projects a project forwards a share of its
+// donations to, and the passed-through support it forwarded or received.
+type UpstreamHandler struct {
+	svc        service.UpstreamService
+	projectSvc service.ProjectService
+}
+
+// NewUpstreamHandler creates an UpstreamHandler.
+func NewUpstreamHandler(svc service.UpstreamService, projectSvc service.ProjectService) *UpstreamHandler {
+	return &UpstreamHandler{svc: svc, projectSvc: projectSvc}
+}
+
+// List handles GET /api/projects/{id}/upstreams (public).
+func (h *UpstreamHandler) List(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	projectID := r.PathValue("id")
+	if _, err := h.projectSvc.GetByID(r.Context(), projectID); err != nil {
+		writeReceiptJSONError(w, http.StatusNotFound, "project_not_found")
+		return
+	}
+
+	upstreams, err := h.svc.List(r.Context(), projectID)
+	if err != nil {
+		slog.Error("list upstream projects failed", "error", err, "project_id", projectID)
+		writeReceiptJSONError(w, http.StatusInternalServerError, "list_failed")
+		return
+	}
+	if upstreams == nil {
+		upstreams = []*model.ProjectUpstream{}
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"upstreams": upstreams})
+}
+
+type upstreamReplaceRequest struct {
+	Upstreams []struct {
+		UpstreamProjectID string `json:"upstream_project_id"`
+		SharePercent      int    `json:"share_percent"`
+	} `json:"upstreams"`
+}
+
+// Replace handles PUT /api/projects/{id}/upstreams (project maintainer or host).
+// The list replaces the previous one; an empty list stops forwarding.
+// Shares apply to donations made after the change.
+func (h *UpstreamHandler) Replace(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, projectID, ok := authorizeProjectManager(w, r, h.projectSvc)
+	if !ok {
+		return
+	}
+
+	var req upstreamReplaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeReceiptJSONError(w, http.StatusBadRequest, "invalid_json")
+		return
+	}
+	in := make([]service.UpstreamInput, len(req.Upstreams))
+	for i, u := range req.Upstreams {
+		in[i] = service.UpstreamInput{UpstreamProjectID: u.UpstreamProjectID, SharePercent: u.SharePercent}
+	}
+
+	upstreams, err := h.svc.Replace(r.Context(), projectID, in)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidUpstreams) {
+			writeReceiptJSONError(w, http.StatusBadRequest, "invalid_upstreams")
+			return
+		}
+		slog.Error("replace upstream projects failed", "error", err, "project_id", projectID)
+		writeReceiptJSONError(w, http.StatusInternalServerError, "update_failed")
+		return
+	}
+	if upstreams == nil {
+		upstreams = []*model.ProjectUpstream{}
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"upstreams": upstreams})
+}
+
+// PassThroughs handles GET /api/projects/{id}/pass-throughs (project maintainer or host).
+// Lists the support the project forwarded to upstream projects or received from
+// downstream projects, newest first, with the state of each Stripe transfer.
+func (h *UpstreamHandler) PassThroughs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, projectID, ok := authorizeProjectManager(w, r, h.projectSvc)
+	if !ok {
+		return
+	}
+
+	limit := 50
+	offset := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 100 {
+			limit = n
+		}
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			offset = n
+		}
+	}
+
+	list, err := h.svc.ListPassThroughs(r.Context(), projectID, limit, offset)
+	if err != nil {
+		slog.Error("list pass-throughs failed", "error", err, "project_id", projectID)
+		writeReceiptJSONError(w, http.StatusInternalServerError, "list_failed")
+		return
+	}
+	if list == nil {
+		list = []*model.PassThrough{}
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"pass_throughs": list})
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/service"
+)
+
+// ---------------------------------------------------------------------------
+// Mock UpstreamService
+// ---------------------------------------------------------------------------
+
+type mockUpstreamService struct {
+	listFunc         func(ctx context.Context, projectID string) ([]*model.ProjectUpstream, error)
+	replaceFunc      func(ctx context.Context, projectID string, in []service.UpstreamInput) ([]*model.ProjectUpstream, error)
+	passThroughsFunc func(ctx context.Context, projectID string, limit, offset int) ([]*model.PassThrough, error)
+}
+
+func (m *mockUpstreamService) List(ctx context.Context, projectID string) ([]*model.ProjectUpstream, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+func (m *mockUpstreamService) Replace(ctx context.Context, projectID string, in []service.UpstreamInput) ([]*model.ProjectUpstream, error) {
+	if m.replaceFunc != nil {
+		return m.replaceFunc(ctx, projectID, in)
+	}
+	return nil, nil
+}
+func (m *mockUpstreamService) SharePercent(_ context.Context, _ string) (int, error) {
+	return 0, nil
+}
+func (m *mockUpstreamService) SplitPayment(_ context.Context, _ *model.DonationPayment, _ int) error {
+	return nil
+}
+func (m *mockUpstreamService) RetryTransfers(_ context.Context, _ int) (int, int, error) {
+	return 0, 0, nil
+}
+func (m *mockUpstreamService) ListPassThroughs(ctx context.Context, projectID string, limit, offset int) ([]*model.PassThrough, error) {
+	if m.passThroughsFunc != nil {
+		return m.passThroughsFunc(ctx, projectID, limit, offset)
+	}
+	return nil, nil
+}
+func (m *mockUpstreamService) MonthlyForwardedByProject(_ context.Context, _ string) ([]*model.MonthlySum, error) {
+	return nil, nil
+}
+func (m *mockUpstreamService) MonthlyReceivedByProject(_ context.Context, _ string) ([]*model.MonthlySum, error) {
+	return nil, nil
+}
+
+// ---------------------------------------------------------------------------
+// Tests
+// ---------------------------------------------------------------------------
+
+func TestUpstreamHandler_List_Public(t *testing.T) {
+	svc := &mockUpstreamService{
+		listFunc: func(_ context.Context, projectID string) ([]*model.ProjectUpstream, error) {
+			return []*model.ProjectUpstream{{ProjectID: projectID, UpstreamProjectID: "p2", UpstreamProjectName: "libfoo", SharePercent: 10}}, nil
+		},
+	}
+	h := NewUpstreamHandler(svc, ownedProjectService())
+	req := httptest.NewRequest(http.MethodGet, "/api/projects/p1/upstreams", nil)
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.List(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `"upstream_project_name":"libfoo"`) || !strings.Contains(rec.Body.String(), `"share_percent":10`) {
+		t.Errorf("unexpected body: %s", rec.Body.String())
+	}
+}
+
+func TestUpstreamHandler_Replace_ForbiddenForNonOwner(t *testing.T) {
+	projMock := &mockMessageProjectService{
+		getByIDFunc: func(ctx context.Context, id string) (*model.Project, error) {
+			return &model.Project{ID: "p1", OwnerID: "other-user"}, nil
+		},
+	}
+	h := NewUpstreamHandler(&mockUpstreamService{}, projMock)
+	req := userAuthRequest(http.MethodPut, "/api/projects/p1/upstreams", `{"upstreams":[]}`)
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.Replace(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rec.Code)
+	}
+}
+
+func TestUpstreamHandler_Replace_Success(t *testing.T) {
+	var got []service.UpstreamInput
+	svc := &mockUpstreamService{
+		replaceFunc: func(_ context.Context, projectID string, in []service.UpstreamInput) ([]*model.ProjectUpstream, error) {
+			got = in
+			return []*model.ProjectUpstream{{ProjectID: projectID, UpstreamProjectID: "p2", SharePercent: 15}}, nil
+		},
+	}
+	h := NewUpstreamHandler(svc, ownedProjectService())
+	req := userAuthRequest(http.MethodPut, "/api/projects/p1/upstreams", `{"upstreams":[{"upstream_project_id":"p2","share_percent":15}]}`)
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.Replace(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(got) != 1 || got[0].UpstreamProjectID != "p2" || got[0].SharePercent != 15 {
+		t.Errorf("unexpected input: %+v", got)
+	}
+}
+
+func TestUpstreamHandler_Replace_Invalid(t *testing.T) {
+	svc := &mockUpstreamService{
+		replaceFunc: func(_ context.Context, _ string, _ []service.UpstreamInput) ([]*model.ProjectUpstream, error) {
+			return nil, service.ErrInvalidUpstreams
+		},
+	}
+	h := NewUpstreamHandler(svc, ownedProjectService())
+	req := userAuthRequest(http.MethodPut, "/api/projects/p1/upstreams", `{"upstreams":[{"upstream_project_id":"p1","share_percent":10}]}`)
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.Replace(rec, req)
+
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "invalid_upstreams") {
+		t.Errorf("expected 400 invalid_upstreams, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestUpstreamHandler_PassThroughs(t *testing.T) {
+	var gotLimit, gotOffset int
+	svc := &mockUpstreamService{
+		passThroughsFunc: func(_ context.Context, _ string, limit, offset int) ([]*model.PassThrough, error) {
+			gotLimit, gotOffset = limit, offset
+			return nil, nil
+		},
+	}
+	h := NewUpstreamHandler(svc, ownedProjectService())
+	req := userAuthRequest(http.MethodGet, "/api/projects/p1/pass-throughs?limit=20&offset=40", "")
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	h.PassThroughs(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if gotLimit != 20 || gotOffset != 40 {
+		t.Errorf("limit/offset = %d/%d, want 20/40", gotLimit, gotOffset)
+	}
+	if strings.TrimSpace(rec.Body.String()) != `{"pass_throughs":[]}` {
+		t.Errorf("unexpected body: %s", rec.Body.String())
+	}
+}
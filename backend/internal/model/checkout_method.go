@@ -0,0 +1,44 @@
+package model
+
+// Payment method types a project can accept at Stripe Checkout.
+// The values are Stripe's payment_method_types.
+const (
+	CheckoutMethodCard         = "card"
+	CheckoutMethodKonbini      = "konbini"
+	CheckoutMethodBankTransfer = "customer_balance" // bank transfer into the donor's Stripe customer balance
+)
+
+// ValidCheckoutMethod reports whether m is a payment method type a project can enable.
+func ValidCheckoutMethod(m string) bool {
+	switch m {
+	case CheckoutMethodCard, CheckoutMethodKonbini, CheckoutMethodBankTransfer:
+		return true
+	}
+	return false
+}
+
+// IsAsyncCheckoutMethod reports whether m settles after the donor leaves Checkout
+// (konbini and bank transfer are paid later, or never).
+func IsAsyncCheckoutMethod(m string) bool {
+	return m == CheckoutMethodKonbini || m == CheckoutMethodBankTransfer
+}
+
+// CheckoutMethodsFor returns the payment method types to offer at a checkout.
+// Stripe accepts konbini and Japanese bank transfers only for one-time payments
+// in JPY, so recurring and other-currency checkouts fall back to cards.
+// Returns nil when cards are the only option (Checkout's default).
+func CheckoutMethodsFor(enabled []string, isRecurring bool, currency string) []string {
+	if isRecurring || currency != "jpy" {
+		return nil
+	}
+	methods := []string{CheckoutMethodCard}
+	for _, m := range enabled {
+		if IsAsyncCheckoutMethod(m) {
+			methods = append(methods, m)
+		}
+	}
+	if len(methods) == 1 {
+		return nil
+	}
+	return methods
+}
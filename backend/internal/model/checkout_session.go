@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// Checkout session statuses (same values as Stripe's checkout.session status)
+const (
+	CheckoutSessionOpen     = "open"
+	CheckoutSessionComplete = "complete"
+	CheckoutSessionExpired  = "expired"
+)
+
+// CheckoutSession is a Stripe Checkout Session we created: a donation that has
+// been started but is only recorded as a Donation once Stripe confirms the payment.
+// Sessions the donor abandons end up expired.
+type CheckoutSession struct {
+	ID                   string     `json:"id"` // Stripe session ID (cs_...)
+	ProjectID            string     `json:"project_id"`
+	DonorType            string     `json:"-"` // "user" or "token"
+	DonorID              string     `json:"-"`
+	Amount               int        `json:"amount"`
+	Currency             string     `json:"currency"`
+	IsRecurring          bool       `json:"is_recurring"`
+	Interval             string     `json:"interval"`
+	TierID               string     `json:"tier_id,omitempty"`
+	Status               string     `json:"status"` // "open", "complete", "expired"
+	StripePaymentID      string     `json:"-"`      // set on completion (one-time)
+	StripeSubscriptionID string     `json:"-"`      // set on completion (recurring)
+	DonationRecorded     bool       `json:"donation_recorded"`
+	PaymentStatus        string     `json:"payment_status,omitempty"` // recorded donation's status; "pending" while a konbini or bank transfer payment is awaited
+	CreatedAt            time.Time  `json:"created_at"`
+	CompletedAt          *time.Time `json:"completed_at,omitempty"`
+	ExpiredAt            *time.Time `json:"expired_at,omitempty"`
+}
+
+// CheckoutStats summarizes the checkout sessions started for a project since a point in time.
+type CheckoutStats struct {
+	ProjectID       string    `json:"project_id"`
+	Since           time.Time `json:"since"`
+	Started         int       `json:"started"`
+	Completed       int       `json:"completed"`
+	Expired         int       `json:"expired"`
+	Open            int       `json:"open"`             // still payable; not counted as abandoned yet
+	AbandonedAmount int       `json:"abandoned_amount"` // total of expired sessions
+	// AbandonmentRate is expired / (completed + expired), 0 when no session has finished.
+	AbandonmentRate float64 `json:"abandonment_rate"`
+}
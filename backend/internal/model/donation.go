@@ -4,27 +4,169 @@ import "time"
 
 // Donation represents a single or recurring donation to a project.
 type Donation struct {
-	ID                   string    `json:"id"`
-	ProjectID            string    `json:"project_id"`
-	DonorType            string    `json:"donor_type"` // "token" or "user"
-	DonorID              string    `json:"donor_id"`
-	Amount               int       `json:"amount"`
-	Currency             string    `json:"currency"`
-	Message              string    `json:"message,omitempty"`
-	IsRecurring          bool      `json:"is_recurring"`
-	StripePaymentID      string    `json:"-"`
-	StripeSubscriptionID string    `json:"-"`
-	Paused               bool      `json:"paused"`
-	NextBillingMessage   string    `json:"next_billing_message,omitempty"`
-	CreatedAt            time.Time `json:"created_at"`
-	UpdatedAt            time.Time `json:"updated_at"`
+	ID                   string     `json:"id"`
+	ProjectID            string     `json:"project_id"`
+	DonorType            string     `json:"donor_type"` // "token" or "user"
+	DonorID              string     `json:"donor_id"`
+	Amount               int        `json:"amount"`         // 寄付額（プロジェクトに届く額。集計・マイルストーンはこの額を使う）
+	ChargedAmount        int        `json:"charged_amount"` // 寄付者への請求額（決済手数料を負担した場合は Amount より多い）
+	Currency             string     `json:"currency"`
+	Message              string     `json:"message,omitempty"`
+	IsRecurring          bool       `json:"is_recurring"`
+	Interval             string     `json:"interval"`          // 定期寄付の請求間隔: "month", "quarter", "half_year", "year"（単発は "month"）
+	TierID               string     `json:"tier_id,omitempty"` // 目安額（DonationTier）から寄付した場合の tier ID
+	StripePaymentID      string     `json:"-"`
+	StripeSubscriptionID string     `json:"-"`
+	Paused               bool       `json:"paused"`
+	NextBillingMessage   string     `json:"next_billing_message,omitempty"`
+	CancelAt             *time.Time `json:"cancel_at,omitempty"` // 定期寄付の解約予定日時（Stripe の cancel_at。カスタマーポータルで解約予約した場合など）
+	RefundedAmount       int        `json:"refunded_amount"`
+	RefundedAt           *time.Time `json:"refunded_at,omitempty"`
+	DisputeStatus        string     `json:"dispute_status,omitempty"` // Stripe の dispute.status（例: "needs_response", "won", "lost"）
+	DisputeAmount        int        `json:"dispute_amount,omitempty"`
+	PaymentStatus        string     `json:"payment_status"` // 決済状態: "active"、定期寄付の "past_due", "unpaid"、コンビニ・銀行振込の "pending", "failed"
+	PaymentFailedAt      *time.Time `json:"payment_failed_at,omitempty"`
+	PaymentMethod        string     `json:"payment_method"`       // "stripe" または手動記録の "bank_transfer", "cash", "other"
+	Reference            string     `json:"reference,omitempty"`  // 振込の照会番号・領収書番号など（手動記録のみ）
+	DonorName            string     `json:"donor_name,omitempty"` // アカウントを持たない寄付者の表示名（手動記録のみ）
+	ReceivedAt           *time.Time `json:"received_at,omitempty"`
+	RecordedBy           string     `json:"recorded_by,omitempty"`
+	VoidedAt             *time.Time `json:"voided_at,omitempty"`
+	VoidReason           string     `json:"void_reason,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
 }
 
+// DisputeWithholdsFunds reports whether a dispute in the given status has
+// funds withdrawn from the project (open disputes and lost disputes).
+// Inquiries (warning_*) and won disputes do not move money.
+func DisputeWithholdsFunds(status string) bool {
+	switch status {
+	case "needs_response", "under_review", "lost":
+		return true
+	}
+	return false
+}
+
+// NetAmount returns the amount that still counts toward the project after
+// refunds and disputes are subtracted. Never negative.
+// Voided manual donations count as zero.
+func (d *Donation) NetAmount() int {
+	if d.VoidedAt != nil {
+		return 0
+	}
+	return netAmount(d.Amount, d.RefundedAmount, d.DisputeStatus, d.DisputeAmount)
+}
+
+func netAmount(amount, refunded int, disputeStatus string, disputed int) int {
+	net := amount - refunded
+	if DisputeWithholdsFunds(disputeStatus) {
+		net -= disputed
+	}
+	if net < 0 {
+		return 0
+	}
+	return net
+}
+
+// Charge returns the amount charged to the donor per billing
+// (Amount when ChargedAmount is not set).
+func (d *Donation) Charge() int {
+	if d.ChargedAmount > 0 {
+		return d.ChargedAmount
+	}
+	return d.Amount
+}
+
+// CoveredFee returns the processing fee the donor pays on top of each charge
+// (0 unless the donor chose to cover fees).
+func (d *Donation) CoveredFee() int {
+	if d.ChargedAmount > d.Amount {
+		return d.ChargedAmount - d.Amount
+	}
+	return 0
+}
+
+// MonthlyAmount returns the pledge converted to a per-month amount
+// (a yearly pledge of 12,000 counts as 1,000 a month).
+func (d *Donation) MonthlyAmount() int {
+	return d.Amount / IntervalMonths(d.Interval)
+}
+
+// Recurring donation billing intervals
+const (
+	IntervalMonth    = "month"
+	IntervalQuarter  = "quarter"
+	IntervalHalfYear = "half_year"
+	IntervalYear     = "year"
+)
+
+// IntervalMonths returns how many months one charge of the interval covers.
+// Unknown or empty intervals are treated as monthly.
+func IntervalMonths(interval string) int {
+	switch interval {
+	case IntervalQuarter:
+		return 3
+	case IntervalHalfYear:
+		return 6
+	case IntervalYear:
+		return 12
+	}
+	return 1
+}
+
+// ValidInterval reports whether interval is one of the supported billing intervals.
+func ValidInterval(interval string) bool {
+	switch interval {
+	case IntervalMonth, IntervalQuarter, IntervalHalfYear, IntervalYear:
+		return true
+	}
+	return false
+}
+
+// IntervalFromStripe converts a Stripe price recurring interval and interval_count
+// into a billing interval. Combinations we do not offer fall back to "month".
+func IntervalFromStripe(interval string, count int) string {
+	if count <= 0 {
+		count = 1
+	}
+	switch {
+	case interval == "year" && count == 1:
+		return IntervalYear
+	case interval == "month" && count == 3:
+		return IntervalQuarter
+	case interval == "month" && count == 6:
+		return IntervalHalfYear
+	}
+	return IntervalMonth
+}
+
+// StripeInterval converts a billing interval into a Stripe price recurring
+// interval and interval_count.
+func StripeInterval(interval string) (string, int) {
+	if interval == IntervalYear {
+		return "year", 1
+	}
+	return "month", IntervalMonths(interval)
+}
+
+// Donation payment statuses (Stripe subscription status の要約と、後払いの支払い方法の入金状態)
+const (
+	PaymentStatusActive  = "active"
+	PaymentStatusPastDue = "past_due" // 請求失敗、Stripe が再試行中
+	PaymentStatusUnpaid  = "unpaid"   // 再試行が尽きて未払いのまま
+	PaymentStatusPending = "pending"  // コンビニ・銀行振込の入金待ち（入金されるまで集計しない）
+	PaymentStatusFailed  = "failed"   // コンビニ・銀行振込が期限までに入金されなかった
+)
+
 // DonationPatch holds fields that can be updated on a donation.
 type DonationPatch struct {
 	Amount             *int
+	ChargedAmount      *int // 手数料を負担している定期寄付の金額変更時に、上乗せ後の請求額を合わせて更新する
 	Paused             *bool
 	NextBillingMessage *string
+	PaymentStatus      *string    // Webhook からのみ更新する（寄付者の PATCH では受け付けない）
+	CancelAt           *time.Time // Webhook からのみ更新する。ゼロ値なら解約予定を取り消す
 }
 
 // MonthlySum represents the total donation amount for a single month.
@@ -39,6 +181,12 @@ type ChartDataPoint struct {
 	MinAmount    int    `json:"minAmount"`
 	TargetAmount int    `json:"targetAmount"`
 	ActualAmount int    `json:"actualAmount"`
+	// MatchedAmount is the amount matching-gift campaigns added that month (owed by sponsors, not included in ActualAmount).
+	MatchedAmount int `json:"matchedAmount"`
+	// PassThroughInAmount is the passed-through support received from downstream projects that month (not included in ActualAmount).
+	PassThroughInAmount int `json:"passThroughInAmount"`
+	// PassThroughOutAmount is the part of ActualAmount forwarded to upstream projects that month.
+	PassThroughOutAmount int `json:"passThroughOutAmount"`
 }
 
 // DonationMessage represents a donation message for project owner viewing.
@@ -48,6 +196,14 @@ type DonationMessage struct {
 	Message     string    `json:"message"`
 	CreatedAt   time.Time `json:"created_at"`
 	IsRecurring bool      `json:"is_recurring"`
+	// RefundedAmount / DisputeStatus はオーナーが返金・チャージバックを把握するためのもの
+	RefundedAmount int    `json:"refunded_amount"`
+	DisputeStatus  string `json:"dispute_status,omitempty"`
+	// PaymentStatus は定期寄付の決済状態（"past_due" などで決済失敗を把握できる）と、
+	// コンビニ・銀行振込の入金待ち（"pending"）・期限切れ（"failed"）
+	PaymentStatus string `json:"payment_status,omitempty"`
+	// TierID は目安額から寄付した場合の tier ID
+	TierID string `json:"tier_id,omitempty"`
 }
 
 // DonationMessageResult holds a page of donation messages with total count.
@@ -59,7 +215,7 @@ type DonationMessageResult struct {
 // ActivityItem represents a single entry in the activity feed.
 type ActivityItem struct {
 	ID          string    `json:"id"`
-	Type        string    `json:"type"` // "donation", "project_created", "project_updated", "milestone"
+	Type        string    `json:"type"` // "donation", "manual_donation", "project_created", "project_updated", "milestone", "refund", "dispute", "matching", "pass_through_in", "pass_through_out"
 	ProjectID   string    `json:"project_id"`
 	ProjectName string    `json:"project_name"`
 	ActorName   *string   `json:"actor_name"`
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// Recurring donation change sources
+const (
+	DonationChangeSourceDonor           = "donor"            // PATCH /api/me/donations/:id
+	DonationChangeSourceStripe          = "stripe"           // Stripe Dashboard や Customer Portal での操作（Webhook の request あり）
+	DonationChangeSourceStripeAutomatic = "stripe_automatic" // Stripe 自身による変更（pause_collection の期限切れ、請求失敗など）
+)
+
+// DonationChange records one change to a recurring donation's amount, paused
+// state, scheduled cancellation or payment status, so donors and owners can
+// see when and why it changed.
+type DonationChange struct {
+	ID            string                         `json:"id"`
+	DonationID    string                         `json:"donation_id"`
+	Source        string                         `json:"source"`               // "donor", "stripe", "stripe_automatic"
+	EventType     string                         `json:"event_type,omitempty"` // Stripe の Webhook イベント（例: "customer.subscription.updated"）
+	StripeEventID string                         `json:"-"`                    // 同じイベントを二重に記録しないために使う
+	Changes       map[string]DonationFieldChange `json:"changes"`
+	CreatedAt     time.Time                      `json:"created_at"`
+}
@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// DonationPayment is one charge that actually arrived for a donation.
+// One-time donations have a single payment; recurring donations get one per invoice.
+type DonationPayment struct {
+	ID              string     `json:"id"`
+	DonationID      string     `json:"donation_id,omitempty"` // empty once the pledge row is deleted
+	ProjectID       string     `json:"project_id"`
+	Amount          int        `json:"amount"`         // amount that reaches the project (charts, milestones, matching)
+	ChargedAmount   int        `json:"charged_amount"` // amount charged to the donor; more than Amount when the donor covered the fees
+	Currency        string     `json:"currency"`
+	StripePaymentID string     `json:"-"`               // PaymentIntent ID (pi_...)
+	StripeInvoiceID string     `json:"-"`               // Invoice ID (in_...), recurring only
+	PaymentMethod   string     `json:"payment_method"`  // "stripe" unless recorded by hand
+	IntervalMonths  int        `json:"interval_months"` // months one charge covers (12 for a yearly pledge); monthly rates spread the amount over them
+	RefundedAmount  int        `json:"refunded_amount"`
+	RefundedAt      *time.Time `json:"refunded_at,omitempty"`
+	DisputeStatus   string     `json:"dispute_status,omitempty"`
+	DisputeAmount   int        `json:"dispute_amount,omitempty"`
+	StripeFee       *int       `json:"stripe_fee,omitempty"` // fee Stripe deducted; nil until synced from the balance transaction
+	StripeNet       *int       `json:"stripe_net,omitempty"` // amount added to the Stripe balance after the fee
+	PaidAt          time.Time  `json:"paid_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// NetAmount returns the amount that still counts toward the project after
+// refunds and disputes are subtracted. Never negative.
+func (p *DonationPayment) NetAmount() int {
+	return netAmount(p.Amount, p.RefundedAmount, p.DisputeStatus, p.DisputeAmount)
+}
+
+// RetainedAmount returns the amount the project keeps for good: refunds and lost
+// disputes are subtracted, open disputes are not (they may still be won). Never negative.
+func (p *DonationPayment) RetainedAmount() int {
+	if p.DisputeStatus == "lost" {
+		return netAmount(p.Amount, p.RefundedAmount, p.DisputeStatus, p.DisputeAmount)
+	}
+	return netAmount(p.Amount, p.RefundedAmount, "", 0)
+}
@@ -0,0 +1,35 @@
+package model
+
+// DonationTier is an amount the project owner suggests to donors
+// (e.g. "Coffee ¥500 / month — covers one day of hosting").
+// Tiers are stored with the project; donations made from a tier keep its ID.
+type DonationTier struct {
+	ID          string `json:"id"`
+	Label       string `json:"label"`
+	Amount      int    `json:"amount"`
+	IsRecurring bool   `json:"is_recurring"`
+	Interval    string `json:"interval,omitempty"`    // recurring tiers only; empty means monthly
+	Description string `json:"description,omitempty"` // what the amount covers
+}
+
+// FindDonationTier returns the tier with the given ID, or nil.
+func FindDonationTier(tiers []DonationTier, id string) *DonationTier {
+	for i := range tiers {
+		if tiers[i].ID == id {
+			return &tiers[i]
+		}
+	}
+	return nil
+}
+
+// DonationTierStat reports how many donations were made from a tier.
+type DonationTierStat struct {
+	TierID          string `json:"tier_id"`
+	Label           string `json:"label"` // empty once the owner removed the tier
+	Amount          int    `json:"amount,omitempty"`
+	IsRecurring     bool   `json:"is_recurring"`
+	Removed         bool   `json:"removed"`
+	Donations       int    `json:"donations"`        // donations started from the tier (voided manual records excluded)
+	ActiveRecurring int    `json:"active_recurring"` // recurring donations from the tier still running
+	PaidAmount      int    `json:"paid_amount"`      // net amount received from those donations
+}
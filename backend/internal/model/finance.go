@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Payout is a transfer from a connected Stripe account's balance to the owner's bank account.
+type Payout struct {
+	ID              string    `json:"id"` // po_...
+	StripeAccountID string    `json:"-"`
+	Amount          int       `json:"amount"`
+	Currency        string    `json:"currency"`
+	Status          string    `json:"status"` // paid, pending, in_transit, canceled, failed
+	ArrivalDate     time.Time `json:"arrival_date"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// MonthlyFinance is what donors paid through Stripe in a month, what Stripe kept,
+// and what reached the owner's bank account.
+type MonthlyFinance struct {
+	Month    string `json:"month"` // "2026-01"
+	Currency string `json:"currency"`
+	Gross    int    `json:"gross"`    // charged to donors, before refunds
+	Fees     int    `json:"fees"`     // Stripe fees of the synced payments
+	Net      int    `json:"net"`      // Gross - Fees
+	Refunded int    `json:"refunded"` // refunded to donors
+	Payouts  int    `json:"payouts"`  // paid out to the bank (payouts arriving this month, excluding failed/canceled)
+	// UnsyncedPayments is the number of payments whose fee is not known yet.
+	// Their fee is missing from Fees until the next finance sync.
+	UnsyncedPayments int `json:"unsynced_payments"`
+}
@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+// Donation payment methods. Everything except PaymentMethodStripe is recorded
+// manually by a project maintainer or a host.
+const (
+	PaymentMethodStripe       = "stripe"
+	PaymentMethodBankTransfer = "bank_transfer"
+	PaymentMethodCash         = "cash"
+	PaymentMethodOther        = "other"
+)
+
+// IsManualPaymentMethod reports whether m is a payment method that can be recorded by hand.
+func IsManualPaymentMethod(m string) bool {
+	switch m {
+	case PaymentMethodBankTransfer, PaymentMethodCash, PaymentMethodOther:
+		return true
+	}
+	return false
+}
+
+// IsManual reports whether the donation was recorded by hand rather than through Stripe.
+func (d *Donation) IsManual() bool {
+	return d.PaymentMethod != "" && d.PaymentMethod != PaymentMethodStripe
+}
+
+// ManualDonationPatch holds fields that can be corrected on a manual donation.
+type ManualDonationPatch struct {
+	Amount        *int
+	PaymentMethod *string
+	Reference     *string
+	DonorName     *string
+	Message       *string
+	ReceivedAt    *time.Time
+}
+
+// Donation audit log actions
+const (
+	DonationAuditCreate = "create"
+	DonationAuditUpdate = "update"
+	DonationAuditVoid   = "void"
+)
+
+// DonationFieldChange is the before/after value of one field in an audit entry.
+type DonationFieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// DonationAuditEntry records who created, corrected or voided a manual donation.
+type DonationAuditEntry struct {
+	ID         string                         `json:"id"`
+	DonationID string                         `json:"donation_id"`
+	ActorID    string                         `json:"actor_id,omitempty"`
+	Action     string                         `json:"action"` // "create", "update", "void"
+	Changes    map[string]DonationFieldChange `json:"changes"`
+	Reason     string                         `json:"reason,omitempty"`
+	CreatedAt  time.Time                      `json:"created_at"`
+}
@@ -0,0 +1,73 @@
+package model
+
+import "time"
+
+// MatchingCampaign is a sponsor's pledge to match donations to a project during
+// a period: every payment is matched at RatioPercent (100 = one-to-one) until
+// MatchedAmount reaches CapAmount.
+type MatchingCampaign struct {
+	ID            string     `json:"id"`
+	ProjectID     string     `json:"project_id"`
+	SponsorName   string     `json:"sponsor_name"`
+	SponsorEmail  string     `json:"sponsor_email,omitempty"` // owner/host only
+	RatioPercent  int        `json:"ratio_percent"`
+	CapAmount     int        `json:"cap_amount"`
+	Currency      string     `json:"currency"`
+	StartsAt      time.Time  `json:"starts_at"`
+	EndsAt        time.Time  `json:"ends_at"`
+	MatchedAmount int        `json:"matched_amount"`
+	SummaryToken  string     `json:"summary_token,omitempty"` // owner/host only: grants the sponsor access to the summary
+	CreatedBy     string     `json:"created_by,omitempty"`
+	CancelledAt   *time.Time `json:"cancelled_at,omitempty"`
+	ClosedAt      *time.Time `json:"closed_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// Remaining returns how much more the sponsor can match before reaching the cap.
+func (c *MatchingCampaign) Remaining() int {
+	return max(c.CapAmount-c.MatchedAmount, 0)
+}
+
+// MatchFor returns the amount the campaign adds to a donation of amount, limited by the remaining cap.
+func (c *MatchingCampaign) MatchFor(amount int) int {
+	return max(min(amount*c.RatioPercent/100, c.Remaining()), 0)
+}
+
+// IsActiveAt reports whether donations paid at t are matched.
+func (c *MatchingCampaign) IsActiveAt(t time.Time) bool {
+	return c.CancelledAt == nil && !t.Before(c.StartsAt) && t.Before(c.EndsAt)
+}
+
+// Public returns a copy without the sponsor's contact details and summary token,
+// for the project detail page.
+func (c *MatchingCampaign) Public() *MatchingCampaign {
+	cp := *c
+	cp.SponsorEmail = ""
+	cp.SummaryToken = ""
+	cp.CreatedBy = ""
+	return &cp
+}
+
+// MatchingContribution is the amount a campaign added to one ledger payment.
+type MatchingContribution struct {
+	ID             string    `json:"id"`
+	CampaignID     string    `json:"campaign_id"`
+	PaymentID      string    `json:"payment_id,omitempty"`
+	DonationAmount int       `json:"donation_amount"`
+	MatchedAmount  int       `json:"matched_amount"`
+	PaidAt         time.Time `json:"paid_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// MatchingSummary is the invoice-style statement of what a sponsor owes for a campaign.
+// Final is true once the campaign is closed after its period ended.
+type MatchingSummary struct {
+	Campaign       *MatchingCampaign       `json:"campaign"`
+	ProjectName    string                  `json:"project_name"`
+	OwnerName      string                  `json:"owner_name"`
+	Contributions  []*MatchingContribution `json:"contributions"`
+	DonationsTotal int                     `json:"donations_total"` // donations that were matched
+	AmountOwed     int                     `json:"amount_owed"`
+	Final          bool                    `json:"final"`
+}
@@ -5,6 +5,7 @@ import "time"
 type Project struct {
 	ID               string     `json:"id"`
 	OwnerID          string     `json:"owner_id"`
+	Slug             string     `json:"slug,omitempty"` // URL 用の識別子（英小文字・数字・ハイフン。未設定なら UUID の URL のみ）
 	Name             string     `json:"name"`
 	Description      string     `json:"description"`
 	Overview         string     `json:"overview,omitempty"`
@@ -15,15 +16,48 @@ type Project struct {
 	MonthlyTarget    int        `json:"monthly_target"`
 	StripeAccountID  string     `json:"stripe_account_id,omitempty"` // Stripe Connect で取得した acct_...
 	ImageURL         string     `json:"image_url,omitempty"`
+	Category         string     `json:"category,omitempty"` // ProjectCategory の slug（未分類は空）
 	CreatedAt        time.Time  `json:"created_at"`
 	UpdatedAt        time.Time  `json:"updated_at"`
 
 	CostItems []CostItem `json:"cost_items,omitempty"`
-	Alerts    *ProjectAlerts     `json:"alerts,omitempty"`
+	// DonationTiers はオーナーが設定した寄付の目安額（表示順）
+	DonationTiers []DonationTier `json:"donation_tiers,omitempty"`
+	// PaymentMethodTypes は Checkout で受け付ける支払い方法（CheckoutMethodCard など。空ならカードのみ）
+	PaymentMethodTypes []string `json:"payment_method_types,omitempty"`
+	// Tags はオーナーが付けたタグ（小文字にそろえた正規化済みの値。表示順）
+	Tags   []string       `json:"tags,omitempty"`
+	Alerts *ProjectAlerts `json:"alerts,omitempty"`
+	// Members はプロジェクトのメンバー（GetByID のみ。作成者を含む）
+	Members []*ProjectMember `json:"members,omitempty"`
 
 	// Transient: not stored in DB, set by handlers/queries
+	// CurrentMonthlyDonations は当月の月額換算の寄付額（年払い・四半期払いは月割り）
 	CurrentMonthlyDonations int    `json:"current_monthly_donations"`
 	StripeConnectURL        string `json:"stripe_connect_url,omitempty"`
+	// StripeAccount は連結アカウントの状態（オーナー向けの GET /api/me/projects のみ。未同期なら nil）
+	StripeAccount *StripeAccountStatus `json:"stripe_account,omitempty"`
+	// MatchingCampaigns は実施中のマッチング寄付キャンペーン（GET /api/projects/{id} のみ。スポンサーの連絡先は含まない）
+	MatchingCampaigns []*MatchingCampaign `json:"matching_campaigns,omitempty"`
+}
+
+// StripeAccountStatus は Stripe 連結アカウントの決済・入金可否と未提出の要件（account.updated で同期）
+type StripeAccountStatus struct {
+	ChargesEnabled bool       `json:"charges_enabled"`
+	PayoutsEnabled bool       `json:"payouts_enabled"`
+	Requirements   []string   `json:"requirements"`              // 未提出・期限切れの要件（例: "individual.verification.document"）
+	DisabledReason string     `json:"disabled_reason,omitempty"` // 例: "requirements.past_due"
+	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+}
+
+// Capable は寄付を受け付けられる（決済・入金がどちらも有効）かどうかを返す
+func (s StripeAccountStatus) Capable() bool {
+	return s.ChargesEnabled && s.PayoutsEnabled
+}
+
+// NeedsAction はオーナーがオンボーディングをやり直す必要があるかどうかを返す
+func (s StripeAccountStatus) NeedsAction() bool {
+	return !s.Capable() || len(s.Requirements) > 0
 }
 
 // ProjectListResult はカーソルベースページネーション付きのプロジェクト一覧
@@ -32,6 +66,40 @@ type ProjectListResult struct {
 	NextCursor string     `json:"next_cursor"`
 }
 
+// プロジェクト検索の並び順
+const (
+	ProjectSearchSortRelevance = "relevance" // 関連度順（既定）。名前 > 概要 > 説明の順に重みづけ
+	ProjectSearchSortNew       = "new"
+	ProjectSearchSortHot       = "hot"
+)
+
+// ProjectSearchQuery は公開中のプロジェクトの全文検索条件
+type ProjectSearchQuery struct {
+	Text   string
+	Sort   string // ProjectSearchSort*
+	Limit  int
+	Cursor string // 前回最後のプロジェクト ID
+}
+
+// ProjectSearchHit は検索結果の 1 件。Highlight の文字列は HTML エスケープ済みで、一致箇所を <mark> で囲む
+type ProjectSearchHit struct {
+	*Project
+	Rank      float64                 `json:"rank"`
+	Highlight *ProjectSearchHighlight `json:"highlight,omitempty"`
+}
+
+// ProjectSearchHighlight は検索結果に表示する一致箇所
+type ProjectSearchHighlight struct {
+	Name    string `json:"name"`    // 名前全体
+	Snippet string `json:"snippet"` // 概要・説明のうち最初に一致した箇所の前後（一致しなければ概要の冒頭）
+}
+
+// ProjectSearchResult はカーソルベースページネーション付きの検索結果
+type ProjectSearchResult struct {
+	Projects   []*ProjectSearchHit `json:"projects"`
+	NextCursor string              `json:"next_cursor"`
+}
+
 type ProjectAlerts struct {
 	ID                string    `json:"id"`
 	ProjectID         string    `json:"project_id"`
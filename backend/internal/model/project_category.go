@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// ProjectCategory はホストが管理するプロジェクトのカテゴリ（OSS・開発ツール、ゲームなど）
+type ProjectCategory struct {
+	Slug      string    `json:"slug"` // URL やフィルタで使う識別子（英小文字・数字・ハイフン）。作成後は変更できない
+	Name      string    `json:"name"`
+	SortOrder int       `json:"sort_order"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ProjectFilter はプロジェクト一覧の絞り込み条件（空の項目は絞り込まない）
+type ProjectFilter struct {
+	Category string   // カテゴリの slug
+	Tags     []string // すべてのタグを持つプロジェクトに絞る（AND）
+}
+
+// ProjectFacetCount はファセットの値ごとの公開中のプロジェクト数
+type ProjectFacetCount struct {
+	Value string `json:"value"`          // カテゴリの slug またはタグ
+	Name  string `json:"name,omitempty"` // カテゴリの表示名（タグは空）
+	Count int    `json:"count"`
+}
+
+// ProjectFacets は GET /api/projects/facets のレスポンス。
+// カテゴリの件数はタグの絞り込みだけを適用し（カテゴリを切り替えたときの件数）、タグの件数は両方を適用する。
+type ProjectFacets struct {
+	Categories []*ProjectFacetCount `json:"categories"`
+	Tags       []*ProjectFacetCount `json:"tags"`
+}
@@ -0,0 +1,76 @@
+package model
+
+import "time"
+
+// プロジェクトのメンバーの役割（上から権限が強い）
+const (
+	ProjectRoleOwner      = "owner"      // メンバーの管理とプロジェクトの削除ができる
+	ProjectRoleMaintainer = "maintainer" // プロジェクトの編集・公開状態・寄付の記録など運営全般
+	ProjectRoleEditor     = "editor"     // 活動報告と画像の投稿のみ
+)
+
+var projectRoleRank = map[string]int{
+	ProjectRoleEditor:     1,
+	ProjectRoleMaintainer: 2,
+	ProjectRoleOwner:      3,
+}
+
+// ValidProjectRole は role がメンバーの役割として有効かどうかを返す
+func ValidProjectRole(role string) bool {
+	_, ok := projectRoleRank[role]
+	return ok
+}
+
+// ProjectRoleAtLeast は role が min 以上の権限を持つかどうかを返す（role が空なら false）
+func ProjectRoleAtLeast(role, min string) bool {
+	r, ok := projectRoleRank[role]
+	return ok && r >= projectRoleRank[min]
+}
+
+// ProjectMember はプロジェクトのメンバー
+type ProjectMember struct {
+	ProjectID string    `json:"project_id"`
+	UserID    string    `json:"user_id"`
+	UserName  string    `json:"user_name"`
+	Role      string    `json:"role"` // ProjectRole*
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// 招待の状態
+const (
+	ProjectInvitationPending  = "pending"
+	ProjectInvitationAccepted = "accepted"
+	ProjectInvitationDeclined = "declined"
+	ProjectInvitationRevoked  = "revoked" // 招待した側が取り消した
+)
+
+// ProjectInvitation はメンバーへの招待。相手はメールアドレスかユーザー ID のどちらかで指定する
+type ProjectInvitation struct {
+	ID          string     `json:"id"`
+	ProjectID   string     `json:"project_id"`
+	ProjectName string     `json:"project_name"`
+	Email       string     `json:"email,omitempty"`
+	UserID      string     `json:"user_id,omitempty"`
+	Role        string     `json:"role"`
+	InvitedBy   string     `json:"invited_by,omitempty"` // 招待したユーザーの ID（退会済みなら空）
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+}
+
+// RoleOf はユーザーのプロジェクトでの役割を返す（メンバーでなければ空）。
+// 作成者（OwnerID）は Members を読み込んでいなくても常に owner とみなす
+func (p *Project) RoleOf(userID string) string {
+	if userID == "" {
+		return ""
+	}
+	if p.OwnerID == userID {
+		return ProjectRoleOwner
+	}
+	for _, m := range p.Members {
+		if m.UserID == userID {
+			return m.Role
+		}
+	}
+	return ""
+}
@@ -0,0 +1,123 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// プロジェクトの更新履歴の種類
+const (
+	ProjectRevisionCreate  = "create"  // 作成時の内容（変更はなく、作成時の予算に戻すための snapshot だけを持つ）
+	ProjectRevisionUpdate  = "update"  // PUT /api/projects/{id} による更新
+	ProjectRevisionRestore = "restore" // 過去の履歴の内容への復元
+)
+
+// ProjectSnapshot は更新履歴に残すプロジェクトの編集可能な項目（画像・ステータス・通知設定は含まない）
+type ProjectSnapshot struct {
+	Name               string         `json:"name"`
+	Description        string         `json:"description"`
+	Overview           string         `json:"overview"`
+	ShareMessage       string         `json:"share_message"`
+	Deadline           *time.Time     `json:"deadline"`
+	OwnerWantMonthly   *int           `json:"owner_want_monthly"`
+	MonthlyTarget      int            `json:"monthly_target"`
+	CostItems          []CostItem     `json:"cost_items"`
+	DonationTiers      []DonationTier `json:"donation_tiers"`
+	PaymentMethodTypes []string       `json:"payment_method_types"`
+	Category           string         `json:"category"`
+	Tags               []string       `json:"tags"`
+	Slug               string         `json:"slug"`
+}
+
+// SnapshotOf は p の編集可能な項目を取り出す。月額目標は cost_items から計算し直す
+func SnapshotOf(p *Project) ProjectSnapshot {
+	s := ProjectSnapshot{
+		Name:               p.Name,
+		Description:        p.Description,
+		Overview:           p.Overview,
+		ShareMessage:       p.ShareMessage,
+		OwnerWantMonthly:   p.OwnerWantMonthly,
+		MonthlyTarget:      TotalMonthly(p.CostItems),
+		CostItems:          p.CostItems,
+		DonationTiers:      p.DonationTiers,
+		PaymentMethodTypes: p.PaymentMethodTypes,
+		Category:           p.Category,
+		Tags:               p.Tags,
+		Slug:               p.Slug,
+	}
+	if p.Deadline != nil {
+		d := p.Deadline.UTC()
+		s.Deadline = &d
+	}
+	// 空の配列と未設定を同じ値として比べる
+	if len(s.CostItems) == 0 {
+		s.CostItems = nil
+	}
+	if len(s.DonationTiers) == 0 {
+		s.DonationTiers = nil
+	}
+	if len(s.PaymentMethodTypes) == 0 {
+		s.PaymentMethodTypes = nil
+	}
+	if len(s.Tags) == 0 {
+		s.Tags = nil
+	}
+	return s
+}
+
+// ApplyTo は s の項目を p に書き戻す（復元用）
+func (s ProjectSnapshot) ApplyTo(p *Project) {
+	p.Name = s.Name
+	p.Description = s.Description
+	p.Overview = s.Overview
+	p.ShareMessage = s.ShareMessage
+	p.Deadline = s.Deadline
+	p.OwnerWantMonthly = s.OwnerWantMonthly
+	p.MonthlyTarget = s.MonthlyTarget
+	p.CostItems = s.CostItems
+	p.DonationTiers = s.DonationTiers
+	p.PaymentMethodTypes = s.PaymentMethodTypes
+	p.Category = s.Category
+	p.Tags = s.Tags
+	p.Slug = s.Slug
+}
+
+// Diff は s から next への項目ごとの変更を JSON のキー名で返す（変更がなければ空）
+func (s ProjectSnapshot) Diff(next ProjectSnapshot) map[string]ProjectFieldChange {
+	from, to := snapshotFields(s), snapshotFields(next)
+	changes := map[string]ProjectFieldChange{}
+	for key, v := range to {
+		if !bytes.Equal(from[key], v) {
+			changes[key] = ProjectFieldChange{From: from[key], To: v}
+		}
+	}
+	return changes
+}
+
+func snapshotFields(s ProjectSnapshot) map[string]json.RawMessage {
+	b, _ := json.Marshal(s)
+	var fields map[string]json.RawMessage
+	_ = json.Unmarshal(b, &fields)
+	return fields
+}
+
+// ProjectFieldChange は更新履歴の 1 項目の変更前後の値
+type ProjectFieldChange struct {
+	From json.RawMessage `json:"from"`
+	To   json.RawMessage `json:"to"`
+}
+
+// ProjectRevision はプロジェクトの 1 回の更新（誰が・いつ・どの項目をどう変えたか）
+type ProjectRevision struct {
+	ID           string                        `json:"id"`
+	ProjectID    string                        `json:"project_id"`
+	ActorID      string                        `json:"actor_id,omitempty"` // 退会済みなら空
+	ActorName    string                        `json:"actor_name,omitempty"`
+	Action       string                        `json:"action"` // ProjectRevision*
+	Changes      map[string]ProjectFieldChange `json:"changes"`
+	RestoredFrom string                        `json:"restored_from,omitempty"` // 復元元の履歴 ID（action が restore の場合）
+	CreatedAt    time.Time                     `json:"created_at"`
+	// Snapshot は更新後の編集可能な項目（復元に使う。API には返さない）
+	Snapshot ProjectSnapshot `json:"-"`
+}
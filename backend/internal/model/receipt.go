@@ -0,0 +1,56 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Receipt kinds
+const (
+	ReceiptKindDonation = "donation" // one donation (every payment of a recurring donation)
+	ReceiptKindAnnual   = "annual"   // every donation of a donor in one calendar year
+)
+
+// ReceiptNumber is the sequential number assigned when a receipt or statement is first issued.
+// Issuing the same document again returns the same number.
+type ReceiptNumber struct {
+	Number   int64     `json:"number"`
+	IssuedAt time.Time `json:"issued_at"`
+}
+
+// Label returns the zero-padded receipt number printed on documents.
+func (n ReceiptNumber) Label() string {
+	return fmt.Sprintf("%08d", n.Number)
+}
+
+// ReceiptLine is one payment listed on a receipt, with the project and its owner.
+// Amount is net of refunds and disputes.
+type ReceiptLine struct {
+	PaymentID     string    `json:"payment_id"`
+	DonationID    string    `json:"donation_id"`
+	ProjectID     string    `json:"project_id"`
+	ProjectName   string    `json:"project_name"`
+	OwnerName     string    `json:"owner_name"`
+	PaymentMethod string    `json:"payment_method"`
+	Amount        int       `json:"amount"`
+	Currency      string    `json:"currency"`
+	PaidAt        time.Time `json:"paid_at"`
+}
+
+// CurrencyAmount is a total in one currency.
+type CurrencyAmount struct {
+	Currency string `json:"currency"`
+	Amount   int    `json:"amount"`
+}
+
+// Receipt is a donor's record of giving: either one donation or an annual statement.
+// It is not a tax receipt; the project owner who received the donation issues those.
+type Receipt struct {
+	ReceiptNumber
+	Kind       string           `json:"kind"`
+	DonationID string           `json:"donation_id,omitempty"` // kind "donation" only
+	Year       int              `json:"year,omitempty"`        // kind "annual" only
+	DonorName  string           `json:"donor_name"`
+	Lines      []*ReceiptLine   `json:"lines"`
+	Totals     []CurrencyAmount `json:"totals"` // one per currency, in order of first appearance
+}
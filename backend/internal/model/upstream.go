@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+// MaxUpstreamSharePercent is the largest total share a project can forward to its upstream projects.
+const MaxUpstreamSharePercent = 100
+
+// MaxUpstreams is the number of upstream projects a project can declare.
+const MaxUpstreams = 10
+
+// ProjectUpstream is a project hosted here that another project depends on, with the
+// percentage of each donation to the dependent project that is forwarded to it.
+type ProjectUpstream struct {
+	ProjectID           string    `json:"project_id"`
+	UpstreamProjectID   string    `json:"upstream_project_id"`
+	UpstreamProjectName string    `json:"upstream_project_name"`
+	SharePercent        int       `json:"share_percent"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+// ShareOf returns the part of amount forwarded to the upstream project, rounded down.
+func (u *ProjectUpstream) ShareOf(amount int) int {
+	return max(amount*u.SharePercent/100, 0)
+}
+
+// Pass-through transfer statuses.
+const (
+	PassThroughStatusPending     = "pending"     // recorded, the Stripe transfer has not been made yet
+	PassThroughStatusTransferred = "transferred" // sent to the upstream project (or kept on the platform for a host project)
+	PassThroughStatusFailed      = "failed"      // the transfer failed and will be retried
+)
+
+// PassThrough is the part of one ledger payment a project forwarded to an upstream
+// project ("passed-through support"). It appears in the activity feeds and charts of
+// both projects; the payment itself still counts toward the receiving project.
+type PassThrough struct {
+	ID               string     `json:"id"`
+	PaymentID        string     `json:"payment_id,omitempty"` // empty once the payment row is deleted
+	FromProjectID    string     `json:"from_project_id"`
+	FromProjectName  string     `json:"from_project_name"`
+	ToProjectID      string     `json:"to_project_id"`
+	ToProjectName    string     `json:"to_project_name"`
+	Amount           int        `json:"amount"`
+	Currency         string     `json:"currency"`
+	SharePercent     int        `json:"share_percent"`
+	Status           string     `json:"status"`
+	StripeTransferID string     `json:"-"`                    // Transfer ID (tr_...); empty for a host project on the platform account
+	LastError        string     `json:"last_error,omitempty"` // why the last transfer attempt failed
+	PaidAt           time.Time  `json:"paid_at"`
+	TransferredAt    *time.Time `json:"transferred_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
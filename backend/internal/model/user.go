@@ -12,6 +12,9 @@ type User struct {
 	SuspendedAt *time.Time `json:"suspended_at,omitempty"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// StripeCustomerID はプラットフォームアカウント上の Stripe Customer（未作成なら空。連結アカウント上の Customer は stripe_customers）
+	StripeCustomerID string `json:"-"`
 }
 
 // IsSuspended returns true if the user account is currently suspended.
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookEvent is a Stripe webhook delivery persisted for deduplication and replay.
+type WebhookEvent struct {
+	ID          string          `json:"id"` // Stripe event ID (evt_...)
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"` // "processing", "processed", "failed"
+	Attempts    int             `json:"attempts"`
+	LastError   string          `json:"last_error,omitempty"`
+	ProcessedAt *time.Time      `json:"processed_at,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
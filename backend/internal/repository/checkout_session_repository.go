@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/givers/backend/internal/model"
+)
+
+// CheckoutSessionRepository handles persistence for the Stripe Checkout Sessions we create.
+type CheckoutSessionRepository interface {
+	// Create records a newly created session as open.
+	Create(ctx context.Context, cs *model.CheckoutSession) error
+	// MarkCompleted records that the session was paid, with the resulting PaymentIntent or Subscription.
+	// Returns ErrNotFound when the session is unknown.
+	MarkCompleted(ctx context.Context, id, paymentID, subscriptionID string) error
+	// MarkExpired records that an open session expired without payment.
+	// Returns ErrNotFound when no open session with the ID exists.
+	MarkExpired(ctx context.Context, id string) error
+	// GetByID returns a session, including whether its donation has been recorded yet.
+	GetByID(ctx context.Context, id string) (*model.CheckoutSession, error)
+	// StatsByProject counts the project's sessions created at or after since, by status.
+	StatsByProject(ctx context.Context, projectID string, since time.Time) (*model.CheckoutStats, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/givers/backend/internal/model"
+)
+
+// DonationChangeRepository handles the change history of recurring donations.
+type DonationChangeRepository interface {
+	// Create appends a change and fills in its ID and CreatedAt.
+	// Returns ErrDuplicate if a change for the same Stripe event is already recorded.
+	Create(ctx context.Context, c *model.DonationChange) error
+	// ListByDonation returns the changes of a donation, oldest first.
+	ListByDonation(ctx context.Context, donationID string) ([]*model.DonationChange, error)
+	// ListByProject returns the changes of a project's donations, newest first.
+	ListByProject(ctx context.Context, projectID string, limit, offset int) ([]*model.DonationChange, error)
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/givers/backend/internal/model"
+)
+
+// DonationPaymentRepository handles the ledger of charges that actually arrived.
+type DonationPaymentRepository interface {
+	// Create inserts a payment and fills in ID and timestamps.
+	// Returns ErrDuplicate if the Stripe payment or invoice is already recorded.
+	Create(ctx context.Context, p *model.DonationPayment) error
+	// GetByStripePaymentID returns a payment by its PaymentIntent ID.
+	GetByStripePaymentID(ctx context.Context, paymentID string) (*model.DonationPayment, error)
+	// ListByDonation returns the payments for a donation, oldest first.
+	ListByDonation(ctx context.Context, donationID string) ([]*model.DonationPayment, error)
+	// RecordRefund sets the cumulative refunded amount of a payment.
+	// refundedAmount is the total refunded so far (Stripe charge.amount_refunded), not a delta.
+	RecordRefund(ctx context.Context, id string, refundedAmount int) error
+	// RecordDispute sets the dispute status and disputed amount of a payment.
+	RecordDispute(ctx context.Context, id string, status string, amount int) error
+	// RecordStripeBalance sets the Stripe fee and net amount of the payment with the given PaymentIntent ID.
+	// Returns ErrNotFound if no payment has that PaymentIntent.
+	RecordStripeBalance(ctx context.Context, paymentID, balanceTransactionID string, fee, net int) error
+}
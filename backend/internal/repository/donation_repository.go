@@ -8,7 +8,7 @@ import (
 
 // DonationRepository handles persistence for donations.
 type DonationRepository interface {
-	// Create inserts a new donation record.
+	// Create inserts a new donation record and fills in its ID.
 	Create(ctx context.Context, d *model.Donation) error
 	// ListByUser returns donations where donor_type='user' and donor_id=userID.
 	ListByUser(ctx context.Context, userID string, limit, offset int) ([]*model.Donation, error)
@@ -22,16 +22,32 @@ type DonationRepository interface {
 	DeleteByStripeSubscriptionID(ctx context.Context, subscriptionID string) error
 	// GetByStripeSubscriptionID returns a donation by its stripe_subscription_id.
 	GetByStripeSubscriptionID(ctx context.Context, subscriptionID string) (*model.Donation, error)
+	// GetByStripePaymentID returns a donation by its stripe_payment_id (PaymentIntent ID).
+	GetByStripePaymentID(ctx context.Context, paymentID string) (*model.Donation, error)
+	// ListStripeLinked returns every donation tied to a Stripe PaymentIntent or subscription
+	// (used by the reconcile command).
+	ListStripeLinked(ctx context.Context) ([]*model.Donation, error)
+	// SyncAdjustments copies the refund / dispute state of a donation's payments
+	// (donation_payments) onto the donation row for display.
+	SyncAdjustments(ctx context.Context, id string) error
 	// MigrateToken migrates donations from donor_type='token' to donor_type='user'.
 	// Returns the number of rows updated.
 	MigrateToken(ctx context.Context, token string, userID string) (int, error)
-	// CurrentMonthSumByProject returns the total donation amount for a project in the current month.
+	// CurrentMonthSumByProject returns the monthly rate of a project for the current month.
+	// Sums read the payments ledger (donation_payments) by paid_at, so each monthly
+	// charge counts in the month it arrived; quarterly and yearly charges are divided
+	// by the months they cover and count in each of those months.
+	// Refunded and disputed amounts are excluded.
 	CurrentMonthSumByProject(ctx context.Context, projectID string) (int, error)
-	// MonthlySumByProject returns monthly donation totals for a project (last 12 months).
+	// MonthlySumByProject returns monthly paid totals for a project (last 12 months).
 	MonthlySumByProject(ctx context.Context, projectID string) ([]*model.MonthlySum, error)
 	// ListByProject returns donations for a specific project.
 	ListByProject(ctx context.Context, projectID string, limit, offset int) ([]*model.Donation, error)
 	// ListMessagesByProject returns donation messages with donor names for a project.
+	// Refunded or disputed donations are included even without a message.
 	// sort must be "asc" or "desc". donor is a partial-match filter on display name.
 	ListMessagesByProject(ctx context.Context, projectID string, limit, offset int, sort, donor string) (*model.DonationMessageResult, error)
+	// TierStatsByProject returns donation counts and paid totals per donation tier of a project.
+	// Only TierID and the counters are set. Donations made without a tier are not included.
+	TierStatsByProject(ctx context.Context, projectID string) ([]*model.DonationTierStat, error)
 }
@@ -7,3 +7,9 @@ var ErrNotFound = errors.New("not found")
 
 // ErrDuplicate is returned when a unique constraint violation occurs (e.g. duplicate stripe_payment_id).
 var ErrDuplicate = errors.New("duplicate")
+
+// ErrUnknownCategory is returned when a project refers to a category that does not exist.
+var ErrUnknownCategory = errors.New("unknown category")
+
+// ErrSlugTaken is returned when a project slug is already used, or was used before a rename, by another project.
+var ErrSlugTaken = errors.New("slug taken")
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/givers/backend/internal/model"
+)
+
+// FinanceRepository handles Stripe payouts and the monthly finances built from them and the payment ledger.
+type FinanceRepository interface {
+	// UpsertPayout records a payout, or updates the amount, status and arrival date of a known one.
+	UpsertPayout(ctx context.Context, p *model.Payout) error
+	// MonthlyByProject returns the project's Stripe gross, fees, refunds and payouts per month and
+	// currency for the last 12 months, oldest first. Payouts are those of the project's connected account.
+	MonthlyByProject(ctx context.Context, projectID string) ([]*model.MonthlyFinance, error)
+}
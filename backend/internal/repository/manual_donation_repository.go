@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/givers/backend/internal/model"
+)
+
+// ManualDonationRepository handles donations recorded by hand (bank transfer, cash, ...).
+// Every write keeps the payments ledger in sync and appends an audit entry
+// in the same transaction.
+type ManualDonationRepository interface {
+	// GetByID returns a manual donation. Returns ErrNotFound for Stripe donations.
+	GetByID(ctx context.Context, id string) (*model.Donation, error)
+	// Create inserts the donation and its ledger payment, fills in ID and timestamps,
+	// and appends entry (DonationID is set by Create).
+	Create(ctx context.Context, d *model.Donation, entry *model.DonationAuditEntry) error
+	// Update saves the correctable fields of d, updates its ledger payment and appends entry.
+	// Returns ErrNotFound if the donation is voided.
+	Update(ctx context.Context, d *model.Donation, entry *model.DonationAuditEntry) error
+	// Void marks the donation voided and removes its ledger payment so it no longer
+	// counts toward totals, then appends entry. Returns ErrNotFound if already voided.
+	Void(ctx context.Context, id, reason string, entry *model.DonationAuditEntry) error
+	// ListByProject returns the manual donations of a project (voided ones included), newest first.
+	ListByProject(ctx context.Context, projectID string) ([]*model.Donation, error)
+	// ListAudit returns the audit entries of a donation, oldest first.
+	ListAudit(ctx context.Context, donationID string) ([]*model.DonationAuditEntry, error)
+}
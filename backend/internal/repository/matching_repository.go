@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/givers/backend/internal/model"
+)
+
+// MatchingRepository handles matching-gift campaigns and the amounts they added to payments.
+type MatchingRepository interface {
+	// Create inserts a campaign and fills in ID and timestamps.
+	Create(ctx context.Context, c *model.MatchingCampaign) error
+	// GetByID returns a campaign, or ErrNotFound.
+	GetByID(ctx context.Context, id string) (*model.MatchingCampaign, error)
+	// ListByProject returns every campaign of a project, newest first.
+	ListByProject(ctx context.Context, projectID string) ([]*model.MatchingCampaign, error)
+	// ListActiveByProject returns the campaigns of a project that match donations paid at t.
+	ListActiveByProject(ctx context.Context, projectID string, t time.Time) ([]*model.MatchingCampaign, error)
+	// Cancel stops a campaign from matching further donations. Returns ErrNotFound if it
+	// is already cancelled or closed.
+	Cancel(ctx context.Context, id string) error
+	// ApplyPayment matches a ledger payment against every active campaign of its project
+	// in the payment's currency, up to each campaign's cap, and returns the new contributions.
+	// A payment already matched by a campaign is skipped, so retries are safe.
+	ApplyPayment(ctx context.Context, p *model.DonationPayment) ([]*model.MatchingContribution, error)
+	// ReleasePayment scales the contributions of a payment down to its retained amount
+	// (after refunds and lost disputes) and returns the difference to each campaign's cap.
+	// Contributions only ever shrink, so retries are safe.
+	ReleasePayment(ctx context.Context, p *model.DonationPayment) ([]*model.MatchingContribution, error)
+	// ListContributions returns the contributions of a campaign, oldest first.
+	ListContributions(ctx context.Context, campaignID string) ([]*model.MatchingContribution, error)
+	// MonthlyMatchedByProject returns the matched amount per month for the last 12 months.
+	MonthlyMatchedByProject(ctx context.Context, projectID string) ([]*model.MonthlySum, error)
+	// CloseEnded marks campaigns whose period ended before now as closed and returns them.
+	CloseEnded(ctx context.Context, now time.Time) ([]*model.MatchingCampaign, error)
+}
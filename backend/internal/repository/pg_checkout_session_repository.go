@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type pgCheckoutSessionRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPgCheckoutSessionRepository returns a PostgreSQL-backed CheckoutSessionRepository.
+func NewPgCheckoutSessionRepository(pool *pgxpool.Pool) CheckoutSessionRepository {
+	return &pgCheckoutSessionRepository{pool: pool}
+}
+
+func (r *pgCheckoutSessionRepository) Create(ctx context.Context, cs *model.CheckoutSession) error {
+	if cs.Status == "" {
+		cs.Status = model.CheckoutSessionOpen
+	}
+	return r.pool.QueryRow(ctx,
+		`INSERT INTO checkout_sessions
+		   (id, project_id, donor_type, donor_id, amount, currency, is_recurring, billing_interval, tier_id, status)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10)
+		 RETURNING created_at`,
+		cs.ID, cs.ProjectID, cs.DonorType, cs.DonorID, cs.Amount, cs.Currency,
+		cs.IsRecurring, cs.Interval, cs.TierID, cs.Status,
+	).Scan(&cs.CreatedAt)
+}
+
+// MarkCompleted also completes sessions already marked expired: Stripe only expires
+// unpaid sessions, so a completion always wins over an out-of-order expiry.
+func (r *pgCheckoutSessionRepository) MarkCompleted(ctx context.Context, id, paymentID, subscriptionID string) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE checkout_sessions
+		 SET status = 'complete',
+		     stripe_payment_id = COALESCE(NULLIF($2, ''), stripe_payment_id),
+		     stripe_subscription_id = COALESCE(NULLIF($3, ''), stripe_subscription_id),
+		     completed_at = COALESCE(completed_at, NOW()),
+		     expired_at = NULL
+		 WHERE id = $1`,
+		id, paymentID, subscriptionID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *pgCheckoutSessionRepository) MarkExpired(ctx context.Context, id string) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE checkout_sessions
+		 SET status = 'expired', expired_at = NOW()
+		 WHERE id = $1 AND status = 'open'`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetByID reports the donation as recorded once the webhook for the session's
+// PaymentIntent or Subscription has created it. A konbini or bank transfer donation
+// awaiting payment is not recorded yet; its payment status says so.
+func (r *pgCheckoutSessionRepository) GetByID(ctx context.Context, id string) (*model.CheckoutSession, error) {
+	cs := &model.CheckoutSession{}
+	err := r.pool.QueryRow(ctx,
+		`SELECT cs.id, cs.project_id, cs.donor_type, cs.donor_id, cs.amount, cs.currency,
+		        cs.is_recurring, cs.billing_interval, COALESCE(cs.tier_id, ''), cs.status,
+		        COALESCE(cs.stripe_payment_id, ''), COALESCE(cs.stripe_subscription_id, ''),
+		        COALESCE(d.payment_status NOT IN ('pending', 'failed'), false), COALESCE(d.payment_status, ''),
+		        cs.created_at, cs.completed_at, cs.expired_at
+		 FROM checkout_sessions cs
+		 LEFT JOIN LATERAL (
+		   SELECT d.payment_status FROM donations d
+		   WHERE (cs.stripe_payment_id IS NOT NULL AND d.stripe_payment_id = cs.stripe_payment_id)
+		      OR (cs.stripe_subscription_id IS NOT NULL AND d.stripe_subscription_id = cs.stripe_subscription_id)
+		   LIMIT 1
+		 ) d ON true
+		 WHERE cs.id = $1`, id,
+	).Scan(
+		&cs.ID, &cs.ProjectID, &cs.DonorType, &cs.DonorID, &cs.Amount, &cs.Currency,
+		&cs.IsRecurring, &cs.Interval, &cs.TierID, &cs.Status,
+		&cs.StripePaymentID, &cs.StripeSubscriptionID,
+		&cs.DonationRecorded, &cs.PaymentStatus,
+		&cs.CreatedAt, &cs.CompletedAt, &cs.ExpiredAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
+func (r *pgCheckoutSessionRepository) StatsByProject(ctx context.Context, projectID string, since time.Time) (*model.CheckoutStats, error) {
+	st := &model.CheckoutStats{ProjectID: projectID, Since: since}
+	err := r.pool.QueryRow(ctx,
+		`SELECT COUNT(*),
+		        COUNT(*) FILTER (WHERE status = 'complete'),
+		        COUNT(*) FILTER (WHERE status = 'expired'),
+		        COUNT(*) FILTER (WHERE status = 'open'),
+		        COALESCE(SUM(amount) FILTER (WHERE status = 'expired'), 0)
+		 FROM checkout_sessions
+		 WHERE project_id = $1 AND created_at >= $2`,
+		projectID, since,
+	).Scan(&st.Started, &st.Completed, &st.Expired, &st.Open, &st.AbandonedAmount)
+	if err != nil {
+		return nil, err
+	}
+	return st, nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type pgDonationChangeRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPgDonationChangeRepository returns a PostgreSQL-backed DonationChangeRepository.
+func NewPgDonationChangeRepository(pool *pgxpool.Pool) DonationChangeRepository {
+	return &pgDonationChangeRepository{pool: pool}
+}
+
+func (r *pgDonationChangeRepository) Create(ctx context.Context, c *model.DonationChange) error {
+	changes := c.Changes
+	if changes == nil {
+		changes = map[string]model.DonationFieldChange{}
+	}
+	b, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	err = r.pool.QueryRow(ctx,
+		`INSERT INTO donation_changes (donation_id, source, event_type, stripe_event_id, changes)
+		 VALUES ($1, $2, NULLIF($3,''), NULLIF($4,''), $5)
+		 RETURNING id, created_at`,
+		c.DonationID, c.Source, c.EventType, c.StripeEventID, b,
+	).Scan(&c.ID, &c.CreatedAt)
+	if err != nil && strings.Contains(err.Error(), "duplicate key") {
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *pgDonationChangeRepository) ListByDonation(ctx context.Context, donationID string) ([]*model.DonationChange, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, donation_id, source, COALESCE(event_type, ''), COALESCE(stripe_event_id, ''), changes, created_at
+		 FROM donation_changes
+		 WHERE donation_id = $1
+		 ORDER BY created_at, id`,
+		donationID)
+	if err != nil {
+		return nil, err
+	}
+	return scanDonationChanges(rows)
+}
+
+func (r *pgDonationChangeRepository) ListByProject(ctx context.Context, projectID string, limit, offset int) ([]*model.DonationChange, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT c.id, c.donation_id, c.source, COALESCE(c.event_type, ''), COALESCE(c.stripe_event_id, ''), c.changes, c.created_at
+		 FROM donation_changes c
+		 JOIN donations d ON d.id = c.donation_id
+		 WHERE d.project_id = $1
+		 ORDER BY c.created_at DESC, c.id DESC
+		 LIMIT $2 OFFSET $3`,
+		projectID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return scanDonationChanges(rows)
+}
+
+func scanDonationChanges(rows pgx.Rows) ([]*model.DonationChange, error) {
+	defer rows.Close()
+	var list []*model.DonationChange
+	for rows.Next() {
+		c := &model.DonationChange{}
+		var changes []byte
+		if err := rows.Scan(&c.ID, &c.DonationID, &c.Source, &c.EventType, &c.StripeEventID, &changes, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		_ = json.Unmarshal(changes, &c.Changes)
+		list = append(list, c)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type pgDonationPaymentRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPgDonationPaymentRepository returns a PostgreSQL-backed DonationPaymentRepository.
+func NewPgDonationPaymentRepository(pool *pgxpool.Pool) DonationPaymentRepository {
+	return &pgDonationPaymentRepository{pool: pool}
+}
+
+const donationPaymentSelectCols = `id, COALESCE(donation_id, ''), project_id, amount, COALESCE(charged_amount, amount), currency,
+	COALESCE(stripe_payment_id, ''), COALESCE(stripe_invoice_id, ''), payment_method, interval_months,
+	refunded_amount, refunded_at, COALESCE(dispute_status, ''), dispute_amount, stripe_fee, stripe_net,
+	paid_at, created_at, updated_at`
+
+func scanDonationPayment(scan func(...any) error) (*model.DonationPayment, error) {
+	p := &model.DonationPayment{}
+	return p, scan(
+		&p.ID, &p.DonationID, &p.ProjectID, &p.Amount, &p.ChargedAmount, &p.Currency,
+		&p.StripePaymentID, &p.StripeInvoiceID, &p.PaymentMethod, &p.IntervalMonths,
+		&p.RefundedAmount, &p.RefundedAt, &p.DisputeStatus, &p.DisputeAmount, &p.StripeFee, &p.StripeNet,
+		&p.PaidAt, &p.CreatedAt, &p.UpdatedAt,
+	)
+}
+
+// Create は入金を記録する。寄付者は寄付から写しておき、寄付が削除されても寄付記録に残るようにする
+func (r *pgDonationPaymentRepository) Create(ctx context.Context, p *model.DonationPayment) error {
+	err := r.pool.QueryRow(ctx,
+		`INSERT INTO donation_payments
+		 (donation_id, project_id, amount, currency, stripe_payment_id, stripe_invoice_id, paid_at, payment_method, interval_months, charged_amount,
+		  donor_type, donor_id)
+		 VALUES (NULLIF($1,''), $2, $3, $4, NULLIF($5,''), NULLIF($6,''), COALESCE($7, NOW()), COALESCE(NULLIF($8,''), 'stripe'), COALESCE(NULLIF($9, 0), 1),
+		         NULLIF(NULLIF($10, 0), $3),
+		         (SELECT donor_type FROM donations WHERE id = NULLIF($1,'')), (SELECT donor_id FROM donations WHERE id = NULLIF($1,'')))
+		 RETURNING id, paid_at, payment_method, interval_months, COALESCE(charged_amount, amount), created_at, updated_at`,
+		p.DonationID, p.ProjectID, p.Amount, p.Currency,
+		p.StripePaymentID, p.StripeInvoiceID, nullTime(p.PaidAt), p.PaymentMethod, p.IntervalMonths, p.ChargedAmount,
+	).Scan(&p.ID, &p.PaidAt, &p.PaymentMethod, &p.IntervalMonths, &p.ChargedAmount, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil && strings.Contains(err.Error(), "duplicate key") {
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *pgDonationPaymentRepository) GetByStripePaymentID(ctx context.Context, paymentID string) (*model.DonationPayment, error) {
+	row := r.pool.QueryRow(ctx,
+		`SELECT `+donationPaymentSelectCols+` FROM donation_payments WHERE stripe_payment_id = $1`, paymentID)
+	p, err := scanDonationPayment(row.Scan)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return p, err
+}
+
+func (r *pgDonationPaymentRepository) ListByDonation(ctx context.Context, donationID string) ([]*model.DonationPayment, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+donationPaymentSelectCols+`
+		 FROM donation_payments
+		 WHERE donation_id = $1
+		 ORDER BY paid_at`,
+		donationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*model.DonationPayment
+	for rows.Next() {
+		p, err := scanDonationPayment(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	return list, rows.Err()
+}
+
+func (r *pgDonationPaymentRepository) RecordRefund(ctx context.Context, id string, refundedAmount int) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE donation_payments
+		 SET refunded_amount = LEAST($2, amount), refunded_at = NOW(), updated_at = NOW()
+		 WHERE id = $1`,
+		id, refundedAmount)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *pgDonationPaymentRepository) RecordDispute(ctx context.Context, id string, status string, amount int) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE donation_payments
+		 SET dispute_status = $2, dispute_amount = LEAST($3, amount), updated_at = NOW()
+		 WHERE id = $1`,
+		id, status, amount)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *pgDonationPaymentRepository) RecordStripeBalance(ctx context.Context, paymentID, balanceTransactionID string, fee, net int) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE donation_payments
+		 SET stripe_fee = $3, stripe_net = $4, stripe_balance_transaction_id = $2, updated_at = NOW()
+		 WHERE stripe_payment_id = $1`,
+		paymentID, balanceTransactionID, fee, net)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// nullTime はゼロ値の time.Time を NULL として渡す
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -2,10 +2,13 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/givers/backend/internal/model"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -18,30 +21,62 @@ func NewPgDonationRepository(pool *pgxpool.Pool) DonationRepository {
 	return &pgDonationRepository{pool: pool}
 }
 
-const donationSelectCols = `id, project_id, donor_type, donor_id, amount, currency,
-	COALESCE(message, ''), is_recurring, COALESCE(stripe_payment_id, ''),
-	COALESCE(stripe_subscription_id, ''), paused, COALESCE(next_billing_message, ''),
+const donationSelectCols = `id, project_id, donor_type, donor_id, amount, COALESCE(charged_amount, amount), currency,
+	COALESCE(message, ''), is_recurring, billing_interval, COALESCE(tier_id, ''), COALESCE(stripe_payment_id, ''),
+	COALESCE(stripe_subscription_id, ''), paused, COALESCE(next_billing_message, ''), cancel_at,
+	refunded_amount, refunded_at, COALESCE(dispute_status, ''), dispute_amount,
+	payment_status, payment_failed_at,
+	payment_method, COALESCE(reference, ''), COALESCE(donor_name, ''), received_at,
+	COALESCE(recorded_by, ''), voided_at, COALESCE(void_reason, ''),
 	created_at, updated_at`
 
+// donationNetAmountExpr は返金額・係争中/敗訴の係争額を差し引いた入金額（集計用）。
+// model.DonationPayment.NetAmount と同じ規則。donation_payments の列を修飾なしで参照する。
+const donationNetAmountExpr = `GREATEST(amount - refunded_amount
+	- CASE WHEN dispute_status IN ('needs_response', 'under_review', 'lost') THEN dispute_amount ELSE 0 END, 0)`
+
+// donationMonthlyNetAmountExpr は入金額を 1 か月あたりに換算した額（年払いは 12 か月で割る）。
+// donationCoversCurrentMonthCond と組み合わせて月額の集計に使う。
+const donationMonthlyNetAmountExpr = `(` + donationNetAmountExpr + `) / interval_months`
+
+// donationCoversCurrentMonthCond は入金が当月をカバーしているか（当月の入金、または当月を含む期間分の年払い・四半期払いなど）。
+const donationCoversCurrentMonthCond = `paid_at >= DATE_TRUNC('month', NOW()) - (interval_months - 1) * INTERVAL '1 month'`
+
+// donationMessageFilter は owner 向けメッセージ一覧の対象行（メッセージあり、または返金・係争あり）。
+// 取り消した手動記録の寄付は含めない。
+const donationMessageFilter = `d.voided_at IS NULL AND ((d.message IS NOT NULL AND d.message != '') OR d.refunded_amount > 0 OR d.dispute_status IS NOT NULL
+	OR d.payment_status != 'active')`
+
+// donationDonorNameExpr は寄付者の表示名（アカウント名、手動記録の寄付者名、どちらもなければ Anonymous）。
+const donationDonorNameExpr = `COALESCE(u.display_name, d.donor_name, 'Anonymous')`
+
 func scanDonation(scan func(...any) error) (*model.Donation, error) {
 	d := &model.Donation{}
 	return d, scan(
 		&d.ID, &d.ProjectID, &d.DonorType, &d.DonorID,
-		&d.Amount, &d.Currency, &d.Message,
-		&d.IsRecurring, &d.StripePaymentID, &d.StripeSubscriptionID,
-		&d.Paused, &d.NextBillingMessage, &d.CreatedAt, &d.UpdatedAt,
+		&d.Amount, &d.ChargedAmount, &d.Currency, &d.Message,
+		&d.IsRecurring, &d.Interval, &d.TierID, &d.StripePaymentID, &d.StripeSubscriptionID,
+		&d.Paused, &d.NextBillingMessage, &d.CancelAt,
+		&d.RefundedAmount, &d.RefundedAt, &d.DisputeStatus, &d.DisputeAmount,
+		&d.PaymentStatus, &d.PaymentFailedAt,
+		&d.PaymentMethod, &d.Reference, &d.DonorName, &d.ReceivedAt,
+		&d.RecordedBy, &d.VoidedAt, &d.VoidReason,
+		&d.CreatedAt, &d.UpdatedAt,
 	)
 }
 
 func (r *pgDonationRepository) Create(ctx context.Context, d *model.Donation) error {
-	_, err := r.pool.Exec(ctx,
+	err := r.pool.QueryRow(ctx,
 		`INSERT INTO donations
 		 (project_id, donor_type, donor_id, amount, currency, message, is_recurring,
-		  stripe_payment_id, stripe_subscription_id)
-		 VALUES ($1, $2, $3, $4, $5, NULLIF($6,''), $7, NULLIF($8,''), NULLIF($9,''))`,
+		  stripe_payment_id, stripe_subscription_id, billing_interval, tier_id, charged_amount, payment_status)
+		 VALUES ($1, $2, $3, $4, $5, NULLIF($6,''), $7, NULLIF($8,''), NULLIF($9,''), COALESCE(NULLIF($10,''), 'month'), NULLIF($11,''),
+		         NULLIF(NULLIF($12, 0), $4), COALESCE(NULLIF($13,''), 'active'))
+		 RETURNING id, billing_interval, COALESCE(charged_amount, amount), payment_status, created_at, updated_at`,
 		d.ProjectID, d.DonorType, d.DonorID, d.Amount, d.Currency,
-		d.Message, d.IsRecurring, d.StripePaymentID, d.StripeSubscriptionID,
-	)
+		d.Message, d.IsRecurring, d.StripePaymentID, d.StripeSubscriptionID, d.Interval, d.TierID, d.ChargedAmount,
+		d.PaymentStatus,
+	).Scan(&d.ID, &d.Interval, &d.ChargedAmount, &d.PaymentStatus, &d.CreatedAt, &d.UpdatedAt)
 	if err != nil && strings.Contains(err.Error(), "duplicate key") {
 		return ErrDuplicate
 	}
@@ -83,7 +118,8 @@ func (r *pgDonationRepository) GetByID(ctx context.Context, id string) (*model.D
 }
 
 func (r *pgDonationRepository) Patch(ctx context.Context, id string, patch model.DonationPatch) error {
-	if patch.Amount == nil && patch.Paused == nil && patch.NextBillingMessage == nil {
+	if patch.Amount == nil && patch.ChargedAmount == nil && patch.Paused == nil && patch.NextBillingMessage == nil && patch.PaymentStatus == nil &&
+		patch.CancelAt == nil {
 		return nil
 	}
 
@@ -96,6 +132,11 @@ func (r *pgDonationRepository) Patch(ctx context.Context, id string, patch model
 		args = append(args, *patch.Amount)
 		argIdx++
 	}
+	if patch.ChargedAmount != nil {
+		setClauses = append(setClauses, fmt.Sprintf("charged_amount = NULLIF($%d, 0)", argIdx))
+		args = append(args, *patch.ChargedAmount)
+		argIdx++
+	}
 	if patch.Paused != nil {
 		setClauses = append(setClauses, fmt.Sprintf("paused = $%d", argIdx))
 		args = append(args, *patch.Paused)
@@ -106,6 +147,23 @@ func (r *pgDonationRepository) Patch(ctx context.Context, id string, patch model
 		args = append(args, *patch.NextBillingMessage)
 		argIdx++
 	}
+	if patch.PaymentStatus != nil {
+		// 失敗し始めた日時を保持し、active（または入金待ち）に戻ったらクリアする
+		setClauses = append(setClauses, fmt.Sprintf(
+			"payment_status = $%d, payment_failed_at = CASE WHEN $%d IN ('active', 'pending') THEN NULL ELSE COALESCE(payment_failed_at, NOW()) END",
+			argIdx, argIdx))
+		args = append(args, *patch.PaymentStatus)
+		argIdx++
+	}
+	if patch.CancelAt != nil {
+		var cancelAt *time.Time
+		if !patch.CancelAt.IsZero() {
+			cancelAt = patch.CancelAt
+		}
+		setClauses = append(setClauses, fmt.Sprintf("cancel_at = $%d", argIdx))
+		args = append(args, cancelAt)
+		argIdx++
+	}
 
 	setClauses = append(setClauses, "updated_at = NOW()")
 	args = append(args, id)
@@ -146,14 +204,91 @@ func (r *pgDonationRepository) DeleteByStripeSubscriptionID(ctx context.Context,
 	return err
 }
 
-func (r *pgDonationRepository) MigrateToken(ctx context.Context, token string, userID string) (int, error) {
+func (r *pgDonationRepository) GetByStripePaymentID(ctx context.Context, paymentID string) (*model.Donation, error) {
+	row := r.pool.QueryRow(ctx,
+		`SELECT `+donationSelectCols+` FROM donations WHERE stripe_payment_id = $1`, paymentID)
+	d, err := scanDonation(row.Scan)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return d, err
+}
+
+func (r *pgDonationRepository) SyncAdjustments(ctx context.Context, id string) error {
 	tag, err := r.pool.Exec(ctx,
+		`UPDATE donations d
+		 SET refunded_amount = s.refunded_amount,
+		     refunded_at     = s.refunded_at,
+		     dispute_status  = s.dispute_status,
+		     dispute_amount  = s.dispute_amount,
+		     updated_at      = NOW()
+		 FROM (
+		   SELECT COALESCE(SUM(refunded_amount), 0) AS refunded_amount,
+		          MAX(refunded_at) AS refunded_at,
+		          (SELECT dispute_status FROM donation_payments
+		           WHERE donation_id = $1 AND dispute_status IS NOT NULL
+		           ORDER BY updated_at DESC LIMIT 1) AS dispute_status,
+		          COALESCE(SUM(dispute_amount) FILTER (WHERE dispute_status IS NOT NULL), 0) AS dispute_amount
+		   FROM donation_payments
+		   WHERE donation_id = $1
+		 ) s
+		 WHERE d.id = $1`,
+		id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *pgDonationRepository) ListStripeLinked(ctx context.Context) ([]*model.Donation, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+donationSelectCols+`
+		 FROM donations
+		 WHERE stripe_payment_id IS NOT NULL OR stripe_subscription_id IS NOT NULL
+		 ORDER BY created_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*model.Donation
+	for rows.Next() {
+		d, err := scanDonation(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, d)
+	}
+	return list, rows.Err()
+}
+
+// MigrateToken は寄付と、その入金の寄付者をトークンからユーザーに移す
+func (r *pgDonationRepository) MigrateToken(ctx context.Context, token string, userID string) (int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx,
 		`UPDATE donations SET donor_type = 'user', donor_id = $1, updated_at = NOW()
 		 WHERE donor_type = 'token' AND donor_id = $2`,
 		userID, token)
 	if err != nil {
 		return 0, err
 	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE donation_payments SET donor_type = 'user', donor_id = $1, updated_at = NOW()
+		 WHERE donor_type = 'token' AND donor_id = $2`,
+		userID, token); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
 	return int(tag.RowsAffected()), nil
 }
 
@@ -185,11 +320,11 @@ func (r *pgDonationRepository) ListMessagesByProject(ctx context.Context, projec
 	// Count total matching messages
 	countQuery := `SELECT COUNT(*) FROM donations d
 		LEFT JOIN users u ON d.donor_type = 'user' AND d.donor_id = u.id
-		WHERE d.project_id = $1 AND d.message IS NOT NULL AND d.message != ''`
+		WHERE d.project_id = $1 AND ` + donationMessageFilter
 	countArgs := []any{projectID}
 	argIdx := 2
 	if donor != "" {
-		countQuery += fmt.Sprintf(` AND COALESCE(u.display_name, 'Anonymous') ILIKE '%%' || $%d || '%%'`, argIdx)
+		countQuery += fmt.Sprintf(` AND `+donationDonorNameExpr+` ILIKE '%%' || $%d || '%%'`, argIdx)
 		countArgs = append(countArgs, donor)
 		argIdx++
 	}
@@ -203,14 +338,16 @@ func (r *pgDonationRepository) ListMessagesByProject(ctx context.Context, projec
 	if sort == "asc" {
 		sortDir = "ASC"
 	}
-	query := fmt.Sprintf(`SELECT COALESCE(u.display_name, 'Anonymous'), d.amount, d.message, d.created_at, d.is_recurring
+	query := `SELECT ` + donationDonorNameExpr + `, d.amount, COALESCE(d.message, ''), d.created_at, d.is_recurring,
+		d.refunded_amount, COALESCE(d.dispute_status, ''),
+		CASE WHEN d.is_recurring OR d.payment_status IN ('pending', 'failed') THEN d.payment_status ELSE '' END, COALESCE(d.tier_id, '')
 		FROM donations d
 		LEFT JOIN users u ON d.donor_type = 'user' AND d.donor_id = u.id
-		WHERE d.project_id = $1 AND d.message IS NOT NULL AND d.message != ''`)
+		WHERE d.project_id = $1 AND ` + donationMessageFilter
 	args := []any{projectID}
 	argIdx = 2
 	if donor != "" {
-		query += fmt.Sprintf(` AND COALESCE(u.display_name, 'Anonymous') ILIKE '%%' || $%d || '%%'`, argIdx)
+		query += fmt.Sprintf(` AND `+donationDonorNameExpr+` ILIKE '%%' || $%d || '%%'`, argIdx)
 		args = append(args, donor)
 		argIdx++
 	}
@@ -226,7 +363,8 @@ func (r *pgDonationRepository) ListMessagesByProject(ctx context.Context, projec
 	var msgs []*model.DonationMessage
 	for rows.Next() {
 		m := &model.DonationMessage{}
-		if err := rows.Scan(&m.DonorName, &m.Amount, &m.Message, &m.CreatedAt, &m.IsRecurring); err != nil {
+		if err := rows.Scan(&m.DonorName, &m.Amount, &m.Message, &m.CreatedAt, &m.IsRecurring,
+			&m.RefundedAmount, &m.DisputeStatus, &m.PaymentStatus, &m.TierID); err != nil {
 			return nil, err
 		}
 		msgs = append(msgs, m)
@@ -240,27 +378,61 @@ func (r *pgDonationRepository) ListMessagesByProject(ctx context.Context, projec
 	return &model.DonationMessageResult{Messages: msgs, Total: total}, nil
 }
 
-// CurrentMonthSumByProject returns the total donation amount for a project in the current month.
+// TierStatsByProject returns, per tier ID, how many donations were made from a tier
+// and how much they paid. Label and amount are left for the caller to fill in from the project.
+func (r *pgDonationRepository) TierStatsByProject(ctx context.Context, projectID string) ([]*model.DonationTierStat, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT d.tier_id, COUNT(*)::int,
+		        COUNT(*) FILTER (WHERE d.is_recurring AND NOT d.paused)::int,
+		        COALESCE(SUM(p.paid), 0)::int
+		 FROM donations d
+		 LEFT JOIN LATERAL (
+		   SELECT SUM(`+donationNetAmountExpr+`) AS paid FROM donation_payments WHERE donation_id = d.id
+		 ) p ON true
+		 WHERE d.project_id = $1 AND d.tier_id IS NOT NULL AND d.voided_at IS NULL
+		   AND d.payment_status NOT IN ('pending', 'failed')
+		 GROUP BY d.tier_id`,
+		projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []*model.DonationTierStat
+	for rows.Next() {
+		s := &model.DonationTierStat{}
+		if err := rows.Scan(&s.TierID, &s.Donations, &s.ActiveRecurring, &s.PaidAmount); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
+
+// CurrentMonthSumByProject returns the monthly rate of a project: payments covering the current month,
+// with quarterly and yearly payments spread over the months they cover.
 func (r *pgDonationRepository) CurrentMonthSumByProject(ctx context.Context, projectID string) (int, error) {
 	var sum int
 	err := r.pool.QueryRow(ctx,
-		`SELECT COALESCE(SUM(amount), 0)::int
-		 FROM donations
+		`SELECT COALESCE(SUM(`+donationMonthlyNetAmountExpr+`), 0)::int
+		 FROM donation_payments
 		 WHERE project_id = $1
-		   AND created_at >= DATE_TRUNC('month', NOW())`,
+		   AND `+donationCoversCurrentMonthCond,
 		projectID,
 	).Scan(&sum)
 	return sum, err
 }
 
+// MonthlySumByProject returns the amounts actually received per month for the last 12 months
+// (a yearly payment counts in full in the month it arrived).
 func (r *pgDonationRepository) MonthlySumByProject(ctx context.Context, projectID string) ([]*model.MonthlySum, error) {
 	rows, err := r.pool.Query(ctx,
-		`SELECT TO_CHAR(DATE_TRUNC('month', created_at), 'YYYY-MM') AS month,
-		        SUM(amount)::int AS amount
-		 FROM donations
+		`SELECT TO_CHAR(DATE_TRUNC('month', paid_at), 'YYYY-MM') AS month,
+		        SUM(`+donationNetAmountExpr+`)::int AS amount
+		 FROM donation_payments
 		 WHERE project_id = $1
-		   AND created_at >= DATE_TRUNC('month', NOW()) - INTERVAL '11 months'
-		 GROUP BY DATE_TRUNC('month', created_at)
+		   AND paid_at >= DATE_TRUNC('month', NOW()) - INTERVAL '11 months'
+		 GROUP BY DATE_TRUNC('month', paid_at)
 		 ORDER BY month`,
 		projectID)
 	if err != nil {
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type pgFinanceRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPgFinanceRepository returns a PostgreSQL-backed FinanceRepository.
+func NewPgFinanceRepository(pool *pgxpool.Pool) FinanceRepository {
+	return &pgFinanceRepository{pool: pool}
+}
+
+func (r *pgFinanceRepository) UpsertPayout(ctx context.Context, p *model.Payout) error {
+	return r.pool.QueryRow(ctx,
+		`INSERT INTO stripe_payouts (id, stripe_account_id, amount, currency, status, arrival_date)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 ON CONFLICT (id) DO UPDATE
+		   SET amount = EXCLUDED.amount, status = EXCLUDED.status, arrival_date = EXCLUDED.arrival_date, updated_at = NOW()
+		 RETURNING created_at, updated_at`,
+		p.ID, p.StripeAccountID, p.Amount, p.Currency, p.Status, p.ArrivalDate,
+	).Scan(&p.CreatedAt, &p.UpdatedAt)
+}
+
+// MonthlyByProject counts Stripe payments in the month they were paid and payouts in the month
+// they arrive. Failed and canceled payouts never reach the bank and are left out.
+func (r *pgFinanceRepository) MonthlyByProject(ctx context.Context, projectID string) ([]*model.MonthlyFinance, error) {
+	rows, err := r.pool.Query(ctx,
+		`WITH charges AS (
+		   SELECT DATE_TRUNC('month', paid_at) AS month, currency,
+		          SUM(COALESCE(charged_amount, amount))::int AS gross,
+		          SUM(COALESCE(stripe_fee, 0))::int AS fees,
+		          SUM(refunded_amount)::int AS refunded,
+		          COUNT(*) FILTER (WHERE stripe_fee IS NULL)::int AS unsynced
+		   FROM donation_payments
+		   WHERE project_id = $1 AND payment_method = 'stripe'
+		     AND paid_at >= DATE_TRUNC('month', NOW()) - INTERVAL '11 months'
+		   GROUP BY 1, 2
+		 ), payouts AS (
+		   SELECT DATE_TRUNC('month', po.arrival_date) AS month, po.currency, SUM(po.amount)::int AS amount
+		   FROM stripe_payouts po
+		   JOIN projects p ON p.stripe_account_id = po.stripe_account_id
+		   WHERE p.id = $1 AND po.status NOT IN ('failed', 'canceled')
+		     AND po.arrival_date >= DATE_TRUNC('month', NOW()) - INTERVAL '11 months'
+		   GROUP BY 1, 2
+		 )
+		 SELECT TO_CHAR(COALESCE(c.month, po.month), 'YYYY-MM') AS month, COALESCE(c.currency, po.currency),
+		        COALESCE(c.gross, 0), COALESCE(c.fees, 0), COALESCE(c.refunded, 0),
+		        COALESCE(po.amount, 0), COALESCE(c.unsynced, 0)
+		 FROM charges c
+		 FULL OUTER JOIN payouts po ON po.month = c.month AND po.currency = c.currency
+		 ORDER BY 1, 2`,
+		projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*model.MonthlyFinance
+	for rows.Next() {
+		f := &model.MonthlyFinance{}
+		if err := rows.Scan(&f.Month, &f.Currency, &f.Gross, &f.Fees, &f.Refunded, &f.Payouts, &f.UnsyncedPayments); err != nil {
+			return nil, err
+		}
+		f.Net = f.Gross - f.Fees
+		list = append(list, f)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type pgManualDonationRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPgManualDonationRepository returns a PostgreSQL-backed ManualDonationRepository.
+func NewPgManualDonationRepository(pool *pgxpool.Pool) ManualDonationRepository {
+	return &pgManualDonationRepository{pool: pool}
+}
+
+func (r *pgManualDonationRepository) GetByID(ctx context.Context, id string) (*model.Donation, error) {
+	row := r.pool.QueryRow(ctx,
+		`SELECT `+donationSelectCols+` FROM donations WHERE id = $1 AND payment_method != 'stripe'`, id)
+	d, err := scanDonation(row.Scan)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return d, err
+}
+
+func (r *pgManualDonationRepository) Create(ctx context.Context, d *model.Donation, entry *model.DonationAuditEntry) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := tx.QueryRow(ctx,
+		`INSERT INTO donations
+		 (project_id, donor_type, donor_id, amount, currency, message, is_recurring,
+		  payment_method, reference, donor_name, received_at, recorded_by)
+		 VALUES ($1, $2, $3, $4, $5, NULLIF($6,''), false,
+		         $7, NULLIF($8,''), NULLIF($9,''), COALESCE($10, NOW()), NULLIF($11,''))
+		 RETURNING id, received_at, created_at, updated_at`,
+		d.ProjectID, d.DonorType, d.DonorID, d.Amount, d.Currency, d.Message,
+		d.PaymentMethod, d.Reference, d.DonorName, d.ReceivedAt, d.RecordedBy,
+	).Scan(&d.ID, &d.ReceivedAt, &d.CreatedAt, &d.UpdatedAt); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO donation_payments (donation_id, project_id, amount, currency, payment_method, paid_at, donor_type, donor_id)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		d.ID, d.ProjectID, d.Amount, d.Currency, d.PaymentMethod, d.ReceivedAt, d.DonorType, d.DonorID,
+	); err != nil {
+		return err
+	}
+
+	entry.DonationID = d.ID
+	if err := insertDonationAudit(ctx, tx, entry); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *pgManualDonationRepository) Update(ctx context.Context, d *model.Donation, entry *model.DonationAuditEntry) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx,
+		`UPDATE donations
+		 SET amount = $2, payment_method = $3, reference = NULLIF($4,''), donor_name = NULLIF($5,''),
+		     message = NULLIF($6,''), received_at = $7, updated_at = NOW()
+		 WHERE id = $1 AND payment_method != 'stripe' AND voided_at IS NULL`,
+		d.ID, d.Amount, d.PaymentMethod, d.Reference, d.DonorName, d.Message, d.ReceivedAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	if _, err := tx.Exec(ctx,
+		`UPDATE donation_payments
+		 SET amount = $2, payment_method = $3, paid_at = $4, updated_at = NOW()
+		 WHERE donation_id = $1`,
+		d.ID, d.Amount, d.PaymentMethod, d.ReceivedAt,
+	); err != nil {
+		return err
+	}
+
+	entry.DonationID = d.ID
+	if err := insertDonationAudit(ctx, tx, entry); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *pgManualDonationRepository) Void(ctx context.Context, id, reason string, entry *model.DonationAuditEntry) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx,
+		`UPDATE donations
+		 SET voided_at = NOW(), void_reason = NULLIF($2,''), updated_at = NOW()
+		 WHERE id = $1 AND payment_method != 'stripe' AND voided_at IS NULL`,
+		id, reason)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	// 入金台帳から外し、月間合計・チャート・マイルストーンの集計対象にしない
+	if _, err := tx.Exec(ctx, `DELETE FROM donation_payments WHERE donation_id = $1`, id); err != nil {
+		return err
+	}
+
+	entry.DonationID = id
+	if err := insertDonationAudit(ctx, tx, entry); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *pgManualDonationRepository) ListByProject(ctx context.Context, projectID string) ([]*model.Donation, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+donationSelectCols+`
+		 FROM donations
+		 WHERE project_id = $1 AND payment_method != 'stripe'
+		 ORDER BY received_at DESC, created_at DESC`,
+		projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*model.Donation
+	for rows.Next() {
+		d, err := scanDonation(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, d)
+	}
+	return list, rows.Err()
+}
+
+func (r *pgManualDonationRepository) ListAudit(ctx context.Context, donationID string) ([]*model.DonationAuditEntry, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, donation_id, COALESCE(actor_id, ''), action, changes, COALESCE(reason, ''), created_at
+		 FROM donation_audit_logs
+		 WHERE donation_id = $1
+		 ORDER BY created_at, id`,
+		donationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*model.DonationAuditEntry
+	for rows.Next() {
+		e := &model.DonationAuditEntry{}
+		var changes []byte
+		if err := rows.Scan(&e.ID, &e.DonationID, &e.ActorID, &e.Action, &changes, &e.Reason, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		_ = json.Unmarshal(changes, &e.Changes)
+		list = append(list, e)
+	}
+	return list, rows.Err()
+}
+
+// insertDonationAudit は監査ログを 1 件追加し、ID と作成日時を entry に設定する
+func insertDonationAudit(ctx context.Context, tx pgx.Tx, entry *model.DonationAuditEntry) error {
+	changes := entry.Changes
+	if changes == nil {
+		changes = map[string]model.DonationFieldChange{}
+	}
+	b, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	return tx.QueryRow(ctx,
+		`INSERT INTO donation_audit_logs (donation_id, actor_id, action, changes, reason)
+		 VALUES ($1, NULLIF($2,''), $3, $4, NULLIF($5,''))
+		 RETURNING id, created_at`,
+		entry.DonationID, entry.ActorID, entry.Action, b, entry.Reason,
+	).Scan(&entry.ID, &entry.CreatedAt)
+}
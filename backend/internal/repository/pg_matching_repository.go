@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type pgMatchingRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPgMatchingRepository returns a PostgreSQL-backed MatchingRepository.
+func NewPgMatchingRepository(pool *pgxpool.Pool) MatchingRepository {
+	return &pgMatchingRepository{pool: pool}
+}
+
+const matchingCampaignSelectCols = `id, project_id, sponsor_name, COALESCE(sponsor_email, ''), ratio_percent, cap_amount,
+	currency, starts_at, ends_at, matched_amount, summary_token, COALESCE(created_by, ''),
+	cancelled_at, closed_at, created_at, updated_at`
+
+func scanMatchingCampaign(scan func(...any) error) (*model.MatchingCampaign, error) {
+	c := &model.MatchingCampaign{}
+	return c, scan(
+		&c.ID, &c.ProjectID, &c.SponsorName, &c.SponsorEmail, &c.RatioPercent, &c.CapAmount,
+		&c.Currency, &c.StartsAt, &c.EndsAt, &c.MatchedAmount, &c.SummaryToken, &c.CreatedBy,
+		&c.CancelledAt, &c.ClosedAt, &c.CreatedAt, &c.UpdatedAt,
+	)
+}
+
+func (r *pgMatchingRepository) Create(ctx context.Context, c *model.MatchingCampaign) error {
+	return r.pool.QueryRow(ctx,
+		`INSERT INTO matching_campaigns
+		 (project_id, sponsor_name, sponsor_email, ratio_percent, cap_amount, currency, starts_at, ends_at, summary_token, created_by)
+		 VALUES ($1, $2, NULLIF($3,''), $4, $5, $6, $7, $8, $9, NULLIF($10,''))
+		 RETURNING id, created_at, updated_at`,
+		c.ProjectID, c.SponsorName, c.SponsorEmail, c.RatioPercent, c.CapAmount, c.Currency,
+		c.StartsAt, c.EndsAt, c.SummaryToken, c.CreatedBy,
+	).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+}
+
+func (r *pgMatchingRepository) GetByID(ctx context.Context, id string) (*model.MatchingCampaign, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+matchingCampaignSelectCols+` FROM matching_campaigns WHERE id = $1`, id)
+	c, err := scanMatchingCampaign(row.Scan)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return c, err
+}
+
+func (r *pgMatchingRepository) ListByProject(ctx context.Context, projectID string) ([]*model.MatchingCampaign, error) {
+	return r.listCampaigns(ctx,
+		`SELECT `+matchingCampaignSelectCols+` FROM matching_campaigns
+		 WHERE project_id = $1
+		 ORDER BY starts_at DESC, created_at DESC`,
+		projectID)
+}
+
+func (r *pgMatchingRepository) ListActiveByProject(ctx context.Context, projectID string, t time.Time) ([]*model.MatchingCampaign, error) {
+	return r.listCampaigns(ctx,
+		`SELECT `+matchingCampaignSelectCols+` FROM matching_campaigns
+		 WHERE project_id = $1 AND cancelled_at IS NULL AND starts_at <= $2 AND ends_at > $2
+		 ORDER BY starts_at, created_at`,
+		projectID, t)
+}
+
+func (r *pgMatchingRepository) listCampaigns(ctx context.Context, query string, args ...any) ([]*model.MatchingCampaign, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*model.MatchingCampaign
+	for rows.Next() {
+		c, err := scanMatchingCampaign(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, c)
+	}
+	return list, rows.Err()
+}
+
+func (r *pgMatchingRepository) Cancel(ctx context.Context, id string) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE matching_campaigns SET cancelled_at = NOW(), updated_at = NOW()
+		 WHERE id = $1 AND cancelled_at IS NULL AND closed_at IS NULL`,
+		id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *pgMatchingRepository) ApplyPayment(ctx context.Context, p *model.DonationPayment) ([]*model.MatchingContribution, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// 同時に届いた入金で上限を超えないよう、対象キャンペーンを行ロックしてから残額を計算する
+	rows, err := tx.Query(ctx,
+		`SELECT `+matchingCampaignSelectCols+` FROM matching_campaigns
+		 WHERE project_id = $1 AND currency = $2 AND cancelled_at IS NULL
+		   AND starts_at <= $3 AND ends_at > $3 AND matched_amount < cap_amount
+		 ORDER BY starts_at, created_at
+		 FOR UPDATE`,
+		p.ProjectID, p.Currency, p.PaidAt)
+	if err != nil {
+		return nil, err
+	}
+	var campaigns []*model.MatchingCampaign
+	for rows.Next() {
+		c, err := scanMatchingCampaign(rows.Scan)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		campaigns = append(campaigns, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var created []*model.MatchingContribution
+	for _, c := range campaigns {
+		matched := c.MatchFor(p.Amount)
+		if matched <= 0 {
+			continue
+		}
+		mc := &model.MatchingContribution{
+			CampaignID:     c.ID,
+			PaymentID:      p.ID,
+			DonationAmount: p.Amount,
+			MatchedAmount:  matched,
+			PaidAt:         p.PaidAt,
+		}
+		err := tx.QueryRow(ctx,
+			`INSERT INTO matching_contributions (campaign_id, payment_id, donation_amount, matched_amount, original_matched_amount, paid_at)
+			 VALUES ($1, $2, $3, $4, $4, $5)
+			 ON CONFLICT (campaign_id, payment_id) DO NOTHING
+			 RETURNING id, created_at`,
+			mc.CampaignID, mc.PaymentID, mc.DonationAmount, mc.MatchedAmount, mc.PaidAt,
+		).Scan(&mc.ID, &mc.CreatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue // この入金は上乗せ済み
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(ctx,
+			`UPDATE matching_campaigns SET matched_amount = matched_amount + $2, updated_at = NOW() WHERE id = $1`,
+			c.ID, matched); err != nil {
+			return nil, err
+		}
+		created = append(created, mc)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (r *pgMatchingRepository) ReleasePayment(ctx context.Context, p *model.DonationPayment) ([]*model.MatchingContribution, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx,
+		`SELECT id, campaign_id, COALESCE(payment_id, ''), donation_amount, matched_amount, original_matched_amount, paid_at, created_at
+		 FROM matching_contributions
+		 WHERE payment_id = $1
+		 ORDER BY id
+		 FOR UPDATE`,
+		p.ID)
+	if err != nil {
+		return nil, err
+	}
+	type contribution struct {
+		mc       *model.MatchingContribution
+		original int
+	}
+	var list []contribution
+	for rows.Next() {
+		c := contribution{mc: &model.MatchingContribution{}}
+		if err := rows.Scan(&c.mc.ID, &c.mc.CampaignID, &c.mc.PaymentID, &c.mc.DonationAmount, &c.mc.MatchedAmount, &c.original, &c.mc.PaidAt, &c.mc.CreatedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		list = append(list, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	retained := p.RetainedAmount()
+	var released []*model.MatchingContribution
+	for _, c := range list {
+		// 当初の上乗せを入金の残額に比例させる（減らすだけで、増やし直さない）
+		matched := 0
+		if p.Amount > 0 {
+			matched = c.original * retained / p.Amount
+		}
+		if matched >= c.mc.MatchedAmount {
+			continue
+		}
+		diff := c.mc.MatchedAmount - matched
+		c.mc.MatchedAmount = matched
+		c.mc.DonationAmount = min(c.mc.DonationAmount, retained)
+		if _, err := tx.Exec(ctx,
+			`UPDATE matching_contributions SET matched_amount = $2, donation_amount = $3 WHERE id = $1`,
+			c.mc.ID, c.mc.MatchedAmount, c.mc.DonationAmount); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(ctx,
+			`UPDATE matching_campaigns SET matched_amount = matched_amount - $2, updated_at = NOW() WHERE id = $1`,
+			c.mc.CampaignID, diff); err != nil {
+			return nil, err
+		}
+		released = append(released, c.mc)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return released, nil
+}
+
+func (r *pgMatchingRepository) ListContributions(ctx context.Context, campaignID string) ([]*model.MatchingContribution, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, campaign_id, COALESCE(payment_id, ''), donation_amount, matched_amount, paid_at, created_at
+		 FROM matching_contributions
+		 WHERE campaign_id = $1
+		 ORDER BY paid_at, id`,
+		campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*model.MatchingContribution
+	for rows.Next() {
+		mc := &model.MatchingContribution{}
+		if err := rows.Scan(&mc.ID, &mc.CampaignID, &mc.PaymentID, &mc.DonationAmount, &mc.MatchedAmount, &mc.PaidAt, &mc.CreatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, mc)
+	}
+	return list, rows.Err()
+}
+
+func (r *pgMatchingRepository) MonthlyMatchedByProject(ctx context.Context, projectID string) ([]*model.MonthlySum, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT TO_CHAR(DATE_TRUNC('month', mc.paid_at), 'YYYY-MM') AS month,
+		        SUM(mc.matched_amount)::int AS amount
+		 FROM matching_contributions mc
+		 JOIN matching_campaigns c ON c.id = mc.campaign_id
+		 WHERE c.project_id = $1
+		   AND mc.paid_at >= DATE_TRUNC('month', NOW()) - INTERVAL '11 months'
+		 GROUP BY DATE_TRUNC('month', mc.paid_at)
+		 ORDER BY month`,
+		projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sums []*model.MonthlySum
+	for rows.Next() {
+		s := &model.MonthlySum{}
+		if err := rows.Scan(&s.Month, &s.Amount); err != nil {
+			return nil, err
+		}
+		sums = append(sums, s)
+	}
+	return sums, rows.Err()
+}
+
+func (r *pgMatchingRepository) CloseEnded(ctx context.Context, now time.Time) ([]*model.MatchingCampaign, error) {
+	return r.listCampaigns(ctx,
+		`UPDATE matching_campaigns SET closed_at = NOW(), updated_at = NOW()
+		 WHERE closed_at IS NULL AND cancelled_at IS NULL AND ends_at <= $1
+		 RETURNING `+matchingCampaignSelectCols,
+		now)
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PgProjectCategoryRepository は ProjectCategoryRepository の PostgreSQL 実装
+type PgProjectCategoryRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPgProjectCategoryRepository は PgProjectCategoryRepository を生成する
+func NewPgProjectCategoryRepository(pool *pgxpool.Pool) *PgProjectCategoryRepository {
+	return &PgProjectCategoryRepository{pool: pool}
+}
+
+// List はカテゴリを表示順に返す
+func (r *PgProjectCategoryRepository) List(ctx context.Context) ([]*model.ProjectCategory, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT slug, name, sort_order, created_at, updated_at
+		 FROM project_categories ORDER BY sort_order, slug`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []*model.ProjectCategory
+	for rows.Next() {
+		var c model.ProjectCategory
+		if err := rows.Scan(&c.Slug, &c.Name, &c.SortOrder, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			return nil, err
+		}
+		categories = append(categories, &c)
+	}
+	return categories, rows.Err()
+}
+
+// Create はカテゴリを作成する
+func (r *PgProjectCategoryRepository) Create(ctx context.Context, c *model.ProjectCategory) error {
+	err := r.pool.QueryRow(ctx,
+		`INSERT INTO project_categories (slug, name, sort_order)
+		 VALUES ($1, $2, $3)
+		 RETURNING created_at, updated_at`,
+		c.Slug, c.Name, c.SortOrder,
+	).Scan(&c.CreatedAt, &c.UpdatedAt)
+	if err != nil && strings.Contains(err.Error(), "duplicate key") {
+		return ErrDuplicate
+	}
+	return err
+}
+
+// Update は表示名と表示順を更新する
+func (r *PgProjectCategoryRepository) Update(ctx context.Context, c *model.ProjectCategory) error {
+	err := r.pool.QueryRow(ctx,
+		`UPDATE project_categories SET name = $2, sort_order = $3, updated_at = NOW()
+		 WHERE slug = $1
+		 RETURNING created_at, updated_at`,
+		c.Slug, c.Name, c.SortOrder,
+	).Scan(&c.CreatedAt, &c.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// Delete はカテゴリを削除する
+func (r *PgProjectCategoryRepository) Delete(ctx context.Context, slug string) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM project_categories WHERE slug = $1`, slug)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PgProjectMemberRepository は ProjectMemberRepository の PostgreSQL 実装
+type PgProjectMemberRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPgProjectMemberRepository は PgProjectMemberRepository を生成する
+func NewPgProjectMemberRepository(pool *pgxpool.Pool) *PgProjectMemberRepository {
+	return &PgProjectMemberRepository{pool: pool}
+}
+
+// listProjectMembers はメンバーをユーザー名付きで返す（GetByID と共用）
+func listProjectMembers(ctx context.Context, pool *pgxpool.Pool, projectID string) ([]*model.ProjectMember, error) {
+	rows, err := pool.Query(ctx,
+		`SELECT m.project_id, m.user_id, COALESCE(u.name, ''), m.role, m.created_at
+		 FROM project_members m
+		 JOIN users u ON u.id = m.user_id
+		 WHERE m.project_id = $1
+		 ORDER BY CASE m.role WHEN 'owner' THEN 0 WHEN 'maintainer' THEN 1 ELSE 2 END, m.created_at, m.user_id`,
+		projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []*model.ProjectMember
+	for rows.Next() {
+		var m model.ProjectMember
+		if err := rows.Scan(&m.ProjectID, &m.UserID, &m.UserName, &m.Role, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, &m)
+	}
+	return members, rows.Err()
+}
+
+// ListMembers はメンバーを役割の強い順・参加順に返す
+func (r *PgProjectMemberRepository) ListMembers(ctx context.Context, projectID string) ([]*model.ProjectMember, error) {
+	return listProjectMembers(ctx, r.pool, projectID)
+}
+
+// UpdateMemberRole はメンバーの役割を変更する
+func (r *PgProjectMemberRepository) UpdateMemberRole(ctx context.Context, projectID, userID, role string) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE project_members SET role = $3, updated_at = NOW() WHERE project_id = $1 AND user_id = $2`,
+		projectID, userID, role)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RemoveMember はメンバーを外す
+func (r *PgProjectMemberRepository) RemoveMember(ctx context.Context, projectID, userID string) error {
+	tag, err := r.pool.Exec(ctx,
+		`DELETE FROM project_members WHERE project_id = $1 AND user_id = $2`, projectID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+const projectInvitationCols = `i.id, i.project_id, p.name, COALESCE(i.email, ''), COALESCE(i.user_id, ''), i.role,
+	COALESCE(i.invited_by, ''), i.status, i.created_at, i.responded_at`
+
+func scanProjectInvitation(row pgx.Row) (*model.ProjectInvitation, error) {
+	var inv model.ProjectInvitation
+	if err := row.Scan(&inv.ID, &inv.ProjectID, &inv.ProjectName, &inv.Email, &inv.UserID, &inv.Role,
+		&inv.InvitedBy, &inv.Status, &inv.CreatedAt, &inv.RespondedAt); err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+func (r *PgProjectMemberRepository) queryInvitations(ctx context.Context, sql string, args ...any) ([]*model.ProjectInvitation, error) {
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invitations []*model.ProjectInvitation
+	for rows.Next() {
+		inv, err := scanProjectInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, inv)
+	}
+	return invitations, rows.Err()
+}
+
+// CreateInvitation は招待を作成する
+func (r *PgProjectMemberRepository) CreateInvitation(ctx context.Context, inv *model.ProjectInvitation) error {
+	err := r.pool.QueryRow(ctx,
+		`WITH i AS (
+		   INSERT INTO project_invitations (project_id, email, user_id, role, invited_by)
+		   VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4, NULLIF($5, ''))
+		   RETURNING id, project_id, status, created_at
+		 )
+		 SELECT i.id, p.name, i.status, i.created_at FROM i JOIN projects p ON p.id = i.project_id`,
+		inv.ProjectID, inv.Email, inv.UserID, inv.Role, inv.InvitedBy,
+	).Scan(&inv.ID, &inv.ProjectName, &inv.Status, &inv.CreatedAt)
+	if err != nil && strings.Contains(err.Error(), "duplicate key") {
+		return ErrDuplicate
+	}
+	return err
+}
+
+// GetInvitation は ID で招待を取得する
+func (r *PgProjectMemberRepository) GetInvitation(ctx context.Context, id string) (*model.ProjectInvitation, error) {
+	inv, err := scanProjectInvitation(r.pool.QueryRow(ctx,
+		`SELECT `+projectInvitationCols+`
+		 FROM project_invitations i JOIN projects p ON p.id = i.project_id
+		 WHERE i.id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return inv, err
+}
+
+// ListInvitationsByProject はプロジェクトの保留中の招待を新しい順に返す
+func (r *PgProjectMemberRepository) ListInvitationsByProject(ctx context.Context, projectID string) ([]*model.ProjectInvitation, error) {
+	return r.queryInvitations(ctx,
+		`SELECT `+projectInvitationCols+`
+		 FROM project_invitations i JOIN projects p ON p.id = i.project_id
+		 WHERE i.project_id = $1 AND i.status = 'pending'
+		 ORDER BY i.created_at DESC, i.id`, projectID)
+}
+
+// ListInvitationsForUser はユーザー宛ての保留中の招待を新しい順に返す（削除済みのプロジェクトは除く）
+func (r *PgProjectMemberRepository) ListInvitationsForUser(ctx context.Context, userID, email string) ([]*model.ProjectInvitation, error) {
+	return r.queryInvitations(ctx,
+		`SELECT `+projectInvitationCols+`
+		 FROM project_invitations i JOIN projects p ON p.id = i.project_id
+		 WHERE i.status = 'pending' AND p.status != 'deleted'
+		   AND (i.user_id = $1 OR ($2 <> '' AND lower(i.email) = lower($2)))
+		 ORDER BY i.created_at DESC, i.id`, userID, email)
+}
+
+// SetInvitationStatus は保留中の招待を辞退・取り消しにする
+func (r *PgProjectMemberRepository) SetInvitationStatus(ctx context.Context, id, status string) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE project_invitations SET status = $2, responded_at = NOW() WHERE id = $1 AND status = 'pending'`,
+		id, status)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// AcceptInvitation は招待を承諾済みにしてメンバーに加える。作成者の役割は変更しない
+func (r *PgProjectMemberRepository) AcceptInvitation(ctx context.Context, id, userID string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var projectID, role string
+	err = tx.QueryRow(ctx,
+		`UPDATE project_invitations SET status = 'accepted', responded_at = NOW()
+		 WHERE id = $1 AND status = 'pending'
+		 RETURNING project_id, role`, id,
+	).Scan(&projectID, &role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO project_members (project_id, user_id, role)
+		 SELECT $1, $2, $3 FROM projects WHERE id = $1 AND owner_id <> $2
+		 ON CONFLICT (project_id, user_id) DO UPDATE SET role = EXCLUDED.role, updated_at = NOW()`,
+		projectID, userID, role,
+	); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
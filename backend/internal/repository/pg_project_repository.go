@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/givers/backend/internal/model"
 	"github.com/jackc/pgx/v5"
@@ -20,15 +22,15 @@ func NewPgProjectRepository(pool *pgxpool.Pool) *PgProjectRepository {
 	return &PgProjectRepository{pool: pool}
 }
 
-const projectSelectCols = `p.id, p.owner_id, p.name, p.description, p.overview, p.share_message, p.deadline, p.status, p.owner_want_monthly, p.monthly_target, COALESCE(p.stripe_account_id, ''), p.cost_items, p.image_url, p.created_at, p.updated_at, COALESCE((SELECT SUM(amount) FROM donations WHERE project_id = p.id AND created_at >= DATE_TRUNC('month', NOW())), 0)::int`
+const projectSelectCols = `p.id, p.owner_id, p.name, p.description, p.overview, p.share_message, p.deadline, p.status, p.owner_want_monthly, p.monthly_target, COALESCE(p.stripe_account_id, ''), p.cost_items, p.donation_tiers, p.payment_method_types, p.image_url, COALESCE(p.category, ''), p.tags, COALESCE(p.slug, ''), p.created_at, p.updated_at, COALESCE((SELECT SUM(` + donationMonthlyNetAmountExpr + `) FROM donation_payments WHERE project_id = p.id AND ` + donationCoversCurrentMonthCond + `), 0)::int`
 
 func scanProject(row pgx.Row) (*model.Project, error) {
 	var p model.Project
-	var costItemsJSON []byte
+	var costItemsJSON, tiersJSON []byte
 	if err := row.Scan(
 		&p.ID, &p.OwnerID, &p.Name, &p.Description, &p.Overview, &p.ShareMessage,
 		&p.Deadline, &p.Status, &p.OwnerWantMonthly, &p.MonthlyTarget,
-		&p.StripeAccountID, &costItemsJSON, &p.ImageURL, &p.CreatedAt, &p.UpdatedAt,
+		&p.StripeAccountID, &costItemsJSON, &tiersJSON, &p.PaymentMethodTypes, &p.ImageURL, &p.Category, &p.Tags, &p.Slug, &p.CreatedAt, &p.UpdatedAt,
 		&p.CurrentMonthlyDonations,
 	); err != nil {
 		return nil, err
@@ -36,6 +38,7 @@ func scanProject(row pgx.Row) (*model.Project, error) {
 	if len(costItemsJSON) > 0 {
 		_ = json.Unmarshal(costItemsJSON, &p.CostItems)
 	}
+	_ = json.Unmarshal(tiersJSON, &p.DonationTiers)
 	return &p, nil
 }
 
@@ -43,11 +46,11 @@ func scanProjects(rows pgx.Rows) ([]*model.Project, error) {
 	var projects []*model.Project
 	for rows.Next() {
 		var p model.Project
-		var costItemsJSON []byte
+		var costItemsJSON, tiersJSON []byte
 		if err := rows.Scan(
 			&p.ID, &p.OwnerID, &p.Name, &p.Description, &p.Overview, &p.ShareMessage,
 			&p.Deadline, &p.Status, &p.OwnerWantMonthly, &p.MonthlyTarget,
-			&p.StripeAccountID, &costItemsJSON, &p.ImageURL, &p.CreatedAt, &p.UpdatedAt,
+			&p.StripeAccountID, &costItemsJSON, &tiersJSON, &p.PaymentMethodTypes, &p.ImageURL, &p.Category, &p.Tags, &p.Slug, &p.CreatedAt, &p.UpdatedAt,
 			&p.CurrentMonthlyDonations,
 		); err != nil {
 			return nil, err
@@ -55,68 +58,60 @@ func scanProjects(rows pgx.Rows) ([]*model.Project, error) {
 		if len(costItemsJSON) > 0 {
 			_ = json.Unmarshal(costItemsJSON, &p.CostItems)
 		}
+		_ = json.Unmarshal(tiersJSON, &p.DonationTiers)
 		projects = append(projects, &p)
 	}
 	return projects, rows.Err()
 }
 
+// projectHotJoin / projectHotOrder は "hot"（当月の達成率降順）の並び順
+const (
+	projectHotJoin = `LEFT JOIN LATERAL (
+	   SELECT COALESCE(SUM(` + donationMonthlyNetAmountExpr + `), 0) AS total
+	   FROM donation_payments
+	   WHERE project_id = p.id
+	     AND ` + donationCoversCurrentMonthCond + `
+	 ) d ON true`
+	projectHotOrder = `CASE WHEN p.monthly_target > 0 THEN d.total::float / p.monthly_target ELSE 0 END DESC, p.created_at DESC`
+)
+
+// projectFilterConds は公開中のプロジェクトを filter で絞り込む条件を返し、値を args に追加する
+func projectFilterConds(filter model.ProjectFilter, args *[]any) []string {
+	conds := []string{`p.status = 'active'`}
+	if filter.Category != "" {
+		*args = append(*args, filter.Category)
+		conds = append(conds, fmt.Sprintf("p.category = $%d", len(*args)))
+	}
+	if len(filter.Tags) > 0 {
+		*args = append(*args, filter.Tags)
+		conds = append(conds, fmt.Sprintf("p.tags @> $%d", len(*args)))
+	}
+	return conds
+}
+
 // List はプロジェクト一覧を取得する。sort は "new"（デフォルト）または "hot"（達成率降順）。
-// cursor はカーソルベースページネーション用（前回最後のプロジェクト ID）。
-func (r *PgProjectRepository) List(ctx context.Context, sort string, limit int, cursor string) (*model.ProjectListResult, error) {
+// filter でカテゴリ・タグを絞り込む。cursor はカーソルベースページネーション用（前回最後のプロジェクト ID）。
+func (r *PgProjectRepository) List(ctx context.Context, sort string, filter model.ProjectFilter, limit int, cursor string) (*model.ProjectListResult, error) {
 	// limit+1 をフェッチして next_cursor の有無を判定
-	fetchLimit := limit + 1
-	var rows pgx.Rows
-	var err error
-
-	switch sort {
-	case "hot":
-		if cursor == "" {
-			rows, err = r.pool.Query(ctx,
-				`SELECT `+projectSelectCols+`
-				 FROM projects p
-				 LEFT JOIN LATERAL (
-				   SELECT COALESCE(SUM(amount), 0) AS total
-				   FROM donations
-				   WHERE project_id = p.id
-				     AND created_at >= date_trunc('month', NOW())
-				 ) d ON true
-				 WHERE p.status = 'active'
-				 ORDER BY CASE WHEN p.monthly_target > 0 THEN d.total::float / p.monthly_target ELSE 0 END DESC,
-				          p.created_at DESC
-				 LIMIT $1`, fetchLimit)
-		} else {
-			// hot ソートでのカーソル: cursor ID より後のものを取得
-			// 達成率は変動するため、cursor の位置を created_at で近似する
-			rows, err = r.pool.Query(ctx,
-				`SELECT `+projectSelectCols+`
-				 FROM projects p
-				 LEFT JOIN LATERAL (
-				   SELECT COALESCE(SUM(amount), 0) AS total
-				   FROM donations
-				   WHERE project_id = p.id
-				     AND created_at >= date_trunc('month', NOW())
-				 ) d ON true
-				 WHERE p.status = 'active'
-				   AND (p.created_at, p.id) < ((SELECT created_at FROM projects WHERE id = $2), $2)
-				 ORDER BY CASE WHEN p.monthly_target > 0 THEN d.total::float / p.monthly_target ELSE 0 END DESC,
-				          p.created_at DESC
-				 LIMIT $1`, fetchLimit, cursor)
-		}
-	default:
-		if cursor == "" {
-			rows, err = r.pool.Query(ctx,
-				`SELECT `+projectSelectCols+`
-				 FROM projects p WHERE p.status = 'active' ORDER BY p.created_at DESC, p.id DESC LIMIT $1`, fetchLimit)
-		} else {
-			rows, err = r.pool.Query(ctx,
-				`SELECT `+projectSelectCols+`
-				 FROM projects p
-				 WHERE p.status = 'active'
-				   AND (p.created_at, p.id) < ((SELECT created_at FROM projects WHERE id = $2), $2)
-				 ORDER BY p.created_at DESC, p.id DESC
-				 LIMIT $1`, fetchLimit, cursor)
-		}
+	args := []any{limit + 1}
+	conds := projectFilterConds(filter, &args)
+	if cursor != "" {
+		// hot ソートでは達成率が変動するため、cursor の位置を created_at で近似する
+		args = append(args, cursor)
+		conds = append(conds, fmt.Sprintf("(p.created_at, p.id) < ((SELECT created_at FROM projects WHERE id = $%d), $%d)", len(args), len(args)))
 	}
+	join, order := "", `p.created_at DESC, p.id DESC`
+	if sort == "hot" {
+		join, order = projectHotJoin, projectHotOrder
+	}
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+projectSelectCols+`
+		 FROM projects p
+		 `+join+`
+		 WHERE `+strings.Join(conds, " AND ")+`
+		 ORDER BY `+order+`
+		 LIMIT $1`, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -137,6 +132,131 @@ func (r *PgProjectRepository) List(ctx context.Context, sort string, limit int,
 	return result, nil
 }
 
+// maxTagFacets はファセットで返すタグの数（件数の多い順）
+const maxTagFacets = 50
+
+// Facets は公開中のプロジェクトのカテゴリ別・タグ別の件数を返す。
+// カテゴリは全件を表示順に（0 件を含む）、タグの絞り込みだけを適用して数える。タグは filter 全体を適用する。
+func (r *PgProjectRepository) Facets(ctx context.Context, filter model.ProjectFilter) (*model.ProjectFacets, error) {
+	tags := filter.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	facets := &model.ProjectFacets{Categories: []*model.ProjectFacetCount{}, Tags: []*model.ProjectFacetCount{}}
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT c.slug, c.name, COUNT(p.id)
+		 FROM project_categories c
+		 LEFT JOIN projects p ON p.category = c.slug AND p.status = 'active' AND p.tags @> $1
+		 GROUP BY c.slug, c.name, c.sort_order
+		 ORDER BY c.sort_order, c.slug`, tags)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var f model.ProjectFacetCount
+		if err := rows.Scan(&f.Value, &f.Name, &f.Count); err != nil {
+			return nil, err
+		}
+		facets.Categories = append(facets.Categories, &f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	args := []any{maxTagFacets}
+	conds := projectFilterConds(filter, &args)
+	rows, err = r.pool.Query(ctx,
+		`SELECT t.tag, COUNT(*)
+		 FROM projects p, unnest(p.tags) AS t(tag)
+		 WHERE `+strings.Join(conds, " AND ")+`
+		 GROUP BY t.tag
+		 ORDER BY COUNT(*) DESC, t.tag
+		 LIMIT $1`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var f model.ProjectFacetCount
+		if err := rows.Scan(&f.Value, &f.Count); err != nil {
+			return nil, err
+		}
+		facets.Tags = append(facets.Tags, &f)
+	}
+	return facets, rows.Err()
+}
+
+// Search は公開中のプロジェクトを名前・概要・説明で全文検索する（search_vector と search_query は 043 のマイグレーションで定義）。
+// Sort が "new" / "hot" の場合は List と同じ並び順とカーソル、"relevance"（既定）は関連度順で、カーソルの位置も関連度で比べる。
+func (r *PgProjectRepository) Search(ctx context.Context, q model.ProjectSearchQuery) (*model.ProjectSearchResult, error) {
+	var join, after, order string
+	switch q.Sort {
+	case model.ProjectSearchSortHot:
+		join = projectHotJoin
+		// 達成率は変動するため、List と同じく cursor の位置を created_at で近似する
+		after = `(p.created_at, p.id) < ((SELECT created_at FROM projects WHERE id = $3), $3)`
+		order = projectHotOrder
+	case model.ProjectSearchSortNew:
+		after = `(p.created_at, p.id) < ((SELECT created_at FROM projects WHERE id = $3), $3)`
+		order = `p.created_at DESC, p.id DESC`
+	default:
+		after = `(m.rank, p.id) < (SELECT rank, id FROM matches WHERE id = $3)`
+		order = `m.rank DESC, p.id DESC`
+	}
+
+	// limit+1 をフェッチして next_cursor の有無を判定
+	rows, err := r.pool.Query(ctx,
+		`WITH q AS (SELECT search_query($1) AS query),
+		 matches AS (
+		   SELECT p.id, ts_rank(p.search_vector, q.query) AS rank
+		   FROM projects p, q
+		   WHERE p.status = 'active' AND p.search_vector @@ q.query
+		 )
+		 SELECT `+projectSelectCols+`, m.rank::float8
+		 FROM projects p
+		 JOIN matches m ON m.id = p.id
+		 `+join+`
+		 WHERE ($3 = '' OR `+after+`)
+		 ORDER BY `+order+`
+		 LIMIT $2`, q.Text, q.Limit+1, q.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []*model.ProjectSearchHit
+	for rows.Next() {
+		var p model.Project
+		var costItemsJSON, tiersJSON []byte
+		hit := &model.ProjectSearchHit{Project: &p}
+		if err := rows.Scan(
+			&p.ID, &p.OwnerID, &p.Name, &p.Description, &p.Overview, &p.ShareMessage,
+			&p.Deadline, &p.Status, &p.OwnerWantMonthly, &p.MonthlyTarget,
+			&p.StripeAccountID, &costItemsJSON, &tiersJSON, &p.PaymentMethodTypes, &p.ImageURL, &p.Category, &p.Tags, &p.Slug, &p.CreatedAt, &p.UpdatedAt,
+			&p.CurrentMonthlyDonations, &hit.Rank,
+		); err != nil {
+			return nil, err
+		}
+		if len(costItemsJSON) > 0 {
+			_ = json.Unmarshal(costItemsJSON, &p.CostItems)
+		}
+		_ = json.Unmarshal(tiersJSON, &p.DonationTiers)
+		hits = append(hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &model.ProjectSearchResult{Projects: hits}
+	if len(hits) > q.Limit {
+		result.NextCursor = hits[q.Limit-1].ID
+		result.Projects = hits[:q.Limit]
+	}
+	return result, nil
+}
+
 // GetByID は ID でプロジェクトを取得する（コスト項目・アラートも含む）
 func (r *PgProjectRepository) GetByID(ctx context.Context, id string) (*model.Project, error) {
 	p, err := scanProject(r.pool.QueryRow(ctx,
@@ -158,21 +278,65 @@ func (r *PgProjectRepository) GetByID(ctx context.Context, id string) (*model.Pr
 		return nil, err
 	}
 
+	members, err := listProjectMembers(ctx, r.pool, id)
+	if err != nil {
+		return nil, err
+	}
+	p.Members = members
 	return p, nil
 }
 
-// ListByOwnerID はオーナーIDでプロジェクト一覧を取得する
+// ListByOwnerID は作成したプロジェクトとメンバーになっているプロジェクトの一覧を取得する
 func (r *PgProjectRepository) ListByOwnerID(ctx context.Context, ownerID string) ([]*model.Project, error) {
 	rows, err := r.pool.Query(ctx,
 		`SELECT `+projectSelectCols+`
-		 FROM projects p WHERE p.owner_id = $1 ORDER BY p.created_at DESC`,
+		 FROM projects p
+		 WHERE p.owner_id = $1
+		    OR EXISTS (SELECT 1 FROM project_members m WHERE m.project_id = p.id AND m.user_id = $1)
+		 ORDER BY p.created_at DESC`,
 		ownerID,
 	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	return scanProjects(rows)
+	projects, err := scanProjects(rows)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.attachStripeAccountStatus(ctx, ownerID, projects); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// attachStripeAccountStatus は同期済みの連結アカウントの状態を ownerID が作成した projects に設定する（オーナー向け）
+func (r *PgProjectRepository) attachStripeAccountStatus(ctx context.Context, ownerID string, projects []*model.Project) error {
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, stripe_charges_enabled, COALESCE(stripe_payouts_enabled, false), stripe_requirements, stripe_disabled_reason, stripe_status_updated_at
+		 FROM projects WHERE owner_id = $1 AND stripe_charges_enabled IS NOT NULL`,
+		ownerID,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	byID := make(map[string]*model.Project, len(projects))
+	for _, p := range projects {
+		byID[p.ID] = p
+	}
+	for rows.Next() {
+		var id string
+		var st model.StripeAccountStatus
+		if err := rows.Scan(&id, &st.ChargesEnabled, &st.PayoutsEnabled, &st.Requirements, &st.DisabledReason, &st.UpdatedAt); err != nil {
+			return err
+		}
+		if p, ok := byID[id]; ok {
+			p.StripeAccount = &st
+		}
+	}
+	return rows.Err()
 }
 
 func marshalCostItems(items []model.CostItem) []byte {
@@ -183,19 +347,117 @@ func marshalCostItems(items []model.CostItem) []byte {
 	return b
 }
 
+// marshalDonationTiers は目安額を JSONB 用に変換する（未設定は空配列）
+func marshalDonationTiers(tiers []model.DonationTier) []byte {
+	if len(tiers) == 0 {
+		return []byte("[]")
+	}
+	b, _ := json.Marshal(tiers)
+	return b
+}
+
+// paymentMethodTypes は支払い方法を TEXT[] 用に変換する（未設定は空配列）
+func paymentMethodTypes(types []string) []string {
+	if types == nil {
+		return []string{}
+	}
+	return types
+}
+
+// projectTags はタグを TEXT[] 用に変換する（未設定は空配列）
+func projectTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+// projectWriteError は存在しないカテゴリを指定したときの外部キー違反を ErrUnknownCategory に、
+// スラッグの重複を ErrSlugTaken に変換する
+func projectWriteError(err error) error {
+	switch {
+	case strings.Contains(err.Error(), "projects_category_fkey"):
+		return ErrUnknownCategory
+	case strings.Contains(err.Error(), "idx_projects_slug"):
+		return ErrSlugTaken
+	}
+	return err
+}
+
+// slugQuerier は pool と tx のどちらでもスラッグの確認をできるようにする
+type slugQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// checkSlugAvailable はスラッグが他のプロジェクトの現在または変更前のスラッグでないことを確認する。
+// projectID のプロジェクト自身の変更前のスラッグは使える
+func checkSlugAvailable(ctx context.Context, q slugQuerier, slug, projectID string) error {
+	if slug == "" {
+		return nil
+	}
+	var taken bool
+	if err := q.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM projects WHERE slug = $1 AND id::text <> $2)
+		     OR EXISTS (SELECT 1 FROM project_slug_redirects WHERE slug = $1 AND project_id::text <> $2)`,
+		slug, projectID,
+	).Scan(&taken); err != nil {
+		return err
+	}
+	if taken {
+		return ErrSlugTaken
+	}
+	return nil
+}
+
+// ResolveSlug はスラッグ（変更前のものを含む）からプロジェクト ID と現在のスラッグを返す。
+// 見つからない場合は ErrNotFound を返す
+func (r *PgProjectRepository) ResolveSlug(ctx context.Context, slug string) (projectID, currentSlug string, err error) {
+	err = r.pool.QueryRow(ctx,
+		`SELECT id, COALESCE(slug, '') FROM projects WHERE slug = $1
+		 UNION ALL
+		 SELECT p.id, COALESCE(p.slug, '') FROM project_slug_redirects s JOIN projects p ON p.id = s.project_id WHERE s.slug = $1
+		 LIMIT 1`,
+		slug,
+	).Scan(&projectID, &currentSlug)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", "", ErrNotFound
+	}
+	return projectID, currentSlug, err
+}
+
 // Create はプロジェクトを作成する
 func (r *PgProjectRepository) Create(ctx context.Context, project *model.Project) error {
 	project.MonthlyTarget = model.TotalMonthly(project.CostItems)
 
-	err := r.pool.QueryRow(ctx,
-		`INSERT INTO projects (owner_id, name, description, overview, share_message, deadline, status, owner_want_monthly, monthly_target, cost_items, image_url)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-		 RETURNING id, created_at, updated_at`,
+	if err := checkSlugAvailable(ctx, r.pool, project.Slug, ""); err != nil {
+		return err
+	}
+
+	snapshot, err := json.Marshal(model.SnapshotOf(project))
+	if err != nil {
+		return err
+	}
+
+	// 作成者は owner としてメンバーにも登録し、作成時の内容を更新履歴（create）に残す（作成時の予算に戻せるように）
+	err = r.pool.QueryRow(ctx,
+		`WITH p AS (
+		   INSERT INTO projects (owner_id, name, description, overview, share_message, deadline, status, owner_want_monthly, monthly_target, cost_items, image_url, donation_tiers, payment_method_types, category, tags, slug)
+		   VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NULLIF($14, ''), $15, NULLIF($16, ''))
+		   RETURNING id, owner_id, created_at, updated_at
+		 ), m AS (
+		   INSERT INTO project_members (project_id, user_id, role) SELECT id, owner_id, 'owner' FROM p
+		 ), v AS (
+		   INSERT INTO project_revisions (project_id, actor_id, action, snapshot, created_at)
+		   SELECT id, owner_id, 'create', $17, created_at FROM p
+		 )
+		 SELECT id, created_at, updated_at FROM p`,
 		project.OwnerID, project.Name, project.Description, project.Overview, project.ShareMessage, project.Deadline,
 		project.Status, project.OwnerWantMonthly, project.MonthlyTarget, marshalCostItems(project.CostItems), project.ImageURL,
+		marshalDonationTiers(project.DonationTiers), paymentMethodTypes(project.PaymentMethodTypes), project.Category, projectTags(project.Tags),
+		project.Slug, snapshot,
 	).Scan(&project.ID, &project.CreatedAt, &project.UpdatedAt)
 	if err != nil {
-		return err
+		return projectWriteError(err)
 	}
 
 	if project.Alerts != nil {
@@ -209,14 +471,64 @@ func (r *PgProjectRepository) Create(ctx context.Context, project *model.Project
 
 // Update はプロジェクトを更新する
 func (r *PgProjectRepository) Update(ctx context.Context, project *model.Project) error {
+	return r.UpdateWithRevision(ctx, project, nil)
+}
+
+// UpdateWithRevision は Update と同じくプロジェクトを更新し、rev が nil でなければ同じトランザクションで
+// 更新履歴を記録する（rev の ID・作成日時を設定する）
+func (r *PgProjectRepository) UpdateWithRevision(ctx context.Context, project *model.Project, rev *model.ProjectRevision) error {
 	project.MonthlyTarget = model.TotalMonthly(project.CostItems)
 
-	if _, err := r.pool.Exec(ctx,
-		`UPDATE projects SET name=$1, description=$2, overview=$3, share_message=$4, deadline=$5, status=$6, owner_want_monthly=$7, monthly_target=$8, cost_items=$9, image_url=$10, updated_at=NOW()
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// スラッグを変えた場合は変更前のスラッグをリダイレクトとして残す（自分の古いスラッグに戻すのは可）
+	var oldSlug string
+	if err := tx.QueryRow(ctx, `SELECT COALESCE(slug, '') FROM projects WHERE id = $1 FOR UPDATE`, project.ID).Scan(&oldSlug); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+	if project.Slug != oldSlug {
+		if err := checkSlugAvailable(ctx, tx, project.Slug, project.ID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM project_slug_redirects WHERE slug = $1`, project.Slug); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(ctx,
+		`UPDATE projects SET name=$1, description=$2, overview=$3, share_message=$4, deadline=$5, status=$6, owner_want_monthly=$7, monthly_target=$8, cost_items=$9, image_url=$10,
+		 donation_tiers=$12, payment_method_types=$13, category=NULLIF($14, ''), tags=$15, slug=NULLIF($16, ''), frozen_by_stripe=(frozen_by_stripe AND status=$6), updated_at=NOW()
 		 WHERE id=$11`,
 		project.Name, project.Description, project.Overview, project.ShareMessage, project.Deadline, project.Status,
 		project.OwnerWantMonthly, project.MonthlyTarget, marshalCostItems(project.CostItems), project.ImageURL, project.ID,
+		marshalDonationTiers(project.DonationTiers), paymentMethodTypes(project.PaymentMethodTypes), project.Category, projectTags(project.Tags),
+		project.Slug,
 	); err != nil {
+		return projectWriteError(err)
+	}
+
+	if oldSlug != "" && oldSlug != project.Slug {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO project_slug_redirects (slug, project_id) VALUES ($1, $2)
+			 ON CONFLICT (slug) DO UPDATE SET project_id = EXCLUDED.project_id, created_at = NOW()`,
+			oldSlug, project.ID); err != nil {
+			return err
+		}
+	}
+	if rev != nil {
+		rev.ProjectID = project.ID
+		if err := insertProjectRevision(ctx, tx, rev); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
 		return err
 	}
 
@@ -257,6 +569,110 @@ func (r *PgProjectRepository) GetStripeAccountID(ctx context.Context, projectID
 	return id, nil
 }
 
+// ListStripeAccountIDs は stripe_account_id を持つプロジェクトの projectID → accountID を返す（照合・財務同期コマンドで使用）
+func (r *PgProjectRepository) ListStripeAccountIDs(ctx context.Context) (map[string]string, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, stripe_account_id FROM projects WHERE stripe_account_id IS NOT NULL AND stripe_account_id != ''`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := map[string]string{}
+	for rows.Next() {
+		var projectID, accountID string
+		if err := rows.Scan(&projectID, &accountID); err != nil {
+			return nil, err
+		}
+		ids[projectID] = accountID
+	}
+	return ids, rows.Err()
+}
+
+// SyncStripeAccountStatus は連結アカウントの状態を accountID に紐づくプロジェクトに保存する。
+// 決済・入金のどちらかが無効になった active プロジェクトは frozen にし（frozen_by_stripe）、
+// 両方が有効に戻ったら自動凍結したプロジェクトだけを active に戻す。
+// status が変わったプロジェクトの projectID → 新しい status を返す。
+func (r *PgProjectRepository) SyncStripeAccountStatus(ctx context.Context, accountID string, st model.StripeAccountStatus) (map[string]string, error) {
+	requirements := st.Requirements
+	if requirements == nil {
+		requirements = []string{}
+	}
+	rows, err := r.pool.Query(ctx,
+		`WITH prev AS (
+			SELECT id, status FROM projects WHERE stripe_account_id = $1 FOR UPDATE
+		)
+		UPDATE projects p SET
+			stripe_charges_enabled = $2, stripe_payouts_enabled = $3,
+			stripe_requirements = $4, stripe_disabled_reason = $5, stripe_status_updated_at = NOW(),
+			status = CASE
+				WHEN NOT $6 AND p.status = 'active' THEN 'frozen'
+				WHEN $6 AND p.status = 'frozen' AND p.frozen_by_stripe THEN 'active'
+				ELSE p.status END,
+			frozen_by_stripe = CASE
+				WHEN NOT $6 AND p.status = 'active' THEN true
+				WHEN $6 THEN false
+				ELSE p.frozen_by_stripe END,
+			updated_at = NOW()
+		FROM prev WHERE p.id = prev.id
+		RETURNING p.id, prev.status, p.status`,
+		accountID, st.ChargesEnabled, st.PayoutsEnabled, requirements, st.DisabledReason, st.Capable(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changed := map[string]string{}
+	for rows.Next() {
+		var id, before, after string
+		if err := rows.Scan(&id, &before, &after); err != nil {
+			return nil, err
+		}
+		if before != after {
+			changed[id] = after
+		}
+	}
+	return changed, rows.Err()
+}
+
+// GetStatus はプロジェクトの status を返す（寄付受付の可否判定に使用）
+func (r *PgProjectRepository) GetStatus(ctx context.Context, projectID string) (string, error) {
+	var status string
+	err := r.pool.QueryRow(ctx, `SELECT status FROM projects WHERE id=$1`, projectID).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrNotFound
+	}
+	return status, err
+}
+
+// GetDonationTiers はプロジェクトの寄付の目安額を返す（Checkout で tier ID から金額を引くのに使用）
+func (r *PgProjectRepository) GetDonationTiers(ctx context.Context, projectID string) ([]model.DonationTier, error) {
+	var tiersJSON []byte
+	err := r.pool.QueryRow(ctx, `SELECT donation_tiers FROM projects WHERE id=$1`, projectID).Scan(&tiersJSON)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var tiers []model.DonationTier
+	if err := json.Unmarshal(tiersJSON, &tiers); err != nil {
+		return nil, err
+	}
+	return tiers, nil
+}
+
+// GetPaymentMethodTypes はプロジェクトが Checkout で受け付ける支払い方法を返す（空ならカードのみ）
+func (r *PgProjectRepository) GetPaymentMethodTypes(ctx context.Context, projectID string) ([]string, error) {
+	var types []string
+	err := r.pool.QueryRow(ctx, `SELECT payment_method_types FROM projects WHERE id=$1`, projectID).Scan(&types)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return types, err
+}
+
 // SaveStripeAccountID は stripe_account_id のみを保存する（status は変更しない）
 func (r *PgProjectRepository) SaveStripeAccountID(ctx context.Context, projectID, stripeAccountID string) error {
 	tag, err := r.pool.Exec(ctx,
@@ -323,3 +739,77 @@ func (r *PgProjectRepository) upsertAlerts(ctx context.Context, a *model.Project
 		a.ProjectID, a.WarningThreshold, a.CriticalThreshold,
 	).Scan(&a.ID, &a.CreatedAt, &a.UpdatedAt)
 }
+
+// ListRevisions はプロジェクトの更新履歴を新しい順に返す
+func (r *PgProjectRepository) ListRevisions(ctx context.Context, projectID string) ([]*model.ProjectRevision, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+projectRevisionSelectCols+`
+		 FROM project_revisions v LEFT JOIN users u ON u.id = v.actor_id
+		 WHERE v.project_id = $1
+		 ORDER BY v.created_at DESC, v.id DESC`,
+		projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*model.ProjectRevision
+	for rows.Next() {
+		rev, err := scanProjectRevision(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, rev)
+	}
+	return list, rows.Err()
+}
+
+// GetRevision はプロジェクトの更新履歴を 1 件返す（別のプロジェクトの履歴は ErrNotFound）
+func (r *PgProjectRepository) GetRevision(ctx context.Context, projectID, revisionID string) (*model.ProjectRevision, error) {
+	rev, err := scanProjectRevision(r.pool.QueryRow(ctx,
+		`SELECT `+projectRevisionSelectCols+`
+		 FROM project_revisions v LEFT JOIN users u ON u.id = v.actor_id
+		 WHERE v.id = $1 AND v.project_id = $2`,
+		revisionID, projectID).Scan)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return rev, err
+}
+
+const projectRevisionSelectCols = `v.id, v.project_id, COALESCE(v.actor_id, ''), COALESCE(u.name, ''), v.action, v.changes, v.snapshot,
+	COALESCE(v.restored_from, ''), v.created_at`
+
+func scanProjectRevision(scan func(dest ...any) error) (*model.ProjectRevision, error) {
+	rev := &model.ProjectRevision{}
+	var changes, snapshot []byte
+	if err := scan(&rev.ID, &rev.ProjectID, &rev.ActorID, &rev.ActorName, &rev.Action, &changes, &snapshot,
+		&rev.RestoredFrom, &rev.CreatedAt); err != nil {
+		return nil, err
+	}
+	_ = json.Unmarshal(changes, &rev.Changes)
+	_ = json.Unmarshal(snapshot, &rev.Snapshot)
+	return rev, nil
+}
+
+// insertProjectRevision は更新履歴を 1 件追加し、ID と作成日時を rev に設定する
+func insertProjectRevision(ctx context.Context, tx pgx.Tx, rev *model.ProjectRevision) error {
+	changes := rev.Changes
+	if changes == nil {
+		changes = map[string]model.ProjectFieldChange{}
+	}
+	c, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	snapshot, err := json.Marshal(rev.Snapshot)
+	if err != nil {
+		return err
+	}
+	return tx.QueryRow(ctx,
+		`INSERT INTO project_revisions (project_id, actor_id, action, changes, snapshot, restored_from)
+		 VALUES ($1, NULLIF($2, ''), $3, $4, $5, NULLIF($6, ''))
+		 RETURNING id, created_at`,
+		rev.ProjectID, rev.ActorID, rev.Action, c, snapshot, rev.RestoredFrom,
+	).Scan(&rev.ID, &rev.CreatedAt)
+}
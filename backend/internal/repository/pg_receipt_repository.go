@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type pgReceiptRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPgReceiptRepository returns a PostgreSQL-backed ReceiptRepository.
+func NewPgReceiptRepository(pool *pgxpool.Pool) ReceiptRepository {
+	return &pgReceiptRepository{pool: pool}
+}
+
+func (r *pgReceiptRepository) GetDonation(ctx context.Context, id string) (*model.Donation, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+donationSelectCols+` FROM donations WHERE id = $1`, id)
+	d, err := scanDonation(row.Scan)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return d, err
+}
+
+func (r *pgReceiptRepository) IssueDonationReceipt(ctx context.Context, userID, donationID string) (model.ReceiptNumber, error) {
+	return r.issue(ctx,
+		`SELECT receipt_number, issued_at FROM donation_receipts WHERE kind = 'donation' AND donation_id = $1`,
+		[]any{donationID},
+		`INSERT INTO donation_receipts (kind, user_id, donation_id) VALUES ('donation', $1, $2)
+		 ON CONFLICT (donation_id) WHERE kind = 'donation' DO NOTHING
+		 RETURNING receipt_number, issued_at`,
+		[]any{userID, donationID})
+}
+
+func (r *pgReceiptRepository) IssueAnnualStatement(ctx context.Context, userID string, year int) (model.ReceiptNumber, error) {
+	return r.issue(ctx,
+		`SELECT receipt_number, issued_at FROM donation_receipts WHERE kind = 'annual' AND user_id = $1 AND year = $2`,
+		[]any{userID, year},
+		`INSERT INTO donation_receipts (kind, user_id, year) VALUES ('annual', $1, $2)
+		 ON CONFLICT (user_id, year) WHERE kind = 'annual' DO NOTHING
+		 RETURNING receipt_number, issued_at`,
+		[]any{userID, year})
+}
+
+// issue は発行済みの番号を返し、なければ採番する。
+// 既存行を先に探すので、再発行で連番が飛ぶことはない（同時発行で競合した場合のみ欠番が出る）。
+func (r *pgReceiptRepository) issue(ctx context.Context, selectSQL string, selectArgs []any, insertSQL string, insertArgs []any) (model.ReceiptNumber, error) {
+	var n model.ReceiptNumber
+	err := r.pool.QueryRow(ctx, selectSQL, selectArgs...).Scan(&n.Number, &n.IssuedAt)
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return n, err
+	}
+	err = r.pool.QueryRow(ctx, insertSQL, insertArgs...).Scan(&n.Number, &n.IssuedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// 同時に発行された番号を使う
+		err = r.pool.QueryRow(ctx, selectSQL, selectArgs...).Scan(&n.Number, &n.IssuedAt)
+	}
+	return n, err
+}
+
+// receiptLineQuery は台帳の入金にプロジェクト名とオーナー名を付けて返す。
+// 金額は寄付者が実際に支払った額（決済手数料を負担した場合は上乗せ後の額）
+const receiptLineQuery = `SELECT p.id, COALESCE(p.donation_id, ''), p.project_id, pr.name, COALESCE(o.name, ''),
+	       p.payment_method, COALESCE(p.charged_amount, p.amount), p.currency, p.refunded_amount, COALESCE(p.dispute_status, ''), p.dispute_amount, p.paid_at
+	FROM donation_payments p
+	JOIN projects pr ON pr.id = p.project_id
+	LEFT JOIN users o ON o.id = pr.owner_id`
+
+func (r *pgReceiptRepository) ListLinesByDonation(ctx context.Context, donationID string) ([]*model.ReceiptLine, error) {
+	return r.listLines(ctx, receiptLineQuery+`
+	WHERE p.donation_id = $1
+	ORDER BY p.paid_at, p.id`, donationID)
+}
+
+// ListLinesByDonor は入金に記録された寄付者で探す（解約などで寄付が削除された入金も含む）
+func (r *pgReceiptRepository) ListLinesByDonor(ctx context.Context, userID string, from, to time.Time) ([]*model.ReceiptLine, error) {
+	return r.listLines(ctx, receiptLineQuery+`
+	WHERE p.donor_type = 'user' AND p.donor_id = $1
+	  AND p.paid_at >= $2 AND p.paid_at < $3
+	ORDER BY p.paid_at, p.id`, userID, from, to)
+}
+
+func (r *pgReceiptRepository) listLines(ctx context.Context, query string, args ...any) ([]*model.ReceiptLine, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*model.ReceiptLine
+	for rows.Next() {
+		l := &model.ReceiptLine{}
+		var p model.DonationPayment
+		if err := rows.Scan(&l.PaymentID, &l.DonationID, &l.ProjectID, &l.ProjectName, &l.OwnerName,
+			&l.PaymentMethod, &p.Amount, &l.Currency, &p.RefundedAmount, &p.DisputeStatus, &p.DisputeAmount, &l.PaidAt); err != nil {
+			return nil, err
+		}
+		l.Amount = p.NetAmount()
+		list = append(list, l)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type pgUpstreamRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPgUpstreamRepository returns a PostgreSQL-backed UpstreamRepository.
+func NewPgUpstreamRepository(pool *pgxpool.Pool) UpstreamRepository {
+	return &pgUpstreamRepository{pool: pool}
+}
+
+func (r *pgUpstreamRepository) ListByProject(ctx context.Context, projectID string) ([]*model.ProjectUpstream, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT u.project_id, u.upstream_project_id, p.name, u.share_percent, u.created_at
+		 FROM project_upstreams u
+		 JOIN projects p ON p.id = u.upstream_project_id
+		 WHERE u.project_id = $1 AND p.status != 'deleted'
+		 ORDER BY u.share_percent DESC, u.created_at, u.upstream_project_id`,
+		projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*model.ProjectUpstream
+	for rows.Next() {
+		u := &model.ProjectUpstream{}
+		if err := rows.Scan(&u.ProjectID, &u.UpstreamProjectID, &u.UpstreamProjectName, &u.SharePercent, &u.CreatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, u)
+	}
+	return list, rows.Err()
+}
+
+func (r *pgUpstreamRepository) Replace(ctx context.Context, projectID string, upstreams []*model.ProjectUpstream) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM project_upstreams WHERE project_id = $1`, projectID); err != nil {
+		return err
+	}
+	for _, u := range upstreams {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO project_upstreams (project_id, upstream_project_id, share_percent) VALUES ($1, $2, $3)`,
+			projectID, u.UpstreamProjectID, u.SharePercent); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *pgUpstreamRepository) CreatePassThroughs(ctx context.Context, list []*model.PassThrough) ([]*model.PassThrough, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var created []*model.PassThrough
+	for _, pt := range list {
+		err := tx.QueryRow(ctx,
+			`INSERT INTO pass_throughs (payment_id, from_project_id, to_project_id, amount, currency, share_percent, paid_at)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7)
+			 ON CONFLICT (payment_id, to_project_id) DO NOTHING
+			 RETURNING id, status, created_at`,
+			pt.PaymentID, pt.FromProjectID, pt.ToProjectID, pt.Amount, pt.Currency, pt.SharePercent, pt.PaidAt,
+		).Scan(&pt.ID, &pt.Status, &pt.CreatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue // この入金は分配済み
+		}
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, pt)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+const passThroughSelect = `
+	SELECT t.id, COALESCE(t.payment_id, ''), t.from_project_id, fp.name, t.to_project_id, tp.name,
+	       t.amount, t.currency, t.share_percent, t.status, COALESCE(t.stripe_transfer_id, ''),
+	       COALESCE(t.last_error, ''), t.paid_at, t.transferred_at, t.created_at
+	FROM pass_throughs t
+	JOIN projects fp ON fp.id = t.from_project_id
+	JOIN projects tp ON tp.id = t.to_project_id`
+
+func (r *pgUpstreamRepository) listPassThroughs(ctx context.Context, query string, args ...any) ([]*model.PassThrough, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*model.PassThrough
+	for rows.Next() {
+		pt := &model.PassThrough{}
+		if err := rows.Scan(
+			&pt.ID, &pt.PaymentID, &pt.FromProjectID, &pt.FromProjectName, &pt.ToProjectID, &pt.ToProjectName,
+			&pt.Amount, &pt.Currency, &pt.SharePercent, &pt.Status, &pt.StripeTransferID,
+			&pt.LastError, &pt.PaidAt, &pt.TransferredAt, &pt.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		list = append(list, pt)
+	}
+	return list, rows.Err()
+}
+
+func (r *pgUpstreamRepository) ListUntransferred(ctx context.Context, limit int) ([]*model.PassThrough, error) {
+	return r.listPassThroughs(ctx,
+		passThroughSelect+` WHERE t.status != 'transferred' ORDER BY t.created_at, t.id LIMIT $1`,
+		limit)
+}
+
+func (r *pgUpstreamRepository) MarkTransferred(ctx context.Context, id, transferID string) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE pass_throughs
+		 SET status = 'transferred', stripe_transfer_id = NULLIF($2, ''), last_error = NULL,
+		     transferred_at = NOW(), updated_at = NOW()
+		 WHERE id = $1`,
+		id, transferID)
+	return err
+}
+
+func (r *pgUpstreamRepository) MarkFailed(ctx context.Context, id, lastError string) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE pass_throughs SET status = 'failed', last_error = $2, updated_at = NOW()
+		 WHERE id = $1 AND status != 'transferred'`,
+		id, lastError)
+	return err
+}
+
+func (r *pgUpstreamRepository) ListPassThroughsByProject(ctx context.Context, projectID string, limit, offset int) ([]*model.PassThrough, error) {
+	return r.listPassThroughs(ctx,
+		passThroughSelect+` WHERE t.from_project_id = $1 OR t.to_project_id = $1
+		 ORDER BY t.paid_at DESC, t.id DESC LIMIT $2 OFFSET $3`,
+		projectID, limit, offset)
+}
+
+func (r *pgUpstreamRepository) MonthlyForwardedByProject(ctx context.Context, projectID string) ([]*model.MonthlySum, error) {
+	return r.monthlySums(ctx, "from_project_id", projectID)
+}
+
+func (r *pgUpstreamRepository) MonthlyReceivedByProject(ctx context.Context, projectID string) ([]*model.MonthlySum, error) {
+	return r.monthlySums(ctx, "to_project_id", projectID)
+}
+
+// monthlySums は column（from_project_id / to_project_id）が projectID の分配を直近 12 か月分、月別に合計する
+func (r *pgUpstreamRepository) monthlySums(ctx context.Context, column, projectID string) ([]*model.MonthlySum, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT TO_CHAR(DATE_TRUNC('month', paid_at), 'YYYY-MM') AS month,
+		        SUM(amount)::int AS amount
+		 FROM pass_throughs
+		 WHERE `+column+` = $1
+		   AND paid_at >= DATE_TRUNC('month', NOW()) - INTERVAL '11 months'
+		 GROUP BY DATE_TRUNC('month', paid_at)
+		 ORDER BY month`,
+		projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sums []*model.MonthlySum
+	for rows.Next() {
+		s := &model.MonthlySum{}
+		if err := rows.Scan(&s.Month, &s.Amount); err != nil {
+			return nil, err
+		}
+		sums = append(sums, s)
+	}
+	return sums, rows.Err()
+}
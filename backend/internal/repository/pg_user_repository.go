@@ -2,10 +2,12 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/givers/backend/internal/model"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -26,8 +28,8 @@ func (r *PgUserRepository) Ping(ctx context.Context) error {
 
 func scanUser(scan func(...any) error) (*model.User, error) {
 	var u model.User
-	var googleID, githubID, discordID *string
-	if err := scan(&u.ID, &u.Email, &googleID, &githubID, &discordID, &u.Name, &u.SuspendedAt, &u.CreatedAt, &u.UpdatedAt); err != nil {
+	var googleID, githubID, discordID, stripeCustomerID *string
+	if err := scan(&u.ID, &u.Email, &googleID, &githubID, &discordID, &u.Name, &u.SuspendedAt, &u.CreatedAt, &u.UpdatedAt, &stripeCustomerID); err != nil {
 		return nil, err
 	}
 	if googleID != nil {
@@ -39,10 +41,13 @@ func scanUser(scan func(...any) error) (*model.User, error) {
 	if discordID != nil {
 		u.DiscordID = *discordID
 	}
+	if stripeCustomerID != nil {
+		u.StripeCustomerID = *stripeCustomerID
+	}
 	return &u, nil
 }
 
-const userSelectCols = `id, email, google_id, github_id, discord_id, name, suspended_at, created_at, updated_at`
+const userSelectCols = `id, email, google_id, github_id, discord_id, name, suspended_at, created_at, updated_at, stripe_customer_id`
 
 // FindByID は ID でユーザーを取得する
 func (r *PgUserRepository) FindByID(ctx context.Context, id string) (*model.User, error) {
@@ -141,3 +146,51 @@ func (r *PgUserRepository) Suspend(ctx context.Context, id string, suspend bool)
 	}
 	return nil
 }
+
+// FindStripeCustomerID はユーザーの accountID 上の Stripe Customer ID を返す（未作成なら空）。
+// accountID が空ならプラットフォームアカウント上の Customer（users.stripe_customer_id）。
+func (r *PgUserRepository) FindStripeCustomerID(ctx context.Context, userID, accountID string) (string, error) {
+	var customerID string
+	if accountID == "" {
+		err := r.pool.QueryRow(ctx,
+			`SELECT COALESCE(stripe_customer_id, '') FROM users WHERE id = $1`, userID,
+		).Scan(&customerID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return customerID, err
+	}
+	err := r.pool.QueryRow(ctx,
+		`SELECT customer_id FROM stripe_customers WHERE user_id = $1 AND stripe_account_id = $2`,
+		userID, accountID).Scan(&customerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	return customerID, err
+}
+
+// SaveStripeCustomerID はユーザーの accountID 上（空ならプラットフォーム）の Stripe Customer ID を保存し、保存済みの ID を返す。
+// 既に保存されている場合は上書きせず既存の ID を返す（同時に作成された場合も 1 つに揃える）。
+func (r *PgUserRepository) SaveStripeCustomerID(ctx context.Context, userID, accountID, customerID string) (string, error) {
+	var saved string
+	var err error
+	if accountID == "" {
+		err = r.pool.QueryRow(ctx,
+			`UPDATE users SET stripe_customer_id = COALESCE(stripe_customer_id, $2), updated_at = NOW()
+			 WHERE id = $1
+			 RETURNING stripe_customer_id`,
+			userID, customerID).Scan(&saved)
+	} else {
+		// 競合時は既存の行を返すため、同じ値で更新する
+		err = r.pool.QueryRow(ctx,
+			`INSERT INTO stripe_customers (user_id, stripe_account_id, customer_id)
+			 SELECT id, $2, $3 FROM users WHERE id = $1
+			 ON CONFLICT (user_id, stripe_account_id) DO UPDATE SET customer_id = stripe_customers.customer_id
+			 RETURNING customer_id`,
+			userID, accountID, customerID).Scan(&saved)
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrNotFound
+	}
+	return saved, err
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type pgWebhookEventRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPgWebhookEventRepository returns a PostgreSQL-backed WebhookEventRepository.
+func NewPgWebhookEventRepository(pool *pgxpool.Pool) WebhookEventRepository {
+	return &pgWebhookEventRepository{pool: pool}
+}
+
+const webhookEventSelectCols = `id, type, payload, status, attempts, COALESCE(last_error, ''),
+	processed_at, created_at, updated_at`
+
+func scanWebhookEvent(scan func(...any) error) (*model.WebhookEvent, error) {
+	e := &model.WebhookEvent{}
+	var payload []byte
+	if err := scan(
+		&e.ID, &e.Type, &payload, &e.Status, &e.Attempts, &e.LastError,
+		&e.ProcessedAt, &e.CreatedAt, &e.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	e.Payload = payload
+	return e, nil
+}
+
+// webhookProcessingLease is how long an event may stay in processing before a
+// redelivery or a replay may take it over. A crash or timeout during dispatch
+// never calls MarkProcessed/MarkFailed, so without the lease the event would stay
+// in processing forever and every Stripe retry would be answered as a duplicate.
+const webhookProcessingLease = 10 * time.Minute
+
+// Begin inserts the event, or re-arms it when the previous attempt failed or its
+// processing lease has expired. The conditional upsert makes concurrent duplicate
+// deliveries race-safe: only one of them gets a row back.
+func (r *pgWebhookEventRepository) Begin(ctx context.Context, id, eventType string, payload []byte) (bool, error) {
+	var attempts int
+	err := r.pool.QueryRow(ctx,
+		`INSERT INTO webhook_events (id, type, payload, status, attempts)
+		 VALUES ($1, $2, $3, 'processing', 1)
+		 ON CONFLICT (id) DO UPDATE SET
+		   status = 'processing',
+		   attempts = webhook_events.attempts + 1,
+		   updated_at = NOW()
+		 WHERE webhook_events.status = 'failed'
+		    OR (webhook_events.status = 'processing' AND webhook_events.updated_at < NOW() - make_interval(secs => $4))
+		 RETURNING attempts`,
+		id, eventType, payload, webhookProcessingLease.Seconds(),
+	).Scan(&attempts)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *pgWebhookEventRepository) BeginReplay(ctx context.Context, id string) (*model.WebhookEvent, error) {
+	row := r.pool.QueryRow(ctx,
+		`UPDATE webhook_events
+		 SET status = 'processing', attempts = attempts + 1, updated_at = NOW()
+		 WHERE id = $1
+		   AND (status = 'failed' OR (status = 'processing' AND updated_at < NOW() - make_interval(secs => $2)))
+		 RETURNING `+webhookEventSelectCols, id, webhookProcessingLease.Seconds())
+	e, err := scanWebhookEvent(row.Scan)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return e, err
+}
+
+func (r *pgWebhookEventRepository) MarkProcessed(ctx context.Context, id string) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE webhook_events
+		 SET status = 'processed', last_error = NULL, processed_at = NOW(), updated_at = NOW()
+		 WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *pgWebhookEventRepository) MarkFailed(ctx context.Context, id, lastError string) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE webhook_events
+		 SET status = 'failed', last_error = $2, updated_at = NOW()
+		 WHERE id = $1`, id, lastError)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *pgWebhookEventRepository) GetByID(ctx context.Context, id string) (*model.WebhookEvent, error) {
+	row := r.pool.QueryRow(ctx,
+		`SELECT `+webhookEventSelectCols+` FROM webhook_events WHERE id = $1`, id)
+	e, err := scanWebhookEvent(row.Scan)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return e, err
+}
+
+func (r *pgWebhookEventRepository) ListByStatus(ctx context.Context, status string, limit, offset int) ([]*model.WebhookEvent, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+webhookEventSelectCols+`
+		 FROM webhook_events
+		 WHERE status = $1
+		 ORDER BY created_at DESC
+		 LIMIT $2 OFFSET $3`,
+		status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*model.WebhookEvent
+	for rows.Next() {
+		e, err := scanWebhookEvent(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, e)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/givers/backend/internal/model"
+)
+
+// ProjectCategoryRepository はホストが管理するプロジェクトカテゴリの永続化インターフェース
+type ProjectCategoryRepository interface {
+	// List はカテゴリを表示順に返す
+	List(ctx context.Context) ([]*model.ProjectCategory, error)
+	// Create はカテゴリを作成する。slug が既にある場合は ErrDuplicate を返す
+	Create(ctx context.Context, c *model.ProjectCategory) error
+	// Update は表示名と表示順を更新する。存在しない場合は ErrNotFound を返す
+	Update(ctx context.Context, c *model.ProjectCategory) error
+	// Delete はカテゴリを削除する（付いていたプロジェクトは未分類になる）。存在しない場合は ErrNotFound を返す
+	Delete(ctx context.Context, slug string) error
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/givers/backend/internal/model"
+)
+
+// ProjectMemberRepository はプロジェクトのメンバーと招待の永続化インターフェース
+type ProjectMemberRepository interface {
+	// ListMembers はメンバーを役割の強い順・参加順に返す
+	ListMembers(ctx context.Context, projectID string) ([]*model.ProjectMember, error)
+	// UpdateMemberRole はメンバーの役割を変更する。メンバーでなければ ErrNotFound を返す
+	UpdateMemberRole(ctx context.Context, projectID, userID, role string) error
+	// RemoveMember はメンバーを外す。メンバーでなければ ErrNotFound を返す
+	RemoveMember(ctx context.Context, projectID, userID string) error
+	// CreateInvitation は招待を作成する。同じ相手への保留中の招待があれば ErrDuplicate を返す
+	CreateInvitation(ctx context.Context, inv *model.ProjectInvitation) error
+	// GetInvitation は ID で招待を取得する
+	GetInvitation(ctx context.Context, id string) (*model.ProjectInvitation, error)
+	// ListInvitationsByProject はプロジェクトの保留中の招待を新しい順に返す
+	ListInvitationsByProject(ctx context.Context, projectID string) ([]*model.ProjectInvitation, error)
+	// ListInvitationsForUser はユーザー ID またはメールアドレス（大文字小文字を区別しない）宛ての保留中の招待を新しい順に返す
+	ListInvitationsForUser(ctx context.Context, userID, email string) ([]*model.ProjectInvitation, error)
+	// SetInvitationStatus は保留中の招待を辞退・取り消しにする。保留中でなければ ErrNotFound を返す
+	SetInvitationStatus(ctx context.Context, id, status string) error
+	// AcceptInvitation は保留中の招待を承諾済みにし、userID を招待の役割でメンバーに加える（既にメンバーなら役割を更新する）。
+	// 保留中でなければ ErrNotFound を返す
+	AcceptInvitation(ctx context.Context, id, userID string) error
+}
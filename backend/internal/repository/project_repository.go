@@ -8,11 +8,24 @@ import (
 
 // ProjectRepository はプロジェクト永続化のインターフェース
 type ProjectRepository interface {
-	List(ctx context.Context, sort string, limit int, cursor string) (*model.ProjectListResult, error)
+	List(ctx context.Context, sort string, filter model.ProjectFilter, limit int, cursor string) (*model.ProjectListResult, error)
+	// Facets は公開中のプロジェクトのカテゴリ別・タグ別の件数を返す
+	Facets(ctx context.Context, filter model.ProjectFilter) (*model.ProjectFacets, error)
+	// Search は公開中のプロジェクトを名前・概要・説明で全文検索する（日本語は bigram で照合）
+	Search(ctx context.Context, q model.ProjectSearchQuery) (*model.ProjectSearchResult, error)
 	GetByID(ctx context.Context, id string) (*model.Project, error)
 	ListByOwnerID(ctx context.Context, ownerID string) ([]*model.Project, error)
+	// Create / Update は存在しないカテゴリを指定すると ErrUnknownCategory を、
+	// 他のプロジェクトが使っている（または以前使っていた）スラッグを指定すると ErrSlugTaken を返す。
+	// Update でスラッグを変えると変更前のスラッグはリダイレクトとして残る
 	Create(ctx context.Context, project *model.Project) error
 	Update(ctx context.Context, project *model.Project) error
+	// UpdateWithRevision は Update と同じ更新に加え、rev が nil でなければ同じトランザクションで更新履歴を記録する
+	UpdateWithRevision(ctx context.Context, project *model.Project, rev *model.ProjectRevision) error
+	// ListRevisions はプロジェクトの更新履歴を新しい順に返す
+	ListRevisions(ctx context.Context, projectID string) ([]*model.ProjectRevision, error)
+	// GetRevision はプロジェクトの更新履歴を 1 件返す（別のプロジェクトの履歴は ErrNotFound）
+	GetRevision(ctx context.Context, projectID, revisionID string) (*model.ProjectRevision, error)
 	Delete(ctx context.Context, id string) error
 	// SaveStripeAccountID は stripe_account_id のみを保存する（status は変更しない）
 	SaveStripeAccountID(ctx context.Context, projectID, stripeAccountID string) error
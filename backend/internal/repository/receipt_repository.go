@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/givers/backend/internal/model"
+)
+
+// ReceiptRepository handles receipt numbers and the payments listed on donor receipts.
+type ReceiptRepository interface {
+	// GetDonation returns a donation by ID, or ErrNotFound.
+	GetDonation(ctx context.Context, id string) (*model.Donation, error)
+	// IssueDonationReceipt returns the receipt number of a donation, assigning the next
+	// number on first issue.
+	IssueDonationReceipt(ctx context.Context, userID, donationID string) (model.ReceiptNumber, error)
+	// IssueAnnualStatement returns the statement number of a donor's year, assigning the
+	// next number on first issue.
+	IssueAnnualStatement(ctx context.Context, userID string, year int) (model.ReceiptNumber, error)
+	// ListLinesByDonation returns the ledger payments of a donation, oldest first.
+	ListLinesByDonation(ctx context.Context, donationID string) ([]*model.ReceiptLine, error)
+	// ListLinesByDonor returns the ledger payments of the user's donations paid in [from, to), oldest first.
+	ListLinesByDonor(ctx context.Context, userID string, from, to time.Time) ([]*model.ReceiptLine, error)
+}
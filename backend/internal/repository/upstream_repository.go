@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/givers/backend/internal/model"
+)
+
+// UpstreamRepository handles the upstream projects a project forwards part of its
+// donations to, and the amounts forwarded from each payment.
+type UpstreamRepository interface {
+	// ListByProject returns the upstream projects of a project with their names, largest share first.
+	// Deleted upstream projects are left out.
+	ListByProject(ctx context.Context, projectID string) ([]*model.ProjectUpstream, error)
+	// Replace sets the upstream projects of a project, replacing the previous list.
+	Replace(ctx context.Context, projectID string, upstreams []*model.ProjectUpstream) error
+	// CreatePassThroughs records the forwarded parts of a payment and returns the new ones with
+	// their IDs. A part already recorded for the same payment and upstream project is skipped,
+	// so retries are safe.
+	CreatePassThroughs(ctx context.Context, list []*model.PassThrough) ([]*model.PassThrough, error)
+	// ListUntransferred returns pending and failed pass-throughs, oldest first.
+	ListUntransferred(ctx context.Context, limit int) ([]*model.PassThrough, error)
+	// MarkTransferred records a successful transfer. transferID is empty when no Stripe transfer was needed.
+	MarkTransferred(ctx context.Context, id, transferID string) error
+	// MarkFailed records a failed transfer attempt.
+	MarkFailed(ctx context.Context, id, lastError string) error
+	// ListPassThroughsByProject returns the pass-throughs a project forwarded or received, newest first.
+	ListPassThroughsByProject(ctx context.Context, projectID string, limit, offset int) ([]*model.PassThrough, error)
+	// MonthlyForwardedByProject returns the amount a project forwarded per month for the last 12 months.
+	MonthlyForwardedByProject(ctx context.Context, projectID string) ([]*model.MonthlySum, error)
+	// MonthlyReceivedByProject returns the amount a project received per month for the last 12 months.
+	MonthlyReceivedByProject(ctx context.Context, projectID string) ([]*model.MonthlySum, error)
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/givers/backend/internal/model"
+)
+
+// WebhookEventRepository handles persistence for incoming Stripe webhook events.
+type WebhookEventRepository interface {
+	// Begin records a delivery and marks it as processing.
+	// A new event, one whose previous attempt failed, or one stuck in processing
+	// past its lease (the previous attempt crashed or timed out) returns started=true.
+	// Events that are already processed (or still being processed) return started=false.
+	Begin(ctx context.Context, id, eventType string, payload []byte) (started bool, err error)
+	// BeginReplay moves a failed event (or one stuck in processing past its lease)
+	// back to processing and returns it.
+	// Returns ErrNotFound when no such event with the given ID exists.
+	BeginReplay(ctx context.Context, id string) (*model.WebhookEvent, error)
+	// MarkProcessed marks an event as successfully handled.
+	MarkProcessed(ctx context.Context, id string) error
+	// MarkFailed marks an event as failed and stores the error message.
+	MarkFailed(ctx context.Context, id, lastError string) error
+	// GetByID returns a single event by its Stripe event ID.
+	GetByID(ctx context.Context, id string) (*model.WebhookEvent, error)
+	// ListByStatus returns events with the given status, newest first.
+	ListByStatus(ctx context.Context, status string, limit, offset int) ([]*model.WebhookEvent, error)
+}
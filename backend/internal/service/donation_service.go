@@ -4,14 +4,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 
 	"github.com/givers/backend/internal/model"
 	"github.com/givers/backend/internal/repository"
+	pkgstripe "github.com/givers/backend/pkg/stripe"
 )
 
 // ErrForbidden is returned when a user tries to modify another user's resource.
 var ErrForbidden = errors.New("forbidden")
 
+// ErrNotRecurring is returned when a subscription-only operation targets a one-time donation.
+var ErrNotRecurring = errors.New("donation is not recurring")
+
+// ErrBillingNotConfigured is returned when no SubscriptionManager is configured.
+var ErrBillingNotConfigured = errors.New("billing not configured")
+
 // MigrateTokenResult holds the result of a token migration.
 type MigrateTokenResult struct {
 	MigratedCount   int
@@ -19,11 +27,19 @@ type MigrateTokenResult struct {
 }
 
 // SubscriptionManager manages Stripe subscription lifecycle.
+// accountID is the connected account the subscription lives on ("" for the platform).
 type SubscriptionManager interface {
-	PauseSubscription(ctx context.Context, subscriptionID string) error
-	ResumeSubscription(ctx context.Context, subscriptionID string) error
-	CancelSubscription(ctx context.Context, subscriptionID string) error
-	UpdateSubscriptionAmount(ctx context.Context, subscriptionID string, newAmount int) error
+	PauseSubscription(ctx context.Context, accountID, subscriptionID string) error
+	ResumeSubscription(ctx context.Context, accountID, subscriptionID string) error
+	CancelSubscription(ctx context.Context, accountID, subscriptionID string) error
+	UpdateSubscriptionAmount(ctx context.Context, accountID, subscriptionID string, newAmount int) error
+	CreatePaymentMethodUpdateSession(ctx context.Context, accountID, subscriptionID, returnURL string) (string, error)
+}
+
+// ProjectStripeAccountLookup resolves the connected account a project's donations are charged on.
+type ProjectStripeAccountLookup interface {
+	// GetStripeAccountID returns "" for host projects, which are charged on the platform account.
+	GetStripeAccountID(ctx context.Context, projectID string) (string, error)
 }
 
 // DonationService provides business logic for donation management.
@@ -33,16 +49,60 @@ type DonationService interface {
 	Delete(ctx context.Context, id, userID string) error
 	MigrateToken(ctx context.Context, token, userID string) (*MigrateTokenResult, error)
 	ListProjectMessages(ctx context.Context, projectID string, limit, offset int, sort, donor string) (*model.DonationMessageResult, error)
+	// TierBreakdown reports how many donations each of the project's tiers received,
+	// in the project's tier order. Tiers the owner removed but that still have donations come last.
+	TierBreakdown(ctx context.Context, project *model.Project) ([]*model.DonationTierStat, error)
+	// CreatePaymentMethodSession returns a Stripe-hosted URL where the donor can
+	// replace the payment method of a recurring donation (e.g. after a card expired).
+	CreatePaymentMethodSession(ctx context.Context, id, userID string) (string, error)
+	// ListChanges returns the change history of one of the donor's donations, oldest first:
+	// changes made through PATCH and those made on Stripe's side (Dashboard, Customer Portal, Stripe itself).
+	ListChanges(ctx context.Context, id, userID string) ([]*model.DonationChange, error)
+	// ListProjectChanges returns the change history of a project's recurring donations, newest first.
+	ListProjectChanges(ctx context.Context, projectID string, limit, offset int) ([]*model.DonationChange, error)
 }
 
 type donationService struct {
-	repo repository.DonationRepository
-	sm   SubscriptionManager
+	repo             repository.DonationRepository
+	sm               SubscriptionManager
+	billingReturnURL string
+	feeSchedule      pkgstripe.FeeSchedule
+	changes          repository.DonationChangeRepository // optional, nil = no change history
+	accounts         ProjectStripeAccountLookup          // optional, nil = every subscription is on the platform
+}
+
+// DonationServiceOption configures optional settings of donationService.
+type DonationServiceOption func(*donationService)
+
+// WithBillingReturnURL sets the URL donors return to after updating their payment method.
+func WithBillingReturnURL(returnURL string) DonationServiceOption {
+	return func(s *donationService) { s.billingReturnURL = returnURL }
+}
+
+// WithDonationFeeSchedule sets the fee schedule used to gross up the new amount
+// of a recurring donation whose donor covers the processing fees.
+func WithDonationFeeSchedule(fs pkgstripe.FeeSchedule) DonationServiceOption {
+	return func(s *donationService) { s.feeSchedule = fs }
+}
+
+// WithDonationChangeLog records donor changes to recurring donations and enables the change history.
+func WithDonationChangeLog(changes repository.DonationChangeRepository) DonationServiceOption {
+	return func(s *donationService) { s.changes = changes }
+}
+
+// WithProjectStripeAccounts sends subscription changes to the connected account of the donation's project.
+// Subscriptions of connected projects are created on that account, so the platform cannot see them.
+func WithProjectStripeAccounts(accounts ProjectStripeAccountLookup) DonationServiceOption {
+	return func(s *donationService) { s.accounts = accounts }
 }
 
 // NewDonationService creates a DonationService. sm can be nil to skip Stripe calls.
-func NewDonationService(repo repository.DonationRepository, sm SubscriptionManager) DonationService {
-	return &donationService{repo: repo, sm: sm}
+func NewDonationService(repo repository.DonationRepository, sm SubscriptionManager, opts ...DonationServiceOption) DonationService {
+	s := &donationService{repo: repo, sm: sm, feeSchedule: pkgstripe.DefaultFeeSchedule}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *donationService) ListByUser(ctx context.Context, userID string, limit, offset int) ([]*model.Donation, error) {
@@ -50,49 +110,99 @@ func (s *donationService) ListByUser(ctx context.Context, userID string, limit,
 }
 
 func (s *donationService) Patch(ctx context.Context, id, userID string, patch model.DonationPatch) error {
-	d, err := s.repo.GetByID(ctx, id)
+	d, err := s.ownStripeDonation(ctx, id, userID)
 	if err != nil {
 		return err
 	}
-	if d.DonorType != "user" || d.DonorID != userID {
-		return ErrForbidden
-	}
 
 	// Stripe subscription pause/resume for recurring donations
 	if patch.Paused != nil && d.IsRecurring && d.StripeSubscriptionID != "" && s.sm != nil {
+		accountID, err := s.stripeAccountID(ctx, d)
+		if err != nil {
+			return err
+		}
 		if *patch.Paused {
-			if err := s.sm.PauseSubscription(ctx, d.StripeSubscriptionID); err != nil {
+			if err := s.sm.PauseSubscription(ctx, accountID, d.StripeSubscriptionID); err != nil {
 				return fmt.Errorf("stripe pause: %w", err)
 			}
 		} else {
-			if err := s.sm.ResumeSubscription(ctx, d.StripeSubscriptionID); err != nil {
+			if err := s.sm.ResumeSubscription(ctx, accountID, d.StripeSubscriptionID); err != nil {
 				return fmt.Errorf("stripe resume: %w", err)
 			}
 		}
 	}
 
+	// A donor who covers the fees keeps covering them at the new amount
+	if patch.Amount != nil && d.CoveredFee() > 0 {
+		charged, ok := s.feeSchedule.GrossUp(*patch.Amount, d.Currency)
+		if !ok {
+			charged = *patch.Amount + d.CoveredFee()
+		}
+		patch.ChargedAmount = &charged
+	}
+
 	// Stripe subscription amount update for recurring donations (#19)
 	if patch.Amount != nil && *patch.Amount != d.Amount && d.IsRecurring && d.StripeSubscriptionID != "" && s.sm != nil {
-		if err := s.sm.UpdateSubscriptionAmount(ctx, d.StripeSubscriptionID, *patch.Amount); err != nil {
+		charged := *patch.Amount
+		if patch.ChargedAmount != nil {
+			charged = *patch.ChargedAmount
+		}
+		accountID, err := s.stripeAccountID(ctx, d)
+		if err != nil {
+			return err
+		}
+		if err := s.sm.UpdateSubscriptionAmount(ctx, accountID, d.StripeSubscriptionID, charged); err != nil {
 			return fmt.Errorf("stripe update amount: %w", err)
 		}
 	}
 
-	return s.repo.Patch(ctx, id, patch)
+	if err := s.repo.Patch(ctx, id, patch); err != nil {
+		return err
+	}
+	if d.IsRecurring {
+		s.recordChange(ctx, d, patch)
+	}
+	return nil
+}
+
+// recordChange appends the donor's change to the donation's history.
+// The change is already applied, so a failure is only logged.
+func (s *donationService) recordChange(ctx context.Context, d *model.Donation, patch model.DonationPatch) {
+	if s.changes == nil {
+		return
+	}
+	changes := map[string]model.DonationFieldChange{}
+	if patch.Amount != nil && *patch.Amount != d.Amount {
+		changes["amount"] = model.DonationFieldChange{From: d.Amount, To: *patch.Amount}
+	}
+	if patch.ChargedAmount != nil && *patch.ChargedAmount != d.Charge() {
+		changes["charged_amount"] = model.DonationFieldChange{From: d.Charge(), To: *patch.ChargedAmount}
+	}
+	if patch.Paused != nil && *patch.Paused != d.Paused {
+		changes["paused"] = model.DonationFieldChange{From: d.Paused, To: *patch.Paused}
+	}
+	if len(changes) == 0 {
+		return
+	}
+	c := &model.DonationChange{DonationID: d.ID, Source: model.DonationChangeSourceDonor, Changes: changes}
+	if err := s.changes.Create(ctx, c); err != nil {
+		slog.Error("record donation change failed", "error", err, "donation_id", d.ID)
+	}
 }
 
 func (s *donationService) Delete(ctx context.Context, id, userID string) error {
-	d, err := s.repo.GetByID(ctx, id)
+	d, err := s.ownStripeDonation(ctx, id, userID)
 	if err != nil {
 		return err
 	}
-	if d.DonorType != "user" || d.DonorID != userID {
-		return ErrForbidden
-	}
 
 	// Cancel Stripe subscription before deleting
 	if d.IsRecurring && d.StripeSubscriptionID != "" && s.sm != nil {
-		if err := s.sm.CancelSubscription(ctx, d.StripeSubscriptionID); err != nil {
+		accountID, err := s.stripeAccountID(ctx, d)
+		if err != nil {
+			return err
+		}
+		if err := s.sm.CancelSubscription(ctx, accountID, d.StripeSubscriptionID); err != nil {
 			return fmt.Errorf("stripe cancel: %w", err)
 		}
 	}
@@ -100,10 +210,110 @@ func (s *donationService) Delete(ctx context.Context, id, userID string) error {
 	return s.repo.Delete(ctx, id)
 }
 
+func (s *donationService) CreatePaymentMethodSession(ctx context.Context, id, userID string) (string, error) {
+	d, err := s.ownStripeDonation(ctx, id, userID)
+	if err != nil {
+		return "", err
+	}
+	if !d.IsRecurring || d.StripeSubscriptionID == "" {
+		return "", ErrNotRecurring
+	}
+	if s.sm == nil {
+		return "", ErrBillingNotConfigured
+	}
+	accountID, err := s.stripeAccountID(ctx, d)
+	if err != nil {
+		return "", err
+	}
+	url, err := s.sm.CreatePaymentMethodUpdateSession(ctx, accountID, d.StripeSubscriptionID, s.billingReturnURL)
+	if err != nil {
+		return "", fmt.Errorf("stripe payment method session: %w", err)
+	}
+	return url, nil
+}
+
+// ownStripeDonation returns a donation the user made through Stripe.
+// Manual donations attributed to the user are managed by the project (with an audit trail), not by the donor.
+func (s *donationService) ownStripeDonation(ctx context.Context, id, userID string) (*model.Donation, error) {
+	d, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if d.DonorType != "user" || d.DonorID != userID {
+		return nil, ErrForbidden
+	}
+	if d.IsManual() {
+		return nil, fmt.Errorf("%w: manual donations are managed by the project", ErrForbidden)
+	}
+	return d, nil
+}
+
+// stripeAccountID returns the connected account the donation's subscription was created on.
+func (s *donationService) stripeAccountID(ctx context.Context, d *model.Donation) (string, error) {
+	if s.accounts == nil {
+		return "", nil
+	}
+	accountID, err := s.accounts.GetStripeAccountID(ctx, d.ProjectID)
+	if err != nil {
+		return "", fmt.Errorf("project stripe account: %w", err)
+	}
+	return accountID, nil
+}
+
+func (s *donationService) ListChanges(ctx context.Context, id, userID string) ([]*model.DonationChange, error) {
+	d, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if d.DonorType != "user" || d.DonorID != userID {
+		return nil, ErrForbidden
+	}
+	if s.changes == nil {
+		return nil, nil
+	}
+	return s.changes.ListByDonation(ctx, id)
+}
+
+func (s *donationService) ListProjectChanges(ctx context.Context, projectID string, limit, offset int) ([]*model.DonationChange, error) {
+	if s.changes == nil {
+		return nil, nil
+	}
+	return s.changes.ListByProject(ctx, projectID, limit, offset)
+}
+
 func (s *donationService) ListProjectMessages(ctx context.Context, projectID string, limit, offset int, sort, donor string) (*model.DonationMessageResult, error) {
 	return s.repo.ListMessagesByProject(ctx, projectID, limit, offset, sort, donor)
 }
 
+func (s *donationService) TierBreakdown(ctx context.Context, project *model.Project) ([]*model.DonationTierStat, error) {
+	stats, err := s.repo.TierStatsByProject(ctx, project.ID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*model.DonationTierStat, len(stats))
+	for _, st := range stats {
+		byID[st.TierID] = st
+	}
+
+	list := []*model.DonationTierStat{}
+	for _, t := range project.DonationTiers {
+		st, ok := byID[t.ID]
+		if !ok {
+			st = &model.DonationTierStat{TierID: t.ID}
+		}
+		delete(byID, t.ID)
+		st.Label, st.Amount, st.IsRecurring = t.Label, t.Amount, t.IsRecurring
+		list = append(list, st)
+	}
+	for _, st := range stats {
+		if _, removed := byID[st.TierID]; removed {
+			st.Removed = true
+			list = append(list, st)
+		}
+	}
+	return list, nil
+}
+
 func (s *donationService) MigrateToken(ctx context.Context, token, userID string) (*MigrateTokenResult, error) {
 	if token == "" {
 		return nil, errors.New("donor_token is required")
@@ -20,6 +20,7 @@ type mockDonationRepository struct {
 	patchFunc       func(ctx context.Context, id string, patch model.DonationPatch) error
 	deleteFunc      func(ctx context.Context, id string) error
 	migrateFunc     func(ctx context.Context, token string, userID string) (int, error)
+	tierStatsFunc   func(ctx context.Context, projectID string) ([]*model.DonationTierStat, error)
 }
 
 func (m *mockDonationRepository) ListByUser(ctx context.Context, userID string, limit, offset int) ([]*model.Donation, error) {
@@ -70,9 +71,24 @@ func (m *mockDonationRepository) ListByProject(ctx context.Context, projectID st
 func (m *mockDonationRepository) GetByStripeSubscriptionID(ctx context.Context, subscriptionID string) (*model.Donation, error) {
 	return nil, nil
 }
+func (m *mockDonationRepository) GetByStripePaymentID(ctx context.Context, paymentID string) (*model.Donation, error) {
+	return nil, repository.ErrNotFound
+}
+func (m *mockDonationRepository) ListStripeLinked(ctx context.Context) ([]*model.Donation, error) {
+	return nil, nil
+}
+func (m *mockDonationRepository) SyncAdjustments(ctx context.Context, id string) error {
+	return nil
+}
 func (m *mockDonationRepository) ListMessagesByProject(ctx context.Context, projectID string, limit, offset int, sort, donor string) (*model.DonationMessageResult, error) {
 	return &model.DonationMessageResult{Messages: []*model.DonationMessage{}, Total: 0}, nil
 }
+func (m *mockDonationRepository) TierStatsByProject(ctx context.Context, projectID string) ([]*model.DonationTierStat, error) {
+	if m.tierStatsFunc != nil {
+		return m.tierStatsFunc(ctx, projectID)
+	}
+	return nil, nil
+}
 
 // ---------------------------------------------------------------------------
 // DonationService.ListByUser tests
@@ -280,36 +296,43 @@ func TestDonationService_MigrateToken_InvalidToken(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 type mockSubscriptionManager struct {
-	pauseFunc        func(ctx context.Context, subID string) error
-	resumeFunc       func(ctx context.Context, subID string) error
-	cancelFunc       func(ctx context.Context, subID string) error
-	updateAmountFunc func(ctx context.Context, subID string, newAmount int) error
+	pauseFunc        func(ctx context.Context, accountID, subID string) error
+	resumeFunc       func(ctx context.Context, accountID, subID string) error
+	cancelFunc       func(ctx context.Context, accountID, subID string) error
+	updateAmountFunc func(ctx context.Context, accountID, subID string, newAmount int) error
+	portalFunc       func(ctx context.Context, accountID, subID, returnURL string) (string, error)
 }
 
-func (m *mockSubscriptionManager) PauseSubscription(ctx context.Context, subID string) error {
+func (m *mockSubscriptionManager) PauseSubscription(ctx context.Context, accountID, subID string) error {
 	if m.pauseFunc != nil {
-		return m.pauseFunc(ctx, subID)
+		return m.pauseFunc(ctx, accountID, subID)
 	}
 	return nil
 }
-func (m *mockSubscriptionManager) ResumeSubscription(ctx context.Context, subID string) error {
+func (m *mockSubscriptionManager) ResumeSubscription(ctx context.Context, accountID, subID string) error {
 	if m.resumeFunc != nil {
-		return m.resumeFunc(ctx, subID)
+		return m.resumeFunc(ctx, accountID, subID)
 	}
 	return nil
 }
-func (m *mockSubscriptionManager) CancelSubscription(ctx context.Context, subID string) error {
+func (m *mockSubscriptionManager) CancelSubscription(ctx context.Context, accountID, subID string) error {
 	if m.cancelFunc != nil {
-		return m.cancelFunc(ctx, subID)
+		return m.cancelFunc(ctx, accountID, subID)
 	}
 	return nil
 }
-func (m *mockSubscriptionManager) UpdateSubscriptionAmount(ctx context.Context, subID string, newAmount int) error {
+func (m *mockSubscriptionManager) UpdateSubscriptionAmount(ctx context.Context, accountID, subID string, newAmount int) error {
 	if m.updateAmountFunc != nil {
-		return m.updateAmountFunc(ctx, subID, newAmount)
+		return m.updateAmountFunc(ctx, accountID, subID, newAmount)
 	}
 	return nil
 }
+func (m *mockSubscriptionManager) CreatePaymentMethodUpdateSession(ctx context.Context, accountID, subID, returnURL string) (string, error) {
+	if m.portalFunc != nil {
+		return m.portalFunc(ctx, accountID, subID, returnURL)
+	}
+	return "", nil
+}
 
 // ---------------------------------------------------------------------------
 // Stripe subscription integration tests
@@ -331,7 +354,7 @@ func TestDonationService_Patch_PausesStripeSubscription(t *testing.T) {
 		},
 	}
 	sm := &mockSubscriptionManager{
-		pauseFunc: func(ctx context.Context, subID string) error {
+		pauseFunc: func(ctx context.Context, accountID, subID string) error {
 			capturedSubID = subID
 			return nil
 		},
@@ -363,7 +386,7 @@ func TestDonationService_Patch_ResumesStripeSubscription(t *testing.T) {
 		},
 	}
 	sm := &mockSubscriptionManager{
-		resumeFunc: func(ctx context.Context, subID string) error {
+		resumeFunc: func(ctx context.Context, accountID, subID string) error {
 			capturedSubID = subID
 			return nil
 		},
@@ -390,7 +413,7 @@ func TestDonationService_Patch_StripeErrorRollsBack(t *testing.T) {
 		},
 	}
 	sm := &mockSubscriptionManager{
-		pauseFunc: func(ctx context.Context, subID string) error {
+		pauseFunc: func(ctx context.Context, accountID, subID string) error {
 			return errors.New("stripe api error")
 		},
 	}
@@ -416,7 +439,7 @@ func TestDonationService_Patch_NoStripeCallForNonRecurring(t *testing.T) {
 		},
 	}
 	sm := &mockSubscriptionManager{
-		pauseFunc: func(ctx context.Context, subID string) error {
+		pauseFunc: func(ctx context.Context, accountID, subID string) error {
 			t.Error("PauseSubscription should not be called for non-recurring donation")
 			return nil
 		},
@@ -443,7 +466,7 @@ func TestDonationService_Delete_CancelsStripeSubscription(t *testing.T) {
 		},
 	}
 	sm := &mockSubscriptionManager{
-		cancelFunc: func(ctx context.Context, subID string) error {
+		cancelFunc: func(ctx context.Context, accountID, subID string) error {
 			capturedSubID = subID
 			return nil
 		},
@@ -469,7 +492,7 @@ func TestDonationService_Delete_StripeErrorPreventsDelete(t *testing.T) {
 		},
 	}
 	sm := &mockSubscriptionManager{
-		cancelFunc: func(ctx context.Context, subID string) error {
+		cancelFunc: func(ctx context.Context, accountID, subID string) error {
 			return errors.New("stripe cancel failed")
 		},
 	}
@@ -523,7 +546,7 @@ func TestDonationService_Patch_UpdatesStripeAmount(t *testing.T) {
 		},
 	}
 	sm := &mockSubscriptionManager{
-		updateAmountFunc: func(ctx context.Context, subID string, amount int) error {
+		updateAmountFunc: func(ctx context.Context, accountID, subID string, amount int) error {
 			capturedSubID = subID
 			capturedAmount = amount
 			return nil
@@ -543,6 +566,42 @@ func TestDonationService_Patch_UpdatesStripeAmount(t *testing.T) {
 	}
 }
 
+func TestDonationService_Patch_CoveredFee_GrossesUpStripeAmount(t *testing.T) {
+	var capturedAmount int
+	var patched model.DonationPatch
+	newAmount := 3000
+
+	repo := &mockDonationRepository{
+		getByIDFunc: func(ctx context.Context, id string) (*model.Donation, error) {
+			return &model.Donation{
+				ID: id, DonorType: "user", DonorID: "u1", Amount: 1000, ChargedAmount: 1038, Currency: "jpy",
+				IsRecurring: true, StripeSubscriptionID: "sub_fee",
+			}, nil
+		},
+		patchFunc: func(ctx context.Context, id string, patch model.DonationPatch) error {
+			patched = patch
+			return nil
+		},
+	}
+	sm := &mockSubscriptionManager{
+		updateAmountFunc: func(ctx context.Context, accountID, subID string, amount int) error {
+			capturedAmount = amount
+			return nil
+		},
+	}
+	svc := NewDonationService(repo, sm)
+
+	if err := svc.Patch(context.Background(), "d1", "u1", model.DonationPatch{Amount: &newAmount}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if capturedAmount != 3113 {
+		t.Errorf("expected Stripe to charge 3113 for an intended 3000, got %d", capturedAmount)
+	}
+	if patched.Amount == nil || *patched.Amount != 3000 || patched.ChargedAmount == nil || *patched.ChargedAmount != 3113 {
+		t.Errorf("unexpected patch: %+v", patched)
+	}
+}
+
 func TestDonationService_Patch_StripeAmountError_ReturnsError(t *testing.T) {
 	newAmount := 5000
 
@@ -555,7 +614,7 @@ func TestDonationService_Patch_StripeAmountError_ReturnsError(t *testing.T) {
 		},
 	}
 	sm := &mockSubscriptionManager{
-		updateAmountFunc: func(ctx context.Context, subID string, amount int) error {
+		updateAmountFunc: func(ctx context.Context, accountID, subID string, amount int) error {
 			return errors.New("stripe api error")
 		},
 	}
@@ -583,7 +642,7 @@ func TestDonationService_Patch_SameAmount_SkipsStripeUpdate(t *testing.T) {
 		},
 	}
 	sm := &mockSubscriptionManager{
-		updateAmountFunc: func(ctx context.Context, subID string, amount int) error {
+		updateAmountFunc: func(ctx context.Context, accountID, subID string, amount int) error {
 			stripeCalled = true
 			return nil
 		},
@@ -615,7 +674,7 @@ func TestDonationService_Patch_NonRecurring_SkipsStripeAmountUpdate(t *testing.T
 		},
 	}
 	sm := &mockSubscriptionManager{
-		updateAmountFunc: func(ctx context.Context, subID string, amount int) error {
+		updateAmountFunc: func(ctx context.Context, accountID, subID string, amount int) error {
 			stripeCalled = true
 			return nil
 		},
@@ -630,3 +689,248 @@ func TestDonationService_Patch_NonRecurring_SkipsStripeAmountUpdate(t *testing.T
 		t.Error("expected UpdateSubscriptionAmount NOT to be called for non-recurring donation")
 	}
 }
+
+// ---------------------------------------------------------------------------
+// DonationService.CreatePaymentMethodSession tests
+// ---------------------------------------------------------------------------
+
+func recurringDonationRepo() *mockDonationRepository {
+	return &mockDonationRepository{
+		getByIDFunc: func(ctx context.Context, id string) (*model.Donation, error) {
+			return &model.Donation{
+				ID: id, DonorType: "user", DonorID: "u1",
+				IsRecurring: true, StripeSubscriptionID: "sub_pm",
+				PaymentStatus: model.PaymentStatusPastDue,
+			}, nil
+		},
+	}
+}
+
+func TestDonationService_CreatePaymentMethodSession_Success(t *testing.T) {
+	var capturedSubID, capturedReturnURL string
+	sm := &mockSubscriptionManager{
+		portalFunc: func(ctx context.Context, accountID, subID, returnURL string) (string, error) {
+			capturedSubID, capturedReturnURL = subID, returnURL
+			return "https://billing.stripe.com/session/abc", nil
+		},
+	}
+	svc := NewDonationService(recurringDonationRepo(), sm, WithBillingReturnURL("https://example.com/me"))
+
+	url, err := svc.CreatePaymentMethodSession(context.Background(), "d1", "u1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if url != "https://billing.stripe.com/session/abc" {
+		t.Errorf("unexpected url %q", url)
+	}
+	if capturedSubID != "sub_pm" || capturedReturnURL != "https://example.com/me" {
+		t.Errorf("unexpected subID=%q returnURL=%q", capturedSubID, capturedReturnURL)
+	}
+}
+
+// stubStripeAccounts maps project IDs to their connected account.
+type stubStripeAccounts map[string]string
+
+func (s stubStripeAccounts) GetStripeAccountID(ctx context.Context, projectID string) (string, error) {
+	return s[projectID], nil
+}
+
+func TestDonationService_ConnectedProjectSubscription_UsesProjectAccount(t *testing.T) {
+	paused := true
+	amount := 2000
+	repo := &mockDonationRepository{
+		getByIDFunc: func(ctx context.Context, id string) (*model.Donation, error) {
+			return &model.Donation{
+				ID: id, ProjectID: "p1", DonorType: "user", DonorID: "u1", Amount: 1000,
+				IsRecurring: true, StripeSubscriptionID: "sub_conn",
+			}, nil
+		},
+		patchFunc:  func(ctx context.Context, id string, patch model.DonationPatch) error { return nil },
+		deleteFunc: func(ctx context.Context, id string) error { return nil },
+	}
+	var accounts []string
+	record := func(accountID string) { accounts = append(accounts, accountID) }
+	sm := &mockSubscriptionManager{
+		pauseFunc:        func(ctx context.Context, accountID, subID string) error { record(accountID); return nil },
+		cancelFunc:       func(ctx context.Context, accountID, subID string) error { record(accountID); return nil },
+		updateAmountFunc: func(ctx context.Context, accountID, subID string, n int) error { record(accountID); return nil },
+		portalFunc: func(ctx context.Context, accountID, subID, returnURL string) (string, error) {
+			record(accountID)
+			return "https://billing.stripe.com/session/conn", nil
+		},
+	}
+	svc := NewDonationService(repo, sm, WithProjectStripeAccounts(stubStripeAccounts{"p1": "acct_p1"}))
+
+	ctx := context.Background()
+	if err := svc.Patch(ctx, "d1", "u1", model.DonationPatch{Paused: &paused, Amount: &amount}); err != nil {
+		t.Fatalf("Patch: %v", err)
+	}
+	if _, err := svc.CreatePaymentMethodSession(ctx, "d1", "u1"); err != nil {
+		t.Fatalf("CreatePaymentMethodSession: %v", err)
+	}
+	if err := svc.Delete(ctx, "d1", "u1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if len(accounts) != 4 {
+		t.Fatalf("expected 4 Stripe calls, got %v", accounts)
+	}
+	for _, a := range accounts {
+		if a != "acct_p1" {
+			t.Errorf("expected every call on acct_p1, got %v", accounts)
+			break
+		}
+	}
+}
+
+func TestDonationService_CreatePaymentMethodSession_Forbidden(t *testing.T) {
+	svc := NewDonationService(recurringDonationRepo(), &mockSubscriptionManager{})
+	if _, err := svc.CreatePaymentMethodSession(context.Background(), "d1", "other"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestDonationService_ManualDonation_NotManagedByDonor(t *testing.T) {
+	repo := &mockDonationRepository{
+		getByIDFunc: func(ctx context.Context, id string) (*model.Donation, error) {
+			return &model.Donation{
+				ID: id, DonorType: "user", DonorID: "u1", Amount: 5000,
+				PaymentMethod: model.PaymentMethodBankTransfer,
+			}, nil
+		},
+		patchFunc: func(ctx context.Context, id string, patch model.DonationPatch) error {
+			t.Error("manual donation must not be patched by the donor")
+			return nil
+		},
+		deleteFunc: func(ctx context.Context, id string) error {
+			t.Error("manual donation must not be deleted by the donor")
+			return nil
+		},
+	}
+	svc := NewDonationService(repo, &mockSubscriptionManager{})
+	ctx := context.Background()
+	amount := 1
+
+	if err := svc.Patch(ctx, "d1", "u1", model.DonationPatch{Amount: &amount}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Patch: expected ErrForbidden, got %v", err)
+	}
+	if err := svc.Delete(ctx, "d1", "u1"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Delete: expected ErrForbidden, got %v", err)
+	}
+	if _, err := svc.CreatePaymentMethodSession(ctx, "d1", "u1"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("CreatePaymentMethodSession: expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestDonationService_CreatePaymentMethodSession_NotRecurring(t *testing.T) {
+	repo := &mockDonationRepository{
+		getByIDFunc: func(ctx context.Context, id string) (*model.Donation, error) {
+			return &model.Donation{ID: id, DonorType: "user", DonorID: "u1", StripePaymentID: "pi_1"}, nil
+		},
+	}
+	svc := NewDonationService(repo, &mockSubscriptionManager{})
+	if _, err := svc.CreatePaymentMethodSession(context.Background(), "d1", "u1"); !errors.Is(err, ErrNotRecurring) {
+		t.Errorf("expected ErrNotRecurring, got %v", err)
+	}
+}
+
+func TestDonationService_CreatePaymentMethodSession_NoSubscriptionManager(t *testing.T) {
+	svc := NewDonationService(recurringDonationRepo(), nil)
+	if _, err := svc.CreatePaymentMethodSession(context.Background(), "d1", "u1"); !errors.Is(err, ErrBillingNotConfigured) {
+		t.Errorf("expected ErrBillingNotConfigured, got %v", err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// DonationService.TierBreakdown tests
+// ---------------------------------------------------------------------------
+
+func TestDonationService_TierBreakdown(t *testing.T) {
+	repo := &mockDonationRepository{
+		tierStatsFunc: func(ctx context.Context, projectID string) ([]*model.DonationTierStat, error) {
+			if projectID != "p1" {
+				t.Errorf("unexpected projectID %q", projectID)
+			}
+			return []*model.DonationTierStat{
+				{TierID: "old", Donations: 2, PaidAmount: 600},
+				{TierID: "t2", Donations: 3, ActiveRecurring: 3, PaidAmount: 9000},
+			}, nil
+		},
+	}
+	svc := NewDonationService(repo, nil)
+	project := &model.Project{ID: "p1", DonationTiers: []model.DonationTier{
+		{ID: "t1", Label: "応援", Amount: 500},
+		{ID: "t2", Label: "月額サポーター", Amount: 3000, IsRecurring: true},
+	}}
+
+	got, err := svc.TierBreakdown(context.Background(), project)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 tiers, got %d", len(got))
+	}
+	if got[0].TierID != "t1" || got[0].Label != "応援" || got[0].Donations != 0 || got[0].Removed {
+		t.Errorf("unexpected first tier: %+v", got[0])
+	}
+	if got[1].TierID != "t2" || got[1].Label != "月額サポーター" || !got[1].IsRecurring || got[1].ActiveRecurring != 3 || got[1].PaidAmount != 9000 {
+		t.Errorf("unexpected second tier: %+v", got[1])
+	}
+	if got[2].TierID != "old" || !got[2].Removed || got[2].Donations != 2 {
+		t.Errorf("expected the removed tier last, got %+v", got[2])
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Change history
+// ---------------------------------------------------------------------------
+
+type mockDonationChangeRepository struct {
+	memDonationChangeLog
+}
+
+func (m *mockDonationChangeRepository) ListByDonation(_ context.Context, donationID string) ([]*model.DonationChange, error) {
+	var list []*model.DonationChange
+	for _, c := range m.changes {
+		if c.DonationID == donationID {
+			list = append(list, c)
+		}
+	}
+	return list, nil
+}
+func (m *mockDonationChangeRepository) ListByProject(_ context.Context, _ string, _, _ int) ([]*model.DonationChange, error) {
+	return m.changes, nil
+}
+
+func TestDonationService_Patch_RecordsChange(t *testing.T) {
+	paused, amount := true, 2000
+	repo := &mockDonationRepository{
+		getByIDFunc: func(ctx context.Context, id string) (*model.Donation, error) {
+			return &model.Donation{ID: id, DonorType: "user", DonorID: "u1", Amount: 1000, IsRecurring: true}, nil
+		},
+		patchFunc: func(ctx context.Context, id string, patch model.DonationPatch) error { return nil },
+	}
+	changes := &mockDonationChangeRepository{}
+	svc := NewDonationService(repo, nil, WithDonationChangeLog(changes))
+
+	if err := svc.Patch(context.Background(), "d1", "u1", model.DonationPatch{Amount: &amount, Paused: &paused}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 変更のない PATCH は記録しない
+	same, message := 1000, "thanks"
+	if err := svc.Patch(context.Background(), "d1", "u1", model.DonationPatch{Amount: &same, NextBillingMessage: &message}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	list, err := svc.ListChanges(context.Background(), "d1", "u1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list) != 1 || list[0].Source != model.DonationChangeSourceDonor || len(list[0].Changes) != 2 ||
+		list[0].Changes["amount"] != (model.DonationFieldChange{From: 1000, To: 2000}) ||
+		list[0].Changes["paused"] != (model.DonationFieldChange{From: false, To: true}) {
+		t.Errorf("unexpected history %+v", list)
+	}
+	if _, err := svc.ListChanges(context.Background(), "d1", "other"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("expected ErrForbidden for another user, got %v", err)
+	}
+}
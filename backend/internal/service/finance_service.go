@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+	pkgstripe "github.com/givers/backend/pkg/stripe"
+)
+
+// ---------------------------------------------------------------------------
+// Minimal interfaces (only what FinanceService needs)
+// ---------------------------------------------------------------------------
+
+type FinanceStripeClient interface {
+	ListBalanceTransactions(ctx context.Context, accountID string, createdSince time.Time) ([]pkgstripe.BalanceTransaction, error)
+	ListPayouts(ctx context.Context, accountID string, createdSince time.Time) ([]pkgstripe.Payout, error)
+}
+
+type FinanceProjectRepo interface {
+	ListStripeAccountIDs(ctx context.Context) (map[string]string, error)
+}
+
+type FinancePaymentLedger interface {
+	RecordStripeBalance(ctx context.Context, paymentID, balanceTransactionID string, fee, net int) error
+}
+
+type FinanceRepo interface {
+	UpsertPayout(ctx context.Context, p *model.Payout) error
+	MonthlyByProject(ctx context.Context, projectID string) ([]*model.MonthlyFinance, error)
+}
+
+// ---------------------------------------------------------------------------
+// FinanceService
+// ---------------------------------------------------------------------------
+
+// FinanceSyncError is a Stripe account that could not be synced.
+type FinanceSyncError struct {
+	AccountID string `json:"account_id"` // empty for the platform account
+	Error     string `json:"error"`
+}
+
+// FinanceSyncReport summarises a finance sync.
+type FinanceSyncReport struct {
+	Accounts int `json:"accounts"` // accounts synced, including the platform
+	Payments int `json:"payments"` // ledger payments given their fee and net amount
+	// Unmatched counts payment balance transactions with no ledger payment, e.g. a charge
+	// whose webhook has not arrived yet. They are picked up by a later sync.
+	Unmatched int                `json:"unmatched"`
+	Payouts   int                `json:"payouts"`
+	Errors    []FinanceSyncError `json:"errors"`
+}
+
+// FinanceService brings what Stripe deducted and paid out into the database, so the
+// monthly finances of a project can show gross, fees, net and payouts.
+type FinanceService interface {
+	// Sync fetches the balance transactions and payouts created at or after since for every
+	// connected account, and the platform's balance transactions for projects without one.
+	// An account that fails is reported in the result and skipped.
+	Sync(ctx context.Context, since time.Time) (*FinanceSyncReport, error)
+	// MonthlyByProject returns the project's finances per month for the last 12 months.
+	MonthlyByProject(ctx context.Context, projectID string) ([]*model.MonthlyFinance, error)
+}
+
+type financeService struct {
+	client   FinanceStripeClient
+	projects FinanceProjectRepo
+	ledger   FinancePaymentLedger
+	repo     FinanceRepo
+}
+
+// NewFinanceService creates a FinanceService.
+func NewFinanceService(client FinanceStripeClient, projects FinanceProjectRepo, ledger FinancePaymentLedger, repo FinanceRepo) FinanceService {
+	return &financeService{client: client, projects: projects, ledger: ledger, repo: repo}
+}
+
+func (s *financeService) Sync(ctx context.Context, since time.Time) (*FinanceSyncReport, error) {
+	projectAccounts, err := s.projects.ListStripeAccountIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list stripe accounts: %w", err)
+	}
+	// 複数のプロジェクトが同じ連結アカウントを使うことがあるので重複を除く
+	seen := map[string]bool{}
+	accounts := []string{}
+	for _, accountID := range projectAccounts {
+		if !seen[accountID] {
+			seen[accountID] = true
+			accounts = append(accounts, accountID)
+		}
+	}
+	sort.Strings(accounts)
+
+	report := &FinanceSyncReport{Errors: []FinanceSyncError{}}
+	// プラットフォーム（""）は連結アカウントのないプロジェクトの決済。入金はプロジェクトに紐づかないので取得しない
+	for _, accountID := range append([]string{""}, accounts...) {
+		if err := s.syncAccount(ctx, accountID, since, report); err != nil {
+			report.Errors = append(report.Errors, FinanceSyncError{AccountID: accountID, Error: err.Error()})
+			continue
+		}
+		report.Accounts++
+	}
+	return report, nil
+}
+
+func (s *financeService) syncAccount(ctx context.Context, accountID string, since time.Time, report *FinanceSyncReport) error {
+	txs, err := s.client.ListBalanceTransactions(ctx, accountID, since)
+	if err != nil {
+		return fmt.Errorf("list balance transactions: %w", err)
+	}
+	for _, tx := range txs {
+		if !tx.IsPayment() || tx.PaymentIntent == "" {
+			continue
+		}
+		err := s.ledger.RecordStripeBalance(ctx, tx.PaymentIntent, tx.ID, tx.Fee, tx.Net)
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			report.Unmatched++
+		case err != nil:
+			return fmt.Errorf("record fee of %s: %w", tx.PaymentIntent, err)
+		default:
+			report.Payments++
+		}
+	}
+
+	if accountID == "" {
+		return nil
+	}
+	payouts, err := s.client.ListPayouts(ctx, accountID, since)
+	if err != nil {
+		return fmt.Errorf("list payouts: %w", err)
+	}
+	for _, po := range payouts {
+		if err := s.repo.UpsertPayout(ctx, &model.Payout{
+			ID:              po.ID,
+			StripeAccountID: accountID,
+			Amount:          po.Amount,
+			Currency:        po.Currency,
+			Status:          po.Status,
+			ArrivalDate:     po.ArrivalDate,
+		}); err != nil {
+			return fmt.Errorf("record payout %s: %w", po.ID, err)
+		}
+		report.Payouts++
+	}
+	return nil
+}
+
+func (s *financeService) MonthlyByProject(ctx context.Context, projectID string) ([]*model.MonthlyFinance, error) {
+	return s.repo.MonthlyByProject(ctx, projectID)
+}
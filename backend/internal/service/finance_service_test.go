@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+	pkgstripe "github.com/givers/backend/pkg/stripe"
+)
+
+// ---------------------------------------------------------------------------
+// Mocks
+// ---------------------------------------------------------------------------
+
+type mockFinanceStripeClient struct {
+	txs      map[string][]pkgstripe.BalanceTransaction // accountID → 残高の増減
+	payouts  map[string][]pkgstripe.Payout
+	failures map[string]error
+	calls    []string
+}
+
+func (m *mockFinanceStripeClient) ListBalanceTransactions(_ context.Context, accountID string, _ time.Time) ([]pkgstripe.BalanceTransaction, error) {
+	m.calls = append(m.calls, "txs:"+accountID)
+	if err := m.failures[accountID]; err != nil {
+		return nil, err
+	}
+	return m.txs[accountID], nil
+}
+
+func (m *mockFinanceStripeClient) ListPayouts(_ context.Context, accountID string, _ time.Time) ([]pkgstripe.Payout, error) {
+	m.calls = append(m.calls, "payouts:"+accountID)
+	return m.payouts[accountID], nil
+}
+
+type mockFinanceProjectRepo struct {
+	accounts map[string]string
+}
+
+func (m *mockFinanceProjectRepo) ListStripeAccountIDs(_ context.Context) (map[string]string, error) {
+	return m.accounts, nil
+}
+
+type mockFinanceLedger struct {
+	known    map[string]bool // 台帳にある PaymentIntent ID
+	recorded map[string][2]int
+}
+
+func (m *mockFinanceLedger) RecordStripeBalance(_ context.Context, paymentID, _ string, fee, net int) error {
+	if !m.known[paymentID] {
+		return repository.ErrNotFound
+	}
+	if m.recorded == nil {
+		m.recorded = map[string][2]int{}
+	}
+	m.recorded[paymentID] = [2]int{fee, net}
+	return nil
+}
+
+type mockFinanceRepo struct {
+	payouts []*model.Payout
+	monthly []*model.MonthlyFinance
+}
+
+func (m *mockFinanceRepo) UpsertPayout(_ context.Context, p *model.Payout) error {
+	m.payouts = append(m.payouts, p)
+	return nil
+}
+
+func (m *mockFinanceRepo) MonthlyByProject(_ context.Context, _ string) ([]*model.MonthlyFinance, error) {
+	return m.monthly, nil
+}
+
+// ---------------------------------------------------------------------------
+// FinanceService.Sync tests
+// ---------------------------------------------------------------------------
+
+func TestFinanceService_Sync(t *testing.T) {
+	client := &mockFinanceStripeClient{
+		txs: map[string][]pkgstripe.BalanceTransaction{
+			"": {{ID: "txn_p", Type: "charge", Amount: 1000, Fee: 36, Net: 964, PaymentIntent: "pi_platform"}},
+			"acct_1": {
+				{ID: "txn_1", Type: "charge", Amount: 3000, Fee: 108, Net: 2892, PaymentIntent: "pi_1"},
+				{ID: "txn_2", Type: "payment", Amount: 500, Fee: 18, Net: 482, PaymentIntent: "pi_unknown"},
+				{ID: "txn_3", Type: "payout", Amount: -2892, Net: -2892},
+			},
+		},
+		payouts: map[string][]pkgstripe.Payout{
+			"acct_1": {{ID: "po_1", Amount: 2892, Currency: "jpy", Status: "paid", ArrivalDate: time.Unix(1700000000, 0)}},
+		},
+	}
+	// p1 と p2 は同じ連結アカウントを使う
+	projects := &mockFinanceProjectRepo{accounts: map[string]string{"p1": "acct_1", "p2": "acct_1"}}
+	ledger := &mockFinanceLedger{known: map[string]bool{"pi_1": true, "pi_platform": true}}
+	repo := &mockFinanceRepo{}
+
+	report, err := NewFinanceService(client, projects, ledger, repo).Sync(context.Background(), time.Time{})
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if report.Accounts != 2 || report.Payments != 2 || report.Unmatched != 1 || report.Payouts != 1 || len(report.Errors) != 0 {
+		t.Errorf("report = %+v", report)
+	}
+	if got := ledger.recorded["pi_1"]; got != [2]int{108, 2892} {
+		t.Errorf("pi_1 = %v, want fee 108 and net 2892", got)
+	}
+	if len(repo.payouts) != 1 || repo.payouts[0].StripeAccountID != "acct_1" || repo.payouts[0].Amount != 2892 {
+		t.Errorf("payouts = %+v", repo.payouts)
+	}
+	// プラットフォームの入金はプロジェクトに紐づかないので取得しない
+	want := []string{"txs:", "txs:acct_1", "payouts:acct_1"}
+	if len(client.calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", client.calls, want)
+	}
+	for i := range want {
+		if client.calls[i] != want[i] {
+			t.Errorf("calls = %v, want %v", client.calls, want)
+			break
+		}
+	}
+}
+
+func TestFinanceService_Sync_SkipsFailingAccount(t *testing.T) {
+	client := &mockFinanceStripeClient{
+		txs: map[string][]pkgstripe.BalanceTransaction{
+			"acct_ok": {{ID: "txn_1", Type: "charge", Amount: 1000, Fee: 36, Net: 964, PaymentIntent: "pi_1"}},
+		},
+		failures: map[string]error{"acct_gone": errors.New("account revoked")},
+	}
+	projects := &mockFinanceProjectRepo{accounts: map[string]string{"p1": "acct_gone", "p2": "acct_ok"}}
+	ledger := &mockFinanceLedger{known: map[string]bool{"pi_1": true}}
+
+	report, err := NewFinanceService(client, projects, ledger, &mockFinanceRepo{}).Sync(context.Background(), time.Time{})
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if len(report.Errors) != 1 || report.Errors[0].AccountID != "acct_gone" {
+		t.Errorf("errors = %+v", report.Errors)
+	}
+	if report.Accounts != 2 || report.Payments != 1 {
+		t.Errorf("report = %+v, want the platform and acct_ok synced", report)
+	}
+}
@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+)
+
+// ErrInvalidManualDonation is returned when a manual donation has an invalid amount,
+// payment method or other field. The wrapped message describes the problem.
+var ErrInvalidManualDonation = errors.New("invalid manual donation")
+
+// ErrDonationVoided is returned when correcting or voiding a donation that is already voided.
+var ErrDonationVoided = errors.New("donation is voided")
+
+// manualDonationTextLimit は reference / donor_name の最大文字数（DB の VARCHAR(100)）
+const manualDonationTextLimit = 100
+
+// ---------------------------------------------------------------------------
+// Minimal interfaces (only what ManualDonationService needs)
+// ---------------------------------------------------------------------------
+
+type ManualDonationRepo interface {
+	GetByID(ctx context.Context, id string) (*model.Donation, error)
+	Create(ctx context.Context, d *model.Donation, entry *model.DonationAuditEntry) error
+	Update(ctx context.Context, d *model.Donation, entry *model.DonationAuditEntry) error
+	Void(ctx context.Context, id, reason string, entry *model.DonationAuditEntry) error
+	ListByProject(ctx context.Context, projectID string) ([]*model.Donation, error)
+	ListAudit(ctx context.Context, donationID string) ([]*model.DonationAuditEntry, error)
+}
+
+type ManualDonationUserGetter interface {
+	FindByID(ctx context.Context, id string) (*model.User, error)
+}
+
+type ManualDonationActivityRecorder interface {
+	Insert(ctx context.Context, a *model.ActivityItem) error
+}
+
+type ManualDonationMilestoneNotifier interface {
+	NotifyDonation(ctx context.Context, projectID string) error
+}
+
+// ---------------------------------------------------------------------------
+// ManualDonationService
+// ---------------------------------------------------------------------------
+
+// ManualDonationInput holds a donation received outside Stripe (bank transfer, cash, ...).
+type ManualDonationInput struct {
+	ProjectID     string
+	Amount        int
+	Currency      string // empty means "jpy"
+	PaymentMethod string // "bank_transfer", "cash" or "other"
+	Reference     string
+	DonorUserID   string // optional: attribute the donation to a registered user
+	DonorName     string // optional: display name for donors without an account
+	Message       string
+	ReceivedAt    *time.Time // nil means now
+}
+
+// ManualDonationService records, corrects and voids manual donations.
+// Manual donations are added to the payments ledger, so they count toward
+// monthly totals, the chart and milestones like Stripe donations do.
+// Callers must check that actorID is a project maintainer or a host.
+type ManualDonationService interface {
+	Record(ctx context.Context, actorID string, in ManualDonationInput) (*model.Donation, error)
+	// Update corrects a manual donation of projectID. Unchanged fields are not audited.
+	Update(ctx context.Context, actorID, projectID, id string, patch model.ManualDonationPatch) (*model.Donation, error)
+	// Void cancels a manual donation of projectID so it no longer counts toward totals.
+	Void(ctx context.Context, actorID, projectID, id, reason string) (*model.Donation, error)
+	ListByProject(ctx context.Context, projectID string) ([]*model.Donation, error)
+	ListAudit(ctx context.Context, projectID, id string) ([]*model.DonationAuditEntry, error)
+}
+
+type manualDonationService struct {
+	repo             ManualDonationRepo
+	users            ManualDonationUserGetter
+	activityRecorder ManualDonationActivityRecorder  // optional
+	milestone        ManualDonationMilestoneNotifier // optional
+	now              func() time.Time
+}
+
+// NewManualDonationService creates a ManualDonationService.
+// activityRecorder and milestone can be nil to skip the activity feed and milestone checks.
+func NewManualDonationService(
+	repo ManualDonationRepo,
+	users ManualDonationUserGetter,
+	activityRecorder ManualDonationActivityRecorder,
+	milestone ManualDonationMilestoneNotifier,
+) ManualDonationService {
+	return &manualDonationService{
+		repo:             repo,
+		users:            users,
+		activityRecorder: activityRecorder,
+		milestone:        milestone,
+		now:              time.Now,
+	}
+}
+
+// get は projectID に属する手動寄付を取得する（別プロジェクトの寄付は ErrNotFound）
+func (s *manualDonationService) get(ctx context.Context, projectID, id string) (*model.Donation, error) {
+	d, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if d.ProjectID != projectID {
+		return nil, repository.ErrNotFound
+	}
+	return d, nil
+}
+
+func (s *manualDonationService) Record(ctx context.Context, actorID string, in ManualDonationInput) (*model.Donation, error) {
+	currency := in.Currency
+	if currency == "" {
+		currency = "jpy"
+	}
+	d := &model.Donation{
+		ProjectID:     in.ProjectID,
+		DonorType:     "manual",
+		Amount:        in.Amount,
+		Currency:      currency,
+		Message:       in.Message,
+		PaymentMethod: in.PaymentMethod,
+		Reference:     in.Reference,
+		DonorName:     in.DonorName,
+		ReceivedAt:    in.ReceivedAt,
+		RecordedBy:    actorID,
+	}
+	if in.DonorUserID != "" {
+		d.DonorType = "user"
+		d.DonorID = in.DonorUserID
+	}
+	if err := s.validate(d); err != nil {
+		return nil, err
+	}
+	if d.DonorID != "" {
+		if _, err := s.users.FindByID(ctx, d.DonorID); errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("%w: donor_user_id does not match a user", ErrInvalidManualDonation)
+		} else if err != nil {
+			return nil, fmt.Errorf("find donor user: %w", err)
+		}
+	}
+
+	entry := &model.DonationAuditEntry{
+		ActorID: actorID,
+		Action:  model.DonationAuditCreate,
+		Changes: map[string]model.DonationFieldChange{
+			"amount":         {To: d.Amount},
+			"payment_method": {To: d.PaymentMethod},
+		},
+	}
+	if d.Reference != "" {
+		entry.Changes["reference"] = model.DonationFieldChange{To: d.Reference}
+	}
+	if d.DonorID != "" {
+		entry.Changes["donor_id"] = model.DonationFieldChange{To: d.DonorID}
+	}
+	if d.DonorName != "" {
+		entry.Changes["donor_name"] = model.DonationFieldChange{To: d.DonorName}
+	}
+	if d.ReceivedAt != nil {
+		entry.Changes["received_at"] = model.DonationFieldChange{To: *d.ReceivedAt}
+	}
+	if err := s.repo.Create(ctx, d, entry); err != nil {
+		return nil, fmt.Errorf("record manual donation: %w", err)
+	}
+
+	if s.activityRecorder != nil {
+		var actorName *string
+		if d.DonorID != "" {
+			actorName = &d.DonorID
+		}
+		amount := d.Amount
+		_ = s.activityRecorder.Insert(ctx, &model.ActivityItem{
+			Type:      "manual_donation",
+			ProjectID: d.ProjectID,
+			ActorName: actorName,
+			Amount:    &amount,
+			Message:   d.PaymentMethod,
+		})
+	}
+	s.notifyMilestone(ctx, d.ProjectID)
+	return d, nil
+}
+
+func (s *manualDonationService) Update(ctx context.Context, actorID, projectID, id string, patch model.ManualDonationPatch) (*model.Donation, error) {
+	d, err := s.get(ctx, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+	if d.VoidedAt != nil {
+		return nil, ErrDonationVoided
+	}
+
+	changes := map[string]model.DonationFieldChange{}
+	if patch.Amount != nil && *patch.Amount != d.Amount {
+		changes["amount"] = model.DonationFieldChange{From: d.Amount, To: *patch.Amount}
+		d.Amount = *patch.Amount
+	}
+	if patch.PaymentMethod != nil && *patch.PaymentMethod != d.PaymentMethod {
+		changes["payment_method"] = model.DonationFieldChange{From: d.PaymentMethod, To: *patch.PaymentMethod}
+		d.PaymentMethod = *patch.PaymentMethod
+	}
+	if patch.Reference != nil && *patch.Reference != d.Reference {
+		changes["reference"] = model.DonationFieldChange{From: d.Reference, To: *patch.Reference}
+		d.Reference = *patch.Reference
+	}
+	if patch.DonorName != nil && *patch.DonorName != d.DonorName {
+		changes["donor_name"] = model.DonationFieldChange{From: d.DonorName, To: *patch.DonorName}
+		d.DonorName = *patch.DonorName
+	}
+	if patch.Message != nil && *patch.Message != d.Message {
+		changes["message"] = model.DonationFieldChange{From: d.Message, To: *patch.Message}
+		d.Message = *patch.Message
+	}
+	if patch.ReceivedAt != nil && (d.ReceivedAt == nil || !patch.ReceivedAt.Equal(*d.ReceivedAt)) {
+		var from any
+		if d.ReceivedAt != nil {
+			from = *d.ReceivedAt
+		}
+		changes["received_at"] = model.DonationFieldChange{From: from, To: *patch.ReceivedAt}
+		d.ReceivedAt = patch.ReceivedAt
+	}
+	if len(changes) == 0 {
+		return d, nil
+	}
+	if err := s.validate(d); err != nil {
+		return nil, err
+	}
+
+	entry := &model.DonationAuditEntry{ActorID: actorID, Action: model.DonationAuditUpdate, Changes: changes}
+	if err := s.repo.Update(ctx, d, entry); err != nil {
+		return nil, fmt.Errorf("update manual donation: %w", err)
+	}
+	_, amountChanged := changes["amount"]
+	_, dateChanged := changes["received_at"]
+	if amountChanged || dateChanged {
+		s.notifyMilestone(ctx, d.ProjectID)
+	}
+	return d, nil
+}
+
+func (s *manualDonationService) Void(ctx context.Context, actorID, projectID, id, reason string) (*model.Donation, error) {
+	d, err := s.get(ctx, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+	if d.VoidedAt != nil {
+		return nil, ErrDonationVoided
+	}
+
+	entry := &model.DonationAuditEntry{ActorID: actorID, Action: model.DonationAuditVoid, Reason: reason}
+	if err := s.repo.Void(ctx, id, reason, entry); err != nil {
+		return nil, fmt.Errorf("void manual donation: %w", err)
+	}
+	voidedAt := entry.CreatedAt
+	d.VoidedAt = &voidedAt
+	d.VoidReason = reason
+	s.notifyMilestone(ctx, d.ProjectID)
+	return d, nil
+}
+
+func (s *manualDonationService) ListByProject(ctx context.Context, projectID string) ([]*model.Donation, error) {
+	return s.repo.ListByProject(ctx, projectID)
+}
+
+func (s *manualDonationService) ListAudit(ctx context.Context, projectID, id string) ([]*model.DonationAuditEntry, error) {
+	if _, err := s.get(ctx, projectID, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListAudit(ctx, id)
+}
+
+// validate は手動寄付の入力値を検証する
+func (s *manualDonationService) validate(d *model.Donation) error {
+	switch {
+	case d.Amount <= 0:
+		return fmt.Errorf("%w: amount must be greater than 0", ErrInvalidManualDonation)
+	case !model.IsManualPaymentMethod(d.PaymentMethod):
+		return fmt.Errorf("%w: payment_method must be bank_transfer, cash or other", ErrInvalidManualDonation)
+	case utf8.RuneCountInString(d.Reference) > manualDonationTextLimit:
+		return fmt.Errorf("%w: reference is too long", ErrInvalidManualDonation)
+	case utf8.RuneCountInString(d.DonorName) > manualDonationTextLimit:
+		return fmt.Errorf("%w: donor_name is too long", ErrInvalidManualDonation)
+	case d.ReceivedAt != nil && d.ReceivedAt.After(s.now()):
+		return fmt.Errorf("%w: received_at must not be in the future", ErrInvalidManualDonation)
+	}
+	return nil
+}
+
+// notifyMilestone は手動寄付で月間合計が変わったときにマイルストーンを確認する（失敗しても無視）
+func (s *manualDonationService) notifyMilestone(ctx context.Context, projectID string) {
+	if s.milestone == nil {
+		return
+	}
+	_ = s.milestone.NotifyDonation(ctx, projectID)
+}
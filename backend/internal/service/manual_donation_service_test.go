@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+)
+
+// ---------------------------------------------------------------------------
+// Mocks
+// ---------------------------------------------------------------------------
+
+// memManualDonationRepo は ManualDonationRepo のインメモリ実装（台帳の代わりに ledger を持つ）
+type memManualDonationRepo struct {
+	donations map[string]*model.Donation
+	ledger    map[string]int // donation ID → 台帳に記録された金額
+	audit     []*model.DonationAuditEntry
+}
+
+func newMemManualDonationRepo() *memManualDonationRepo {
+	return &memManualDonationRepo{donations: map[string]*model.Donation{}, ledger: map[string]int{}}
+}
+
+func (m *memManualDonationRepo) GetByID(_ context.Context, id string) (*model.Donation, error) {
+	d, ok := m.donations[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	cp := *d
+	return &cp, nil
+}
+
+func (m *memManualDonationRepo) Create(_ context.Context, d *model.Donation, entry *model.DonationAuditEntry) error {
+	d.ID = "md-1"
+	if d.ReceivedAt == nil {
+		now := time.Now()
+		d.ReceivedAt = &now
+	}
+	cp := *d
+	m.donations[d.ID] = &cp
+	m.ledger[d.ID] = d.Amount
+	entry.DonationID = d.ID
+	m.audit = append(m.audit, entry)
+	return nil
+}
+
+func (m *memManualDonationRepo) Update(_ context.Context, d *model.Donation, entry *model.DonationAuditEntry) error {
+	cp := *d
+	m.donations[d.ID] = &cp
+	m.ledger[d.ID] = d.Amount
+	entry.DonationID = d.ID
+	m.audit = append(m.audit, entry)
+	return nil
+}
+
+func (m *memManualDonationRepo) Void(_ context.Context, id, reason string, entry *model.DonationAuditEntry) error {
+	now := time.Now()
+	m.donations[id].VoidedAt = &now
+	m.donations[id].VoidReason = reason
+	delete(m.ledger, id)
+	entry.DonationID = id
+	entry.CreatedAt = now
+	m.audit = append(m.audit, entry)
+	return nil
+}
+
+func (m *memManualDonationRepo) ListByProject(_ context.Context, projectID string) ([]*model.Donation, error) {
+	var list []*model.Donation
+	for _, d := range m.donations {
+		if d.ProjectID == projectID {
+			list = append(list, d)
+		}
+	}
+	return list, nil
+}
+
+func (m *memManualDonationRepo) ListAudit(_ context.Context, donationID string) ([]*model.DonationAuditEntry, error) {
+	var list []*model.DonationAuditEntry
+	for _, e := range m.audit {
+		if e.DonationID == donationID {
+			list = append(list, e)
+		}
+	}
+	return list, nil
+}
+
+// mockManualDonationUsers は登録済みのユーザー ID の集合
+type mockManualDonationUsers map[string]bool
+
+func (m mockManualDonationUsers) FindByID(_ context.Context, id string) (*model.User, error) {
+	if !m[id] {
+		return nil, repository.ErrNotFound
+	}
+	return &model.User{ID: id}, nil
+}
+
+type mockManualDonationMilestone struct {
+	notified []string
+}
+
+func (m *mockManualDonationMilestone) NotifyDonation(_ context.Context, projectID string) error {
+	m.notified = append(m.notified, projectID)
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Tests
+// ---------------------------------------------------------------------------
+
+func TestManualDonationService_Record(t *testing.T) {
+	repo := newMemManualDonationRepo()
+	var activities []*model.ActivityItem
+	activity := &mockStripeActivityRecorder{insertFunc: func(_ context.Context, a *model.ActivityItem) error {
+		activities = append(activities, a)
+		return nil
+	}}
+	milestone := &mockManualDonationMilestone{}
+	svc := NewManualDonationService(repo, mockManualDonationUsers{"u-9": true}, activity, milestone)
+
+	d, err := svc.Record(context.Background(), "owner-1", ManualDonationInput{
+		ProjectID:     "p1",
+		Amount:        5000,
+		PaymentMethod: model.PaymentMethodBankTransfer,
+		Reference:     "振込 0012",
+		DonorName:     "山田",
+	})
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if d.DonorType != "manual" || d.Currency != "jpy" || d.RecordedBy != "owner-1" || !d.IsManual() {
+		t.Errorf("donation = %+v", d)
+	}
+	if repo.ledger[d.ID] != 5000 {
+		t.Errorf("ledger = %v, want 5000", repo.ledger)
+	}
+	if len(repo.audit) != 1 || repo.audit[0].Action != model.DonationAuditCreate || repo.audit[0].ActorID != "owner-1" {
+		t.Errorf("audit = %+v", repo.audit)
+	}
+	if len(activities) != 1 || activities[0].Type != "manual_donation" || activities[0].Message != model.PaymentMethodBankTransfer {
+		t.Errorf("activity = %+v", activities)
+	}
+	if len(milestone.notified) != 1 || milestone.notified[0] != "p1" {
+		t.Errorf("milestone notified = %v", milestone.notified)
+	}
+}
+
+func TestManualDonationService_Record_AttributesDonorUser(t *testing.T) {
+	repo := newMemManualDonationRepo()
+	svc := NewManualDonationService(repo, mockManualDonationUsers{"u-9": true}, nil, nil)
+
+	d, err := svc.Record(context.Background(), "host-1", ManualDonationInput{
+		ProjectID: "p1", Amount: 1000, PaymentMethod: model.PaymentMethodCash, DonorUserID: "u-9",
+	})
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if d.DonorType != "user" || d.DonorID != "u-9" {
+		t.Errorf("donor = %s/%s, want user/u-9", d.DonorType, d.DonorID)
+	}
+}
+
+func TestManualDonationService_Record_Invalid(t *testing.T) {
+	future := time.Now().Add(48 * time.Hour)
+	tests := []struct {
+		name string
+		in   ManualDonationInput
+	}{
+		{"zero amount", ManualDonationInput{ProjectID: "p1", Amount: 0, PaymentMethod: model.PaymentMethodCash}},
+		{"stripe method", ManualDonationInput{ProjectID: "p1", Amount: 100, PaymentMethod: model.PaymentMethodStripe}},
+		{"unknown method", ManualDonationInput{ProjectID: "p1", Amount: 100, PaymentMethod: "paypal"}},
+		{"future date", ManualDonationInput{ProjectID: "p1", Amount: 100, PaymentMethod: model.PaymentMethodCash, ReceivedAt: &future}},
+		{"unknown donor user", ManualDonationInput{ProjectID: "p1", Amount: 100, PaymentMethod: model.PaymentMethodCash, DonorUserID: "u-missing"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemManualDonationRepo()
+			svc := NewManualDonationService(repo, mockManualDonationUsers{"u-9": true}, nil, nil)
+			_, err := svc.Record(context.Background(), "owner-1", tt.in)
+			if !errors.Is(err, ErrInvalidManualDonation) {
+				t.Errorf("err = %v, want ErrInvalidManualDonation", err)
+			}
+			if len(repo.donations) != 0 {
+				t.Error("invalid donation must not be stored")
+			}
+		})
+	}
+}
+
+func TestManualDonationService_Update_AuditsChangedFields(t *testing.T) {
+	repo := newMemManualDonationRepo()
+	milestone := &mockManualDonationMilestone{}
+	svc := NewManualDonationService(repo, mockManualDonationUsers{"u-9": true}, nil, milestone)
+	ctx := context.Background()
+
+	d, _ := svc.Record(ctx, "owner-1", ManualDonationInput{ProjectID: "p1", Amount: 3000, PaymentMethod: model.PaymentMethodCash, Reference: "R1"})
+
+	amount := 3500
+	ref := "R1" // 変更なし
+	updated, err := svc.Update(ctx, "owner-1", "p1", d.ID, model.ManualDonationPatch{Amount: &amount, Reference: &ref})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if updated.Amount != 3500 || repo.ledger[d.ID] != 3500 {
+		t.Errorf("amount = %d, ledger = %d, want 3500", updated.Amount, repo.ledger[d.ID])
+	}
+	last := repo.audit[len(repo.audit)-1]
+	if last.Action != model.DonationAuditUpdate || len(last.Changes) != 1 {
+		t.Fatalf("audit = %+v", last)
+	}
+	if c := last.Changes["amount"]; c.From != 3000 || c.To != 3500 {
+		t.Errorf("amount change = %+v", c)
+	}
+	if len(milestone.notified) != 2 {
+		t.Errorf("milestone notified %d times, want 2", len(milestone.notified))
+	}
+}
+
+func TestManualDonationService_Update_NoChanges(t *testing.T) {
+	repo := newMemManualDonationRepo()
+	svc := NewManualDonationService(repo, mockManualDonationUsers{"u-9": true}, nil, nil)
+	ctx := context.Background()
+
+	d, _ := svc.Record(ctx, "owner-1", ManualDonationInput{ProjectID: "p1", Amount: 3000, PaymentMethod: model.PaymentMethodCash})
+	amount := 3000
+	if _, err := svc.Update(ctx, "owner-1", "p1", d.ID, model.ManualDonationPatch{Amount: &amount}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if len(repo.audit) != 1 {
+		t.Errorf("audit entries = %d, want 1 (create only)", len(repo.audit))
+	}
+}
+
+func TestManualDonationService_Update_OtherProject(t *testing.T) {
+	repo := newMemManualDonationRepo()
+	svc := NewManualDonationService(repo, mockManualDonationUsers{"u-9": true}, nil, nil)
+	ctx := context.Background()
+
+	d, _ := svc.Record(ctx, "owner-1", ManualDonationInput{ProjectID: "p1", Amount: 3000, PaymentMethod: model.PaymentMethodCash})
+	amount := 1
+	if _, err := svc.Update(ctx, "owner-2", "p2", d.ID, model.ManualDonationPatch{Amount: &amount}); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestManualDonationService_Void(t *testing.T) {
+	repo := newMemManualDonationRepo()
+	milestone := &mockManualDonationMilestone{}
+	svc := NewManualDonationService(repo, mockManualDonationUsers{"u-9": true}, nil, milestone)
+	ctx := context.Background()
+
+	d, _ := svc.Record(ctx, "owner-1", ManualDonationInput{ProjectID: "p1", Amount: 3000, PaymentMethod: model.PaymentMethodCash})
+	voided, err := svc.Void(ctx, "host-1", "p1", d.ID, "二重記録")
+	if err != nil {
+		t.Fatalf("Void: %v", err)
+	}
+	if voided.VoidedAt == nil || voided.VoidReason != "二重記録" || voided.NetAmount() != 0 {
+		t.Errorf("voided = %+v", voided)
+	}
+	if _, ok := repo.ledger[d.ID]; ok {
+		t.Error("voided donation must be removed from the ledger")
+	}
+	last := repo.audit[len(repo.audit)-1]
+	if last.Action != model.DonationAuditVoid || last.ActorID != "host-1" || last.Reason != "二重記録" {
+		t.Errorf("audit = %+v", last)
+	}
+
+	// 取消済みの寄付は修正・再取消できない
+	amount := 1
+	if _, err := svc.Update(ctx, "owner-1", "p1", d.ID, model.ManualDonationPatch{Amount: &amount}); !errors.Is(err, ErrDonationVoided) {
+		t.Errorf("Update after void: err = %v, want ErrDonationVoided", err)
+	}
+	if _, err := svc.Void(ctx, "owner-1", "p1", d.ID, "again"); !errors.Is(err, ErrDonationVoided) {
+		t.Errorf("Void twice: err = %v, want ErrDonationVoided", err)
+	}
+
+	// 記録時と取消時に1回ずつ。失敗した操作では通知しない
+	if len(milestone.notified) != 2 || milestone.notified[1] != "p1" {
+		t.Errorf("milestone notified = %v", milestone.notified)
+	}
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+)
+
+// ErrInvalidMatchingCampaign is returned when a campaign has an invalid sponsor, ratio,
+// cap or period. The wrapped message describes the problem.
+var ErrInvalidMatchingCampaign = errors.New("invalid matching campaign")
+
+// ErrMatchingCampaignFinished is returned when cancelling a campaign that is already cancelled or closed.
+var ErrMatchingCampaignFinished = errors.New("matching campaign is already finished")
+
+// matchingMaxRatioPercent は比率の上限（10 倍）
+const matchingMaxRatioPercent = 1000
+
+// ---------------------------------------------------------------------------
+// Minimal interfaces (only what MatchingService needs)
+// ---------------------------------------------------------------------------
+
+type MatchingRepo interface {
+	Create(ctx context.Context, c *model.MatchingCampaign) error
+	GetByID(ctx context.Context, id string) (*model.MatchingCampaign, error)
+	ListByProject(ctx context.Context, projectID string) ([]*model.MatchingCampaign, error)
+	ListActiveByProject(ctx context.Context, projectID string, t time.Time) ([]*model.MatchingCampaign, error)
+	Cancel(ctx context.Context, id string) error
+	ApplyPayment(ctx context.Context, p *model.DonationPayment) ([]*model.MatchingContribution, error)
+	ReleasePayment(ctx context.Context, p *model.DonationPayment) ([]*model.MatchingContribution, error)
+	ListContributions(ctx context.Context, campaignID string) ([]*model.MatchingContribution, error)
+	MonthlyMatchedByProject(ctx context.Context, projectID string) ([]*model.MonthlySum, error)
+	CloseEnded(ctx context.Context, now time.Time) ([]*model.MatchingCampaign, error)
+}
+
+type MatchingProjectGetter interface {
+	GetByID(ctx context.Context, id string) (*model.Project, error)
+}
+
+type MatchingUserGetter interface {
+	FindByID(ctx context.Context, id string) (*model.User, error)
+}
+
+type MatchingActivityRecorder interface {
+	Insert(ctx context.Context, a *model.ActivityItem) error
+}
+
+// ---------------------------------------------------------------------------
+// MatchingService
+// ---------------------------------------------------------------------------
+
+// MatchingCampaignInput holds a sponsor's matching pledge for a project.
+type MatchingCampaignInput struct {
+	ProjectID    string
+	SponsorName  string
+	SponsorEmail string // optional, shown to the owner/host only
+	RatioPercent int    // 0 means 100 (one-to-one)
+	CapAmount    int
+	Currency     string     // empty means "jpy"
+	StartsAt     *time.Time // nil means now
+	EndsAt       time.Time
+}
+
+// MatchingService manages matching-gift campaigns. Ledger payments are matched
+// as the Stripe webhook records them; the sponsor settles the total with the
+// project owner after the period ends, using the summary.
+// Callers must check that actorID is a project maintainer or a host.
+type MatchingService interface {
+	Create(ctx context.Context, actorID string, in MatchingCampaignInput) (*model.MatchingCampaign, error)
+	ListByProject(ctx context.Context, projectID string) ([]*model.MatchingCampaign, error)
+	// Cancel stops a campaign of projectID. Amounts matched so far are still owed.
+	Cancel(ctx context.Context, projectID, id string) (*model.MatchingCampaign, error)
+	// ActiveByProject returns the running campaigns of a project without private sponsor details.
+	ActiveByProject(ctx context.Context, projectID string) ([]*model.MatchingCampaign, error)
+	MonthlyMatchedByProject(ctx context.Context, projectID string) ([]*model.MonthlySum, error)
+	// ApplyPayment matches a ledger payment and records a "matching" activity per campaign.
+	ApplyPayment(ctx context.Context, p *model.DonationPayment) error
+	// ReleasePayment reduces what campaigns added to a payment after a refund or a lost dispute,
+	// so the sponsor is not charged for it and the cap can match other donations.
+	ReleasePayment(ctx context.Context, p *model.DonationPayment) error
+	// Summary returns the statement of a campaign of projectID.
+	Summary(ctx context.Context, projectID, id string) (*model.MatchingSummary, error)
+	// SponsorSummary returns the statement of a campaign for the holder of its summary token.
+	// Returns ErrNotFound if the token does not match.
+	SponsorSummary(ctx context.Context, id, token string) (*model.MatchingSummary, error)
+	// CloseEnded closes the campaigns whose period has ended and returns their final summaries.
+	CloseEnded(ctx context.Context) ([]*model.MatchingSummary, error)
+}
+
+type matchingService struct {
+	repo             MatchingRepo
+	projects         MatchingProjectGetter
+	users            MatchingUserGetter
+	activityRecorder MatchingActivityRecorder // optional
+	now              func() time.Time
+}
+
+// NewMatchingService creates a MatchingService. activityRecorder can be nil to skip the activity feed.
+func NewMatchingService(repo MatchingRepo, projects MatchingProjectGetter, users MatchingUserGetter, activityRecorder MatchingActivityRecorder) MatchingService {
+	return &matchingService{
+		repo:             repo,
+		projects:         projects,
+		users:            users,
+		activityRecorder: activityRecorder,
+		now:              time.Now,
+	}
+}
+
+func (s *matchingService) Create(ctx context.Context, actorID string, in MatchingCampaignInput) (*model.MatchingCampaign, error) {
+	c := &model.MatchingCampaign{
+		ProjectID:    in.ProjectID,
+		SponsorName:  strings.TrimSpace(in.SponsorName),
+		SponsorEmail: strings.TrimSpace(in.SponsorEmail),
+		RatioPercent: in.RatioPercent,
+		CapAmount:    in.CapAmount,
+		Currency:     strings.ToLower(in.Currency),
+		StartsAt:     s.now(),
+		EndsAt:       in.EndsAt,
+		CreatedBy:    actorID,
+	}
+	if c.RatioPercent == 0 {
+		c.RatioPercent = 100
+	}
+	if c.Currency == "" {
+		c.Currency = "jpy"
+	}
+	if in.StartsAt != nil {
+		c.StartsAt = *in.StartsAt
+	}
+	if err := s.validate(c); err != nil {
+		return nil, err
+	}
+
+	token, err := newSummaryToken()
+	if err != nil {
+		return nil, fmt.Errorf("generate summary token: %w", err)
+	}
+	c.SummaryToken = token
+	if err := s.repo.Create(ctx, c); err != nil {
+		return nil, fmt.Errorf("create matching campaign: %w", err)
+	}
+	return c, nil
+}
+
+func (s *matchingService) validate(c *model.MatchingCampaign) error {
+	switch {
+	case c.SponsorName == "":
+		return fmt.Errorf("%w: sponsor_name is required", ErrInvalidMatchingCampaign)
+	case utf8.RuneCountInString(c.SponsorName) > 100:
+		return fmt.Errorf("%w: sponsor_name is too long", ErrInvalidMatchingCampaign)
+	case c.SponsorEmail != "" && (!strings.Contains(c.SponsorEmail, "@") || len(c.SponsorEmail) > 255):
+		return fmt.Errorf("%w: sponsor_email is invalid", ErrInvalidMatchingCampaign)
+	case c.RatioPercent < 1 || c.RatioPercent > matchingMaxRatioPercent:
+		return fmt.Errorf("%w: ratio_percent must be between 1 and %d", ErrInvalidMatchingCampaign, matchingMaxRatioPercent)
+	case c.CapAmount <= 0:
+		return fmt.Errorf("%w: cap_amount must be greater than 0", ErrInvalidMatchingCampaign)
+	case len(c.Currency) != 3:
+		return fmt.Errorf("%w: currency must be a 3-letter code", ErrInvalidMatchingCampaign)
+	case !c.EndsAt.After(c.StartsAt):
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidMatchingCampaign)
+	case !c.EndsAt.After(s.now()):
+		return fmt.Errorf("%w: ends_at must be in the future", ErrInvalidMatchingCampaign)
+	}
+	return nil
+}
+
+// newSummaryToken はスポンサーに共有する明細 URL 用のランダムトークンを生成する
+func newSummaryToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (s *matchingService) ListByProject(ctx context.Context, projectID string) ([]*model.MatchingCampaign, error) {
+	return s.repo.ListByProject(ctx, projectID)
+}
+
+// get は projectID に属するキャンペーンを取得する（別プロジェクトのキャンペーンは ErrNotFound）
+func (s *matchingService) get(ctx context.Context, projectID, id string) (*model.MatchingCampaign, error) {
+	c, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.ProjectID != projectID {
+		return nil, repository.ErrNotFound
+	}
+	return c, nil
+}
+
+func (s *matchingService) Cancel(ctx context.Context, projectID, id string) (*model.MatchingCampaign, error) {
+	c, err := s.get(ctx, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.CancelledAt != nil || c.ClosedAt != nil {
+		return nil, ErrMatchingCampaignFinished
+	}
+	if err := s.repo.Cancel(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrMatchingCampaignFinished
+		}
+		return nil, fmt.Errorf("cancel matching campaign: %w", err)
+	}
+	now := s.now()
+	c.CancelledAt = &now
+	return c, nil
+}
+
+func (s *matchingService) ActiveByProject(ctx context.Context, projectID string) ([]*model.MatchingCampaign, error) {
+	list, err := s.repo.ListActiveByProject(ctx, projectID, s.now())
+	if err != nil {
+		return nil, err
+	}
+	public := make([]*model.MatchingCampaign, len(list))
+	for i, c := range list {
+		public[i] = c.Public()
+	}
+	return public, nil
+}
+
+func (s *matchingService) MonthlyMatchedByProject(ctx context.Context, projectID string) ([]*model.MonthlySum, error) {
+	return s.repo.MonthlyMatchedByProject(ctx, projectID)
+}
+
+func (s *matchingService) ApplyPayment(ctx context.Context, p *model.DonationPayment) error {
+	contributions, err := s.repo.ApplyPayment(ctx, p)
+	if err != nil {
+		return fmt.Errorf("match payment: %w", err)
+	}
+	if s.activityRecorder == nil {
+		return nil
+	}
+	for _, mc := range contributions {
+		c, err := s.repo.GetByID(ctx, mc.CampaignID)
+		if err != nil {
+			continue
+		}
+		amount := mc.MatchedAmount
+		_ = s.activityRecorder.Insert(ctx, &model.ActivityItem{
+			Type:      "matching",
+			ProjectID: p.ProjectID,
+			Amount:    &amount,
+			Message:   c.SponsorName,
+		})
+	}
+	return nil
+}
+
+func (s *matchingService) ReleasePayment(ctx context.Context, p *model.DonationPayment) error {
+	if _, err := s.repo.ReleasePayment(ctx, p); err != nil {
+		return fmt.Errorf("release matching: %w", err)
+	}
+	return nil
+}
+
+func (s *matchingService) Summary(ctx context.Context, projectID, id string) (*model.MatchingSummary, error) {
+	c, err := s.get(ctx, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.summarize(ctx, c)
+}
+
+func (s *matchingService) SponsorSummary(ctx context.Context, id, token string) (*model.MatchingSummary, error) {
+	c, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(c.SummaryToken)) != 1 {
+		return nil, repository.ErrNotFound
+	}
+	return s.summarize(ctx, c)
+}
+
+func (s *matchingService) CloseEnded(ctx context.Context) ([]*model.MatchingSummary, error) {
+	closed, err := s.repo.CloseEnded(ctx, s.now())
+	if err != nil {
+		return nil, fmt.Errorf("close matching campaigns: %w", err)
+	}
+	summaries := make([]*model.MatchingSummary, 0, len(closed))
+	for _, c := range closed {
+		sum, err := s.summarize(ctx, c)
+		if err != nil {
+			return summaries, err
+		}
+		summaries = append(summaries, sum)
+	}
+	return summaries, nil
+}
+
+// summarize はキャンペーンの明細（上乗せした入金の一覧と請求額）を作る
+func (s *matchingService) summarize(ctx context.Context, c *model.MatchingCampaign) (*model.MatchingSummary, error) {
+	contributions, err := s.repo.ListContributions(ctx, c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list matching contributions: %w", err)
+	}
+	sum := &model.MatchingSummary{
+		Campaign:      c,
+		Contributions: contributions,
+		Final:         c.ClosedAt != nil || c.CancelledAt != nil,
+	}
+	for _, mc := range contributions {
+		sum.DonationsTotal += mc.DonationAmount
+		sum.AmountOwed += mc.MatchedAmount
+	}
+
+	project, err := s.projects.GetByID(ctx, c.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("find project: %w", err)
+	}
+	sum.ProjectName = project.Name
+	if owner, err := s.users.FindByID(ctx, project.OwnerID); err == nil {
+		sum.OwnerName = owner.Name
+	}
+	return sum, nil
+}
@@ -0,0 +1,505 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+)
+
+// ---------------------------------------------------------------------------
+// Mocks
+// ---------------------------------------------------------------------------
+
+// memMatchingRepo は MatchingRepo のインメモリ実装（上乗せの計算は pg 実装と同じく MatchFor を使う）
+type memMatchingRepo struct {
+	campaigns     map[string]*model.MatchingCampaign
+	contributions []*model.MatchingContribution
+	original      map[string]int // contribution ID -> 入金全額に対する当初の上乗せ額
+	applyErr      error
+}
+
+func newMemMatchingRepo() *memMatchingRepo {
+	return &memMatchingRepo{campaigns: map[string]*model.MatchingCampaign{}, original: map[string]int{}}
+}
+
+func (m *memMatchingRepo) Create(_ context.Context, c *model.MatchingCampaign) error {
+	c.ID = fmt.Sprintf("mc-%d", len(m.campaigns)+1)
+	cp := *c
+	m.campaigns[c.ID] = &cp
+	return nil
+}
+
+func (m *memMatchingRepo) GetByID(_ context.Context, id string) (*model.MatchingCampaign, error) {
+	c, ok := m.campaigns[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	cp := *c
+	return &cp, nil
+}
+
+func (m *memMatchingRepo) ListByProject(_ context.Context, projectID string) ([]*model.MatchingCampaign, error) {
+	var list []*model.MatchingCampaign
+	for _, c := range m.campaigns {
+		if c.ProjectID == projectID {
+			list = append(list, c)
+		}
+	}
+	return list, nil
+}
+
+func (m *memMatchingRepo) ListActiveByProject(ctx context.Context, projectID string, t time.Time) ([]*model.MatchingCampaign, error) {
+	var list []*model.MatchingCampaign
+	for _, c := range m.campaigns {
+		if c.ProjectID == projectID && c.IsActiveAt(t) {
+			list = append(list, c)
+		}
+	}
+	return list, nil
+}
+
+func (m *memMatchingRepo) Cancel(_ context.Context, id string) error {
+	c, ok := m.campaigns[id]
+	if !ok || c.CancelledAt != nil || c.ClosedAt != nil {
+		return repository.ErrNotFound
+	}
+	now := time.Now()
+	c.CancelledAt = &now
+	return nil
+}
+
+func (m *memMatchingRepo) ApplyPayment(_ context.Context, p *model.DonationPayment) ([]*model.MatchingContribution, error) {
+	if m.applyErr != nil {
+		return nil, m.applyErr
+	}
+	var created []*model.MatchingContribution
+	for _, c := range m.campaigns {
+		if c.ProjectID != p.ProjectID || c.Currency != p.Currency || !c.IsActiveAt(p.PaidAt) {
+			continue
+		}
+		if m.matched(c.ID, p.ID) {
+			continue
+		}
+		matched := c.MatchFor(p.Amount)
+		if matched <= 0 {
+			continue
+		}
+		mc := &model.MatchingContribution{
+			ID:             fmt.Sprintf("mcc-%d", len(m.contributions)+1),
+			CampaignID:     c.ID,
+			PaymentID:      p.ID,
+			DonationAmount: p.Amount,
+			MatchedAmount:  matched,
+			PaidAt:         p.PaidAt,
+		}
+		c.MatchedAmount += matched
+		m.contributions = append(m.contributions, mc)
+		m.original[mc.ID] = matched
+		created = append(created, mc)
+	}
+	return created, nil
+}
+
+func (m *memMatchingRepo) ReleasePayment(_ context.Context, p *model.DonationPayment) ([]*model.MatchingContribution, error) {
+	if m.applyErr != nil {
+		return nil, m.applyErr
+	}
+	retained := p.RetainedAmount()
+	var released []*model.MatchingContribution
+	for _, mc := range m.contributions {
+		if mc.PaymentID != p.ID {
+			continue
+		}
+		matched := 0
+		if p.Amount > 0 {
+			matched = m.original[mc.ID] * retained / p.Amount
+		}
+		if matched >= mc.MatchedAmount {
+			continue
+		}
+		m.campaigns[mc.CampaignID].MatchedAmount -= mc.MatchedAmount - matched
+		mc.MatchedAmount = matched
+		mc.DonationAmount = min(mc.DonationAmount, retained)
+		released = append(released, mc)
+	}
+	return released, nil
+}
+
+func (m *memMatchingRepo) matched(campaignID, paymentID string) bool {
+	for _, mc := range m.contributions {
+		if mc.CampaignID == campaignID && mc.PaymentID == paymentID {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *memMatchingRepo) ListContributions(_ context.Context, campaignID string) ([]*model.MatchingContribution, error) {
+	var list []*model.MatchingContribution
+	for _, mc := range m.contributions {
+		if mc.CampaignID == campaignID {
+			list = append(list, mc)
+		}
+	}
+	return list, nil
+}
+
+func (m *memMatchingRepo) MonthlyMatchedByProject(_ context.Context, _ string) ([]*model.MonthlySum, error) {
+	return nil, nil
+}
+
+func (m *memMatchingRepo) CloseEnded(_ context.Context, now time.Time) ([]*model.MatchingCampaign, error) {
+	var closed []*model.MatchingCampaign
+	for _, c := range m.campaigns {
+		if c.ClosedAt == nil && c.CancelledAt == nil && !c.EndsAt.After(now) {
+			c.ClosedAt = &now
+			cp := *c
+			closed = append(closed, &cp)
+		}
+	}
+	return closed, nil
+}
+
+type mockMatchingProjects struct{}
+
+func (mockMatchingProjects) GetByID(_ context.Context, id string) (*model.Project, error) {
+	return &model.Project{ID: id, Name: "Project " + id, OwnerID: "owner-1"}, nil
+}
+
+type mockMatchingUsers struct{}
+
+func (mockMatchingUsers) FindByID(_ context.Context, id string) (*model.User, error) {
+	return &model.User{ID: id, Name: "オーナー"}, nil
+}
+
+var matchingTestNow = time.Date(2026, 4, 10, 12, 0, 0, 0, time.UTC)
+
+func newTestMatchingService(repo MatchingRepo, recorder MatchingActivityRecorder) *matchingService {
+	s := NewMatchingService(repo, mockMatchingProjects{}, mockMatchingUsers{}, recorder).(*matchingService)
+	s.now = func() time.Time { return matchingTestNow }
+	return s
+}
+
+func createTestCampaign(t *testing.T, svc MatchingService, ratio, capAmount int) *model.MatchingCampaign {
+	t.Helper()
+	start := matchingTestNow.Add(-24 * time.Hour)
+	c, err := svc.Create(context.Background(), "owner-1", MatchingCampaignInput{
+		ProjectID:    "p1",
+		SponsorName:  "Acme 株式会社",
+		SponsorEmail: "csr@acme.example",
+		RatioPercent: ratio,
+		CapAmount:    capAmount,
+		StartsAt:     &start,
+		EndsAt:       matchingTestNow.Add(30 * 24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return c
+}
+
+// ---------------------------------------------------------------------------
+// Tests
+// ---------------------------------------------------------------------------
+
+func TestMatchingService_Create(t *testing.T) {
+	repo := newMemMatchingRepo()
+	svc := newTestMatchingService(repo, nil)
+
+	c := createTestCampaign(t, svc, 0, 50000)
+	if c.ID == "" || c.RatioPercent != 100 || c.Currency != "jpy" || c.CreatedBy != "owner-1" {
+		t.Errorf("unexpected campaign: %+v", c)
+	}
+	if len(c.SummaryToken) != 64 {
+		t.Errorf("expected a 32-byte hex summary token, got %q", c.SummaryToken)
+	}
+
+	// 開始日時を省略すると作成時点から
+	c2, err := svc.Create(context.Background(), "owner-1", MatchingCampaignInput{
+		ProjectID: "p1", SponsorName: "S", CapAmount: 1000, EndsAt: matchingTestNow.Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !c2.StartsAt.Equal(matchingTestNow) || c2.SummaryToken == c.SummaryToken {
+		t.Errorf("unexpected campaign: %+v", c2)
+	}
+}
+
+func TestMatchingService_Create_Invalid(t *testing.T) {
+	svc := newTestMatchingService(newMemMatchingRepo(), nil)
+	valid := MatchingCampaignInput{
+		ProjectID: "p1", SponsorName: "S", RatioPercent: 100, CapAmount: 1000, EndsAt: matchingTestNow.Add(time.Hour),
+	}
+	past := matchingTestNow.Add(-2 * time.Hour)
+
+	tests := []struct {
+		name   string
+		modify func(in *MatchingCampaignInput)
+	}{
+		{"no sponsor", func(in *MatchingCampaignInput) { in.SponsorName = "  " }},
+		{"bad email", func(in *MatchingCampaignInput) { in.SponsorEmail = "acme" }},
+		{"negative ratio", func(in *MatchingCampaignInput) { in.RatioPercent = -1 }},
+		{"ratio too high", func(in *MatchingCampaignInput) { in.RatioPercent = 1001 }},
+		{"zero cap", func(in *MatchingCampaignInput) { in.CapAmount = 0 }},
+		{"bad currency", func(in *MatchingCampaignInput) { in.Currency = "yen!" }},
+		{"ends before starts", func(in *MatchingCampaignInput) { in.StartsAt = &valid.EndsAt; in.EndsAt = matchingTestNow.Add(time.Minute) }},
+		{"already ended", func(in *MatchingCampaignInput) { in.StartsAt = &past; in.EndsAt = matchingTestNow.Add(-time.Hour) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := valid
+			tt.modify(&in)
+			if _, err := svc.Create(context.Background(), "owner-1", in); !errors.Is(err, ErrInvalidMatchingCampaign) {
+				t.Errorf("expected ErrInvalidMatchingCampaign, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMatchingService_ApplyPayment_UpToCap(t *testing.T) {
+	repo := newMemMatchingRepo()
+	var activities []*model.ActivityItem
+	recorder := &mockStripeActivityRecorder{insertFunc: func(_ context.Context, a *model.ActivityItem) error {
+		activities = append(activities, a)
+		return nil
+	}}
+	svc := newTestMatchingService(repo, recorder)
+	c := createTestCampaign(t, svc, 200, 5000)
+	ctx := context.Background()
+
+	pay := func(id string, amount int) {
+		t.Helper()
+		if err := svc.ApplyPayment(ctx, &model.DonationPayment{
+			ID: id, ProjectID: "p1", Amount: amount, Currency: "jpy", PaidAt: matchingTestNow,
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	pay("pay-1", 1000) // 2000 上乗せ
+	pay("pay-1", 1000) // 再送は上乗せしない
+	pay("pay-2", 2000) // 残り 3000 まで
+	pay("pay-3", 1000) // 上限到達済み
+
+	got, _ := repo.GetByID(ctx, c.ID)
+	if got.MatchedAmount != 5000 {
+		t.Errorf("expected matched 5000, got %d", got.MatchedAmount)
+	}
+	if len(repo.contributions) != 2 || repo.contributions[1].MatchedAmount != 3000 {
+		t.Errorf("unexpected contributions: %+v", repo.contributions)
+	}
+	if len(activities) != 2 || activities[0].Type != "matching" || *activities[0].Amount != 2000 || activities[0].Message != "Acme 株式会社" {
+		t.Errorf("unexpected activities: %+v", activities)
+	}
+	if activities[0].ActorName != nil {
+		t.Error("matching activity should not expose the donor")
+	}
+}
+
+func TestMatchingService_ApplyPayment_SkipsOtherCurrencyAndPeriod(t *testing.T) {
+	repo := newMemMatchingRepo()
+	svc := newTestMatchingService(repo, nil)
+	createTestCampaign(t, svc, 100, 5000)
+	ctx := context.Background()
+
+	for _, p := range []*model.DonationPayment{
+		{ID: "pay-usd", ProjectID: "p1", Amount: 1000, Currency: "usd", PaidAt: matchingTestNow},
+		{ID: "pay-old", ProjectID: "p1", Amount: 1000, Currency: "jpy", PaidAt: matchingTestNow.Add(-48 * time.Hour)},
+		{ID: "pay-other", ProjectID: "p2", Amount: 1000, Currency: "jpy", PaidAt: matchingTestNow},
+	} {
+		if err := svc.ApplyPayment(ctx, p); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(repo.contributions) != 0 {
+		t.Errorf("expected no contributions, got %+v", repo.contributions)
+	}
+}
+
+func TestMatchingService_ApplyPayment_Error(t *testing.T) {
+	repo := newMemMatchingRepo()
+	repo.applyErr = errors.New("db error")
+	svc := newTestMatchingService(repo, nil)
+
+	if err := svc.ApplyPayment(context.Background(), &model.DonationPayment{ID: "pay-1", ProjectID: "p1"}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestMatchingService_ReleasePayment_RefundAndLostDispute(t *testing.T) {
+	repo := newMemMatchingRepo()
+	svc := newTestMatchingService(repo, nil)
+	c := createTestCampaign(t, svc, 200, 5000)
+	ctx := context.Background()
+
+	p := &model.DonationPayment{ID: "pay-1", ProjectID: "p1", Amount: 2000, Currency: "jpy", PaidAt: matchingTestNow}
+	if err := svc.ApplyPayment(ctx, p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	release := func() {
+		t.Helper()
+		if err := svc.ReleasePayment(ctx, p); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// 上限 5000 のうち 4000 を上乗せ、半額返金で 2000 に減らして上限に戻す（再送しても同じ）
+	p.RefundedAmount = 1000
+	release()
+	release()
+	if got, _ := repo.GetByID(ctx, c.ID); got.MatchedAmount != 2000 {
+		t.Errorf("expected matched 2000 after a half refund, got %d", got.MatchedAmount)
+	}
+	if mc := repo.contributions[0]; mc.MatchedAmount != 2000 || mc.DonationAmount != 1000 {
+		t.Errorf("unexpected contribution after refund: %+v", mc)
+	}
+
+	// 係争中は減らさない
+	p.DisputeStatus, p.DisputeAmount = "needs_response", 1000
+	release()
+	if got, _ := repo.GetByID(ctx, c.ID); got.MatchedAmount != 2000 {
+		t.Errorf("expected an open dispute to keep the match, got %d", got.MatchedAmount)
+	}
+
+	// 敗訴で残りもなくなる。空いた上限は別の入金に使える
+	p.DisputeStatus = "lost"
+	release()
+	if got, _ := repo.GetByID(ctx, c.ID); got.MatchedAmount != 0 {
+		t.Errorf("expected matched 0 after a lost dispute, got %d", got.MatchedAmount)
+	}
+	if err := svc.ApplyPayment(ctx, &model.DonationPayment{
+		ID: "pay-2", ProjectID: "p1", Amount: 2500, Currency: "jpy", PaidAt: matchingTestNow,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sum, err := svc.Summary(ctx, "p1", c.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sum.AmountOwed != 5000 || sum.DonationsTotal != 2500 {
+		t.Errorf("unexpected summary: owed=%d donations=%d", sum.AmountOwed, sum.DonationsTotal)
+	}
+}
+
+func TestMatchingService_ReleasePayment_Error(t *testing.T) {
+	repo := newMemMatchingRepo()
+	repo.applyErr = errors.New("db error")
+	svc := newTestMatchingService(repo, nil)
+
+	if err := svc.ReleasePayment(context.Background(), &model.DonationPayment{ID: "pay-1", ProjectID: "p1"}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestMatchingService_Cancel(t *testing.T) {
+	repo := newMemMatchingRepo()
+	svc := newTestMatchingService(repo, nil)
+	c := createTestCampaign(t, svc, 100, 5000)
+	ctx := context.Background()
+
+	if _, err := svc.Cancel(ctx, "p2", c.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for another project, got %v", err)
+	}
+	got, err := svc.Cancel(ctx, "p1", c.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.CancelledAt == nil {
+		t.Error("expected cancelled_at to be set")
+	}
+	if _, err := svc.Cancel(ctx, "p1", c.ID); !errors.Is(err, ErrMatchingCampaignFinished) {
+		t.Errorf("expected ErrMatchingCampaignFinished, got %v", err)
+	}
+
+	// 中止後の入金は上乗せしない
+	if err := svc.ApplyPayment(ctx, &model.DonationPayment{
+		ID: "pay-1", ProjectID: "p1", Amount: 1000, Currency: "jpy", PaidAt: matchingTestNow,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.contributions) != 0 {
+		t.Errorf("expected no contributions after cancel, got %+v", repo.contributions)
+	}
+}
+
+func TestMatchingService_ActiveByProject_HidesSponsorDetails(t *testing.T) {
+	svc := newTestMatchingService(newMemMatchingRepo(), nil)
+	createTestCampaign(t, svc, 100, 5000)
+
+	list, err := svc.ActiveByProject(context.Background(), "p1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("expected 1 campaign, got %d", len(list))
+	}
+	if list[0].SponsorEmail != "" || list[0].SummaryToken != "" || list[0].CreatedBy != "" {
+		t.Errorf("expected private fields to be cleared, got %+v", list[0])
+	}
+}
+
+func TestMatchingService_SponsorSummary(t *testing.T) {
+	repo := newMemMatchingRepo()
+	svc := newTestMatchingService(repo, nil)
+	c := createTestCampaign(t, svc, 50, 5000)
+	ctx := context.Background()
+	for i, amount := range []int{1000, 3000} {
+		if err := svc.ApplyPayment(ctx, &model.DonationPayment{
+			ID: fmt.Sprintf("pay-%d", i), ProjectID: "p1", Amount: amount, Currency: "jpy", PaidAt: matchingTestNow,
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if _, err := svc.SponsorSummary(ctx, c.ID, "wrong"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a wrong token, got %v", err)
+	}
+	if _, err := svc.SponsorSummary(ctx, c.ID, ""); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for an empty token, got %v", err)
+	}
+
+	sum, err := svc.SponsorSummary(ctx, c.ID, c.SummaryToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sum.DonationsTotal != 4000 || sum.AmountOwed != 2000 || len(sum.Contributions) != 2 {
+		t.Errorf("unexpected summary: %+v", sum)
+	}
+	if sum.ProjectName != "Project p1" || sum.OwnerName != "オーナー" || sum.Final {
+		t.Errorf("unexpected summary header: %+v", sum)
+	}
+}
+
+func TestMatchingService_CloseEnded(t *testing.T) {
+	repo := newMemMatchingRepo()
+	svc := newTestMatchingService(repo, nil)
+	c := createTestCampaign(t, svc, 100, 5000)
+	ctx := context.Background()
+	if err := svc.ApplyPayment(ctx, &model.DonationPayment{
+		ID: "pay-1", ProjectID: "p1", Amount: 1500, Currency: "jpy", PaidAt: matchingTestNow,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 期間中は締めない
+	if sums, err := svc.CloseEnded(ctx); err != nil || len(sums) != 0 {
+		t.Fatalf("expected nothing to close, got %v, %v", sums, err)
+	}
+
+	svc.now = func() time.Time { return c.EndsAt }
+	sums, err := svc.CloseEnded(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sums) != 1 || sums[0].AmountOwed != 1500 || !sums[0].Final {
+		t.Errorf("unexpected summaries: %+v", sums)
+	}
+	if sums, _ := svc.CloseEnded(ctx); len(sums) != 0 {
+		t.Errorf("expected closed campaigns to be skipped, got %+v", sums)
+	}
+}
@@ -16,6 +16,7 @@ type MilestoneProjectRepo interface {
 }
 
 type MilestoneDonationRepo interface {
+	// CurrentMonthSumByProject は当月の月額換算の寄付額（年払い・四半期払いは月割り）
 	CurrentMonthSumByProject(ctx context.Context, projectID string) (int, error)
 }
 
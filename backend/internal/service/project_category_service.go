@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+)
+
+// ProjectCategoryService はホストが管理するプロジェクトカテゴリのビジネスロジック
+type ProjectCategoryService interface {
+	List(ctx context.Context) ([]*model.ProjectCategory, error)
+	Create(ctx context.Context, c *model.ProjectCategory) error
+	Update(ctx context.Context, c *model.ProjectCategory) error
+	Delete(ctx context.Context, slug string) error
+}
+
+// カテゴリの slug（英小文字・数字をハイフンでつないだもの）と表示名の上限
+var reCategorySlug = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+const (
+	maxCategorySlugLength = 50
+	maxCategoryNameLength = 50
+)
+
+// ProjectCategoryServiceImpl は ProjectCategoryService の実装
+type ProjectCategoryServiceImpl struct {
+	repo repository.ProjectCategoryRepository
+}
+
+// NewProjectCategoryService は ProjectCategoryServiceImpl を生成する
+func NewProjectCategoryService(repo repository.ProjectCategoryRepository) ProjectCategoryService {
+	return &ProjectCategoryServiceImpl{repo: repo}
+}
+
+// List はカテゴリを表示順に返す
+func (s *ProjectCategoryServiceImpl) List(ctx context.Context) ([]*model.ProjectCategory, error) {
+	return s.repo.List(ctx)
+}
+
+// Create はカテゴリを作成する。slug が既にある場合は repository.ErrDuplicate を返す
+func (s *ProjectCategoryServiceImpl) Create(ctx context.Context, c *model.ProjectCategory) error {
+	c.Slug = strings.TrimSpace(c.Slug)
+	if len(c.Slug) > maxCategorySlugLength || !reCategorySlug.MatchString(c.Slug) {
+		return fmt.Errorf("%w: slug must be lowercase letters, digits and hyphens (at most %d)", ErrInvalidProjectCategory, maxCategorySlugLength)
+	}
+	if err := validateCategoryName(c); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, c)
+}
+
+// Update はカテゴリの表示名と表示順を更新する（slug は変更できない）
+func (s *ProjectCategoryServiceImpl) Update(ctx context.Context, c *model.ProjectCategory) error {
+	if err := validateCategoryName(c); err != nil {
+		return err
+	}
+	return s.repo.Update(ctx, c)
+}
+
+// Delete はカテゴリを削除する。付いていたプロジェクトは未分類になる
+func (s *ProjectCategoryServiceImpl) Delete(ctx context.Context, slug string) error {
+	return s.repo.Delete(ctx, slug)
+}
+
+func validateCategoryName(c *model.ProjectCategory) error {
+	c.Name = strings.TrimSpace(c.Name)
+	if c.Name == "" || utf8.RuneCountInString(c.Name) > maxCategoryNameLength {
+		return fmt.Errorf("%w: name must be 1-%d characters", ErrInvalidProjectCategory, maxCategoryNameLength)
+	}
+	return nil
+}
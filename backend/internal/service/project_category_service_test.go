@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/givers/backend/internal/model"
+)
+
+type mockProjectCategoryRepository struct {
+	created *model.ProjectCategory
+	updated *model.ProjectCategory
+}
+
+func (m *mockProjectCategoryRepository) List(_ context.Context) ([]*model.ProjectCategory, error) {
+	return nil, nil
+}
+func (m *mockProjectCategoryRepository) Create(_ context.Context, c *model.ProjectCategory) error {
+	m.created = c
+	return nil
+}
+func (m *mockProjectCategoryRepository) Update(_ context.Context, c *model.ProjectCategory) error {
+	m.updated = c
+	return nil
+}
+func (m *mockProjectCategoryRepository) Delete(_ context.Context, _ string) error { return nil }
+
+func TestProjectCategoryService_Create(t *testing.T) {
+	repo := &mockProjectCategoryRepository{}
+	svc := NewProjectCategoryService(repo)
+
+	if err := svc.Create(context.Background(), &model.ProjectCategory{Slug: " oss-tools ", Name: " OSS・開発ツール ", SortOrder: 10}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.created == nil || repo.created.Slug != "oss-tools" || repo.created.Name != "OSS・開発ツール" {
+		t.Errorf("unexpected category: %+v", repo.created)
+	}
+
+	invalid := []*model.ProjectCategory{
+		{Slug: "OSS", Name: "OSS"},
+		{Slug: "oss--tools", Name: "OSS"},
+		{Slug: "-oss", Name: "OSS"},
+		{Slug: "ゲーム", Name: "ゲーム"},
+		{Slug: strings.Repeat("a", maxCategorySlugLength+1), Name: "long"},
+		{Slug: "games", Name: "  "},
+	}
+	for _, c := range invalid {
+		if err := svc.Create(context.Background(), c); !errors.Is(err, ErrInvalidProjectCategory) {
+			t.Errorf("%+v: expected ErrInvalidProjectCategory, got %v", c, err)
+		}
+	}
+}
+
+func TestProjectCategoryService_Update_RequiresName(t *testing.T) {
+	repo := &mockProjectCategoryRepository{}
+	svc := NewProjectCategoryService(repo)
+
+	err := svc.Update(context.Background(), &model.ProjectCategory{Slug: "games", Name: strings.Repeat("あ", maxCategoryNameLength+1)})
+	if !errors.Is(err, ErrInvalidProjectCategory) {
+		t.Errorf("expected ErrInvalidProjectCategory, got %v", err)
+	}
+	if repo.updated != nil {
+		t.Error("expected the repository not to be called")
+	}
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+)
+
+var (
+	// ErrInvalidProjectInvitation は招待の相手や役割が不正な場合のエラー
+	ErrInvalidProjectInvitation = errors.New("invalid project invitation")
+	// ErrAlreadyProjectMember は招待の相手が既にメンバーの場合のエラー
+	ErrAlreadyProjectMember = errors.New("already a project member")
+	// ErrProjectCreator はプロジェクトの作成者を外したり降格したりしようとした場合のエラー
+	ErrProjectCreator = errors.New("the project creator is always an owner")
+)
+
+// ProjectMemberUserFinder は招待の相手を確認するためのユーザー取得
+type ProjectMemberUserFinder interface {
+	FindByID(ctx context.Context, id string) (*model.User, error)
+	FindByEmail(ctx context.Context, email string) (*model.User, error)
+}
+
+// ProjectInvitationInput は招待の内容。Email と UserID のどちらか一方を指定する
+type ProjectInvitationInput struct {
+	Email  string
+	UserID string
+	Role   string
+}
+
+// ProjectMemberService はプロジェクトのメンバーと招待のビジネスロジック。
+// 操作できるかどうか（owner かどうかなど）は呼び出し側で確認する。
+// project には GetByID で取得した（Members を含む）ものを渡す
+type ProjectMemberService interface {
+	ListMembers(ctx context.Context, projectID string) ([]*model.ProjectMember, error)
+	// ListInvitations はプロジェクトの保留中の招待を返す
+	ListInvitations(ctx context.Context, projectID string) ([]*model.ProjectInvitation, error)
+	Invite(ctx context.Context, project *model.Project, inviterID string, in ProjectInvitationInput) (*model.ProjectInvitation, error)
+	// RevokeInvitation は保留中の招待を取り消す。別のプロジェクトの招待なら repository.ErrNotFound を返す
+	RevokeInvitation(ctx context.Context, projectID, invitationID string) error
+	ChangeRole(ctx context.Context, project *model.Project, userID, role string) error
+	// Remove はメンバーを外す（自分で抜ける場合も含む）
+	Remove(ctx context.Context, project *model.Project, userID string) error
+	// ListMyInvitations はユーザー ID またはユーザーのメールアドレス宛ての保留中の招待を返す
+	ListMyInvitations(ctx context.Context, userID string) ([]*model.ProjectInvitation, error)
+	// Accept / Decline は自分宛ての保留中の招待に応答する。自分宛てでなければ repository.ErrNotFound を返す
+	Accept(ctx context.Context, invitationID, userID string) error
+	Decline(ctx context.Context, invitationID, userID string) error
+}
+
+// ProjectMemberServiceImpl は ProjectMemberService の実装
+type ProjectMemberServiceImpl struct {
+	repo  repository.ProjectMemberRepository
+	users ProjectMemberUserFinder
+}
+
+// NewProjectMemberService は ProjectMemberServiceImpl を生成する
+func NewProjectMemberService(repo repository.ProjectMemberRepository, users ProjectMemberUserFinder) ProjectMemberService {
+	return &ProjectMemberServiceImpl{repo: repo, users: users}
+}
+
+// ListMembers はメンバーを役割の強い順に返す
+func (s *ProjectMemberServiceImpl) ListMembers(ctx context.Context, projectID string) ([]*model.ProjectMember, error) {
+	return s.repo.ListMembers(ctx, projectID)
+}
+
+// ListInvitations はプロジェクトの保留中の招待を返す
+func (s *ProjectMemberServiceImpl) ListInvitations(ctx context.Context, projectID string) ([]*model.ProjectInvitation, error) {
+	return s.repo.ListInvitationsByProject(ctx, projectID)
+}
+
+// Invite はメールアドレスまたはユーザー ID でメンバーに招待する。
+// 同じ相手への保留中の招待があれば repository.ErrDuplicate を返す
+func (s *ProjectMemberServiceImpl) Invite(ctx context.Context, project *model.Project, inviterID string, in ProjectInvitationInput) (*model.ProjectInvitation, error) {
+	in.Email = strings.TrimSpace(in.Email)
+	in.UserID = strings.TrimSpace(in.UserID)
+	if !model.ValidProjectRole(in.Role) {
+		return nil, fmt.Errorf("%w: role must be owner, maintainer or editor", ErrInvalidProjectInvitation)
+	}
+	if (in.Email == "") == (in.UserID == "") {
+		return nil, fmt.Errorf("%w: specify either email or user_id", ErrInvalidProjectInvitation)
+	}
+
+	if in.UserID != "" {
+		if _, err := s.users.FindByID(ctx, in.UserID); err != nil {
+			return nil, fmt.Errorf("%w: user not found", ErrInvalidProjectInvitation)
+		}
+		if project.RoleOf(in.UserID) != "" {
+			return nil, ErrAlreadyProjectMember
+		}
+	} else {
+		if !strings.Contains(in.Email, "@") || len(in.Email) > 255 {
+			return nil, fmt.Errorf("%w: invalid email", ErrInvalidProjectInvitation)
+		}
+		if u, err := s.users.FindByEmail(ctx, in.Email); err == nil && project.RoleOf(u.ID) != "" {
+			return nil, ErrAlreadyProjectMember
+		}
+	}
+
+	inv := &model.ProjectInvitation{
+		ProjectID: project.ID,
+		Email:     in.Email,
+		UserID:    in.UserID,
+		Role:      in.Role,
+		InvitedBy: inviterID,
+	}
+	if err := s.repo.CreateInvitation(ctx, inv); err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+// RevokeInvitation は保留中の招待を取り消す
+func (s *ProjectMemberServiceImpl) RevokeInvitation(ctx context.Context, projectID, invitationID string) error {
+	inv, err := s.repo.GetInvitation(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if inv.ProjectID != projectID {
+		return repository.ErrNotFound
+	}
+	return s.repo.SetInvitationStatus(ctx, invitationID, model.ProjectInvitationRevoked)
+}
+
+// ChangeRole はメンバーの役割を変更する。作成者の役割は変更できない
+func (s *ProjectMemberServiceImpl) ChangeRole(ctx context.Context, project *model.Project, userID, role string) error {
+	if !model.ValidProjectRole(role) {
+		return fmt.Errorf("%w: role must be owner, maintainer or editor", ErrInvalidProjectInvitation)
+	}
+	if userID == project.OwnerID {
+		return ErrProjectCreator
+	}
+	return s.repo.UpdateMemberRole(ctx, project.ID, userID, role)
+}
+
+// Remove はメンバーを外す。作成者は外せない
+func (s *ProjectMemberServiceImpl) Remove(ctx context.Context, project *model.Project, userID string) error {
+	if userID == project.OwnerID {
+		return ErrProjectCreator
+	}
+	return s.repo.RemoveMember(ctx, project.ID, userID)
+}
+
+// ListMyInvitations は自分宛ての保留中の招待を返す
+func (s *ProjectMemberServiceImpl) ListMyInvitations(ctx context.Context, userID string) ([]*model.ProjectInvitation, error) {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListInvitationsForUser(ctx, userID, user.Email)
+}
+
+// Accept は招待を承諾してメンバーになる
+func (s *ProjectMemberServiceImpl) Accept(ctx context.Context, invitationID, userID string) error {
+	if err := s.checkInvitee(ctx, invitationID, userID); err != nil {
+		return err
+	}
+	return s.repo.AcceptInvitation(ctx, invitationID, userID)
+}
+
+// Decline は招待を辞退する
+func (s *ProjectMemberServiceImpl) Decline(ctx context.Context, invitationID, userID string) error {
+	if err := s.checkInvitee(ctx, invitationID, userID); err != nil {
+		return err
+	}
+	return s.repo.SetInvitationStatus(ctx, invitationID, model.ProjectInvitationDeclined)
+}
+
+// checkInvitee は招待が userID（またはそのメールアドレス）宛ての保留中のものか確認する。
+// 他人宛ての招待は存在を明かさないよう repository.ErrNotFound とする
+func (s *ProjectMemberServiceImpl) checkInvitee(ctx context.Context, invitationID, userID string) error {
+	inv, err := s.repo.GetInvitation(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if inv.Status != model.ProjectInvitationPending {
+		return repository.ErrNotFound
+	}
+	if inv.UserID != "" {
+		if inv.UserID != userID {
+			return repository.ErrNotFound
+		}
+		return nil
+	}
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(user.Email, inv.Email) {
+		return repository.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+)
+
+type mockProjectMemberRepository struct {
+	invitation *model.ProjectInvitation
+	created    *model.ProjectInvitation
+	status     string
+	accepted   string
+	updated    string
+	removed    string
+}
+
+func (m *mockProjectMemberRepository) ListMembers(_ context.Context, _ string) ([]*model.ProjectMember, error) {
+	return nil, nil
+}
+func (m *mockProjectMemberRepository) UpdateMemberRole(_ context.Context, _, userID, role string) error {
+	m.updated = userID + ":" + role
+	return nil
+}
+func (m *mockProjectMemberRepository) RemoveMember(_ context.Context, _, userID string) error {
+	m.removed = userID
+	return nil
+}
+func (m *mockProjectMemberRepository) CreateInvitation(_ context.Context, inv *model.ProjectInvitation) error {
+	inv.ID = "inv-1"
+	inv.Status = model.ProjectInvitationPending
+	m.created = inv
+	return nil
+}
+func (m *mockProjectMemberRepository) GetInvitation(_ context.Context, id string) (*model.ProjectInvitation, error) {
+	if m.invitation == nil || m.invitation.ID != id {
+		return nil, repository.ErrNotFound
+	}
+	return m.invitation, nil
+}
+func (m *mockProjectMemberRepository) ListInvitationsByProject(_ context.Context, _ string) ([]*model.ProjectInvitation, error) {
+	return nil, nil
+}
+func (m *mockProjectMemberRepository) ListInvitationsForUser(_ context.Context, _, _ string) ([]*model.ProjectInvitation, error) {
+	return nil, nil
+}
+func (m *mockProjectMemberRepository) SetInvitationStatus(_ context.Context, _, status string) error {
+	m.status = status
+	return nil
+}
+func (m *mockProjectMemberRepository) AcceptInvitation(_ context.Context, _, userID string) error {
+	m.accepted = userID
+	return nil
+}
+
+type mockProjectMemberUsers map[string]*model.User
+
+func (m mockProjectMemberUsers) FindByID(_ context.Context, id string) (*model.User, error) {
+	if u, ok := m[id]; ok {
+		return u, nil
+	}
+	return nil, errors.New("not found")
+}
+func (m mockProjectMemberUsers) FindByEmail(_ context.Context, email string) (*model.User, error) {
+	for _, u := range m {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+var memberTestUsers = mockProjectMemberUsers{
+	"owner-1":  {ID: "owner-1", Email: "owner@example.com"},
+	"editor-1": {ID: "editor-1", Email: "editor@example.com"},
+	"user-2":   {ID: "user-2", Email: "User2@Example.com"},
+}
+
+func memberTestProject() *model.Project {
+	return &model.Project{ID: "p1", OwnerID: "owner-1", Members: []*model.ProjectMember{
+		{UserID: "owner-1", Role: model.ProjectRoleOwner},
+		{UserID: "editor-1", Role: model.ProjectRoleEditor},
+	}}
+}
+
+func TestProjectMemberService_Invite(t *testing.T) {
+	repo := &mockProjectMemberRepository{}
+	svc := NewProjectMemberService(repo, memberTestUsers)
+
+	inv, err := svc.Invite(context.Background(), memberTestProject(), "owner-1",
+		ProjectInvitationInput{Email: " new@example.com ", Role: model.ProjectRoleMaintainer})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inv.ID != "inv-1" || inv.Email != "new@example.com" || inv.InvitedBy != "owner-1" || inv.ProjectID != "p1" {
+		t.Errorf("unexpected invitation: %+v", inv)
+	}
+
+	tests := []struct {
+		name string
+		in   ProjectInvitationInput
+		want error
+	}{
+		{"unknown role", ProjectInvitationInput{UserID: "user-2", Role: "admin"}, ErrInvalidProjectInvitation},
+		{"no invitee", ProjectInvitationInput{Role: model.ProjectRoleEditor}, ErrInvalidProjectInvitation},
+		{"both email and user", ProjectInvitationInput{Email: "a@example.com", UserID: "user-2", Role: model.ProjectRoleEditor}, ErrInvalidProjectInvitation},
+		{"invalid email", ProjectInvitationInput{Email: "example.com", Role: model.ProjectRoleEditor}, ErrInvalidProjectInvitation},
+		{"unknown user", ProjectInvitationInput{UserID: "nobody", Role: model.ProjectRoleEditor}, ErrInvalidProjectInvitation},
+		{"member by ID", ProjectInvitationInput{UserID: "editor-1", Role: model.ProjectRoleOwner}, ErrAlreadyProjectMember},
+		{"member by email", ProjectInvitationInput{Email: "editor@example.com", Role: model.ProjectRoleOwner}, ErrAlreadyProjectMember},
+	}
+	for _, tt := range tests {
+		if _, err := svc.Invite(context.Background(), memberTestProject(), "owner-1", tt.in); !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestProjectMemberService_CreatorCannotBeChanged(t *testing.T) {
+	repo := &mockProjectMemberRepository{}
+	svc := NewProjectMemberService(repo, memberTestUsers)
+	p := memberTestProject()
+
+	if err := svc.ChangeRole(context.Background(), p, "owner-1", model.ProjectRoleEditor); !errors.Is(err, ErrProjectCreator) {
+		t.Errorf("ChangeRole: expected ErrProjectCreator, got %v", err)
+	}
+	if err := svc.Remove(context.Background(), p, "owner-1"); !errors.Is(err, ErrProjectCreator) {
+		t.Errorf("Remove: expected ErrProjectCreator, got %v", err)
+	}
+	if err := svc.ChangeRole(context.Background(), p, "editor-1", model.ProjectRoleMaintainer); err != nil || repo.updated != "editor-1:maintainer" {
+		t.Errorf("ChangeRole: err=%v updated=%q", err, repo.updated)
+	}
+	if err := svc.Remove(context.Background(), p, "editor-1"); err != nil || repo.removed != "editor-1" {
+		t.Errorf("Remove: err=%v removed=%q", err, repo.removed)
+	}
+}
+
+func TestProjectMemberService_Accept(t *testing.T) {
+	tests := []struct {
+		name   string
+		inv    *model.ProjectInvitation
+		userID string
+		want   error
+	}{
+		{"by user ID", &model.ProjectInvitation{ID: "inv-1", UserID: "user-2", Status: model.ProjectInvitationPending}, "user-2", nil},
+		{"by email ignoring case", &model.ProjectInvitation{ID: "inv-1", Email: "user2@example.com", Status: model.ProjectInvitationPending}, "user-2", nil},
+		{"addressed to someone else", &model.ProjectInvitation{ID: "inv-1", UserID: "editor-1", Status: model.ProjectInvitationPending}, "user-2", repository.ErrNotFound},
+		{"other email", &model.ProjectInvitation{ID: "inv-1", Email: "owner@example.com", Status: model.ProjectInvitationPending}, "user-2", repository.ErrNotFound},
+		{"already revoked", &model.ProjectInvitation{ID: "inv-1", UserID: "user-2", Status: model.ProjectInvitationRevoked}, "user-2", repository.ErrNotFound},
+	}
+	for _, tt := range tests {
+		repo := &mockProjectMemberRepository{invitation: tt.inv}
+		svc := NewProjectMemberService(repo, memberTestUsers)
+		err := svc.Accept(context.Background(), "inv-1", tt.userID)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+		if tt.want == nil && repo.accepted != tt.userID {
+			t.Errorf("%s: expected the invitation to be accepted by %s, got %q", tt.name, tt.userID, repo.accepted)
+		}
+	}
+}
+
+func TestProjectMemberService_RevokeInvitation_OtherProject(t *testing.T) {
+	repo := &mockProjectMemberRepository{invitation: &model.ProjectInvitation{ID: "inv-1", ProjectID: "p2", Status: model.ProjectInvitationPending}}
+	svc := NewProjectMemberService(repo, memberTestUsers)
+
+	if err := svc.RevokeInvitation(context.Background(), "p1", "inv-1"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if err := svc.RevokeInvitation(context.Background(), "p2", "inv-1"); err != nil || repo.status != model.ProjectInvitationRevoked {
+		t.Errorf("err=%v status=%q", err, repo.status)
+	}
+}
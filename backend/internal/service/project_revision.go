@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+
+	"github.com/givers/backend/internal/model"
+)
+
+// UpdateWithRevision はプロジェクトを更新し、変更前と比べて変わった項目を更新履歴に記録する。
+// 履歴に残す項目（model.ProjectSnapshot）が変わっていなければ履歴は記録しない
+func (s *ProjectServiceImpl) UpdateWithRevision(ctx context.Context, actorID string, project *model.Project) error {
+	return s.updateWithRevision(ctx, project, &model.ProjectRevision{ActorID: actorID, Action: model.ProjectRevisionUpdate})
+}
+
+// ListRevisions はプロジェクトの更新履歴を新しい順に返す
+func (s *ProjectServiceImpl) ListRevisions(ctx context.Context, projectID string) ([]*model.ProjectRevision, error) {
+	return s.projectRepo.ListRevisions(ctx, projectID)
+}
+
+// RestoreRevision はプロジェクトを revisionID の更新後の内容に戻す。
+// 復元も更新履歴（action が restore）として残るので、復元自体を取り消すこともできる。
+// 戻す内容は今の入力と同じく検証する（削除されたカテゴリや他のプロジェクトが使っているスラッグはエラー）
+func (s *ProjectServiceImpl) RestoreRevision(ctx context.Context, actorID, projectID, revisionID string) (*model.Project, error) {
+	rev, err := s.projectRepo.GetRevision(ctx, projectID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	rev.Snapshot.ApplyTo(project)
+	restore := &model.ProjectRevision{ActorID: actorID, Action: model.ProjectRevisionRestore, RestoredFrom: rev.ID}
+	if err := s.updateWithRevision(ctx, project, restore); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// updateWithRevision は project を検証して保存し、差分があれば rev を同じトランザクションで記録する
+func (s *ProjectServiceImpl) updateWithRevision(ctx context.Context, project *model.Project, rev *model.ProjectRevision) error {
+	if err := normalizeProject(project); err != nil {
+		return err
+	}
+	before, err := s.projectRepo.GetByID(ctx, project.ID)
+	if err != nil {
+		return err
+	}
+	rev.Snapshot = model.SnapshotOf(project)
+	rev.Changes = model.SnapshotOf(before).Diff(rev.Snapshot)
+	if len(rev.Changes) == 0 {
+		rev = nil
+	}
+	return projectWriteError(s.projectRepo.UpdateWithRevision(ctx, project, rev), project)
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+)
+
+func revisionTestProject() *model.Project {
+	want := 50000
+	deadline := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	return &model.Project{
+		ID: "p1", OwnerID: "owner", Name: "P", Status: "active", Slug: "givers",
+		OwnerWantMonthly: &want, Deadline: &deadline,
+		CostItems: []model.CostItem{{Label: "サーバー費用", UnitPrice: 10000, Quantity: 1}},
+		Tags:      []string{},
+	}
+}
+
+func TestProjectService_UpdateWithRevision_RecordsFieldDiff(t *testing.T) {
+	var gotRev *model.ProjectRevision
+	svc := NewProjectService(&mockProjectRepository{
+		getByIDFunc: func(_ context.Context, _ string) (*model.Project, error) { return revisionTestProject(), nil },
+		updateWithRevisionFunc: func(_ context.Context, _ *model.Project, rev *model.ProjectRevision) error {
+			gotRev = rev
+			return nil
+		},
+	})
+
+	p := revisionTestProject()
+	p.CostItems = append(p.CostItems, model.CostItem{Label: "開発者費用", UnitPrice: 20000, Quantity: 2})
+	p.OwnerWantMonthly = nil
+	p.Status = "frozen" // 履歴の対象外
+	p.Tags = nil        // 空の配列と未設定は同じ
+	if err := svc.UpdateWithRevision(context.Background(), "u1", p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotRev == nil {
+		t.Fatal("expected a revision")
+	}
+	if gotRev.ActorID != "u1" || gotRev.Action != model.ProjectRevisionUpdate {
+		t.Errorf("unexpected revision: %+v", gotRev)
+	}
+	if len(gotRev.Changes) != 3 {
+		t.Errorf("expected cost_items, monthly_target and owner_want_monthly, got %v", gotRev.Changes)
+	}
+	if c := gotRev.Changes["monthly_target"]; string(c.From) != "10000" || string(c.To) != "50000" {
+		t.Errorf("unexpected monthly_target change: %s → %s", c.From, c.To)
+	}
+	if c := gotRev.Changes["owner_want_monthly"]; string(c.From) != "50000" || string(c.To) != "null" {
+		t.Errorf("unexpected owner_want_monthly change: %s → %s", c.From, c.To)
+	}
+	if gotRev.Snapshot.MonthlyTarget != 50000 {
+		t.Errorf("expected the snapshot to hold the new values, got %+v", gotRev.Snapshot)
+	}
+}
+
+func TestProjectService_UpdateWithRevision_NoChanges(t *testing.T) {
+	called := false
+	svc := NewProjectService(&mockProjectRepository{
+		getByIDFunc: func(_ context.Context, _ string) (*model.Project, error) { return revisionTestProject(), nil },
+		updateWithRevisionFunc: func(_ context.Context, _ *model.Project, rev *model.ProjectRevision) error {
+			called = true
+			if rev != nil {
+				t.Errorf("expected no revision, got %+v", rev.Changes)
+			}
+			return nil
+		},
+	})
+	if err := svc.UpdateWithRevision(context.Background(), "u1", revisionTestProject()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !called {
+		t.Error("expected the project to be saved")
+	}
+}
+
+func TestProjectService_RestoreRevision(t *testing.T) {
+	old := revisionTestProject()
+	old.Name = "Old name"
+	old.Slug = "old-givers"
+	var saved *model.Project
+	var gotRev *model.ProjectRevision
+	svc := NewProjectService(&mockProjectRepository{
+		getByIDFunc: func(_ context.Context, _ string) (*model.Project, error) { return revisionTestProject(), nil },
+		getRevisionFunc: func(_ context.Context, projectID, revisionID string) (*model.ProjectRevision, error) {
+			if projectID != "p1" || revisionID != "r1" {
+				return nil, repository.ErrNotFound
+			}
+			return &model.ProjectRevision{ID: "r1", ProjectID: "p1", Snapshot: model.SnapshotOf(old)}, nil
+		},
+		updateWithRevisionFunc: func(_ context.Context, p *model.Project, rev *model.ProjectRevision) error {
+			saved, gotRev = p, rev
+			return nil
+		},
+	})
+
+	p, err := svc.RestoreRevision(context.Background(), "host", "p1", "r1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Name != "Old name" || p.Slug != "old-givers" || p.Status != "active" || saved != p {
+		t.Errorf("unexpected restored project: %+v", p)
+	}
+	if gotRev == nil || gotRev.Action != model.ProjectRevisionRestore || gotRev.RestoredFrom != "r1" || len(gotRev.Changes) != 2 {
+		t.Errorf("unexpected revision: %+v", gotRev)
+	}
+
+	if _, err := svc.RestoreRevision(context.Background(), "host", "p1", "other"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestProjectService_RestoreRevision_SlugTaken(t *testing.T) {
+	svc := NewProjectService(&mockProjectRepository{
+		getByIDFunc: func(_ context.Context, _ string) (*model.Project, error) { return revisionTestProject(), nil },
+		getRevisionFunc: func(_ context.Context, _, _ string) (*model.ProjectRevision, error) {
+			return &model.ProjectRevision{ID: "r1", Snapshot: model.ProjectSnapshot{Name: "P", Slug: "taken"}}, nil
+		},
+		updateWithRevisionFunc: func(_ context.Context, _ *model.Project, _ *model.ProjectRevision) error {
+			return repository.ErrSlugTaken
+		},
+	})
+	if _, err := svc.RestoreRevision(context.Background(), "u1", "p1", "r1"); !errors.Is(err, ErrProjectSlugTaken) {
+		t.Errorf("expected ErrProjectSlugTaken, got %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/givers/backend/internal/model"
+)
+
+// ErrInvalidSearchQuery は検索語が空・長すぎる・検索できる文字を含まない場合のエラー
+var ErrInvalidSearchQuery = errors.New("invalid search query")
+
+// 検索語とハイライトの上限
+const (
+	maxSearchQueryLength = 100 // 検索語の文字数
+	searchSnippetLead    = 30  // スニペットで最初の一致より前に残す文字数
+	searchSnippetLength  = 120 // スニペットの文字数
+)
+
+// 検索の文字種（043 のマイグレーションの search_tokens / search_query と同じ区分）
+const (
+	searchRuneOther = iota
+	searchRuneCJK   // かな・漢字（bigram で照合）
+	searchRuneWord  // 英数字（単語の前方一致で照合）
+)
+
+// Search はプロジェクトを全文検索し、名前とスニペットのハイライトを付けて返す。
+// sort は "relevance"（既定）/ "new" / "hot"。
+func (s *ProjectServiceImpl) Search(ctx context.Context, query, sort string, limit int, cursor string) (*model.ProjectSearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" || utf8.RuneCountInString(query) > maxSearchQueryLength {
+		return nil, fmt.Errorf("%w: query must be 1-%d characters", ErrInvalidSearchQuery, maxSearchQueryLength)
+	}
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("%w: query has no searchable characters", ErrInvalidSearchQuery)
+	}
+	switch sort {
+	case model.ProjectSearchSortNew, model.ProjectSearchSortHot:
+	default:
+		sort = model.ProjectSearchSortRelevance
+	}
+
+	result, err := s.projectRepo.Search(ctx, model.ProjectSearchQuery{Text: query, Sort: sort, Limit: limit, Cursor: cursor})
+	if err != nil {
+		return nil, err
+	}
+	for _, hit := range result.Projects {
+		hit.Highlight = highlightProject(hit.Project, terms)
+	}
+	return result, nil
+}
+
+// searchRuneClass は文字の検索上の種類を返す
+func searchRuneClass(r rune) int {
+	switch {
+	case r >= 0x3005 && r <= 0x3007, r >= 0x3041 && r <= 0x30FF, r >= 0x3400 && r <= 0x4DBF,
+		r >= 0x4E00 && r <= 0x9FFF, r >= 0xF900 && r <= 0xFAFF:
+		return searchRuneCJK
+	case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r >= 0xC0 && r <= 0x24F:
+		return searchRuneWord
+	}
+	return searchRuneOther
+}
+
+// foldSearchRunes は search_normalize と同じく全角英数字を半角にそろえて小文字にする。
+// 文字数は変えないので、結果の位置は元の文字列の位置と対応する。
+func foldSearchRunes(s string) []rune {
+	rs := []rune(s)
+	for i, r := range rs {
+		if (r >= '０' && r <= '９') || (r >= 'Ａ' && r <= 'Ｚ') || (r >= 'ａ' && r <= 'ｚ') {
+			r -= 0xFEE0
+		}
+		rs[i] = unicode.ToLower(r)
+	}
+	return rs
+}
+
+// searchTerms は検索語を日本語と英数字の連続部分に分ける（記号や空白は区切りとして捨てる）
+func searchTerms(query string) [][]rune {
+	rs := foldSearchRunes(query)
+	var terms [][]rune
+	for i := 0; i < len(rs); {
+		class := searchRuneClass(rs[i])
+		j := i + 1
+		for j < len(rs) && searchRuneClass(rs[j]) == class {
+			j++
+		}
+		if class != searchRuneOther && !slices.ContainsFunc(terms, func(t []rune) bool { return slices.Equal(t, rs[i:j]) }) {
+			terms = append(terms, rs[i:j])
+		}
+		i = j
+	}
+	return terms
+}
+
+// markSearchMatches は text のうち検索語に一致する文字に印を付ける。
+// 英数字の語は検索と同じく単語の先頭からの一致のみ、日本語はどこに現れても一致とする。
+func markSearchMatches(text string, terms [][]rune) []bool {
+	rs := foldSearchRunes(text)
+	marks := make([]bool, len(rs))
+	for _, term := range terms {
+		word := searchRuneClass(term[0]) == searchRuneWord
+		for i := 0; i+len(term) <= len(rs); i++ {
+			if word && i > 0 && searchRuneClass(rs[i-1]) == searchRuneWord {
+				continue
+			}
+			if slices.Equal(rs[i:i+len(term)], term) {
+				for k := i; k < i+len(term); k++ {
+					marks[k] = true
+				}
+			}
+		}
+	}
+	return marks
+}
+
+// highlightProject は名前全体と、概要（なければ説明）の最初の一致の周辺をハイライトする。
+// どちらにも一致がなければ（名前だけに一致した場合など）概要の先頭をスニペットにする。
+func highlightProject(p *model.Project, terms [][]rune) *model.ProjectSearchHighlight {
+	h := &model.ProjectSearchHighlight{Name: renderHighlight([]rune(p.Name), markSearchMatches(p.Name, terms))}
+	for _, text := range []string{p.Overview, p.Description} {
+		marks := markSearchMatches(text, terms)
+		if first := slices.Index(marks, true); first >= 0 {
+			h.Snippet = searchSnippet([]rune(text), marks, first)
+			return h
+		}
+	}
+	h.Snippet = searchSnippet([]rune(p.Overview), make([]bool, utf8.RuneCountInString(p.Overview)), 0)
+	return h
+}
+
+// searchSnippet は first の少し前から searchSnippetLength 文字を切り出し、省略した側に「…」を付ける
+func searchSnippet(rs []rune, marks []bool, first int) string {
+	start := max(first-searchSnippetLead, 0)
+	end := min(start+searchSnippetLength, len(rs))
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	b.WriteString(renderHighlight(rs[start:end], marks[start:end]))
+	if end < len(rs) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// renderHighlight は HTML エスケープした text の一致部分を <mark> で囲む。改行などの空白は半角スペースにする
+func renderHighlight(rs []rune, marks []bool) string {
+	var b strings.Builder
+	for i := 0; i < len(rs); {
+		j := i + 1
+		for j < len(rs) && marks[j] == marks[i] {
+			j++
+		}
+		seg := html.EscapeString(strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return ' '
+			}
+			return r
+		}, string(rs[i:j])))
+		if marks[i] {
+			b.WriteString("<mark>" + seg + "</mark>")
+		} else {
+			b.WriteString(seg)
+		}
+		i = j
+	}
+	return b.String()
+}
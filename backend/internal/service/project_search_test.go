@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/givers/backend/internal/model"
+)
+
+func TestProjectService_Search_InvalidQuery(t *testing.T) {
+	called := false
+	svc := NewProjectService(&mockProjectRepository{
+		searchFunc: func(_ context.Context, _ model.ProjectSearchQuery) (*model.ProjectSearchResult, error) {
+			called = true
+			return &model.ProjectSearchResult{}, nil
+		},
+	})
+
+	for _, q := range []string{"", "   ", "!?★", strings.Repeat("あ", maxSearchQueryLength+1)} {
+		if _, err := svc.Search(context.Background(), q, "", 20, ""); !errors.Is(err, ErrInvalidSearchQuery) {
+			t.Errorf("%q: expected ErrInvalidSearchQuery, got %v", q, err)
+		}
+	}
+	if called {
+		t.Error("expected the repository not to be called")
+	}
+}
+
+func TestProjectService_Search_DefaultsAndHighlight(t *testing.T) {
+	var got model.ProjectSearchQuery
+	svc := NewProjectService(&mockProjectRepository{
+		searchFunc: func(_ context.Context, q model.ProjectSearchQuery) (*model.ProjectSearchResult, error) {
+			got = q
+			return &model.ProjectSearchResult{Projects: []*model.ProjectSearchHit{{
+				Project: &model.Project{
+					ID:          "p1",
+					Name:        "Go の寄付サイト <beta>",
+					Overview:    "オープンソースの寄付\nGolang で書かれています",
+					Description: "説明",
+				},
+			}}}, nil
+		},
+	})
+
+	res, err := svc.Search(context.Background(), "  寄付　ＧＯ ", "unknown", 20, "c1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Text != "寄付　ＧＯ" || got.Sort != model.ProjectSearchSortRelevance || got.Limit != 20 || got.Cursor != "c1" {
+		t.Errorf("unexpected repository query: %+v", got)
+	}
+	h := res.Projects[0].Highlight
+	if h == nil {
+		t.Fatal("expected a highlight")
+	}
+	if want := "<mark>Go</mark> の<mark>寄付</mark>サイト &lt;beta&gt;"; h.Name != want {
+		t.Errorf("name: got %q, want %q", h.Name, want)
+	}
+	if want := "オープンソースの<mark>寄付</mark> <mark>Go</mark>lang で書かれています"; h.Snippet != want {
+		t.Errorf("snippet: got %q, want %q", h.Snippet, want)
+	}
+}
+
+func TestHighlightProject_Snippet(t *testing.T) {
+	terms := searchTerms("go")
+	long := strings.Repeat("あ", 50) + "go" + strings.Repeat("い", 200)
+
+	tests := []struct {
+		name string
+		p    *model.Project
+		want string
+	}{
+		{"match in the middle is trimmed on both sides",
+			&model.Project{Overview: long},
+			"…" + strings.Repeat("あ", searchSnippetLead) + "<mark>go</mark>" + strings.Repeat("い", searchSnippetLength-searchSnippetLead-2) + "…"},
+		{"falls back to the description",
+			&model.Project{Overview: "概要", Description: "Written in Go"},
+			"Written in <mark>Go</mark>"},
+		{"only a word prefix matches",
+			&model.Project{Overview: "ergo go"},
+			"ergo <mark>go</mark>"},
+		{"no match uses the start of the overview",
+			&model.Project{Name: "go", Overview: strings.Repeat("う", 130)},
+			strings.Repeat("う", searchSnippetLength) + "…"},
+	}
+	for _, tt := range tests {
+		if got := highlightProject(tt.p, terms).Snippet; got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -8,10 +8,18 @@ import (
 
 // ProjectService はプロジェクトに関するビジネスロジックのインターフェース
 type ProjectService interface {
-	List(ctx context.Context, sort string, limit int, cursor string) (*model.ProjectListResult, error)
+	List(ctx context.Context, sort string, filter model.ProjectFilter, limit int, cursor string) (*model.ProjectListResult, error)
+	Facets(ctx context.Context, filter model.ProjectFilter) (*model.ProjectFacets, error)
+	Search(ctx context.Context, query, sort string, limit int, cursor string) (*model.ProjectSearchResult, error)
 	GetByID(ctx context.Context, id string) (*model.Project, error)
 	ListByOwnerID(ctx context.Context, ownerID string) ([]*model.Project, error)
 	Create(ctx context.Context, project *model.Project) error
 	Update(ctx context.Context, project *model.Project) error
+	// UpdateWithRevision は Update と同じく更新し、変更した項目を actorID による更新履歴として記録する
+	UpdateWithRevision(ctx context.Context, actorID string, project *model.Project) error
+	// ListRevisions はプロジェクトの更新履歴を新しい順に返す
+	ListRevisions(ctx context.Context, projectID string) ([]*model.ProjectRevision, error)
+	// RestoreRevision はプロジェクトを revisionID の更新後の内容に戻し、復元を更新履歴として記録する
+	RestoreRevision(ctx context.Context, actorID, projectID, revisionID string) (*model.Project, error)
 	Delete(ctx context.Context, id string) error
 }
@@ -2,11 +2,67 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/givers/backend/internal/model"
 	"github.com/givers/backend/internal/repository"
 )
 
+// ErrInvalidDonationTiers は寄付の目安額の設定が不正な場合のエラー
+var ErrInvalidDonationTiers = errors.New("invalid donation tiers")
+
+// ErrInvalidPaymentMethodTypes は受け付ける支払い方法の設定が不正な場合のエラー
+var ErrInvalidPaymentMethodTypes = errors.New("invalid payment method types")
+
+// ErrInvalidProjectCategory はカテゴリが存在しない・形式が不正な場合のエラー
+var ErrInvalidProjectCategory = errors.New("invalid project category")
+
+// ErrInvalidProjectTags はタグの数・長さが不正な場合のエラー
+var ErrInvalidProjectTags = errors.New("invalid project tags")
+
+// ErrInvalidProjectSlug はスラッグの形式が不正・予約語・UUID の形の場合のエラー
+var ErrInvalidProjectSlug = errors.New("invalid project slug")
+
+// ErrProjectSlugTaken はスラッグを他のプロジェクトが使っている（または以前使っていた）場合のエラー
+var ErrProjectSlugTaken = errors.New("project slug taken")
+
+// スラッグの長さと形式。UUID の形のものは ID と区別できないため使えない
+const (
+	minProjectSlugLength = 3
+	maxProjectSlugLength = 60
+)
+
+var (
+	reProjectSlug = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	reUUID        = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+)
+
+// reservedProjectSlugs は API やフロントエンドのパス（/projects/new など）と重なるため使えないスラッグ
+var reservedProjectSlugs = []string{
+	"admin", "api", "create", "edit", "facets", "host", "index", "invitations", "me", "members",
+	"new", "projects", "search", "settings", "updates",
+}
+
+// 寄付の目安額の上限
+const (
+	maxDonationTiers           = 10
+	maxDonationTierLabel       = 50
+	maxDonationTierDescription = 200
+)
+
+// タグの上限
+const (
+	maxProjectTags      = 10
+	maxProjectTagLength = 30
+)
+
 // ProjectServiceImpl は ProjectService の実装
 type ProjectServiceImpl struct {
 	projectRepo repository.ProjectRepository
@@ -18,11 +74,27 @@ func NewProjectService(projectRepo repository.ProjectRepository) ProjectService
 }
 
 // List はプロジェクト一覧を取得する
-func (s *ProjectServiceImpl) List(ctx context.Context, sort string, limit int, cursor string) (*model.ProjectListResult, error) {
+func (s *ProjectServiceImpl) List(ctx context.Context, sort string, filter model.ProjectFilter, limit int, cursor string) (*model.ProjectListResult, error) {
 	if sort == "" {
 		sort = "new"
 	}
-	return s.projectRepo.List(ctx, sort, limit, cursor)
+	return s.projectRepo.List(ctx, sort, normalizeProjectFilter(filter), limit, cursor)
+}
+
+// Facets は絞り込み条件でのカテゴリ別・タグ別のプロジェクト数を返す
+func (s *ProjectServiceImpl) Facets(ctx context.Context, filter model.ProjectFilter) (*model.ProjectFacets, error) {
+	return s.projectRepo.Facets(ctx, normalizeProjectFilter(filter))
+}
+
+// normalizeProjectFilter は保存時と同じ正規化をかけて、指定されたタグで一致させる（空のタグは無視する）
+func normalizeProjectFilter(filter model.ProjectFilter) model.ProjectFilter {
+	out := model.ProjectFilter{Category: strings.TrimSpace(filter.Category)}
+	for _, t := range filter.Tags {
+		if t = normalizeProjectTag(t); t != "" && !slices.Contains(out.Tags, t) {
+			out.Tags = append(out.Tags, t)
+		}
+	}
+	return out
 }
 
 // GetByID は ID でプロジェクトを取得する
@@ -40,12 +112,160 @@ func (s *ProjectServiceImpl) Create(ctx context.Context, project *model.Project)
 	if project.Status == "" {
 		project.Status = "active"
 	}
-	return s.projectRepo.Create(ctx, project)
+	if err := normalizeProject(project); err != nil {
+		return err
+	}
+	return projectWriteError(s.projectRepo.Create(ctx, project), project)
 }
 
 // Update はプロジェクトを更新する
 func (s *ProjectServiceImpl) Update(ctx context.Context, project *model.Project) error {
-	return s.projectRepo.Update(ctx, project)
+	if err := normalizeProject(project); err != nil {
+		return err
+	}
+	return projectWriteError(s.projectRepo.Update(ctx, project), project)
+}
+
+// normalizeProject は作成・更新時の入力（寄付の目安額・支払い方法・カテゴリとタグ・スラッグ）を正規化・検証する
+func normalizeProject(project *model.Project) error {
+	if err := normalizeDonationTiers(project.DonationTiers); err != nil {
+		return err
+	}
+	types, err := normalizePaymentMethodTypes(project.PaymentMethodTypes)
+	if err != nil {
+		return err
+	}
+	project.PaymentMethodTypes = types
+	if err := normalizeProjectTaxonomy(project); err != nil {
+		return err
+	}
+	if err := normalizeProjectSlug(project); err != nil {
+		return err
+	}
+	return nil
+}
+
+// normalizeProjectTaxonomy はカテゴリの前後の空白を除き、タグを正規化・検証する
+func normalizeProjectTaxonomy(project *model.Project) error {
+	project.Category = strings.TrimSpace(project.Category)
+	if len(project.Tags) > maxProjectTags {
+		return fmt.Errorf("%w: at most %d tags", ErrInvalidProjectTags, maxProjectTags)
+	}
+	var tags []string
+	for _, t := range project.Tags {
+		t = normalizeProjectTag(t)
+		if t == "" || utf8.RuneCountInString(t) > maxProjectTagLength {
+			return fmt.Errorf("%w: tag must be 1-%d characters", ErrInvalidProjectTags, maxProjectTagLength)
+		}
+		if !slices.Contains(tags, t) {
+			tags = append(tags, t)
+		}
+	}
+	project.Tags = tags
+	return nil
+}
+
+// normalizeProjectTag は先頭の # を除き、全角英数字を半角に、大文字を小文字にそろえ、空白をハイフンにする
+// （"#Open Source" と "open-source" を同じタグとして扱う）
+func normalizeProjectTag(tag string) string {
+	tag = strings.TrimLeft(strings.TrimSpace(tag), "#＃")
+	return strings.Join(strings.Fields(string(foldSearchRunes(tag))), "-")
+}
+
+// projectWriteError は存在しないカテゴリ・使用中のスラッグをサービスのエラーに変換する
+func projectWriteError(err error, project *model.Project) error {
+	switch {
+	case errors.Is(err, repository.ErrUnknownCategory):
+		return fmt.Errorf("%w: unknown category %q", ErrInvalidProjectCategory, project.Category)
+	case errors.Is(err, repository.ErrSlugTaken):
+		return fmt.Errorf("%w: %q", ErrProjectSlugTaken, project.Slug)
+	}
+	return err
+}
+
+// normalizeProjectSlug はスラッグを小文字にそろえて検証する（空は未設定）
+func normalizeProjectSlug(project *model.Project) error {
+	project.Slug = strings.ToLower(strings.TrimSpace(project.Slug))
+	slug := project.Slug
+	switch {
+	case slug == "":
+		return nil
+	case len(slug) < minProjectSlugLength || len(slug) > maxProjectSlugLength || !reProjectSlug.MatchString(slug):
+		return fmt.Errorf("%w: slug must be %d-%d lowercase letters, digits and hyphens", ErrInvalidProjectSlug, minProjectSlugLength, maxProjectSlugLength)
+	case reUUID.MatchString(slug):
+		return fmt.Errorf("%w: slug must not look like a project ID", ErrInvalidProjectSlug)
+	case slices.Contains(reservedProjectSlugs, slug):
+		return fmt.Errorf("%w: %q is reserved", ErrInvalidProjectSlug, slug)
+	}
+	return nil
+}
+
+// normalizePaymentMethodTypes は支払い方法を検証し、カードを先頭に重複を除いて返す。
+// カードは常に受け付けるため、カードのみの場合は nil を返す。
+func normalizePaymentMethodTypes(types []string) ([]string, error) {
+	out := []string{model.CheckoutMethodCard}
+	for _, m := range types {
+		if !model.ValidCheckoutMethod(m) {
+			return nil, fmt.Errorf("%w: unknown payment method %q", ErrInvalidPaymentMethodTypes, m)
+		}
+		if !slices.Contains(out, m) {
+			out = append(out, m)
+		}
+	}
+	if len(out) == 1 {
+		return nil, nil
+	}
+	return out, nil
+}
+
+// normalizeDonationTiers は目安額を検証し、ID のない（新しく追加された）目安額に ID を振る。
+// 既存の目安額は ID を送り返してもらうことで、過去の寄付との対応を保つ。
+func normalizeDonationTiers(tiers []model.DonationTier) error {
+	if len(tiers) > maxDonationTiers {
+		return fmt.Errorf("%w: at most %d tiers", ErrInvalidDonationTiers, maxDonationTiers)
+	}
+	seen := map[string]bool{}
+	for i := range tiers {
+		t := &tiers[i]
+		t.Label = strings.TrimSpace(t.Label)
+		t.Description = strings.TrimSpace(t.Description)
+		if !t.IsRecurring || t.Interval == model.IntervalMonth {
+			t.Interval = ""
+		}
+		switch {
+		case t.Label == "" || utf8.RuneCountInString(t.Label) > maxDonationTierLabel:
+			return fmt.Errorf("%w: label must be 1-%d characters", ErrInvalidDonationTiers, maxDonationTierLabel)
+		case utf8.RuneCountInString(t.Description) > maxDonationTierDescription:
+			return fmt.Errorf("%w: description must be at most %d characters", ErrInvalidDonationTiers, maxDonationTierDescription)
+		case t.Amount <= 0:
+			return fmt.Errorf("%w: amount must be greater than 0", ErrInvalidDonationTiers)
+		case t.Interval != "" && !model.ValidInterval(t.Interval):
+			return fmt.Errorf("%w: unknown interval %q", ErrInvalidDonationTiers, t.Interval)
+		case len(t.ID) > 32:
+			return fmt.Errorf("%w: id is too long", ErrInvalidDonationTiers)
+		}
+		if t.ID == "" {
+			id, err := newDonationTierID()
+			if err != nil {
+				return fmt.Errorf("generate tier id: %w", err)
+			}
+			t.ID = id
+		}
+		if seen[t.ID] {
+			return fmt.Errorf("%w: duplicate id %q", ErrInvalidDonationTiers, t.ID)
+		}
+		seen[t.ID] = true
+	}
+	return nil
+}
+
+// newDonationTierID は目安額の ID（16 文字の hex）を生成する
+func newDonationTierID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
 }
 
 // Delete はプロジェクトを削除する
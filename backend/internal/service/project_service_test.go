@@ -3,28 +3,51 @@ package service
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
 )
 
 // mockProjectRepository は ProjectRepository のモック
 type mockProjectRepository struct {
-	listFunc          func(ctx context.Context, sort string, limit int, cursor string) (*model.ProjectListResult, error)
+	listFunc          func(ctx context.Context, sort string, filter model.ProjectFilter, limit int, cursor string) (*model.ProjectListResult, error)
+	facetsFunc        func(ctx context.Context, filter model.ProjectFilter) (*model.ProjectFacets, error)
+	searchFunc        func(ctx context.Context, q model.ProjectSearchQuery) (*model.ProjectSearchResult, error)
 	getByIDFunc       func(ctx context.Context, id string) (*model.Project, error)
 	listByOwnerIDFunc func(ctx context.Context, ownerID string) ([]*model.Project, error)
 	createFunc        func(ctx context.Context, project *model.Project) error
 	updateFunc        func(ctx context.Context, project *model.Project) error
 	deleteFunc        func(ctx context.Context, id string) error
+
+	updateWithRevisionFunc func(ctx context.Context, project *model.Project, rev *model.ProjectRevision) error
+	listRevisionsFunc      func(ctx context.Context, projectID string) ([]*model.ProjectRevision, error)
+	getRevisionFunc        func(ctx context.Context, projectID, revisionID string) (*model.ProjectRevision, error)
 }
 
-func (m *mockProjectRepository) List(ctx context.Context, sort string, limit int, cursor string) (*model.ProjectListResult, error) {
+func (m *mockProjectRepository) List(ctx context.Context, sort string, filter model.ProjectFilter, limit int, cursor string) (*model.ProjectListResult, error) {
 	if m.listFunc != nil {
-		return m.listFunc(ctx, sort, limit, cursor)
+		return m.listFunc(ctx, sort, filter, limit, cursor)
 	}
 	return &model.ProjectListResult{}, nil
 }
 
+func (m *mockProjectRepository) Facets(ctx context.Context, filter model.ProjectFilter) (*model.ProjectFacets, error) {
+	if m.facetsFunc != nil {
+		return m.facetsFunc(ctx, filter)
+	}
+	return &model.ProjectFacets{}, nil
+}
+
+func (m *mockProjectRepository) Search(ctx context.Context, q model.ProjectSearchQuery) (*model.ProjectSearchResult, error) {
+	if m.searchFunc != nil {
+		return m.searchFunc(ctx, q)
+	}
+	return &model.ProjectSearchResult{}, nil
+}
+
 func (m *mockProjectRepository) GetByID(ctx context.Context, id string) (*model.Project, error) {
 	if m.getByIDFunc != nil {
 		return m.getByIDFunc(ctx, id)
@@ -53,6 +76,27 @@ func (m *mockProjectRepository) Update(ctx context.Context, project *model.Proje
 	return nil
 }
 
+func (m *mockProjectRepository) UpdateWithRevision(ctx context.Context, project *model.Project, rev *model.ProjectRevision) error {
+	if m.updateWithRevisionFunc != nil {
+		return m.updateWithRevisionFunc(ctx, project, rev)
+	}
+	return nil
+}
+
+func (m *mockProjectRepository) ListRevisions(ctx context.Context, projectID string) ([]*model.ProjectRevision, error) {
+	if m.listRevisionsFunc != nil {
+		return m.listRevisionsFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+
+func (m *mockProjectRepository) GetRevision(ctx context.Context, projectID, revisionID string) (*model.ProjectRevision, error) {
+	if m.getRevisionFunc != nil {
+		return m.getRevisionFunc(ctx, projectID, revisionID)
+	}
+	return nil, repository.ErrNotFound
+}
+
 func (m *mockProjectRepository) Delete(ctx context.Context, id string) error {
 	if m.deleteFunc != nil {
 		return m.deleteFunc(ctx, id)
@@ -75,7 +119,7 @@ func TestProjectService_List(t *testing.T) {
 	}
 
 	mock := &mockProjectRepository{
-		listFunc: func(ctx context.Context, sort string, limit int, cursor string) (*model.ProjectListResult, error) {
+		listFunc: func(ctx context.Context, sort string, filter model.ProjectFilter, limit int, cursor string) (*model.ProjectListResult, error) {
 			if sort != "new" {
 				t.Errorf("expected sort=new (default), got %q", sort)
 			}
@@ -90,7 +134,7 @@ func TestProjectService_List(t *testing.T) {
 	}
 
 	svc := NewProjectService(mock)
-	got, err := svc.List(ctx, "", 10, "")
+	got, err := svc.List(ctx, "", model.ProjectFilter{}, 10, "")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -106,7 +150,7 @@ func TestProjectService_List_SortHot(t *testing.T) {
 	}
 
 	mock := &mockProjectRepository{
-		listFunc: func(ctx context.Context, sort string, limit int, cursor string) (*model.ProjectListResult, error) {
+		listFunc: func(ctx context.Context, sort string, filter model.ProjectFilter, limit int, cursor string) (*model.ProjectListResult, error) {
 			if sort != "hot" {
 				t.Errorf("expected sort=hot, got %q", sort)
 			}
@@ -115,7 +159,7 @@ func TestProjectService_List_SortHot(t *testing.T) {
 	}
 
 	svc := NewProjectService(mock)
-	got, err := svc.List(ctx, "hot", 20, "")
+	got, err := svc.List(ctx, "hot", model.ProjectFilter{}, 20, "")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -132,7 +176,7 @@ func TestProjectService_List_WithCursor(t *testing.T) {
 	}
 
 	mock := &mockProjectRepository{
-		listFunc: func(ctx context.Context, sort string, limit int, cursor string) (*model.ProjectListResult, error) {
+		listFunc: func(ctx context.Context, sort string, filter model.ProjectFilter, limit int, cursor string) (*model.ProjectListResult, error) {
 			if cursor != "cursor-abc" {
 				t.Errorf("expected cursor=cursor-abc, got %q", cursor)
 			}
@@ -141,7 +185,7 @@ func TestProjectService_List_WithCursor(t *testing.T) {
 	}
 
 	svc := NewProjectService(mock)
-	got, err := svc.List(ctx, "new", 20, "cursor-abc")
+	got, err := svc.List(ctx, "new", model.ProjectFilter{}, 20, "cursor-abc")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -215,3 +259,159 @@ func TestProjectService_Create_PreservesStatus(t *testing.T) {
 		t.Errorf("expected status=draft, got %q", created.Status)
 	}
 }
+
+func TestProjectService_Create_NormalizesDonationTiers(t *testing.T) {
+	svc := NewProjectService(&mockProjectRepository{})
+	p := &model.Project{OwnerID: "u1", Name: "Test", DonationTiers: []model.DonationTier{
+		{ID: "keep", Label: " 応援 ", Amount: 500, Interval: model.IntervalYear},
+		{Label: "月額サポーター", Amount: 3000, IsRecurring: true, Interval: model.IntervalMonth},
+		{Label: "年間サポーター", Amount: 30000, IsRecurring: true, Interval: model.IntervalYear},
+	}}
+	if err := svc.Create(context.Background(), p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tiers := p.DonationTiers
+	if tiers[0].ID != "keep" || tiers[0].Label != "応援" || tiers[0].Interval != "" {
+		t.Errorf("unexpected first tier: %+v", tiers[0])
+	}
+	if tiers[1].ID == "" || tiers[2].ID == "" || tiers[1].ID == tiers[2].ID {
+		t.Errorf("expected new tiers to get distinct IDs, got %q %q", tiers[1].ID, tiers[2].ID)
+	}
+	if tiers[1].Interval != "" || tiers[2].Interval != model.IntervalYear {
+		t.Errorf("unexpected intervals: %q %q", tiers[1].Interval, tiers[2].Interval)
+	}
+}
+
+func TestProjectService_Update_InvalidDonationTiers(t *testing.T) {
+	mock := &mockProjectRepository{
+		updateFunc: func(ctx context.Context, project *model.Project) error {
+			t.Error("invalid tiers must not be saved")
+			return nil
+		},
+	}
+	svc := NewProjectService(mock)
+
+	tests := []struct {
+		name  string
+		tiers []model.DonationTier
+	}{
+		{"empty label", []model.DonationTier{{Label: " ", Amount: 500}}},
+		{"zero amount", []model.DonationTier{{Label: "応援", Amount: 0}}},
+		{"unknown interval", []model.DonationTier{{Label: "応援", Amount: 500, IsRecurring: true, Interval: "week"}}},
+		{"duplicate id", []model.DonationTier{{ID: "a", Label: "A", Amount: 500}, {ID: "a", Label: "B", Amount: 1000}}},
+		{"too many", make([]model.DonationTier, maxDonationTiers+1)},
+	}
+	for _, tt := range tests {
+		p := &model.Project{ID: "p1", Name: "Test", DonationTiers: tt.tiers}
+		if err := svc.Update(context.Background(), p); !errors.Is(err, ErrInvalidDonationTiers) {
+			t.Errorf("%s: expected ErrInvalidDonationTiers, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestProjectService_Update_NormalizesPaymentMethodTypes(t *testing.T) {
+	svc := NewProjectService(&mockProjectRepository{})
+
+	tests := []struct {
+		name  string
+		types []string
+		want  []string
+	}{
+		{"card only", []string{model.CheckoutMethodCard}, nil},
+		{"unset", nil, nil},
+		{"card is added first", []string{model.CheckoutMethodKonbini, model.CheckoutMethodKonbini},
+			[]string{model.CheckoutMethodCard, model.CheckoutMethodKonbini}},
+		{"all", []string{model.CheckoutMethodBankTransfer, model.CheckoutMethodCard, model.CheckoutMethodKonbini},
+			[]string{model.CheckoutMethodCard, model.CheckoutMethodBankTransfer, model.CheckoutMethodKonbini}},
+	}
+	for _, tt := range tests {
+		p := &model.Project{ID: "p1", Name: "Test", PaymentMethodTypes: tt.types}
+		if err := svc.Update(context.Background(), p); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if !slices.Equal(p.PaymentMethodTypes, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, p.PaymentMethodTypes, tt.want)
+		}
+	}
+
+	p := &model.Project{ID: "p1", Name: "Test", PaymentMethodTypes: []string{"paypay"}}
+	if err := svc.Update(context.Background(), p); !errors.Is(err, ErrInvalidPaymentMethodTypes) {
+		t.Errorf("expected ErrInvalidPaymentMethodTypes, got %v", err)
+	}
+}
+
+func TestProjectService_Create_NormalizesTags(t *testing.T) {
+	svc := NewProjectService(&mockProjectRepository{})
+	p := &model.Project{Name: "Test", Category: " games ", Tags: []string{" #Go ", "Open  Source", "ｇｏ", "ゲーム"}}
+	if err := svc.Create(context.Background(), p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Category != "games" {
+		t.Errorf("expected category to be trimmed, got %q", p.Category)
+	}
+	if want := []string{"go", "open-source", "ゲーム"}; !slices.Equal(p.Tags, want) {
+		t.Errorf("got %v, want %v", p.Tags, want)
+	}
+
+	for _, tags := range [][]string{{"#"}, {strings.Repeat("a", maxProjectTagLength+1)}, make([]string, maxProjectTags+1)} {
+		p := &model.Project{Name: "Test", Tags: tags}
+		if err := svc.Create(context.Background(), p); !errors.Is(err, ErrInvalidProjectTags) {
+			t.Errorf("%v: expected ErrInvalidProjectTags, got %v", tags, err)
+		}
+	}
+}
+
+func TestProjectService_Update_UnknownCategory(t *testing.T) {
+	svc := NewProjectService(&mockProjectRepository{
+		updateFunc: func(_ context.Context, _ *model.Project) error { return repository.ErrUnknownCategory },
+	})
+	err := svc.Update(context.Background(), &model.Project{ID: "p1", Name: "Test", Category: "nope"})
+	if !errors.Is(err, ErrInvalidProjectCategory) {
+		t.Errorf("expected ErrInvalidProjectCategory, got %v", err)
+	}
+}
+
+func TestProjectService_Create_ValidatesSlug(t *testing.T) {
+	svc := NewProjectService(&mockProjectRepository{})
+	p := &model.Project{Name: "Test", Slug: " My-Project-2 "}
+	if err := svc.Create(context.Background(), p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Slug != "my-project-2" {
+		t.Errorf("expected slug to be normalized, got %q", p.Slug)
+	}
+
+	for _, slug := range []string{"ab", "my_project", "-givers", "givers-", "a--b", "ぎばーず", strings.Repeat("a", maxProjectSlugLength+1),
+		"new", "search", "0b6f3c52-8f0e-4a4e-9d8a-3f1c2b7e5a10"} {
+		p := &model.Project{Name: "Test", Slug: slug}
+		if err := svc.Create(context.Background(), p); !errors.Is(err, ErrInvalidProjectSlug) {
+			t.Errorf("%q: expected ErrInvalidProjectSlug, got %v", slug, err)
+		}
+	}
+}
+
+func TestProjectService_Update_SlugTaken(t *testing.T) {
+	svc := NewProjectService(&mockProjectRepository{
+		updateFunc: func(_ context.Context, _ *model.Project) error { return repository.ErrSlugTaken },
+	})
+	err := svc.Update(context.Background(), &model.Project{ID: "p1", Name: "Test", Slug: "givers"})
+	if !errors.Is(err, ErrProjectSlugTaken) {
+		t.Errorf("expected ErrProjectSlugTaken, got %v", err)
+	}
+}
+
+func TestProjectService_Facets_NormalizesFilter(t *testing.T) {
+	var got model.ProjectFilter
+	svc := NewProjectService(&mockProjectRepository{
+		facetsFunc: func(_ context.Context, filter model.ProjectFilter) (*model.ProjectFacets, error) {
+			got = filter
+			return &model.ProjectFacets{}, nil
+		},
+	})
+	if _, err := svc.Facets(context.Background(), model.ProjectFilter{Category: "games", Tags: []string{"#Go", "", "go"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Category != "games" || !slices.Equal(got.Tags, []string{"go"}) {
+		t.Errorf("unexpected filter: %+v", got)
+	}
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/givers/backend/internal/model"
+)
+
+// ErrNoReceiptPayments is returned when there is no payment to list on a receipt
+// (the donation has not been charged yet, or nothing was given in that year).
+var ErrNoReceiptPayments = errors.New("no payments for receipt")
+
+// ErrInvalidReceiptYear is returned for a statement year before receiptFirstYear or in the future.
+var ErrInvalidReceiptYear = errors.New("invalid receipt year")
+
+// ReceiptTimeZone is the time zone of receipt dates and statement years (calendar years in Japan time).
+var ReceiptTimeZone = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// receiptFirstYear は年間寄付記録を発行できる最初の年
+const receiptFirstYear = 2020
+
+// ---------------------------------------------------------------------------
+// Minimal interfaces (only what ReceiptService needs)
+// ---------------------------------------------------------------------------
+
+type ReceiptRepo interface {
+	GetDonation(ctx context.Context, id string) (*model.Donation, error)
+	IssueDonationReceipt(ctx context.Context, userID, donationID string) (model.ReceiptNumber, error)
+	IssueAnnualStatement(ctx context.Context, userID string, year int) (model.ReceiptNumber, error)
+	ListLinesByDonation(ctx context.Context, donationID string) ([]*model.ReceiptLine, error)
+	ListLinesByDonor(ctx context.Context, userID string, from, to time.Time) ([]*model.ReceiptLine, error)
+}
+
+type ReceiptUserGetter interface {
+	FindByID(ctx context.Context, id string) (*model.User, error)
+}
+
+// ---------------------------------------------------------------------------
+// ReceiptService
+// ---------------------------------------------------------------------------
+
+// ReceiptService builds donor receipts from the payments ledger.
+// Receipts list net amounts (refunds and disputes subtracted); fully refunded
+// payments and voided manual donations are left out.
+type ReceiptService interface {
+	// DonationReceipt returns the receipt of one of userID's donations.
+	// Returns ErrForbidden if the donation belongs to someone else.
+	DonationReceipt(ctx context.Context, userID, donationID string) (*model.Receipt, error)
+	// AnnualStatement returns every donation userID paid in year, across all projects.
+	AnnualStatement(ctx context.Context, userID string, year int) (*model.Receipt, error)
+}
+
+type receiptService struct {
+	repo  ReceiptRepo
+	users ReceiptUserGetter
+	now   func() time.Time
+}
+
+// NewReceiptService creates a ReceiptService.
+func NewReceiptService(repo ReceiptRepo, users ReceiptUserGetter) ReceiptService {
+	return &receiptService{repo: repo, users: users, now: time.Now}
+}
+
+func (s *receiptService) DonationReceipt(ctx context.Context, userID, donationID string) (*model.Receipt, error) {
+	d, err := s.repo.GetDonation(ctx, donationID)
+	if err != nil {
+		return nil, err
+	}
+	if d.DonorType != "user" || d.DonorID != userID {
+		return nil, ErrForbidden
+	}
+	if d.VoidedAt != nil {
+		return nil, ErrDonationVoided
+	}
+
+	lines, err := s.repo.ListLinesByDonation(ctx, donationID)
+	if err != nil {
+		return nil, fmt.Errorf("list receipt payments: %w", err)
+	}
+	rc := &model.Receipt{Kind: model.ReceiptKindDonation, DonationID: donationID}
+	if err := s.fill(ctx, rc, userID, lines); err != nil {
+		return nil, err
+	}
+	if rc.ReceiptNumber, err = s.repo.IssueDonationReceipt(ctx, userID, donationID); err != nil {
+		return nil, fmt.Errorf("issue receipt number: %w", err)
+	}
+	return rc, nil
+}
+
+func (s *receiptService) AnnualStatement(ctx context.Context, userID string, year int) (*model.Receipt, error) {
+	if year < receiptFirstYear || year > s.now().In(ReceiptTimeZone).Year() {
+		return nil, ErrInvalidReceiptYear
+	}
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, ReceiptTimeZone)
+	lines, err := s.repo.ListLinesByDonor(ctx, userID, from, from.AddDate(1, 0, 0))
+	if err != nil {
+		return nil, fmt.Errorf("list statement payments: %w", err)
+	}
+	rc := &model.Receipt{Kind: model.ReceiptKindAnnual, Year: year}
+	if err := s.fill(ctx, rc, userID, lines); err != nil {
+		return nil, err
+	}
+	if rc.ReceiptNumber, err = s.repo.IssueAnnualStatement(ctx, userID, year); err != nil {
+		return nil, fmt.Errorf("issue statement number: %w", err)
+	}
+	return rc, nil
+}
+
+// fill は返金済みの入金を除いた明細・通貨別合計・寄付者名を設定する。
+// 番号は明細があると分かってから採番する（空の記録で番号を消費しない）。
+func (s *receiptService) fill(ctx context.Context, rc *model.Receipt, userID string, lines []*model.ReceiptLine) error {
+	totals := map[string]int{}
+	for _, l := range lines {
+		if l.Amount <= 0 {
+			continue
+		}
+		if _, ok := totals[l.Currency]; !ok {
+			rc.Totals = append(rc.Totals, model.CurrencyAmount{Currency: l.Currency})
+		}
+		totals[l.Currency] += l.Amount
+		rc.Lines = append(rc.Lines, l)
+	}
+	if len(rc.Lines) == 0 {
+		return ErrNoReceiptPayments
+	}
+	for i := range rc.Totals {
+		rc.Totals[i].Amount = totals[rc.Totals[i].Currency]
+	}
+
+	u, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("find donor: %w", err)
+	}
+	rc.DonorName = u.Name
+	return nil
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+)
+
+// ---------------------------------------------------------------------------
+// Mocks
+// ---------------------------------------------------------------------------
+
+type mockReceiptRepo struct {
+	donations  map[string]*model.Donation
+	lines      []*model.ReceiptLine
+	issued     []string // 採番した対象（donation ID または "annual:<year>"）
+	gotFrom    time.Time
+	gotTo      time.Time
+	nextNumber int64
+}
+
+func (m *mockReceiptRepo) GetDonation(_ context.Context, id string) (*model.Donation, error) {
+	d, ok := m.donations[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return d, nil
+}
+
+func (m *mockReceiptRepo) issue(key string) model.ReceiptNumber {
+	m.issued = append(m.issued, key)
+	m.nextNumber++
+	return model.ReceiptNumber{Number: m.nextNumber, IssuedAt: time.Now()}
+}
+
+func (m *mockReceiptRepo) IssueDonationReceipt(_ context.Context, _, donationID string) (model.ReceiptNumber, error) {
+	return m.issue(donationID), nil
+}
+
+func (m *mockReceiptRepo) IssueAnnualStatement(_ context.Context, _ string, year int) (model.ReceiptNumber, error) {
+	return m.issue("annual:" + strconv.Itoa(year)), nil
+}
+
+func (m *mockReceiptRepo) ListLinesByDonation(_ context.Context, donationID string) ([]*model.ReceiptLine, error) {
+	var list []*model.ReceiptLine
+	for _, l := range m.lines {
+		if l.DonationID == donationID {
+			list = append(list, l)
+		}
+	}
+	return list, nil
+}
+
+func (m *mockReceiptRepo) ListLinesByDonor(_ context.Context, _ string, from, to time.Time) ([]*model.ReceiptLine, error) {
+	m.gotFrom, m.gotTo = from, to
+	return m.lines, nil
+}
+
+type mockReceiptUsers struct{}
+
+func (mockReceiptUsers) FindByID(_ context.Context, id string) (*model.User, error) {
+	return &model.User{ID: id, Name: "寄付 太郎"}, nil
+}
+
+// ---------------------------------------------------------------------------
+// Tests
+// ---------------------------------------------------------------------------
+
+func TestReceiptService_DonationReceipt(t *testing.T) {
+	repo := &mockReceiptRepo{
+		donations: map[string]*model.Donation{"d1": {ID: "d1", DonorType: "user", DonorID: "u1"}},
+		lines: []*model.ReceiptLine{
+			{DonationID: "d1", ProjectName: "P", OwnerName: "Owner", Amount: 1000, Currency: "jpy"},
+			{DonationID: "d1", ProjectName: "P", OwnerName: "Owner", Amount: 0, Currency: "jpy"}, // 全額返金
+			{DonationID: "d1", ProjectName: "P", OwnerName: "Owner", Amount: 500, Currency: "jpy"},
+		},
+	}
+	svc := NewReceiptService(repo, mockReceiptUsers{})
+
+	rc, err := svc.DonationReceipt(context.Background(), "u1", "d1")
+	if err != nil {
+		t.Fatalf("DonationReceipt: %v", err)
+	}
+	if rc.Kind != model.ReceiptKindDonation || rc.Number != 1 || rc.DonorName != "寄付 太郎" {
+		t.Errorf("receipt = %+v", rc)
+	}
+	if len(rc.Lines) != 2 {
+		t.Errorf("lines = %d, want 2 (fully refunded payment excluded)", len(rc.Lines))
+	}
+	if len(rc.Totals) != 1 || rc.Totals[0] != (model.CurrencyAmount{Currency: "jpy", Amount: 1500}) {
+		t.Errorf("totals = %+v", rc.Totals)
+	}
+}
+
+func TestReceiptService_DonationReceipt_Errors(t *testing.T) {
+	voided := time.Now()
+	repo := &mockReceiptRepo{donations: map[string]*model.Donation{
+		"other":  {ID: "other", DonorType: "user", DonorID: "u2"},
+		"token":  {ID: "token", DonorType: "token", DonorID: "u1"},
+		"voided": {ID: "voided", DonorType: "user", DonorID: "u1", VoidedAt: &voided},
+		"unpaid": {ID: "unpaid", DonorType: "user", DonorID: "u1"},
+	}}
+	svc := NewReceiptService(repo, mockReceiptUsers{})
+
+	tests := []struct {
+		id   string
+		want error
+	}{
+		{"other", ErrForbidden},
+		{"token", ErrForbidden},
+		{"voided", ErrDonationVoided},
+		{"unpaid", ErrNoReceiptPayments},
+		{"missing", repository.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if _, err := svc.DonationReceipt(context.Background(), "u1", tt.id); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	if len(repo.issued) != 0 {
+		t.Errorf("no receipt number should be issued on error, issued %v", repo.issued)
+	}
+}
+
+func TestReceiptService_AnnualStatement(t *testing.T) {
+	repo := &mockReceiptRepo{lines: []*model.ReceiptLine{
+		{DonationID: "d1", ProjectName: "A", Amount: 1000, Currency: "jpy"},
+		{DonationID: "d2", ProjectName: "B", Amount: 1250, Currency: "usd"},
+		{DonationID: "d3", ProjectName: "C", Amount: 2000, Currency: "jpy"},
+	}}
+	svc := NewReceiptService(repo, mockReceiptUsers{}).(*receiptService)
+	svc.now = func() time.Time { return time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC) }
+
+	rc, err := svc.AnnualStatement(context.Background(), "u1", 2025)
+	if err != nil {
+		t.Fatalf("AnnualStatement: %v", err)
+	}
+	if rc.Kind != model.ReceiptKindAnnual || rc.Year != 2025 || len(rc.Lines) != 3 {
+		t.Errorf("statement = %+v", rc)
+	}
+	want := []model.CurrencyAmount{{Currency: "jpy", Amount: 3000}, {Currency: "usd", Amount: 1250}}
+	if len(rc.Totals) != 2 || rc.Totals[0] != want[0] || rc.Totals[1] != want[1] {
+		t.Errorf("totals = %+v, want %+v", rc.Totals, want)
+	}
+	// 年の区切りは日本時間
+	if !repo.gotFrom.Equal(time.Date(2024, 12, 31, 15, 0, 0, 0, time.UTC)) || !repo.gotTo.Equal(time.Date(2025, 12, 31, 15, 0, 0, 0, time.UTC)) {
+		t.Errorf("range = [%v, %v)", repo.gotFrom, repo.gotTo)
+	}
+	if len(repo.issued) != 1 || repo.issued[0] != "annual:2025" {
+		t.Errorf("issued = %v", repo.issued)
+	}
+}
+
+func TestReceiptService_AnnualStatement_InvalidYear(t *testing.T) {
+	svc := NewReceiptService(&mockReceiptRepo{}, mockReceiptUsers{}).(*receiptService)
+	svc.now = func() time.Time { return time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC) }
+
+	for _, year := range []int{1999, 2027} {
+		if _, err := svc.AnnualStatement(context.Background(), "u1", year); !errors.Is(err, ErrInvalidReceiptYear) {
+			t.Errorf("year %d: err = %v, want ErrInvalidReceiptYear", year, err)
+		}
+	}
+	if _, err := svc.AnnualStatement(context.Background(), "u1", 2026); !errors.Is(err, ErrNoReceiptPayments) {
+		t.Errorf("empty year: err = %v, want ErrNoReceiptPayments", err)
+	}
+}
@@ -0,0 +1,412 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+	pkgstripe "github.com/givers/backend/pkg/stripe"
+)
+
+// ---------------------------------------------------------------------------
+// Minimal interfaces (only what ReconcileService needs)
+// ---------------------------------------------------------------------------
+
+type ReconcileStripeClient interface {
+	ListSubscriptions(ctx context.Context, accountID string) ([]pkgstripe.Subscription, error)
+	GetSubscription(ctx context.Context, accountID, subscriptionID string) (pkgstripe.Subscription, error)
+	ListPaymentIntents(ctx context.Context, accountID string, createdSince time.Time) ([]pkgstripe.PaymentIntent, error)
+	ListConnectedAccounts(ctx context.Context) ([]pkgstripe.ConnectedAccount, error)
+}
+
+type ReconcileDonationRepo interface {
+	ListStripeLinked(ctx context.Context) ([]*model.Donation, error)
+	Create(ctx context.Context, d *model.Donation) error
+	Patch(ctx context.Context, id string, patch model.DonationPatch) error
+	DeleteByStripeSubscriptionID(ctx context.Context, subscriptionID string) error
+}
+
+type ReconcileProjectRepo interface {
+	ListStripeAccountIDs(ctx context.Context) (map[string]string, error)
+}
+
+type ReconcilePaymentLedger interface {
+	Create(ctx context.Context, p *model.DonationPayment) error
+}
+
+// ---------------------------------------------------------------------------
+// Report
+// ---------------------------------------------------------------------------
+
+// Reconcile issue kinds
+const (
+	ReconcileMissingDonation      = "missing_donation"      // Stripe にあるが donations に行がない
+	ReconcileAmountMismatch       = "amount_mismatch"       // 金額が Stripe と異なる
+	ReconcilePausedDrift          = "paused_drift"          // 一時停止状態が Stripe と異なる
+	ReconcileOrphanedSubscription = "orphaned_subscription" // Stripe で終了済み・存在しないサブスクの行が残っている
+	ReconcileMissingAccount       = "missing_account"       // projects.stripe_account_id が Stripe に存在しない
+	ReconcileOrphanedAccount      = "orphaned_account"      // どのプロジェクトにも紐づかない連結アカウント
+)
+
+// ReconcileIssue は Stripe と DB の不一致 1 件
+type ReconcileIssue struct {
+	Kind       string `json:"kind"`
+	StripeID   string `json:"stripe_id"`
+	DonationID string `json:"donation_id,omitempty"`
+	ProjectID  string `json:"project_id,omitempty"`
+	Detail     string `json:"detail,omitempty"`
+	Fixed      bool   `json:"fixed"`
+}
+
+// ReconcileReport は照合結果
+type ReconcileReport struct {
+	Issues []ReconcileIssue `json:"issues"`
+}
+
+// Unresolved は修正されていない不一致の件数を返す
+func (r *ReconcileReport) Unresolved() int {
+	n := 0
+	for _, is := range r.Issues {
+		if !is.Fixed {
+			n++
+		}
+	}
+	return n
+}
+
+// ---------------------------------------------------------------------------
+// ReconcileService
+// ---------------------------------------------------------------------------
+
+// ReconcileService は Stripe 上のサブスクリプション・PaymentIntent・連結アカウントと
+// donations / projects.stripe_account_id を突き合わせる。Webhook の取りこぼしの検出用。
+// 連結アカウントのプロジェクトへの寄付はそのアカウント上で作成されるため、
+// プラットフォームとプロジェクトの各連結アカウントを Stripe-Account 付きで照合する。
+type ReconcileService struct {
+	client        ReconcileStripeClient
+	donationRepo  ReconcileDonationRepo
+	projectRepo   ReconcileProjectRepo
+	paymentLedger ReconcilePaymentLedger // nil なら fix 時に入金台帳へは記録しない
+}
+
+func NewReconcileService(
+	client ReconcileStripeClient,
+	dr ReconcileDonationRepo,
+	pr ReconcileProjectRepo,
+	ledger ReconcilePaymentLedger,
+) *ReconcileService {
+	return &ReconcileService{client: client, donationRepo: dr, projectRepo: pr, paymentLedger: ledger}
+}
+
+// Run は照合を行い、不一致を報告する。
+// fix=true の場合は寄付の不一致を Stripe 側に合わせて修正する（連結アカウントの不一致は報告のみ）。
+// PaymentIntent は createdSince 以降に作成されたものだけを対象にする。
+func (s *ReconcileService) Run(ctx context.Context, createdSince time.Time, fix bool) (*ReconcileReport, error) {
+	donations, err := s.donationRepo.ListStripeLinked(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list donations: %w", err)
+	}
+	bySubscription := map[string]*model.Donation{}
+	byPayment := map[string]*model.Donation{}
+	for _, d := range donations {
+		if d.StripeSubscriptionID != "" {
+			bySubscription[d.StripeSubscriptionID] = d
+		}
+		if d.StripePaymentID != "" {
+			byPayment[d.StripePaymentID] = d
+		}
+	}
+
+	connected, err := s.client.ListConnectedAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list connected accounts: %w", err)
+	}
+	projectAccounts, err := s.projectRepo.ListStripeAccountIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list project accounts: %w", err)
+	}
+	known := map[string]bool{"": true} // "" はプラットフォーム
+	for _, a := range connected {
+		known[a.ID] = true
+	}
+	accounts := scannedAccounts(projectAccounts, known)
+
+	report := &ReconcileReport{Issues: []ReconcileIssue{}}
+	if err := s.reconcileSubscriptions(ctx, accounts, projectAccounts, known, bySubscription, fix, report); err != nil {
+		return nil, err
+	}
+	if err := s.reconcilePaymentIntents(ctx, accounts, byPayment, createdSince, fix, report); err != nil {
+		return nil, err
+	}
+	reconcileAccounts(connected, projectAccounts, report)
+	return report, nil
+}
+
+// scannedAccounts は照合するアカウント（プラットフォームと、Stripe に存在するプロジェクトの連結アカウント）を返す
+func scannedAccounts(projectAccounts map[string]string, known map[string]bool) []string {
+	accounts := []string{""}
+	seen := map[string]bool{"": true}
+	for _, accountID := range projectAccounts {
+		if known[accountID] && !seen[accountID] {
+			seen[accountID] = true
+			accounts = append(accounts, accountID)
+		}
+	}
+	slices.Sort(accounts[1:])
+	return accounts
+}
+
+// subscriptionEnded は Stripe 上で課金が終わったサブスクリプションかどうか
+func subscriptionEnded(status string) bool {
+	return status == "canceled" || status == "incomplete_expired"
+}
+
+func (s *ReconcileService) reconcileSubscriptions(ctx context.Context, accounts []string, projectAccounts map[string]string, known map[string]bool, bySubscription map[string]*model.Donation, fix bool, report *ReconcileReport) error {
+	var subs []pkgstripe.Subscription
+	for _, accountID := range accounts {
+		list, err := s.client.ListSubscriptions(ctx, accountID)
+		if err != nil {
+			return fmt.Errorf("list subscriptions %s: %w", accountLabel(accountID), err)
+		}
+		subs = append(subs, list...)
+	}
+
+	var err error
+	seen := map[string]bool{}
+	for _, sub := range subs {
+		seen[sub.ID] = true
+		projectID := sub.Metadata["project_id"]
+		d, ok := bySubscription[sub.ID]
+		if !ok && projectID == "" {
+			continue // このプラットフォームの寄付ではない
+		}
+		if ok {
+			projectID = d.ProjectID
+		}
+
+		switch {
+		case !ok && !subscriptionEnded(sub.Status):
+			is := ReconcileIssue{Kind: ReconcileMissingDonation, StripeID: sub.ID, ProjectID: projectID,
+				Detail: fmt.Sprintf("subscription %s has no donation row", sub.Status)}
+			if fix {
+				is.DonationID, err = s.createDonation(ctx, sub.Metadata, sub.Amount, sub.Currency, func(d *model.Donation) {
+					d.IsRecurring = true
+					d.Interval = model.IntervalFromStripe(sub.Interval, sub.IntervalCount)
+					d.StripeSubscriptionID = sub.ID
+				})
+				if err != nil {
+					return err
+				}
+				is.Fixed = true
+			}
+			report.Issues = append(report.Issues, is)
+
+		case ok && subscriptionEnded(sub.Status):
+			is := ReconcileIssue{Kind: ReconcileOrphanedSubscription, StripeID: sub.ID, DonationID: d.ID, ProjectID: projectID,
+				Detail: "subscription is " + sub.Status}
+			if fix {
+				if err := s.donationRepo.DeleteByStripeSubscriptionID(ctx, sub.ID); err != nil {
+					return fmt.Errorf("delete donation %s: %w", d.ID, err)
+				}
+				is.Fixed = true
+			}
+			report.Issues = append(report.Issues, is)
+
+		case ok:
+			if sub.Amount > 0 && d.Charge() != sub.Amount {
+				is := ReconcileIssue{Kind: ReconcileAmountMismatch, StripeID: sub.ID, DonationID: d.ID, ProjectID: projectID,
+					Detail: fmt.Sprintf("db=%d stripe=%d", d.Charge(), sub.Amount)}
+				if fix {
+					if err := s.donationRepo.Patch(ctx, d.ID, chargedAmountPatch(d, sub.Amount)); err != nil {
+						return fmt.Errorf("patch donation %s: %w", d.ID, err)
+					}
+					is.Fixed = true
+				}
+				report.Issues = append(report.Issues, is)
+			}
+			if d.Paused != sub.Paused {
+				paused := sub.Paused
+				is := ReconcileIssue{Kind: ReconcilePausedDrift, StripeID: sub.ID, DonationID: d.ID, ProjectID: projectID,
+					Detail: fmt.Sprintf("db=%t stripe=%t", d.Paused, sub.Paused)}
+				if fix {
+					if err := s.donationRepo.Patch(ctx, d.ID, model.DonationPatch{Paused: &paused}); err != nil {
+						return fmt.Errorf("patch donation %s: %w", d.ID, err)
+					}
+					is.Fixed = true
+				}
+				report.Issues = append(report.Issues, is)
+			}
+		}
+	}
+
+	// 一覧に現れなかったサブスクリプションを参照している寄付。一覧の漏れで寄付を消さないよう、
+	// プロジェクトのアカウントで直接取得して存在しない・終了済みと確かめられたものだけを修正する
+	for subID, d := range bySubscription {
+		if seen[subID] {
+			continue
+		}
+		accountID := projectAccounts[d.ProjectID]
+		is := ReconcileIssue{Kind: ReconcileOrphanedSubscription, StripeID: subID, DonationID: d.ID, ProjectID: d.ProjectID}
+		confirmed := false
+		if !known[accountID] {
+			is.Detail = "connected account " + accountID + " not found in Stripe; subscription not confirmed"
+		} else {
+			sub, err := s.client.GetSubscription(ctx, accountID, subID)
+			se, _ := pkgstripe.AsError(err)
+			switch {
+			case se != nil && se.IsNotFound():
+				is.Detail, confirmed = "subscription not found in Stripe", true
+			case err != nil:
+				return fmt.Errorf("get subscription %s on %s: %w", subID, accountLabel(accountID), err)
+			case !subscriptionEnded(sub.Status):
+				continue // 一覧の取得後に作成された・一覧から漏れた
+			default:
+				is.Detail, confirmed = "subscription is "+sub.Status, true
+			}
+		}
+		if fix && confirmed {
+			if err := s.donationRepo.DeleteByStripeSubscriptionID(ctx, subID); err != nil {
+				return fmt.Errorf("delete donation %s: %w", d.ID, err)
+			}
+			is.Fixed = true
+		}
+		report.Issues = append(report.Issues, is)
+	}
+	return nil
+}
+
+func (s *ReconcileService) reconcilePaymentIntents(ctx context.Context, accounts []string, byPayment map[string]*model.Donation, createdSince time.Time, fix bool, report *ReconcileReport) error {
+	var intents []pkgstripe.PaymentIntent
+	for _, accountID := range accounts {
+		list, err := s.client.ListPaymentIntents(ctx, accountID, createdSince)
+		if err != nil {
+			return fmt.Errorf("list payment intents %s: %w", accountLabel(accountID), err)
+		}
+		intents = append(intents, list...)
+	}
+
+	var err error
+	for _, pi := range intents {
+		// 定期寄付の請求分は metadata を持たないため、単発寄付の PaymentIntent だけが対象
+		projectID := pi.Metadata["project_id"]
+		if pi.Status != "succeeded" || projectID == "" {
+			continue
+		}
+
+		d, ok := byPayment[pi.ID]
+		if !ok {
+			is := ReconcileIssue{Kind: ReconcileMissingDonation, StripeID: pi.ID, ProjectID: projectID,
+				Detail: "payment intent succeeded but has no donation row"}
+			if fix {
+				is.DonationID, err = s.createDonation(ctx, pi.Metadata, pi.Amount, pi.Currency, func(d *model.Donation) {
+					d.IsRecurring = pi.Metadata["is_recurring"] == "true"
+					d.StripePaymentID = pi.ID
+				})
+				if err != nil {
+					return err
+				}
+				if s.paymentLedger != nil {
+					err := s.paymentLedger.Create(ctx, &model.DonationPayment{
+						DonationID:      is.DonationID,
+						ProjectID:       projectID,
+						Amount:          pkgstripe.IntendedAmount(pi.Metadata, pi.Amount),
+						ChargedAmount:   pi.Amount,
+						Currency:        currencyOrDefault(pi.Currency),
+						StripePaymentID: pi.ID,
+						PaidAt:          pi.Created,
+					})
+					if err != nil && !errors.Is(err, repository.ErrDuplicate) {
+						return fmt.Errorf("record payment %s: %w", pi.ID, err)
+					}
+				}
+				is.Fixed = true
+			}
+			report.Issues = append(report.Issues, is)
+			continue
+		}
+
+		if d.Charge() != pi.Amount {
+			is := ReconcileIssue{Kind: ReconcileAmountMismatch, StripeID: pi.ID, DonationID: d.ID, ProjectID: d.ProjectID,
+				Detail: fmt.Sprintf("db=%d stripe=%d", d.Charge(), pi.Amount)}
+			if fix {
+				if err := s.donationRepo.Patch(ctx, d.ID, chargedAmountPatch(d, pi.Amount)); err != nil {
+					return fmt.Errorf("patch donation %s: %w", d.ID, err)
+				}
+				is.Fixed = true
+			}
+			report.Issues = append(report.Issues, is)
+		}
+	}
+	return nil
+}
+
+func reconcileAccounts(accounts []pkgstripe.ConnectedAccount, projectAccounts map[string]string, report *ReconcileReport) {
+	known := map[string]bool{}
+	for _, a := range accounts {
+		known[a.ID] = true
+	}
+	referenced := map[string]bool{}
+	for projectID, accountID := range projectAccounts {
+		referenced[accountID] = true
+		if !known[accountID] {
+			report.Issues = append(report.Issues, ReconcileIssue{Kind: ReconcileMissingAccount, StripeID: accountID, ProjectID: projectID,
+				Detail: "connected account not found in Stripe"})
+		}
+	}
+	for _, a := range accounts {
+		if !referenced[a.ID] {
+			report.Issues = append(report.Issues, ReconcileIssue{Kind: ReconcileOrphanedAccount, StripeID: a.ID,
+				Detail: "no project references this account"})
+		}
+	}
+}
+
+// accountLabel はログ・エラー用のアカウント名（空ならプラットフォーム）
+func accountLabel(accountID string) string {
+	if accountID == "" {
+		return "platform"
+	}
+	return accountID
+}
+
+// chargedAmountPatch は Stripe の請求額に合わせる更新。寄付者が手数料を負担している寄付は
+// 上乗せ分を保ったまま寄付額を合わせる。
+func chargedAmountPatch(d *model.Donation, charged int) model.DonationPatch {
+	amount := max(charged-d.CoveredFee(), 0)
+	return model.DonationPatch{Amount: &amount, ChargedAmount: &charged}
+}
+
+// createDonation は Stripe の metadata から寄付行を作成する（Webhook の取りこぼし補填用）。
+// activity は作成しない（実際の寄付時刻とずれるため）。
+func (s *ReconcileService) createDonation(ctx context.Context, metadata map[string]string, amount int, currency string, apply func(*model.Donation)) (string, error) {
+	donorType := metadata["donor_type"]
+	if donorType == "" {
+		donorType = "token"
+	}
+	d := &model.Donation{
+		ProjectID:     metadata["project_id"],
+		DonorType:     donorType,
+		DonorID:       metadata["donor_id"],
+		Amount:        pkgstripe.IntendedAmount(metadata, amount),
+		ChargedAmount: amount,
+		Currency:      currencyOrDefault(currency),
+		Message:       metadata["message"],
+		TierID:        metadata["tier_id"],
+	}
+	apply(d)
+	if err := s.donationRepo.Create(ctx, d); err != nil {
+		return "", fmt.Errorf("create donation for %s%s: %w", d.StripeSubscriptionID, d.StripePaymentID, err)
+	}
+	return d.ID, nil
+}
+
+func currencyOrDefault(currency string) string {
+	if currency == "" {
+		return "jpy"
+	}
+	return currency
+}
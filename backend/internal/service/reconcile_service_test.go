@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/givers/backend/internal/model"
+	pkgstripe "github.com/givers/backend/pkg/stripe"
+)
+
+// ---------------------------------------------------------------------------
+// Mocks
+// ---------------------------------------------------------------------------
+
+// mockReconcileStripeClient はアカウントごとのオブジェクトを返す（キーの "" はプラットフォーム）
+type mockReconcileStripeClient struct {
+	subs     map[string][]pkgstripe.Subscription
+	unlisted map[string][]pkgstripe.Subscription // GetSubscription でだけ見つかる（一覧から漏れた）
+	intents  map[string][]pkgstripe.PaymentIntent
+	accounts []pkgstripe.ConnectedAccount
+	gets     []string // GetSubscription の "accountID/subscriptionID"
+}
+
+func (m *mockReconcileStripeClient) ListSubscriptions(_ context.Context, accountID string) ([]pkgstripe.Subscription, error) {
+	return m.subs[accountID], nil
+}
+func (m *mockReconcileStripeClient) GetSubscription(_ context.Context, accountID, subscriptionID string) (pkgstripe.Subscription, error) {
+	m.gets = append(m.gets, accountID+"/"+subscriptionID)
+	for _, sub := range append(m.subs[accountID], m.unlisted[accountID]...) {
+		if sub.ID == subscriptionID {
+			return sub, nil
+		}
+	}
+	return pkgstripe.Subscription{}, &pkgstripe.Error{HTTPStatus: 404, Code: "resource_missing"}
+}
+func (m *mockReconcileStripeClient) ListPaymentIntents(_ context.Context, accountID string, _ time.Time) ([]pkgstripe.PaymentIntent, error) {
+	return m.intents[accountID], nil
+}
+func (m *mockReconcileStripeClient) ListConnectedAccounts(_ context.Context) ([]pkgstripe.ConnectedAccount, error) {
+	return m.accounts, nil
+}
+
+type mockReconcileDonationRepo struct {
+	donations []*model.Donation
+	created   []*model.Donation
+	patches   map[string]model.DonationPatch
+	deleted   []string
+}
+
+func (m *mockReconcileDonationRepo) ListStripeLinked(_ context.Context) ([]*model.Donation, error) {
+	return m.donations, nil
+}
+func (m *mockReconcileDonationRepo) Create(_ context.Context, d *model.Donation) error {
+	d.ID = "new-" + d.StripeSubscriptionID + d.StripePaymentID
+	m.created = append(m.created, d)
+	return nil
+}
+func (m *mockReconcileDonationRepo) Patch(_ context.Context, id string, patch model.DonationPatch) error {
+	if m.patches == nil {
+		m.patches = map[string]model.DonationPatch{}
+	}
+	prev := m.patches[id]
+	if patch.Amount != nil {
+		prev.Amount = patch.Amount
+	}
+	if patch.ChargedAmount != nil {
+		prev.ChargedAmount = patch.ChargedAmount
+	}
+	if patch.Paused != nil {
+		prev.Paused = patch.Paused
+	}
+	m.patches[id] = prev
+	return nil
+}
+func (m *mockReconcileDonationRepo) DeleteByStripeSubscriptionID(_ context.Context, subscriptionID string) error {
+	m.deleted = append(m.deleted, subscriptionID)
+	return nil
+}
+
+type mockReconcileProjectRepo struct {
+	accounts map[string]string
+}
+
+func (m *mockReconcileProjectRepo) ListStripeAccountIDs(_ context.Context) (map[string]string, error) {
+	return m.accounts, nil
+}
+
+type mockReconcileLedger struct {
+	payments []*model.DonationPayment
+}
+
+func (m *mockReconcileLedger) Create(_ context.Context, p *model.DonationPayment) error {
+	m.payments = append(m.payments, p)
+	return nil
+}
+
+func issueKinds(r *ReconcileReport) map[string]int {
+	kinds := map[string]int{}
+	for _, is := range r.Issues {
+		kinds[is.Kind]++
+	}
+	return kinds
+}
+
+func reconcileFixture() (*mockReconcileStripeClient, *mockReconcileDonationRepo, *mockReconcileProjectRepo) {
+	// p1 は連結アカウント acct_ok のプロジェクト、p2 はホストのプロジェクト（プラットフォームで決済）
+	client := &mockReconcileStripeClient{
+		subs: map[string][]pkgstripe.Subscription{
+			"acct_ok": {
+				{ID: "sub_ok", Status: "active", Amount: 1000, Metadata: map[string]string{"project_id": "p1"}},
+				{ID: "sub_missing", Status: "active", Amount: 500, Currency: "jpy", Metadata: map[string]string{"project_id": "p1", "donor_type": "user", "donor_id": "u1"}},
+				{ID: "sub_amount", Status: "active", Amount: 3000, Metadata: map[string]string{"project_id": "p1"}},
+				{ID: "sub_paused", Status: "active", Amount: 1000, Paused: true, Metadata: map[string]string{"project_id": "p1"}},
+				{ID: "sub_canceled", Status: "canceled", Amount: 1000, Metadata: map[string]string{"project_id": "p1"}},
+			},
+			"": {
+				{ID: "sub_other", Status: "active", Amount: 1000},
+			},
+		},
+		intents: map[string][]pkgstripe.PaymentIntent{
+			"acct_ok": {
+				{ID: "pi_ok", Amount: 2000, Status: "succeeded", Metadata: map[string]string{"project_id": "p1"}},
+			},
+			"": {
+				{ID: "pi_missing", Amount: 700, Status: "succeeded", Metadata: map[string]string{"project_id": "p2", "message": "hi"}, Created: time.Unix(1700000000, 0)},
+				{ID: "pi_failed", Amount: 700, Status: "requires_payment_method", Metadata: map[string]string{"project_id": "p2"}},
+				{ID: "pi_invoice", Amount: 1000, Status: "succeeded"},
+			},
+		},
+		accounts: []pkgstripe.ConnectedAccount{{ID: "acct_ok"}, {ID: "acct_unused"}},
+	}
+	repo := &mockReconcileDonationRepo{
+		donations: []*model.Donation{
+			{ID: "d-ok", ProjectID: "p1", Amount: 1000, IsRecurring: true, StripeSubscriptionID: "sub_ok"},
+			{ID: "d-amount", ProjectID: "p1", Amount: 1000, IsRecurring: true, StripeSubscriptionID: "sub_amount"},
+			{ID: "d-paused", ProjectID: "p1", Amount: 1000, IsRecurring: true, StripeSubscriptionID: "sub_paused"},
+			{ID: "d-canceled", ProjectID: "p1", Amount: 1000, IsRecurring: true, StripeSubscriptionID: "sub_canceled"},
+			{ID: "d-gone", ProjectID: "p1", Amount: 1000, IsRecurring: true, StripeSubscriptionID: "sub_gone"},
+			{ID: "d-unconfirmed", ProjectID: "p3", Amount: 1000, IsRecurring: true, StripeSubscriptionID: "sub_unconfirmed"},
+			{ID: "d-pi", ProjectID: "p1", Amount: 2000, StripePaymentID: "pi_ok"},
+		},
+	}
+	projects := &mockReconcileProjectRepo{accounts: map[string]string{"p1": "acct_ok", "p3": "acct_deleted"}}
+	return client, repo, projects
+}
+
+// ---------------------------------------------------------------------------
+// ReconcileService.Run tests
+// ---------------------------------------------------------------------------
+
+func TestReconcileService_Run_ReportsDrift(t *testing.T) {
+	client, repo, projects := reconcileFixture()
+	svc := NewReconcileService(client, repo, projects, nil)
+
+	report, err := svc.Run(context.Background(), time.Time{}, false)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	want := map[string]int{
+		ReconcileMissingDonation:      2, // sub_missing, pi_missing
+		ReconcileAmountMismatch:       1, // sub_amount
+		ReconcilePausedDrift:          1, // sub_paused
+		ReconcileOrphanedSubscription: 3, // sub_canceled, sub_gone, sub_unconfirmed
+		ReconcileMissingAccount:       1, // acct_deleted
+		ReconcileOrphanedAccount:      1, // acct_unused
+	}
+	got := issueKinds(report)
+	for kind, n := range want {
+		if got[kind] != n {
+			t.Errorf("%s: got %d, want %d (issues=%+v)", kind, got[kind], n, report.Issues)
+		}
+	}
+	if report.Unresolved() != len(report.Issues) {
+		t.Errorf("Unresolved() = %d, want %d", report.Unresolved(), len(report.Issues))
+	}
+	if len(repo.created) != 0 || len(repo.patches) != 0 || len(repo.deleted) != 0 {
+		t.Error("report-only run must not write")
+	}
+}
+
+func TestReconcileService_Run_FixCorrectsDonations(t *testing.T) {
+	client, repo, projects := reconcileFixture()
+	ledger := &mockReconcileLedger{}
+	svc := NewReconcileService(client, repo, projects, ledger)
+
+	report, err := svc.Run(context.Background(), time.Time{}, true)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	// 連結アカウントの不一致と、存在しない連結アカウント上のサブスクリプションは報告のみ
+	if report.Unresolved() != 3 {
+		t.Errorf("Unresolved() = %d, want 3 (account issues and sub_unconfirmed)", report.Unresolved())
+	}
+
+	if len(repo.created) != 2 {
+		t.Fatalf("created %d donations, want 2", len(repo.created))
+	}
+	sub := repo.created[0]
+	if sub.StripeSubscriptionID != "sub_missing" || !sub.IsRecurring || sub.DonorType != "user" || sub.DonorID != "u1" || sub.Amount != 500 {
+		t.Errorf("subscription donation = %+v", sub)
+	}
+	pi := repo.created[1]
+	if pi.StripePaymentID != "pi_missing" || pi.ProjectID != "p2" || pi.DonorType != "token" || pi.Currency != "jpy" || pi.Message != "hi" {
+		t.Errorf("payment donation = %+v", pi)
+	}
+	if len(ledger.payments) != 1 || ledger.payments[0].DonationID != pi.ID || !ledger.payments[0].PaidAt.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("ledger payments = %+v", ledger.payments)
+	}
+
+	if p := repo.patches["d-amount"]; p.Amount == nil || *p.Amount != 3000 {
+		t.Errorf("d-amount patch = %+v", p)
+	}
+	if p := repo.patches["d-paused"]; p.Paused == nil || !*p.Paused {
+		t.Errorf("d-paused patch = %+v", p)
+	}
+	if _, ok := repo.patches["d-ok"]; ok {
+		t.Error("d-ok should not be patched")
+	}
+
+	deleted := map[string]bool{}
+	for _, id := range repo.deleted {
+		deleted[id] = true
+	}
+	if len(repo.deleted) != 2 || !deleted["sub_canceled"] || !deleted["sub_gone"] {
+		t.Errorf("deleted = %v, want sub_canceled and sub_gone", repo.deleted)
+	}
+	// 一覧になかった sub_gone はプロジェクトのアカウントで存在しないことを確かめてから削除する
+	if len(client.gets) != 1 || client.gets[0] != "acct_ok/sub_gone" {
+		t.Errorf("gets = %v, want [acct_ok/sub_gone]", client.gets)
+	}
+}
+
+func TestReconcileService_Run_UnlistedActiveSubscriptionIsKept(t *testing.T) {
+	client := &mockReconcileStripeClient{
+		unlisted: map[string][]pkgstripe.Subscription{
+			"acct_1": {{ID: "sub_late", Status: "active", Amount: 1000, Metadata: map[string]string{"project_id": "p1"}}},
+		},
+		accounts: []pkgstripe.ConnectedAccount{{ID: "acct_1"}},
+	}
+	repo := &mockReconcileDonationRepo{
+		donations: []*model.Donation{{ID: "d1", ProjectID: "p1", Amount: 1000, StripeSubscriptionID: "sub_late"}},
+	}
+	projects := &mockReconcileProjectRepo{accounts: map[string]string{"p1": "acct_1"}}
+
+	report, err := NewReconcileService(client, repo, projects, nil).Run(context.Background(), time.Time{}, true)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(report.Issues) != 0 || len(repo.deleted) != 0 {
+		t.Errorf("issues = %+v, deleted = %v, want none", report.Issues, repo.deleted)
+	}
+}
+
+func TestReconcileService_Run_NoDrift(t *testing.T) {
+	client := &mockReconcileStripeClient{
+		subs: map[string][]pkgstripe.Subscription{
+			"acct_1": {{ID: "sub_1", Status: "active", Amount: 1000, Metadata: map[string]string{"project_id": "p1"}}},
+		},
+		accounts: []pkgstripe.ConnectedAccount{{ID: "acct_1"}},
+	}
+	repo := &mockReconcileDonationRepo{
+		donations: []*model.Donation{{ID: "d1", ProjectID: "p1", Amount: 1000, StripeSubscriptionID: "sub_1"}},
+	}
+	projects := &mockReconcileProjectRepo{accounts: map[string]string{"p1": "acct_1"}}
+
+	report, err := NewReconcileService(client, repo, projects, nil).Run(context.Background(), time.Time{}, true)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(report.Issues) != 0 {
+		t.Errorf("issues = %+v, want none", report.Issues)
+	}
+}
+
+func TestReconcileService_Run_CoveredFee(t *testing.T) {
+	client := &mockReconcileStripeClient{
+		subs: map[string][]pkgstripe.Subscription{
+			"": {
+				{ID: "sub_fee", Status: "active", Amount: 1038, Metadata: map[string]string{"project_id": "p1"}},
+				{ID: "sub_fee_changed", Status: "active", Amount: 3113, Metadata: map[string]string{"project_id": "p1"}},
+			},
+		},
+	}
+	repo := &mockReconcileDonationRepo{
+		donations: []*model.Donation{
+			{ID: "d-fee", ProjectID: "p1", Amount: 1000, ChargedAmount: 1038, StripeSubscriptionID: "sub_fee"},
+			{ID: "d-fee-changed", ProjectID: "p1", Amount: 1000, ChargedAmount: 1038, StripeSubscriptionID: "sub_fee_changed"},
+		},
+	}
+	projects := &mockReconcileProjectRepo{accounts: map[string]string{}}
+
+	report, err := NewReconcileService(client, repo, projects, nil).Run(context.Background(), time.Time{}, true)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	// Stripe の請求額は手数料込みなので、上乗せ分だけの差は不一致ではない
+	if got := issueKinds(report); len(report.Issues) != 1 || got[ReconcileAmountMismatch] != 1 {
+		t.Errorf("issues = %+v, want one amount mismatch (sub_fee_changed)", report.Issues)
+	}
+	p := repo.patches["d-fee-changed"]
+	if p.Amount == nil || *p.Amount != 3075 || p.ChargedAmount == nil || *p.ChargedAmount != 3113 {
+		t.Errorf("d-fee-changed patch = %+v", p)
+	}
+}
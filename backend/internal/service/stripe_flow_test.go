@@ -0,0 +1,666 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+	pkgstripe "github.com/givers/backend/pkg/stripe"
+	"github.com/givers/backend/pkg/stripe/stripetest"
+)
+
+// ---------------------------------------------------------------------------
+// End-to-end: Checkout → Webhook → 寄付記録（stripetest のフェイク Stripe を使用）
+// ---------------------------------------------------------------------------
+
+// memStripeDonationRepo は StripeDonationRepo のインメモリ実装
+type memStripeDonationRepo struct {
+	donations []*model.Donation
+}
+
+func (m *memStripeDonationRepo) Create(_ context.Context, d *model.Donation) error {
+	for _, existing := range m.donations {
+		if (d.StripePaymentID != "" && existing.StripePaymentID == d.StripePaymentID) ||
+			(d.StripeSubscriptionID != "" && existing.StripeSubscriptionID == d.StripeSubscriptionID) {
+			return repository.ErrDuplicate
+		}
+	}
+	d.ID = fmt.Sprintf("don-%d", len(m.donations)+1)
+	m.donations = append(m.donations, d)
+	return nil
+}
+func (m *memStripeDonationRepo) DeleteByStripeSubscriptionID(_ context.Context, subscriptionID string) error {
+	for i, d := range m.donations {
+		if d.StripeSubscriptionID == subscriptionID {
+			m.donations = append(m.donations[:i], m.donations[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+func (m *memStripeDonationRepo) GetByStripeSubscriptionID(_ context.Context, subscriptionID string) (*model.Donation, error) {
+	for _, d := range m.donations {
+		if d.StripeSubscriptionID == subscriptionID {
+			return d, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+func (m *memStripeDonationRepo) Patch(_ context.Context, id string, patch model.DonationPatch) error {
+	for _, d := range m.donations {
+		if d.ID == id {
+			if patch.Amount != nil {
+				d.Amount = *patch.Amount
+			}
+			if patch.ChargedAmount != nil {
+				d.ChargedAmount = *patch.ChargedAmount
+			}
+			if patch.Paused != nil {
+				d.Paused = *patch.Paused
+			}
+			if patch.NextBillingMessage != nil {
+				d.NextBillingMessage = *patch.NextBillingMessage
+			}
+			if patch.PaymentStatus != nil {
+				d.PaymentStatus = *patch.PaymentStatus
+			}
+			if patch.CancelAt != nil {
+				d.CancelAt = nil
+				if !patch.CancelAt.IsZero() {
+					d.CancelAt = patch.CancelAt
+				}
+			}
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+func (m *memStripeDonationRepo) GetByStripePaymentID(_ context.Context, paymentID string) (*model.Donation, error) {
+	for _, d := range m.donations {
+		if d.StripePaymentID == paymentID {
+			return d, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+func (m *memStripeDonationRepo) SyncAdjustments(_ context.Context, _ string) error {
+	return nil
+}
+
+// newStripeFlow はフェイク Stripe と、その Webhook を ProcessWebhook に流すエンドポイントをつなぐ
+func newStripeFlow(t *testing.T, projectRepo StripeProjectRepo, opts ...StripeServiceOption) (*stripetest.Server, StripeService, *memStripeDonationRepo, *mockStripePaymentLedger) {
+	t.Helper()
+	fake := stripetest.NewServer("sk_test_flow", "whsec_flow")
+	t.Cleanup(fake.Close)
+
+	donations := &memStripeDonationRepo{}
+	ledger := &mockStripePaymentLedger{}
+	svc := NewStripeService(fake.Client(), projectRepo, donations, "https://example.com",
+		append([]StripeServiceOption{WithPaymentLedger(ledger)}, opts...)...)
+
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		if err := svc.ProcessWebhook(r.Context(), payload, r.Header.Get("Stripe-Signature")); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(hook.Close)
+	fake.WebhookURL = hook.URL
+
+	return fake, svc, donations, ledger
+}
+
+func TestStripeFlow_OneTimeDonation(t *testing.T) {
+	fake, svc, donations, ledger := newStripeFlow(t, &mockStripeProjectRepo{})
+	ctx := context.Background()
+
+	checkoutURL, err := svc.CreateCheckout(ctx, CheckoutRequest{
+		ProjectID: "p1", Amount: 2000, Message: "ありがとう", DonorType: "user", DonorID: "u1",
+	})
+	if err != nil {
+		t.Fatalf("CreateCheckout: %v", err)
+	}
+	if err := fake.CompleteCheckout(ctx, stripetest.SessionIDFromURL(checkoutURL)); err != nil {
+		t.Fatalf("CompleteCheckout: %v", err)
+	}
+
+	if len(donations.donations) != 1 {
+		t.Fatalf("donations = %d, want 1", len(donations.donations))
+	}
+	d := donations.donations[0]
+	if d.ProjectID != "p1" || d.Amount != 2000 || d.DonorID != "u1" || d.Message != "ありがとう" || d.IsRecurring {
+		t.Errorf("donation = %+v", d)
+	}
+	if len(ledger.payments) != 1 || ledger.payments[0].DonationID != d.ID || ledger.payments[0].StripePaymentID != d.StripePaymentID {
+		t.Errorf("ledger = %+v", ledger.payments)
+	}
+}
+
+func TestStripeFlow_RecurringDonationOnConnectedAccount(t *testing.T) {
+	var accountID string
+	projectRepo := &mockStripeProjectRepo{
+		getByIDFunc: func(_ context.Context, _ string) (string, error) { return accountID, nil },
+	}
+	fake, svc, donations, ledger := newStripeFlow(t, projectRepo)
+	ctx := context.Background()
+
+	var err error
+	accountID, err = fake.Client().CreateConnectedAccount(ctx, pkgstripe.CreateAccountParams{Email: "owner@example.com"})
+	if err != nil {
+		t.Fatalf("CreateConnectedAccount: %v", err)
+	}
+
+	checkoutURL, err := svc.CreateCheckout(ctx, CheckoutRequest{ProjectID: "p1", Amount: 1000, IsRecurring: true})
+	if err != nil {
+		t.Fatalf("CreateCheckout: %v", err)
+	}
+	sessionID := stripetest.SessionIDFromURL(checkoutURL)
+	if cs, _ := fake.CheckoutSession(sessionID); cs.StripeAccount != accountID {
+		t.Errorf("Stripe-Account = %q, want %q", cs.StripeAccount, accountID)
+	}
+	if err := fake.CompleteCheckout(ctx, sessionID); err != nil {
+		t.Fatalf("CompleteCheckout: %v", err)
+	}
+
+	if len(donations.donations) != 1 {
+		t.Fatalf("donations = %d, want 1", len(donations.donations))
+	}
+	d := donations.donations[0]
+	if !d.IsRecurring || d.Amount != 1000 || d.StripeSubscriptionID == "" {
+		t.Errorf("donation = %+v", d)
+	}
+	// 初回請求（invoice.payment_succeeded）が台帳に記録される
+	if len(ledger.payments) != 1 || ledger.payments[0].DonationID != d.ID || ledger.payments[0].Amount != 1000 {
+		t.Errorf("ledger = %+v", ledger.payments)
+	}
+}
+
+func TestStripeFlow_CheckoutSessionCompletedAndExpired(t *testing.T) {
+	store := newMockCheckoutSessionStore()
+	fake, svc, donations, _ := newStripeFlow(t, &mockStripeProjectRepo{}, WithCheckoutSessionStore(store))
+	ctx := context.Background()
+
+	paidURL, err := svc.CreateCheckout(ctx, CheckoutRequest{ProjectID: "p1", Amount: 2000})
+	if err != nil {
+		t.Fatalf("CreateCheckout: %v", err)
+	}
+	abandonedURL, err := svc.CreateCheckout(ctx, CheckoutRequest{ProjectID: "p1", Amount: 5000})
+	if err != nil {
+		t.Fatalf("CreateCheckout: %v", err)
+	}
+	paidID, abandonedID := stripetest.SessionIDFromURL(paidURL), stripetest.SessionIDFromURL(abandonedURL)
+	if cs, err := svc.GetCheckoutSession(ctx, paidID); err != nil || cs.Status != model.CheckoutSessionOpen || cs.Amount != 2000 {
+		t.Fatalf("pending session = %+v (%v)", cs, err)
+	}
+
+	if err := fake.CompleteCheckout(ctx, paidID); err != nil {
+		t.Fatalf("CompleteCheckout: %v", err)
+	}
+	if err := fake.ExpireCheckout(ctx, abandonedID); err != nil {
+		t.Fatalf("ExpireCheckout: %v", err)
+	}
+
+	if cs := store.sessions[paidID]; cs.Status != model.CheckoutSessionComplete || cs.StripePaymentID != donations.donations[0].StripePaymentID {
+		t.Errorf("completed session = %+v", cs)
+	}
+	if cs := store.sessions[abandonedID]; cs.Status != model.CheckoutSessionExpired {
+		t.Errorf("abandoned session = %+v", cs)
+	}
+	if len(donations.donations) != 1 {
+		t.Errorf("donations = %d, want 1", len(donations.donations))
+	}
+}
+
+func TestStripeFlow_AccountRestrictedFreezesProject(t *testing.T) {
+	var synced []model.StripeAccountStatus
+	projectRepo := &mockStripeProjectRepo{
+		syncAccountStatusFunc: func(_ context.Context, _ string, st model.StripeAccountStatus) (map[string]string, error) {
+			synced = append(synced, st)
+			return nil, nil
+		},
+	}
+	fake, _, _, _ := newStripeFlow(t, projectRepo)
+	ctx := context.Background()
+
+	accountID, err := fake.Client().CreateConnectedAccount(ctx, pkgstripe.CreateAccountParams{Email: "owner@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fake.CompleteOnboarding(accountID); err != nil {
+		t.Fatal(err)
+	}
+	if err := fake.RestrictAccount(ctx, accountID, "requirements.past_due", "individual.verification.document"); err != nil {
+		t.Fatalf("RestrictAccount: %v", err)
+	}
+	if err := fake.RestoreAccount(ctx, accountID); err != nil {
+		t.Fatalf("RestoreAccount: %v", err)
+	}
+
+	if len(synced) != 2 {
+		t.Fatalf("synced %d times, want 2", len(synced))
+	}
+	if synced[0].Capable() || synced[0].DisabledReason != "requirements.past_due" || len(synced[0].Requirements) != 1 {
+		t.Errorf("restricted status = %+v", synced[0])
+	}
+	if !synced[1].Capable() || synced[1].NeedsAction() {
+		t.Errorf("restored status = %+v", synced[1])
+	}
+}
+
+func TestStripeFlow_FinanceSync(t *testing.T) {
+	var accountID string
+	projectRepo := &mockStripeProjectRepo{
+		getByIDFunc: func(_ context.Context, _ string) (string, error) { return accountID, nil },
+	}
+	fake, svc, _, ledger := newStripeFlow(t, projectRepo)
+	ctx := context.Background()
+
+	var err error
+	accountID, err = fake.Client().CreateConnectedAccount(ctx, pkgstripe.CreateAccountParams{Email: "owner@example.com"})
+	if err != nil {
+		t.Fatalf("CreateConnectedAccount: %v", err)
+	}
+	checkoutURL, err := svc.CreateCheckout(ctx, CheckoutRequest{ProjectID: "p1", Amount: 2000})
+	if err != nil {
+		t.Fatalf("CreateCheckout: %v", err)
+	}
+	if err := fake.CompleteCheckout(ctx, stripetest.SessionIDFromURL(checkoutURL)); err != nil {
+		t.Fatalf("CompleteCheckout: %v", err)
+	}
+	if _, err := fake.CreatePayout(accountID, 1928, "jpy", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &mockFinanceRepo{}
+	finance := NewFinanceService(fake.Client(), &mockFinanceProjectRepo{accounts: map[string]string{"p1": accountID}}, ledger, repo)
+	report, err := finance.Sync(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if report.Payments != 1 || report.Payouts != 1 || len(report.Errors) != 0 {
+		t.Errorf("report = %+v", report)
+	}
+	p := ledger.payments[0]
+	if p.StripeFee == nil || *p.StripeFee != 72 || p.StripeNet == nil || *p.StripeNet != 1928 {
+		t.Errorf("payment fee/net = %v/%v, want 72/1928", p.StripeFee, p.StripeNet)
+	}
+	if len(repo.payouts) != 1 || repo.payouts[0].StripeAccountID != accountID || repo.payouts[0].Amount != 1928 {
+		t.Errorf("payouts = %+v", repo.payouts)
+	}
+}
+
+func TestStripeFlow_KonbiniDonationPendingUntilPaid(t *testing.T) {
+	projectRepo := &mockStripeProjectRepo{paymentMethodTypes: []string{model.CheckoutMethodCard, model.CheckoutMethodKonbini}}
+	fake, svc, donations, ledger := newStripeFlow(t, projectRepo)
+	ctx := context.Background()
+
+	checkoutURL, err := svc.CreateCheckout(ctx, CheckoutRequest{ProjectID: "p1", Amount: 3000, DonorType: "user", DonorID: "u1"})
+	if err != nil {
+		t.Fatalf("CreateCheckout: %v", err)
+	}
+	sessionID := stripetest.SessionIDFromURL(checkoutURL)
+	if cs, _ := fake.CheckoutSession(sessionID); len(cs.PaymentMethodTypes) != 2 || cs.PaymentMethodTypes[1] != model.CheckoutMethodKonbini {
+		t.Errorf("payment_method_types = %v", cs.PaymentMethodTypes)
+	}
+	if err := fake.CompleteCheckoutAsync(ctx, sessionID, model.CheckoutMethodKonbini); err != nil {
+		t.Fatalf("CompleteCheckoutAsync: %v", err)
+	}
+
+	// 入金待ちの寄付は記録されるが、台帳（集計）には入らない
+	if len(donations.donations) != 1 {
+		t.Fatalf("donations = %d, want 1", len(donations.donations))
+	}
+	d := donations.donations[0]
+	if d.PaymentStatus != model.PaymentStatusPending || d.Amount != 3000 || d.DonorID != "u1" || d.StripePaymentID == "" {
+		t.Errorf("pending donation = %+v", d)
+	}
+	if len(ledger.payments) != 0 {
+		t.Errorf("ledger = %+v, want no payments while pending", ledger.payments)
+	}
+
+	if err := fake.SettleAsyncPayment(ctx, sessionID, true); err != nil {
+		t.Fatalf("SettleAsyncPayment: %v", err)
+	}
+	if len(donations.donations) != 1 || d.PaymentStatus != model.PaymentStatusActive {
+		t.Errorf("donations = %d, status = %q, want 1 active", len(donations.donations), d.PaymentStatus)
+	}
+	if len(ledger.payments) != 1 || ledger.payments[0].DonationID != d.ID || ledger.payments[0].Amount != 3000 {
+		t.Errorf("ledger = %+v", ledger.payments)
+	}
+}
+
+func TestStripeFlow_BankTransferNeverPaid(t *testing.T) {
+	projectRepo := &mockStripeProjectRepo{paymentMethodTypes: []string{model.CheckoutMethodCard, model.CheckoutMethodBankTransfer}}
+	fake, svc, donations, ledger := newStripeFlow(t, projectRepo)
+	ctx := context.Background()
+
+	checkoutURL, err := svc.CreateCheckout(ctx, CheckoutRequest{ProjectID: "p1", Amount: 10000})
+	if err != nil {
+		t.Fatalf("CreateCheckout: %v", err)
+	}
+	sessionID := stripetest.SessionIDFromURL(checkoutURL)
+	if err := fake.CompleteCheckoutAsync(ctx, sessionID, model.CheckoutMethodBankTransfer); err != nil {
+		t.Fatalf("CompleteCheckoutAsync: %v", err)
+	}
+	if err := fake.SettleAsyncPayment(ctx, sessionID, false); err != nil {
+		t.Fatalf("SettleAsyncPayment: %v", err)
+	}
+
+	if len(donations.donations) != 1 || donations.donations[0].PaymentStatus != model.PaymentStatusFailed {
+		t.Fatalf("donations = %+v, want 1 failed", donations.donations)
+	}
+	if len(ledger.payments) != 0 {
+		t.Errorf("ledger = %+v, want no payments", ledger.payments)
+	}
+}
+
+func TestStripeFlow_RecurringCheckoutIsCardOnly(t *testing.T) {
+	projectRepo := &mockStripeProjectRepo{paymentMethodTypes: []string{model.CheckoutMethodCard, model.CheckoutMethodKonbini}}
+	fake, svc, _, _ := newStripeFlow(t, projectRepo)
+	ctx := context.Background()
+
+	checkoutURL, err := svc.CreateCheckout(ctx, CheckoutRequest{ProjectID: "p1", Amount: 1000, IsRecurring: true})
+	if err != nil {
+		t.Fatalf("CreateCheckout: %v", err)
+	}
+	if cs, _ := fake.CheckoutSession(stripetest.SessionIDFromURL(checkoutURL)); len(cs.PaymentMethodTypes) != 0 {
+		t.Errorf("payment_method_types = %v, want card only (unset)", cs.PaymentMethodTypes)
+	}
+}
+
+// memStripeCustomerStore は StripeCustomerStore のインメモリ実装
+// （プラットフォームの Customer は User.StripeCustomerID、連結アカウントの Customer は accounts に持つ）
+type memStripeCustomerStore struct {
+	users    map[string]*model.User
+	accounts map[string]string // userID + "/" + accountID -> customerID
+}
+
+func (m *memStripeCustomerStore) FindByID(_ context.Context, id string) (*model.User, error) {
+	u, ok := m.users[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	copied := *u
+	return &copied, nil
+}
+func (m *memStripeCustomerStore) FindStripeCustomerID(_ context.Context, userID, accountID string) (string, error) {
+	u, ok := m.users[userID]
+	if !ok {
+		return "", repository.ErrNotFound
+	}
+	if accountID == "" {
+		return u.StripeCustomerID, nil
+	}
+	return m.accounts[userID+"/"+accountID], nil
+}
+func (m *memStripeCustomerStore) SaveStripeCustomerID(_ context.Context, userID, accountID, customerID string) (string, error) {
+	u, ok := m.users[userID]
+	if !ok {
+		return "", repository.ErrNotFound
+	}
+	if accountID != "" {
+		if m.accounts == nil {
+			m.accounts = map[string]string{}
+		}
+		key := userID + "/" + accountID
+		if m.accounts[key] == "" {
+			m.accounts[key] = customerID
+		}
+		return m.accounts[key], nil
+	}
+	if u.StripeCustomerID == "" {
+		u.StripeCustomerID = customerID
+	}
+	return u.StripeCustomerID, nil
+}
+
+func TestStripeFlow_CustomerReusedAndPortalChangesSynced(t *testing.T) {
+	customers := &memStripeCustomerStore{users: map[string]*model.User{
+		"u1": {ID: "u1", Email: "donor@example.com", Name: "Donor"},
+	}}
+	fake, svc, donations, _ := newStripeFlow(t, &mockStripeProjectRepo{}, WithCustomerStore(customers))
+	ctx := context.Background()
+
+	if _, err := svc.CreateBillingPortalSession(ctx, "u1", ""); !errors.Is(err, ErrNoBillingAccount) {
+		t.Errorf("expected ErrNoBillingAccount before the first checkout, got %v", err)
+	}
+
+	recurringURL, err := svc.CreateCheckout(ctx, CheckoutRequest{ProjectID: "p1", Amount: 1000, IsRecurring: true, DonorType: "user", DonorID: "u1"})
+	if err != nil {
+		t.Fatalf("CreateCheckout: %v", err)
+	}
+	customerID := customers.users["u1"].StripeCustomerID
+	if c, ok := fake.Customer(customerID); !ok || c.Email != "donor@example.com" || c.Metadata["user_id"] != "u1" {
+		t.Fatalf("customer %q = %+v", customerID, c)
+	}
+	oneTimeURL, err := svc.CreateCheckout(ctx, CheckoutRequest{ProjectID: "p1", Amount: 500, DonorType: "user", DonorID: "u1"})
+	if err != nil {
+		t.Fatalf("CreateCheckout: %v", err)
+	}
+	for _, u := range []string{recurringURL, oneTimeURL} {
+		if cs, _ := fake.CheckoutSession(stripetest.SessionIDFromURL(u)); cs.Customer != customerID {
+			t.Errorf("checkout customer = %q, want %q", cs.Customer, customerID)
+		}
+	}
+	// 匿名の寄付者には Customer を作らない
+	tokenURL, err := svc.CreateCheckout(ctx, CheckoutRequest{ProjectID: "p1", Amount: 500, DonorType: "token", DonorID: "tok"})
+	if err != nil {
+		t.Fatalf("CreateCheckout: %v", err)
+	}
+	if cs, _ := fake.CheckoutSession(stripetest.SessionIDFromURL(tokenURL)); cs.Customer != "" {
+		t.Errorf("token donor checkout customer = %q", cs.Customer)
+	}
+
+	sessionID := stripetest.SessionIDFromURL(recurringURL)
+	if err := fake.CompleteCheckout(ctx, sessionID); err != nil {
+		t.Fatalf("CompleteCheckout: %v", err)
+	}
+	cs, _ := fake.CheckoutSession(sessionID)
+	if sub, _ := fake.Subscription(cs.SubscriptionID); sub.Customer != customerID {
+		t.Errorf("subscription customer = %q, want %q", sub.Customer, customerID)
+	}
+	portalURL, err := svc.CreateBillingPortalSession(ctx, "u1", "p1")
+	if err != nil || !strings.HasPrefix(portalURL, fake.URL) {
+		t.Fatalf("CreateBillingPortalSession = %q, %v", portalURL, err)
+	}
+
+	// ポータルで金額変更・一時停止・解約予約 → customer.subscription.updated で寄付に反映される
+	cancelAt := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+	if err := fake.UpdateSubscription(ctx, cs.SubscriptionID, func(sub *stripetest.Subscription) {
+		sub.Amount, sub.Paused, sub.CancelAt = 3000, true, cancelAt
+	}); err != nil {
+		t.Fatalf("UpdateSubscription: %v", err)
+	}
+	d := donations.donations[0]
+	if d.Amount != 3000 || d.ChargedAmount != 3000 || !d.Paused || d.CancelAt == nil || !d.CancelAt.Equal(cancelAt) {
+		t.Errorf("donation after portal update = %+v", d)
+	}
+	if err := fake.UpdateSubscription(ctx, cs.SubscriptionID, func(sub *stripetest.Subscription) {
+		sub.Paused, sub.CancelAt = false, time.Time{}
+	}); err != nil {
+		t.Fatalf("UpdateSubscription: %v", err)
+	}
+	if d.Paused || d.CancelAt != nil || d.Amount != 3000 {
+		t.Errorf("donation after resume = %+v", d)
+	}
+}
+
+func TestStripeFlow_ConnectedAccountCustomerReusedAndPortal(t *testing.T) {
+	var accountID string
+	projectRepo := &mockStripeProjectRepo{
+		getByIDFunc: func(_ context.Context, _ string) (string, error) { return accountID, nil },
+	}
+	customers := &memStripeCustomerStore{users: map[string]*model.User{
+		"u1": {ID: "u1", Email: "donor@example.com", StripeCustomerID: "cus_platform"},
+	}}
+	fake, svc, _, _ := newStripeFlow(t, projectRepo, WithCustomerStore(customers))
+	ctx := context.Background()
+
+	var err error
+	accountID, err = fake.Client().CreateConnectedAccount(ctx, pkgstripe.CreateAccountParams{Email: "owner@example.com"})
+	if err != nil {
+		t.Fatalf("CreateConnectedAccount: %v", err)
+	}
+	if _, err := svc.CreateBillingPortalSession(ctx, "u1", "p1"); !errors.Is(err, ErrNoBillingAccount) {
+		t.Errorf("expected ErrNoBillingAccount before the first connected checkout, got %v", err)
+	}
+
+	// Customer はアカウントごとなので、連結アカウントにはそのアカウント上の Customer を作って使い回す
+	var customerID string
+	for i := 0; i < 2; i++ {
+		checkoutURL, err := svc.CreateCheckout(ctx, CheckoutRequest{ProjectID: "p1", Amount: 1000, IsRecurring: true, DonorType: "user", DonorID: "u1"})
+		if err != nil {
+			t.Fatalf("CreateCheckout: %v", err)
+		}
+		cs, _ := fake.CheckoutSession(stripetest.SessionIDFromURL(checkoutURL))
+		if cs.Customer == "" || cs.Customer == "cus_platform" || (customerID != "" && cs.Customer != customerID) {
+			t.Fatalf("connected account checkout customer = %q (previous %q)", cs.Customer, customerID)
+		}
+		customerID = cs.Customer
+	}
+	if c, ok := fake.Customer(customerID); !ok || c.StripeAccount != accountID || c.Metadata["user_id"] != "u1" {
+		t.Errorf("customer %q = %+v", customerID, c)
+	}
+	if customers.users["u1"].StripeCustomerID != "cus_platform" {
+		t.Errorf("platform customer changed to %q", customers.users["u1"].StripeCustomerID)
+	}
+
+	// ポータルはプロジェクトの連結アカウントで開く
+	portalURL, err := svc.CreateBillingPortalSession(ctx, "u1", "p1")
+	if err != nil || !strings.HasPrefix(portalURL, fake.URL) {
+		t.Fatalf("CreateBillingPortalSession = %q, %v", portalURL, err)
+	}
+}
+
+func TestStripeFlow_StripeSideChangesKeptInHistory(t *testing.T) {
+	changeLog := &memDonationChangeLog{}
+	fake, svc, donations, _ := newStripeFlow(t, &mockStripeProjectRepo{}, WithChangeLog(changeLog))
+	ctx := context.Background()
+
+	checkoutURL, err := svc.CreateCheckout(ctx, CheckoutRequest{ProjectID: "p1", Amount: 1000, IsRecurring: true, DonorType: "user", DonorID: "u1"})
+	if err != nil {
+		t.Fatalf("CreateCheckout: %v", err)
+	}
+	sessionID := stripetest.SessionIDFromURL(checkoutURL)
+	if err := fake.CompleteCheckout(ctx, sessionID); err != nil {
+		t.Fatalf("CompleteCheckout: %v", err)
+	}
+	cs, _ := fake.CheckoutSession(sessionID)
+	d := donations.donations[0]
+
+	// ダッシュボード / ポータルでの一時停止 → pause_collection の期限切れで Stripe が自動再開
+	if err := fake.UpdateSubscription(ctx, cs.SubscriptionID, func(sub *stripetest.Subscription) { sub.Paused = true }); err != nil {
+		t.Fatalf("UpdateSubscription: %v", err)
+	}
+	if err := fake.EndPause(ctx, cs.SubscriptionID); err != nil {
+		t.Fatalf("EndPause: %v", err)
+	}
+	// 再試行が尽きて unpaid、Stripe の paused / resumed
+	for _, status := range []string{"unpaid", "paused", "active"} {
+		if err := fake.SetSubscriptionStatus(ctx, cs.SubscriptionID, status); err != nil {
+			t.Fatalf("SetSubscriptionStatus(%s): %v", status, err)
+		}
+	}
+	if d.Paused || d.PaymentStatus != model.PaymentStatusActive {
+		t.Errorf("donation after resume = %+v", d)
+	}
+
+	want := []struct {
+		source, eventType, field string
+	}{
+		{model.DonationChangeSourceStripe, "customer.subscription.updated", "paused"},
+		{model.DonationChangeSourceStripeAutomatic, "customer.subscription.updated", "paused"},
+		{model.DonationChangeSourceStripeAutomatic, "customer.subscription.updated", "payment_status"},
+		{model.DonationChangeSourceStripeAutomatic, "customer.subscription.paused", "paused"},
+		{model.DonationChangeSourceStripeAutomatic, "customer.subscription.resumed", "paused"},
+	}
+	if len(changeLog.changes) != len(want) {
+		t.Fatalf("expected %d changes, got %d: %+v", len(want), len(changeLog.changes), changeLog.changes)
+	}
+	for i, w := range want {
+		c := changeLog.changes[i]
+		if _, ok := c.Changes[w.field]; !ok || c.Source != w.source || c.EventType != w.eventType || c.DonationID != d.ID {
+			t.Errorf("change %d = %+v, want %s %s %s", i, c, w.source, w.eventType, w.field)
+		}
+	}
+}
+
+func TestStripeFlow_DonationSplitToUpstreamProject(t *testing.T) {
+	projects := newTestUpstreamProjects()
+	projectRepo := &mockStripeProjectRepo{
+		getByIDFunc: func(ctx context.Context, id string) (string, error) { return projects.GetStripeAccountID(ctx, id) },
+	}
+	upstreamRepo := newMemUpstreamRepo()
+	var upstreams UpstreamService
+	fake, svc, donations, ledger := newStripeFlow(t, projectRepo, WithUpstreamSplit(upstreamSplitterFunc(func() UpstreamService { return upstreams })))
+	upstreams = NewUpstreamService(upstreamRepo, projects, fake.Client(), nil)
+	ctx := context.Background()
+
+	for _, id := range []string{"app", "lib"} {
+		accountID, err := fake.Client().CreateConnectedAccount(ctx, pkgstripe.CreateAccountParams{Email: id + "@example.com"})
+		if err != nil {
+			t.Fatalf("CreateConnectedAccount: %v", err)
+		}
+		if err := fake.CompleteOnboarding(accountID); err != nil {
+			t.Fatalf("CompleteOnboarding: %v", err)
+		}
+		projects.accounts[id] = accountID
+	}
+	if _, err := upstreams.Replace(ctx, "app", []UpstreamInput{{UpstreamProjectID: "lib", SharePercent: 10}}); err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+
+	// 単発 2000 円と定期 1000 円。上流の取り分はアプリケーション手数料としてプラットフォームに集めてから送金する
+	for _, req := range []CheckoutRequest{
+		{ProjectID: "app", Amount: 2000},
+		{ProjectID: "app", Amount: 1000, IsRecurring: true, DonorType: "user", DonorID: "u1"},
+	} {
+		checkoutURL, err := svc.CreateCheckout(ctx, req)
+		if err != nil {
+			t.Fatalf("CreateCheckout: %v", err)
+		}
+		if err := fake.CompleteCheckout(ctx, stripetest.SessionIDFromURL(checkoutURL)); err != nil {
+			t.Fatalf("CompleteCheckout: %v", err)
+		}
+	}
+
+	if len(donations.donations) != 2 || len(ledger.payments) != 2 {
+		t.Fatalf("donations = %d, payments = %d, want 2 and 2", len(donations.donations), len(ledger.payments))
+	}
+	transfers := fake.Transfers()
+	if len(transfers) != 2 {
+		t.Fatalf("transfers = %d, want 2", len(transfers))
+	}
+	for i, want := range []int{200, 100} {
+		tr := transfers[i]
+		if tr.Destination != projects.accounts["lib"] || tr.Amount != want || tr.Currency != "jpy" || tr.TransferGroup != ledger.payments[i].ID {
+			t.Errorf("transfer %d = %+v", i, tr)
+		}
+	}
+	for _, pt := range upstreamRepo.passThroughs {
+		if pt.Status != model.PassThroughStatusTransferred || pt.FromProjectID != "app" || pt.ToProjectID != "lib" {
+			t.Errorf("pass-through = %+v", pt)
+		}
+	}
+}
+
+// upstreamSplitterFunc は Stripe クライアントを作った後で UpstreamService を渡すためのアダプタ（テスト用）
+type upstreamSplitterFunc func() UpstreamService
+
+func (f upstreamSplitterFunc) SharePercent(ctx context.Context, projectID string) (int, error) {
+	return f().SharePercent(ctx, projectID)
+}
+
+func (f upstreamSplitterFunc) SplitPayment(ctx context.Context, p *model.DonationPayment, available int) error {
+	return f().SplitPayment(ctx, p, available)
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/givers/backend/internal/model"
 	"github.com/givers/backend/internal/repository"
@@ -16,6 +19,9 @@ type CheckoutRequest struct {
 	Amount      int
 	Currency    string
 	IsRecurring bool
+	Interval    string // 定期寄付の請求間隔（model.IntervalMonth など）。空なら毎月
+	TierID      string // 目安額の ID。指定時は Amount / IsRecurring / Interval の代わりに目安額の設定を使う
+	CoverFees   bool   // 寄付者が決済手数料を負担する（Amount がそのまま届くように請求額を上乗せする）
 	Message     string
 	Locale      string
 	FrontendURL string
@@ -28,6 +34,13 @@ type StripeProjectRepo interface {
 	GetStripeAccountID(ctx context.Context, projectID string) (string, error)
 	SaveStripeAccountID(ctx context.Context, projectID, stripeAccountID string) error
 	ActivateProject(ctx context.Context, projectID string) error
+	GetStatus(ctx context.Context, projectID string) (string, error)
+	// GetDonationTiers はオーナーが設定した寄付の目安額を返す
+	GetDonationTiers(ctx context.Context, projectID string) ([]model.DonationTier, error)
+	// GetPaymentMethodTypes はオーナーが有効にした支払い方法を返す（空ならカードのみ）
+	GetPaymentMethodTypes(ctx context.Context, projectID string) ([]string, error)
+	// SyncStripeAccountStatus は連結アカウントの状態を保存し、status が変わったプロジェクトの projectID → status を返す
+	SyncStripeAccountStatus(ctx context.Context, accountID string, st model.StripeAccountStatus) (map[string]string, error)
 }
 
 // StripeDonationRepo は Webhook イベントで寄付レコードを操作するためのミニマムインターフェース
@@ -36,6 +49,22 @@ type StripeDonationRepo interface {
 	DeleteByStripeSubscriptionID(ctx context.Context, subscriptionID string) error
 	GetByStripeSubscriptionID(ctx context.Context, subscriptionID string) (*model.Donation, error)
 	Patch(ctx context.Context, id string, patch model.DonationPatch) error
+	GetByStripePaymentID(ctx context.Context, paymentID string) (*model.Donation, error)
+	SyncAdjustments(ctx context.Context, id string) error
+}
+
+// StripePaymentLedger は着金した決済を台帳（donation_payments）に記録するためのミニマムインターフェース
+type StripePaymentLedger interface {
+	Create(ctx context.Context, p *model.DonationPayment) error
+	GetByStripePaymentID(ctx context.Context, paymentID string) (*model.DonationPayment, error)
+	RecordRefund(ctx context.Context, id string, refundedAmount int) error
+	RecordDispute(ctx context.Context, id string, status string, amount int) error
+}
+
+// StripeMatcher は台帳に記録した入金をマッチングキャンペーンで上乗せし、返金・敗訴で減らすミニマムインターフェース
+type StripeMatcher interface {
+	ApplyPayment(ctx context.Context, p *model.DonationPayment) error
+	ReleasePayment(ctx context.Context, p *model.DonationPayment) error
 }
 
 // StripeActivityRecorder は寄付確定時にアクティビティを記録するためのミニマムインターフェース
@@ -48,6 +77,69 @@ type StripeMilestoneNotifier interface {
 	NotifyDonation(ctx context.Context, projectID string) error
 }
 
+// StripeWebhookEventStore は Webhook イベントの永続化・重複排除・再実行に使うミニマムインターフェース
+type StripeWebhookEventStore interface {
+	Begin(ctx context.Context, id, eventType string, payload []byte) (started bool, err error)
+	BeginReplay(ctx context.Context, id string) (*model.WebhookEvent, error)
+	MarkProcessed(ctx context.Context, id string) error
+	MarkFailed(ctx context.Context, id, lastError string) error
+	ListByStatus(ctx context.Context, status string, limit, offset int) ([]*model.WebhookEvent, error)
+}
+
+// StripeCheckoutSessionStore は作成した Checkout Session の記録と完了・期限切れの反映に使うミニマムインターフェース
+type StripeCheckoutSessionStore interface {
+	Create(ctx context.Context, cs *model.CheckoutSession) error
+	MarkCompleted(ctx context.Context, id, paymentID, subscriptionID string) error
+	MarkExpired(ctx context.Context, id string) error
+	GetByID(ctx context.Context, id string) (*model.CheckoutSession, error)
+	StatsByProject(ctx context.Context, projectID string, since time.Time) (*model.CheckoutStats, error)
+}
+
+// StripeCustomerStore はユーザーの Stripe Customer ID をアカウントごとに保存する（accountID が空ならプラットフォームアカウント）
+type StripeCustomerStore interface {
+	FindByID(ctx context.Context, id string) (*model.User, error)
+	FindStripeCustomerID(ctx context.Context, userID, accountID string) (string, error)
+	SaveStripeCustomerID(ctx context.Context, userID, accountID, customerID string) (string, error)
+}
+
+// StripeDonationChangeLog は定期寄付の変更履歴を記録するミニマムインターフェース
+type StripeDonationChangeLog interface {
+	Create(ctx context.Context, c *model.DonationChange) error
+}
+
+// StripeUpstreamSplitter は入金のうち上流プロジェクトの取り分を分配するミニマムインターフェース
+type StripeUpstreamSplitter interface {
+	SharePercent(ctx context.Context, projectID string) (int, error)
+	SplitPayment(ctx context.Context, p *model.DonationPayment, available int) error
+}
+
+// ErrCheckoutSessionsNotConfigured は Checkout Session ストアが未設定の場合のエラー
+var ErrCheckoutSessionsNotConfigured = errors.New("stripe: checkout session store not configured")
+
+// ErrWebhookEventsNotConfigured は Webhook イベントストアが未設定の場合のエラー
+var ErrWebhookEventsNotConfigured = errors.New("stripe: webhook event store not configured")
+
+// ErrCustomersNotConfigured は Customer ストアが未設定の場合のエラー
+var ErrCustomersNotConfigured = errors.New("stripe: customer store not configured")
+
+// ErrNoBillingAccount はユーザーにまだ Stripe Customer がない（ログインして寄付したことがない）場合のエラー
+var ErrNoBillingAccount = errors.New("stripe: user has no billing account")
+
+// ErrInvalidInterval は定期寄付の請求間隔が不正な場合のエラー
+var ErrInvalidInterval = errors.New("stripe: invalid billing interval")
+
+// ErrInvalidTier は指定された目安額がプロジェクトにない場合のエラー
+var ErrInvalidTier = errors.New("stripe: unknown donation tier")
+
+// ErrRecurringRequiresLogin は未ログインで定期寄付の目安額を選んだ場合のエラー
+var ErrRecurringRequiresLogin = errors.New("stripe: recurring donations require login")
+
+// ErrCoverFeesUnsupported は手数料表にない通貨で手数料の負担を選んだ場合のエラー
+var ErrCoverFeesUnsupported = errors.New("stripe: processing fees cannot be covered in this currency")
+
+// ErrProjectNotAcceptingDonations は凍結・削除されたプロジェクトへの寄付の場合のエラー
+var ErrProjectNotAcceptingDonations = errors.New("stripe: project is not accepting donations")
+
 // StripeService は Stripe 連携のビジネスロジック
 type StripeService interface {
 	// CreateAccountAndOnboarding は v2 API でアカウント作成 → Account Link URL を返す
@@ -60,38 +152,111 @@ type StripeService interface {
 	CreateCheckout(ctx context.Context, req CheckoutRequest) (string, error)
 	// ProcessWebhook は Webhook のシグネチャを検証してイベントを処理する
 	ProcessWebhook(ctx context.Context, payload []byte, sigHeader string) error
+	// ListWebhookEvents は保存済み Webhook イベントをステータスで絞り込んで返す
+	ListWebhookEvents(ctx context.Context, status string, limit, offset int) ([]*model.WebhookEvent, error)
+	// ReplayWebhookEvent は失敗した Webhook イベントを同じハンドラで再実行する
+	ReplayWebhookEvent(ctx context.Context, eventID string) error
+	// GetCheckoutSession は Checkout Session の状態（寄付が記録済みかどうかを含む）を返す
+	GetCheckoutSession(ctx context.Context, sessionID string) (*model.CheckoutSession, error)
+	// CheckoutStats はプロジェクトで since 以降に開始された Checkout Session を状態別に集計する
+	CheckoutStats(ctx context.Context, projectID string, since time.Time) (*model.CheckoutStats, error)
+	// CreateBillingPortalSession はユーザーの定期寄付・支払い方法を管理する Customer Portal の URL を返す。
+	// Customer はアカウントごとなので、projectID があればそのプロジェクトの連結アカウントのポータル、
+	// 空ならプラットフォームアカウント（ホストのプロジェクト）のポータルを開く
+	CreateBillingPortalSession(ctx context.Context, userID, projectID string) (string, error)
 }
 
 // StripeServiceImpl は StripeService の実装
 type StripeServiceImpl struct {
-	client             pkgstripe.Client
-	projectRepo        StripeProjectRepo
-	donationRepo       StripeDonationRepo
-	activityRecorder   StripeActivityRecorder  // optional, nil = skip
-	milestoneNotifier  StripeMilestoneNotifier // optional, nil = skip
-	frontendURL        string
+	client            pkgstripe.Client
+	projectRepo       StripeProjectRepo
+	donationRepo      StripeDonationRepo
+	activityRecorder  StripeActivityRecorder     // optional, nil = skip
+	milestoneNotifier StripeMilestoneNotifier    // optional, nil = skip
+	eventStore        StripeWebhookEventStore    // optional, nil = no persistence / dedup
+	paymentLedger     StripePaymentLedger        // optional, nil = no payment ledger / refunds
+	matcher           StripeMatcher              // optional, nil = no matching (requires paymentLedger)
+	checkoutSessions  StripeCheckoutSessionStore // optional, nil = sessions are not tracked
+	customers         StripeCustomerStore        // optional, nil = a new customer per checkout, no portal
+	changeLog         StripeDonationChangeLog    // optional, nil = subscription changes are not recorded
+	upstreams         StripeUpstreamSplitter     // optional, nil = no pass-through to upstream projects (requires paymentLedger)
+	feeSchedule       pkgstripe.FeeSchedule      // cover_fees の上乗せ額の計算に使う（既定は pkgstripe.DefaultFeeSchedule）
+	frontendURL       string
+}
+
+// StripeServiceOption は StripeServiceImpl の任意の依存を設定する
+type StripeServiceOption func(*StripeServiceImpl)
+
+// WithWebhookEventStore は Webhook イベントの永続化・重複排除を有効にする
+func WithWebhookEventStore(store StripeWebhookEventStore) StripeServiceOption {
+	return func(s *StripeServiceImpl) { s.eventStore = store }
+}
+
+// WithPaymentLedger は入金台帳への記録（請求ごとの入金・返金・係争）を有効にする
+func WithPaymentLedger(ledger StripePaymentLedger) StripeServiceOption {
+	return func(s *StripeServiceImpl) { s.paymentLedger = ledger }
+}
+
+// WithMatching は台帳に記録した入金へのマッチングキャンペーンの上乗せを有効にする（WithPaymentLedger が必要）
+func WithMatching(matcher StripeMatcher) StripeServiceOption {
+	return func(s *StripeServiceImpl) { s.matcher = matcher }
+}
+
+// WithCheckoutSessionStore は作成した Checkout Session の記録（完了・期限切れ・放棄率の集計）を有効にする
+func WithCheckoutSessionStore(store StripeCheckoutSessionStore) StripeServiceOption {
+	return func(s *StripeServiceImpl) { s.checkoutSessions = store }
+}
+
+// WithCustomerStore はログイン中の寄付者の Stripe Customer の作成・再利用と Customer Portal を有効にする
+func WithCustomerStore(store StripeCustomerStore) StripeServiceOption {
+	return func(s *StripeServiceImpl) { s.customers = store }
+}
+
+// WithChangeLog は Webhook で反映した定期寄付の変更（Stripe 側での金額変更・一時停止など）の記録を有効にする
+func WithChangeLog(log StripeDonationChangeLog) StripeServiceOption {
+	return func(s *StripeServiceImpl) { s.changeLog = log }
+}
+
+// WithUpstreamSplit は上流プロジェクトへの分配（連結アカウントの決済ではアプリケーション手数料として徴収して送金）を有効にする（WithPaymentLedger が必要）
+func WithUpstreamSplit(splitter StripeUpstreamSplitter) StripeServiceOption {
+	return func(s *StripeServiceImpl) { s.upstreams = splitter }
+}
+
+// WithFeeSchedule は寄付者が決済手数料を負担する場合の手数料表を設定する
+func WithFeeSchedule(fs pkgstripe.FeeSchedule) StripeServiceOption {
+	return func(s *StripeServiceImpl) { s.feeSchedule = fs }
 }
 
 // NewStripeService は StripeServiceImpl を生成する
-func NewStripeService(client pkgstripe.Client, projectRepo StripeProjectRepo, donationRepo StripeDonationRepo, frontendURL string) StripeService {
-	return &StripeServiceImpl{
+func NewStripeService(client pkgstripe.Client, projectRepo StripeProjectRepo, donationRepo StripeDonationRepo, frontendURL string, opts ...StripeServiceOption) StripeService {
+	s := &StripeServiceImpl{
 		client:       client,
 		projectRepo:  projectRepo,
 		donationRepo: donationRepo,
+		feeSchedule:  pkgstripe.DefaultFeeSchedule,
 		frontendURL:  frontendURL,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // NewStripeServiceWithActivity は ActivityRecorder + MilestoneNotifier 付きの StripeServiceImpl を生成する
-func NewStripeServiceWithActivity(client pkgstripe.Client, projectRepo StripeProjectRepo, donationRepo StripeDonationRepo, frontendURL string, activityRecorder StripeActivityRecorder, milestoneNotifier StripeMilestoneNotifier) StripeService {
-	return &StripeServiceImpl{
+func NewStripeServiceWithActivity(client pkgstripe.Client, projectRepo StripeProjectRepo, donationRepo StripeDonationRepo, frontendURL string, activityRecorder StripeActivityRecorder, milestoneNotifier StripeMilestoneNotifier, opts ...StripeServiceOption) StripeService {
+	s := &StripeServiceImpl{
 		client:            client,
 		projectRepo:       projectRepo,
 		donationRepo:      donationRepo,
 		activityRecorder:  activityRecorder,
 		milestoneNotifier: milestoneNotifier,
+		feeSchedule:       pkgstripe.DefaultFeeSchedule,
 		frontendURL:       frontendURL,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // CreateAccountAndOnboarding は v2 API でアカウントを作成し、Account Link URL を返す
@@ -158,9 +323,39 @@ func (s *StripeServiceImpl) RefreshOnboarding(ctx context.Context, projectID str
 
 // CreateCheckout はプロジェクトの stripe_account_id を取得して Checkout Session を作成する
 func (s *StripeServiceImpl) CreateCheckout(ctx context.Context, req CheckoutRequest) (string, error) {
+	if req.TierID != "" {
+		tiers, err := s.projectRepo.GetDonationTiers(ctx, req.ProjectID)
+		if err != nil {
+			return "", fmt.Errorf("get donation tiers: %w", err)
+		}
+		tier := model.FindDonationTier(tiers, req.TierID)
+		if tier == nil {
+			return "", ErrInvalidTier
+		}
+		req.Amount, req.IsRecurring, req.Interval = tier.Amount, tier.IsRecurring, tier.Interval
+		if req.IsRecurring && req.DonorType == "token" {
+			return "", ErrRecurringRequiresLogin
+		}
+	}
 	if req.Amount <= 0 {
 		return "", errors.New("amount must be greater than 0")
 	}
+	interval := req.Interval
+	if interval == "" {
+		interval = model.IntervalMonth
+	}
+	if !model.ValidInterval(interval) || (!req.IsRecurring && interval != model.IntervalMonth) {
+		return "", ErrInvalidInterval
+	}
+
+	// 凍結中（連結アカウントの停止による自動凍結を含む）・削除済みのプロジェクトには寄付できない
+	status, err := s.projectRepo.GetStatus(ctx, req.ProjectID)
+	if err != nil {
+		return "", fmt.Errorf("get project: %w", err)
+	}
+	if status == "frozen" || status == "deleted" {
+		return "", ErrProjectNotAcceptingDonations
+	}
 
 	stripeAccountID, err := s.projectRepo.GetStripeAccountID(ctx, req.ProjectID)
 	if err != nil {
@@ -178,23 +373,172 @@ func (s *StripeServiceImpl) CreateCheckout(ctx context.Context, req CheckoutRequ
 		locale = "ja"
 	}
 
+	// 手数料を負担する場合は、手数料を差し引いても Amount が届くように請求額を上乗せする
+	charged, intended := req.Amount, 0
+	if req.CoverFees {
+		var ok bool
+		if charged, ok = s.feeSchedule.GrossUp(req.Amount, currency); !ok {
+			return "", ErrCoverFeesUnsupported
+		}
+		intended = req.Amount
+	}
+
+	// SuccessURL の {CHECKOUT_SESSION_ID} は Stripe が Session ID に置き換える（フロントが状態をポーリングする）
 	params := pkgstripe.CheckoutParams{
 		StripeAccountID: stripeAccountID,
 		ProjectID:       req.ProjectID,
-		Amount:          req.Amount,
+		Amount:          charged,
+		IntendedAmount:  intended,
 		Currency:        currency,
 		IsRecurring:     req.IsRecurring,
 		Message:         req.Message,
 		Locale:          locale,
-		SuccessURL:      s.frontendURL + "/projects/" + req.ProjectID + "?donated=1",
+		SuccessURL:      s.frontendURL + "/projects/" + req.ProjectID + "?donated=1&session_id={CHECKOUT_SESSION_ID}",
 		CancelURL:       s.frontendURL + "/projects/" + req.ProjectID,
 		DonorType:       req.DonorType,
 		DonorID:         req.DonorID,
+		TierID:          req.TierID,
+	}
+	if req.IsRecurring {
+		params.Interval, params.IntervalCount = model.StripeInterval(interval)
+	}
+	// コンビニ・銀行振込は単発の円建て寄付のみ（定期寄付・他通貨はカードのみ）
+	methods, err := s.projectRepo.GetPaymentMethodTypes(ctx, req.ProjectID)
+	if err != nil {
+		return "", fmt.Errorf("get payment method types: %w", err)
+	}
+	params.PaymentMethodTypes = model.CheckoutMethodsFor(methods, req.IsRecurring, currency)
+	// 連結アカウントの決済では、上流プロジェクトの取り分をアプリケーション手数料としてプラットフォームに集める
+	// （送金はプラットフォームの残高からしかできない）。ホストのプロジェクトは全額がプラットフォームに入る
+	if stripeAccountID != "" && s.upstreams != nil && s.paymentLedger != nil {
+		share, err := s.upstreams.SharePercent(ctx, req.ProjectID)
+		if err != nil {
+			return "", fmt.Errorf("get upstream share: %w", err)
+		}
+		if share > 0 {
+			if req.IsRecurring {
+				params.ApplicationFeePercent = share
+			} else {
+				params.ApplicationFeeAmount = req.Amount * share / 100
+			}
+		}
+	}
+	// Customer はアカウントごとなので、決済するアカウント上のユーザーの Customer を使う
+	if req.DonorType == "user" && req.DonorID != "" {
+		params.CustomerID = s.ensureCustomer(ctx, req.DonorID, stripeAccountID)
+	}
+	session, err := s.client.CreateCheckoutSession(ctx, params)
+	if err != nil {
+		return "", err
+	}
+	s.recordCheckoutSession(ctx, &model.CheckoutSession{
+		ID:          session.ID,
+		ProjectID:   req.ProjectID,
+		DonorType:   req.DonorType,
+		DonorID:     req.DonorID,
+		Amount:      req.Amount,
+		Currency:    currency,
+		IsRecurring: req.IsRecurring,
+		Interval:    interval,
+		TierID:      req.TierID,
+	})
+	return session.URL, nil
+}
+
+// ensureCustomer はユーザーの accountID 上（空ならプラットフォーム）の Stripe Customer ID を返し、なければ作成して保存する。
+// 失敗しても寄付は止めずに空を返す（Checkout が Customer を作成する）。
+func (s *StripeServiceImpl) ensureCustomer(ctx context.Context, userID, accountID string) string {
+	if s.customers == nil {
+		return ""
+	}
+	customerID, err := s.customers.FindStripeCustomerID(ctx, userID, accountID)
+	if err != nil {
+		slog.Warn("stripe: get customer failed", "user_id", userID, "stripe_account_id", accountID, "error", err)
+		return ""
+	}
+	if customerID != "" {
+		return customerID
+	}
+	u, err := s.customers.FindByID(ctx, userID)
+	if err != nil {
+		slog.Warn("stripe: get user for customer failed", "user_id", userID, "error", err)
+		return ""
+	}
+	customerID, err = s.client.CreateCustomer(ctx, pkgstripe.CustomerParams{StripeAccountID: accountID, Email: u.Email, Name: u.Name, UserID: u.ID})
+	if err != nil {
+		slog.Warn("stripe: create customer failed", "user_id", userID, "stripe_account_id", accountID, "error", err)
+		return ""
+	}
+	// 同時に作成された場合は先に保存された方に揃える（作成した Customer は使われずに残る）
+	saved, err := s.customers.SaveStripeCustomerID(ctx, userID, accountID, customerID)
+	if err != nil {
+		slog.Warn("stripe: save customer failed", "user_id", userID, "customer_id", customerID, "error", err)
+		return customerID
+	}
+	return saved
+}
+
+// CreateBillingPortalSession はユーザーの Customer の Customer Portal Session を作成する。
+// projectID が連結アカウントのプロジェクトなら、そのアカウント上の Customer のポータルになる（アカウントをまたいだ一覧はできない）。
+// ポータルでの変更は customer.subscription.updated / deleted で寄付に反映される。
+func (s *StripeServiceImpl) CreateBillingPortalSession(ctx context.Context, userID, projectID string) (string, error) {
+	if s.customers == nil {
+		return "", ErrCustomersNotConfigured
+	}
+	var accountID string
+	if projectID != "" {
+		var err error
+		if accountID, err = s.projectRepo.GetStripeAccountID(ctx, projectID); err != nil {
+			return "", fmt.Errorf("get project: %w", err)
+		}
+	}
+	customerID, err := s.customers.FindStripeCustomerID(ctx, userID, accountID)
+	if err != nil {
+		return "", fmt.Errorf("get customer: %w", err)
+	}
+	if customerID == "" {
+		return "", ErrNoBillingAccount
+	}
+	return s.client.CreateBillingPortalSession(ctx, accountID, customerID, s.frontendURL+"/me")
+}
+
+// recordCheckoutSession は作成した Checkout Session を記録する。
+// 決済ページはすでに作成済みなので、記録に失敗しても寄付は止めずにログだけ残す。
+func (s *StripeServiceImpl) recordCheckoutSession(ctx context.Context, cs *model.CheckoutSession) {
+	if s.checkoutSessions == nil || cs.ID == "" {
+		return
+	}
+	if err := s.checkoutSessions.Create(ctx, cs); err != nil {
+		slog.Error("stripe: record checkout session failed", "session_id", cs.ID, "project_id", cs.ProjectID, "error", err)
+	}
+}
+
+// GetCheckoutSession は Checkout Session の状態を返す。未記録の Session は repository.ErrNotFound。
+func (s *StripeServiceImpl) GetCheckoutSession(ctx context.Context, sessionID string) (*model.CheckoutSession, error) {
+	if s.checkoutSessions == nil {
+		return nil, ErrCheckoutSessionsNotConfigured
+	}
+	return s.checkoutSessions.GetByID(ctx, sessionID)
+}
+
+// CheckoutStats はプロジェクトの Checkout Session を集計し、放棄率
+// （期限切れ / (完了 + 期限切れ)。支払い可能なままの Session は数えない）を計算する
+func (s *StripeServiceImpl) CheckoutStats(ctx context.Context, projectID string, since time.Time) (*model.CheckoutStats, error) {
+	if s.checkoutSessions == nil {
+		return nil, ErrCheckoutSessionsNotConfigured
+	}
+	st, err := s.checkoutSessions.StatsByProject(ctx, projectID, since)
+	if err != nil {
+		return nil, err
 	}
-	return s.client.CreateCheckoutSession(ctx, params)
+	if finished := st.Completed + st.Expired; finished > 0 {
+		st.AbandonmentRate = float64(st.Expired) / float64(finished)
+	}
+	return st, nil
 }
 
-// ProcessWebhook は Webhook シグネチャを検証してイベントを処理する
+// ProcessWebhook は Webhook シグネチャを検証してイベントを処理する。
+// イベントストアが設定されている場合、処理済みイベントの再配信はスキップする。
 func (s *StripeServiceImpl) ProcessWebhook(ctx context.Context, payload []byte, sigHeader string) error {
 	if err := s.client.VerifyWebhookSignature(payload, sigHeader); err != nil {
 		return fmt.Errorf("webhook signature: %w", err)
@@ -203,6 +547,63 @@ func (s *StripeServiceImpl) ProcessWebhook(ctx context.Context, payload []byte,
 	if err != nil {
 		return err
 	}
+	if s.eventStore == nil || event.ID == "" {
+		return s.dispatchEvent(ctx, event)
+	}
+
+	started, err := s.eventStore.Begin(ctx, event.ID, event.Type, payload)
+	if err != nil {
+		return fmt.Errorf("record webhook event: %w", err)
+	}
+	if !started {
+		slog.Info("stripe webhook: duplicate event skipped", "event_id", event.ID, "type", event.Type)
+		return nil
+	}
+	return s.runStoredEvent(ctx, event)
+}
+
+// ListWebhookEvents は保存済み Webhook イベントを返す
+func (s *StripeServiceImpl) ListWebhookEvents(ctx context.Context, status string, limit, offset int) ([]*model.WebhookEvent, error) {
+	if s.eventStore == nil {
+		return nil, ErrWebhookEventsNotConfigured
+	}
+	return s.eventStore.ListByStatus(ctx, status, limit, offset)
+}
+
+// ReplayWebhookEvent は失敗した Webhook イベントを保存済みペイロードから再実行する。
+// 失敗状態でも処理中のまま期限（10 分）を過ぎた状態でもないイベントは repository.ErrNotFound を返す。
+func (s *StripeServiceImpl) ReplayWebhookEvent(ctx context.Context, eventID string) error {
+	if s.eventStore == nil {
+		return ErrWebhookEventsNotConfigured
+	}
+	stored, err := s.eventStore.BeginReplay(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	event, err := s.client.ParseWebhookEvent(stored.Payload)
+	if err != nil {
+		_ = s.eventStore.MarkFailed(ctx, eventID, err.Error())
+		return fmt.Errorf("parse stored webhook event: %w", err)
+	}
+	return s.runStoredEvent(ctx, event)
+}
+
+// runStoredEvent はイベントを処理し、結果をイベントストアに記録する
+func (s *StripeServiceImpl) runStoredEvent(ctx context.Context, event pkgstripe.WebhookEvent) error {
+	if err := s.dispatchEvent(ctx, event); err != nil {
+		if markErr := s.eventStore.MarkFailed(ctx, event.ID, err.Error()); markErr != nil {
+			slog.Error("stripe webhook: mark failed failed", "event_id", event.ID, "error", markErr)
+		}
+		return err
+	}
+	if err := s.eventStore.MarkProcessed(ctx, event.ID); err != nil {
+		slog.Error("stripe webhook: mark processed failed", "event_id", event.ID, "error", err)
+	}
+	return nil
+}
+
+// dispatchEvent はイベントタイプごとのハンドラに振り分ける
+func (s *StripeServiceImpl) dispatchEvent(ctx context.Context, event pkgstripe.WebhookEvent) error {
 	switch event.Type {
 	case "payment_intent.succeeded":
 		return s.handlePaymentIntentSucceeded(ctx, event)
@@ -212,43 +613,96 @@ func (s *StripeServiceImpl) ProcessWebhook(ctx context.Context, payload []byte,
 		return s.handleSubscriptionDeleted(ctx, event)
 	case "invoice.payment_succeeded":
 		return s.handleInvoicePaymentSucceeded(ctx, event)
+	case "invoice.payment_failed":
+		return s.handleInvoicePaymentFailed(ctx, event)
+	case "customer.subscription.updated", "customer.subscription.paused", "customer.subscription.resumed":
+		return s.handleSubscriptionUpdated(ctx, event)
+	case "charge.refunded":
+		return s.handleChargeRefunded(ctx, event)
+	case "charge.dispute.created", "charge.dispute.closed":
+		return s.handleChargeDispute(ctx, event)
+	case "account.updated":
+		return s.handleAccountUpdated(ctx, event)
+	case "checkout.session.completed":
+		return s.handleCheckoutSessionCompleted(ctx, event)
+	case "checkout.session.expired":
+		return s.handleCheckoutSessionExpired(ctx, event)
+	case "checkout.session.async_payment_succeeded":
+		return s.handleAsyncPaymentResult(ctx, event, model.PaymentStatusActive)
+	case "checkout.session.async_payment_failed":
+		return s.handleAsyncPaymentResult(ctx, event, model.PaymentStatusFailed)
+	}
+	if isV2AccountEvent(event.Type) {
+		return s.handleV2AccountEvent(ctx, event)
 	}
 	return nil
 }
 
-func (s *StripeServiceImpl) handlePaymentIntentSucceeded(ctx context.Context, event pkgstripe.WebhookEvent) error {
-	obj := event.Data.Object
-	projectID := obj.Metadata["project_id"]
-	if projectID == "" {
-		return errors.New("stripe webhook: payment_intent.succeeded missing project_id in metadata")
-	}
+// isV2AccountEvent は連結アカウントに関する v2 の thin イベント
+// （v2.core.account.updated、v2.core.account[requirements].updated など）かどうかを返す
+func isV2AccountEvent(eventType string) bool {
+	return strings.HasPrefix(eventType, "v2.core.account.") || strings.HasPrefix(eventType, "v2.core.account[")
+}
 
-	donorType := obj.Metadata["donor_type"]
+// oneTimeDonation は単発寄付の決済のメタデータから寄付を組み立てる。
+// 寄付者が手数料を負担した場合、Amount には上乗せ前の寄付額を使う。
+func oneTimeDonation(metadata map[string]string, charged int, currency, paymentIntentID string) *model.Donation {
+	donorType := metadata["donor_type"]
 	if donorType == "" {
 		donorType = "token"
 	}
-	donorID := obj.Metadata["donor_id"]
-
-	currency := obj.Currency
 	if currency == "" {
 		currency = "jpy"
 	}
-
-	d := &model.Donation{
-		ProjectID:       projectID,
+	return &model.Donation{
+		ProjectID:       metadata["project_id"],
 		DonorType:       donorType,
-		DonorID:         donorID,
-		Amount:          obj.Amount,
+		DonorID:         metadata["donor_id"],
+		Amount:          pkgstripe.IntendedAmount(metadata, charged),
+		ChargedAmount:   charged,
 		Currency:        currency,
-		Message:         obj.Metadata["message"],
-		IsRecurring:     obj.Metadata["is_recurring"] == "true",
-		StripePaymentID: obj.ID,
+		Message:         metadata["message"],
+		IsRecurring:     metadata["is_recurring"] == "true",
+		TierID:          metadata["tier_id"],
+		StripePaymentID: paymentIntentID,
 	}
+}
+
+func (s *StripeServiceImpl) handlePaymentIntentSucceeded(ctx context.Context, event pkgstripe.WebhookEvent) error {
+	obj := event.Data.Object
+	if obj.Metadata["project_id"] == "" {
+		return errors.New("stripe webhook: payment_intent.succeeded missing project_id in metadata")
+	}
+
+	d := oneTimeDonation(obj.Metadata, obj.Amount, obj.Currency, obj.ID)
 	if err := s.donationRepo.Create(ctx, d); err != nil && !errors.Is(err, repository.ErrDuplicate) {
 		return err
 	}
-	s.recordDonationActivity(ctx, projectID, donorID, obj.Amount, obj.Metadata["message"])
-	s.notifyMilestone(ctx, projectID)
+	if s.paymentLedger != nil {
+		if d.ID == "" {
+			// 再送、またはコンビニ・銀行振込で入金待ちとして作成済みの場合は既存の寄付に入金を紐づける
+			existing, err := s.donationRepo.GetByStripePaymentID(ctx, obj.ID)
+			if err != nil {
+				return fmt.Errorf("find donation by payment intent: %w", err)
+			}
+			if err := s.setPaymentStatus(ctx, existing, model.PaymentStatusActive); err != nil {
+				return err
+			}
+			d.ID = existing.ID
+		}
+		if err := s.recordPayment(ctx, &model.DonationPayment{
+			DonationID:      d.ID,
+			ProjectID:       d.ProjectID,
+			Amount:          d.Amount,
+			ChargedAmount:   d.ChargedAmount,
+			Currency:        d.Currency,
+			StripePaymentID: obj.ID,
+		}, upstreamAvailable(event, d.Amount)); err != nil {
+			return err
+		}
+	}
+	s.recordDonationActivity(ctx, d.ProjectID, d.DonorID, d.Amount, d.Message)
+	s.notifyMilestone(ctx, d.ProjectID)
 	return nil
 }
 
@@ -265,24 +719,30 @@ func (s *StripeServiceImpl) handleSubscriptionCreated(ctx context.Context, event
 	}
 	donorID := obj.Metadata["donor_id"]
 
-	amount := obj.Amount
+	charged := obj.Amount
 	currency := obj.Currency
+	interval := model.IntervalMonth
 	if obj.Plan != nil {
-		amount = obj.Plan.Amount
+		charged = obj.Plan.Amount
 		currency = obj.Plan.Currency
+		interval = model.IntervalFromStripe(obj.Plan.Interval, obj.Plan.IntervalCount)
 	}
 	if currency == "" {
 		currency = "jpy"
 	}
+	amount := pkgstripe.IntendedAmount(obj.Metadata, charged)
 
 	d := &model.Donation{
 		ProjectID:            projectID,
 		DonorType:            donorType,
 		DonorID:              donorID,
 		Amount:               amount,
+		ChargedAmount:        charged,
 		Currency:             currency,
 		Message:              obj.Metadata["message"],
 		IsRecurring:          true,
+		Interval:             interval,
+		TierID:               obj.Metadata["tier_id"],
 		StripeSubscriptionID: obj.ID,
 	}
 	if err := s.donationRepo.Create(ctx, d); err != nil && !errors.Is(err, repository.ErrDuplicate) {
@@ -293,6 +753,46 @@ func (s *StripeServiceImpl) handleSubscriptionCreated(ctx context.Context, event
 	return nil
 }
 
+// recordPayment は入金を台帳に記録し、マッチングキャンペーンの上乗せと上流プロジェクトへの分配を反映する（記録済みの入金は無視）。
+// 上乗せ・分配の記録に失敗した場合はエラーを返して Stripe の再送で再実行する（反映済みの分はスキップされる）。
+// upstreamAvailable は上流へ分配できる額（プラットフォームでの決済は全額、連結アカウントの決済はアプリケーション手数料）。
+func (s *StripeServiceImpl) recordPayment(ctx context.Context, p *model.DonationPayment, upstreamAvailable int) error {
+	if err := s.paymentLedger.Create(ctx, p); err != nil {
+		if !errors.Is(err, repository.ErrDuplicate) {
+			return fmt.Errorf("record payment: %w", err)
+		}
+		if (s.matcher == nil && s.upstreams == nil) || p.StripePaymentID == "" {
+			return nil
+		}
+		// 再送の場合は記録済みの入金で上乗せ・分配をやり直す
+		existing, err := s.paymentLedger.GetByStripePaymentID(ctx, p.StripePaymentID)
+		if err != nil {
+			return fmt.Errorf("find payment: %w", err)
+		}
+		p = existing
+	}
+	if s.matcher != nil {
+		if err := s.matcher.ApplyPayment(ctx, p); err != nil {
+			return fmt.Errorf("apply matching: %w", err)
+		}
+	}
+	if s.upstreams != nil {
+		if err := s.upstreams.SplitPayment(ctx, p, upstreamAvailable); err != nil {
+			return fmt.Errorf("split payment: %w", err)
+		}
+	}
+	return nil
+}
+
+// upstreamAvailable は Webhook イベントの入金のうち上流プロジェクトへ分配できる額を返す。
+// 連結アカウントでの決済（event.Account あり）はプラットフォームに入ったアプリケーション手数料だけを分配できる。
+func upstreamAvailable(event pkgstripe.WebhookEvent, amount int) int {
+	if event.Account == "" {
+		return amount
+	}
+	return event.Data.Object.ApplicationFeeAmount
+}
+
 // recordDonationActivity は寄付確定時に activity を記録する（失敗しても無視）
 func (s *StripeServiceImpl) recordDonationActivity(ctx context.Context, projectID, donorID string, amount int, message string) {
 	if s.activityRecorder == nil {
@@ -311,6 +811,20 @@ func (s *StripeServiceImpl) recordDonationActivity(ctx context.Context, projectI
 	})
 }
 
+// recordAdjustmentActivity は返金・係争を activity に記録する（失敗しても無視）。
+// 寄付者名は公開フィードに出さないため actor は設定しない。
+func (s *StripeServiceImpl) recordAdjustmentActivity(ctx context.Context, activityType, projectID string, amount int, message string) {
+	if s.activityRecorder == nil {
+		return
+	}
+	_ = s.activityRecorder.Insert(ctx, &model.ActivityItem{
+		Type:      activityType,
+		ProjectID: projectID,
+		Amount:    &amount,
+		Message:   message,
+	})
+}
+
 // notifyMilestone は寄付確定時にマイルストーンチェックを実行する（失敗しても無視）
 func (s *StripeServiceImpl) notifyMilestone(ctx context.Context, projectID string) {
 	if s.milestoneNotifier == nil {
@@ -327,7 +841,8 @@ func (s *StripeServiceImpl) handleSubscriptionDeleted(ctx context.Context, event
 	return s.donationRepo.DeleteByStripeSubscriptionID(ctx, subscriptionID)
 }
 
-// handleInvoicePaymentSucceeded はサブスクの請求成功時に next_billing_message をアクティビティに記録してクリアする (#19)
+// handleInvoicePaymentSucceeded はサブスクの請求成功時に入金を台帳に記録し、
+// next_billing_message をアクティビティに記録してクリアする (#19)
 func (s *StripeServiceImpl) handleInvoicePaymentSucceeded(ctx context.Context, event pkgstripe.WebhookEvent) error {
 	obj := event.Data.Object
 	subscriptionID := obj.Subscription
@@ -337,9 +852,42 @@ func (s *StripeServiceImpl) handleInvoicePaymentSucceeded(ctx context.Context, e
 
 	d, err := s.donationRepo.GetByStripeSubscriptionID(ctx, subscriptionID)
 	if err != nil || d == nil {
+		if s.paymentLedger != nil {
+			// subscription.created より先に届いた場合など。失敗扱いにして Stripe の再送で記録する
+			return fmt.Errorf("stripe webhook: donation not found for subscription %s", subscriptionID)
+		}
 		return nil // donation not found, skip silently
 	}
 
+	// 請求ごとの入金を台帳に記録する（0 円請求は記録しない）。
+	// 寄付者が手数料を負担している場合、台帳の金額は上乗せ分を除いた額
+	if s.paymentLedger != nil && obj.AmountPaid > 0 {
+		currency := obj.Currency
+		if currency == "" {
+			currency = d.Currency
+		}
+		if err := s.recordPayment(ctx, &model.DonationPayment{
+			DonationID:      d.ID,
+			ProjectID:       d.ProjectID,
+			Amount:          max(obj.AmountPaid-d.CoveredFee(), 0),
+			ChargedAmount:   obj.AmountPaid,
+			Currency:        currency,
+			StripePaymentID: obj.PaymentIntent,
+			StripeInvoiceID: obj.ID,
+			IntervalMonths:  model.IntervalMonths(d.Interval),
+		}, upstreamAvailable(event, obj.AmountPaid)); err != nil {
+			return err
+		}
+		s.notifyMilestone(ctx, d.ProjectID)
+	}
+
+	// 支払い方法の更新などで請求が成功したら決済状態を戻す
+	if d.PaymentStatus == model.PaymentStatusPastDue || d.PaymentStatus == model.PaymentStatusUnpaid {
+		if err := s.setPaymentStatus(ctx, d, model.PaymentStatusActive); err != nil {
+			return err
+		}
+	}
+
 	if d.NextBillingMessage == "" {
 		return nil // no message to record
 	}
@@ -353,3 +901,343 @@ func (s *StripeServiceImpl) handleInvoicePaymentSucceeded(ctx context.Context, e
 
 	return nil
 }
+
+// paymentForCharge は charge / dispute の payment_intent から入金台帳の行を引く。
+// 台帳未設定、または該当する入金がない場合は nil を返す。
+func (s *StripeServiceImpl) paymentForCharge(ctx context.Context, eventType, paymentIntentID string) (*model.DonationPayment, error) {
+	if s.paymentLedger == nil {
+		return nil, nil
+	}
+	if paymentIntentID == "" {
+		slog.Info("stripe webhook: charge without payment_intent skipped", "type", eventType)
+		return nil, nil
+	}
+	p, err := s.paymentLedger.GetByStripePaymentID(ctx, paymentIntentID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && p == nil) {
+		slog.Info("stripe webhook: payment not found for charge", "type", eventType, "payment_intent", paymentIntentID)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find payment by payment intent: %w", err)
+	}
+	return p, nil
+}
+
+// syncDonationAdjustments は入金の返金・係争状態を寄付レコードに反映する（表示用、失敗してもログのみ）
+func (s *StripeServiceImpl) syncDonationAdjustments(ctx context.Context, p *model.DonationPayment) {
+	if p.DonationID == "" {
+		return
+	}
+	if err := s.donationRepo.SyncAdjustments(ctx, p.DonationID); err != nil {
+		slog.Error("stripe webhook: sync donation adjustments failed", "donation_id", p.DonationID, "error", err)
+	}
+}
+
+// releaseMatching は返金・敗訴で減った入金に合わせてマッチングキャンペーンの上乗せを減らし、上限に戻す。
+// 失敗した場合はエラーを返して Stripe の再送で再実行する（減らし済みの分は変わらない）。
+func (s *StripeServiceImpl) releaseMatching(ctx context.Context, p *model.DonationPayment) error {
+	if s.matcher == nil {
+		return nil
+	}
+	if err := s.matcher.ReleasePayment(ctx, p); err != nil {
+		return fmt.Errorf("release matching: %w", err)
+	}
+	return nil
+}
+
+// handleChargeRefunded は全額・一部返金を入金台帳に反映し、集計とマッチングの上乗せから除外する。
+// amount_refunded は累計値なので、再送・再実行されても結果は変わらない。
+func (s *StripeServiceImpl) handleChargeRefunded(ctx context.Context, event pkgstripe.WebhookEvent) error {
+	obj := event.Data.Object
+	p, err := s.paymentForCharge(ctx, event.Type, obj.PaymentIntent)
+	if err != nil || p == nil {
+		return err
+	}
+	// amount_refunded は寄付者が負担した手数料を含む請求額ベースなので、台帳と同じく寄付額で頭打ちにして比べる
+	refunded := min(obj.AmountRefunded, p.Amount)
+	if delta := refunded - p.RefundedAmount; delta > 0 {
+		if err := s.paymentLedger.RecordRefund(ctx, p.ID, refunded); err != nil {
+			return fmt.Errorf("record refund: %w", err)
+		}
+		s.syncDonationAdjustments(ctx, p)
+		s.recordAdjustmentActivity(ctx, "refund", p.ProjectID, delta, "")
+		p.RefundedAmount = refunded
+	}
+	return s.releaseMatching(ctx, p)
+}
+
+// handleChargeDispute は係争（チャージバック）の開始・終了を入金台帳に反映する。
+// 係争中と敗訴（lost）の金額は集計から除外され、勝訴（won）で元に戻る。
+// マッチングの上乗せは勝訴の可能性がある係争中には減らさず、敗訴で確定してから減らす。
+func (s *StripeServiceImpl) handleChargeDispute(ctx context.Context, event pkgstripe.WebhookEvent) error {
+	obj := event.Data.Object
+	if obj.Status == "" {
+		return fmt.Errorf("stripe webhook: %s missing dispute status", event.Type)
+	}
+	p, err := s.paymentForCharge(ctx, event.Type, obj.PaymentIntent)
+	if err != nil || p == nil {
+		return err
+	}
+	if p.DisputeStatus != obj.Status {
+		if err := s.paymentLedger.RecordDispute(ctx, p.ID, obj.Status, obj.Amount); err != nil {
+			return fmt.Errorf("record dispute: %w", err)
+		}
+		s.syncDonationAdjustments(ctx, p)
+		s.recordAdjustmentActivity(ctx, "dispute", p.ProjectID, obj.Amount, obj.Status)
+		p.DisputeStatus, p.DisputeAmount = obj.Status, min(obj.Amount, p.Amount)
+	}
+	if p.DisputeStatus != "lost" {
+		return nil
+	}
+	return s.releaseMatching(ctx, p)
+}
+
+// handleInvoicePaymentFailed は定期寄付の請求失敗（カード期限切れなど）を past_due として記録する。
+// Stripe が再試行を続けている間は past_due、再試行が尽きると subscription.updated で unpaid になる。
+func (s *StripeServiceImpl) handleInvoicePaymentFailed(ctx context.Context, event pkgstripe.WebhookEvent) error {
+	subscriptionID := event.Data.Object.Subscription
+	if subscriptionID == "" {
+		return nil // one-time invoice, skip
+	}
+	d, err := s.donationRepo.GetByStripeSubscriptionID(ctx, subscriptionID)
+	if err != nil || d == nil {
+		slog.Info("stripe webhook: donation not found for failed invoice", "subscription_id", subscriptionID)
+		return nil
+	}
+	if d.PaymentStatus == model.PaymentStatusUnpaid {
+		return nil // unpaid は past_due より進んだ状態なので戻さない
+	}
+	return s.setPaymentStatus(ctx, d, model.PaymentStatusPastDue)
+}
+
+// handleSubscriptionUpdated はサブスクリプションの変更を寄付に反映する。寄付者の PATCH 以外の変更
+// （Stripe ダッシュボード、Customer Portal、pause_collection の期限切れやプラン変更など Stripe 自身による変更）も
+// ここで請求額・一時停止・解約予定・決済状態に反映し、変わった項目を変更履歴に記録する。
+// customer.subscription.paused / resumed も data.object は同じ subscription なのでここで扱う。
+func (s *StripeServiceImpl) handleSubscriptionUpdated(ctx context.Context, event pkgstripe.WebhookEvent) error {
+	obj := event.Data.Object
+	var status string
+	switch obj.Status {
+	case "active", "trialing", "paused":
+		status = model.PaymentStatusActive // paused は支払い方法なしで無料期間が終わった状態（一時停止として扱う）
+	case "past_due":
+		status = model.PaymentStatusPastDue
+	case "unpaid":
+		status = model.PaymentStatusUnpaid
+	default:
+		return nil // canceled / incomplete などは他のイベントで扱う
+	}
+	d, err := s.donationRepo.GetByStripeSubscriptionID(ctx, obj.ID)
+	if err != nil || d == nil {
+		slog.Info("stripe webhook: donation not found for subscription update", "subscription_id", obj.ID)
+		return nil
+	}
+	patch, changes := subscriptionPatch(d, obj, status)
+	if len(changes) == 0 {
+		return nil // 寄付者の PATCH で反映済み、または関係のない変更
+	}
+	if err := s.recordChange(ctx, d, event, changes); err != nil {
+		return err
+	}
+	if err := s.donationRepo.Patch(ctx, d.ID, patch); err != nil {
+		return fmt.Errorf("update donation from subscription: %w", err)
+	}
+	return nil
+}
+
+// subscriptionPatch は subscription の請求額・一時停止・解約予定と決済状態のうち寄付と異なるものの更新と、
+// その変更内容（履歴用）を返す
+func subscriptionPatch(d *model.Donation, obj pkgstripe.WebhookEventObject, status string) (model.DonationPatch, map[string]model.DonationFieldChange) {
+	var patch model.DonationPatch
+	changes := map[string]model.DonationFieldChange{}
+	if charged := obj.SubscriptionCharge(); charged > 0 && charged != d.Charge() {
+		patch = chargedAmountPatch(d, charged)
+		if *patch.Amount != d.Amount {
+			changes["amount"] = model.DonationFieldChange{From: d.Amount, To: *patch.Amount}
+		}
+		if d.CoveredFee() > 0 {
+			changes["charged_amount"] = model.DonationFieldChange{From: d.Charge(), To: charged}
+		}
+	}
+	if paused := obj.PauseCollection != nil || obj.Status == "paused"; paused != d.Paused {
+		patch.Paused = &paused
+		changes["paused"] = model.DonationFieldChange{From: d.Paused, To: paused}
+	}
+	var cancelAt time.Time // ゼロ値は解約予定の取り消し
+	if obj.CancelAt > 0 {
+		cancelAt = time.Unix(obj.CancelAt, 0).UTC()
+	}
+	if (d.CancelAt == nil) != cancelAt.IsZero() || (d.CancelAt != nil && !d.CancelAt.Equal(cancelAt)) {
+		patch.CancelAt = &cancelAt
+		var from, to any
+		if d.CancelAt != nil {
+			from = *d.CancelAt
+		}
+		if !cancelAt.IsZero() {
+			to = cancelAt
+		}
+		changes["cancel_at"] = model.DonationFieldChange{From: from, To: to}
+	}
+	if status != d.PaymentStatus {
+		patch.PaymentStatus = &status
+		changes["payment_status"] = model.DonationFieldChange{From: d.PaymentStatus, To: status}
+	}
+	return patch, changes
+}
+
+// recordChange は Webhook で反映する寄付の変更を履歴に記録する。寄付の更新より先に記録し、
+// 更新に失敗して Stripe が再送しても同じイベントは二重に記録しない。
+func (s *StripeServiceImpl) recordChange(ctx context.Context, d *model.Donation, event pkgstripe.WebhookEvent, changes map[string]model.DonationFieldChange) error {
+	if s.changeLog == nil {
+		return nil
+	}
+	source := model.DonationChangeSourceStripe
+	if event.Automatic() {
+		source = model.DonationChangeSourceStripeAutomatic
+	}
+	err := s.changeLog.Create(ctx, &model.DonationChange{
+		DonationID:    d.ID,
+		Source:        source,
+		EventType:     event.Type,
+		StripeEventID: event.ID,
+		Changes:       changes,
+	})
+	if err != nil && !errors.Is(err, repository.ErrDuplicate) {
+		return fmt.Errorf("record donation change: %w", err)
+	}
+	return nil
+}
+
+// handleCheckoutSessionCompleted は Checkout Session を完了にする。寄付の記録自体は
+// payment_intent.succeeded / customer.subscription.created で行う（順不同で届く）。
+// コンビニ・銀行振込は完了時点では未入金なので、入金待ちの寄付として先に記録する。
+// 記録のない Session（機能追加前に作成されたものなど）は無視する。
+func (s *StripeServiceImpl) handleCheckoutSessionCompleted(ctx context.Context, event pkgstripe.WebhookEvent) error {
+	obj := event.Data.Object
+	if obj.Mode == "payment" && obj.PaymentStatus == "unpaid" {
+		if err := s.createPendingDonation(ctx, obj); err != nil {
+			return err
+		}
+	}
+	if s.checkoutSessions == nil {
+		return nil
+	}
+	err := s.checkoutSessions.MarkCompleted(ctx, obj.ID, obj.PaymentIntent, obj.Subscription)
+	if errors.Is(err, repository.ErrNotFound) {
+		slog.Info("stripe webhook: unknown checkout session", "session_id", obj.ID)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("mark checkout session completed: %w", err)
+	}
+	return nil
+}
+
+// createPendingDonation はコンビニ・銀行振込の入金待ちの寄付を記録する。
+// 入金されるまで台帳には記録しないため、集計には含まれない。
+// payment_intent.succeeded が先に届いて寄付が作成済みの場合は何もしない。
+func (s *StripeServiceImpl) createPendingDonation(ctx context.Context, obj pkgstripe.WebhookEventObject) error {
+	if obj.Metadata["project_id"] == "" || obj.PaymentIntent == "" {
+		slog.Info("stripe webhook: unpaid checkout session without donation metadata", "session_id", obj.ID)
+		return nil
+	}
+	d := oneTimeDonation(obj.Metadata, obj.AmountTotal, obj.Currency, obj.PaymentIntent)
+	d.PaymentStatus = model.PaymentStatusPending
+	if err := s.donationRepo.Create(ctx, d); err != nil && !errors.Is(err, repository.ErrDuplicate) {
+		return fmt.Errorf("create pending donation: %w", err)
+	}
+	return nil
+}
+
+// handleAsyncPaymentResult はコンビニ・銀行振込の入金（または期限切れ）を入金待ちの寄付に反映する。
+// 入金の記録は payment_intent.succeeded で行う。
+func (s *StripeServiceImpl) handleAsyncPaymentResult(ctx context.Context, event pkgstripe.WebhookEvent, status string) error {
+	obj := event.Data.Object
+	if obj.PaymentIntent == "" {
+		return nil
+	}
+	d, err := s.donationRepo.GetByStripePaymentID(ctx, obj.PaymentIntent)
+	if errors.Is(err, repository.ErrNotFound) {
+		slog.Info("stripe webhook: no donation for async payment", "session_id", obj.ID, "payment_intent", obj.PaymentIntent)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("find donation by payment intent: %w", err)
+	}
+	if d.PaymentStatus != model.PaymentStatusPending {
+		return nil
+	}
+	return s.setPaymentStatus(ctx, d, status)
+}
+
+// handleCheckoutSessionExpired は支払われずに期限切れになった Checkout Session を記録する（放棄）
+func (s *StripeServiceImpl) handleCheckoutSessionExpired(ctx context.Context, event pkgstripe.WebhookEvent) error {
+	if s.checkoutSessions == nil {
+		return nil
+	}
+	obj := event.Data.Object
+	err := s.checkoutSessions.MarkExpired(ctx, obj.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		slog.Info("stripe webhook: no open checkout session to expire", "session_id", obj.ID)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("mark checkout session expired: %w", err)
+	}
+	return nil
+}
+
+// handleAccountUpdated は連結アカウントの決済・入金可否と要件をプロジェクトに反映する
+func (s *StripeServiceImpl) handleAccountUpdated(ctx context.Context, event pkgstripe.WebhookEvent) error {
+	accountID := event.Account
+	if accountID == "" {
+		accountID = event.Data.Object.ID
+	}
+	return s.syncAccountStatus(ctx, accountID, event.Data.Object.AccountStatus())
+}
+
+// handleV2AccountEvent は v2 の thin イベントを受けて連結アカウントの状態を取得し直し、プロジェクトに反映する
+func (s *StripeServiceImpl) handleV2AccountEvent(ctx context.Context, event pkgstripe.WebhookEvent) error {
+	if event.RelatedObject == nil || event.RelatedObject.ID == "" {
+		return nil
+	}
+	accountID := event.RelatedObject.ID
+	st, err := s.client.GetAccountStatus(ctx, accountID)
+	if err != nil {
+		return fmt.Errorf("stripe get account status: %w", err)
+	}
+	return s.syncAccountStatus(ctx, accountID, st)
+}
+
+// syncAccountStatus は連結アカウントの状態を保存し、決済・入金ができなくなったプロジェクトを凍結する
+func (s *StripeServiceImpl) syncAccountStatus(ctx context.Context, accountID string, st pkgstripe.AccountStatus) error {
+	if accountID == "" {
+		return nil
+	}
+	changed, err := s.projectRepo.SyncStripeAccountStatus(ctx, accountID, model.StripeAccountStatus{
+		ChargesEnabled: st.ChargesEnabled,
+		PayoutsEnabled: st.PayoutsEnabled,
+		Requirements:   st.Requirements,
+		DisabledReason: st.DisabledReason,
+	})
+	if err != nil {
+		return fmt.Errorf("sync stripe account status: %w", err)
+	}
+	for projectID, status := range changed {
+		slog.Warn("stripe webhook: project status changed by account capability",
+			"project_id", projectID, "account_id", accountID, "status", status,
+			"charges_enabled", st.ChargesEnabled, "payouts_enabled", st.PayoutsEnabled, "disabled_reason", st.DisabledReason)
+	}
+	return nil
+}
+
+// setPaymentStatus は決済状態が変わる場合のみ寄付を更新する
+func (s *StripeServiceImpl) setPaymentStatus(ctx context.Context, d *model.Donation, status string) error {
+	if d.PaymentStatus == status {
+		return nil
+	}
+	if err := s.donationRepo.Patch(ctx, d.ID, model.DonationPatch{PaymentStatus: &status}); err != nil {
+		return fmt.Errorf("update payment status: %w", err)
+	}
+	return nil
+}
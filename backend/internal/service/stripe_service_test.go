@@ -3,7 +3,10 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/givers/backend/internal/model"
 	"github.com/givers/backend/internal/repository"
@@ -18,9 +21,11 @@ type mockStripeClient struct {
 	createConnectedAccountFunc func(ctx context.Context, params pkgstripe.CreateAccountParams) (string, error)
 	createAccountLinkFunc      func(ctx context.Context, accountID, returnURL, refreshURL string) (string, error)
 	getAccountOnboardedFunc    func(ctx context.Context, accountID string) (bool, error)
-	createCheckoutSessionFunc  func(ctx context.Context, params pkgstripe.CheckoutParams) (string, error)
+	getAccountStatusFunc       func(ctx context.Context, accountID string) (pkgstripe.AccountStatus, error)
+	createCheckoutSessionFunc  func(ctx context.Context, params pkgstripe.CheckoutParams) (pkgstripe.CheckoutSession, error)
 	verifyWebhookSignatureFunc func(payload []byte, sigHeader string) error
 	parseWebhookEventFunc      func(payload []byte) (pkgstripe.WebhookEvent, error)
+	createCustomerFunc         func(ctx context.Context, params pkgstripe.CustomerParams) (string, error)
 }
 
 func (m *mockStripeClient) CreateConnectedAccount(ctx context.Context, params pkgstripe.CreateAccountParams) (string, error) {
@@ -41,11 +46,17 @@ func (m *mockStripeClient) GetAccountOnboarded(ctx context.Context, accountID st
 	}
 	return true, nil
 }
-func (m *mockStripeClient) CreateCheckoutSession(ctx context.Context, params pkgstripe.CheckoutParams) (string, error) {
+func (m *mockStripeClient) GetAccountStatus(ctx context.Context, accountID string) (pkgstripe.AccountStatus, error) {
+	if m.getAccountStatusFunc != nil {
+		return m.getAccountStatusFunc(ctx, accountID)
+	}
+	return pkgstripe.AccountStatus{ChargesEnabled: true, PayoutsEnabled: true}, nil
+}
+func (m *mockStripeClient) CreateCheckoutSession(ctx context.Context, params pkgstripe.CheckoutParams) (pkgstripe.CheckoutSession, error) {
 	if m.createCheckoutSessionFunc != nil {
 		return m.createCheckoutSessionFunc(ctx, params)
 	}
-	return "", nil
+	return pkgstripe.CheckoutSession{}, nil
 }
 func (m *mockStripeClient) VerifyWebhookSignature(payload []byte, sigHeader string) error {
 	if m.verifyWebhookSignatureFunc != nil {
@@ -59,12 +70,45 @@ func (m *mockStripeClient) ParseWebhookEvent(payload []byte) (pkgstripe.WebhookE
 	}
 	return pkgstripe.WebhookEvent{}, nil
 }
-func (m *mockStripeClient) PauseSubscription(_ context.Context, _ string) error  { return nil }
-func (m *mockStripeClient) ResumeSubscription(_ context.Context, _ string) error { return nil }
-func (m *mockStripeClient) CancelSubscription(_ context.Context, _ string) error { return nil }
-func (m *mockStripeClient) UpdateSubscriptionAmount(_ context.Context, _ string, _ int) error {
+func (m *mockStripeClient) PauseSubscription(_ context.Context, _, _ string) error  { return nil }
+func (m *mockStripeClient) ResumeSubscription(_ context.Context, _, _ string) error { return nil }
+func (m *mockStripeClient) CancelSubscription(_ context.Context, _, _ string) error { return nil }
+func (m *mockStripeClient) UpdateSubscriptionAmount(_ context.Context, _, _ string, _ int) error {
 	return nil
 }
+func (m *mockStripeClient) CreatePaymentMethodUpdateSession(_ context.Context, _, _, _ string) (string, error) {
+	return "", nil
+}
+func (m *mockStripeClient) CreateCustomer(ctx context.Context, params pkgstripe.CustomerParams) (string, error) {
+	if m.createCustomerFunc != nil {
+		return m.createCustomerFunc(ctx, params)
+	}
+	return "cus_mock", nil
+}
+func (m *mockStripeClient) CreateBillingPortalSession(_ context.Context, _, customerID, _ string) (string, error) {
+	return "https://billing.stripe.com/p/session/" + customerID, nil
+}
+func (m *mockStripeClient) ListSubscriptions(_ context.Context, _ string) ([]pkgstripe.Subscription, error) {
+	return nil, nil
+}
+func (m *mockStripeClient) GetSubscription(_ context.Context, _, _ string) (pkgstripe.Subscription, error) {
+	return pkgstripe.Subscription{}, nil
+}
+func (m *mockStripeClient) ListPaymentIntents(_ context.Context, _ string, _ time.Time) ([]pkgstripe.PaymentIntent, error) {
+	return nil, nil
+}
+func (m *mockStripeClient) ListConnectedAccounts(_ context.Context) ([]pkgstripe.ConnectedAccount, error) {
+	return nil, nil
+}
+func (m *mockStripeClient) ListBalanceTransactions(_ context.Context, _ string, _ time.Time) ([]pkgstripe.BalanceTransaction, error) {
+	return nil, nil
+}
+func (m *mockStripeClient) ListPayouts(_ context.Context, _ string, _ time.Time) ([]pkgstripe.Payout, error) {
+	return nil, nil
+}
+func (m *mockStripeClient) CreateTransfer(_ context.Context, params pkgstripe.TransferParams) (string, error) {
+	return "tr_mock", nil
+}
 
 // ---------------------------------------------------------------------------
 // Tests: CreateAccountAndOnboarding
@@ -235,14 +279,14 @@ func TestStripeService_CreateCheckout_Success(t *testing.T) {
 	ctx := context.Background()
 
 	stripeClient := &mockStripeClient{
-		createCheckoutSessionFunc: func(_ context.Context, params pkgstripe.CheckoutParams) (string, error) {
+		createCheckoutSessionFunc: func(_ context.Context, params pkgstripe.CheckoutParams) (pkgstripe.CheckoutSession, error) {
 			if params.StripeAccountID != "acct_owner" {
 				t.Errorf("expected StripeAccountID=acct_owner, got %q", params.StripeAccountID)
 			}
 			if params.Amount != 1000 {
 				t.Errorf("expected Amount=1000, got %d", params.Amount)
 			}
-			return "https://checkout.stripe.com/test", nil
+			return pkgstripe.CheckoutSession{ID: "cs_test", URL: "https://checkout.stripe.com/test"}, nil
 		},
 	}
 	projectRepo := &mockStripeProjectRepo{
@@ -275,9 +319,9 @@ func TestStripeService_CreateCheckout_PlatformDirect(t *testing.T) {
 	var capturedParams pkgstripe.CheckoutParams
 
 	stripeClient := &mockStripeClient{
-		createCheckoutSessionFunc: func(_ context.Context, params pkgstripe.CheckoutParams) (string, error) {
+		createCheckoutSessionFunc: func(_ context.Context, params pkgstripe.CheckoutParams) (pkgstripe.CheckoutSession, error) {
 			capturedParams = params
-			return "https://checkout.stripe.com/platform", nil
+			return pkgstripe.CheckoutSession{ID: "cs_test", URL: "https://checkout.stripe.com/platform"}, nil
 		},
 	}
 	projectRepo := &mockStripeProjectRepo{
@@ -312,6 +356,144 @@ func TestStripeService_CreateCheckout_AmountTooLow(t *testing.T) {
 	}
 }
 
+func TestStripeService_CreateCheckout_FrozenProject(t *testing.T) {
+	for _, status := range []string{"frozen", "deleted"} {
+		stripeClient := &mockStripeClient{
+			createCheckoutSessionFunc: func(_ context.Context, _ pkgstripe.CheckoutParams) (pkgstripe.CheckoutSession, error) {
+				t.Errorf("%s: checkout session must not be created", status)
+				return pkgstripe.CheckoutSession{}, nil
+			},
+		}
+		projectRepo := &mockStripeProjectRepo{
+			getStatusFunc: func(_ context.Context, _ string) (string, error) { return status, nil },
+		}
+		svc := newTestStripeServiceWithRepo(stripeClient, projectRepo)
+
+		_, err := svc.CreateCheckout(context.Background(), CheckoutRequest{ProjectID: "proj-1", Amount: 1000})
+		if !errors.Is(err, ErrProjectNotAcceptingDonations) {
+			t.Errorf("%s: expected ErrProjectNotAcceptingDonations, got %v", status, err)
+		}
+	}
+}
+
+func TestStripeService_CreateCheckout_Interval(t *testing.T) {
+	tests := []struct {
+		interval  string
+		wantUnit  string
+		wantCount int
+	}{
+		{"", "month", 1},
+		{model.IntervalMonth, "month", 1},
+		{model.IntervalQuarter, "month", 3},
+		{model.IntervalHalfYear, "month", 6},
+		{model.IntervalYear, "year", 1},
+	}
+	for _, tt := range tests {
+		var captured pkgstripe.CheckoutParams
+		stripeClient := &mockStripeClient{
+			createCheckoutSessionFunc: func(_ context.Context, params pkgstripe.CheckoutParams) (pkgstripe.CheckoutSession, error) {
+				captured = params
+				return pkgstripe.CheckoutSession{ID: "cs_test", URL: "https://checkout.stripe.com/test"}, nil
+			},
+		}
+		svc := newTestStripeServiceWithRepo(stripeClient, &mockStripeProjectRepo{})
+
+		_, err := svc.CreateCheckout(context.Background(), CheckoutRequest{
+			ProjectID: "proj-1", Amount: 12000, IsRecurring: true, Interval: tt.interval,
+		})
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.interval, err)
+		}
+		if captured.Interval != tt.wantUnit || captured.IntervalCount != tt.wantCount {
+			t.Errorf("%q: expected %s x%d, got %s x%d", tt.interval, tt.wantUnit, tt.wantCount, captured.Interval, captured.IntervalCount)
+		}
+	}
+}
+
+func TestStripeService_CreateCheckout_InvalidInterval(t *testing.T) {
+	stripeClient := &mockStripeClient{
+		createCheckoutSessionFunc: func(_ context.Context, _ pkgstripe.CheckoutParams) (pkgstripe.CheckoutSession, error) {
+			t.Error("checkout session must not be created")
+			return pkgstripe.CheckoutSession{}, nil
+		},
+	}
+	svc := newTestStripeServiceWithRepo(stripeClient, &mockStripeProjectRepo{})
+
+	for _, req := range []CheckoutRequest{
+		{ProjectID: "proj-1", Amount: 1000, IsRecurring: true, Interval: "week"},
+		{ProjectID: "proj-1", Amount: 1000, IsRecurring: false, Interval: model.IntervalYear}, // 単発寄付に請求間隔はない
+	} {
+		if _, err := svc.CreateCheckout(context.Background(), req); !errors.Is(err, ErrInvalidInterval) {
+			t.Errorf("%+v: expected ErrInvalidInterval, got %v", req, err)
+		}
+	}
+}
+func TestStripeService_CreateCheckout_Tier(t *testing.T) {
+	var captured pkgstripe.CheckoutParams
+	stripeClient := &mockStripeClient{
+		createCheckoutSessionFunc: func(_ context.Context, params pkgstripe.CheckoutParams) (pkgstripe.CheckoutSession, error) {
+			captured = params
+			return pkgstripe.CheckoutSession{ID: "cs_test", URL: "https://checkout.stripe.com/test"}, nil
+		},
+	}
+	projectRepo := &mockStripeProjectRepo{tiers: []model.DonationTier{
+		{ID: "t-once", Label: "応援", Amount: 500},
+		{ID: "t-year", Label: "年間サポーター", Amount: 12000, IsRecurring: true, Interval: model.IntervalYear},
+	}}
+	svc := newTestStripeServiceWithRepo(stripeClient, projectRepo)
+
+	// クライアントが送った金額・種別は目安額の設定で上書きされる
+	_, err := svc.CreateCheckout(context.Background(), CheckoutRequest{
+		ProjectID: "proj-1", Amount: 1, TierID: "t-year", DonorType: "user", DonorID: "user-1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if captured.Amount != 12000 || !captured.IsRecurring || captured.Interval != "year" || captured.IntervalCount != 1 {
+		t.Errorf("expected the tier's recurring yearly amount, got %+v", captured)
+	}
+	if captured.TierID != "t-year" {
+		t.Errorf("expected TierID=t-year, got %q", captured.TierID)
+	}
+
+	_, err = svc.CreateCheckout(context.Background(), CheckoutRequest{
+		ProjectID: "proj-1", Amount: 99999, IsRecurring: true, TierID: "t-once", DonorType: "token", DonorID: "tok",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if captured.Amount != 500 || captured.IsRecurring || captured.TierID != "t-once" {
+		t.Errorf("expected the tier's one-time amount, got %+v", captured)
+	}
+}
+
+func TestStripeService_CreateCheckout_TierErrors(t *testing.T) {
+	stripeClient := &mockStripeClient{
+		createCheckoutSessionFunc: func(_ context.Context, _ pkgstripe.CheckoutParams) (pkgstripe.CheckoutSession, error) {
+			t.Error("checkout session must not be created")
+			return pkgstripe.CheckoutSession{}, nil
+		},
+	}
+	projectRepo := &mockStripeProjectRepo{tiers: []model.DonationTier{
+		{ID: "t-month", Label: "月額サポーター", Amount: 1000, IsRecurring: true},
+	}}
+	svc := newTestStripeServiceWithRepo(stripeClient, projectRepo)
+
+	tests := []struct {
+		req  CheckoutRequest
+		want error
+	}{
+		{CheckoutRequest{ProjectID: "proj-1", TierID: "missing", DonorType: "user", DonorID: "user-1"}, ErrInvalidTier},
+		{CheckoutRequest{ProjectID: "proj-1", TierID: "t-month", DonorType: "token", DonorID: "tok"}, ErrRecurringRequiresLogin},
+	}
+	for _, tt := range tests {
+		if _, err := svc.CreateCheckout(context.Background(), tt.req); !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.req.TierID, tt.want, err)
+		}
+	}
+}
+
+
 // ---------------------------------------------------------------------------
 // Tests: ProcessWebhook
 // ---------------------------------------------------------------------------
@@ -439,10 +621,7 @@ func TestStripeService_ProcessWebhook_SubscriptionCreated_Idempotent(t *testing.
 			"donor_type": "user",
 			"donor_id":   "user-1",
 		},
-		Plan: &struct {
-			Amount   int    `json:"amount"`
-			Currency string `json:"currency"`
-		}{Amount: 2000, Currency: "jpy"},
+		Plan: &pkgstripe.SubscriptionPlan{Amount: 2000, Currency: "jpy"},
 	}
 	event := pkgstripe.WebhookEvent{Type: "customer.subscription.created", ID: "evt_dup_sub"}
 	event.Data.Object = obj
@@ -500,10 +679,7 @@ func TestStripeService_ProcessWebhook_SubscriptionCreated_CreatesDonation(t *tes
 			"message":     "毎月応援します",
 			"is_recurring": "true",
 		},
-		Plan: &struct {
-			Amount   int    `json:"amount"`
-			Currency string `json:"currency"`
-		}{Amount: 2000, Currency: "jpy"},
+		Plan: &pkgstripe.SubscriptionPlan{Amount: 2000, Currency: "jpy"},
 	}
 	event := pkgstripe.WebhookEvent{Type: "customer.subscription.created", ID: "evt_sub"}
 	event.Data.Object = obj
@@ -540,6 +716,62 @@ func TestStripeService_ProcessWebhook_SubscriptionCreated_CreatesDonation(t *tes
 	}
 }
 
+func TestStripeService_ProcessWebhook_SubscriptionCreated_StoresInterval(t *testing.T) {
+	obj := pkgstripe.WebhookEventObject{
+		ID:       "sub_year",
+		Metadata: map[string]string{"project_id": "proj-2", "donor_type": "user", "donor_id": "user-2"},
+		Plan:     &pkgstripe.SubscriptionPlan{Amount: 12000, Currency: "jpy", Interval: "year", IntervalCount: 1},
+	}
+	event := pkgstripe.WebhookEvent{Type: "customer.subscription.created", ID: "evt_sub_year"}
+	event.Data.Object = obj
+
+	var created *model.Donation
+	donationRepo := &mockStripeDonationRepo{
+		createFunc: func(_ context.Context, d *model.Donation) error {
+			created = d
+			return nil
+		},
+	}
+	svc := newTestStripeServiceFull(webhookTestClient(event), &mockStripeProjectRepo{}, donationRepo)
+
+	if err := svc.ProcessWebhook(context.Background(), []byte(`{}`), "valid-sig"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created == nil || created.Interval != model.IntervalYear || created.Amount != 12000 {
+		t.Errorf("unexpected donation: %+v", created)
+	}
+}
+func TestStripeService_ProcessWebhook_StoresTierID(t *testing.T) {
+	for _, eventType := range []string{"payment_intent.succeeded", "customer.subscription.created"} {
+		obj := pkgstripe.WebhookEventObject{
+			ID:       "obj_tier",
+			Amount:   3000,
+			Currency: "jpy",
+			Metadata: map[string]string{"project_id": "proj-1", "donor_type": "user", "donor_id": "user-1", "tier_id": "t-1"},
+			Plan:     &pkgstripe.SubscriptionPlan{Amount: 3000, Currency: "jpy", Interval: "month", IntervalCount: 1},
+		}
+		event := pkgstripe.WebhookEvent{Type: eventType, ID: "evt_" + eventType}
+		event.Data.Object = obj
+
+		var created *model.Donation
+		donationRepo := &mockStripeDonationRepo{
+			createFunc: func(_ context.Context, d *model.Donation) error {
+				created = d
+				return nil
+			},
+		}
+		svc := newTestStripeServiceFull(webhookTestClient(event), &mockStripeProjectRepo{}, donationRepo)
+
+		if err := svc.ProcessWebhook(context.Background(), []byte(`{}`), "valid-sig"); err != nil {
+			t.Fatalf("%s: unexpected error: %v", eventType, err)
+		}
+		if created == nil || created.TierID != "t-1" {
+			t.Errorf("%s: expected TierID=t-1, got %+v", eventType, created)
+		}
+	}
+}
+
+
 func TestStripeService_ProcessWebhook_SubscriptionDeleted_DeletesDonation(t *testing.T) {
 	ctx := context.Background()
 	var deletedSubID string
@@ -636,10 +868,7 @@ func TestStripeService_ProcessWebhook_SubscriptionCreated_RecordsActivity(t *tes
 			"donor_type": "token",
 			"donor_id":   "tok-abc",
 		},
-		Plan: &struct {
-			Amount   int    `json:"amount"`
-			Currency string `json:"currency"`
-		}{Amount: 2000, Currency: "jpy"},
+		Plan: &pkgstripe.SubscriptionPlan{Amount: 2000, Currency: "jpy"},
 	}
 	event := pkgstripe.WebhookEvent{Type: "customer.subscription.created", ID: "evt_sub_act"}
 	event.Data.Object = obj
@@ -731,6 +960,10 @@ type mockStripeProjectRepo struct {
 	getByIDFunc             func(ctx context.Context, id string) (string, error) // returns stripeAccountID
 	saveStripeAccountIDFunc func(ctx context.Context, projectID, stripeAccountID string) error
 	activateProjectFunc     func(ctx context.Context, projectID string) error
+	getStatusFunc           func(ctx context.Context, projectID string) (string, error)
+	syncAccountStatusFunc   func(ctx context.Context, accountID string, st model.StripeAccountStatus) (map[string]string, error)
+	tiers                   []model.DonationTier
+	paymentMethodTypes      []string
 }
 
 func (m *mockStripeProjectRepo) GetStripeAccountID(ctx context.Context, id string) (string, error) {
@@ -751,12 +984,32 @@ func (m *mockStripeProjectRepo) ActivateProject(ctx context.Context, projectID s
 	}
 	return nil
 }
+func (m *mockStripeProjectRepo) GetStatus(ctx context.Context, projectID string) (string, error) {
+	if m.getStatusFunc != nil {
+		return m.getStatusFunc(ctx, projectID)
+	}
+	return "active", nil
+}
+func (m *mockStripeProjectRepo) GetDonationTiers(_ context.Context, _ string) ([]model.DonationTier, error) {
+	return m.tiers, nil
+}
+func (m *mockStripeProjectRepo) GetPaymentMethodTypes(_ context.Context, _ string) ([]string, error) {
+	return m.paymentMethodTypes, nil
+}
+func (m *mockStripeProjectRepo) SyncStripeAccountStatus(ctx context.Context, accountID string, st model.StripeAccountStatus) (map[string]string, error) {
+	if m.syncAccountStatusFunc != nil {
+		return m.syncAccountStatusFunc(ctx, accountID, st)
+	}
+	return nil, nil
+}
 
 type mockStripeDonationRepo struct {
 	createFunc                    func(ctx context.Context, d *model.Donation) error
 	deleteBySubscriptionIDFunc    func(ctx context.Context, subscriptionID string) error
 	getByStripeSubscriptionIDFunc func(ctx context.Context, subscriptionID string) (*model.Donation, error)
 	patchFunc                     func(ctx context.Context, id string, patch model.DonationPatch) error
+	getByStripePaymentIDFunc      func(ctx context.Context, paymentID string) (*model.Donation, error)
+	syncAdjustmentsFunc           func(ctx context.Context, id string) error
 }
 
 func (m *mockStripeDonationRepo) Create(ctx context.Context, d *model.Donation) error {
@@ -787,6 +1040,93 @@ func (m *mockStripeDonationRepo) Patch(ctx context.Context, id string, patch mod
 	return nil
 }
 
+func (m *mockStripeDonationRepo) GetByStripePaymentID(ctx context.Context, paymentID string) (*model.Donation, error) {
+	if m.getByStripePaymentIDFunc != nil {
+		return m.getByStripePaymentIDFunc(ctx, paymentID)
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *mockStripeDonationRepo) SyncAdjustments(ctx context.Context, id string) error {
+	if m.syncAdjustmentsFunc != nil {
+		return m.syncAdjustmentsFunc(ctx, id)
+	}
+	return nil
+}
+
+// mockStripePaymentLedger は StripePaymentLedger のインメモリ実装（テスト用）
+type mockStripePaymentLedger struct {
+	payments  []*model.DonationPayment
+	createErr error
+	getErr    error
+}
+
+func (m *mockStripePaymentLedger) Create(_ context.Context, p *model.DonationPayment) error {
+	if m.createErr != nil {
+		return m.createErr
+	}
+	for _, existing := range m.payments {
+		if (p.StripePaymentID != "" && existing.StripePaymentID == p.StripePaymentID) ||
+			(p.StripeInvoiceID != "" && existing.StripeInvoiceID == p.StripeInvoiceID) {
+			return repository.ErrDuplicate
+		}
+	}
+	p.ID = fmt.Sprintf("pay-%d", len(m.payments)+1)
+	m.payments = append(m.payments, p)
+	return nil
+}
+
+func (m *mockStripePaymentLedger) GetByStripePaymentID(_ context.Context, paymentID string) (*model.DonationPayment, error) {
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+	for _, p := range m.payments {
+		if p.StripePaymentID == paymentID {
+			cp := *p
+			return &cp, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *mockStripePaymentLedger) find(id string) *model.DonationPayment {
+	for _, p := range m.payments {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func (m *mockStripePaymentLedger) RecordRefund(_ context.Context, id string, refundedAmount int) error {
+	p := m.find(id)
+	if p == nil {
+		return repository.ErrNotFound
+	}
+	p.RefundedAmount = min(refundedAmount, p.Amount) // pg 実装と同じく入金額で頭打ち
+	return nil
+}
+
+func (m *mockStripePaymentLedger) RecordDispute(_ context.Context, id string, status string, amount int) error {
+	p := m.find(id)
+	if p == nil {
+		return repository.ErrNotFound
+	}
+	p.DisputeStatus, p.DisputeAmount = status, amount
+	return nil
+}
+
+// RecordStripeBalance は FinancePaymentLedger の実装（フローテストで手数料の同期に使う）
+func (m *mockStripePaymentLedger) RecordStripeBalance(_ context.Context, paymentID, _ string, fee, net int) error {
+	for _, p := range m.payments {
+		if p.StripePaymentID == paymentID {
+			p.StripeFee, p.StripeNet = &fee, &net
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
 func newTestStripeService(client pkgstripe.Client) StripeService {
 	return NewStripeService(client, &mockStripeProjectRepo{}, &mockStripeDonationRepo{}, "https://example.com")
 }
@@ -951,3 +1291,1520 @@ func TestStripeService_ProcessWebhook_InvoicePaymentSucceeded_NoSubscription_Ski
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+// ---------------------------------------------------------------------------
+// Tests: webhook event persistence / dedup / replay
+// ---------------------------------------------------------------------------
+
+// mockWebhookEventStore は StripeWebhookEventStore のインメモリ実装（テスト用）
+type mockWebhookEventStore struct {
+	events map[string]*model.WebhookEvent
+}
+
+func newMockWebhookEventStore() *mockWebhookEventStore {
+	return &mockWebhookEventStore{events: make(map[string]*model.WebhookEvent)}
+}
+
+func (m *mockWebhookEventStore) Begin(_ context.Context, id, eventType string, payload []byte) (bool, error) {
+	e, ok := m.events[id]
+	if !ok {
+		m.events[id] = &model.WebhookEvent{ID: id, Type: eventType, Payload: payload, Status: "processing", Attempts: 1}
+		return true, nil
+	}
+	if e.Status != "failed" {
+		return false, nil
+	}
+	e.Status = "processing"
+	e.Attempts++
+	return true, nil
+}
+func (m *mockWebhookEventStore) BeginReplay(_ context.Context, id string) (*model.WebhookEvent, error) {
+	e, ok := m.events[id]
+	if !ok || e.Status != "failed" {
+		return nil, repository.ErrNotFound
+	}
+	e.Status = "processing"
+	e.Attempts++
+	return e, nil
+}
+func (m *mockWebhookEventStore) MarkProcessed(_ context.Context, id string) error {
+	m.events[id].Status = "processed"
+	m.events[id].LastError = ""
+	return nil
+}
+func (m *mockWebhookEventStore) MarkFailed(_ context.Context, id, lastError string) error {
+	m.events[id].Status = "failed"
+	m.events[id].LastError = lastError
+	return nil
+}
+func (m *mockWebhookEventStore) ListByStatus(_ context.Context, status string, _, _ int) ([]*model.WebhookEvent, error) {
+	var list []*model.WebhookEvent
+	for _, e := range m.events {
+		if e.Status == status {
+			list = append(list, e)
+		}
+	}
+	return list, nil
+}
+
+func paymentIntentEvent(eventID, paymentID string) pkgstripe.WebhookEvent {
+	event := pkgstripe.WebhookEvent{Type: "payment_intent.succeeded", ID: eventID}
+	event.Data.Object = pkgstripe.WebhookEventObject{
+		ID:       paymentID,
+		Amount:   1000,
+		Currency: "jpy",
+		Metadata: map[string]string{"project_id": "proj-1", "donor_type": "user", "donor_id": "user-1"},
+	}
+	return event
+}
+
+func TestStripeService_ProcessWebhook_EventStore_SkipsDuplicateDelivery(t *testing.T) {
+	ctx := context.Background()
+	event := paymentIntentEvent("evt_once", "pi_once")
+	createCount := 0
+
+	stripeClient := &mockStripeClient{
+		parseWebhookEventFunc: func(_ []byte) (pkgstripe.WebhookEvent, error) { return event, nil },
+	}
+	donationRepo := &mockStripeDonationRepo{
+		createFunc: func(_ context.Context, _ *model.Donation) error {
+			createCount++
+			return nil
+		},
+	}
+	store := newMockWebhookEventStore()
+	svc := NewStripeService(stripeClient, &mockStripeProjectRepo{}, donationRepo, "https://example.com", WithWebhookEventStore(store))
+
+	for i := 0; i < 2; i++ {
+		if err := svc.ProcessWebhook(ctx, []byte(`{"id":"evt_once"}`), "valid-sig"); err != nil {
+			t.Fatalf("delivery %d: unexpected error: %v", i+1, err)
+		}
+	}
+	if createCount != 1 {
+		t.Errorf("expected handler to run once, ran %d times", createCount)
+	}
+	if got := store.events["evt_once"]; got.Status != "processed" || got.Attempts != 1 {
+		t.Errorf("expected processed with 1 attempt, got status=%q attempts=%d", got.Status, got.Attempts)
+	}
+	if string(store.events["evt_once"].Payload) != `{"id":"evt_once"}` {
+		t.Errorf("expected raw payload to be stored, got %q", string(store.events["evt_once"].Payload))
+	}
+}
+
+func TestStripeService_ProcessWebhook_EventStore_RecordsFailureAndRetries(t *testing.T) {
+	ctx := context.Background()
+	event := paymentIntentEvent("evt_fail", "pi_fail")
+	fail := true
+
+	stripeClient := &mockStripeClient{
+		parseWebhookEventFunc: func(_ []byte) (pkgstripe.WebhookEvent, error) { return event, nil },
+	}
+	donationRepo := &mockStripeDonationRepo{
+		createFunc: func(_ context.Context, _ *model.Donation) error {
+			if fail {
+				return errors.New("db down")
+			}
+			return nil
+		},
+	}
+	store := newMockWebhookEventStore()
+	svc := NewStripeService(stripeClient, &mockStripeProjectRepo{}, donationRepo, "https://example.com", WithWebhookEventStore(store))
+
+	if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err == nil {
+		t.Fatal("expected error from failing handler")
+	}
+	if got := store.events["evt_fail"]; got.Status != "failed" || got.LastError != "db down" {
+		t.Fatalf("expected failed with last_error, got status=%q last_error=%q", got.Status, got.LastError)
+	}
+
+	// Stripe の再配信では失敗イベントを再処理する
+	fail = false
+	if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err != nil {
+		t.Fatalf("unexpected error on redelivery: %v", err)
+	}
+	if got := store.events["evt_fail"]; got.Status != "processed" || got.Attempts != 2 {
+		t.Errorf("expected processed after 2 attempts, got status=%q attempts=%d", got.Status, got.Attempts)
+	}
+}
+
+func TestStripeService_ReplayWebhookEvent_RerunsFailedEvent(t *testing.T) {
+	ctx := context.Background()
+	event := paymentIntentEvent("evt_replay", "pi_replay")
+	var parsedPayload []byte
+	var created *model.Donation
+
+	stripeClient := &mockStripeClient{
+		parseWebhookEventFunc: func(payload []byte) (pkgstripe.WebhookEvent, error) {
+			parsedPayload = payload
+			return event, nil
+		},
+	}
+	donationRepo := &mockStripeDonationRepo{
+		createFunc: func(_ context.Context, d *model.Donation) error {
+			created = d
+			return nil
+		},
+	}
+	store := newMockWebhookEventStore()
+	store.events["evt_replay"] = &model.WebhookEvent{
+		ID: "evt_replay", Type: "payment_intent.succeeded", Payload: []byte(`{"stored":true}`),
+		Status: "failed", Attempts: 3, LastError: "db down",
+	}
+	svc := NewStripeService(stripeClient, &mockStripeProjectRepo{}, donationRepo, "https://example.com", WithWebhookEventStore(store))
+
+	if err := svc.ReplayWebhookEvent(ctx, "evt_replay"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(parsedPayload) != `{"stored":true}` {
+		t.Errorf("expected stored payload to be parsed, got %q", string(parsedPayload))
+	}
+	if created == nil || created.StripePaymentID != "pi_replay" {
+		t.Fatal("expected donation to be created by replay")
+	}
+	if got := store.events["evt_replay"]; got.Status != "processed" || got.Attempts != 4 {
+		t.Errorf("expected processed after 4 attempts, got status=%q attempts=%d", got.Status, got.Attempts)
+	}
+}
+
+func TestStripeService_ReplayWebhookEvent_ProcessedEventNotReplayable(t *testing.T) {
+	ctx := context.Background()
+	store := newMockWebhookEventStore()
+	store.events["evt_done"] = &model.WebhookEvent{ID: "evt_done", Status: "processed", Attempts: 1}
+	svc := NewStripeService(&mockStripeClient{}, &mockStripeProjectRepo{}, &mockStripeDonationRepo{}, "https://example.com", WithWebhookEventStore(store))
+
+	if err := svc.ReplayWebhookEvent(ctx, "evt_done"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for processed event, got %v", err)
+	}
+}
+
+func TestStripeService_ReplayWebhookEvent_NoStore(t *testing.T) {
+	svc := newTestStripeService(&mockStripeClient{})
+	if err := svc.ReplayWebhookEvent(context.Background(), "evt_1"); !errors.Is(err, ErrWebhookEventsNotConfigured) {
+		t.Errorf("expected ErrWebhookEventsNotConfigured, got %v", err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Tests: payments ledger
+// ---------------------------------------------------------------------------
+
+func webhookTestClient(event pkgstripe.WebhookEvent) *mockStripeClient {
+	return &mockStripeClient{
+		verifyWebhookSignatureFunc: func(_ []byte, _ string) error { return nil },
+		parseWebhookEventFunc:      func(_ []byte) (pkgstripe.WebhookEvent, error) { return event, nil },
+	}
+}
+
+func newTestStripeServiceWithLedger(event pkgstripe.WebhookEvent, donationRepo StripeDonationRepo, ledger StripePaymentLedger, recorder StripeActivityRecorder, notifier StripeMilestoneNotifier) StripeService {
+	return NewStripeServiceWithActivity(webhookTestClient(event), &mockStripeProjectRepo{}, donationRepo, "https://example.com", recorder, notifier,
+		WithPaymentLedger(ledger),
+	)
+}
+
+type countingMilestoneNotifier struct{ calls int }
+
+func (n *countingMilestoneNotifier) NotifyDonation(_ context.Context, _ string) error {
+	n.calls++
+	return nil
+}
+
+func TestStripeService_ProcessWebhook_PaymentIntentSucceeded_RecordsPayment(t *testing.T) {
+	ctx := context.Background()
+	event := paymentIntentEvent("evt_pi_ledger", "pi_ledger")
+	donationRepo := &mockStripeDonationRepo{
+		createFunc: func(_ context.Context, d *model.Donation) error {
+			d.ID = "don-1"
+			return nil
+		},
+	}
+	ledger := &mockStripePaymentLedger{}
+	svc := newTestStripeServiceWithLedger(event, donationRepo, ledger, nil, nil)
+
+	if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ledger.payments) != 1 {
+		t.Fatalf("expected 1 payment, got %d", len(ledger.payments))
+	}
+	p := ledger.payments[0]
+	if p.DonationID != "don-1" || p.StripePaymentID != "pi_ledger" || p.Amount != event.Data.Object.Amount {
+		t.Errorf("unexpected payment: %+v", p)
+	}
+}
+
+func TestStripeService_ProcessWebhook_PaymentIntentSucceeded_DuplicateLinksExistingDonation(t *testing.T) {
+	ctx := context.Background()
+	event := paymentIntentEvent("evt_pi_dup", "pi_dup")
+	donationRepo := &mockStripeDonationRepo{
+		createFunc: func(_ context.Context, _ *model.Donation) error {
+			return repository.ErrDuplicate
+		},
+		getByStripePaymentIDFunc: func(_ context.Context, paymentID string) (*model.Donation, error) {
+			return &model.Donation{ID: "don-existing", StripePaymentID: paymentID}, nil
+		},
+	}
+	ledger := &mockStripePaymentLedger{}
+	svc := newTestStripeServiceWithLedger(event, donationRepo, ledger, nil, nil)
+
+	// 2 回届いても入金は 1 件
+	for i := 0; i < 2; i++ {
+		if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(ledger.payments) != 1 || ledger.payments[0].DonationID != "don-existing" {
+		t.Errorf("expected one payment linked to don-existing, got %+v", ledger.payments)
+	}
+}
+
+func TestStripeService_ProcessWebhook_PaymentIntentSucceeded_LedgerError(t *testing.T) {
+	ctx := context.Background()
+	event := paymentIntentEvent("evt_pi_err", "pi_err")
+	ledger := &mockStripePaymentLedger{createErr: errors.New("db error")}
+	svc := newTestStripeServiceWithLedger(event, &mockStripeDonationRepo{}, ledger, nil, nil)
+
+	if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err == nil {
+		t.Fatal("expected ledger error to fail the event, got nil")
+	}
+}
+
+func invoiceEvent(eventID, invoiceID, subscriptionID string, amountPaid int) pkgstripe.WebhookEvent {
+	event := pkgstripe.WebhookEvent{Type: "invoice.payment_succeeded", ID: eventID}
+	event.Data.Object = pkgstripe.WebhookEventObject{
+		ID:            invoiceID,
+		Currency:      "jpy",
+		Subscription:  subscriptionID,
+		AmountPaid:    amountPaid,
+		PaymentIntent: "pi_" + invoiceID,
+	}
+	return event
+}
+
+func TestStripeService_ProcessWebhook_InvoicePaymentSucceeded_RecordsEachCharge(t *testing.T) {
+	ctx := context.Background()
+	donationRepo := &mockStripeDonationRepo{
+		getByStripeSubscriptionIDFunc: func(_ context.Context, subID string) (*model.Donation, error) {
+			return &model.Donation{ID: "don-sub", ProjectID: "proj-1", Amount: 1000, Currency: "jpy", IsRecurring: true, StripeSubscriptionID: subID}, nil
+		},
+	}
+	ledger := &mockStripePaymentLedger{}
+	notifier := &countingMilestoneNotifier{}
+
+	for _, ev := range []pkgstripe.WebhookEvent{
+		invoiceEvent("evt_inv_1", "in_1", "sub_1", 1000),
+		invoiceEvent("evt_inv_2", "in_2", "sub_1", 1200),
+		invoiceEvent("evt_inv_2_retry", "in_2", "sub_1", 1200), // 同じ請求の再送
+	} {
+		svc := newTestStripeServiceWithLedger(ev, donationRepo, ledger, nil, notifier)
+		if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err != nil {
+			t.Fatalf("%s: unexpected error: %v", ev.ID, err)
+		}
+	}
+
+	if len(ledger.payments) != 2 {
+		t.Fatalf("expected 2 payments, got %d", len(ledger.payments))
+	}
+	second := ledger.payments[1]
+	if second.DonationID != "don-sub" || second.Amount != 1200 || second.StripeInvoiceID != "in_2" || second.StripePaymentID != "pi_in_2" {
+		t.Errorf("unexpected second payment: %+v", second)
+	}
+	if notifier.calls == 0 {
+		t.Error("expected milestone check after a recurring charge")
+	}
+}
+
+func TestStripeService_ProcessWebhook_InvoicePaymentSucceeded_RecordsIntervalMonths(t *testing.T) {
+	donationRepo := &mockStripeDonationRepo{
+		getByStripeSubscriptionIDFunc: func(_ context.Context, subID string) (*model.Donation, error) {
+			return &model.Donation{ID: "don-q", ProjectID: "proj-1", Amount: 3000, Currency: "jpy", IsRecurring: true,
+				Interval: model.IntervalQuarter, StripeSubscriptionID: subID}, nil
+		},
+	}
+	ledger := &mockStripePaymentLedger{}
+	svc := newTestStripeServiceWithLedger(invoiceEvent("evt_inv_q", "in_q", "sub_q", 3000), donationRepo, ledger, nil, nil)
+
+	if err := svc.ProcessWebhook(context.Background(), []byte(`{}`), "valid-sig"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ledger.payments) != 1 || ledger.payments[0].IntervalMonths != 3 {
+		t.Errorf("expected a payment covering 3 months, got %+v", ledger.payments)
+	}
+}
+
+func TestStripeService_ProcessWebhook_InvoicePaymentSucceeded_ZeroAmountNotRecorded(t *testing.T) {
+	ctx := context.Background()
+	donationRepo := &mockStripeDonationRepo{
+		getByStripeSubscriptionIDFunc: func(_ context.Context, _ string) (*model.Donation, error) {
+			return &model.Donation{ID: "don-sub", ProjectID: "proj-1"}, nil
+		},
+	}
+	ledger := &mockStripePaymentLedger{}
+	svc := newTestStripeServiceWithLedger(invoiceEvent("evt_inv_0", "in_0", "sub_1", 0), donationRepo, ledger, nil, nil)
+
+	if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ledger.payments) != 0 {
+		t.Errorf("expected no payment for a zero-amount invoice, got %d", len(ledger.payments))
+	}
+}
+
+func TestStripeService_ProcessWebhook_InvoicePaymentSucceeded_UnknownSubscriptionRetries(t *testing.T) {
+	ctx := context.Background()
+	donationRepo := &mockStripeDonationRepo{
+		getByStripeSubscriptionIDFunc: func(_ context.Context, _ string) (*model.Donation, error) {
+			return nil, errors.New("no rows")
+		},
+	}
+	svc := newTestStripeServiceWithLedger(invoiceEvent("evt_inv_early", "in_early", "sub_new", 1000), donationRepo, &mockStripePaymentLedger{}, nil, nil)
+
+	// subscription.created より先に届いた請求は失敗させて再送を待つ
+	if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err == nil {
+		t.Fatal("expected error for invoice of unknown subscription, got nil")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Tests: refunds / disputes
+// ---------------------------------------------------------------------------
+
+func chargeEvent(eventType, eventID string, obj pkgstripe.WebhookEventObject) pkgstripe.WebhookEvent {
+	event := pkgstripe.WebhookEvent{Type: eventType, ID: eventID}
+	event.Data.Object = obj
+	return event
+}
+
+func ledgerWithPayment(p model.DonationPayment) *mockStripePaymentLedger {
+	p.ID = "pay-1"
+	return &mockStripePaymentLedger{payments: []*model.DonationPayment{&p}}
+}
+
+func TestStripeService_ProcessWebhook_ChargeRefunded_Partial(t *testing.T) {
+	ctx := context.Background()
+	event := chargeEvent("charge.refunded", "evt_refund",
+		pkgstripe.WebhookEventObject{ID: "ch_1", PaymentIntent: "pi_1", Amount: 3000, AmountRefunded: 1000})
+	ledger := ledgerWithPayment(model.DonationPayment{DonationID: "don-1", ProjectID: "proj-1", Amount: 3000, StripePaymentID: "pi_1"})
+
+	var syncedID string
+	donationRepo := &mockStripeDonationRepo{
+		syncAdjustmentsFunc: func(_ context.Context, id string) error {
+			syncedID = id
+			return nil
+		},
+	}
+	var activity *model.ActivityItem
+	recorder := &mockStripeActivityRecorder{
+		insertFunc: func(_ context.Context, a *model.ActivityItem) error {
+			activity = a
+			return nil
+		},
+	}
+	svc := newTestStripeServiceWithLedger(event, donationRepo, ledger, recorder, nil)
+
+	if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := ledger.payments[0]; got.RefundedAmount != 1000 || got.NetAmount() != 2000 {
+		t.Errorf("expected refunded=1000 net=2000, got refunded=%d net=%d", got.RefundedAmount, got.NetAmount())
+	}
+	if syncedID != "don-1" {
+		t.Errorf("expected donation don-1 to be synced, got %q", syncedID)
+	}
+	if activity == nil || activity.Type != "refund" || activity.Amount == nil || *activity.Amount != 1000 {
+		t.Errorf("expected refund activity of 1000, got %+v", activity)
+	}
+	if activity != nil && activity.ActorName != nil {
+		t.Error("refund activity should not expose the donor")
+	}
+}
+
+func TestStripeService_ProcessWebhook_ChargeRefunded_RecordsOnlyDelta(t *testing.T) {
+	ctx := context.Background()
+	event := chargeEvent("charge.refunded", "evt_refund_full",
+		pkgstripe.WebhookEventObject{ID: "ch_1", PaymentIntent: "pi_1", Amount: 3000, AmountRefunded: 3000})
+	ledger := ledgerWithPayment(model.DonationPayment{ProjectID: "proj-1", Amount: 3000, RefundedAmount: 1000, StripePaymentID: "pi_1"})
+
+	var amounts []int
+	recorder := &mockStripeActivityRecorder{
+		insertFunc: func(_ context.Context, a *model.ActivityItem) error {
+			amounts = append(amounts, *a.Amount)
+			return nil
+		},
+	}
+	svc := newTestStripeServiceWithLedger(event, &mockStripeDonationRepo{}, ledger, recorder, nil)
+
+	// 2 回目（再送）は何もしない
+	for i := 0; i < 2; i++ {
+		if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(amounts) != 1 || amounts[0] != 2000 {
+		t.Errorf("expected one refund activity of 2000, got %v", amounts)
+	}
+	if got := ledger.payments[0].NetAmount(); got != 0 {
+		t.Errorf("expected net 0 after full refund, got %d", got)
+	}
+}
+
+func TestStripeService_ProcessWebhook_ChargeRefunded_CoveredFeeFullRefundRedelivered(t *testing.T) {
+	ctx := context.Background()
+	// cover_fees の寄付: 寄付額 3000、寄付者への請求額 3113 を全額返金
+	event := chargeEvent("charge.refunded", "evt_refund_fee",
+		pkgstripe.WebhookEventObject{ID: "ch_1", PaymentIntent: "pi_1", Amount: 3113, AmountRefunded: 3113})
+	ledger := ledgerWithPayment(model.DonationPayment{ProjectID: "proj-1", Amount: 3000, ChargedAmount: 3113, StripePaymentID: "pi_1"})
+
+	var amounts []int
+	recorder := &mockStripeActivityRecorder{
+		insertFunc: func(_ context.Context, a *model.ActivityItem) error {
+			amounts = append(amounts, *a.Amount)
+			return nil
+		},
+	}
+	svc := newTestStripeServiceWithLedger(event, &mockStripeDonationRepo{}, ledger, recorder, nil)
+
+	// 再送で負担分の差額を返金として数え直さない
+	for i := 0; i < 2; i++ {
+		if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(amounts) != 1 || amounts[0] != 3000 {
+		t.Errorf("expected one refund activity of 3000, got %v", amounts)
+	}
+	if got := ledger.payments[0]; got.RefundedAmount != 3000 || got.NetAmount() != 0 {
+		t.Errorf("expected refunded=3000 net=0, got refunded=%d net=%d", got.RefundedAmount, got.NetAmount())
+	}
+}
+
+func TestStripeService_ProcessWebhook_ChargeRefunded_UnknownPaymentSkipped(t *testing.T) {
+	ctx := context.Background()
+	event := chargeEvent("charge.refunded", "evt_refund_unknown",
+		pkgstripe.WebhookEventObject{ID: "ch_x", PaymentIntent: "pi_unknown", AmountRefunded: 500})
+	svc := newTestStripeServiceWithLedger(event, &mockStripeDonationRepo{}, &mockStripePaymentLedger{}, nil, nil)
+
+	if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err != nil {
+		t.Fatalf("expected unknown payment to be skipped, got: %v", err)
+	}
+}
+
+func TestStripeService_ProcessWebhook_ChargeRefunded_LedgerError(t *testing.T) {
+	ctx := context.Background()
+	event := chargeEvent("charge.refunded", "evt_refund_err",
+		pkgstripe.WebhookEventObject{ID: "ch_1", PaymentIntent: "pi_1", AmountRefunded: 500})
+	svc := newTestStripeServiceWithLedger(event, &mockStripeDonationRepo{}, &mockStripePaymentLedger{getErr: errors.New("db error")}, nil, nil)
+
+	if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err == nil {
+		t.Fatal("expected error so the event can be retried, got nil")
+	}
+}
+
+func TestStripeService_ProcessWebhook_ChargeRefunded_RecurringCharge(t *testing.T) {
+	ctx := context.Background()
+	// 定期寄付の 2 回目の請求（invoice の PaymentIntent）への返金
+	ledger := &mockStripePaymentLedger{payments: []*model.DonationPayment{
+		{ID: "pay-1", DonationID: "don-sub", ProjectID: "proj-1", Amount: 1000, StripeInvoiceID: "in_1", StripePaymentID: "pi_in_1"},
+		{ID: "pay-2", DonationID: "don-sub", ProjectID: "proj-1", Amount: 1000, StripeInvoiceID: "in_2", StripePaymentID: "pi_in_2"},
+	}}
+	event := chargeEvent("charge.refunded", "evt_refund_sub",
+		pkgstripe.WebhookEventObject{ID: "ch_2", PaymentIntent: "pi_in_2", Amount: 1000, AmountRefunded: 1000})
+	svc := newTestStripeServiceWithLedger(event, &mockStripeDonationRepo{}, ledger, nil, nil)
+
+	if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ledger.payments[0].RefundedAmount != 0 || ledger.payments[1].RefundedAmount != 1000 {
+		t.Errorf("expected only the second charge refunded, got %d / %d",
+			ledger.payments[0].RefundedAmount, ledger.payments[1].RefundedAmount)
+	}
+}
+
+func TestStripeService_ProcessWebhook_DisputeCreatedAndClosed(t *testing.T) {
+	ctx := context.Background()
+	ledger := ledgerWithPayment(model.DonationPayment{DonationID: "don-1", ProjectID: "proj-1", Amount: 5000, StripePaymentID: "pi_1"})
+	var activities []*model.ActivityItem
+	recorder := &mockStripeActivityRecorder{
+		insertFunc: func(_ context.Context, a *model.ActivityItem) error {
+			activities = append(activities, a)
+			return nil
+		},
+	}
+
+	for _, tc := range []struct {
+		eventType, status string
+		wantNet           int
+	}{
+		{"charge.dispute.created", "needs_response", 0},
+		{"charge.dispute.closed", "won", 5000},
+	} {
+		event := chargeEvent(tc.eventType, "evt_"+tc.status,
+			pkgstripe.WebhookEventObject{ID: "dp_1", PaymentIntent: "pi_1", Amount: 5000, Status: tc.status})
+		svc := newTestStripeServiceWithLedger(event, &mockStripeDonationRepo{}, ledger, recorder, nil)
+		if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.eventType, err)
+		}
+		got := ledger.payments[0]
+		if got.DisputeStatus != tc.status || got.NetAmount() != tc.wantNet {
+			t.Errorf("%s: expected status=%q net=%d, got status=%q net=%d",
+				tc.eventType, tc.status, tc.wantNet, got.DisputeStatus, got.NetAmount())
+		}
+	}
+
+	if len(activities) != 2 {
+		t.Fatalf("expected 2 dispute activities, got %d", len(activities))
+	}
+	if activities[0].Type != "dispute" || activities[1].Message != "won" {
+		t.Errorf("unexpected activities: %+v, %+v", activities[0], activities[1])
+	}
+}
+
+func TestStripeService_ProcessWebhook_DisputeMissingStatus(t *testing.T) {
+	ctx := context.Background()
+	event := chargeEvent("charge.dispute.created", "evt_dp_bad",
+		pkgstripe.WebhookEventObject{ID: "dp_1", PaymentIntent: "pi_1", Amount: 5000})
+	svc := newTestStripeServiceWithLedger(event, &mockStripeDonationRepo{}, &mockStripePaymentLedger{}, nil, nil)
+
+	if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err == nil {
+		t.Fatal("expected error for dispute without status, got nil")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Tests: dunning (payment_status)
+// ---------------------------------------------------------------------------
+
+func dunningDonationRepo(status string, patched *[]string) *mockStripeDonationRepo {
+	return &mockStripeDonationRepo{
+		getByStripeSubscriptionIDFunc: func(_ context.Context, subID string) (*model.Donation, error) {
+			return &model.Donation{ID: "don-sub", ProjectID: "proj-1", IsRecurring: true, StripeSubscriptionID: subID, PaymentStatus: status}, nil
+		},
+		patchFunc: func(_ context.Context, _ string, patch model.DonationPatch) error {
+			if patch.PaymentStatus != nil {
+				*patched = append(*patched, *patch.PaymentStatus)
+			}
+			return nil
+		},
+	}
+}
+
+func TestStripeService_ProcessWebhook_InvoicePaymentFailed_MarksPastDue(t *testing.T) {
+	var patched []string
+	event := chargeEvent("invoice.payment_failed", "evt_inv_failed",
+		pkgstripe.WebhookEventObject{ID: "in_f", Subscription: "sub_1"})
+	svc := newTestStripeServiceFull(webhookTestClient(event), &mockStripeProjectRepo{}, dunningDonationRepo(model.PaymentStatusActive, &patched))
+
+	if err := svc.ProcessWebhook(context.Background(), []byte(`{}`), "valid-sig"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(patched) != 1 || patched[0] != model.PaymentStatusPastDue {
+		t.Errorf("expected past_due, got %v", patched)
+	}
+}
+
+func TestStripeService_ProcessWebhook_InvoicePaymentFailed_KeepsUnpaid(t *testing.T) {
+	var patched []string
+	event := chargeEvent("invoice.payment_failed", "evt_inv_failed2",
+		pkgstripe.WebhookEventObject{ID: "in_f", Subscription: "sub_1"})
+	svc := newTestStripeServiceFull(webhookTestClient(event), &mockStripeProjectRepo{}, dunningDonationRepo(model.PaymentStatusUnpaid, &patched))
+
+	if err := svc.ProcessWebhook(context.Background(), []byte(`{}`), "valid-sig"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(patched) != 0 {
+		t.Errorf("expected unpaid to stay, got patches %v", patched)
+	}
+}
+
+func TestStripeService_ProcessWebhook_SubscriptionUpdated_Statuses(t *testing.T) {
+	tests := []struct {
+		stripeStatus, current string
+		want                  []string
+	}{
+		{"past_due", model.PaymentStatusActive, []string{model.PaymentStatusPastDue}},
+		{"unpaid", model.PaymentStatusPastDue, []string{model.PaymentStatusUnpaid}},
+		{"active", model.PaymentStatusUnpaid, []string{model.PaymentStatusActive}},
+		{"active", model.PaymentStatusActive, nil},
+		{"canceled", model.PaymentStatusPastDue, nil},
+	}
+	for _, tt := range tests {
+		var patched []string
+		event := chargeEvent("customer.subscription.updated", "evt_sub_upd",
+			pkgstripe.WebhookEventObject{ID: "sub_1", Status: tt.stripeStatus})
+		svc := newTestStripeServiceFull(webhookTestClient(event), &mockStripeProjectRepo{}, dunningDonationRepo(tt.current, &patched))
+
+		if err := svc.ProcessWebhook(context.Background(), []byte(`{}`), "valid-sig"); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.stripeStatus, err)
+		}
+		if len(patched) != len(tt.want) || (len(patched) == 1 && patched[0] != tt.want[0]) {
+			t.Errorf("%s from %s: expected %v, got %v", tt.stripeStatus, tt.current, tt.want, patched)
+		}
+	}
+}
+
+// memDonationChangeLog は StripeDonationChangeLog のインメモリ実装（同じイベントは ErrDuplicate）
+type memDonationChangeLog struct {
+	changes []*model.DonationChange
+}
+
+func (m *memDonationChangeLog) Create(_ context.Context, c *model.DonationChange) error {
+	for _, existing := range m.changes {
+		if c.StripeEventID != "" && existing.StripeEventID == c.StripeEventID {
+			return repository.ErrDuplicate
+		}
+	}
+	m.changes = append(m.changes, c)
+	return nil
+}
+
+func TestStripeService_ProcessWebhook_SubscriptionPausedAndResumed(t *testing.T) {
+	donation := &model.Donation{ID: "don-sub", IsRecurring: true, Amount: 1000, StripeSubscriptionID: "sub_1", PaymentStatus: model.PaymentStatusActive}
+	var patches []model.DonationPatch
+	donationRepo := &mockStripeDonationRepo{
+		getByStripeSubscriptionIDFunc: func(_ context.Context, _ string) (*model.Donation, error) {
+			copied := *donation
+			return &copied, nil
+		},
+		patchFunc: func(_ context.Context, _ string, patch model.DonationPatch) error {
+			patches = append(patches, patch)
+			if patch.Paused != nil {
+				donation.Paused = *patch.Paused
+			}
+			return nil
+		},
+	}
+	changeLog := &memDonationChangeLog{}
+	plan := &pkgstripe.SubscriptionPlan{Amount: 1000, Currency: "jpy", Interval: "month", IntervalCount: 1}
+
+	// 支払い方法なしで無料期間が終わり Stripe が一時停止 → resumed で再開
+	for _, tt := range []struct {
+		eventType, status string
+		paused            bool
+	}{
+		{"customer.subscription.paused", "paused", true},
+		{"customer.subscription.resumed", "active", false},
+	} {
+		event := chargeEvent(tt.eventType, "evt_"+tt.status, pkgstripe.WebhookEventObject{ID: "sub_1", Status: tt.status, Plan: plan})
+		svc := NewStripeService(webhookTestClient(event), &mockStripeProjectRepo{}, donationRepo, "https://example.com", WithChangeLog(changeLog))
+		if err := svc.ProcessWebhook(context.Background(), []byte(`{}`), "valid-sig"); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.eventType, err)
+		}
+		if donation.Paused != tt.paused {
+			t.Errorf("%s: paused = %v, want %v", tt.eventType, donation.Paused, tt.paused)
+		}
+	}
+	if len(patches) != 2 || patches[0].Amount != nil || patches[0].PaymentStatus != nil {
+		t.Errorf("expected only paused to change, got %+v", patches)
+	}
+	if len(changeLog.changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", changeLog.changes)
+	}
+	c := changeLog.changes[0]
+	if c.DonationID != "don-sub" || c.Source != model.DonationChangeSourceStripeAutomatic || c.EventType != "customer.subscription.paused" ||
+		c.StripeEventID != "evt_paused" || c.Changes["paused"] != (model.DonationFieldChange{From: false, To: true}) {
+		t.Errorf("unexpected change %+v", c)
+	}
+}
+
+func TestStripeService_ProcessWebhook_SubscriptionUpdated_RecordsChangeOnce(t *testing.T) {
+	event := chargeEvent("customer.subscription.updated", "evt_portal", pkgstripe.WebhookEventObject{
+		ID: "sub_1", Status: "past_due", CancelAt: time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		Plan: &pkgstripe.SubscriptionPlan{Amount: 2000, Currency: "jpy", Interval: "month", IntervalCount: 1},
+	})
+	event.Request = &pkgstripe.EventRequest{ID: "req_portal"}
+	patchErr := errors.New("db down")
+	var patched *model.DonationPatch
+	donationRepo := &mockStripeDonationRepo{
+		getByStripeSubscriptionIDFunc: func(_ context.Context, _ string) (*model.Donation, error) {
+			return &model.Donation{ID: "don-sub", IsRecurring: true, Amount: 1000, StripeSubscriptionID: "sub_1", PaymentStatus: model.PaymentStatusActive}, nil
+		},
+		patchFunc: func(_ context.Context, _ string, patch model.DonationPatch) error {
+			patched = &patch
+			return patchErr
+		},
+	}
+	changeLog := &memDonationChangeLog{}
+	svc := NewStripeService(webhookTestClient(event), &mockStripeProjectRepo{}, donationRepo, "https://example.com", WithChangeLog(changeLog))
+
+	// 更新に失敗したら Stripe の再送で再実行する。履歴は先に記録済みなので二重にならない
+	if err := svc.ProcessWebhook(context.Background(), []byte(`{}`), "valid-sig"); !errors.Is(err, patchErr) {
+		t.Fatalf("expected the patch error, got %v", err)
+	}
+	patchErr = nil
+	if err := svc.ProcessWebhook(context.Background(), []byte(`{}`), "valid-sig"); err != nil {
+		t.Fatalf("unexpected error on redelivery: %v", err)
+	}
+	if patched == nil || *patched.Amount != 2000 || *patched.PaymentStatus != model.PaymentStatusPastDue || patched.CancelAt == nil || patched.Paused != nil {
+		t.Errorf("unexpected patch %+v", patched)
+	}
+	if len(changeLog.changes) != 1 {
+		t.Fatalf("expected 1 change, got %d", len(changeLog.changes))
+	}
+	c := changeLog.changes[0]
+	if c.Source != model.DonationChangeSourceStripe || len(c.Changes) != 3 ||
+		c.Changes["amount"] != (model.DonationFieldChange{From: 1000, To: 2000}) ||
+		c.Changes["payment_status"] != (model.DonationFieldChange{From: model.PaymentStatusActive, To: model.PaymentStatusPastDue}) {
+		t.Errorf("unexpected change %+v", c)
+	}
+}
+
+func TestStripeService_ProcessWebhook_InvoicePaymentSucceeded_RecoversPastDue(t *testing.T) {
+	var patched []string
+	event := invoiceEvent("evt_inv_recover", "in_r", "sub_1", 1000)
+	svc := newTestStripeServiceFull(webhookTestClient(event), &mockStripeProjectRepo{}, dunningDonationRepo(model.PaymentStatusPastDue, &patched))
+
+	if err := svc.ProcessWebhook(context.Background(), []byte(`{}`), "valid-sig"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(patched) != 1 || patched[0] != model.PaymentStatusActive {
+		t.Errorf("expected active after successful charge, got %v", patched)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Tests: connected account capability (account.updated)
+// ---------------------------------------------------------------------------
+
+func TestStripeService_ProcessWebhook_AccountUpdated_SyncsStatus(t *testing.T) {
+	var gotAccountID string
+	var got model.StripeAccountStatus
+	projectRepo := &mockStripeProjectRepo{
+		syncAccountStatusFunc: func(_ context.Context, accountID string, st model.StripeAccountStatus) (map[string]string, error) {
+			gotAccountID, got = accountID, st
+			return map[string]string{"proj-1": "frozen"}, nil
+		},
+	}
+	event := chargeEvent("account.updated", "evt_acct", pkgstripe.WebhookEventObject{
+		ID:             "acct_1",
+		ChargesEnabled: false,
+		PayoutsEnabled: true,
+		Requirements: &pkgstripe.AccountRequirements{
+			PastDue:        []string{"individual.verification.document"},
+			DisabledReason: "requirements.past_due",
+		},
+	})
+	event.Account = "acct_1"
+	svc := newTestStripeServiceFull(webhookTestClient(event), projectRepo, &mockStripeDonationRepo{})
+
+	if err := svc.ProcessWebhook(context.Background(), []byte(`{}`), "valid-sig"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotAccountID != "acct_1" {
+		t.Errorf("accountID = %q, want acct_1", gotAccountID)
+	}
+	if got.ChargesEnabled || !got.PayoutsEnabled || got.DisabledReason != "requirements.past_due" ||
+		len(got.Requirements) != 1 || got.Requirements[0] != "individual.verification.document" {
+		t.Errorf("status = %+v", got)
+	}
+}
+
+func TestStripeService_ProcessWebhook_AccountUpdated_RepoError(t *testing.T) {
+	projectRepo := &mockStripeProjectRepo{
+		syncAccountStatusFunc: func(_ context.Context, _ string, _ model.StripeAccountStatus) (map[string]string, error) {
+			return nil, errors.New("db down")
+		},
+	}
+	event := chargeEvent("account.updated", "evt_acct_err", pkgstripe.WebhookEventObject{ID: "acct_1"})
+	svc := newTestStripeServiceFull(webhookTestClient(event), projectRepo, &mockStripeDonationRepo{})
+
+	if err := svc.ProcessWebhook(context.Background(), []byte(`{}`), "valid-sig"); err == nil {
+		t.Error("expected error so that Stripe retries the event")
+	}
+}
+
+func TestStripeService_ProcessWebhook_V2AccountEvent_FetchesStatus(t *testing.T) {
+	tests := []struct {
+		eventType string
+		wantSync  bool
+	}{
+		{"v2.core.account[requirements].updated", true},
+		{"v2.core.account[configuration.merchant].capability_status_updated", true},
+		{"v2.core.account.updated", true},
+		{"v2.core.account_link.returned", false},
+	}
+	for _, tt := range tests {
+		var fetched, synced string
+		client := webhookTestClient(pkgstripe.WebhookEvent{
+			Type:          tt.eventType,
+			ID:            "evt_v2",
+			RelatedObject: &pkgstripe.EventRelatedObject{ID: "acct_2", Type: "v2.core.account"},
+		})
+		client.getAccountStatusFunc = func(_ context.Context, accountID string) (pkgstripe.AccountStatus, error) {
+			fetched = accountID
+			return pkgstripe.AccountStatus{ChargesEnabled: true, PayoutsEnabled: false, Requirements: []string{"external_account"}}, nil
+		}
+		projectRepo := &mockStripeProjectRepo{
+			syncAccountStatusFunc: func(_ context.Context, accountID string, st model.StripeAccountStatus) (map[string]string, error) {
+				if st.PayoutsEnabled || len(st.Requirements) != 1 {
+					t.Errorf("%s: status = %+v", tt.eventType, st)
+				}
+				synced = accountID
+				return nil, nil
+			},
+		}
+		svc := newTestStripeServiceFull(client, projectRepo, &mockStripeDonationRepo{})
+
+		if err := svc.ProcessWebhook(context.Background(), []byte(`{}`), "valid-sig"); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.eventType, err)
+		}
+		if tt.wantSync && (fetched != "acct_2" || synced != "acct_2") {
+			t.Errorf("%s: fetched=%q synced=%q, want acct_2", tt.eventType, fetched, synced)
+		}
+		if !tt.wantSync && (fetched != "" || synced != "") {
+			t.Errorf("%s: should be ignored, fetched=%q synced=%q", tt.eventType, fetched, synced)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Tests: matching-gift campaigns
+// ---------------------------------------------------------------------------
+
+type mockStripeMatcher struct {
+	applied  []*model.DonationPayment
+	released []model.DonationPayment
+	err      error
+}
+
+func (m *mockStripeMatcher) ApplyPayment(_ context.Context, p *model.DonationPayment) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.applied = append(m.applied, p)
+	return nil
+}
+
+func (m *mockStripeMatcher) ReleasePayment(_ context.Context, p *model.DonationPayment) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.released = append(m.released, *p)
+	return nil
+}
+
+func TestStripeService_ProcessWebhook_PaymentIntentSucceeded_AppliesMatching(t *testing.T) {
+	ctx := context.Background()
+	event := paymentIntentEvent("evt_pi_match", "pi_match")
+	donationRepo := &mockStripeDonationRepo{
+		createFunc: func(_ context.Context, d *model.Donation) error {
+			d.ID = "don-1"
+			return nil
+		},
+		getByStripePaymentIDFunc: func(_ context.Context, paymentID string) (*model.Donation, error) {
+			return &model.Donation{ID: "don-1", StripePaymentID: paymentID}, nil
+		},
+	}
+	ledger := &mockStripePaymentLedger{}
+	matcher := &mockStripeMatcher{}
+	svc := NewStripeService(webhookTestClient(event), &mockStripeProjectRepo{}, donationRepo, "https://example.com",
+		WithPaymentLedger(ledger),
+		WithMatching(matcher),
+	)
+
+	// 再送でも記録済みの入金で上乗せをやり直す（重複はマッチング側でスキップされる）
+	for i := 0; i < 2; i++ {
+		if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(matcher.applied) != 2 {
+		t.Fatalf("expected matching to be applied on each delivery, got %d", len(matcher.applied))
+	}
+	for _, p := range matcher.applied {
+		if p.ID != "pay-1" || p.StripePaymentID != "pi_match" {
+			t.Errorf("expected the recorded payment pay-1, got %+v", p)
+		}
+	}
+}
+
+func TestStripeService_ProcessWebhook_PaymentIntentSucceeded_MatchingError(t *testing.T) {
+	ctx := context.Background()
+	event := paymentIntentEvent("evt_pi_match_err", "pi_match_err")
+	ledger := &mockStripePaymentLedger{}
+	matcher := &mockStripeMatcher{err: errors.New("db error")}
+	donationRepo := &mockStripeDonationRepo{
+		createFunc: func(_ context.Context, d *model.Donation) error {
+			d.ID = "don-1"
+			return nil
+		},
+	}
+	svc := NewStripeService(webhookTestClient(event), &mockStripeProjectRepo{}, donationRepo, "https://example.com",
+		WithPaymentLedger(ledger),
+		WithMatching(matcher),
+	)
+
+	// 上乗せに失敗したら Stripe に再送させる
+	if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err == nil {
+		t.Fatal("expected matching error to fail the event, got nil")
+	}
+	if len(ledger.payments) != 1 {
+		t.Errorf("expected the payment to be recorded before matching, got %d", len(ledger.payments))
+	}
+}
+
+func TestStripeService_ProcessWebhook_ChargeRefunded_ReleasesMatching(t *testing.T) {
+	ctx := context.Background()
+	event := chargeEvent("charge.refunded", "evt_refund_match",
+		pkgstripe.WebhookEventObject{ID: "ch_1", PaymentIntent: "pi_1", Amount: 3000, AmountRefunded: 1000})
+	ledger := ledgerWithPayment(model.DonationPayment{ProjectID: "proj-1", Amount: 3000, StripePaymentID: "pi_1"})
+	matcher := &mockStripeMatcher{}
+	svc := NewStripeService(webhookTestClient(event), &mockStripeProjectRepo{}, &mockStripeDonationRepo{}, "https://example.com",
+		WithPaymentLedger(ledger),
+		WithMatching(matcher),
+	)
+
+	// 再送でも減額をやり直す（減らし済みの分はマッチング側で変わらない）
+	for i := 0; i < 2; i++ {
+		if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(matcher.released) != 2 {
+		t.Fatalf("expected matching to be released on each delivery, got %d", len(matcher.released))
+	}
+	for _, p := range matcher.released {
+		if p.ID != "pay-1" || p.RetainedAmount() != 2000 {
+			t.Errorf("expected pay-1 with 2000 retained, got %+v", p)
+		}
+	}
+}
+
+func TestStripeService_ProcessWebhook_Dispute_ReleasesMatchingOnlyWhenLost(t *testing.T) {
+	ctx := context.Background()
+	ledger := ledgerWithPayment(model.DonationPayment{ProjectID: "proj-1", Amount: 5000, StripePaymentID: "pi_1"})
+	matcher := &mockStripeMatcher{}
+
+	for _, tc := range []struct{ eventType, status string }{
+		{"charge.dispute.created", "needs_response"},
+		{"charge.dispute.closed", "lost"},
+	} {
+		event := chargeEvent(tc.eventType, "evt_dp_"+tc.status,
+			pkgstripe.WebhookEventObject{ID: "dp_1", PaymentIntent: "pi_1", Amount: 5000, Status: tc.status})
+		svc := NewStripeService(webhookTestClient(event), &mockStripeProjectRepo{}, &mockStripeDonationRepo{}, "https://example.com",
+			WithPaymentLedger(ledger),
+			WithMatching(matcher),
+		)
+		if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.status, err)
+		}
+	}
+
+	// 係争中は勝訴の可能性があるので減らさない
+	if len(matcher.released) != 1 {
+		t.Fatalf("expected matching to be released once, got %d", len(matcher.released))
+	}
+	if got := matcher.released[0]; got.DisputeStatus != "lost" || got.RetainedAmount() != 0 {
+		t.Errorf("expected the lost payment with nothing retained, got %+v", got)
+	}
+}
+
+func TestStripeService_ProcessWebhook_ChargeRefunded_ReleaseMatchingError(t *testing.T) {
+	ctx := context.Background()
+	event := chargeEvent("charge.refunded", "evt_refund_match_err",
+		pkgstripe.WebhookEventObject{ID: "ch_1", PaymentIntent: "pi_1", Amount: 3000, AmountRefunded: 3000})
+	ledger := ledgerWithPayment(model.DonationPayment{ProjectID: "proj-1", Amount: 3000, StripePaymentID: "pi_1"})
+	svc := NewStripeService(webhookTestClient(event), &mockStripeProjectRepo{}, &mockStripeDonationRepo{}, "https://example.com",
+		WithPaymentLedger(ledger),
+		WithMatching(&mockStripeMatcher{err: errors.New("db error")}),
+	)
+
+	// 減額に失敗したら Stripe に再送させる（返金は記録済み）
+	if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err == nil {
+		t.Fatal("expected release error to fail the event, got nil")
+	}
+	if ledger.payments[0].RefundedAmount != 3000 {
+		t.Errorf("expected the refund to be recorded, got %d", ledger.payments[0].RefundedAmount)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Tests: upstream projects (passed-through support)
+// ---------------------------------------------------------------------------
+
+type mockStripeUpstreamSplitter struct {
+	share     int
+	split     []*model.DonationPayment
+	available []int
+	err       error
+}
+
+func (m *mockStripeUpstreamSplitter) SharePercent(_ context.Context, _ string) (int, error) {
+	return m.share, nil
+}
+
+func (m *mockStripeUpstreamSplitter) SplitPayment(_ context.Context, p *model.DonationPayment, available int) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.split = append(m.split, p)
+	m.available = append(m.available, available)
+	return nil
+}
+
+func TestStripeService_CreateCheckout_UpstreamApplicationFee(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name        string
+		account     string
+		recurring   bool
+		wantAmount  int
+		wantPercent int
+	}{
+		{"connected one-time", "acct_owner", false, 250, 0},
+		{"connected recurring", "acct_owner", true, 0, 25},
+		// ホストのプロジェクトは全額がプラットフォームに入るので手数料は不要
+		{"platform", "", false, 0, 0},
+	}
+	for _, tt := range tests {
+		var captured pkgstripe.CheckoutParams
+		stripeClient := &mockStripeClient{
+			createCheckoutSessionFunc: func(_ context.Context, params pkgstripe.CheckoutParams) (pkgstripe.CheckoutSession, error) {
+				captured = params
+				return pkgstripe.CheckoutSession{ID: "cs_test", URL: "https://checkout.stripe.com/test"}, nil
+			},
+		}
+		projectRepo := &mockStripeProjectRepo{
+			getByIDFunc: func(_ context.Context, _ string) (string, error) { return tt.account, nil },
+		}
+		svc := NewStripeService(stripeClient, projectRepo, &mockStripeDonationRepo{}, "https://example.com",
+			WithPaymentLedger(&mockStripePaymentLedger{}),
+			WithUpstreamSplit(&mockStripeUpstreamSplitter{share: 25}),
+		)
+
+		if _, err := svc.CreateCheckout(ctx, CheckoutRequest{ProjectID: "proj-1", Amount: 1000, IsRecurring: tt.recurring}); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if captured.ApplicationFeeAmount != tt.wantAmount || captured.ApplicationFeePercent != tt.wantPercent {
+			t.Errorf("%s: application fee = %d / %d%%, want %d / %d%%", tt.name,
+				captured.ApplicationFeeAmount, captured.ApplicationFeePercent, tt.wantAmount, tt.wantPercent)
+		}
+	}
+}
+
+func TestStripeService_ProcessWebhook_PaymentIntentSucceeded_SplitsUpstream(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name          string
+		account       string
+		fee           int
+		wantAvailable int
+	}{
+		{"platform", "", 0, 1000},
+		{"connected account", "acct_owner", 250, 250},
+	}
+	for _, tt := range tests {
+		event := paymentIntentEvent("evt_pi_split", "pi_split")
+		event.Account = tt.account
+		event.Data.Object.ApplicationFeeAmount = tt.fee
+		donationRepo := &mockStripeDonationRepo{
+			createFunc: func(_ context.Context, d *model.Donation) error {
+				d.ID = "don-1"
+				return nil
+			},
+			getByStripePaymentIDFunc: func(_ context.Context, paymentID string) (*model.Donation, error) {
+				return &model.Donation{ID: "don-1", StripePaymentID: paymentID}, nil
+			},
+		}
+		splitter := &mockStripeUpstreamSplitter{}
+		svc := NewStripeService(webhookTestClient(event), &mockStripeProjectRepo{}, donationRepo, "https://example.com",
+			WithPaymentLedger(&mockStripePaymentLedger{}),
+			WithUpstreamSplit(splitter),
+		)
+
+		// 再送でも記録済みの入金で分配をやり直す（分配済みの分はスキップされる）
+		for i := 0; i < 2; i++ {
+			if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+		}
+		if len(splitter.split) != 2 || splitter.split[0].ID != "pay-1" || splitter.split[1].ID != "pay-1" {
+			t.Fatalf("%s: expected the recorded payment to be split on each delivery, got %v", tt.name, splitter.split)
+		}
+		if splitter.available[0] != tt.wantAvailable {
+			t.Errorf("%s: available = %d, want %d", tt.name, splitter.available[0], tt.wantAvailable)
+		}
+	}
+}
+
+func TestStripeService_ProcessWebhook_PaymentIntentSucceeded_SplitError(t *testing.T) {
+	ctx := context.Background()
+	event := paymentIntentEvent("evt_pi_split_err", "pi_split_err")
+	donationRepo := &mockStripeDonationRepo{
+		createFunc: func(_ context.Context, d *model.Donation) error {
+			d.ID = "don-1"
+			return nil
+		},
+	}
+	svc := NewStripeService(webhookTestClient(event), &mockStripeProjectRepo{}, donationRepo, "https://example.com",
+		WithPaymentLedger(&mockStripePaymentLedger{}),
+		WithUpstreamSplit(&mockStripeUpstreamSplitter{err: errors.New("db error")}),
+	)
+
+	// 分配の記録に失敗したら Stripe に再送させる
+	if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err == nil {
+		t.Fatal("expected split error to fail the event, got nil")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Tests: checkout sessions (pending donations)
+// ---------------------------------------------------------------------------
+
+// mockCheckoutSessionStore は StripeCheckoutSessionStore のインメモリ実装（テスト用）
+type mockCheckoutSessionStore struct {
+	sessions  map[string]*model.CheckoutSession
+	createErr error
+	stats     *model.CheckoutStats
+}
+
+func newMockCheckoutSessionStore() *mockCheckoutSessionStore {
+	return &mockCheckoutSessionStore{sessions: make(map[string]*model.CheckoutSession)}
+}
+
+func (m *mockCheckoutSessionStore) Create(_ context.Context, cs *model.CheckoutSession) error {
+	if m.createErr != nil {
+		return m.createErr
+	}
+	cs.Status = model.CheckoutSessionOpen
+	m.sessions[cs.ID] = cs
+	return nil
+}
+func (m *mockCheckoutSessionStore) MarkCompleted(_ context.Context, id, paymentID, subscriptionID string) error {
+	cs, ok := m.sessions[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	cs.Status = model.CheckoutSessionComplete
+	cs.StripePaymentID, cs.StripeSubscriptionID = paymentID, subscriptionID
+	return nil
+}
+func (m *mockCheckoutSessionStore) MarkExpired(_ context.Context, id string) error {
+	cs, ok := m.sessions[id]
+	if !ok || cs.Status != model.CheckoutSessionOpen {
+		return repository.ErrNotFound
+	}
+	cs.Status = model.CheckoutSessionExpired
+	return nil
+}
+func (m *mockCheckoutSessionStore) GetByID(_ context.Context, id string) (*model.CheckoutSession, error) {
+	cs, ok := m.sessions[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return cs, nil
+}
+func (m *mockCheckoutSessionStore) StatsByProject(_ context.Context, projectID string, since time.Time) (*model.CheckoutStats, error) {
+	if m.stats != nil {
+		return m.stats, nil
+	}
+	return &model.CheckoutStats{ProjectID: projectID, Since: since}, nil
+}
+
+func checkoutSessionEvent(eventType, sessionID string) pkgstripe.WebhookEvent {
+	event := pkgstripe.WebhookEvent{Type: eventType, ID: "evt_" + sessionID}
+	event.Data.Object = pkgstripe.WebhookEventObject{
+		ID:            sessionID,
+		PaymentIntent: "pi_cs",
+		Metadata:      map[string]string{"project_id": "proj-1"},
+	}
+	return event
+}
+
+func TestStripeService_CreateCheckout_RecordsSession(t *testing.T) {
+	var captured pkgstripe.CheckoutParams
+	stripeClient := &mockStripeClient{
+		createCheckoutSessionFunc: func(_ context.Context, params pkgstripe.CheckoutParams) (pkgstripe.CheckoutSession, error) {
+			captured = params
+			return pkgstripe.CheckoutSession{ID: "cs_rec", URL: "https://checkout.stripe.com/test"}, nil
+		},
+	}
+	store := newMockCheckoutSessionStore()
+	svc := NewStripeService(stripeClient, &mockStripeProjectRepo{}, &mockStripeDonationRepo{}, "https://example.com",
+		WithCheckoutSessionStore(store))
+
+	url, err := svc.CreateCheckout(context.Background(), CheckoutRequest{
+		ProjectID: "proj-1", Amount: 3000, IsRecurring: true, Interval: model.IntervalQuarter,
+		DonorType: "user", DonorID: "user-1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if url != "https://checkout.stripe.com/test" {
+		t.Errorf("unexpected URL: %q", url)
+	}
+	if !strings.HasSuffix(captured.SuccessURL, "?donated=1&session_id={CHECKOUT_SESSION_ID}") {
+		t.Errorf("expected the success URL to carry the session ID, got %q", captured.SuccessURL)
+	}
+	cs := store.sessions["cs_rec"]
+	if cs == nil {
+		t.Fatal("expected the checkout session to be recorded")
+	}
+	if cs.ProjectID != "proj-1" || cs.Amount != 3000 || cs.Currency != "jpy" || !cs.IsRecurring ||
+		cs.Interval != model.IntervalQuarter || cs.DonorType != "user" || cs.DonorID != "user-1" {
+		t.Errorf("unexpected session: %+v", cs)
+	}
+}
+
+func TestStripeService_CreateCheckout_SessionStoreError(t *testing.T) {
+	stripeClient := &mockStripeClient{
+		createCheckoutSessionFunc: func(_ context.Context, _ pkgstripe.CheckoutParams) (pkgstripe.CheckoutSession, error) {
+			return pkgstripe.CheckoutSession{ID: "cs_err", URL: "https://checkout.stripe.com/test"}, nil
+		},
+	}
+	store := newMockCheckoutSessionStore()
+	store.createErr = errors.New("db error")
+	svc := NewStripeService(stripeClient, &mockStripeProjectRepo{}, &mockStripeDonationRepo{}, "https://example.com",
+		WithCheckoutSessionStore(store))
+
+	// 決済ページは作成済みなので、記録の失敗で寄付を止めない
+	url, err := svc.CreateCheckout(context.Background(), CheckoutRequest{ProjectID: "proj-1", Amount: 1000})
+	if err != nil || url != "https://checkout.stripe.com/test" {
+		t.Errorf("expected the checkout URL despite the store error, got %q, %v", url, err)
+	}
+}
+
+func TestStripeService_ProcessWebhook_CheckoutSessionCompleted(t *testing.T) {
+	ctx := context.Background()
+	store := newMockCheckoutSessionStore()
+	store.sessions["cs_1"] = &model.CheckoutSession{ID: "cs_1", ProjectID: "proj-1", Status: model.CheckoutSessionOpen}
+	svc := NewStripeService(webhookTestClient(checkoutSessionEvent("checkout.session.completed", "cs_1")),
+		&mockStripeProjectRepo{}, &mockStripeDonationRepo{}, "https://example.com",
+		WithCheckoutSessionStore(store))
+
+	if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cs, err := svc.GetCheckoutSession(ctx, "cs_1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cs.Status != model.CheckoutSessionComplete || cs.StripePaymentID != "pi_cs" {
+		t.Errorf("expected a completed session linked to pi_cs, got %+v", cs)
+	}
+}
+
+func TestStripeService_ProcessWebhook_CheckoutSessionExpired(t *testing.T) {
+	ctx := context.Background()
+	store := newMockCheckoutSessionStore()
+	store.sessions["cs_1"] = &model.CheckoutSession{ID: "cs_1", ProjectID: "proj-1", Status: model.CheckoutSessionOpen}
+	svc := NewStripeService(webhookTestClient(checkoutSessionEvent("checkout.session.expired", "cs_1")),
+		&mockStripeProjectRepo{}, &mockStripeDonationRepo{}, "https://example.com",
+		WithCheckoutSessionStore(store))
+
+	if err := svc.ProcessWebhook(ctx, []byte(`{}`), "valid-sig"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.sessions["cs_1"].Status != model.CheckoutSessionExpired {
+		t.Errorf("expected the session to expire, got %s", store.sessions["cs_1"].Status)
+	}
+}
+
+func TestStripeService_ProcessWebhook_CheckoutSessionUnknownSkipped(t *testing.T) {
+	for _, eventType := range []string{"checkout.session.completed", "checkout.session.expired"} {
+		store := newMockCheckoutSessionStore()
+		svc := NewStripeService(webhookTestClient(checkoutSessionEvent(eventType, "cs_unknown")),
+			&mockStripeProjectRepo{}, &mockStripeDonationRepo{}, "https://example.com",
+			WithCheckoutSessionStore(store))
+
+		// 機能追加前に作成された Session などは再送させずに無視する
+		if err := svc.ProcessWebhook(context.Background(), []byte(`{}`), "valid-sig"); err != nil {
+			t.Errorf("%s: expected unknown session to be skipped, got %v", eventType, err)
+		}
+	}
+}
+
+func TestStripeService_CheckoutStats_AbandonmentRate(t *testing.T) {
+	store := newMockCheckoutSessionStore()
+	store.stats = &model.CheckoutStats{ProjectID: "proj-1", Started: 10, Completed: 6, Expired: 2, Open: 2, AbandonedAmount: 4000}
+	svc := NewStripeService(&mockStripeClient{}, &mockStripeProjectRepo{}, &mockStripeDonationRepo{}, "https://example.com",
+		WithCheckoutSessionStore(store))
+
+	st, err := svc.CheckoutStats(context.Background(), "proj-1", time.Now().AddDate(0, 0, -30))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 支払い可能なままの Session は分母に含めない
+	if st.AbandonmentRate != 0.25 {
+		t.Errorf("expected abandonment rate 0.25, got %v", st.AbandonmentRate)
+	}
+}
+
+func TestStripeService_CheckoutSessions_NotConfigured(t *testing.T) {
+	svc := newTestStripeService(&mockStripeClient{})
+	if _, err := svc.GetCheckoutSession(context.Background(), "cs_1"); !errors.Is(err, ErrCheckoutSessionsNotConfigured) {
+		t.Errorf("expected ErrCheckoutSessionsNotConfigured, got %v", err)
+	}
+	if _, err := svc.CheckoutStats(context.Background(), "proj-1", time.Now()); !errors.Is(err, ErrCheckoutSessionsNotConfigured) {
+		t.Errorf("expected ErrCheckoutSessionsNotConfigured, got %v", err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Tests: 決済手数料の寄付者負担（cover_fees）
+// ---------------------------------------------------------------------------
+
+func TestStripeService_CreateCheckout_CoverFees(t *testing.T) {
+	var captured pkgstripe.CheckoutParams
+	stripeClient := &mockStripeClient{
+		createCheckoutSessionFunc: func(_ context.Context, params pkgstripe.CheckoutParams) (pkgstripe.CheckoutSession, error) {
+			captured = params
+			return pkgstripe.CheckoutSession{ID: "cs_fee", URL: "https://checkout.stripe.com/test"}, nil
+		},
+	}
+	store := newMockCheckoutSessionStore()
+	svc := NewStripeService(stripeClient, &mockStripeProjectRepo{}, &mockStripeDonationRepo{}, "https://example.com",
+		WithCheckoutSessionStore(store))
+
+	if _, err := svc.CreateCheckout(context.Background(), CheckoutRequest{ProjectID: "proj-1", Amount: 1000, CoverFees: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if captured.Amount != 1038 || captured.IntendedAmount != 1000 {
+		t.Errorf("expected a charge of 1038 for an intended 1000, got %d / %d", captured.Amount, captured.IntendedAmount)
+	}
+	if cs := store.sessions["cs_fee"]; cs == nil || cs.Amount != 1000 {
+		t.Errorf("expected the session to record the intended amount, got %+v", cs)
+	}
+}
+
+func TestStripeService_CreateCheckout_CoverFeesCustomSchedule(t *testing.T) {
+	var captured pkgstripe.CheckoutParams
+	stripeClient := &mockStripeClient{
+		createCheckoutSessionFunc: func(_ context.Context, params pkgstripe.CheckoutParams) (pkgstripe.CheckoutSession, error) {
+			captured = params
+			return pkgstripe.CheckoutSession{ID: "cs_usd", URL: "https://checkout.stripe.com/test"}, nil
+		},
+	}
+	svc := NewStripeService(stripeClient, &mockStripeProjectRepo{}, &mockStripeDonationRepo{}, "https://example.com",
+		WithFeeSchedule(pkgstripe.FeeSchedule{"usd": {BasisPoints: 290, Fixed: 30}}))
+
+	if _, err := svc.CreateCheckout(context.Background(), CheckoutRequest{ProjectID: "proj-1", Amount: 1000, Currency: "usd", CoverFees: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if captured.Amount != 1061 || captured.IntendedAmount != 1000 {
+		t.Errorf("unexpected amounts: %d / %d", captured.Amount, captured.IntendedAmount)
+	}
+
+	// 手数料表にない通貨は上乗せできない
+	_, err := svc.CreateCheckout(context.Background(), CheckoutRequest{ProjectID: "proj-1", Amount: 1000, Currency: "jpy", CoverFees: true})
+	if !errors.Is(err, ErrCoverFeesUnsupported) {
+		t.Errorf("expected ErrCoverFeesUnsupported, got %v", err)
+	}
+}
+
+func TestStripeService_CreateCheckout_WithoutCoverFees(t *testing.T) {
+	var captured pkgstripe.CheckoutParams
+	stripeClient := &mockStripeClient{
+		createCheckoutSessionFunc: func(_ context.Context, params pkgstripe.CheckoutParams) (pkgstripe.CheckoutSession, error) {
+			captured = params
+			return pkgstripe.CheckoutSession{ID: "cs_plain", URL: "https://checkout.stripe.com/test"}, nil
+		},
+	}
+	svc := newTestStripeService(stripeClient)
+
+	if _, err := svc.CreateCheckout(context.Background(), CheckoutRequest{ProjectID: "proj-1", Amount: 1000}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if captured.Amount != 1000 || captured.IntendedAmount != 0 {
+		t.Errorf("expected an unchanged charge without intended metadata, got %d / %d", captured.Amount, captured.IntendedAmount)
+	}
+}
+
+func TestStripeService_ProcessWebhook_PaymentIntentSucceeded_CoveredFee(t *testing.T) {
+	event := paymentIntentEvent("evt_pi_fee", "pi_fee")
+	event.Data.Object.Amount = 1038
+	event.Data.Object.Metadata[pkgstripe.IntendedAmountMetadataKey] = "1000"
+
+	var created *model.Donation
+	donationRepo := &mockStripeDonationRepo{
+		createFunc: func(_ context.Context, d *model.Donation) error {
+			d.ID = "don-fee"
+			created = d
+			return nil
+		},
+	}
+	ledger := &mockStripePaymentLedger{}
+	svc := newTestStripeServiceWithLedger(event, donationRepo, ledger, nil, nil)
+
+	if err := svc.ProcessWebhook(context.Background(), []byte(`{}`), "valid-sig"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created == nil || created.Amount != 1000 || created.ChargedAmount != 1038 || created.CoveredFee() != 38 {
+		t.Errorf("expected the intended amount with the charge alongside, got %+v", created)
+	}
+	if len(ledger.payments) != 1 || ledger.payments[0].Amount != 1000 || ledger.payments[0].ChargedAmount != 1038 {
+		t.Errorf("unexpected payments: %+v", ledger.payments)
+	}
+}
+
+func TestStripeService_ProcessWebhook_SubscriptionCreated_CoveredFee(t *testing.T) {
+	event := pkgstripe.WebhookEvent{Type: "customer.subscription.created", ID: "evt_sub_fee"}
+	event.Data.Object = pkgstripe.WebhookEventObject{
+		ID:       "sub_fee",
+		Metadata: map[string]string{"project_id": "proj-1", pkgstripe.IntendedAmountMetadataKey: "3000"},
+		Plan:     &pkgstripe.SubscriptionPlan{Amount: 3113, Currency: "jpy", Interval: "month", IntervalCount: 1},
+	}
+	var created *model.Donation
+	donationRepo := &mockStripeDonationRepo{
+		createFunc: func(_ context.Context, d *model.Donation) error {
+			created = d
+			return nil
+		},
+	}
+	svc := newTestStripeServiceFull(webhookTestClient(event), &mockStripeProjectRepo{}, donationRepo)
+
+	if err := svc.ProcessWebhook(context.Background(), []byte(`{}`), "valid-sig"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created == nil || created.Amount != 3000 || created.ChargedAmount != 3113 {
+		t.Errorf("unexpected donation: %+v", created)
+	}
+}
+
+func TestStripeService_ProcessWebhook_InvoicePaymentSucceeded_CoveredFee(t *testing.T) {
+	donationRepo := &mockStripeDonationRepo{
+		getByStripeSubscriptionIDFunc: func(_ context.Context, subID string) (*model.Donation, error) {
+			return &model.Donation{ID: "don-fee", ProjectID: "proj-1", Amount: 3000, ChargedAmount: 3113, Currency: "jpy",
+				IsRecurring: true, StripeSubscriptionID: subID}, nil
+		},
+	}
+	ledger := &mockStripePaymentLedger{}
+	svc := newTestStripeServiceWithLedger(invoiceEvent("evt_inv_fee", "in_fee", "sub_fee", 3113), donationRepo, ledger, nil, nil)
+
+	if err := svc.ProcessWebhook(context.Background(), []byte(`{}`), "valid-sig"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ledger.payments) != 1 || ledger.payments[0].Amount != 3000 || ledger.payments[0].ChargedAmount != 3113 {
+		t.Errorf("expected the ledger to record 3000 reaching the project out of 3113, got %+v", ledger.payments)
+	}
+}
+
+func TestStripeService_CreateCheckout_CustomerCreationError(t *testing.T) {
+	var captured pkgstripe.CheckoutParams
+	stripeClient := &mockStripeClient{
+		createCustomerFunc: func(_ context.Context, _ pkgstripe.CustomerParams) (string, error) {
+			return "", errors.New("stripe down")
+		},
+		createCheckoutSessionFunc: func(_ context.Context, params pkgstripe.CheckoutParams) (pkgstripe.CheckoutSession, error) {
+			captured = params
+			return pkgstripe.CheckoutSession{ID: "cs_1", URL: "https://checkout.stripe.com/test"}, nil
+		},
+	}
+	customers := &memStripeCustomerStore{users: map[string]*model.User{"u1": {ID: "u1"}}}
+	svc := NewStripeService(stripeClient, &mockStripeProjectRepo{}, &mockStripeDonationRepo{}, "https://example.com",
+		WithCustomerStore(customers))
+
+	// Customer を作れなくても寄付は止めず、Checkout に Customer の作成を任せる
+	url, err := svc.CreateCheckout(context.Background(), CheckoutRequest{ProjectID: "proj-1", Amount: 1000, DonorType: "user", DonorID: "u1"})
+	if err != nil || url != "https://checkout.stripe.com/test" {
+		t.Fatalf("expected the checkout URL despite the customer error, got %q, %v", url, err)
+	}
+	if captured.CustomerID != "" || customers.users["u1"].StripeCustomerID != "" {
+		t.Errorf("expected no customer, got params %q / saved %q", captured.CustomerID, customers.users["u1"].StripeCustomerID)
+	}
+}
+
+func TestStripeService_CreateBillingPortalSession_NotConfigured(t *testing.T) {
+	svc := NewStripeService(&mockStripeClient{}, &mockStripeProjectRepo{}, &mockStripeDonationRepo{}, "https://example.com")
+	if _, err := svc.CreateBillingPortalSession(context.Background(), "u1", ""); !errors.Is(err, ErrCustomersNotConfigured) {
+		t.Errorf("expected ErrCustomersNotConfigured, got %v", err)
+	}
+}
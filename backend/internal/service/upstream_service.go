@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+	pkgstripe "github.com/givers/backend/pkg/stripe"
+)
+
+// ErrInvalidUpstreams is returned when a list of upstream projects has an invalid
+// project or share. The wrapped message describes the problem.
+var ErrInvalidUpstreams = errors.New("invalid upstream projects")
+
+// ---------------------------------------------------------------------------
+// Minimal interfaces (only what UpstreamService needs)
+// ---------------------------------------------------------------------------
+
+type UpstreamRepo interface {
+	ListByProject(ctx context.Context, projectID string) ([]*model.ProjectUpstream, error)
+	Replace(ctx context.Context, projectID string, upstreams []*model.ProjectUpstream) error
+	CreatePassThroughs(ctx context.Context, list []*model.PassThrough) ([]*model.PassThrough, error)
+	ListUntransferred(ctx context.Context, limit int) ([]*model.PassThrough, error)
+	MarkTransferred(ctx context.Context, id, transferID string) error
+	MarkFailed(ctx context.Context, id, lastError string) error
+	ListPassThroughsByProject(ctx context.Context, projectID string, limit, offset int) ([]*model.PassThrough, error)
+	MonthlyForwardedByProject(ctx context.Context, projectID string) ([]*model.MonthlySum, error)
+	MonthlyReceivedByProject(ctx context.Context, projectID string) ([]*model.MonthlySum, error)
+}
+
+type UpstreamProjectGetter interface {
+	GetByID(ctx context.Context, id string) (*model.Project, error)
+	GetStripeAccountID(ctx context.Context, projectID string) (string, error)
+}
+
+type UpstreamTransferer interface {
+	CreateTransfer(ctx context.Context, params pkgstripe.TransferParams) (string, error)
+}
+
+type UpstreamActivityRecorder interface {
+	Insert(ctx context.Context, a *model.ActivityItem) error
+}
+
+// ---------------------------------------------------------------------------
+// UpstreamService
+// ---------------------------------------------------------------------------
+
+// UpstreamInput is one upstream project in a list set by the project owner.
+type UpstreamInput struct {
+	UpstreamProjectID string
+	SharePercent      int
+}
+
+// UpstreamService manages the upstream projects a project depends on and forwards
+// their share of each ledger payment with a Stripe transfer ("passed-through support").
+// Forwarding does not cascade: support a project receives from downstream is not split again.
+// Callers must check that the actor is a project maintainer or a host before Replace.
+type UpstreamService interface {
+	List(ctx context.Context, projectID string) ([]*model.ProjectUpstream, error)
+	// Replace validates and sets the upstream projects of projectID, then returns the saved list.
+	Replace(ctx context.Context, projectID string, in []UpstreamInput) ([]*model.ProjectUpstream, error)
+	// SharePercent returns the total share projectID forwards to its upstream projects.
+	SharePercent(ctx context.Context, projectID string) (int, error)
+	// SplitPayment records the upstream shares of a ledger payment and transfers them.
+	// available caps the total forwarded (the application fee collected on a connected
+	// account's charge). Payments already split are skipped, so redeliveries are safe.
+	// A failed transfer is left for RetryTransfers and is not an error.
+	SplitPayment(ctx context.Context, p *model.DonationPayment, available int) error
+	// RetryTransfers sends the pass-throughs whose transfer has not succeeded yet.
+	RetryTransfers(ctx context.Context, limit int) (sent, failed int, err error)
+	ListPassThroughs(ctx context.Context, projectID string, limit, offset int) ([]*model.PassThrough, error)
+	MonthlyForwardedByProject(ctx context.Context, projectID string) ([]*model.MonthlySum, error)
+	MonthlyReceivedByProject(ctx context.Context, projectID string) ([]*model.MonthlySum, error)
+}
+
+type upstreamService struct {
+	repo             UpstreamRepo
+	projects         UpstreamProjectGetter
+	transferer       UpstreamTransferer
+	activityRecorder UpstreamActivityRecorder // optional
+}
+
+// NewUpstreamService creates an UpstreamService. activityRecorder can be nil to skip the activity feed.
+func NewUpstreamService(repo UpstreamRepo, projects UpstreamProjectGetter, transferer UpstreamTransferer, activityRecorder UpstreamActivityRecorder) UpstreamService {
+	return &upstreamService{
+		repo:             repo,
+		projects:         projects,
+		transferer:       transferer,
+		activityRecorder: activityRecorder,
+	}
+}
+
+func (s *upstreamService) List(ctx context.Context, projectID string) ([]*model.ProjectUpstream, error) {
+	return s.repo.ListByProject(ctx, projectID)
+}
+
+func (s *upstreamService) Replace(ctx context.Context, projectID string, in []UpstreamInput) ([]*model.ProjectUpstream, error) {
+	if len(in) > model.MaxUpstreams {
+		return nil, fmt.Errorf("%w: at most %d upstream projects", ErrInvalidUpstreams, model.MaxUpstreams)
+	}
+	seen := make(map[string]bool, len(in))
+	total := 0
+	upstreams := make([]*model.ProjectUpstream, 0, len(in))
+	for _, u := range in {
+		switch {
+		case u.UpstreamProjectID == "":
+			return nil, fmt.Errorf("%w: upstream_project_id is required", ErrInvalidUpstreams)
+		case u.UpstreamProjectID == projectID:
+			return nil, fmt.Errorf("%w: a project cannot be its own upstream", ErrInvalidUpstreams)
+		case seen[u.UpstreamProjectID]:
+			return nil, fmt.Errorf("%w: upstream project %s is listed twice", ErrInvalidUpstreams, u.UpstreamProjectID)
+		case u.SharePercent < 1 || u.SharePercent > model.MaxUpstreamSharePercent:
+			return nil, fmt.Errorf("%w: share_percent must be between 1 and %d", ErrInvalidUpstreams, model.MaxUpstreamSharePercent)
+		}
+		seen[u.UpstreamProjectID] = true
+		total += u.SharePercent
+
+		p, err := s.projects.GetByID(ctx, u.UpstreamProjectID)
+		if errors.Is(err, repository.ErrNotFound) || (err == nil && (p == nil || p.Status == "deleted")) {
+			return nil, fmt.Errorf("%w: upstream project %s not found", ErrInvalidUpstreams, u.UpstreamProjectID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("find upstream project: %w", err)
+		}
+		upstreams = append(upstreams, &model.ProjectUpstream{
+			ProjectID:         projectID,
+			UpstreamProjectID: u.UpstreamProjectID,
+			SharePercent:      u.SharePercent,
+		})
+	}
+	if total > model.MaxUpstreamSharePercent {
+		return nil, fmt.Errorf("%w: shares add up to more than %d%%", ErrInvalidUpstreams, model.MaxUpstreamSharePercent)
+	}
+
+	if err := s.repo.Replace(ctx, projectID, upstreams); err != nil {
+		return nil, fmt.Errorf("save upstream projects: %w", err)
+	}
+	return s.repo.ListByProject(ctx, projectID)
+}
+
+func (s *upstreamService) SharePercent(ctx context.Context, projectID string) (int, error) {
+	upstreams, err := s.repo.ListByProject(ctx, projectID)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, u := range upstreams {
+		total += u.SharePercent
+	}
+	return min(total, model.MaxUpstreamSharePercent), nil
+}
+
+func (s *upstreamService) SplitPayment(ctx context.Context, p *model.DonationPayment, available int) error {
+	if p.ID == "" || p.Amount <= 0 || available <= 0 {
+		return nil
+	}
+	upstreams, err := s.repo.ListByProject(ctx, p.ProjectID)
+	if err != nil {
+		return fmt.Errorf("list upstream projects: %w", err)
+	}
+
+	// 上流への配分は徴収できた額（連結アカウントの決済ではアプリケーション手数料）を超えない
+	remaining := available
+	var list []*model.PassThrough
+	for _, u := range upstreams {
+		amount := min(u.ShareOf(p.Amount), remaining)
+		if amount <= 0 {
+			continue
+		}
+		remaining -= amount
+		list = append(list, &model.PassThrough{
+			PaymentID:     p.ID,
+			FromProjectID: p.ProjectID,
+			ToProjectID:   u.UpstreamProjectID,
+			ToProjectName: u.UpstreamProjectName,
+			Amount:        amount,
+			Currency:      p.Currency,
+			SharePercent:  u.SharePercent,
+			PaidAt:        p.PaidAt,
+		})
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	created, err := s.repo.CreatePassThroughs(ctx, list)
+	if err != nil {
+		return fmt.Errorf("record pass-throughs: %w", err)
+	}
+
+	var fromName string
+	if len(created) > 0 {
+		if from, err := s.projects.GetByID(ctx, p.ProjectID); err == nil {
+			fromName = from.Name
+		}
+	}
+	for _, pt := range created {
+		pt.FromProjectName = fromName
+		s.recordActivities(ctx, pt)
+		_ = s.transfer(ctx, pt)
+	}
+	return nil
+}
+
+// recordActivities は分配を両プロジェクトのアクティビティに記録する（失敗しても無視）。
+// 上流側には下流プロジェクト名、下流側には上流プロジェクト名をメッセージに入れる。
+func (s *upstreamService) recordActivities(ctx context.Context, pt *model.PassThrough) {
+	if s.activityRecorder == nil {
+		return
+	}
+	amount := pt.Amount
+	_ = s.activityRecorder.Insert(ctx, &model.ActivityItem{
+		Type:      "pass_through_out",
+		ProjectID: pt.FromProjectID,
+		Amount:    &amount,
+		Message:   pt.ToProjectName,
+	})
+	_ = s.activityRecorder.Insert(ctx, &model.ActivityItem{
+		Type:      "pass_through_in",
+		ProjectID: pt.ToProjectID,
+		Amount:    &amount,
+		Message:   pt.FromProjectName,
+	})
+}
+
+// transfer は分配額を上流プロジェクトの連結アカウントへ送金し、結果を記録する。
+// 上流がホストのプロジェクト（連結アカウントなし）の場合、資金はプラットフォームに残るので送金しない。
+func (s *upstreamService) transfer(ctx context.Context, pt *model.PassThrough) error {
+	destination, err := s.projects.GetStripeAccountID(ctx, pt.ToProjectID)
+	if err != nil {
+		return s.markFailed(ctx, pt, fmt.Errorf("get stripe account id: %w", err))
+	}
+	transferID := ""
+	if destination != "" {
+		transferID, err = s.transferer.CreateTransfer(ctx, pkgstripe.TransferParams{
+			Destination:   destination,
+			Amount:        pt.Amount,
+			Currency:      pt.Currency,
+			TransferGroup: pt.PaymentID,
+			Metadata: map[string]string{
+				"pass_through_id": pt.ID,
+				"from_project_id": pt.FromProjectID,
+				"to_project_id":   pt.ToProjectID,
+			},
+			// 再実行しても二重に送金しない
+			IdempotencyKey: "pass-through-" + pt.ID,
+		})
+		if err != nil {
+			return s.markFailed(ctx, pt, err)
+		}
+	}
+	if err := s.repo.MarkTransferred(ctx, pt.ID, transferID); err != nil {
+		return fmt.Errorf("mark pass-through transferred: %w", err)
+	}
+	pt.Status, pt.StripeTransferID = model.PassThroughStatusTransferred, transferID
+	return nil
+}
+
+func (s *upstreamService) markFailed(ctx context.Context, pt *model.PassThrough, cause error) error {
+	pt.Status, pt.LastError = model.PassThroughStatusFailed, cause.Error()
+	if err := s.repo.MarkFailed(ctx, pt.ID, cause.Error()); err != nil {
+		return fmt.Errorf("mark pass-through failed: %w", err)
+	}
+	return cause
+}
+
+func (s *upstreamService) RetryTransfers(ctx context.Context, limit int) (int, int, error) {
+	list, err := s.repo.ListUntransferred(ctx, limit)
+	if err != nil {
+		return 0, 0, fmt.Errorf("list untransferred pass-throughs: %w", err)
+	}
+	sent, failed := 0, 0
+	for _, pt := range list {
+		if err := s.transfer(ctx, pt); err != nil {
+			failed++
+			continue
+		}
+		sent++
+	}
+	return sent, failed, nil
+}
+
+func (s *upstreamService) ListPassThroughs(ctx context.Context, projectID string, limit, offset int) ([]*model.PassThrough, error) {
+	return s.repo.ListPassThroughsByProject(ctx, projectID, limit, offset)
+}
+
+func (s *upstreamService) MonthlyForwardedByProject(ctx context.Context, projectID string) ([]*model.MonthlySum, error) {
+	return s.repo.MonthlyForwardedByProject(ctx, projectID)
+}
+
+func (s *upstreamService) MonthlyReceivedByProject(ctx context.Context, projectID string) ([]*model.MonthlySum, error) {
+	return s.repo.MonthlyReceivedByProject(ctx, projectID)
+}
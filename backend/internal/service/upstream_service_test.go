@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/givers/backend/internal/model"
+	"github.com/givers/backend/internal/repository"
+	pkgstripe "github.com/givers/backend/pkg/stripe"
+)
+
+// ---------------------------------------------------------------------------
+// Mocks
+// ---------------------------------------------------------------------------
+
+// memUpstreamRepo は UpstreamRepo のインメモリ実装（入金と上流プロジェクトの組で重複を除くのは pg 実装と同じ）
+type memUpstreamRepo struct {
+	upstreams    map[string][]*model.ProjectUpstream
+	passThroughs []*model.PassThrough
+}
+
+func newMemUpstreamRepo() *memUpstreamRepo {
+	return &memUpstreamRepo{upstreams: map[string][]*model.ProjectUpstream{}}
+}
+
+func (m *memUpstreamRepo) ListByProject(_ context.Context, projectID string) ([]*model.ProjectUpstream, error) {
+	return m.upstreams[projectID], nil
+}
+
+func (m *memUpstreamRepo) Replace(_ context.Context, projectID string, upstreams []*model.ProjectUpstream) error {
+	for _, u := range upstreams {
+		u.UpstreamProjectName = "Project " + u.UpstreamProjectID
+	}
+	m.upstreams[projectID] = upstreams
+	return nil
+}
+
+func (m *memUpstreamRepo) CreatePassThroughs(_ context.Context, list []*model.PassThrough) ([]*model.PassThrough, error) {
+	var created []*model.PassThrough
+	for _, pt := range list {
+		if m.find(pt.PaymentID, pt.ToProjectID) != nil {
+			continue
+		}
+		pt.ID = fmt.Sprintf("pt-%d", len(m.passThroughs)+1)
+		pt.Status = model.PassThroughStatusPending
+		m.passThroughs = append(m.passThroughs, pt)
+		created = append(created, pt)
+	}
+	return created, nil
+}
+
+func (m *memUpstreamRepo) find(paymentID, toProjectID string) *model.PassThrough {
+	for _, pt := range m.passThroughs {
+		if pt.PaymentID == paymentID && pt.ToProjectID == toProjectID {
+			return pt
+		}
+	}
+	return nil
+}
+
+func (m *memUpstreamRepo) byID(id string) *model.PassThrough {
+	for _, pt := range m.passThroughs {
+		if pt.ID == id {
+			return pt
+		}
+	}
+	return nil
+}
+
+func (m *memUpstreamRepo) ListUntransferred(_ context.Context, limit int) ([]*model.PassThrough, error) {
+	var list []*model.PassThrough
+	for _, pt := range m.passThroughs {
+		if pt.Status != model.PassThroughStatusTransferred && len(list) < limit {
+			cp := *pt
+			list = append(list, &cp)
+		}
+	}
+	return list, nil
+}
+
+func (m *memUpstreamRepo) MarkTransferred(_ context.Context, id, transferID string) error {
+	pt := m.byID(id)
+	now := time.Now()
+	pt.Status, pt.StripeTransferID, pt.LastError, pt.TransferredAt = model.PassThroughStatusTransferred, transferID, "", &now
+	return nil
+}
+
+func (m *memUpstreamRepo) MarkFailed(_ context.Context, id, lastError string) error {
+	pt := m.byID(id)
+	pt.Status, pt.LastError = model.PassThroughStatusFailed, lastError
+	return nil
+}
+
+func (m *memUpstreamRepo) ListPassThroughsByProject(_ context.Context, _ string, _, _ int) ([]*model.PassThrough, error) {
+	return m.passThroughs, nil
+}
+
+func (m *memUpstreamRepo) MonthlyForwardedByProject(_ context.Context, _ string) ([]*model.MonthlySum, error) {
+	return nil, nil
+}
+
+func (m *memUpstreamRepo) MonthlyReceivedByProject(_ context.Context, _ string) ([]*model.MonthlySum, error) {
+	return nil, nil
+}
+
+// mockUpstreamProjects は projects に登録したプロジェクトを返す（accounts は連結アカウント ID、なければホストのプロジェクト）
+type mockUpstreamProjects struct {
+	projects map[string]*model.Project
+	accounts map[string]string
+}
+
+func (m *mockUpstreamProjects) GetByID(_ context.Context, id string) (*model.Project, error) {
+	p, ok := m.projects[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return p, nil
+}
+
+func (m *mockUpstreamProjects) GetStripeAccountID(_ context.Context, projectID string) (string, error) {
+	return m.accounts[projectID], nil
+}
+
+type mockUpstreamTransferer struct {
+	transfers []pkgstripe.TransferParams
+	err       error
+}
+
+func (m *mockUpstreamTransferer) CreateTransfer(_ context.Context, params pkgstripe.TransferParams) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+	m.transfers = append(m.transfers, params)
+	return fmt.Sprintf("tr_%d", len(m.transfers)), nil
+}
+
+func newTestUpstreamProjects() *mockUpstreamProjects {
+	return &mockUpstreamProjects{
+		projects: map[string]*model.Project{
+			"app":  {ID: "app", Name: "App", Status: "active"},
+			"lib":  {ID: "lib", Name: "Lib", Status: "active"},
+			"host": {ID: "host", Name: "Host tools", Status: "active"},
+			"gone": {ID: "gone", Name: "Gone", Status: "deleted"},
+		},
+		accounts: map[string]string{"app": "acct_app", "lib": "acct_lib"},
+	}
+}
+
+func testPassThroughPayment(amount int) *model.DonationPayment {
+	return &model.DonationPayment{
+		ID:        "pay-1",
+		ProjectID: "app",
+		Amount:    amount,
+		Currency:  "jpy",
+		PaidAt:    time.Date(2026, 4, 10, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Tests
+// ---------------------------------------------------------------------------
+
+func TestUpstreamService_Replace_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []UpstreamInput
+	}{
+		{"self", []UpstreamInput{{UpstreamProjectID: "app", SharePercent: 10}}},
+		{"duplicate", []UpstreamInput{{UpstreamProjectID: "lib", SharePercent: 10}, {UpstreamProjectID: "lib", SharePercent: 5}}},
+		{"zero share", []UpstreamInput{{UpstreamProjectID: "lib", SharePercent: 0}}},
+		{"total over 100", []UpstreamInput{{UpstreamProjectID: "lib", SharePercent: 60}, {UpstreamProjectID: "host", SharePercent: 50}}},
+		{"unknown project", []UpstreamInput{{UpstreamProjectID: "nope", SharePercent: 10}}},
+		{"deleted project", []UpstreamInput{{UpstreamProjectID: "gone", SharePercent: 10}}},
+		{"missing id", []UpstreamInput{{SharePercent: 10}}},
+	}
+	for _, tt := range tests {
+		svc := NewUpstreamService(newMemUpstreamRepo(), newTestUpstreamProjects(), &mockUpstreamTransferer{}, nil)
+		if _, err := svc.Replace(context.Background(), "app", tt.in); !errors.Is(err, ErrInvalidUpstreams) {
+			t.Errorf("%s: expected ErrInvalidUpstreams, got %v", tt.name, err)
+		}
+	}
+
+	tooMany := make([]UpstreamInput, model.MaxUpstreams+1)
+	svc := NewUpstreamService(newMemUpstreamRepo(), newTestUpstreamProjects(), &mockUpstreamTransferer{}, nil)
+	if _, err := svc.Replace(context.Background(), "app", tooMany); !errors.Is(err, ErrInvalidUpstreams) {
+		t.Errorf("too many: expected ErrInvalidUpstreams, got %v", err)
+	}
+}
+
+func TestUpstreamService_Replace(t *testing.T) {
+	ctx := context.Background()
+	svc := NewUpstreamService(newMemUpstreamRepo(), newTestUpstreamProjects(), &mockUpstreamTransferer{}, nil)
+
+	list, err := svc.Replace(ctx, "app", []UpstreamInput{{UpstreamProjectID: "lib", SharePercent: 10}, {UpstreamProjectID: "host", SharePercent: 5}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list) != 2 || list[0].UpstreamProjectName != "Project lib" {
+		t.Errorf("unexpected list: %+v", list)
+	}
+	if share, _ := svc.SharePercent(ctx, "app"); share != 15 {
+		t.Errorf("SharePercent = %d, want 15", share)
+	}
+
+	// 空のリストで分配をやめる
+	if _, err := svc.Replace(ctx, "app", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if share, _ := svc.SharePercent(ctx, "app"); share != 0 {
+		t.Errorf("SharePercent = %d, want 0", share)
+	}
+}
+
+func TestUpstreamService_SplitPayment(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemUpstreamRepo()
+	transferer := &mockUpstreamTransferer{}
+	var activities []*model.ActivityItem
+	recorder := &mockStripeActivityRecorder{insertFunc: func(_ context.Context, a *model.ActivityItem) error {
+		activities = append(activities, a)
+		return nil
+	}}
+	svc := NewUpstreamService(repo, newTestUpstreamProjects(), transferer, recorder)
+	if _, err := svc.Replace(ctx, "app", []UpstreamInput{{UpstreamProjectID: "lib", SharePercent: 10}, {UpstreamProjectID: "host", SharePercent: 5}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 再送で同じ入金を分配しても二重に記録・送金しない
+	for i := 0; i < 2; i++ {
+		if err := svc.SplitPayment(ctx, testPassThroughPayment(3333), 3333); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(repo.passThroughs) != 2 {
+		t.Fatalf("expected 2 pass-throughs, got %d", len(repo.passThroughs))
+	}
+	lib, host := repo.find("pay-1", "lib"), repo.find("pay-1", "host")
+	if lib.Amount != 333 || lib.Status != model.PassThroughStatusTransferred || lib.StripeTransferID != "tr_1" {
+		t.Errorf("unexpected lib pass-through: %+v", lib)
+	}
+	// ホストのプロジェクトへの分配はプラットフォームに残るので送金しない
+	if host.Amount != 166 || host.Status != model.PassThroughStatusTransferred || host.StripeTransferID != "" {
+		t.Errorf("unexpected host pass-through: %+v", host)
+	}
+	if len(transferer.transfers) != 1 {
+		t.Fatalf("expected 1 transfer, got %d", len(transferer.transfers))
+	}
+	tr := transferer.transfers[0]
+	if tr.Destination != "acct_lib" || tr.Amount != 333 || tr.Currency != "jpy" || tr.TransferGroup != "pay-1" ||
+		tr.IdempotencyKey != "pass-through-"+lib.ID || tr.Metadata["from_project_id"] != "app" {
+		t.Errorf("unexpected transfer: %+v", tr)
+	}
+
+	if len(activities) != 4 {
+		t.Fatalf("expected 4 activities, got %d", len(activities))
+	}
+	out, in := activities[0], activities[1]
+	if out.Type != "pass_through_out" || out.ProjectID != "app" || *out.Amount != 333 || out.Message != "Project lib" {
+		t.Errorf("unexpected outgoing activity: %+v", out)
+	}
+	if in.Type != "pass_through_in" || in.ProjectID != "lib" || *in.Amount != 333 || in.Message != "App" {
+		t.Errorf("unexpected incoming activity: %+v", in)
+	}
+}
+
+func TestUpstreamService_SplitPayment_CappedByAvailable(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemUpstreamRepo()
+	svc := NewUpstreamService(repo, newTestUpstreamProjects(), &mockUpstreamTransferer{}, nil)
+	if _, err := svc.Replace(ctx, "app", []UpstreamInput{{UpstreamProjectID: "lib", SharePercent: 20}, {UpstreamProjectID: "host", SharePercent: 10}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 徴収できたアプリケーション手数料を超えては分配しない
+	if err := svc.SplitPayment(ctx, testPassThroughPayment(1000), 250); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lib := repo.find("pay-1", "lib"); lib == nil || lib.Amount != 200 {
+		t.Errorf("unexpected lib pass-through: %+v", lib)
+	}
+	if host := repo.find("pay-1", "host"); host == nil || host.Amount != 50 {
+		t.Errorf("unexpected host pass-through: %+v", host)
+	}
+
+	// 手数料を徴収できなかった入金は分配しない
+	p := testPassThroughPayment(1000)
+	p.ID = "pay-2"
+	if err := svc.SplitPayment(ctx, p, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.passThroughs) != 2 {
+		t.Errorf("expected no pass-throughs without an application fee, got %d", len(repo.passThroughs))
+	}
+}
+
+func TestUpstreamService_RetryTransfers(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemUpstreamRepo()
+	transferer := &mockUpstreamTransferer{err: errors.New("insufficient funds")}
+	svc := NewUpstreamService(repo, newTestUpstreamProjects(), transferer, nil)
+	if _, err := svc.Replace(ctx, "app", []UpstreamInput{{UpstreamProjectID: "lib", SharePercent: 10}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 送金の失敗は入金の処理を止めず、再送用に失敗として残す
+	if err := svc.SplitPayment(ctx, testPassThroughPayment(1000), 1000); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pt := repo.find("pay-1", "lib")
+	if pt.Status != model.PassThroughStatusFailed || pt.LastError != "insufficient funds" {
+		t.Fatalf("unexpected pass-through: %+v", pt)
+	}
+
+	sent, failed, err := svc.RetryTransfers(ctx, 100)
+	if err != nil || sent != 0 || failed != 1 {
+		t.Errorf("RetryTransfers = %d, %d, %v; want 0, 1, nil", sent, failed, err)
+	}
+
+	transferer.err = nil
+	sent, failed, err = svc.RetryTransfers(ctx, 100)
+	if err != nil || sent != 1 || failed != 0 {
+		t.Errorf("RetryTransfers = %d, %d, %v; want 1, 0, nil", sent, failed, err)
+	}
+	if pt.Status != model.PassThroughStatusTransferred || pt.StripeTransferID != "tr_1" || pt.LastError != "" {
+		t.Errorf("unexpected pass-through after retry: %+v", pt)
+	}
+	if transferer.transfers[0].IdempotencyKey != "pass-through-"+pt.ID {
+		t.Errorf("unexpected idempotency key %q", transferer.transfers[0].IdempotencyKey)
+	}
+}
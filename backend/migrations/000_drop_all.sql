@@ -3,7 +3,19 @@
 -- 依存関係の逆順で削除する。
 -- =============================================================================
 
+DROP TABLE IF EXISTS stripe_customers   CASCADE;
+DROP TABLE IF EXISTS stripe_payouts     CASCADE;
+DROP TABLE IF EXISTS checkout_sessions  CASCADE;
+DROP TABLE IF EXISTS webhook_events     CASCADE;
 DROP TABLE IF EXISTS sessions           CASCADE;
+DROP TABLE IF EXISTS pass_throughs       CASCADE;
+DROP TABLE IF EXISTS project_upstreams   CASCADE;
+DROP TABLE IF EXISTS donation_changes    CASCADE;
+DROP TABLE IF EXISTS matching_contributions CASCADE;
+DROP TABLE IF EXISTS matching_campaigns  CASCADE;
+DROP TABLE IF EXISTS donation_receipts   CASCADE;
+DROP TABLE IF EXISTS donation_audit_logs CASCADE;
+DROP TABLE IF EXISTS donation_payments   CASCADE;
 DROP TABLE IF EXISTS activities          CASCADE;
 DROP TABLE IF EXISTS user_cost_presets   CASCADE;
 DROP TABLE IF EXISTS donations           CASCADE;
@@ -12,8 +24,18 @@ DROP TABLE IF EXISTS watches             CASCADE;
 DROP TABLE IF EXISTS project_alerts      CASCADE;
 DROP TABLE IF EXISTS contact_messages    CASCADE;
 DROP TABLE IF EXISTS platform_health     CASCADE;
+DROP TABLE IF EXISTS project_revisions CASCADE;
+DROP TABLE IF EXISTS project_slug_redirects CASCADE;
+DROP TABLE IF EXISTS project_invitations CASCADE;
+DROP TABLE IF EXISTS project_members     CASCADE;
 DROP TABLE IF EXISTS project_cost_items  CASCADE;
 DROP TABLE IF EXISTS project_costs       CASCADE;
 DROP TABLE IF EXISTS projects            CASCADE;
+DROP TABLE IF EXISTS project_categories  CASCADE;
 DROP TABLE IF EXISTS users               CASCADE;
 DROP TABLE IF EXISTS schema_migrations   CASCADE;
+
+-- 全文検索用の関数（043）
+DROP FUNCTION IF EXISTS search_query(TEXT);
+DROP FUNCTION IF EXISTS search_tokens(TEXT);
+DROP FUNCTION IF EXISTS search_normalize(TEXT);
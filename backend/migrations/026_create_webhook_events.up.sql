@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS webhook_events (
+    id           VARCHAR(255) PRIMARY KEY,
+    type         VARCHAR(100) NOT NULL,
+    payload      JSONB NOT NULL,
+    status       VARCHAR(20) NOT NULL DEFAULT 'processing' CHECK (status IN ('processing', 'processed', 'failed')),
+    attempts     INTEGER NOT NULL DEFAULT 0,
+    last_error   TEXT,
+    processed_at TIMESTAMP WITH TIME ZONE,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_events_status ON webhook_events(status, created_at DESC);
@@ -0,0 +1,9 @@
+DELETE FROM activities WHERE type IN ('refund', 'dispute');
+ALTER TABLE activities DROP CONSTRAINT IF EXISTS activities_type_check;
+ALTER TABLE activities ADD CONSTRAINT activities_type_check
+    CHECK (type IN ('donation', 'project_created', 'project_updated', 'milestone'));
+
+ALTER TABLE donations DROP COLUMN IF EXISTS dispute_amount;
+ALTER TABLE donations DROP COLUMN IF EXISTS dispute_status;
+ALTER TABLE donations DROP COLUMN IF EXISTS refunded_at;
+ALTER TABLE donations DROP COLUMN IF EXISTS refunded_amount;
@@ -0,0 +1,8 @@
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS refunded_amount INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS refunded_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS dispute_status VARCHAR(30);
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS dispute_amount INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE activities DROP CONSTRAINT IF EXISTS activities_type_check;
+ALTER TABLE activities ADD CONSTRAINT activities_type_check
+    CHECK (type IN ('donation', 'project_created', 'project_updated', 'milestone', 'refund', 'dispute'));
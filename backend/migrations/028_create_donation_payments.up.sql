@@ -0,0 +1,33 @@
+-- 実際に着金した決済の台帳。単発寄付は 1 件、定期寄付は請求ごとに 1 件。
+-- donations（寄付の申込み）が削除されても入金履歴は残す。
+CREATE TABLE IF NOT EXISTS donation_payments (
+    id                VARCHAR(36) PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    donation_id       VARCHAR(36) REFERENCES donations(id) ON DELETE SET NULL,
+    project_id        VARCHAR(36) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    amount            INTEGER NOT NULL CHECK (amount > 0),
+    currency          VARCHAR(3) NOT NULL DEFAULT 'jpy',
+    stripe_payment_id TEXT,
+    stripe_invoice_id TEXT,
+    refunded_amount   INTEGER NOT NULL DEFAULT 0,
+    refunded_at       TIMESTAMP WITH TIME ZONE,
+    dispute_status    VARCHAR(30),
+    dispute_amount    INTEGER NOT NULL DEFAULT 0,
+    paid_at           TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_donation_payments_project_paid_at ON donation_payments(project_id, paid_at);
+CREATE INDEX IF NOT EXISTS idx_donation_payments_donation_id ON donation_payments(donation_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_donation_payments_stripe_payment_id_unique
+    ON donation_payments(stripe_payment_id) WHERE stripe_payment_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_donation_payments_stripe_invoice_id_unique
+    ON donation_payments(stripe_invoice_id) WHERE stripe_invoice_id IS NOT NULL;
+
+-- 既存の寄付は申込み時点の 1 回分を入金として移行する（定期寄付の過去の請求履歴は復元できない）
+INSERT INTO donation_payments
+    (donation_id, project_id, amount, currency, stripe_payment_id,
+     refunded_amount, refunded_at, dispute_status, dispute_amount, paid_at, created_at)
+SELECT id, project_id, amount, currency, stripe_payment_id,
+       refunded_amount, refunded_at, dispute_status, dispute_amount, created_at, created_at
+FROM donations;
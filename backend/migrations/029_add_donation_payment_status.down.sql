@@ -0,0 +1,2 @@
+ALTER TABLE donations DROP COLUMN IF EXISTS payment_failed_at;
+ALTER TABLE donations DROP COLUMN IF EXISTS payment_status;
@@ -0,0 +1,3 @@
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS payment_status VARCHAR(20) NOT NULL DEFAULT 'active'
+    CHECK (payment_status IN ('active', 'past_due', 'unpaid'));
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS payment_failed_at TIMESTAMP WITH TIME ZONE;
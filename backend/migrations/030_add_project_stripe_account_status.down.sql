@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_projects_stripe_account_id;
+ALTER TABLE projects DROP COLUMN IF EXISTS frozen_by_stripe;
+ALTER TABLE projects DROP COLUMN IF EXISTS stripe_status_updated_at;
+ALTER TABLE projects DROP COLUMN IF EXISTS stripe_disabled_reason;
+ALTER TABLE projects DROP COLUMN IF EXISTS stripe_requirements;
+ALTER TABLE projects DROP COLUMN IF EXISTS stripe_payouts_enabled;
+ALTER TABLE projects DROP COLUMN IF EXISTS stripe_charges_enabled;
@@ -0,0 +1,10 @@
+-- 連結アカウントの決済・入金可否（account.updated Webhook で同期）。NULL は未同期
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS stripe_charges_enabled BOOLEAN;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS stripe_payouts_enabled BOOLEAN;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS stripe_requirements TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS stripe_disabled_reason VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS stripe_status_updated_at TIMESTAMP WITH TIME ZONE;
+-- 連結アカウントの停止で自動凍結したプロジェクト（再開時に active へ戻す）
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS frozen_by_stripe BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS idx_projects_stripe_account_id ON projects(stripe_account_id);
@@ -0,0 +1,22 @@
+DELETE FROM activities WHERE type = 'manual_donation';
+ALTER TABLE activities DROP CONSTRAINT IF EXISTS activities_type_check;
+ALTER TABLE activities ADD CONSTRAINT activities_type_check
+    CHECK (type IN ('donation', 'project_created', 'project_updated', 'milestone', 'refund', 'dispute'));
+
+DROP TABLE IF EXISTS donation_audit_logs;
+
+ALTER TABLE donation_payments DROP COLUMN IF EXISTS payment_method;
+
+DELETE FROM donation_payments WHERE donation_id IN (SELECT id FROM donations WHERE payment_method != 'stripe');
+DELETE FROM donations WHERE payment_method != 'stripe';
+ALTER TABLE donations DROP CONSTRAINT IF EXISTS donations_donor_type_check;
+ALTER TABLE donations ADD CONSTRAINT donations_donor_type_check
+    CHECK (donor_type IN ('token', 'user'));
+
+ALTER TABLE donations DROP COLUMN IF EXISTS void_reason;
+ALTER TABLE donations DROP COLUMN IF EXISTS voided_at;
+ALTER TABLE donations DROP COLUMN IF EXISTS recorded_by;
+ALTER TABLE donations DROP COLUMN IF EXISTS received_at;
+ALTER TABLE donations DROP COLUMN IF EXISTS donor_name;
+ALTER TABLE donations DROP COLUMN IF EXISTS reference;
+ALTER TABLE donations DROP COLUMN IF EXISTS payment_method;
@@ -0,0 +1,33 @@
+-- 銀行振込・現金など Stripe を経由しない寄付（オーナーまたはホストが手動で記録する）
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS payment_method VARCHAR(20) NOT NULL DEFAULT 'stripe'
+    CHECK (payment_method IN ('stripe', 'bank_transfer', 'cash', 'other'));
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS reference VARCHAR(100);
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS donor_name VARCHAR(100);
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS received_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS recorded_by VARCHAR(36) REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS voided_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS void_reason TEXT;
+
+-- 寄付者アカウントに紐付けない手動記録は donor_type = 'manual'
+ALTER TABLE donations DROP CONSTRAINT IF EXISTS donations_donor_type_check;
+ALTER TABLE donations ADD CONSTRAINT donations_donor_type_check
+    CHECK (donor_type IN ('token', 'user', 'manual'));
+
+ALTER TABLE donation_payments ADD COLUMN IF NOT EXISTS payment_method VARCHAR(20) NOT NULL DEFAULT 'stripe';
+
+-- 手動記録の寄付の作成・修正・取消の履歴
+CREATE TABLE IF NOT EXISTS donation_audit_logs (
+    id          VARCHAR(36) PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    donation_id VARCHAR(36) NOT NULL REFERENCES donations(id) ON DELETE CASCADE,
+    actor_id    VARCHAR(36) REFERENCES users(id) ON DELETE SET NULL,
+    action      VARCHAR(20) NOT NULL CHECK (action IN ('create', 'update', 'void')),
+    changes     JSONB NOT NULL DEFAULT '{}',
+    reason      TEXT,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_donation_audit_logs_donation_id ON donation_audit_logs(donation_id, created_at);
+
+ALTER TABLE activities DROP CONSTRAINT IF EXISTS activities_type_check;
+ALTER TABLE activities ADD CONSTRAINT activities_type_check
+    CHECK (type IN ('donation', 'project_created', 'project_updated', 'milestone', 'refund', 'dispute', 'manual_donation'));
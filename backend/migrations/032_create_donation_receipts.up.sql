@@ -0,0 +1,15 @@
+-- 寄付者向けの寄付記録（寄付ごと）と年間寄付記録（寄付者・年ごと）の発行履歴。
+-- 記録番号は初回発行時に採番し、再発行しても同じ番号を返す。
+CREATE TABLE IF NOT EXISTS donation_receipts (
+    id             VARCHAR(36) PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    receipt_number BIGSERIAL NOT NULL UNIQUE,
+    kind           VARCHAR(20) NOT NULL CHECK (kind IN ('donation', 'annual')),
+    user_id        VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    donation_id    VARCHAR(36) REFERENCES donations(id) ON DELETE SET NULL,
+    year           INTEGER,
+    issued_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK ((kind = 'donation' AND year IS NULL) OR (kind = 'annual' AND donation_id IS NULL AND year IS NOT NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_donation_receipts_donation ON donation_receipts(donation_id) WHERE kind = 'donation';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_donation_receipts_annual ON donation_receipts(user_id, year) WHERE kind = 'annual';
@@ -0,0 +1,7 @@
+DELETE FROM activities WHERE type = 'matching';
+ALTER TABLE activities DROP CONSTRAINT IF EXISTS activities_type_check;
+ALTER TABLE activities ADD CONSTRAINT activities_type_check
+    CHECK (type IN ('donation', 'project_created', 'project_updated', 'milestone', 'refund', 'dispute', 'manual_donation'));
+
+DROP TABLE IF EXISTS matching_contributions;
+DROP TABLE IF EXISTS matching_campaigns;
@@ -0,0 +1,41 @@
+-- マッチング寄付キャンペーン: スポンサーが期間中の寄付に比率（ratio_percent、100 = 同額）を掛けた額を上限まで上乗せする
+CREATE TABLE IF NOT EXISTS matching_campaigns (
+    id             VARCHAR(36) PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    project_id     VARCHAR(36) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    sponsor_name   VARCHAR(100) NOT NULL,
+    sponsor_email  VARCHAR(255),
+    ratio_percent  INTEGER NOT NULL DEFAULT 100 CHECK (ratio_percent > 0 AND ratio_percent <= 1000),
+    cap_amount     INTEGER NOT NULL CHECK (cap_amount > 0),
+    currency       VARCHAR(3) NOT NULL DEFAULT 'jpy',
+    starts_at      TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at        TIMESTAMP WITH TIME ZONE NOT NULL,
+    matched_amount INTEGER NOT NULL DEFAULT 0,
+    summary_token  VARCHAR(64) NOT NULL,
+    created_by     VARCHAR(36) REFERENCES users(id) ON DELETE SET NULL,
+    cancelled_at   TIMESTAMP WITH TIME ZONE,
+    closed_at      TIMESTAMP WITH TIME ZONE,
+    created_at     TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (ends_at > starts_at),
+    CHECK (matched_amount >= 0 AND matched_amount <= cap_amount)
+);
+
+CREATE INDEX IF NOT EXISTS idx_matching_campaigns_project ON matching_campaigns(project_id, starts_at, ends_at);
+
+-- 入金ごとの上乗せ額（同じ入金を二重に数えない）
+CREATE TABLE IF NOT EXISTS matching_contributions (
+    id              VARCHAR(36) PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    campaign_id     VARCHAR(36) NOT NULL REFERENCES matching_campaigns(id) ON DELETE CASCADE,
+    payment_id      VARCHAR(36) REFERENCES donation_payments(id) ON DELETE SET NULL,
+    donation_amount INTEGER NOT NULL,
+    matched_amount  INTEGER NOT NULL CHECK (matched_amount > 0),
+    paid_at         TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (campaign_id, payment_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_matching_contributions_campaign ON matching_contributions(campaign_id, paid_at);
+
+ALTER TABLE activities DROP CONSTRAINT IF EXISTS activities_type_check;
+ALTER TABLE activities ADD CONSTRAINT activities_type_check
+    CHECK (type IN ('donation', 'project_created', 'project_updated', 'milestone', 'refund', 'dispute', 'manual_donation', 'matching'));
@@ -0,0 +1,2 @@
+ALTER TABLE donation_payments DROP COLUMN IF EXISTS interval_months;
+ALTER TABLE donations DROP COLUMN IF EXISTS billing_interval;
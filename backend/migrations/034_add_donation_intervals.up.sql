@@ -0,0 +1,7 @@
+-- 定期寄付の請求間隔（月・四半期・半年・年）。集計では入金額をカバーする月数で割って月額に換算する
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS billing_interval VARCHAR(10) NOT NULL DEFAULT 'month'
+    CHECK (billing_interval IN ('month', 'quarter', 'half_year', 'year'));
+
+-- 1 回の入金がカバーする月数（年払いは 12）
+ALTER TABLE donation_payments ADD COLUMN IF NOT EXISTS interval_months SMALLINT NOT NULL DEFAULT 1
+    CHECK (interval_months IN (1, 3, 6, 12));
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_donations_project_tier;
+ALTER TABLE donations DROP COLUMN IF EXISTS tier_id;
+ALTER TABLE projects DROP COLUMN IF EXISTS donation_tiers;
@@ -0,0 +1,6 @@
+-- オーナーが設定する寄付の目安額（ラベル・金額・単発/定期・説明）。cost_items と同じくプロジェクトに JSONB で持つ
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS donation_tiers JSONB NOT NULL DEFAULT '[]'::jsonb;
+
+-- 目安額から寄付した場合の tier ID（オーナー向けの内訳で段階ごとに集計する）
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS tier_id VARCHAR(32);
+CREATE INDEX IF NOT EXISTS idx_donations_project_tier ON donations(project_id, tier_id) WHERE tier_id IS NOT NULL;
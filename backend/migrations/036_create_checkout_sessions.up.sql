@@ -0,0 +1,21 @@
+-- 作成した Stripe Checkout Session（寄付の開始）。決済完了・期限切れは Webhook で更新する。
+-- 放棄率（期限切れ / (完了 + 期限切れ)）をプロジェクトごとに集計する。
+CREATE TABLE IF NOT EXISTS checkout_sessions (
+    id                     VARCHAR(255) PRIMARY KEY, -- Stripe の cs_...
+    project_id             VARCHAR(36) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    donor_type             VARCHAR(10) NOT NULL CHECK (donor_type IN ('token', 'user')),
+    donor_id               VARCHAR(36) NOT NULL,
+    amount                 INTEGER NOT NULL CHECK (amount > 0),
+    currency               VARCHAR(3) NOT NULL DEFAULT 'jpy',
+    is_recurring           BOOLEAN NOT NULL DEFAULT false,
+    billing_interval       VARCHAR(20) NOT NULL DEFAULT 'month',
+    tier_id                VARCHAR(32),
+    status                 VARCHAR(10) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'complete', 'expired')),
+    stripe_payment_id      TEXT,
+    stripe_subscription_id TEXT,
+    created_at             TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at           TIMESTAMP WITH TIME ZONE,
+    expired_at             TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_checkout_sessions_project ON checkout_sessions(project_id, created_at);
@@ -0,0 +1,2 @@
+ALTER TABLE donation_payments DROP COLUMN IF EXISTS charged_amount;
+ALTER TABLE donations DROP COLUMN IF EXISTS charged_amount;
@@ -0,0 +1,4 @@
+-- 寄付者が決済手数料を負担した場合の請求額（上乗せ後）。amount はプロジェクトに届く寄付額のまま。
+-- NULL は手数料を上乗せしていない（請求額 = amount）。手動記録の寄付も NULL
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS charged_amount INTEGER;
+ALTER TABLE donation_payments ADD COLUMN IF NOT EXISTS charged_amount INTEGER;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS stripe_payouts;
+ALTER TABLE donation_payments DROP COLUMN IF EXISTS stripe_balance_transaction_id;
+ALTER TABLE donation_payments DROP COLUMN IF EXISTS stripe_net;
+ALTER TABLE donation_payments DROP COLUMN IF EXISTS stripe_fee;
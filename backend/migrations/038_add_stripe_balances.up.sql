@@ -0,0 +1,19 @@
+-- Stripe の残高取引（balance transaction）から取得した決済ごとの手数料と入金額。
+-- NULL はまだ取得していない（finance-sync で埋まる）
+ALTER TABLE donation_payments ADD COLUMN IF NOT EXISTS stripe_fee INTEGER;
+ALTER TABLE donation_payments ADD COLUMN IF NOT EXISTS stripe_net INTEGER;
+ALTER TABLE donation_payments ADD COLUMN IF NOT EXISTS stripe_balance_transaction_id TEXT;
+
+-- 連結アカウントから銀行口座への入金（payout）。プロジェクトとは stripe_account_id で紐づく
+CREATE TABLE IF NOT EXISTS stripe_payouts (
+    id                VARCHAR(255) PRIMARY KEY, -- Stripe の po_...
+    stripe_account_id VARCHAR(255) NOT NULL,
+    amount            INTEGER NOT NULL,
+    currency          VARCHAR(3) NOT NULL DEFAULT 'jpy',
+    status            VARCHAR(20) NOT NULL,
+    arrival_date      TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_stripe_payouts_account ON stripe_payouts(stripe_account_id, arrival_date);
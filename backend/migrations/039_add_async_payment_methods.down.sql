@@ -0,0 +1,7 @@
+-- 入金されていないコンビニ・銀行振込の寄付は未払いとして残す
+UPDATE donations SET payment_status = 'unpaid' WHERE payment_status IN ('pending', 'failed');
+ALTER TABLE donations DROP CONSTRAINT IF EXISTS donations_payment_status_check;
+ALTER TABLE donations ADD CONSTRAINT donations_payment_status_check
+    CHECK (payment_status IN ('active', 'past_due', 'unpaid'));
+
+ALTER TABLE projects DROP COLUMN IF EXISTS payment_method_types;
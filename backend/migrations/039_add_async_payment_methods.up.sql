@@ -0,0 +1,7 @@
+-- Checkout で受け付ける支払い方法（Stripe の payment_method_types。空ならカードのみ）
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS payment_method_types TEXT[] NOT NULL DEFAULT '{}';
+
+-- コンビニ・銀行振込の寄付は入金まで pending、期限までに入金されなければ failed
+ALTER TABLE donations DROP CONSTRAINT IF EXISTS donations_payment_status_check;
+ALTER TABLE donations ADD CONSTRAINT donations_payment_status_check
+    CHECK (payment_status IN ('active', 'past_due', 'unpaid', 'pending', 'failed'));
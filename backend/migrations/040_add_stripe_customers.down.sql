@@ -0,0 +1,2 @@
+ALTER TABLE donations DROP COLUMN IF EXISTS cancel_at;
+ALTER TABLE users DROP COLUMN IF EXISTS stripe_customer_id;
@@ -0,0 +1,5 @@
+-- プラットフォームアカウント上の Stripe Customer（カスタマーポータルで定期寄付を管理するため）
+ALTER TABLE users ADD COLUMN IF NOT EXISTS stripe_customer_id TEXT UNIQUE;
+
+-- カスタマーポータル等で「期間終了時に解約」にした定期寄付の解約予定日時
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS cancel_at TIMESTAMPTZ;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS donation_changes;
@@ -0,0 +1,13 @@
+-- 定期寄付の金額・一時停止・解約予定・決済状態の変更履歴。
+-- 寄付者の PATCH に加え、Stripe ダッシュボード・Customer Portal・Stripe 自身による変更（Webhook）も記録する
+CREATE TABLE IF NOT EXISTS donation_changes (
+    id              VARCHAR(36) PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    donation_id     VARCHAR(36) NOT NULL REFERENCES donations(id) ON DELETE CASCADE,
+    source          VARCHAR(20) NOT NULL CHECK (source IN ('donor', 'stripe', 'stripe_automatic')),
+    event_type      VARCHAR(100),
+    stripe_event_id VARCHAR(255) UNIQUE, -- 同じ Webhook イベントを二重に記録しない
+    changes         JSONB NOT NULL DEFAULT '{}',
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_donation_changes_donation_id ON donation_changes(donation_id, created_at);
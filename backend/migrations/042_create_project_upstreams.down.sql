@@ -0,0 +1,7 @@
+DELETE FROM activities WHERE type IN ('pass_through_in', 'pass_through_out');
+ALTER TABLE activities DROP CONSTRAINT IF EXISTS activities_type_check;
+ALTER TABLE activities ADD CONSTRAINT activities_type_check
+    CHECK (type IN ('donation', 'project_created', 'project_updated', 'milestone', 'refund', 'dispute', 'manual_donation', 'matching'));
+
+DROP TABLE IF EXISTS pass_throughs;
+DROP TABLE IF EXISTS project_upstreams;
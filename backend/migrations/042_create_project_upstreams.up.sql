@@ -0,0 +1,40 @@
+-- 上流プロジェクト: プロジェクトが依存しているプロジェクトと、受け取った寄付から分配する割合
+CREATE TABLE IF NOT EXISTS project_upstreams (
+    project_id          VARCHAR(36) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    upstream_project_id VARCHAR(36) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    share_percent       INTEGER NOT NULL CHECK (share_percent > 0 AND share_percent <= 100),
+    created_at          TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (project_id, upstream_project_id),
+    CHECK (project_id <> upstream_project_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_upstreams_upstream ON project_upstreams(upstream_project_id);
+
+-- 入金ごとの上流プロジェクトへの分配（同じ入金を二重に分配しない）。
+-- Stripe の送金は status で追跡し、失敗したものは cmd/pass-through-transfers で再試行する
+CREATE TABLE IF NOT EXISTS pass_throughs (
+    id                 VARCHAR(36) PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    payment_id         VARCHAR(36) REFERENCES donation_payments(id) ON DELETE SET NULL,
+    from_project_id    VARCHAR(36) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    to_project_id      VARCHAR(36) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    amount             INTEGER NOT NULL CHECK (amount > 0),
+    currency           VARCHAR(3) NOT NULL DEFAULT 'jpy',
+    share_percent      INTEGER NOT NULL,
+    status             VARCHAR(20) NOT NULL DEFAULT 'pending'
+                       CHECK (status IN ('pending', 'transferred', 'failed')),
+    stripe_transfer_id VARCHAR(255),
+    last_error         TEXT,
+    paid_at            TIMESTAMP WITH TIME ZONE NOT NULL,
+    transferred_at     TIMESTAMP WITH TIME ZONE,
+    created_at         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (payment_id, to_project_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pass_throughs_from ON pass_throughs(from_project_id, paid_at);
+CREATE INDEX IF NOT EXISTS idx_pass_throughs_to ON pass_throughs(to_project_id, paid_at);
+CREATE INDEX IF NOT EXISTS idx_pass_throughs_untransferred ON pass_throughs(created_at) WHERE status <> 'transferred';
+
+ALTER TABLE activities DROP CONSTRAINT IF EXISTS activities_type_check;
+ALTER TABLE activities ADD CONSTRAINT activities_type_check
+    CHECK (type IN ('donation', 'project_created', 'project_updated', 'milestone', 'refund', 'dispute', 'manual_donation', 'matching', 'pass_through_in', 'pass_through_out'));
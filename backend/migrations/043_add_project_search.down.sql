@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_projects_search_vector;
+ALTER TABLE projects DROP COLUMN IF EXISTS search_vector;
+DROP FUNCTION IF EXISTS search_query(TEXT);
+DROP FUNCTION IF EXISTS search_tokens(TEXT);
+DROP FUNCTION IF EXISTS search_normalize(TEXT);
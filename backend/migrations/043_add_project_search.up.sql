@@ -0,0 +1,72 @@
+-- プロジェクトの全文検索（名前・概要・説明）。
+-- pg_trgm と既定のパーサーは C ロケールで日本語を単語として扱わないため、検索用のトークン列を自前で作る:
+-- 日本語（かな・漢字）は連続部分を 2 文字ずつ（bigram）に分け、末尾の 1 文字も加える（1 文字の検索語を前方一致で引けるように）。
+-- 英数字は単語単位。全角英数字は半角にそろえて小文字にする。
+
+CREATE OR REPLACE FUNCTION search_normalize(input TEXT) RETURNS TEXT
+LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE AS $$
+    SELECT lower(translate(input,
+        '０１２３４５６７８９ＡＢＣＤＥＦＧＨＩＪＫＬＭＮＯＰＱＲＳＴＵＶＷＸＹＺａｂｃｄｅｆｇｈｉｊｋｌｍｎｏｐｑｒｓｔｕｖｗｘｙｚ',
+        '0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz'))
+$$;
+
+-- search_tokens は文書側のトークン列（空白区切り）を返す
+CREATE OR REPLACE FUNCTION search_tokens(input TEXT) RETURNS TEXT
+LANGUAGE plpgsql IMMUTABLE STRICT PARALLEL SAFE AS $$
+DECLARE
+    s      TEXT := search_normalize(input);
+    run    TEXT;
+    tokens TEXT[] := '{}';
+BEGIN
+    FOR run IN SELECT m[1] FROM regexp_matches(s, '[々-〇ぁ-ヿ㐀-䶿一-鿿豈-﫿]+', 'g') AS m LOOP
+        FOR i IN 1 .. char_length(run) - 1 LOOP
+            tokens := tokens || substr(run, i, 2);
+        END LOOP;
+        tokens := tokens || substr(run, char_length(run), 1);
+    END LOOP;
+    FOR run IN SELECT m[1] FROM regexp_matches(s, '[a-z0-9À-ɏ]+', 'g') AS m LOOP
+        tokens := tokens || run;
+    END LOOP;
+    RETURN array_to_string(tokens, ' ');
+END
+$$;
+
+-- search_query は検索語を tsquery にする。日本語は bigram を隣接（<->）で結び、1 文字なら前方一致。
+-- 英数字の単語は前方一致。語どうしは AND。検索できる文字がなければ NULL（何にも一致しない）
+CREATE OR REPLACE FUNCTION search_query(input TEXT) RETURNS tsquery
+LANGUAGE plpgsql IMMUTABLE STRICT PARALLEL SAFE AS $$
+DECLARE
+    s       TEXT := search_normalize(input);
+    run     TEXT;
+    bigrams TEXT[];
+    terms   TEXT[] := '{}';
+BEGIN
+    FOR run IN SELECT m[1] FROM regexp_matches(s, '[々-〇ぁ-ヿ㐀-䶿一-鿿豈-﫿]+', 'g') AS m LOOP
+        IF char_length(run) = 1 THEN
+            terms := terms || (run || ':*');
+        ELSE
+            bigrams := '{}';
+            FOR i IN 1 .. char_length(run) - 1 LOOP
+                bigrams := bigrams || substr(run, i, 2);
+            END LOOP;
+            terms := terms || ('(' || array_to_string(bigrams, ' <-> ') || ')');
+        END IF;
+    END LOOP;
+    FOR run IN SELECT m[1] FROM regexp_matches(s, '[a-z0-9À-ɏ]+', 'g') AS m LOOP
+        terms := terms || (run || ':*');
+    END LOOP;
+    IF cardinality(terms) = 0 THEN
+        RETURN NULL;
+    END IF;
+    RETURN to_tsquery('simple', array_to_string(terms, ' & '));
+END
+$$;
+
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', search_tokens(name)), 'A') ||
+        setweight(to_tsvector('simple', search_tokens(overview)), 'B') ||
+        setweight(to_tsvector('simple', search_tokens(description)), 'C')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_projects_search_vector ON projects USING GIN (search_vector);
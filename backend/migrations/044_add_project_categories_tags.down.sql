@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_projects_tags;
+DROP INDEX IF EXISTS idx_projects_category;
+ALTER TABLE projects DROP COLUMN IF EXISTS tags;
+ALTER TABLE projects DROP COLUMN IF EXISTS category;
+DROP TABLE IF EXISTS project_categories;
@@ -0,0 +1,24 @@
+-- プロジェクトのカテゴリ（ホストが管理）とタグ（オーナーが自由に付ける）
+CREATE TABLE IF NOT EXISTS project_categories (
+    slug       VARCHAR(50) PRIMARY KEY CHECK (slug ~ '^[a-z0-9]+(-[a-z0-9]+)*$'),
+    name       VARCHAR(100) NOT NULL,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO project_categories (slug, name, sort_order) VALUES
+    ('oss-tools', 'OSS・開発ツール', 10),
+    ('games', 'ゲーム', 20),
+    ('community', 'コミュニティ', 30),
+    ('education', '教育', 40)
+ON CONFLICT (slug) DO NOTHING;
+
+-- カテゴリを削除したプロジェクトは未分類に戻す
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS category VARCHAR(50)
+    REFERENCES project_categories(slug) ON DELETE SET NULL;
+-- タグは正規化済み（小文字・前後の空白と # を除く）の文字列
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_projects_category ON projects(category) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_projects_tags ON projects USING GIN (tags);
@@ -0,0 +1,39 @@
+-- プロジェクトのメンバー（owner / maintainer / editor）。
+-- 作成者（projects.owner_id）は常に owner として登録され、外したり降格したりできない
+CREATE TABLE IF NOT EXISTS project_members (
+    project_id VARCHAR(36) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    user_id    VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role       VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'maintainer', 'editor')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (project_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_members_user ON project_members(user_id);
+
+INSERT INTO project_members (project_id, user_id, role, created_at)
+SELECT id, owner_id, 'owner', created_at FROM projects
+ON CONFLICT (project_id, user_id) DO NOTHING;
+
+-- メンバーへの招待。メールアドレスかユーザー ID のどちらか一方で相手を指定する。
+-- 保留中の招待は同じ相手に 1 件まで
+CREATE TABLE IF NOT EXISTS project_invitations (
+    id           VARCHAR(36) PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    project_id   VARCHAR(36) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    email        VARCHAR(255),
+    user_id      VARCHAR(36) REFERENCES users(id) ON DELETE CASCADE,
+    role         VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'maintainer', 'editor')),
+    invited_by   VARCHAR(36) REFERENCES users(id) ON DELETE SET NULL,
+    status       VARCHAR(20) NOT NULL DEFAULT 'pending'
+                 CHECK (status IN ('pending', 'accepted', 'declined', 'revoked')),
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    responded_at TIMESTAMP WITH TIME ZONE,
+    CHECK ((email IS NULL) <> (user_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_project_invitations_pending_email
+    ON project_invitations(project_id, lower(email)) WHERE status = 'pending' AND email IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_project_invitations_pending_user
+    ON project_invitations(project_id, user_id) WHERE status = 'pending' AND user_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_project_invitations_email ON project_invitations(lower(email)) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_project_invitations_user ON project_invitations(user_id) WHERE status = 'pending';
@@ -0,0 +1,14 @@
+-- プロジェクトの URL 用スラッグ（英小文字・数字・ハイフン。任意）。
+-- URL の {id} には UUID の代わりにスラッグを使える
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS slug VARCHAR(60)
+    CHECK (slug ~ '^[a-z0-9]+(-[a-z0-9]+)*$');
+CREATE UNIQUE INDEX IF NOT EXISTS idx_projects_slug ON projects(slug);
+
+-- 変更前のスラッグ。古い URL から現在のスラッグへリダイレクトし、他のプロジェクトには使わせない
+CREATE TABLE IF NOT EXISTS project_slug_redirects (
+    slug       VARCHAR(60) PRIMARY KEY,
+    project_id VARCHAR(36) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_slug_redirects_project ON project_slug_redirects(project_id);
@@ -0,0 +1,14 @@
+-- プロジェクトの更新履歴（誰が・いつ・どの項目を変えたか）。寄付者が予算の推移を確認できるよう公開する。
+-- snapshot は更新後の編集可能な項目で、過去の内容への復元に使う
+CREATE TABLE IF NOT EXISTS project_revisions (
+    id            VARCHAR(36) PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    project_id    VARCHAR(36) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    actor_id      VARCHAR(36) REFERENCES users(id) ON DELETE SET NULL,
+    action        VARCHAR(20) NOT NULL CHECK (action IN ('update', 'restore')),
+    changes       JSONB NOT NULL DEFAULT '{}',
+    snapshot      JSONB NOT NULL,
+    restored_from VARCHAR(36) REFERENCES project_revisions(id) ON DELETE SET NULL,
+    created_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_revisions_project_id ON project_revisions(project_id, created_at);
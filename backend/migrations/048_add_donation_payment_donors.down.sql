@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_donation_payments_donor;
+ALTER TABLE donation_payments DROP COLUMN IF EXISTS donor_id;
+ALTER TABLE donation_payments DROP COLUMN IF EXISTS donor_type;
@@ -0,0 +1,12 @@
+-- 入金ごとの寄付者。寄付（申込み）が削除されて donation_id が NULL になっても、
+-- 年間の寄付記録に入金を残せるよう記録時点の寄付者を持たせる
+ALTER TABLE donation_payments ADD COLUMN IF NOT EXISTS donor_type VARCHAR(10);
+ALTER TABLE donation_payments ADD COLUMN IF NOT EXISTS donor_id VARCHAR(64);
+
+-- 既存の入金は寄付から移す（すでに寄付が削除された入金の寄付者は復元できない）
+UPDATE donation_payments p
+SET donor_type = d.donor_type, donor_id = d.donor_id
+FROM donations d
+WHERE d.id = p.donation_id AND p.donor_type IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_donation_payments_donor ON donation_payments(donor_type, donor_id, paid_at);
@@ -0,0 +1,6 @@
+ALTER TABLE matching_contributions DROP COLUMN IF EXISTS original_matched_amount;
+
+DELETE FROM matching_contributions WHERE matched_amount = 0;
+ALTER TABLE matching_contributions DROP CONSTRAINT IF EXISTS matching_contributions_matched_amount_check;
+ALTER TABLE matching_contributions ADD CONSTRAINT matching_contributions_matched_amount_check
+    CHECK (matched_amount > 0);
@@ -0,0 +1,10 @@
+-- 返金・係争の敗訴で入金が減った分だけ上乗せを減らし、キャンペーンの上限に戻す。
+-- 全額返金された入金の上乗せは 0 として残す（再送で上乗せし直さないため）
+ALTER TABLE matching_contributions DROP CONSTRAINT IF EXISTS matching_contributions_matched_amount_check;
+ALTER TABLE matching_contributions ADD CONSTRAINT matching_contributions_matched_amount_check
+    CHECK (matched_amount >= 0);
+
+-- 入金全額に対する当初の上乗せ額（減額はここから入金の残額に比例して計算する）
+ALTER TABLE matching_contributions ADD COLUMN IF NOT EXISTS original_matched_amount INTEGER;
+UPDATE matching_contributions SET original_matched_amount = matched_amount WHERE original_matched_amount IS NULL;
+ALTER TABLE matching_contributions ALTER COLUMN original_matched_amount SET NOT NULL;
@@ -0,0 +1,9 @@
+-- 連結アカウント上のユーザーの Stripe Customer（Customer はアカウントごとに存在する）。
+-- プラットフォームアカウント上の Customer は users.stripe_customer_id に持つ
+CREATE TABLE IF NOT EXISTS stripe_customers (
+    user_id           VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    stripe_account_id VARCHAR(255) NOT NULL,
+    customer_id       TEXT NOT NULL UNIQUE,
+    created_at        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, stripe_account_id)
+);
@@ -0,0 +1,4 @@
+DELETE FROM project_revisions WHERE action = 'create';
+ALTER TABLE project_revisions DROP CONSTRAINT IF EXISTS project_revisions_action_check;
+ALTER TABLE project_revisions ADD CONSTRAINT project_revisions_action_check
+    CHECK (action IN ('update', 'restore'));
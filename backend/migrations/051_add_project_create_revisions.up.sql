@@ -0,0 +1,38 @@
+-- 作成時の内容を更新履歴（action = 'create'）として残し、作成時の予算に戻せるようにする
+ALTER TABLE project_revisions DROP CONSTRAINT IF EXISTS project_revisions_action_check;
+ALTER TABLE project_revisions ADD CONSTRAINT project_revisions_action_check
+    CHECK (action IN ('create', 'update', 'restore'));
+
+-- 既存のプロジェクトは作成時の内容を復元する:
+-- 更新履歴があれば最初の履歴の snapshot に変更前（from）の値を重ね、なければ今の内容を使う
+INSERT INTO project_revisions (project_id, actor_id, action, snapshot, created_at)
+SELECT p.id, p.owner_id, 'create',
+       COALESCE(
+           first_rev.snapshot || COALESCE((SELECT jsonb_object_agg(c.key, c.value -> 'from') FROM jsonb_each(first_rev.changes) c), '{}'::jsonb),
+           jsonb_build_object(
+               'name', p.name,
+               'description', p.description,
+               'overview', p.overview,
+               'share_message', p.share_message,
+               'deadline', p.deadline,
+               'owner_want_monthly', p.owner_want_monthly,
+               'monthly_target', p.monthly_target,
+               'cost_items', NULLIF(p.cost_items, '[]'::jsonb),
+               'donation_tiers', NULLIF(p.donation_tiers, '[]'::jsonb),
+               'payment_method_types', NULLIF(to_jsonb(p.payment_method_types), '[]'::jsonb),
+               'category', COALESCE(p.category, ''),
+               'tags', NULLIF(to_jsonb(p.tags), '[]'::jsonb),
+               'slug', COALESCE(p.slug, '')
+           )
+       ),
+       p.created_at
+FROM projects p
+LEFT JOIN LATERAL (
+    SELECT v.snapshot, v.changes FROM project_revisions v
+    WHERE v.project_id = p.id
+    ORDER BY v.created_at, v.id
+    LIMIT 1
+) first_rev ON TRUE
+WHERE NOT EXISTS (
+    SELECT 1 FROM project_revisions v WHERE v.project_id = p.id AND v.action = 'create'
+);
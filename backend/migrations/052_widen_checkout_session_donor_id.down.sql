@@ -0,0 +1 @@
+ALTER TABLE checkout_sessions ALTER COLUMN donor_id TYPE VARCHAR(36);
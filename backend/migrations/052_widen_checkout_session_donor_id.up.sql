@@ -0,0 +1,2 @@
+-- donor_token（32 バイトの base64 = 44 文字）が入るよう donations.donor_id（024）と同じ幅にする
+ALTER TABLE checkout_sessions ALTER COLUMN donor_id TYPE VARCHAR(64);
@@ -0,0 +1,183 @@
+// Package pdf はテキストと罫線だけの簡単な PDF（A4 縦）を書き出す。
+//
+// 日本語を含む文字列を扱うため、フォントは Adobe-Japan1 の HeiseiKakuGo-W5
+// （エンコーディング UniJIS-UCS2-H）を埋め込まずに参照する。PDF ビューアが
+// 内蔵の日本語フォントで代替表示するので、フォントファイルを同梱する必要がない。
+// BMP 外の文字（絵文字など）は "?" として描画する。
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// A4 縦のページサイズ（pt）
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Document は書き出し前の PDF 文書
+type Document struct {
+	title string
+	pages []*Page
+}
+
+// Page は 1 ページ分の描画内容。座標の原点はページ左下（pt）。
+type Page struct {
+	content bytes.Buffer
+}
+
+// New は文書を作成する。title は文書情報（ビューアのタイトル欄）に入る。
+func New(title string) *Document {
+	return &Document{title: title}
+}
+
+// AddPage はページを末尾に追加して返す
+func (d *Document) AddPage() *Page {
+	p := &Page{}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Text は (x, y) をベースラインの左端として s を size pt で描画する
+func (p *Page) Text(x, y, size float64, s string) {
+	fmt.Fprintf(&p.content, "BT /F1 %s Tf %s %s Td <%s> Tj ET\n", num(size), num(x), num(y), encodeText(s))
+}
+
+// Line は (x1, y1) から (x2, y2) まで太さ width pt の直線を描画する
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n", num(width), num(x1), num(y1), num(x2), num(y2))
+}
+
+// TextWidth は s を size pt で描画したときの幅を返す（半角は 0.5em、それ以外は 1em）
+func TextWidth(s string, size float64) float64 {
+	var em float64
+	for _, r := range s {
+		if isHalfWidth(r) {
+			em += 0.5
+		} else {
+			em++
+		}
+	}
+	return em * size
+}
+
+// isHalfWidth は UniJIS-UCS2-H で半角グリフ（CID 1-95, 327-389）に対応する文字か（¥ は CID 61）
+func isHalfWidth(r rune) bool {
+	return (r >= 0x20 && r <= 0x7e) || r == 0xa5 || (r >= 0xff61 && r <= 0xff9f)
+}
+
+// encodeText は s を UniJIS-UCS2-H の文字コード（UTF-16BE、BMP のみ）の 16 進文字列にする
+func encodeText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\t' || r == '\n' || r == '\r':
+			r = ' '
+		case r < 0x20:
+			continue
+		case r > 0xffff || utf16.IsSurrogate(r):
+			r = '?'
+		}
+		fmt.Fprintf(&b, "%04X", r)
+	}
+	return b.String()
+}
+
+// encodeTextString は文書情報用の PDF テキスト文字列（BOM 付き UTF-16BE）を返す
+func encodeTextString(s string) string {
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, u := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", u)
+	}
+	b.WriteString(">")
+	return b.String()
+}
+
+func num(v float64) string {
+	s := fmt.Sprintf("%.2f", v)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "" || s == "-" {
+		return "0"
+	}
+	return s
+}
+
+// 固定のオブジェクト番号。ページ i（0 始まり）は firstPageObj+2i、その内容は firstPageObj+2i+1。
+const (
+	catalogObj = 1 + iota
+	pagesObj
+	fontObj
+	cidFontObj
+	fontDescriptorObj
+	infoObj
+	firstPageObj
+)
+
+// WriteTo は文書を PDF として w に書き出す
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	pages := d.pages
+	if len(pages) == 0 {
+		pages = []*Page{{}}
+	}
+
+	var buf bytes.Buffer
+	offsets := make([]int, firstPageObj+2*len(pages))
+	obj := func(n int, body string) {
+		offsets[n] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", n, body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	obj(catalogObj, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesObj))
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPageObj+2*i)
+	}
+	obj(pagesObj, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %s %s] >>",
+		strings.Join(kids, " "), len(pages), num(PageWidth), num(PageHeight)))
+
+	obj(fontObj, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /HeiseiKakuGo-W5 /Encoding /UniJIS-UCS2-H /DescendantFonts [%d 0 R] >>", cidFontObj))
+	obj(cidFontObj, fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType0 /BaseFont /HeiseiKakuGo-W5"+
+		" /CIDSystemInfo << /Registry (Adobe) /Ordering (Japan1) /Supplement 2 >>"+
+		" /FontDescriptor %d 0 R /DW 1000 /W [1 95 500 327 389 500] >>", fontDescriptorObj))
+	obj(fontDescriptorObj, "<< /Type /FontDescriptor /FontName /HeiseiKakuGo-W5 /Flags 4"+
+		" /FontBBox [-92 -250 1010 922] /ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 700 /StemV 80 >>")
+	obj(infoObj, fmt.Sprintf("<< /Title %s /Producer (GIVErS) >>", encodeTextString(d.title)))
+
+	for i, p := range pages {
+		var z bytes.Buffer
+		zw := zlib.NewWriter(&z)
+		if _, err := zw.Write(p.content.Bytes()); err != nil {
+			return 0, err
+		}
+		if err := zw.Close(); err != nil {
+			return 0, err
+		}
+		pageObj := firstPageObj + 2*i
+		obj(pageObj, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /Resources << /Font << /F1 %d 0 R >> >> /Contents %d 0 R >>",
+			pagesObj, fontObj, pageObj+1))
+		offsets[pageObj+1] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", pageObj+1, z.Len())
+		buf.Write(z.Bytes())
+		buf.WriteString("\nendstream\nendobj\n")
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets))
+	for _, off := range offsets[1:] {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(offsets), catalogObj, infoObj, xref)
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
@@ -0,0 +1,97 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestEncodeText(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"A1", "00410031"},
+		{"寄付", "5BC44ED8"},
+		{"a\tb", "00610020" + "0062"},
+		{"😀", "003F"},
+		{"x\x00", "0078"},
+	}
+	for _, tt := range tests {
+		if got := encodeText(tt.in); got != tt.want {
+			t.Errorf("encodeText(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	if got := TextWidth("ab", 10); got != 10 {
+		t.Errorf("half-width = %v, want 10", got)
+	}
+	if got := TextWidth("寄付1", 10); got != 25 {
+		t.Errorf("mixed = %v, want 25", got)
+	}
+}
+
+func TestDocument_WriteTo(t *testing.T) {
+	doc := New("寄付記録")
+	p := doc.AddPage()
+	p.Text(50, 800, 12, "寄付記録 No. 1")
+	p.Line(50, 790, 545, 790, 0.5)
+	doc.AddPage().Text(50, 800, 12, "2")
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	out := buf.Bytes()
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Fatal("missing PDF header or trailer")
+	}
+	if !bytes.Contains(out, []byte("/Count 2")) {
+		t.Error("expected 2 pages")
+	}
+
+	// xref の各オフセットが "N 0 obj" を指していること
+	start := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	if start == nil {
+		t.Fatal("startxref not found")
+	}
+	xref, _ := strconv.Atoi(string(start[1]))
+	if !bytes.HasPrefix(out[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at xref table", xref)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xref:], -1)
+	if len(entries) != 10 {
+		t.Fatalf("xref entries = %d, want 10", len(entries))
+	}
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !bytes.HasPrefix(out[off:], []byte(want)) {
+			t.Errorf("object %d: offset %d points at %q", i+1, off, out[off:off+10])
+		}
+	}
+
+	// 1 ページ目の内容ストリームに文字列が 16 進で入っていること
+	m := regexp.MustCompile(`(?s)/Length (\d+) /Filter /FlateDecode >>\nstream\n`).FindSubmatchIndex(out)
+	if m == nil {
+		t.Fatal("content stream not found")
+	}
+	length, _ := strconv.Atoi(string(out[m[2]:m[3]]))
+	zr, err := zlib.NewReader(bytes.NewReader(out[m[1] : m[1]+length]))
+	if err != nil {
+		t.Fatalf("zlib: %v", err)
+	}
+	content, _ := io.ReadAll(zr)
+	if !strings.Contains(string(content), "<"+encodeText("寄付記録 No. 1")+"> Tj") {
+		t.Errorf("content = %s", content)
+	}
+	if !strings.Contains(string(content), "0.5 w 50 790 m 545 790 l S") {
+		t.Errorf("line not drawn: %s", content)
+	}
+}
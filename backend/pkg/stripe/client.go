@@ -3,7 +3,6 @@
 package stripe
 
 import (
-	"bytes"
 	"context"
 	"crypto/hmac"
 	"crypto/sha256"
@@ -28,19 +27,94 @@ type CreateAccountParams struct {
 	Country     string // "jp", "us" など
 }
 
+// AccountStatus は連結アカウントの決済・入金の可否と未提出の要件
+type AccountStatus struct {
+	ChargesEnabled bool
+	PayoutsEnabled bool
+	Requirements   []string // requirements.past_due と currently_due（重複なし）
+	DisabledReason string   // requirements.disabled_reason（例: "requirements.past_due"）
+}
+
+// AccountRequirements は v1 Account の requirements
+type AccountRequirements struct {
+	CurrentlyDue   []string `json:"currently_due"`
+	PastDue        []string `json:"past_due"`
+	DisabledReason string   `json:"disabled_reason"`
+}
+
+// newAccountStatus は v1 Account の charges_enabled / payouts_enabled / requirements から AccountStatus を作る
+func newAccountStatus(chargesEnabled, payoutsEnabled bool, req *AccountRequirements) AccountStatus {
+	st := AccountStatus{ChargesEnabled: chargesEnabled, PayoutsEnabled: payoutsEnabled, Requirements: []string{}}
+	if req == nil {
+		return st
+	}
+	seen := map[string]bool{}
+	for _, list := range [][]string{req.PastDue, req.CurrentlyDue} {
+		for _, r := range list {
+			if !seen[r] {
+				seen[r] = true
+				st.Requirements = append(st.Requirements, r)
+			}
+		}
+	}
+	st.DisabledReason = req.DisabledReason
+	return st
+}
+
 // CheckoutParams はチェックアウトセッション作成に必要なパラメータ
 type CheckoutParams struct {
 	StripeAccountID string // acct_... （プロジェクトオーナーの Connect アカウント）
 	ProjectID       string
-	Amount          int    // 金額（円）
+	Amount          int    // 請求額（円）。寄付者が手数料を負担する場合は上乗せ後の額
+	IntendedAmount  int    // 手数料を上乗せする前の寄付額（上乗せしない場合は 0。metadata に載せる）
 	Currency        string // "jpy"
 	IsRecurring     bool
+	Interval        string // 定期寄付の請求間隔 "month" | "year"（空なら "month"）
+	IntervalCount   int    // Interval 何回分ごとに請求するか（0 なら 1。例: 四半期は month × 3）
+	TierID          string // 寄付の目安額から寄付した場合の ID（metadata に載せる）
 	Message         string
 	Locale          string // "ja" | "en" | "auto"
 	SuccessURL      string
 	CancelURL       string
 	DonorType       string // "user" or "token" — metadata として保存
 	DonorID         string // user_id or donor_token
+
+	// PaymentMethodTypes は受け付ける支払い方法（"card", "konbini", "customer_balance"）。
+	// 空なら指定しない（Stripe の既定）。コンビニ・銀行振込は単発・JPY のみ
+	PaymentMethodTypes []string
+	// CustomerID はログイン中の寄付者の Customer（cus_...）。空なら Checkout が必要に応じて作成する。
+	// Customer はアカウントごとなので、StripeAccountID と同じアカウントのものを指定する
+	CustomerID string
+	// ApplicationFeeAmount は単発寄付で連結アカウントの決済からプラットフォームが受け取る額
+	// （上流プロジェクトへの分配分）。StripeAccountID がある場合のみ指定できる
+	ApplicationFeeAmount int
+	// ApplicationFeePercent は定期寄付の請求ごとにプラットフォームが受け取る割合（%）。StripeAccountID がある場合のみ指定できる
+	ApplicationFeePercent int
+}
+
+// CustomerParams は Customer 作成に必要なパラメータ
+type CustomerParams struct {
+	StripeAccountID string // 作成先の連結アカウント（空ならプラットフォーム）。Customer はアカウントごとに存在する
+	Email           string
+	Name            string
+	UserID          string // metadata[user_id] として保存
+}
+
+// TransferParams はプラットフォームの残高から連結アカウントへの送金に必要なパラメータ
+type TransferParams struct {
+	Destination   string // 送金先の連結アカウント（acct_...）
+	Amount        int
+	Currency      string
+	TransferGroup string            // 同じ決済から分配した送金をまとめる（例: 入金台帳の ID）
+	Metadata      map[string]string // 送金の記録 ID など
+	// IdempotencyKey は送金の記録から決めるキー。再試行・再実行で二重に送金しないよう必須
+	IdempotencyKey string
+}
+
+// CheckoutSession は作成した Checkout Session の ID（cs_...）と決済ページの URL
+type CheckoutSession struct {
+	ID  string
+	URL string
 }
 
 // WebhookEventObject は payment_intent や subscription の data.object
@@ -49,21 +123,83 @@ type WebhookEventObject struct {
 	Amount       int               `json:"amount"`
 	Currency     string            `json:"currency"`
 	Metadata     map[string]string `json:"metadata"`
-	Subscription string            `json:"subscription"` // invoice イベントで使用
+	Subscription string            `json:"subscription"` // invoice / checkout.session イベントで使用
+	AmountPaid   int               `json:"amount_paid"`  // invoice: 実際に請求・入金された額
+	// payment_intent / invoice: 連結アカウントの決済からプラットフォームが受け取った額（上流プロジェクトへの分配分）
+	ApplicationFeeAmount int `json:"application_fee_amount"`
+	// charge / charge.dispute / checkout.session イベントで使用
+	PaymentIntent  string `json:"payment_intent"`
+	AmountRefunded int    `json:"amount_refunded"` // charge.refunded: 累計返金額
+	Status         string `json:"status"`          // dispute: needs_response, under_review, won, lost など。checkout.session: open, complete, expired
+	Reason         string `json:"reason"`          // dispute: fraudulent, duplicate など
+	// checkout.session イベントで使用。コンビニ・銀行振込は完了時点では payment_status が "unpaid"
+	Mode          string `json:"mode"`           // payment, subscription
+	PaymentStatus string `json:"payment_status"` // paid, unpaid, no_payment_required
+	AmountTotal   int    `json:"amount_total"`
+	// account.updated イベントで使用（data.object は v1 の Account）
+	ChargesEnabled bool                 `json:"charges_enabled"`
+	PayoutsEnabled bool                 `json:"payouts_enabled"`
+	Requirements   *AccountRequirements `json:"requirements"`
 	// subscription の場合のみ使用
-	Plan *struct {
-		Amount   int    `json:"amount"`
-		Currency string `json:"currency"`
-	} `json:"plan"`
+	Plan            *SubscriptionPlan `json:"plan"`
+	Quantity        int               `json:"quantity"`         // Billing Portal で数量を変更できる設定の場合に 1 以外になる
+	CancelAt        int64             `json:"cancel_at"`        // 解約予定日時（Unix 秒）。Billing Portal の「期間終了時に解約」など。0 なら予定なし
+	PauseCollection *PauseCollection  `json:"pause_collection"` // 一時停止中のみ設定される
+}
+
+// PauseCollection は subscription の pause_collection
+type PauseCollection struct {
+	Behavior string `json:"behavior"` // "void" など
+}
+
+// SubscriptionCharge は subscription の 1 回あたりの請求額（plan の金額 × 数量）を返す。plan がなければ 0
+func (o WebhookEventObject) SubscriptionCharge() int {
+	if o.Plan == nil {
+		return 0
+	}
+	return o.Plan.Amount * max(o.Quantity, 1)
+}
+
+// SubscriptionPlan は subscription の plan（金額と請求間隔）
+type SubscriptionPlan struct {
+	Amount        int    `json:"amount"`
+	Currency      string `json:"currency"`
+	Interval      string `json:"interval"`       // "month" または "year"
+	IntervalCount int    `json:"interval_count"` // Interval 何回分ごとに請求するか（例: 3 か月ごとなら 3）
+}
+
+// AccountStatus は account.updated イベントの data.object から連結アカウントの状態を返す
+func (o WebhookEventObject) AccountStatus() AccountStatus {
+	return newAccountStatus(o.ChargesEnabled, o.PayoutsEnabled, o.Requirements)
+}
+
+// EventRelatedObject は v2 の thin イベントが指すオブジェクト（data を持たないので API で取得し直す）
+type EventRelatedObject struct {
+	ID   string `json:"id"`
+	Type string `json:"type"` // 例: "v2.core.account"
 }
 
 // WebhookEvent は Stripe Webhook のイベント
 type WebhookEvent struct {
-	Type string `json:"type"`
-	ID   string `json:"id"`
-	Data struct {
+	Type    string `json:"type"`
+	ID      string `json:"id"`
+	Account string `json:"account"` // Connect イベントの発生元の連結アカウント（acct_...）
+	Data    struct {
 		Object WebhookEventObject `json:"object"`
 	} `json:"data"`
+	RelatedObject *EventRelatedObject `json:"related_object"` // v2 の thin イベントのみ
+	Request       *EventRequest       `json:"request"`        // イベントを起こした API リクエスト
+}
+
+// EventRequest はイベントを起こした API リクエスト。ダッシュボードや Customer Portal での操作も
+// リクエストとして記録され、Stripe 自身による変更（pause_collection の期限切れなど）では ID が null になる
+type EventRequest struct {
+	ID string `json:"id"`
+}
+
+// Automatic は API リクエストではなく Stripe 自身が起こしたイベントかどうかを返す
+func (e WebhookEvent) Automatic() bool {
+	return e.Request == nil || e.Request.ID == ""
 }
 
 // Client は Stripe API クライアントのインターフェース
@@ -74,34 +210,74 @@ type Client interface {
 	CreateAccountLink(ctx context.Context, accountID, returnURL, refreshURL string) (string, error)
 	// GetAccountOnboarded は連結アカウントのオンボーディング完了状態を返す
 	GetAccountOnboarded(ctx context.Context, accountID string) (bool, error)
-	// CreateCheckoutSession は Stripe Checkout Session を作成し URL を返す
-	CreateCheckoutSession(ctx context.Context, params CheckoutParams) (string, error)
+	// GetAccountStatus は連結アカウントの決済・入金の可否と未提出の要件を返す
+	GetAccountStatus(ctx context.Context, accountID string) (AccountStatus, error)
+	// CreateCheckoutSession は Stripe Checkout Session を作成し ID と URL を返す
+	CreateCheckoutSession(ctx context.Context, params CheckoutParams) (CheckoutSession, error)
 	// VerifyWebhookSignature は Stripe-Signature ヘッダーを検証する
 	VerifyWebhookSignature(payload []byte, sigHeader string) error
 	// ParseWebhookEvent は Webhook ペイロードをパースする
 	ParseWebhookEvent(payload []byte) (WebhookEvent, error)
 	// PauseSubscription は定期課金を一時停止する
-	PauseSubscription(ctx context.Context, subscriptionID string) error
+	PauseSubscription(ctx context.Context, accountID, subscriptionID string) error
 	// ResumeSubscription は一時停止中の定期課金を再開する
-	ResumeSubscription(ctx context.Context, subscriptionID string) error
+	ResumeSubscription(ctx context.Context, accountID, subscriptionID string) error
 	// CancelSubscription は定期課金をキャンセルする
-	CancelSubscription(ctx context.Context, subscriptionID string) error
+	CancelSubscription(ctx context.Context, accountID, subscriptionID string) error
 	// UpdateSubscriptionAmount はサブスクリプションの金額を変更する
-	UpdateSubscriptionAmount(ctx context.Context, subscriptionID string, newAmount int) error
+	UpdateSubscriptionAmount(ctx context.Context, accountID, subscriptionID string, newAmount int) error
+	// CreatePaymentMethodUpdateSession はサブスクリプションの支払い方法を更新するための
+	// Billing Portal Session を作成し URL を返す
+	CreatePaymentMethodUpdateSession(ctx context.Context, accountID, subscriptionID, returnURL string) (string, error)
+	// CreateCustomer は params.StripeAccountID のアカウント（空ならプラットフォーム）に Customer を作成し cus_... を返す
+	CreateCustomer(ctx context.Context, params CustomerParams) (string, error)
+	// CreateBillingPortalSession は accountID のアカウント（空ならプラットフォーム）上の Customer の
+	// サブスクリプション・支払い方法を管理する Billing Portal Session を作成し URL を返す
+	CreateBillingPortalSession(ctx context.Context, accountID, customerID, returnURL string) (string, error)
+	// CreateTransfer はプラットフォームの残高から連結アカウントに送金し tr_... を返す
+	CreateTransfer(ctx context.Context, params TransferParams) (string, error)
+	// ListSubscriptions は全ステータスのサブスクリプションを取得する（照合用。accountID が空ならプラットフォーム）
+	ListSubscriptions(ctx context.Context, accountID string) ([]Subscription, error)
+	// GetSubscription はサブスクリプションを 1 件取得する（照合用。accountID が空ならプラットフォーム）
+	GetSubscription(ctx context.Context, accountID, subscriptionID string) (Subscription, error)
+	// ListPaymentIntents は createdSince 以降の PaymentIntent を取得する（照合用。accountID が空ならプラットフォーム）
+	ListPaymentIntents(ctx context.Context, accountID string, createdSince time.Time) ([]PaymentIntent, error)
+	// ListConnectedAccounts は連結アカウントを取得する（照合用）
+	ListConnectedAccounts(ctx context.Context) ([]ConnectedAccount, error)
+	// ListBalanceTransactions は createdSince 以降の残高の増減を取得する（手数料の集計用。accountID が空ならプラットフォーム）
+	ListBalanceTransactions(ctx context.Context, accountID string, createdSince time.Time) ([]BalanceTransaction, error)
+	// ListPayouts は createdSince 以降の連結アカウントの入金を取得する（入金の集計用）
+	ListPayouts(ctx context.Context, accountID string, createdSince time.Time) ([]Payout, error)
 }
 
+// DefaultAPIBase は Stripe API のベース URL
+const DefaultAPIBase = "https://api.stripe.com"
+
 // RealClient は Stripe API への raw HTTP クライアント実装
 type RealClient struct {
 	SecretKey     string
-	WebhookSecret string // whsec_...
+	WebhookSecret string        // whsec_...
+	APIBase       string        // 空なら DefaultAPIBase（テストでフェイクサーバーに向ける）
+	MaxRetries    int           // 429 / 5xx の再試行回数（0 なら再試行しない）
+	RetryBackoff  time.Duration // 再試行の待ち時間の基準（0 なら defaultBackoffBase）
 	httpClient    *http.Client
 }
 
+// apiURL は APIBase にパスを連結した URL を返す
+func (c *RealClient) apiURL(path string) string {
+	base := c.APIBase
+	if base == "" {
+		base = DefaultAPIBase
+	}
+	return base + path
+}
+
 // NewClient は RealClient を生成する
 func NewClient(secretKey, webhookSecret string) *RealClient {
 	return &RealClient{
 		SecretKey:     secretKey,
 		WebhookSecret: webhookSecret,
+		MaxRetries:    defaultMaxRetries,
 		httpClient:    &http.Client{Timeout: 30 * time.Second},
 	}
 }
@@ -146,38 +322,12 @@ func (c *RealClient) CreateConnectedAccount(ctx context.Context, params CreateAc
 		},
 	}
 
-	jsonBody, err := json.Marshal(body)
-	if err != nil {
-		return "", err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
-		"https://api.stripe.com/v2/core/accounts",
-		bytes.NewReader(jsonBody))
-	if err != nil {
-		return "", err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.SecretKey)
-	req.Header.Set("Stripe-Version", StripeAPIVersion)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
 	var result struct {
-		ID    string `json:"id"`
-		Error *struct {
-			Message string `json:"message"`
-		} `json:"error"`
+		ID string `json:"id"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", err
-	}
-	if result.Error != nil {
-		return "", fmt.Errorf("stripe create account: %s", result.Error.Message)
+	err := c.do(ctx, apiRequest{method: http.MethodPost, path: "/v2/core/accounts", json: body, v2: true}, &result)
+	if err != nil {
+		return "", fmt.Errorf("stripe create account: %w", err)
 	}
 	if result.ID == "" {
 		return "", errors.New("stripe create account: empty account ID in response")
@@ -203,38 +353,12 @@ func (c *RealClient) CreateAccountLink(ctx context.Context, accountID, returnURL
 		},
 	}
 
-	jsonBody, err := json.Marshal(body)
-	if err != nil {
-		return "", err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
-		"https://api.stripe.com/v2/core/account_links",
-		bytes.NewReader(jsonBody))
-	if err != nil {
-		return "", err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.SecretKey)
-	req.Header.Set("Stripe-Version", StripeAPIVersion)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
 	var result struct {
-		URL   string `json:"url"`
-		Error *struct {
-			Message string `json:"message"`
-		} `json:"error"`
+		URL string `json:"url"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", err
-	}
-	if result.Error != nil {
-		return "", fmt.Errorf("stripe create account link: %s", result.Error.Message)
+	err := c.do(ctx, apiRequest{method: http.MethodPost, path: "/v2/core/account_links", json: body, v2: true}, &result)
+	if err != nil {
+		return "", fmt.Errorf("stripe create account link: %w", err)
 	}
 	if result.URL == "" {
 		return "", errors.New("stripe create account link: empty URL in response")
@@ -249,33 +373,14 @@ func (c *RealClient) GetAccountOnboarded(ctx context.Context, accountID string)
 		return false, ErrNotConfigured
 	}
 
-	endpoint := fmt.Sprintf("https://api.stripe.com/v2/core/accounts/%s?include=requirements", accountID)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
-	if err != nil {
-		return false, err
-	}
-	req.Header.Set("Authorization", "Bearer "+c.SecretKey)
-	req.Header.Set("Stripe-Version", StripeAPIVersion)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return false, err
-	}
-	defer resp.Body.Close()
-
 	var result struct {
 		Requirements *struct {
 			CurrentlyDue []string `json:"currently_due"`
 		} `json:"requirements"`
-		Error *struct {
-			Message string `json:"message"`
-		} `json:"error"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return false, err
 	}
-	if result.Error != nil {
-		return false, fmt.Errorf("stripe get account: %s", result.Error.Message)
+	path := fmt.Sprintf("/v2/core/accounts/%s?include=requirements", accountID)
+	if err := c.do(ctx, apiRequest{method: http.MethodGet, path: path, v2: true}, &result); err != nil {
+		return false, fmt.Errorf("stripe get account: %w", err)
 	}
 	if result.Requirements == nil {
 		return true, nil
@@ -283,17 +388,45 @@ func (c *RealClient) GetAccountOnboarded(ctx context.Context, accountID string)
 	return len(result.Requirements.CurrentlyDue) == 0, nil
 }
 
-// CreateCheckoutSession は Stripe Checkout Session を作成し URL を返す
-func (c *RealClient) CreateCheckoutSession(ctx context.Context, params CheckoutParams) (string, error) {
+// GetAccountStatus は v1 API で連結アカウントの charges_enabled / payouts_enabled / requirements を取得する。
+// v2 の thin イベントは data を持たないため、状態はここで取得し直す。
+func (c *RealClient) GetAccountStatus(ctx context.Context, accountID string) (AccountStatus, error) {
 	if c.SecretKey == "" {
-		return "", ErrNotConfigured
+		return AccountStatus{}, ErrNotConfigured
+	}
+
+	var result struct {
+		ChargesEnabled bool                 `json:"charges_enabled"`
+		PayoutsEnabled bool                 `json:"payouts_enabled"`
+		Requirements   *AccountRequirements `json:"requirements"`
+	}
+	if err := c.do(ctx, apiRequest{method: http.MethodGet, path: "/v1/accounts/" + url.PathEscape(accountID)}, &result); err != nil {
+		return AccountStatus{}, fmt.Errorf("stripe get account: %w", err)
+	}
+	return newAccountStatus(result.ChargesEnabled, result.PayoutsEnabled, result.Requirements), nil
+}
+
+// CreateCheckoutSession は Stripe Checkout Session を作成し ID と URL を返す
+func (c *RealClient) CreateCheckoutSession(ctx context.Context, params CheckoutParams) (CheckoutSession, error) {
+	if c.SecretKey == "" {
+		return CheckoutSession{}, ErrNotConfigured
 	}
 
 	data := url.Values{}
 	if params.IsRecurring {
+		interval, count := params.Interval, params.IntervalCount
+		if interval == "" {
+			interval = "month"
+		}
+		if count <= 0 {
+			count = 1
+		}
 		data.Set("mode", "subscription")
-		data.Set("line_items[0][price_data][recurring][interval]", "month")
-		data.Set("line_items[0][price_data][product_data][name]", "月次サポート")
+		data.Set("line_items[0][price_data][recurring][interval]", interval)
+		if count > 1 {
+			data.Set("line_items[0][price_data][recurring][interval_count]", strconv.Itoa(count))
+		}
+		data.Set("line_items[0][price_data][product_data][name]", recurringProductName(interval, count))
 	} else {
 		data.Set("mode", "payment")
 		data.Set("line_items[0][price_data][product_data][name]", "寄付")
@@ -307,60 +440,89 @@ func (c *RealClient) CreateCheckoutSession(ctx context.Context, params CheckoutP
 		data.Set("locale", params.Locale)
 	}
 
+	// 連結アカウントでの決済（direct charge）からプラットフォームが受け取る額。上流プロジェクトへの送金の原資になる
+	if params.ApplicationFeeAmount > 0 && !params.IsRecurring {
+		data.Set("payment_intent_data[application_fee_amount]", strconv.Itoa(params.ApplicationFeeAmount))
+	}
+	if params.ApplicationFeePercent > 0 && params.IsRecurring {
+		data.Set("subscription_data[application_fee_percent]", strconv.Itoa(params.ApplicationFeePercent))
+	}
+
+	if params.CustomerID != "" {
+		// 同じ Customer にまとめて、Billing Portal でサブスクリプションと支払い方法を管理できるようにする
+		data.Set("customer", params.CustomerID)
+	}
+	for i, t := range params.PaymentMethodTypes {
+		data.Set("payment_method_types["+strconv.Itoa(i)+"]", t)
+		if t == "customer_balance" {
+			// 銀行振込は顧客ごとの振込先口座に入金してもらうため Customer が必要
+			data.Set("payment_method_options[customer_balance][funding_type]", "bank_transfer")
+			data.Set("payment_method_options[customer_balance][bank_transfer][type]", "jp_bank_transfer")
+			if params.CustomerID == "" {
+				data.Set("customer_creation", "always")
+			}
+		}
+	}
+
 	// metadata を PaymentIntent / Subscription に伝播させる
 	// （Checkout Session の metadata はイベントオブジェクトにコピーされない）
+	metadata := map[string]string{"project_id": params.ProjectID}
 	if params.IsRecurring {
-		data.Set("subscription_data[metadata][project_id]", params.ProjectID)
-		data.Set("subscription_data[metadata][is_recurring]", "true")
-		if params.DonorType != "" {
-			data.Set("subscription_data[metadata][donor_type]", params.DonorType)
-			data.Set("subscription_data[metadata][donor_id]", params.DonorID)
-		}
-		if params.Message != "" {
-			data.Set("subscription_data[metadata][message]", params.Message)
-		}
-	} else {
-		data.Set("payment_intent_data[metadata][project_id]", params.ProjectID)
-		if params.DonorType != "" {
-			data.Set("payment_intent_data[metadata][donor_type]", params.DonorType)
-			data.Set("payment_intent_data[metadata][donor_id]", params.DonorID)
-		}
-		if params.Message != "" {
-			data.Set("payment_intent_data[metadata][message]", params.Message)
-		}
+		metadata["is_recurring"] = "true"
 	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
-		"https://api.stripe.com/v1/checkout/sessions",
-		strings.NewReader(data.Encode()))
-	if err != nil {
-		return "", err
+	if params.DonorType != "" {
+		metadata["donor_type"] = params.DonorType
+		metadata["donor_id"] = params.DonorID
 	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.SetBasicAuth(c.SecretKey, "")
-	if params.StripeAccountID != "" {
-		req.Header.Set("Stripe-Account", params.StripeAccountID)
+	if params.Message != "" {
+		metadata["message"] = params.Message
 	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return "", err
+	if params.TierID != "" {
+		metadata["tier_id"] = params.TierID
 	}
-	defer resp.Body.Close()
-
-	var session struct {
-		URL   string `json:"url"`
-		Error *struct {
-			Message string `json:"message"`
-		} `json:"error"`
+	if params.IntendedAmount > 0 {
+		metadata[IntendedAmountMetadataKey] = strconv.Itoa(params.IntendedAmount)
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&session); err != nil {
-		return "", err
+	for k, v := range metadata {
+		if params.IsRecurring {
+			data.Set("subscription_data[metadata]["+k+"]", v)
+			continue
+		}
+		data.Set("payment_intent_data[metadata]["+k+"]", v)
+		// コンビニ・銀行振込は入金前に checkout.session.completed が届くので、
+		// 入金待ちの寄付を記録できるよう Session 自体にも載せる
+		data.Set("metadata["+k+"]", v)
 	}
-	if session.Error != nil {
-		return "", fmt.Errorf("stripe checkout error: %s", session.Error.Message)
+
+	var session struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	err := c.do(ctx, apiRequest{
+		method:        http.MethodPost,
+		path:          "/v1/checkout/sessions",
+		form:          data,
+		stripeAccount: params.StripeAccountID,
+	}, &session)
+	if err != nil {
+		return CheckoutSession{}, fmt.Errorf("stripe checkout error: %w", err)
 	}
-	return session.URL, nil
+	return CheckoutSession{ID: session.ID, URL: session.URL}, nil
+}
+
+// recurringProductName は請求間隔に応じた定期寄付の商品名（Checkout と請求書に表示される）
+func recurringProductName(interval string, count int) string {
+	switch {
+	case interval == "month" && count == 1:
+		return "月次サポート"
+	case interval == "month" && count == 3:
+		return "四半期サポート"
+	case interval == "month" && count == 6:
+		return "半年サポート"
+	case interval == "year" && count == 1:
+		return "年次サポート"
+	}
+	return "定期サポート"
 }
 
 // VerifyWebhookSignature は Stripe-Signature ヘッダーを HMAC-SHA256 で検証する
@@ -417,77 +579,46 @@ func (c *RealClient) ParseWebhookEvent(payload []byte) (WebhookEvent, error) {
 }
 
 // PauseSubscription は pause_collection を設定してサブスクリプションを一時停止する
-func (c *RealClient) PauseSubscription(ctx context.Context, subscriptionID string) error {
+func (c *RealClient) PauseSubscription(ctx context.Context, accountID, subscriptionID string) error {
 	if c.SecretKey == "" {
 		return ErrNotConfigured
 	}
 	data := url.Values{}
 	data.Set("pause_collection[behavior]", "void")
 
-	return c.updateSubscription(ctx, subscriptionID, data)
+	return c.updateSubscription(ctx, accountID, subscriptionID, data)
 }
 
 // ResumeSubscription は pause_collection を解除してサブスクリプションを再開する
-func (c *RealClient) ResumeSubscription(ctx context.Context, subscriptionID string) error {
+func (c *RealClient) ResumeSubscription(ctx context.Context, accountID, subscriptionID string) error {
 	if c.SecretKey == "" {
 		return ErrNotConfigured
 	}
 	data := url.Values{}
 	data.Set("pause_collection", "")
 
-	return c.updateSubscription(ctx, subscriptionID, data)
+	return c.updateSubscription(ctx, accountID, subscriptionID, data)
 }
 
 // CancelSubscription はサブスクリプションをキャンセルする
-func (c *RealClient) CancelSubscription(ctx context.Context, subscriptionID string) error {
+func (c *RealClient) CancelSubscription(ctx context.Context, accountID, subscriptionID string) error {
 	if c.SecretKey == "" {
 		return ErrNotConfigured
 	}
-	endpoint := fmt.Sprintf("https://api.stripe.com/v1/subscriptions/%s", subscriptionID)
-	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, nil)
-	if err != nil {
-		return err
-	}
-	req.SetBasicAuth(c.SecretKey, "")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 {
-		var errResp struct {
-			Error struct {
-				Message string `json:"message"`
-			} `json:"error"`
-		}
-		_ = json.NewDecoder(resp.Body).Decode(&errResp)
-		return fmt.Errorf("stripe cancel subscription: %s", errResp.Error.Message)
+	path := fmt.Sprintf("/v1/subscriptions/%s", subscriptionID)
+	if err := c.do(ctx, apiRequest{method: http.MethodDelete, path: path, stripeAccount: accountID}, nil); err != nil {
+		return fmt.Errorf("stripe cancel subscription: %w", err)
 	}
 	return nil
 }
 
 // UpdateSubscriptionAmount はサブスクリプションの既存 price の金額を更新する。
 // Stripe ではインライン price のみ金額変更可能。price_data で新しい price を作り直す。
-func (c *RealClient) UpdateSubscriptionAmount(ctx context.Context, subscriptionID string, newAmount int) error {
+func (c *RealClient) UpdateSubscriptionAmount(ctx context.Context, accountID, subscriptionID string, newAmount int) error {
 	if c.SecretKey == "" {
 		return ErrNotConfigured
 	}
 	// 現在のサブスクリプションを取得して既存 item ID を得る
-	getEndpoint := fmt.Sprintf("https://api.stripe.com/v1/subscriptions/%s", subscriptionID)
-	getReq, err := http.NewRequestWithContext(ctx, http.MethodGet, getEndpoint, nil)
-	if err != nil {
-		return err
-	}
-	getReq.SetBasicAuth(c.SecretKey, "")
-
-	resp, err := c.httpClient.Do(getReq)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
 	var sub struct {
 		Items struct {
 			Data []struct {
@@ -495,20 +626,16 @@ func (c *RealClient) UpdateSubscriptionAmount(ctx context.Context, subscriptionI
 				Price struct {
 					Currency  string `json:"currency"`
 					Recurring struct {
-						Interval string `json:"interval"`
+						Interval      string `json:"interval"`
+						IntervalCount int    `json:"interval_count"`
 					} `json:"recurring"`
 				} `json:"price"`
 			} `json:"data"`
 		} `json:"items"`
-		Error *struct {
-			Message string `json:"message"`
-		} `json:"error"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&sub); err != nil {
-		return err
-	}
-	if sub.Error != nil {
-		return fmt.Errorf("stripe get subscription: %s", sub.Error.Message)
+	path := fmt.Sprintf("/v1/subscriptions/%s", subscriptionID)
+	if err := c.do(ctx, apiRequest{method: http.MethodGet, path: path, stripeAccount: accountID}, &sub); err != nil {
+		return fmt.Errorf("stripe get subscription: %w", err)
 	}
 	if len(sub.Items.Data) == 0 {
 		return errors.New("stripe: subscription has no items")
@@ -519,36 +646,139 @@ func (c *RealClient) UpdateSubscriptionAmount(ctx context.Context, subscriptionI
 	data.Set("items[0][id]", item.ID)
 	data.Set("items[0][price_data][currency]", item.Price.Currency)
 	data.Set("items[0][price_data][unit_amount]", strconv.Itoa(newAmount))
-	data.Set("items[0][price_data][recurring][interval]", item.Price.Recurring.Interval)
-	data.Set("items[0][price_data][product_data][name]", "月次サポート")
+	// 請求間隔は変えずに金額だけ差し替える
+	recurring := item.Price.Recurring
+	if recurring.IntervalCount <= 0 {
+		recurring.IntervalCount = 1
+	}
+	data.Set("items[0][price_data][recurring][interval]", recurring.Interval)
+	if recurring.IntervalCount > 1 {
+		data.Set("items[0][price_data][recurring][interval_count]", strconv.Itoa(recurring.IntervalCount))
+	}
+	data.Set("items[0][price_data][product_data][name]", recurringProductName(recurring.Interval, recurring.IntervalCount))
 	data.Set("proration_behavior", "none")
 
-	return c.updateSubscription(ctx, subscriptionID, data)
+	return c.updateSubscription(ctx, accountID, subscriptionID, data)
+}
+
+// CreatePaymentMethodUpdateSession はサブスクリプションの Customer を取得し、
+// 支払い方法の更新フローに直接入る Billing Portal Session を作成する。
+// 更新後の未払い請求の再試行は Stripe 側で行われる。
+func (c *RealClient) CreatePaymentMethodUpdateSession(ctx context.Context, accountID, subscriptionID, returnURL string) (string, error) {
+	if c.SecretKey == "" {
+		return "", ErrNotConfigured
+	}
+	var sub struct {
+		Customer string `json:"customer"`
+	}
+	path := fmt.Sprintf("/v1/subscriptions/%s", subscriptionID)
+	if err := c.do(ctx, apiRequest{method: http.MethodGet, path: path, stripeAccount: accountID}, &sub); err != nil {
+		return "", fmt.Errorf("stripe get subscription: %w", err)
+	}
+	if sub.Customer == "" {
+		return "", errors.New("stripe: subscription has no customer")
+	}
+
+	data := url.Values{}
+	data.Set("customer", sub.Customer)
+	data.Set("return_url", returnURL)
+	data.Set("flow_data[type]", "payment_method_update")
+
+	var session struct {
+		URL string `json:"url"`
+	}
+	err := c.do(ctx, apiRequest{method: http.MethodPost, path: "/v1/billing_portal/sessions", form: data, stripeAccount: accountID}, &session)
+	if err != nil {
+		return "", fmt.Errorf("stripe billing portal error: %w", err)
+	}
+	return session.URL, nil
+}
+
+// CreateCustomer は Customer を作成する（StripeAccountID があればその連結アカウント上に）
+func (c *RealClient) CreateCustomer(ctx context.Context, params CustomerParams) (string, error) {
+	if c.SecretKey == "" {
+		return "", ErrNotConfigured
+	}
+	data := url.Values{}
+	if params.Email != "" {
+		data.Set("email", params.Email)
+	}
+	if params.Name != "" {
+		data.Set("name", params.Name)
+	}
+	if params.UserID != "" {
+		data.Set("metadata[user_id]", params.UserID)
+	}
+
+	var customer struct {
+		ID string `json:"id"`
+	}
+	if err := c.do(ctx, apiRequest{method: http.MethodPost, path: "/v1/customers", form: data, stripeAccount: params.StripeAccountID}, &customer); err != nil {
+		return "", fmt.Errorf("stripe create customer: %w", err)
+	}
+	return customer.ID, nil
 }
 
-func (c *RealClient) updateSubscription(ctx context.Context, subscriptionID string, data url.Values) error {
-	endpoint := fmt.Sprintf("https://api.stripe.com/v1/subscriptions/%s", subscriptionID)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(data.Encode()))
+// CreateBillingPortalSession は Customer の Billing Portal Session を作成する。
+// 連結アカウント上の Customer は Stripe-Account ヘッダーでそのアカウントのポータルを開く。
+// ポータルでの解約・金額変更などは customer.subscription.updated / deleted で反映される。
+func (c *RealClient) CreateBillingPortalSession(ctx context.Context, accountID, customerID, returnURL string) (string, error) {
+	if c.SecretKey == "" {
+		return "", ErrNotConfigured
+	}
+	data := url.Values{}
+	data.Set("customer", customerID)
+	data.Set("return_url", returnURL)
+
+	var session struct {
+		URL string `json:"url"`
+	}
+	err := c.do(ctx, apiRequest{method: http.MethodPost, path: "/v1/billing_portal/sessions", form: data, stripeAccount: accountID}, &session)
 	if err != nil {
-		return err
+		return "", fmt.Errorf("stripe billing portal error: %w", err)
 	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.SetBasicAuth(c.SecretKey, "")
+	return session.URL, nil
+}
 
-	resp, err := c.httpClient.Do(req)
+// CreateTransfer はプラットフォームの残高から連結アカウントに送金する。
+// 残高が不足している場合（決済の資金がまだ利用可能になっていない場合など）は balance_insufficient のエラーになる。
+func (c *RealClient) CreateTransfer(ctx context.Context, params TransferParams) (string, error) {
+	if c.SecretKey == "" {
+		return "", ErrNotConfigured
+	}
+	if params.IdempotencyKey == "" {
+		return "", errors.New("stripe create transfer: idempotency key is required")
+	}
+	data := url.Values{}
+	data.Set("destination", params.Destination)
+	data.Set("amount", strconv.Itoa(params.Amount))
+	data.Set("currency", params.Currency)
+	if params.TransferGroup != "" {
+		data.Set("transfer_group", params.TransferGroup)
+	}
+	for k, v := range params.Metadata {
+		data.Set("metadata["+k+"]", v)
+	}
+
+	var transfer struct {
+		ID string `json:"id"`
+	}
+	err := c.do(ctx, apiRequest{
+		method:         http.MethodPost,
+		path:           "/v1/transfers",
+		form:           data,
+		idempotencyKey: params.IdempotencyKey,
+	}, &transfer)
 	if err != nil {
-		return err
+		return "", fmt.Errorf("stripe create transfer: %w", err)
 	}
-	defer resp.Body.Close()
+	return transfer.ID, nil
+}
 
-	if resp.StatusCode >= 400 {
-		var errResp struct {
-			Error struct {
-				Message string `json:"message"`
-			} `json:"error"`
-		}
-		_ = json.NewDecoder(resp.Body).Decode(&errResp)
-		return fmt.Errorf("stripe update subscription: %s", errResp.Error.Message)
+func (c *RealClient) updateSubscription(ctx context.Context, accountID, subscriptionID string, data url.Values) error {
+	path := fmt.Sprintf("/v1/subscriptions/%s", subscriptionID)
+	if err := c.do(ctx, apiRequest{method: http.MethodPost, path: path, form: data, stripeAccount: accountID}, nil); err != nil {
+		return fmt.Errorf("stripe update subscription: %w", err)
 	}
 	return nil
 }
@@ -1,10 +1,12 @@
 package stripe
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"net/http"
 	"testing"
 	"time"
 )
@@ -102,3 +104,174 @@ func TestRealClient_ParseWebhookEvent_PaymentIntentSucceeded(t *testing.T) {
 		t.Errorf("expected donor_type=user, got %q", event.Data.Object.Metadata["donor_type"])
 	}
 }
+
+func TestRealClient_ParseWebhookEvent_InvoicePaymentFailed(t *testing.T) {
+	c := NewClient("", "")
+	payload := []byte(`{
+		"type":"invoice.payment_failed",
+		"id":"evt_failed",
+		"data":{"object":{"id":"in_1","subscription":"sub_1","amount_paid":0,"payment_intent":"pi_1"}}
+	}`)
+	event, err := c.ParseWebhookEvent(payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.Data.Object.Subscription != "sub_1" || event.Data.Object.PaymentIntent != "pi_1" {
+		t.Errorf("unexpected object: %+v", event.Data.Object)
+	}
+}
+
+func TestRealClient_ParseWebhookEvent_AccountUpdated(t *testing.T) {
+	c := NewClient("", "")
+	payload := []byte(`{
+		"type":"account.updated",
+		"id":"evt_acct",
+		"account":"acct_1",
+		"data":{"object":{
+			"id":"acct_1",
+			"charges_enabled":false,
+			"payouts_enabled":true,
+			"requirements":{"currently_due":["external_account","individual.verification.document"],"past_due":["individual.verification.document"],"disabled_reason":"requirements.past_due"}
+		}}
+	}`)
+	event, err := c.ParseWebhookEvent(payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.Account != "acct_1" {
+		t.Errorf("account = %q, want acct_1", event.Account)
+	}
+	st := event.Data.Object.AccountStatus()
+	if st.ChargesEnabled || !st.PayoutsEnabled || st.DisabledReason != "requirements.past_due" {
+		t.Errorf("status = %+v", st)
+	}
+	// past_due が先、重複は除く
+	if len(st.Requirements) != 2 || st.Requirements[0] != "individual.verification.document" || st.Requirements[1] != "external_account" {
+		t.Errorf("requirements = %v", st.Requirements)
+	}
+}
+
+func TestRealClient_ParseWebhookEvent_V2ThinEvent(t *testing.T) {
+	c := NewClient("", "")
+	payload := []byte(`{
+		"id":"evt_v2",
+		"object":"v2.core.event",
+		"type":"v2.core.account[requirements].updated",
+		"related_object":{"id":"acct_1","type":"v2.core.account","url":"/v2/core/accounts/acct_1"}
+	}`)
+	event, err := c.ParseWebhookEvent(payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.RelatedObject == nil || event.RelatedObject.ID != "acct_1" || event.RelatedObject.Type != "v2.core.account" {
+		t.Errorf("related_object = %+v", event.RelatedObject)
+	}
+}
+
+func TestRealClient_ParseWebhookEvent_SubscriptionPaused(t *testing.T) {
+	c := NewClient("", "")
+	tests := []struct {
+		request   string
+		automatic bool
+	}{
+		{`"request":{"id":null,"idempotency_key":null},`, true},
+		{`"request":{"id":"req_1","idempotency_key":"key-1"},`, false},
+		{``, true},
+	}
+	for _, tt := range tests {
+		payload := []byte(`{
+			"id":"evt_paused",
+			"type":"customer.subscription.paused",` + tt.request + `
+			"data":{"object":{"id":"sub_1","status":"paused","cancel_at":1798761600,"pause_collection":{"behavior":"void"},
+				"quantity":2,"plan":{"amount":1500,"currency":"jpy","interval":"month","interval_count":1}}}
+		}`)
+		event, err := c.ParseWebhookEvent(payload)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if event.Automatic() != tt.automatic {
+			t.Errorf("%s: Automatic() = %v, want %v", tt.request, event.Automatic(), tt.automatic)
+		}
+		obj := event.Data.Object
+		if obj.Status != "paused" || obj.PauseCollection == nil || obj.CancelAt != 1798761600 || obj.SubscriptionCharge() != 3000 {
+			t.Errorf("subscription = %+v", obj)
+		}
+	}
+}
+
+func TestRealClient_GetAccountStatus(t *testing.T) {
+	c := newListTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/accounts/acct_1" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		fmt.Fprint(w, `{"id":"acct_1","charges_enabled":true,"payouts_enabled":false,"requirements":{"currently_due":["external_account"],"past_due":[],"disabled_reason":null}}`)
+	})
+
+	st, err := c.GetAccountStatus(context.Background(), "acct_1")
+	if err != nil {
+		t.Fatalf("GetAccountStatus: %v", err)
+	}
+	if !st.ChargesEnabled || st.PayoutsEnabled || len(st.Requirements) != 1 || st.Requirements[0] != "external_account" || st.DisabledReason != "" {
+		t.Errorf("status = %+v", st)
+	}
+}
+
+func TestRealClient_CreatePaymentMethodUpdateSession_NotConfigured(t *testing.T) {
+	c := NewClient("", "")
+	if _, err := c.CreatePaymentMethodUpdateSession(context.Background(), "", "sub_1", "https://example.com/me"); err != ErrNotConfigured {
+		t.Errorf("expected ErrNotConfigured, got %v", err)
+	}
+}
+
+func TestRealClient_CreateCustomer_NotConfigured(t *testing.T) {
+	c := NewClient("", "")
+	if _, err := c.CreateCustomer(context.Background(), CustomerParams{UserID: "user-1"}); err != ErrNotConfigured {
+		t.Errorf("expected ErrNotConfigured, got %v", err)
+	}
+	if _, err := c.CreateBillingPortalSession(context.Background(), "", "cus_1", "https://example.com/me"); err != ErrNotConfigured {
+		t.Errorf("expected ErrNotConfigured, got %v", err)
+	}
+}
+
+func TestRealClient_CreateTransfer(t *testing.T) {
+	var keys []string
+	c := newListTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/transfers" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_ = r.ParseForm()
+		if r.PostForm.Get("destination") != "acct_up" || r.PostForm.Get("amount") != "300" || r.PostForm.Get("currency") != "jpy" ||
+			r.PostForm.Get("transfer_group") != "payment-1" || r.PostForm.Get("metadata[pass_through_id]") != "pt-1" {
+			t.Errorf("unexpected form %v", r.PostForm)
+		}
+		fmt.Fprint(w, `{"id":"tr_1"}`)
+	})
+	params := TransferParams{
+		Destination:    "acct_up",
+		Amount:         300,
+		Currency:       "jpy",
+		TransferGroup:  "payment-1",
+		Metadata:       map[string]string{"pass_through_id": "pt-1"},
+		IdempotencyKey: "pass-through-pt-1",
+	}
+
+	// 同じ記録からの再実行は同じ Idempotency-Key で送る
+	for range 2 {
+		id, err := c.CreateTransfer(context.Background(), params)
+		if err != nil {
+			t.Fatalf("CreateTransfer: %v", err)
+		}
+		if id != "tr_1" {
+			t.Errorf("id = %q, want tr_1", id)
+		}
+	}
+	if len(keys) != 2 || keys[0] != "pass-through-pt-1" || keys[1] != keys[0] {
+		t.Errorf("idempotency keys = %v", keys)
+	}
+
+	params.IdempotencyKey = ""
+	if _, err := c.CreateTransfer(context.Background(), params); err == nil {
+		t.Error("expected an error without an idempotency key")
+	}
+}
@@ -0,0 +1,75 @@
+package stripe
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// FeeRate は 1 通貨の決済手数料（料率 + 1 決済あたりの固定額）
+type FeeRate struct {
+	BasisPoints int // 料率（1 bp = 0.01%。3.6% は 360）
+	Fixed       int // 固定額（通貨の最小単位。USD なら 30 = $0.30）
+}
+
+// FeeSchedule は通貨（小文字の ISO コード）ごとの決済手数料。
+// 寄付者が手数料を負担する（cover_fees）場合の上乗せ額の計算に使う。
+type FeeSchedule map[string]FeeRate
+
+// DefaultFeeSchedule は Stripe の国内カード決済の標準手数料（日本: 3.6%）
+var DefaultFeeSchedule = FeeSchedule{"jpy": {BasisPoints: 360}}
+
+// IntendedAmountMetadataKey は手数料を上乗せした決済で、寄付者が寄付しようとした額を載せる metadata のキー
+const IntendedAmountMetadataKey = "intended_amount"
+
+// GrossUp は手数料を差し引いても amount がプロジェクトに届くように上乗せした請求額を返す。
+// 請求額 = ceil((amount + 固定額) / (1 - 料率))。通貨が登録されていない場合は false。
+func (fs FeeSchedule) GrossUp(amount int, currency string) (int, bool) {
+	rate, ok := fs[strings.ToLower(currency)]
+	if !ok {
+		return 0, false
+	}
+	remain := 10000 - rate.BasisPoints
+	return int(math.Ceil(float64(amount+rate.Fixed) * 10000 / float64(remain))), true
+}
+
+// ParseFeeSchedule は "jpy:3.6%,usd:2.9%+30" 形式の手数料表を解析する。
+// 各要素は "通貨:料率%" に任意で "+固定額"（通貨の最小単位）を付ける。空文字列は DefaultFeeSchedule。
+func ParseFeeSchedule(s string) (FeeSchedule, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return DefaultFeeSchedule, nil
+	}
+	fs := FeeSchedule{}
+	for _, entry := range strings.Split(s, ",") {
+		currency, spec, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		currency = strings.ToLower(strings.TrimSpace(currency))
+		if !ok || len(currency) != 3 {
+			return nil, fmt.Errorf("fee schedule %q: expected currency:percent%%[+fixed]", entry)
+		}
+		percent, fixed, hasFixed := strings.Cut(strings.TrimSpace(spec), "+")
+		p, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(percent), "%"), 64)
+		if err != nil || p < 0 || p >= 50 {
+			return nil, fmt.Errorf("fee schedule %q: invalid percentage", entry)
+		}
+		rate := FeeRate{BasisPoints: int(math.Round(p * 100))}
+		if hasFixed {
+			rate.Fixed, err = strconv.Atoi(strings.TrimSpace(fixed))
+			if err != nil || rate.Fixed < 0 {
+				return nil, fmt.Errorf("fee schedule %q: invalid fixed amount", entry)
+			}
+		}
+		fs[currency] = rate
+	}
+	return fs, nil
+}
+
+// IntendedAmount は metadata の intended_amount（手数料を上乗せする前の寄付額）を返す。
+// 手数料を上乗せしていない決済では charged をそのまま返す。
+func IntendedAmount(metadata map[string]string, charged int) int {
+	if v, err := strconv.Atoi(metadata[IntendedAmountMetadataKey]); err == nil && v > 0 && v <= charged {
+		return v
+	}
+	return charged
+}
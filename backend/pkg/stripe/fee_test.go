@@ -0,0 +1,70 @@
+package stripe
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFeeSchedule_GrossUp(t *testing.T) {
+	fs := FeeSchedule{"jpy": {BasisPoints: 360}, "usd": {BasisPoints: 290, Fixed: 30}}
+	tests := []struct {
+		amount   int
+		currency string
+		want     int
+	}{
+		{1000, "jpy", 1038}, // 1038 × 3.6% = 37.4 → 1000 円が届く
+		{3000, "JPY", 3113},
+		{1000, "usd", 1061}, // $10.00 + 30¢ を 97.1% で割る
+	}
+	for _, tt := range tests {
+		got, ok := fs.GrossUp(tt.amount, tt.currency)
+		if !ok || got != tt.want {
+			t.Errorf("GrossUp(%d, %s) = %d, %t; want %d", tt.amount, tt.currency, got, ok, tt.want)
+		}
+		rate := fs[strings.ToLower(tt.currency)]
+		if net := got - (got*rate.BasisPoints+9999)/10000 - rate.Fixed; net < tt.amount {
+			t.Errorf("GrossUp(%d, %s): %d leaves only %d after fees", tt.amount, tt.currency, got, net)
+		}
+	}
+	if _, ok := fs.GrossUp(1000, "eur"); ok {
+		t.Error("expected an unknown currency to be rejected")
+	}
+}
+
+func TestParseFeeSchedule(t *testing.T) {
+	fs, err := ParseFeeSchedule(" JPY:3.6% , usd:2.9%+30 ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fs["jpy"] != (FeeRate{BasisPoints: 360}) || fs["usd"] != (FeeRate{BasisPoints: 290, Fixed: 30}) || len(fs) != 2 {
+		t.Errorf("unexpected schedule: %+v", fs)
+	}
+
+	if fs, err := ParseFeeSchedule(""); err != nil || fs["jpy"].BasisPoints != 360 {
+		t.Errorf("expected the default schedule, got %+v (%v)", fs, err)
+	}
+	for _, bad := range []string{"jpy", "yen-x:3.6%", "jpy:abc%", "jpy:60%", "usd:2.9%+x", "usd:2.9%+-1"} {
+		if _, err := ParseFeeSchedule(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
+
+func TestIntendedAmount(t *testing.T) {
+	tests := []struct {
+		metadata map[string]string
+		charged  int
+		want     int
+	}{
+		{map[string]string{"intended_amount": "1000"}, 1038, 1000},
+		{map[string]string{}, 1038, 1038},
+		{nil, 500, 500},
+		{map[string]string{"intended_amount": "x"}, 500, 500},
+		{map[string]string{"intended_amount": "900"}, 500, 500}, // 請求額を超える値は信用しない
+	}
+	for _, tt := range tests {
+		if got := IntendedAmount(tt.metadata, tt.charged); got != tt.want {
+			t.Errorf("IntendedAmount(%v, %d) = %d, want %d", tt.metadata, tt.charged, got, tt.want)
+		}
+	}
+}
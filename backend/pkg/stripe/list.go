@@ -0,0 +1,341 @@
+package stripe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// listPageSize は list API 1 回あたりの取得件数（Stripe の上限）
+const listPageSize = 100
+
+// Subscription は照合（reconcile）に必要なサブスクリプションの情報
+type Subscription struct {
+	ID       string
+	Status   string // active, past_due, unpaid, canceled, incomplete, trialing, paused など
+	Customer string
+	Paused   bool // pause_collection が設定されている
+	Amount   int  // 先頭 item の price.unit_amount
+	Currency string
+	// Interval / IntervalCount は先頭 item の price.recurring（例: "year", 1）
+	Interval      string
+	IntervalCount int
+	Metadata      map[string]string
+}
+
+// PaymentIntent は照合に必要な PaymentIntent の情報
+type PaymentIntent struct {
+	ID       string
+	Amount   int
+	Currency string
+	Status   string // succeeded, canceled, requires_payment_method など
+	Metadata map[string]string
+	Created  time.Time
+}
+
+// ConnectedAccount は照合に必要な連結アカウントの情報
+type ConnectedAccount struct {
+	ID               string
+	ChargesEnabled   bool
+	DetailsSubmitted bool
+}
+
+// BalanceTransaction は残高の増減 1 件（決済・返金・入金・手数料など）
+type BalanceTransaction struct {
+	ID            string
+	Type          string // charge, payment, refund, payout, stripe_fee など
+	Amount        int    // 総額（返金・入金は負）
+	Fee           int    // Stripe が差し引いた手数料
+	Net           int    // Amount - Fee（残高に入った額）
+	Currency      string
+	PaymentIntent string // 決済（charge / payment）の PaymentIntent ID。それ以外は空
+	Created       time.Time
+	AvailableOn   time.Time // 残高が入金可能になる日時
+}
+
+// IsPayment は決済による残高の増加（charge / payment）かどうかを返す
+func (t BalanceTransaction) IsPayment() bool {
+	return t.Type == "charge" || t.Type == "payment"
+}
+
+// Payout は連結アカウントの残高から銀行口座への入金
+type Payout struct {
+	ID          string
+	Amount      int
+	Currency    string
+	Status      string // paid, pending, in_transit, canceled, failed
+	ArrivalDate time.Time
+	Created     time.Time
+}
+
+// ListSubscriptions は accountID（空ならプラットフォーム）の全ステータスのサブスクリプションを
+// ページングしながらすべて取得する
+func (c *RealClient) ListSubscriptions(ctx context.Context, accountID string) ([]Subscription, error) {
+	if c.SecretKey == "" {
+		return nil, ErrNotConfigured
+	}
+	params := url.Values{}
+	params.Set("status", "all")
+
+	var subs []Subscription
+	err := c.listAll(ctx, "/v1/subscriptions", accountID, params, func(raw json.RawMessage) error {
+		sub, err := decodeSubscription(raw)
+		if err != nil {
+			return err
+		}
+		subs = append(subs, sub)
+		return nil
+	})
+	return subs, err
+}
+
+// GetSubscription は accountID（空ならプラットフォーム）のサブスクリプションを 1 件取得する。
+// 存在しない場合は IsNotFound() が true の *Error を返す
+func (c *RealClient) GetSubscription(ctx context.Context, accountID, subscriptionID string) (Subscription, error) {
+	if c.SecretKey == "" {
+		return Subscription{}, ErrNotConfigured
+	}
+	var raw json.RawMessage
+	path := fmt.Sprintf("/v1/subscriptions/%s", subscriptionID)
+	if err := c.do(ctx, apiRequest{method: http.MethodGet, path: path, stripeAccount: accountID}, &raw); err != nil {
+		return Subscription{}, fmt.Errorf("stripe get subscription: %w", err)
+	}
+	return decodeSubscription(raw)
+}
+
+// decodeSubscription は subscription オブジェクトから照合に必要な情報を取り出す
+func decodeSubscription(raw json.RawMessage) (Subscription, error) {
+	var s struct {
+		ID              string            `json:"id"`
+		Status          string            `json:"status"`
+		Customer        string            `json:"customer"`
+		PauseCollection *json.RawMessage  `json:"pause_collection"`
+		Metadata        map[string]string `json:"metadata"`
+		Items           struct {
+			Data []struct {
+				Price struct {
+					UnitAmount int    `json:"unit_amount"`
+					Currency   string `json:"currency"`
+					Recurring  struct {
+						Interval      string `json:"interval"`
+						IntervalCount int    `json:"interval_count"`
+					} `json:"recurring"`
+				} `json:"price"`
+			} `json:"data"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return Subscription{}, err
+	}
+	sub := Subscription{
+		ID:       s.ID,
+		Status:   s.Status,
+		Customer: s.Customer,
+		Paused:   s.PauseCollection != nil && string(*s.PauseCollection) != "null",
+		Metadata: s.Metadata,
+	}
+	if len(s.Items.Data) > 0 {
+		sub.Amount = s.Items.Data[0].Price.UnitAmount
+		sub.Currency = s.Items.Data[0].Price.Currency
+		sub.Interval = s.Items.Data[0].Price.Recurring.Interval
+		sub.IntervalCount = s.Items.Data[0].Price.Recurring.IntervalCount
+	}
+	return sub, nil
+}
+
+// ListPaymentIntents は accountID（空ならプラットフォーム）で createdSince 以降に作成された PaymentIntent をすべて取得する
+func (c *RealClient) ListPaymentIntents(ctx context.Context, accountID string, createdSince time.Time) ([]PaymentIntent, error) {
+	if c.SecretKey == "" {
+		return nil, ErrNotConfigured
+	}
+	params := url.Values{}
+	if !createdSince.IsZero() {
+		params.Set("created[gte]", strconv.FormatInt(createdSince.Unix(), 10))
+	}
+
+	var intents []PaymentIntent
+	err := c.listAll(ctx, "/v1/payment_intents", accountID, params, func(raw json.RawMessage) error {
+		var pi struct {
+			ID       string            `json:"id"`
+			Amount   int               `json:"amount"`
+			Currency string            `json:"currency"`
+			Status   string            `json:"status"`
+			Metadata map[string]string `json:"metadata"`
+			Created  int64             `json:"created"`
+		}
+		if err := json.Unmarshal(raw, &pi); err != nil {
+			return err
+		}
+		intents = append(intents, PaymentIntent{
+			ID:       pi.ID,
+			Amount:   pi.Amount,
+			Currency: pi.Currency,
+			Status:   pi.Status,
+			Metadata: pi.Metadata,
+			Created:  time.Unix(pi.Created, 0),
+		})
+		return nil
+	})
+	return intents, err
+}
+
+// ListConnectedAccounts はプラットフォームの連結アカウントをすべて取得する
+func (c *RealClient) ListConnectedAccounts(ctx context.Context) ([]ConnectedAccount, error) {
+	if c.SecretKey == "" {
+		return nil, ErrNotConfigured
+	}
+
+	var accounts []ConnectedAccount
+	err := c.listAll(ctx, "/v1/accounts", "", url.Values{}, func(raw json.RawMessage) error {
+		var a struct {
+			ID               string `json:"id"`
+			ChargesEnabled   bool   `json:"charges_enabled"`
+			DetailsSubmitted bool   `json:"details_submitted"`
+		}
+		if err := json.Unmarshal(raw, &a); err != nil {
+			return err
+		}
+		accounts = append(accounts, ConnectedAccount{
+			ID:               a.ID,
+			ChargesEnabled:   a.ChargesEnabled,
+			DetailsSubmitted: a.DetailsSubmitted,
+		})
+		return nil
+	})
+	return accounts, err
+}
+
+// ListBalanceTransactions は createdSince 以降の残高の増減をすべて取得する。
+// accountID が空ならプラットフォーム、そうでなければ連結アカウント（Stripe-Account）の残高。
+// 決済の PaymentIntent を得るため source（charge）を展開する。
+func (c *RealClient) ListBalanceTransactions(ctx context.Context, accountID string, createdSince time.Time) ([]BalanceTransaction, error) {
+	if c.SecretKey == "" {
+		return nil, ErrNotConfigured
+	}
+	params := url.Values{}
+	params.Set("expand[]", "data.source")
+	if !createdSince.IsZero() {
+		params.Set("created[gte]", strconv.FormatInt(createdSince.Unix(), 10))
+	}
+
+	var txs []BalanceTransaction
+	err := c.listAll(ctx, "/v1/balance_transactions", accountID, params, func(raw json.RawMessage) error {
+		var bt struct {
+			ID          string          `json:"id"`
+			Type        string          `json:"type"`
+			Amount      int             `json:"amount"`
+			Fee         int             `json:"fee"`
+			Net         int             `json:"net"`
+			Currency    string          `json:"currency"`
+			Source      json.RawMessage `json:"source"`
+			Created     int64           `json:"created"`
+			AvailableOn int64           `json:"available_on"`
+		}
+		if err := json.Unmarshal(raw, &bt); err != nil {
+			return err
+		}
+		tx := BalanceTransaction{
+			ID:          bt.ID,
+			Type:        bt.Type,
+			Amount:      bt.Amount,
+			Fee:         bt.Fee,
+			Net:         bt.Net,
+			Currency:    bt.Currency,
+			Created:     time.Unix(bt.Created, 0),
+			AvailableOn: time.Unix(bt.AvailableOn, 0),
+		}
+		// 展開されていない source は ID の文字列になる
+		var source struct {
+			PaymentIntent string `json:"payment_intent"`
+		}
+		if len(bt.Source) > 0 && bt.Source[0] == '{' {
+			if err := json.Unmarshal(bt.Source, &source); err != nil {
+				return err
+			}
+			tx.PaymentIntent = source.PaymentIntent
+		}
+		txs = append(txs, tx)
+		return nil
+	})
+	return txs, err
+}
+
+// ListPayouts は createdSince 以降に作成された連結アカウントの入金をすべて取得する
+func (c *RealClient) ListPayouts(ctx context.Context, accountID string, createdSince time.Time) ([]Payout, error) {
+	if c.SecretKey == "" {
+		return nil, ErrNotConfigured
+	}
+	params := url.Values{}
+	if !createdSince.IsZero() {
+		params.Set("created[gte]", strconv.FormatInt(createdSince.Unix(), 10))
+	}
+
+	var payouts []Payout
+	err := c.listAll(ctx, "/v1/payouts", accountID, params, func(raw json.RawMessage) error {
+		var po struct {
+			ID          string `json:"id"`
+			Amount      int    `json:"amount"`
+			Currency    string `json:"currency"`
+			Status      string `json:"status"`
+			ArrivalDate int64  `json:"arrival_date"`
+			Created     int64  `json:"created"`
+		}
+		if err := json.Unmarshal(raw, &po); err != nil {
+			return err
+		}
+		payouts = append(payouts, Payout{
+			ID:          po.ID,
+			Amount:      po.Amount,
+			Currency:    po.Currency,
+			Status:      po.Status,
+			ArrivalDate: time.Unix(po.ArrivalDate, 0),
+			Created:     time.Unix(po.Created, 0),
+		})
+		return nil
+	})
+	return payouts, err
+}
+
+// listAll は v1 の list API を starting_after でページングし、各要素を each に渡す。
+// stripeAccount が空でなければ連結アカウントのリソースを取得する。
+func (c *RealClient) listAll(ctx context.Context, path, stripeAccount string, params url.Values, each func(json.RawMessage) error) error {
+	startingAfter := ""
+	for {
+		q := url.Values{}
+		for k, v := range params {
+			q[k] = v
+		}
+		q.Set("limit", strconv.Itoa(listPageSize))
+		if startingAfter != "" {
+			q.Set("starting_after", startingAfter)
+		}
+
+		var page struct {
+			Data    []json.RawMessage `json:"data"`
+			HasMore bool              `json:"has_more"`
+		}
+		if err := c.do(ctx, apiRequest{method: http.MethodGet, path: path + "?" + q.Encode(), stripeAccount: stripeAccount}, &page); err != nil {
+			return fmt.Errorf("stripe list %s: %w", path, err)
+		}
+
+		for _, raw := range page.Data {
+			if err := each(raw); err != nil {
+				return err
+			}
+		}
+		if !page.HasMore || len(page.Data) == 0 {
+			return nil
+		}
+		var last struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(page.Data[len(page.Data)-1], &last); err != nil {
+			return err
+		}
+		startingAfter = last.ID
+	}
+}
@@ -0,0 +1,173 @@
+package stripe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newListTestClient(t *testing.T, handler http.HandlerFunc) *RealClient {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	c := NewClient("sk_test", "whsec")
+	c.APIBase = srv.URL
+	return c
+}
+
+func TestRealClient_ListSubscriptions_Paginates(t *testing.T) {
+	var requests []string
+	c := newListTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/subscriptions" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("status") != "all" {
+			t.Errorf("status = %q, want all", r.URL.Query().Get("status"))
+		}
+		requests = append(requests, r.URL.Query().Get("starting_after"))
+		if r.URL.Query().Get("starting_after") == "" {
+			fmt.Fprint(w, `{"data":[{"id":"sub_1","status":"active","customer":"cus_1","pause_collection":null,"metadata":{"project_id":"p1"},"items":{"data":[{"price":{"unit_amount":1000,"currency":"jpy","recurring":{"interval":"year","interval_count":1}}}]}}],"has_more":true}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":[{"id":"sub_2","status":"active","pause_collection":{"behavior":"void"},"items":{"data":[]}}],"has_more":false}`)
+	})
+
+	subs, err := c.ListSubscriptions(context.Background(), "")
+	if err != nil {
+		t.Fatalf("ListSubscriptions: %v", err)
+	}
+	if len(requests) != 2 || requests[1] != "sub_1" {
+		t.Errorf("requests = %v, want second page after sub_1", requests)
+	}
+	if len(subs) != 2 {
+		t.Fatalf("len(subs) = %d, want 2", len(subs))
+	}
+	if subs[0].Amount != 1000 || subs[0].Currency != "jpy" || subs[0].Interval != "year" || subs[0].IntervalCount != 1 || subs[0].Paused || subs[0].Metadata["project_id"] != "p1" {
+		t.Errorf("subs[0] = %+v", subs[0])
+	}
+	if !subs[1].Paused {
+		t.Errorf("subs[1].Paused = false, want true")
+	}
+}
+
+func TestRealClient_ListPaymentIntents_CreatedFilter(t *testing.T) {
+	since := time.Unix(1700000000, 0)
+	c := newListTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("created[gte]"); got != "1700000000" {
+			t.Errorf("created[gte] = %q", got)
+		}
+		if got := r.Header.Get("Stripe-Account"); got != "acct_1" {
+			t.Errorf("Stripe-Account = %q, want acct_1", got)
+		}
+		fmt.Fprint(w, `{"data":[{"id":"pi_1","amount":500,"currency":"jpy","status":"succeeded","created":1700000100}],"has_more":false}`)
+	})
+
+	intents, err := c.ListPaymentIntents(context.Background(), "acct_1", since)
+	if err != nil {
+		t.Fatalf("ListPaymentIntents: %v", err)
+	}
+	if len(intents) != 1 || intents[0].Amount != 500 || !intents[0].Created.Equal(time.Unix(1700000100, 0)) {
+		t.Errorf("intents = %+v", intents)
+	}
+}
+
+func TestRealClient_GetSubscription(t *testing.T) {
+	c := newListTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Stripe-Account") != "acct_1" {
+			t.Errorf("Stripe-Account = %q, want acct_1", r.Header.Get("Stripe-Account"))
+		}
+		if r.URL.Path == "/v1/subscriptions/sub_gone" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"type":"invalid_request_error","code":"resource_missing","message":"No such subscription: 'sub_gone'"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"sub_1","status":"canceled","pause_collection":null,"items":{"data":[{"price":{"unit_amount":1000,"currency":"jpy","recurring":{"interval":"month","interval_count":1}}}]}}`)
+	})
+
+	sub, err := c.GetSubscription(context.Background(), "acct_1", "sub_1")
+	if err != nil {
+		t.Fatalf("GetSubscription: %v", err)
+	}
+	if sub.ID != "sub_1" || sub.Status != "canceled" || sub.Amount != 1000 {
+		t.Errorf("sub = %+v", sub)
+	}
+
+	_, err = c.GetSubscription(context.Background(), "acct_1", "sub_gone")
+	if se, ok := AsError(err); !ok || !se.IsNotFound() {
+		t.Errorf("err = %v, want not found", err)
+	}
+}
+
+func TestRealClient_ListConnectedAccounts_Error(t *testing.T) {
+	c := newListTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":{"message":"Invalid API Key"}}`)
+	})
+
+	if _, err := c.ListConnectedAccounts(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestRealClient_ListSubscriptions_NotConfigured(t *testing.T) {
+	c := NewClient("", "")
+	if _, err := c.ListSubscriptions(context.Background(), ""); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("err = %v, want ErrNotConfigured", err)
+	}
+}
+
+func TestRealClient_ListBalanceTransactions_ConnectedAccount(t *testing.T) {
+	c := newListTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/balance_transactions" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Stripe-Account"); got != "acct_1" {
+			t.Errorf("Stripe-Account = %q, want acct_1", got)
+		}
+		if got := r.URL.Query().Get("expand[]"); got != "data.source" {
+			t.Errorf("expand[] = %q, want data.source", got)
+		}
+		fmt.Fprint(w, `{"data":[
+			{"id":"txn_1","type":"charge","amount":1000,"fee":36,"net":964,"currency":"jpy","source":{"id":"ch_1","object":"charge","payment_intent":"pi_1"},"created":1700000000,"available_on":1700600000},
+			{"id":"txn_2","type":"payout","amount":-964,"fee":0,"net":-964,"currency":"jpy","source":"po_1","created":1700700000}
+		],"has_more":false}`)
+	})
+
+	txs, err := c.ListBalanceTransactions(context.Background(), "acct_1", time.Time{})
+	if err != nil {
+		t.Fatalf("ListBalanceTransactions: %v", err)
+	}
+	if len(txs) != 2 {
+		t.Fatalf("len(txs) = %d, want 2", len(txs))
+	}
+	if !txs[0].IsPayment() || txs[0].Fee != 36 || txs[0].Net != 964 || txs[0].PaymentIntent != "pi_1" || !txs[0].AvailableOn.Equal(time.Unix(1700600000, 0)) {
+		t.Errorf("txs[0] = %+v", txs[0])
+	}
+	if txs[1].IsPayment() || txs[1].PaymentIntent != "" || txs[1].Amount != -964 {
+		t.Errorf("txs[1] = %+v", txs[1])
+	}
+}
+
+func TestRealClient_ListPayouts(t *testing.T) {
+	c := newListTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/payouts" || r.Header.Get("Stripe-Account") != "acct_1" {
+			t.Errorf("unexpected request %s (Stripe-Account=%q)", r.URL.Path, r.Header.Get("Stripe-Account"))
+		}
+		if got := r.URL.Query().Get("created[gte]"); got != "1700000000" {
+			t.Errorf("created[gte] = %q", got)
+		}
+		fmt.Fprint(w, `{"data":[{"id":"po_1","amount":964,"currency":"jpy","status":"paid","arrival_date":1700800000,"created":1700700000}],"has_more":false}`)
+	})
+
+	payouts, err := c.ListPayouts(context.Background(), "acct_1", time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("ListPayouts: %v", err)
+	}
+	if len(payouts) != 1 || payouts[0].Amount != 964 || payouts[0].Status != "paid" || !payouts[0].ArrivalDate.Equal(time.Unix(1700800000, 0)) {
+		t.Errorf("payouts = %+v", payouts)
+	}
+}
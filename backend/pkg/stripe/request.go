@@ -0,0 +1,258 @@
+package stripe
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	mathrand "math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Stripe のエラー種別（error.type）
+const (
+	ErrorTypeAPI            = "api_error"
+	ErrorTypeCard           = "card_error"
+	ErrorTypeIdempotency    = "idempotency_error"
+	ErrorTypeInvalidRequest = "invalid_request_error"
+	ErrorTypeAuthentication = "authentication_error"
+	ErrorTypeRateLimit      = "rate_limit_error"
+)
+
+// Error は Stripe API のエラーレスポンス
+type Error struct {
+	HTTPStatus  int
+	Type        string // ErrorType* のいずれか
+	Code        string // 例: "card_declined", "amount_too_small", "rate_limit"
+	DeclineCode string // card_error のみ。例: "insufficient_funds"
+	Param       string // 問題のあるパラメータ名
+	Message     string
+}
+
+func (e *Error) Error() string {
+	msg := e.Type
+	if e.Code != "" {
+		msg += " (" + e.Code + ")"
+	}
+	return fmt.Sprintf("%s: %s", msg, e.Message)
+}
+
+// IsRateLimited はレート制限によるエラーかどうかを返す
+func (e *Error) IsRateLimited() bool {
+	return e.HTTPStatus == http.StatusTooManyRequests || e.Type == ErrorTypeRateLimit || e.Code == "rate_limit"
+}
+
+// IsCardError はカード起因（拒否・期限切れなど）のエラーかどうかを返す
+func (e *Error) IsCardError() bool {
+	return e.Type == ErrorTypeCard
+}
+
+// IsAuthentication は API キーの問題によるエラーかどうかを返す
+func (e *Error) IsAuthentication() bool {
+	return e.HTTPStatus == http.StatusUnauthorized || e.Type == ErrorTypeAuthentication
+}
+
+// IsNotFound は対象のオブジェクトが（そのアカウントに）存在しないエラーかどうかを返す
+func (e *Error) IsNotFound() bool {
+	return e.HTTPStatus == http.StatusNotFound || e.Code == "resource_missing"
+}
+
+// IsServerError は Stripe 側の障害（5xx）かどうかを返す
+func (e *Error) IsServerError() bool {
+	return e.HTTPStatus >= 500
+}
+
+// AsError は err に含まれる *Error を取り出す
+func AsError(err error) (*Error, bool) {
+	var se *Error
+	if errors.As(err, &se) {
+		return se, true
+	}
+	return nil, false
+}
+
+const (
+	defaultMaxRetries  = 2
+	defaultBackoffBase = 500 * time.Millisecond
+	maxBackoff         = 5 * time.Second
+)
+
+// apiRequest は Stripe API への 1 回の呼び出し
+type apiRequest struct {
+	method        string
+	path          string     // クエリ文字列を含んでよい
+	form          url.Values // v1: フォームエンコードのボディ
+	json          any        // v2: JSON ボディ
+	v2            bool       // v2 API（Bearer 認証 + Stripe-Version）
+	stripeAccount string     // Stripe-Account ヘッダー
+	// idempotencyKey は POST の Idempotency-Key。空なら呼び出しごとに生成する。
+	// 呼び出し側の記録から決めたキーを渡すと、プロセスをまたいだ再実行でも二重に作成されない
+	idempotencyKey string
+}
+
+// do は API を呼び出し、成功時はレスポンスを out にデコードする。
+// 429 / 5xx はジッター付き指数バックオフで再試行し、POST には Idempotency-Key を付けて
+// 再試行で連結アカウントや Checkout Session が二重に作成されないようにする。
+// エラーレスポンスは *Error として返す。
+func (c *RealClient) do(ctx context.Context, r apiRequest, out any) error {
+	var body []byte
+	contentType := ""
+	switch {
+	case r.json != nil:
+		b, err := json.Marshal(r.json)
+		if err != nil {
+			return err
+		}
+		body, contentType = b, "application/json"
+	case r.form != nil:
+		body, contentType = []byte(r.form.Encode()), "application/x-www-form-urlencoded"
+	}
+
+	idempotencyKey := r.idempotencyKey
+	if r.method == http.MethodPost && idempotencyKey == "" {
+		idempotencyKey = newIdempotencyKey()
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, r.method, c.apiURL(r.path), bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if r.v2 {
+			req.Header.Set("Authorization", "Bearer "+c.SecretKey)
+			req.Header.Set("Stripe-Version", StripeAPIVersion)
+		} else {
+			req.SetBasicAuth(c.SecretKey, "")
+		}
+		if idempotencyKey != "" {
+			req.Header.Set("Idempotency-Key", idempotencyKey)
+		}
+		if r.stripeAccount != "" {
+			req.Header.Set("Stripe-Account", r.stripeAccount)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode < 400 {
+			if out == nil {
+				return nil
+			}
+			return json.Unmarshal(respBody, out)
+		}
+
+		apiErr := parseError(resp.StatusCode, respBody)
+		if attempt >= c.maxRetries() || !shouldRetry(resp) {
+			return apiErr
+		}
+		if err := c.sleep(ctx, attempt); err != nil {
+			return apiErr
+		}
+	}
+}
+
+// shouldRetry は 429 / 5xx を再試行対象とする。Stripe-Should-Retry ヘッダーがあればそれに従う。
+func shouldRetry(resp *http.Response) bool {
+	switch resp.Header.Get("Stripe-Should-Retry") {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+func (c *RealClient) maxRetries() int {
+	if c.MaxRetries < 0 {
+		return 0
+	}
+	return c.MaxRetries
+}
+
+// sleep は attempt 回目の再試行前に待つ（base * 2^attempt の 50〜100% のジッター、上限 maxBackoff）
+func (c *RealClient) sleep(ctx context.Context, attempt int) error {
+	base := c.RetryBackoff
+	if base <= 0 {
+		base = defaultBackoffBase
+	}
+	d := base << attempt
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+	d = d/2 + time.Duration(mathrand.Int64N(int64(d/2)+1))
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// parseError はエラーレスポンスのボディを *Error にする
+func parseError(status int, body []byte) *Error {
+	var resp struct {
+		Error *struct {
+			Type        string `json:"type"`
+			Code        string `json:"code"`
+			DeclineCode string `json:"decline_code"`
+			Param       string `json:"param"`
+			Message     string `json:"message"`
+		} `json:"error"`
+	}
+	e := &Error{HTTPStatus: status}
+	if err := json.Unmarshal(body, &resp); err == nil && resp.Error != nil {
+		e.Type = resp.Error.Type
+		e.Code = resp.Error.Code
+		e.DeclineCode = resp.Error.DeclineCode
+		e.Param = resp.Error.Param
+		e.Message = resp.Error.Message
+	}
+	if e.Type == "" {
+		e.Type = defaultErrorType(status)
+	}
+	if e.Message == "" {
+		e.Message = strings.ToLower(http.StatusText(status))
+	}
+	return e
+}
+
+// defaultErrorType は error.type がないレスポンス（v2 API やプロキシのエラーなど）の種別を HTTP ステータスから推定する
+func defaultErrorType(status int) string {
+	switch {
+	case status == http.StatusUnauthorized:
+		return ErrorTypeAuthentication
+	case status == http.StatusTooManyRequests:
+		return ErrorTypeRateLimit
+	case status == http.StatusPaymentRequired:
+		return ErrorTypeCard
+	case status >= 500:
+		return ErrorTypeAPI
+	}
+	return ErrorTypeInvalidRequest
+}
+
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,145 @@
+package stripe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newRetryTestClient(t *testing.T, handler http.HandlerFunc) *RealClient {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	c := NewClient("sk_test", "")
+	c.APIBase = srv.URL
+	c.RetryBackoff = time.Millisecond
+	return c
+}
+
+func checkoutTestParams() CheckoutParams {
+	return CheckoutParams{ProjectID: "p1", Amount: 1000, Currency: "jpy", SuccessURL: "https://example.com/ok", CancelURL: "https://example.com/ng"}
+}
+
+func TestRealClient_Do_TypedError(t *testing.T) {
+	c := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusPaymentRequired)
+		fmt.Fprint(w, `{"error":{"type":"card_error","code":"card_declined","decline_code":"insufficient_funds","param":"payment_method","message":"Your card has insufficient funds."}}`)
+	})
+
+	_, err := c.CreateCheckoutSession(context.Background(), checkoutTestParams())
+	se, ok := AsError(err)
+	if !ok {
+		t.Fatalf("expected *Error, got %T: %v", err, err)
+	}
+	if se.HTTPStatus != http.StatusPaymentRequired || se.Type != ErrorTypeCard || se.Code != "card_declined" ||
+		se.DeclineCode != "insufficient_funds" || se.Param != "payment_method" || !se.IsCardError() {
+		t.Errorf("error = %+v", se)
+	}
+}
+
+func TestRealClient_Do_ErrorWithoutBody(t *testing.T) {
+	c := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	_, err := c.GetAccountOnboarded(context.Background(), "acct_1")
+	var se *Error
+	if !errors.As(err, &se) || se.Type != ErrorTypeAuthentication || !se.IsAuthentication() {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestRealClient_Do_RetriesRateLimitWithSameIdempotencyKey(t *testing.T) {
+	var keys []string
+	c := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"error":{"type":"invalid_request_error","code":"rate_limit","message":"slow down"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"cs_1","url":"https://checkout.example/cs_1"}`)
+	})
+
+	got, err := c.CreateCheckoutSession(context.Background(), checkoutTestParams())
+	if err != nil {
+		t.Fatalf("CreateCheckoutSession: %v", err)
+	}
+	if got.ID != "cs_1" || got.URL != "https://checkout.example/cs_1" {
+		t.Errorf("session = %+v", got)
+	}
+	if len(keys) != 3 {
+		t.Fatalf("attempts = %d, want 3", len(keys))
+	}
+	if keys[0] == "" || keys[0] != keys[1] || keys[1] != keys[2] {
+		t.Errorf("idempotency keys = %v, want the same non-empty key on every attempt", keys)
+	}
+}
+
+func TestRealClient_Do_GivesUpAfterMaxRetries(t *testing.T) {
+	attempts := 0
+	c := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	err := c.CancelSubscription(context.Background(), "", "sub_1")
+	se, ok := AsError(err)
+	if !ok || !se.IsServerError() {
+		t.Fatalf("err = %v", err)
+	}
+	if attempts != defaultMaxRetries+1 {
+		t.Errorf("attempts = %d, want %d", attempts, defaultMaxRetries+1)
+	}
+}
+
+func TestRealClient_Do_NoRetryOnBadRequest(t *testing.T) {
+	attempts := 0
+	c := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":{"type":"invalid_request_error","code":"amount_too_small","param":"line_items[0][price_data][unit_amount]","message":"Amount must be at least ¥50"}}`)
+	})
+
+	_, err := c.CreateCheckoutSession(context.Background(), checkoutTestParams())
+	se, ok := AsError(err)
+	if !ok || se.Code != "amount_too_small" {
+		t.Fatalf("err = %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestRealClient_Do_RespectsShouldRetryHeader(t *testing.T) {
+	attempts := 0
+	c := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Stripe-Should-Retry", "false")
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	if err := c.PauseSubscription(context.Background(), "", "sub_1"); err == nil {
+		t.Fatal("expected error")
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestRealClient_Do_NoIdempotencyKeyOnGet(t *testing.T) {
+	c := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if key := r.Header.Get("Idempotency-Key"); key != "" {
+			t.Errorf("GET sent Idempotency-Key %q", key)
+		}
+		fmt.Fprint(w, `{"requirements":{"currently_due":[]}}`)
+	})
+
+	if _, err := c.GetAccountOnboarded(context.Background(), "acct_1"); err != nil {
+		t.Fatal(err)
+	}
+}
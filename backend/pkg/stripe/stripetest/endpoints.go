@@ -0,0 +1,711 @@
+package stripetest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// v2 accounts / account links
+// ---------------------------------------------------------------------------
+
+func (s *Server) createAccount(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		ContactEmail string `json:"contact_email"`
+		DisplayName  string `json:"display_name"`
+		Identity     struct {
+			Country string `json:"country"`
+		} `json:"identity"`
+	}
+	if r.Header.Get("Content-Type") != "application/json" {
+		writeError(w, http.StatusBadRequest, "v2 endpoints require a JSON body")
+		return
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if body.Identity.Country == "" {
+		writeError(w, http.StatusBadRequest, "identity.country is required")
+		return
+	}
+
+	s.mu.Lock()
+	a := &Account{
+		ID:           s.newID("acct"),
+		Email:        body.ContactEmail,
+		DisplayName:  body.DisplayName,
+		Country:      body.Identity.Country,
+		CurrentlyDue: []string{"business_profile.url", "external_account"},
+		Created:      time.Now(),
+	}
+	s.accounts[a.ID] = a
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{"id": a.ID, "object": "v2.core.account"})
+}
+
+func (s *Server) getAccount(w http.ResponseWriter, r *http.Request) {
+	a, ok := s.Account(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "No such account: "+r.PathValue("id"))
+		return
+	}
+	resp := map[string]any{"id": a.ID, "object": "v2.core.account"}
+	if r.URL.Query().Get("include") == "requirements" {
+		due := a.CurrentlyDue
+		if due == nil {
+			due = []string{}
+		}
+		resp["requirements"] = map[string]any{"currently_due": due}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) createAccountLink(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Account string `json:"account"`
+		UseCase struct {
+			Type              string `json:"type"`
+			AccountOnboarding struct {
+				ReturnURL  string `json:"return_url"`
+				RefreshURL string `json:"refresh_url"`
+			} `json:"account_onboarding"`
+		} `json:"use_case"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if _, ok := s.Account(body.Account); !ok {
+		writeError(w, http.StatusNotFound, "No such account: "+body.Account)
+		return
+	}
+	if body.UseCase.Type != "account_onboarding" || body.UseCase.AccountOnboarding.ReturnURL == "" || body.UseCase.AccountOnboarding.RefreshURL == "" {
+		writeError(w, http.StatusBadRequest, "use_case.account_onboarding.return_url and refresh_url are required")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"object": "v2.core.account_link",
+		"url":    s.URL + "/connect/onboarding/" + body.Account,
+	})
+}
+
+func (s *Server) listAccounts(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	ids := make([]string, 0, len(s.accounts))
+	for id := range s.accounts {
+		ids = append(ids, id)
+	}
+	ids, hasMore := pageFromQuery(ids, r)
+	data := make([]map[string]any, 0, len(ids))
+	for _, id := range ids {
+		data = append(data, accountJSON(s.accounts[id]))
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{"object": "list", "data": data, "has_more": hasMore})
+}
+
+func (s *Server) getAccountV1(w http.ResponseWriter, r *http.Request) {
+	a, ok := s.Account(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "No such account: "+r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, accountJSON(&a))
+}
+
+// accountJSON は v1 の Account オブジェクト（list / retrieve / account.updated の data.object）
+func accountJSON(a *Account) map[string]any {
+	var disabledReason any
+	if a.DisabledReason != "" {
+		disabledReason = a.DisabledReason
+	} else if len(a.CurrentlyDue) > 0 {
+		disabledReason = "requirements.pending_verification"
+	}
+	return map[string]any{
+		"id":                a.ID,
+		"object":            "account",
+		"charges_enabled":   a.Enabled(),
+		"payouts_enabled":   a.Enabled(),
+		"details_submitted": len(a.CurrentlyDue) == 0,
+		"requirements": map[string]any{
+			"currently_due":   nonNil(a.CurrentlyDue),
+			"past_due":        nonNil(a.PastDue),
+			"disabled_reason": disabledReason,
+		},
+	}
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// intervalCount は recurring[interval_count] を読む（省略時は 1）
+func intervalCount(v string) (int, bool) {
+	if v == "" {
+		return 1, true
+	}
+	n, err := strconv.Atoi(v)
+	return n, err == nil && n > 0
+}
+
+// ---------------------------------------------------------------------------
+// v1 checkout sessions
+// ---------------------------------------------------------------------------
+
+func (s *Server) createCheckoutSession(w http.ResponseWriter, r *http.Request) {
+	f := r.PostForm
+	cs := &CheckoutSession{
+		Mode:          f.Get("mode"),
+		Currency:      f.Get("line_items[0][price_data][currency]"),
+		Interval:      f.Get("line_items[0][price_data][recurring][interval]"),
+		ProductName:   f.Get("line_items[0][price_data][product_data][name]"),
+		SuccessURL:    f.Get("success_url"),
+		CancelURL:     f.Get("cancel_url"),
+		Locale:        f.Get("locale"),
+		StripeAccount: r.Header.Get("Stripe-Account"),
+	}
+
+	switch cs.Mode {
+	case "payment":
+		if cs.Interval != "" {
+			writeError(w, http.StatusBadRequest, "recurring prices require mode=subscription")
+			return
+		}
+		cs.Metadata = formMetadata(f, "payment_intent_data")
+	case "subscription":
+		if cs.Interval == "" {
+			writeError(w, http.StatusBadRequest, "mode=subscription requires a recurring price")
+			return
+		}
+		cs.Metadata = formMetadata(f, "subscription_data")
+	default:
+		writeError(w, http.StatusBadRequest, "Invalid mode: "+cs.Mode)
+		return
+	}
+	amount, err := strconv.Atoi(f.Get("line_items[0][price_data][unit_amount]"))
+	if err != nil || amount <= 0 {
+		writeError(w, http.StatusBadRequest, "line_items[0][price_data][unit_amount] must be a positive integer")
+		return
+	}
+	cs.Amount = amount
+	if cs.Interval != "" {
+		count, ok := intervalCount(f.Get("line_items[0][price_data][recurring][interval_count]"))
+		if !ok {
+			writeError(w, http.StatusBadRequest, "line_items[0][price_data][recurring][interval_count] must be a positive integer")
+			return
+		}
+		cs.IntervalCount = count
+	}
+	if cs.Currency == "" || f.Get("line_items[0][quantity]") == "" || cs.ProductName == "" {
+		writeError(w, http.StatusBadRequest, "line_items[0] requires currency, quantity and product_data[name]")
+		return
+	}
+	if cs.SuccessURL == "" || cs.CancelURL == "" {
+		writeError(w, http.StatusBadRequest, "success_url and cancel_url are required")
+		return
+	}
+	if cs.StripeAccount != "" {
+		if _, ok := s.Account(cs.StripeAccount); !ok {
+			writeError(w, http.StatusForbidden, "The provided key does not have access to account '"+cs.StripeAccount+"'")
+			return
+		}
+	}
+	if cs.Customer = f.Get("customer"); cs.Customer != "" {
+		if f.Get("customer_creation") != "" {
+			writeError(w, http.StatusBadRequest, "You may only specify one of these parameters: customer, customer_creation.")
+			return
+		}
+		s.mu.Lock()
+		c, ok := s.customers[cs.Customer]
+		s.mu.Unlock()
+		// Customer はアカウントごとなので、別アカウントの Customer は存在しない扱い
+		if !ok || c.StripeAccount != cs.StripeAccount {
+			writeError(w, http.StatusBadRequest, "No such customer: '"+cs.Customer+"'")
+			return
+		}
+	}
+	cs.SessionMetadata = formMetadata(f, "")
+	for i := 0; ; i++ {
+		t := f.Get("payment_method_types[" + strconv.Itoa(i) + "]")
+		if t == "" {
+			break
+		}
+		cs.PaymentMethodTypes = append(cs.PaymentMethodTypes, t)
+	}
+	if msg := checkPaymentMethodTypes(cs, f.Get("customer_creation")); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+	if msg := parseApplicationFee(cs, f); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	s.mu.Lock()
+	cs.ID = s.newID("cs")
+	s.sessions[cs.ID] = cs
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id":     cs.ID,
+		"object": "checkout.session",
+		"mode":   cs.Mode,
+		"url":    s.URL + "/checkout/" + cs.ID,
+	})
+}
+
+// parseApplicationFee は application fee（単発は金額、定期は割合）を読み取る。
+// Stripe と同じく、連結アカウントでの決済（Stripe-Account ヘッダー）でのみ受け付ける。問題なければ空文字を返す
+func parseApplicationFee(cs *CheckoutSession, f url.Values) string {
+	amount := f.Get("payment_intent_data[application_fee_amount]")
+	percent := f.Get("subscription_data[application_fee_percent]")
+	if amount == "" && percent == "" {
+		return ""
+	}
+	if cs.StripeAccount == "" {
+		return "Can only apply an application fee when the request is made on behalf of another account"
+	}
+	if amount != "" {
+		n, err := strconv.Atoi(amount)
+		if err != nil || n < 0 || n > cs.Amount || cs.Mode != "payment" {
+			return "payment_intent_data[application_fee_amount] must be between 0 and the amount in payment mode"
+		}
+		cs.ApplicationFeeAmount = n
+	}
+	if percent != "" {
+		n, err := strconv.Atoi(percent)
+		if err != nil || n < 0 || n > 100 || cs.Mode != "subscription" {
+			return "subscription_data[application_fee_percent] must be between 0 and 100 in subscription mode"
+		}
+		cs.ApplicationFeePercent = n
+	}
+	return ""
+}
+
+// createTransfer はプラットフォームから連結アカウントへの送金。送金先が存在しない、
+// または決済・入金が有効でない（オンボーディング未完了・停止中）場合は Stripe と同じく 400 を返す
+func (s *Server) createTransfer(w http.ResponseWriter, r *http.Request) {
+	f := r.PostForm
+	if r.Header.Get("Stripe-Account") != "" {
+		writeError(w, http.StatusBadRequest, "Transfers must be created by the platform account")
+		return
+	}
+	amount, err := strconv.Atoi(f.Get("amount"))
+	if err != nil || amount <= 0 || f.Get("currency") == "" {
+		writeError(w, http.StatusBadRequest, "amount must be a positive integer and currency is required")
+		return
+	}
+	destination := f.Get("destination")
+	account, ok := s.Account(destination)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "No such destination: '"+destination+"'")
+		return
+	}
+	if !account.Enabled() {
+		writeError(w, http.StatusBadRequest, "Your destination account needs to have at least one of the following capabilities enabled: transfers, crypto_transfers, legacy_payments")
+		return
+	}
+
+	s.mu.Lock()
+	tr := &Transfer{
+		ID:            s.newID("tr"),
+		Destination:   destination,
+		Amount:        amount,
+		Currency:      f.Get("currency"),
+		TransferGroup: f.Get("transfer_group"),
+		Metadata:      formMetadata(f, ""),
+		Created:       time.Now(),
+	}
+	s.transfers[tr.ID] = tr
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id":             tr.ID,
+		"object":         "transfer",
+		"amount":         tr.Amount,
+		"currency":       tr.Currency,
+		"destination":    tr.Destination,
+		"transfer_group": tr.TransferGroup,
+		"metadata":       copyMetadata(tr.Metadata),
+		"created":        tr.Created.Unix(),
+	})
+}
+
+// checkPaymentMethodTypes は Stripe と同じく、コンビニ・銀行振込を単発・JPY に限る。問題なければ空文字を返す
+func checkPaymentMethodTypes(cs *CheckoutSession, customerCreation string) string {
+	for _, t := range cs.PaymentMethodTypes {
+		switch t {
+		case "card":
+		case "konbini", "customer_balance":
+			if cs.Mode != "payment" {
+				return "The payment method `" + t + "` cannot be used in subscription mode."
+			}
+			if cs.Currency != "jpy" {
+				return "The payment method `" + t + "` does not support currency " + cs.Currency + "."
+			}
+			if t == "customer_balance" && customerCreation != "always" && cs.Customer == "" {
+				return "The payment method `customer_balance` requires a Customer."
+			}
+		default:
+			return "Invalid payment_method_types: " + t
+		}
+	}
+	return ""
+}
+
+// ---------------------------------------------------------------------------
+// v1 subscriptions / payment intents
+// ---------------------------------------------------------------------------
+
+func (s *Server) listSubscriptions(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	account := r.Header.Get("Stripe-Account")
+	s.mu.Lock()
+	ids := make([]string, 0, len(s.subscriptions))
+	for id, sub := range s.subscriptions {
+		if sub.StripeAccount != account {
+			continue
+		}
+		// status 未指定時は Stripe と同様に canceled を除外する
+		if status == "all" || (status == "" && sub.Status != "canceled") || sub.Status == status {
+			ids = append(ids, id)
+		}
+	}
+	ids, hasMore := pageFromQuery(ids, r)
+	data := make([]map[string]any, 0, len(ids))
+	for _, id := range ids {
+		data = append(data, subscriptionJSON(s.subscriptions[id]))
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{"object": "list", "data": data, "has_more": hasMore})
+}
+
+func (s *Server) getSubscription(w http.ResponseWriter, r *http.Request) {
+	sub, ok := s.Subscription(r.PathValue("id"))
+	// サブスクリプションは作成されたアカウントにしか存在しない
+	if !ok || sub.StripeAccount != r.Header.Get("Stripe-Account") {
+		writeError(w, http.StatusNotFound, "No such subscription: '"+r.PathValue("id")+"'")
+		return
+	}
+	writeJSON(w, http.StatusOK, subscriptionJSON(&sub))
+}
+
+func (s *Server) updateSubscription(w http.ResponseWriter, r *http.Request) {
+	f := r.PostForm
+	s.mu.Lock()
+	sub, ok := s.subscriptions[r.PathValue("id")]
+	if !ok || sub.StripeAccount != r.Header.Get("Stripe-Account") {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "No such subscription: '"+r.PathValue("id")+"'")
+		return
+	}
+	if sub.Status == "canceled" {
+		s.mu.Unlock()
+		writeError(w, http.StatusBadRequest, "A canceled subscription can only update its cancellation_details and metadata.")
+		return
+	}
+
+	if _, ok := f["pause_collection"]; ok {
+		// pause_collection= （空文字）で一時停止を解除
+		sub.Paused = false
+	}
+	if f.Get("pause_collection[behavior]") != "" {
+		sub.Paused = true
+	}
+	if itemID := f.Get("items[0][id]"); itemID != "" {
+		if itemID != sub.ItemID {
+			s.mu.Unlock()
+			writeError(w, http.StatusBadRequest, "No such subscription item: '"+itemID+"'")
+			return
+		}
+		amount, err := strconv.Atoi(f.Get("items[0][price_data][unit_amount]"))
+		if err != nil || amount <= 0 || f.Get("items[0][price_data][currency]") == "" || f.Get("items[0][price_data][recurring][interval]") == "" {
+			s.mu.Unlock()
+			writeError(w, http.StatusBadRequest, "items[0][price_data] requires currency, unit_amount and recurring[interval]")
+			return
+		}
+		count, ok := intervalCount(f.Get("items[0][price_data][recurring][interval_count]"))
+		if !ok {
+			s.mu.Unlock()
+			writeError(w, http.StatusBadRequest, "items[0][price_data][recurring][interval_count] must be a positive integer")
+			return
+		}
+		sub.Amount = amount
+		sub.Currency = f.Get("items[0][price_data][currency]")
+		sub.Interval = f.Get("items[0][price_data][recurring][interval]")
+		sub.IntervalCount = count
+	}
+	resp := subscriptionJSON(sub)
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) cancelSubscription(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	sub, ok := s.subscriptions[r.PathValue("id")]
+	if !ok || sub.StripeAccount != r.Header.Get("Stripe-Account") {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "No such subscription: '"+r.PathValue("id")+"'")
+		return
+	}
+	sub.Status = "canceled"
+	resp := subscriptionJSON(sub)
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) listPaymentIntents(w http.ResponseWriter, r *http.Request) {
+	var since int64
+	if v := r.URL.Query().Get("created[gte]"); v != "" {
+		since, _ = strconv.ParseInt(v, 10, 64)
+	}
+	account := r.Header.Get("Stripe-Account")
+	s.mu.Lock()
+	ids := make([]string, 0, len(s.paymentIntents))
+	for id, pi := range s.paymentIntents {
+		if pi.StripeAccount == account && pi.Created.Unix() >= since {
+			ids = append(ids, id)
+		}
+	}
+	ids, hasMore := pageFromQuery(ids, r)
+	data := make([]map[string]any, 0, len(ids))
+	for _, id := range ids {
+		data = append(data, paymentIntentJSON(s.paymentIntents[id]))
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{"object": "list", "data": data, "has_more": hasMore})
+}
+
+// ---------------------------------------------------------------------------
+// v1 balance transactions / payouts（Stripe-Account の残高が対象）
+// ---------------------------------------------------------------------------
+
+func (s *Server) listBalanceTransactions(w http.ResponseWriter, r *http.Request) {
+	account := r.Header.Get("Stripe-Account")
+	since := createdSince(r)
+	expand := false
+	for _, e := range r.URL.Query()["expand[]"] {
+		expand = expand || e == "data.source"
+	}
+	s.mu.Lock()
+	ids := make([]string, 0, len(s.balanceTxs))
+	for id, bt := range s.balanceTxs {
+		if bt.StripeAccount == account && bt.Created.Unix() >= since {
+			ids = append(ids, id)
+		}
+	}
+	ids, hasMore := pageFromQuery(ids, r)
+	data := make([]map[string]any, 0, len(ids))
+	for _, id := range ids {
+		data = append(data, balanceTransactionJSON(s.balanceTxs[id], expand))
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{"object": "list", "data": data, "has_more": hasMore})
+}
+
+func (s *Server) listPayouts(w http.ResponseWriter, r *http.Request) {
+	account := r.Header.Get("Stripe-Account")
+	since := createdSince(r)
+	s.mu.Lock()
+	ids := make([]string, 0, len(s.payouts))
+	for id, po := range s.payouts {
+		if po.StripeAccount == account && po.Created.Unix() >= since {
+			ids = append(ids, id)
+		}
+	}
+	ids, hasMore := pageFromQuery(ids, r)
+	data := make([]map[string]any, 0, len(ids))
+	for _, id := range ids {
+		po := s.payouts[id]
+		data = append(data, map[string]any{
+			"id":           po.ID,
+			"object":       "payout",
+			"amount":       po.Amount,
+			"currency":     po.Currency,
+			"status":       po.Status,
+			"arrival_date": po.ArrivalDate.Unix(),
+			"created":      po.Created.Unix(),
+		})
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{"object": "list", "data": data, "has_more": hasMore})
+}
+
+// createdSince は created[gte] の値（指定なしは 0）
+func createdSince(r *http.Request) int64 {
+	since, _ := strconv.ParseInt(r.URL.Query().Get("created[gte]"), 10, 64)
+	return since
+}
+
+// ---------------------------------------------------------------------------
+// v1 customers / billing portal
+// ---------------------------------------------------------------------------
+
+func (s *Server) createCustomer(w http.ResponseWriter, r *http.Request) {
+	f := r.PostForm
+	account := r.Header.Get("Stripe-Account")
+	if account != "" {
+		if _, ok := s.Account(account); !ok {
+			writeError(w, http.StatusForbidden, "The provided key does not have access to account '"+account+"'")
+			return
+		}
+	}
+	s.mu.Lock()
+	c := &Customer{
+		ID:            s.newID("cus"),
+		Email:         f.Get("email"),
+		Name:          f.Get("name"),
+		Metadata:      formMetadata(f, ""),
+		StripeAccount: account,
+	}
+	s.customers[c.ID] = c
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id":       c.ID,
+		"object":   "customer",
+		"email":    c.Email,
+		"name":     c.Name,
+		"metadata": copyMetadata(c.Metadata),
+	})
+}
+
+func (s *Server) createBillingPortalSession(w http.ResponseWriter, r *http.Request) {
+	f := r.PostForm
+	customer := f.Get("customer")
+	s.mu.Lock()
+	c, known := s.customers[customer]
+	known = known && c.StripeAccount == r.Header.Get("Stripe-Account")
+	var id string
+	if known {
+		id = s.newID("bps")
+	}
+	s.mu.Unlock()
+	if !known {
+		writeError(w, http.StatusNotFound, "No such customer: '"+customer+"'")
+		return
+	}
+	if f.Get("return_url") == "" {
+		writeError(w, http.StatusBadRequest, "return_url is required")
+		return
+	}
+	flow := f.Get("flow_data[type]")
+	if flow != "" && flow != "payment_method_update" && flow != "subscription_cancel" && flow != "subscription_update" {
+		writeError(w, http.StatusBadRequest, "Invalid flow_data[type]: "+flow)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id":     id,
+		"object": "billing_portal.session",
+		"url":    s.URL + "/billing/" + id,
+	})
+}
+
+// ---------------------------------------------------------------------------
+// JSON representations
+// ---------------------------------------------------------------------------
+
+func pageFromQuery(ids []string, r *http.Request) ([]string, bool) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	return page(ids, r.URL.Query().Get("starting_after"), limit)
+}
+
+func subscriptionJSON(sub *Subscription) map[string]any {
+	var pause, cancelAt any
+	if sub.Paused {
+		pause = map[string]any{"behavior": "void"}
+	}
+	if !sub.CancelAt.IsZero() {
+		cancelAt = sub.CancelAt.Unix()
+	}
+	return map[string]any{
+		"id":               sub.ID,
+		"object":           "subscription",
+		"customer":         sub.Customer,
+		"status":           sub.Status,
+		"pause_collection": pause,
+		"cancel_at":        cancelAt,
+		"quantity":         1,
+		"metadata":         copyMetadata(sub.Metadata),
+		"created":          sub.Created.Unix(),
+		"plan": map[string]any{
+			"amount":         sub.Amount,
+			"currency":       sub.Currency,
+			"interval":       sub.Interval,
+			"interval_count": sub.IntervalCount,
+		},
+		"items": map[string]any{
+			"object": "list",
+			"data": []map[string]any{{
+				"id": sub.ItemID,
+				"price": map[string]any{
+					"unit_amount": sub.Amount,
+					"currency":    sub.Currency,
+					"recurring":   map[string]any{"interval": sub.Interval, "interval_count": sub.IntervalCount},
+				},
+			}},
+		},
+	}
+}
+
+func paymentIntentJSON(pi *PaymentIntent) map[string]any {
+	return map[string]any{
+		"id":                     pi.ID,
+		"object":                 "payment_intent",
+		"amount":                 pi.Amount,
+		"currency":               pi.Currency,
+		"status":                 pi.Status,
+		"metadata":               copyMetadata(pi.Metadata),
+		"application_fee_amount": nullIfZero(pi.ApplicationFeeAmount),
+		"created":                pi.Created.Unix(),
+	}
+}
+
+// nullIfZero は Stripe が未設定の数値を null で返すのに合わせる
+func nullIfZero(n int) any {
+	if n == 0 {
+		return nil
+	}
+	return n
+}
+
+// balanceTransactionJSON は残高の増減。expand なら source を charge オブジェクトに展開する
+func balanceTransactionJSON(bt *BalanceTransaction, expand bool) map[string]any {
+	var source any
+	switch {
+	case bt.Type == "charge" && expand:
+		source = map[string]any{"id": bt.ChargeID, "object": "charge", "payment_intent": bt.PaymentIntentID}
+	case bt.Type == "charge":
+		source = bt.ChargeID
+	default:
+		source = bt.PayoutID
+	}
+	return map[string]any{
+		"id":           bt.ID,
+		"object":       "balance_transaction",
+		"type":         bt.Type,
+		"amount":       bt.Amount,
+		"fee":          bt.Fee,
+		"net":          bt.Amount - bt.Fee,
+		"currency":     bt.Currency,
+		"source":       source,
+		"created":      bt.Created.Unix(),
+		"available_on": bt.Created.AddDate(0, 0, 4).Unix(),
+	}
+}
+
+func copyMetadata(md map[string]string) map[string]string {
+	out := make(map[string]string, len(md))
+	for k, v := range md {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,656 @@
+// Package stripetest はテスト用のインプロセス Stripe フェイクサーバーを提供する。
+//
+// stripe.RealClient が呼び出すエンドポイント（連結アカウント、Account Link、
+// Checkout Session、Customer、サブスクリプション、送金、照合用 list API、残高・入金の list API、Billing Portal）を
+// メモリ上の状態で実装し、署名付きの Webhook イベントを任意の URL に送信できる。
+// ネットワークに出ずに Checkout → Webhook → 寄付記録 の一連の流れを go test で検証するために使う。
+//
+//	srv := stripetest.NewServer("sk_test", "whsec_test")
+//	defer srv.Close()
+//	client := srv.Client() // APIBase がフェイクサーバーを向いた *stripe.RealClient
+package stripetest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/givers/backend/pkg/stripe"
+)
+
+// Account は v2 API で作成された連結アカウント
+type Account struct {
+	ID           string
+	Email        string
+	DisplayName  string
+	Country      string
+	CurrentlyDue []string // 空ならオンボーディング完了
+	// PastDue / DisabledReason が設定されていると決済・入金が停止する（RestrictAccount）
+	PastDue        []string
+	DisabledReason string
+	Created        time.Time
+}
+
+// Enabled は charges_enabled / payouts_enabled の値（未提出・期限切れの要件がなく停止されていない）
+func (a Account) Enabled() bool {
+	return len(a.CurrentlyDue) == 0 && len(a.PastDue) == 0 && a.DisabledReason == ""
+}
+
+// CheckoutSession は作成された Checkout Session
+type CheckoutSession struct {
+	ID            string
+	Mode          string // "payment" or "subscription"
+	Amount        int
+	Currency      string
+	Interval      string // subscription のみ
+	IntervalCount int    // subscription のみ（指定なしは 1）
+	ProductName   string
+	SuccessURL    string
+	CancelURL     string
+	Locale        string
+	StripeAccount string            // Stripe-Account ヘッダー（空ならプラットフォーム）
+	Customer      string            // customer で指定された既存の Customer（空なら完了時に作成する）
+	Metadata      map[string]string // PaymentIntent / Subscription に伝播する metadata
+	// SessionMetadata は Session 自体の metadata（checkout.session イベントに載る）
+	SessionMetadata map[string]string
+	// PaymentMethodTypes は payment_method_types（空なら指定なし）
+	PaymentMethodTypes []string
+	// ApplicationFeeAmount / ApplicationFeePercent はプラットフォームが受け取る額・割合（連結アカウントのみ）
+	ApplicationFeeAmount  int
+	ApplicationFeePercent int
+	// CompleteCheckout / CompleteCheckoutAsync 後に設定される
+	Completed       bool
+	PaymentIntentID string
+	SubscriptionID  string
+	// AsyncPending はコンビニ・銀行振込の入金待ち（CompleteCheckoutAsync 後、SettleAsyncPayment まで）
+	AsyncPending bool
+	// AsyncFailed は SettleAsyncPayment で期限切れになった
+	AsyncFailed bool
+	// ExpireCheckout 後に設定される
+	Expired bool
+}
+
+// Subscription はサブスクリプション
+type Subscription struct {
+	ID            string
+	Customer      string
+	Status        string
+	ItemID        string
+	Amount        int
+	Currency      string
+	Interval      string
+	IntervalCount int // Interval 何回分ごとに請求するか（1 以上）
+	Paused        bool
+	CancelAt      time.Time // ゼロ値なら解約予定なし（Billing Portal の「期間終了時に解約」で設定される）
+	Metadata      map[string]string
+	// ApplicationFeePercent は請求ごとにプラットフォームが受け取る割合（%）
+	ApplicationFeePercent int
+	Created               time.Time
+	StripeAccount         string // 作成されたアカウント（空ならプラットフォーム）
+}
+
+// Customer は Customer。Customer はアカウントごとに存在する
+type Customer struct {
+	ID            string
+	Email         string
+	Name          string
+	Metadata      map[string]string
+	StripeAccount string // Stripe-Account ヘッダー（空ならプラットフォーム）
+}
+
+// PaymentIntent は PaymentIntent
+type PaymentIntent struct {
+	ID                   string
+	Amount               int
+	Currency             string
+	Status               string
+	Metadata             map[string]string
+	ApplicationFeeAmount int // 連結アカウントでの決済からプラットフォームが受け取った額
+	Created              time.Time
+	StripeAccount        string // 作成されたアカウント（空ならプラットフォーム）
+}
+
+// Transfer はプラットフォームから連結アカウントへの送金
+type Transfer struct {
+	ID            string
+	Destination   string
+	Amount        int
+	Currency      string
+	TransferGroup string
+	Metadata      map[string]string
+	Created       time.Time
+}
+
+// BalanceTransaction は残高の増減（決済と入金のみ記録する）
+type BalanceTransaction struct {
+	ID              string
+	Type            string // "charge" or "payout"
+	Amount          int
+	Fee             int
+	Currency        string
+	ChargeID        string // charge のみ
+	PaymentIntentID string // charge のみ
+	PayoutID        string // payout のみ
+	StripeAccount   string // 空ならプラットフォーム
+	Created         time.Time
+}
+
+// Payout は連結アカウントから銀行口座への入金
+type Payout struct {
+	ID            string
+	StripeAccount string
+	Amount        int
+	Currency      string
+	Status        string // "paid" or "in_transit"
+	ArrivalDate   time.Time
+	Created       time.Time
+}
+
+// Server はインプロセスの Stripe フェイクサーバー
+type Server struct {
+	URL           string
+	SecretKey     string
+	WebhookSecret string
+	// WebhookURL が設定されていれば CompleteCheckout などで発生したイベントを送信する
+	WebhookURL string
+	// FeeBasisPoints は決済ごとに差し引く手数料（ベーシスポイント。NewServer では 360 = 3.6%）
+	FeeBasisPoints int
+
+	srv *httptest.Server
+
+	mu             sync.Mutex
+	seq            int
+	accounts       map[string]*Account
+	sessions       map[string]*CheckoutSession
+	subscriptions  map[string]*Subscription
+	paymentIntents map[string]*PaymentIntent
+	balanceTxs     map[string]*BalanceTransaction
+	payouts        map[string]*Payout
+	customers      map[string]*Customer
+	transfers      map[string]*Transfer
+	idempotent     map[string]*httptest.ResponseRecorder // Idempotency-Key → 最初のレスポンス
+	faults         []int                                 // FailNext で予約したエラーステータス
+	requests       int
+}
+
+// NewServer はフェイクサーバーを起動する。終了時に Close を呼ぶこと。
+func NewServer(secretKey, webhookSecret string) *Server {
+	s := &Server{
+		SecretKey:      secretKey,
+		WebhookSecret:  webhookSecret,
+		FeeBasisPoints: 360,
+		accounts:       map[string]*Account{},
+		sessions:       map[string]*CheckoutSession{},
+		subscriptions:  map[string]*Subscription{},
+		paymentIntents: map[string]*PaymentIntent{},
+		balanceTxs:     map[string]*BalanceTransaction{},
+		payouts:        map[string]*Payout{},
+		customers:      map[string]*Customer{},
+		transfers:      map[string]*Transfer{},
+		idempotent:     map[string]*httptest.ResponseRecorder{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v2/core/accounts", s.v2(s.createAccount))
+	mux.HandleFunc("GET /v2/core/accounts/{id}", s.v2(s.getAccount))
+	mux.HandleFunc("POST /v2/core/account_links", s.v2(s.createAccountLink))
+	mux.HandleFunc("GET /v1/accounts", s.v1(s.listAccounts))
+	mux.HandleFunc("GET /v1/accounts/{id}", s.v1(s.getAccountV1))
+	mux.HandleFunc("POST /v1/checkout/sessions", s.v1(s.createCheckoutSession))
+	mux.HandleFunc("GET /v1/subscriptions", s.v1(s.listSubscriptions))
+	mux.HandleFunc("GET /v1/subscriptions/{id}", s.v1(s.getSubscription))
+	mux.HandleFunc("POST /v1/subscriptions/{id}", s.v1(s.updateSubscription))
+	mux.HandleFunc("DELETE /v1/subscriptions/{id}", s.v1(s.cancelSubscription))
+	mux.HandleFunc("GET /v1/payment_intents", s.v1(s.listPaymentIntents))
+	mux.HandleFunc("GET /v1/balance_transactions", s.v1(s.listBalanceTransactions))
+	mux.HandleFunc("GET /v1/payouts", s.v1(s.listPayouts))
+	mux.HandleFunc("POST /v1/customers", s.v1(s.createCustomer))
+	mux.HandleFunc("POST /v1/billing_portal/sessions", s.v1(s.createBillingPortalSession))
+	mux.HandleFunc("POST /v1/transfers", s.v1(s.createTransfer))
+
+	s.srv = httptest.NewServer(s.intercept(mux))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close はサーバーを停止する
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Client はフェイクサーバーに接続する RealClient を返す（再試行の待ち時間は短縮）
+func (s *Server) Client() *stripe.RealClient {
+	c := stripe.NewClient(s.SecretKey, s.WebhookSecret)
+	c.APIBase = s.URL
+	c.RetryBackoff = time.Millisecond
+	return c
+}
+
+// FailNext は次の n 件のリクエストを status（429 や 500 など）で失敗させる
+func (s *Server) FailNext(status, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < n; i++ {
+		s.faults = append(s.faults, status)
+	}
+}
+
+// Requests はこれまでに受け付けた API リクエスト数（FailNext で失敗させた分を含む）を返す
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// intercept は FailNext の障害注入と、Idempotency-Key による POST の再送検出を行う。
+// 同じキーの POST には最初のレスポンスをそのまま返す（Stripe と同じく 5xx は保存しない）。
+func (s *Server) intercept(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests++
+		if len(s.faults) > 0 {
+			status := s.faults[0]
+			s.faults = s.faults[1:]
+			s.mu.Unlock()
+			if status == http.StatusTooManyRequests {
+				writeJSON(w, status, map[string]any{"error": map[string]string{
+					"type": "invalid_request_error", "code": "rate_limit", "message": "Too many requests made to the API too quickly",
+				}})
+				return
+			}
+			writeJSON(w, status, map[string]any{"error": map[string]string{
+				"type": "api_error", "message": "An unknown error occurred",
+			}})
+			return
+		}
+		key := r.Header.Get("Idempotency-Key")
+		cached := s.idempotent[key]
+		s.mu.Unlock()
+
+		if r.Method != http.MethodPost || key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if cached != nil {
+			w.Header().Set("Idempotent-Replayed", "true")
+			writeRecorded(w, cached)
+			return
+		}
+
+		rec := httptest.NewRecorder()
+		next.ServeHTTP(rec, r)
+		if rec.Code < 500 {
+			s.mu.Lock()
+			s.idempotent[key] = rec
+			s.mu.Unlock()
+		}
+		writeRecorded(w, rec)
+	})
+}
+
+func writeRecorded(w http.ResponseWriter, rec *httptest.ResponseRecorder) {
+	for k, v := range rec.Header() {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(rec.Code)
+	_, _ = w.Write(rec.Body.Bytes())
+}
+
+// newID は prefix 付きの ID を発行する（作成順にソートできるよう連番をゼロ埋め）。mu を保持して呼ぶこと。
+func (s *Server) newID(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s_test%08d", prefix, s.seq)
+}
+
+// ---------------------------------------------------------------------------
+// State accessors
+// ---------------------------------------------------------------------------
+
+// Account は連結アカウントのスナップショットを返す
+func (s *Server) Account(id string) (Account, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.accounts[id]
+	if !ok {
+		return Account{}, false
+	}
+	return *a, true
+}
+
+// CompleteOnboarding は連結アカウントの未提出項目をすべて解消する
+func (s *Server) CompleteOnboarding(accountID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.accounts[accountID]
+	if !ok {
+		return fmt.Errorf("stripetest: no such account %s", accountID)
+	}
+	a.CurrentlyDue = nil
+	return nil
+}
+
+// RestrictAccount は要件の期限切れなどで連結アカウントの決済・入金を停止し、
+// WebhookURL が設定されていれば account.updated を送信する
+func (s *Server) RestrictAccount(ctx context.Context, accountID, disabledReason string, pastDue ...string) error {
+	return s.updateAccount(ctx, accountID, func(a *Account) {
+		a.DisabledReason = disabledReason
+		a.PastDue = pastDue
+	})
+}
+
+// RestoreAccount は連結アカウントの要件をすべて解消して決済・入金を再開し、
+// WebhookURL が設定されていれば account.updated を送信する
+func (s *Server) RestoreAccount(ctx context.Context, accountID string) error {
+	return s.updateAccount(ctx, accountID, func(a *Account) {
+		a.CurrentlyDue, a.PastDue, a.DisabledReason = nil, nil, ""
+	})
+}
+
+func (s *Server) updateAccount(ctx context.Context, accountID string, update func(a *Account)) error {
+	s.mu.Lock()
+	a, ok := s.accounts[accountID]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("stripetest: no such account %s", accountID)
+	}
+	update(a)
+	object := accountJSON(a)
+	s.mu.Unlock()
+
+	if s.WebhookURL == "" {
+		return nil
+	}
+	return s.sendEvent(ctx, "account.updated", accountID, "", object)
+}
+
+// CheckoutSession は Checkout Session のスナップショットを返す
+func (s *Server) CheckoutSession(id string) (CheckoutSession, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cs, ok := s.sessions[id]
+	if !ok {
+		return CheckoutSession{}, false
+	}
+	return *cs, true
+}
+
+// Subscription はサブスクリプションのスナップショットを返す
+func (s *Server) Subscription(id string) (Subscription, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub, ok := s.subscriptions[id]
+	if !ok {
+		return Subscription{}, false
+	}
+	return *sub, true
+}
+
+// UpdateSubscription は寄付者が Billing Portal でサブスクリプションを変更したものとして update を適用し、
+// WebhookURL が設定されていれば customer.subscription.updated を送信する
+func (s *Server) UpdateSubscription(ctx context.Context, subscriptionID string, update func(sub *Subscription)) error {
+	s.mu.Lock()
+	sub, ok := s.subscriptions[subscriptionID]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("stripetest: no such subscription %s", subscriptionID)
+	}
+	update(sub)
+	object := subscriptionJSON(sub)
+	requestID := s.newID("req")
+	s.mu.Unlock()
+
+	if s.WebhookURL == "" {
+		return nil
+	}
+	return s.sendEvent(ctx, "customer.subscription.updated", "", requestID, object)
+}
+
+// EndPause は pause_collection の再開日時（resumes_at）が来て Stripe が一時停止を解除したものとして扱い、
+// WebhookURL が設定されていれば API リクエストを伴わない customer.subscription.updated を送信する
+func (s *Server) EndPause(ctx context.Context, subscriptionID string) error {
+	return s.changeSubscription(ctx, subscriptionID, "customer.subscription.updated", func(sub *Subscription) {
+		sub.Paused = false
+	})
+}
+
+// SetSubscriptionStatus は Stripe 自身がサブスクリプションの status を変えたもの（無料期間が支払い方法なしで
+// 終わって paused になる、再試行が尽きて unpaid になるなど）として扱う。WebhookURL が設定されていれば
+// paused になったときは customer.subscription.paused、paused から戻ったときは customer.subscription.resumed、
+// それ以外は customer.subscription.updated を送信する
+func (s *Server) SetSubscriptionStatus(ctx context.Context, subscriptionID, status string) error {
+	s.mu.Lock()
+	sub, ok := s.subscriptions[subscriptionID]
+	var previous string
+	if ok {
+		previous = sub.Status
+	}
+	s.mu.Unlock()
+
+	eventType := "customer.subscription.updated"
+	switch {
+	case status == "paused" && previous != "paused":
+		eventType = "customer.subscription.paused"
+	case status != "paused" && previous == "paused":
+		eventType = "customer.subscription.resumed"
+	}
+	return s.changeSubscription(ctx, subscriptionID, eventType, func(sub *Subscription) {
+		sub.Status = status
+	})
+}
+
+// changeSubscription は Stripe 自身による変更として update を適用し、eventType のイベントを送信する
+func (s *Server) changeSubscription(ctx context.Context, subscriptionID, eventType string, update func(sub *Subscription)) error {
+	s.mu.Lock()
+	sub, ok := s.subscriptions[subscriptionID]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("stripetest: no such subscription %s", subscriptionID)
+	}
+	update(sub)
+	object := subscriptionJSON(sub)
+	s.mu.Unlock()
+
+	return s.sendEvents(ctx, "", []event{{eventType, object}})
+}
+
+// Customer は Customer のスナップショットを返す
+func (s *Server) Customer(id string) (Customer, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.customers[id]
+	if !ok {
+		return Customer{}, false
+	}
+	return *c, true
+}
+
+// PaymentIntent は PaymentIntent のスナップショットを返す
+func (s *Server) PaymentIntent(id string) (PaymentIntent, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pi, ok := s.paymentIntents[id]
+	if !ok {
+		return PaymentIntent{}, false
+	}
+	return *pi, true
+}
+
+// Transfers は作成された送金を作成順に返す
+func (s *Server) Transfers() []Transfer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]Transfer, 0, len(s.transfers))
+	for _, tr := range s.transfers {
+		list = append(list, *tr)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+// BalanceTransactions は accountID（空ならプラットフォーム）の残高の増減を作成順に返す
+func (s *Server) BalanceTransactions(accountID string) []BalanceTransaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var list []BalanceTransaction
+	for _, bt := range s.balanceTxs {
+		if bt.StripeAccount == accountID {
+			list = append(list, *bt)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+// CreatePayout は連結アカウントの残高から amount を入金したものとして扱う。
+// arrival が現在以前なら paid、未来なら in_transit になる。
+func (s *Server) CreatePayout(accountID string, amount int, currency string, arrival time.Time) (Payout, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.accounts[accountID]; !ok {
+		return Payout{}, fmt.Errorf("stripetest: no such account %s", accountID)
+	}
+	now := time.Now()
+	po := &Payout{
+		ID:            s.newID("po"),
+		StripeAccount: accountID,
+		Amount:        amount,
+		Currency:      currency,
+		Status:        "paid",
+		ArrivalDate:   arrival,
+		Created:       now,
+	}
+	if arrival.After(now) {
+		po.Status = "in_transit"
+	}
+	s.payouts[po.ID] = po
+	bt := &BalanceTransaction{
+		ID:            s.newID("txn"),
+		Type:          "payout",
+		Amount:        -amount,
+		Currency:      currency,
+		PayoutID:      po.ID,
+		StripeAccount: accountID,
+		Created:       now,
+	}
+	s.balanceTxs[bt.ID] = bt
+	return *po, nil
+}
+
+// recordCharge は決済 pi の残高の増加を手数料付きで記録する。mu を保持して呼ぶこと。
+func (s *Server) recordCharge(account string, pi *PaymentIntent) {
+	bt := &BalanceTransaction{
+		ID:              s.newID("txn"),
+		Type:            "charge",
+		Amount:          pi.Amount,
+		Fee:             (pi.Amount*s.FeeBasisPoints + 5000) / 10000,
+		Currency:        pi.Currency,
+		ChargeID:        s.newID("ch"),
+		PaymentIntentID: pi.ID,
+		StripeAccount:   account,
+		Created:         pi.Created,
+	}
+	s.balanceTxs[bt.ID] = bt
+}
+
+// SessionIDFromURL は CreateCheckoutSession が返した URL から Session ID を取り出す
+func SessionIDFromURL(checkoutURL string) string {
+	return checkoutURL[strings.LastIndex(checkoutURL, "/")+1:]
+}
+
+// ---------------------------------------------------------------------------
+// Auth / response helpers
+// ---------------------------------------------------------------------------
+
+// v2 は v2 API の認証（Bearer + Stripe-Version）を検証する
+func (s *Server) v2(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+s.SecretKey {
+			writeError(w, http.StatusUnauthorized, "Invalid API Key provided")
+			return
+		}
+		if r.Header.Get("Stripe-Version") != stripe.StripeAPIVersion {
+			writeError(w, http.StatusBadRequest, "v2 endpoints require Stripe-Version "+stripe.StripeAPIVersion)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// v1 は v1 API の認証（Basic 認証のユーザー名にシークレットキー）を検証する
+func (s *Server) v1(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, _, ok := r.BasicAuth()
+		if !ok || user != s.SecretKey {
+			writeError(w, http.StatusUnauthorized, "Invalid API Key provided")
+			return
+		}
+		if r.Method == http.MethodPost {
+			if ct := r.Header.Get("Content-Type"); ct != "application/x-www-form-urlencoded" {
+				writeError(w, http.StatusBadRequest, "v1 endpoints require form encoding, got "+ct)
+				return
+			}
+			if err := r.ParseForm(); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid form body")
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	errType := "invalid_request_error"
+	if status == http.StatusUnauthorized {
+		errType = "authentication_error"
+	}
+	writeJSON(w, status, map[string]any{
+		"error": map[string]string{"type": errType, "message": message},
+	})
+}
+
+// formMetadata は prefix[metadata][key] 形式のフォーム値を map にする（prefix が空なら metadata[key]）
+func formMetadata(form map[string][]string, prefix string) map[string]string {
+	md := map[string]string{}
+	p := "metadata["
+	if prefix != "" {
+		p = prefix + "[metadata]["
+	}
+	for k, v := range form {
+		if strings.HasPrefix(k, p) && strings.HasSuffix(k, "]") && len(v) > 0 {
+			md[strings.TrimSuffix(strings.TrimPrefix(k, p), "]")] = v[0]
+		}
+	}
+	return md
+}
+
+// page は starting_after / limit で ID 昇順のリストを切り出す
+func page(ids []string, startingAfter string, limit int) ([]string, bool) {
+	sort.Strings(ids)
+	start := 0
+	if startingAfter != "" {
+		start = sort.SearchStrings(ids, startingAfter)
+		if start < len(ids) && ids[start] == startingAfter {
+			start++
+		}
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+	end := start + limit
+	if end >= len(ids) {
+		return ids[start:], false
+	}
+	return ids[start:end], true
+}
@@ -0,0 +1,868 @@
+package stripetest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/givers/backend/pkg/stripe"
+)
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	srv := NewServer("sk_test_fake", "whsec_fake")
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func checkoutParams(recurring bool) stripe.CheckoutParams {
+	return stripe.CheckoutParams{
+		ProjectID:   "p1",
+		Amount:      1500,
+		Currency:    "jpy",
+		IsRecurring: recurring,
+		Message:     "応援しています",
+		SuccessURL:  "https://example.com/ok",
+		CancelURL:   "https://example.com/cancel",
+		DonorType:   "user",
+		DonorID:     "u1",
+	}
+}
+
+func TestServer_AccountOnboarding(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+
+	accountID, err := client.CreateConnectedAccount(ctx, stripe.CreateAccountParams{Email: "owner@example.com", DisplayName: "Owner"})
+	if err != nil {
+		t.Fatalf("CreateConnectedAccount: %v", err)
+	}
+	if a, ok := srv.Account(accountID); !ok || a.Email != "owner@example.com" || a.Country != "jp" {
+		t.Errorf("account = %+v, ok=%v", a, ok)
+	}
+
+	link, err := client.CreateAccountLink(ctx, accountID, "https://example.com/return", "https://example.com/refresh")
+	if err != nil || !strings.HasPrefix(link, srv.URL) {
+		t.Fatalf("CreateAccountLink = %q, %v", link, err)
+	}
+
+	onboarded, err := client.GetAccountOnboarded(ctx, accountID)
+	if err != nil || onboarded {
+		t.Fatalf("GetAccountOnboarded before = %v, %v; want false", onboarded, err)
+	}
+	if err := srv.CompleteOnboarding(accountID); err != nil {
+		t.Fatal(err)
+	}
+	onboarded, err = client.GetAccountOnboarded(ctx, accountID)
+	if err != nil || !onboarded {
+		t.Fatalf("GetAccountOnboarded after = %v, %v; want true", onboarded, err)
+	}
+}
+
+func TestServer_RejectsWrongKey(t *testing.T) {
+	srv := newTestServer(t)
+	client := stripe.NewClient("sk_wrong", "")
+	client.APIBase = srv.URL
+
+	if _, err := client.CreateConnectedAccount(context.Background(), stripe.CreateAccountParams{}); err == nil {
+		t.Error("v2 call with wrong key should fail")
+	}
+	if _, err := client.CreateCheckoutSession(context.Background(), checkoutParams(false)); err == nil {
+		t.Error("v1 call with wrong key should fail")
+	}
+}
+
+func TestServer_CheckoutSession_FormEncoding(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client()
+
+	session, err := client.CreateCheckoutSession(context.Background(), checkoutParams(true))
+	if err != nil {
+		t.Fatalf("CreateCheckoutSession: %v", err)
+	}
+	cs, ok := srv.CheckoutSession(session.ID)
+	if !ok {
+		t.Fatalf("session for %s not found", session.ID)
+	}
+	if cs.Mode != "subscription" || cs.Amount != 1500 || cs.Interval != "month" {
+		t.Errorf("session = %+v", cs)
+	}
+	want := map[string]string{"project_id": "p1", "is_recurring": "true", "donor_type": "user", "donor_id": "u1", "message": "応援しています"}
+	for k, v := range want {
+		if cs.Metadata[k] != v {
+			t.Errorf("metadata[%s] = %q, want %q", k, cs.Metadata[k], v)
+		}
+	}
+}
+
+func TestServer_CheckoutSession_UnknownConnectedAccount(t *testing.T) {
+	srv := newTestServer(t)
+	params := checkoutParams(false)
+	params.StripeAccountID = "acct_missing"
+
+	if _, err := srv.Client().CreateCheckoutSession(context.Background(), params); err == nil {
+		t.Error("expected error for unknown Stripe-Account")
+	}
+}
+
+func TestServer_CheckoutSession_IntervalCount(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+	params := checkoutParams(true)
+	params.Interval, params.IntervalCount = "month", 3
+
+	session, err := client.CreateCheckoutSession(ctx, params)
+	if err != nil {
+		t.Fatalf("CreateCheckoutSession: %v", err)
+	}
+	cs, _ := srv.CheckoutSession(session.ID)
+	if cs.Interval != "month" || cs.IntervalCount != 3 || cs.ProductName != "四半期サポート" {
+		t.Errorf("session = %+v", cs)
+	}
+	if err := srv.CompleteCheckout(ctx, session.ID); err != nil {
+		t.Fatalf("CompleteCheckout: %v", err)
+	}
+	cs, _ = srv.CheckoutSession(session.ID)
+
+	// 金額を変えても請求間隔は変わらない
+	if err := client.UpdateSubscriptionAmount(ctx, "", cs.SubscriptionID, 4500); err != nil {
+		t.Fatalf("UpdateSubscriptionAmount: %v", err)
+	}
+	if sub, _ := srv.Subscription(cs.SubscriptionID); sub.Amount != 4500 || sub.Interval != "month" || sub.IntervalCount != 3 {
+		t.Errorf("subscription = %+v", sub)
+	}
+}
+
+func TestServer_SubscriptionLifecycle(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+
+	session, err := client.CreateCheckoutSession(ctx, checkoutParams(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.CompleteCheckout(ctx, session.ID); err != nil {
+		t.Fatalf("CompleteCheckout: %v", err)
+	}
+	cs, _ := srv.CheckoutSession(session.ID)
+	subID := cs.SubscriptionID
+
+	if err := client.PauseSubscription(ctx, "", subID); err != nil {
+		t.Fatalf("PauseSubscription: %v", err)
+	}
+	if sub, _ := srv.Subscription(subID); !sub.Paused {
+		t.Error("subscription should be paused")
+	}
+	if err := client.ResumeSubscription(ctx, "", subID); err != nil {
+		t.Fatalf("ResumeSubscription: %v", err)
+	}
+	if sub, _ := srv.Subscription(subID); sub.Paused {
+		t.Error("subscription should be resumed")
+	}
+
+	if err := client.UpdateSubscriptionAmount(ctx, "", subID, 3000); err != nil {
+		t.Fatalf("UpdateSubscriptionAmount: %v", err)
+	}
+	if sub, _ := srv.Subscription(subID); sub.Amount != 3000 || sub.Interval != "month" {
+		t.Errorf("subscription = %+v", sub)
+	}
+
+	portalURL, err := client.CreatePaymentMethodUpdateSession(ctx, "", subID, "https://example.com/me")
+	if err != nil || !strings.HasPrefix(portalURL, srv.URL) {
+		t.Fatalf("CreatePaymentMethodUpdateSession = %q, %v", portalURL, err)
+	}
+
+	if err := client.CancelSubscription(ctx, "", subID); err != nil {
+		t.Fatalf("CancelSubscription: %v", err)
+	}
+	if sub, _ := srv.Subscription(subID); sub.Status != "canceled" {
+		t.Errorf("status = %q, want canceled", sub.Status)
+	}
+	if err := client.PauseSubscription(ctx, "", subID); err == nil {
+		t.Error("pausing a canceled subscription should fail")
+	}
+}
+
+// 連結アカウントのサブスクリプションは Stripe-Account を付けないと見つからない
+func TestServer_ConnectedSubscriptionRequiresStripeAccount(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+
+	accountID, err := client.CreateConnectedAccount(ctx, stripe.CreateAccountParams{Email: "owner@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := checkoutParams(true)
+	params.StripeAccountID = accountID
+	session, err := client.CreateCheckoutSession(ctx, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.CompleteCheckout(ctx, session.ID); err != nil {
+		t.Fatalf("CompleteCheckout: %v", err)
+	}
+	cs, _ := srv.CheckoutSession(session.ID)
+	subID := cs.SubscriptionID
+
+	if err := client.PauseSubscription(ctx, "", subID); err == nil {
+		t.Error("pausing on the platform account should fail")
+	}
+	if subs, err := client.ListSubscriptions(ctx, ""); err != nil || len(subs) != 0 {
+		t.Errorf("platform subscriptions = %+v, %v, want none", subs, err)
+	}
+	if subs, err := client.ListSubscriptions(ctx, accountID); err != nil || len(subs) != 1 || subs[0].ID != subID {
+		t.Errorf("connected subscriptions = %+v, %v", subs, err)
+	}
+	if _, err := client.CreatePaymentMethodUpdateSession(ctx, "", subID, "https://example.com/me"); err == nil {
+		t.Error("portal on the platform account should fail")
+	}
+
+	if err := client.PauseSubscription(ctx, accountID, subID); err != nil {
+		t.Fatalf("PauseSubscription: %v", err)
+	}
+	if err := client.UpdateSubscriptionAmount(ctx, accountID, subID, 3000); err != nil {
+		t.Fatalf("UpdateSubscriptionAmount: %v", err)
+	}
+	if sub, _ := srv.Subscription(subID); !sub.Paused || sub.Amount != 3000 {
+		t.Errorf("subscription = %+v", sub)
+	}
+	if _, err := client.CreatePaymentMethodUpdateSession(ctx, accountID, subID, "https://example.com/me"); err != nil {
+		t.Fatalf("CreatePaymentMethodUpdateSession: %v", err)
+	}
+	if err := client.CancelSubscription(ctx, accountID, subID); err != nil {
+		t.Fatalf("CancelSubscription: %v", err)
+	}
+}
+
+func TestServer_ListPaginates(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+
+	const n = 105 // list API のページサイズ（100）を超える
+	for i := 0; i < n; i++ {
+		session, err := client.CreateCheckoutSession(ctx, checkoutParams(false))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := srv.CompleteCheckout(ctx, session.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	intents, err := client.ListPaymentIntents(ctx, "", time.Time{})
+	if err != nil {
+		t.Fatalf("ListPaymentIntents: %v", err)
+	}
+	if len(intents) != n {
+		t.Errorf("len(intents) = %d, want %d", len(intents), n)
+	}
+	seen := map[string]bool{}
+	for _, pi := range intents {
+		if seen[pi.ID] {
+			t.Fatalf("duplicate payment intent %s", pi.ID)
+		}
+		seen[pi.ID] = true
+	}
+}
+
+func TestServer_CompleteCheckout_SendsSignedWebhook(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+
+	var received []stripe.WebhookEvent
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		if err := client.VerifyWebhookSignature(payload, r.Header.Get("Stripe-Signature")); err != nil {
+			t.Errorf("VerifyWebhookSignature: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		event, err := client.ParseWebhookEvent(payload)
+		if err != nil {
+			t.Errorf("ParseWebhookEvent: %v", err)
+		}
+		received = append(received, event)
+	}))
+	defer hook.Close()
+	srv.WebhookURL = hook.URL
+
+	session, err := client.CreateCheckoutSession(ctx, checkoutParams(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.CompleteCheckout(ctx, session.ID); err != nil {
+		t.Fatalf("CompleteCheckout: %v", err)
+	}
+
+	if len(received) != 3 {
+		t.Fatalf("received %d events, want 3", len(received))
+	}
+	created, invoice, completed := received[0], received[1], received[2]
+	if created.Type != "customer.subscription.created" || created.Data.Object.Metadata["project_id"] != "p1" || created.Data.Object.Plan == nil || created.Data.Object.Plan.Amount != 1500 {
+		t.Errorf("subscription event = %+v", created)
+	}
+	if invoice.Type != "invoice.payment_succeeded" || invoice.Data.Object.Subscription != created.Data.Object.ID || invoice.Data.Object.AmountPaid != 1500 || invoice.Data.Object.PaymentIntent == "" {
+		t.Errorf("invoice event = %+v", invoice)
+	}
+	if completed.Type != "checkout.session.completed" || completed.Data.Object.ID != session.ID || completed.Data.Object.Status != "complete" || completed.Data.Object.Subscription != created.Data.Object.ID {
+		t.Errorf("checkout session event = %+v", completed)
+	}
+}
+
+func TestServer_ExpireCheckout(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+
+	var received []stripe.WebhookEvent
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		event, err := client.ParseWebhookEvent(payload)
+		if err != nil {
+			t.Errorf("ParseWebhookEvent: %v", err)
+		}
+		received = append(received, event)
+	}))
+	defer hook.Close()
+	srv.WebhookURL = hook.URL
+
+	session, err := client.CreateCheckoutSession(ctx, checkoutParams(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.ExpireCheckout(ctx, session.ID); err != nil {
+		t.Fatalf("ExpireCheckout: %v", err)
+	}
+	if len(received) != 1 || received[0].Type != "checkout.session.expired" || received[0].Data.Object.ID != session.ID || received[0].Data.Object.Status != "expired" {
+		t.Errorf("events = %+v", received)
+	}
+	if err := srv.CompleteCheckout(ctx, session.ID); err == nil {
+		t.Error("completing an expired session should fail")
+	}
+}
+
+func TestServer_CheckoutSession_AsyncPaymentMethods(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+
+	// コンビニ・銀行振込は定期寄付・JPY 以外では使えない
+	recurring := checkoutParams(true)
+	recurring.PaymentMethodTypes = []string{"card", "konbini"}
+	if _, err := client.CreateCheckoutSession(ctx, recurring); err == nil {
+		t.Error("konbini should be rejected in subscription mode")
+	}
+	usd := checkoutParams(false)
+	usd.Currency = "usd"
+	usd.PaymentMethodTypes = []string{"card", "customer_balance"}
+	if _, err := client.CreateCheckoutSession(ctx, usd); err == nil {
+		t.Error("bank transfer should be rejected for usd")
+	}
+
+	var received []stripe.WebhookEvent
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		event, err := client.ParseWebhookEvent(payload)
+		if err != nil {
+			t.Errorf("ParseWebhookEvent: %v", err)
+		}
+		received = append(received, event)
+	}))
+	defer hook.Close()
+	srv.WebhookURL = hook.URL
+
+	params := checkoutParams(false)
+	params.PaymentMethodTypes = []string{"card", "konbini", "customer_balance"}
+	session, err := client.CreateCheckoutSession(ctx, params)
+	if err != nil {
+		t.Fatalf("CreateCheckoutSession: %v", err)
+	}
+	if err := srv.CompleteCheckoutAsync(ctx, session.ID, "konbini"); err != nil {
+		t.Fatalf("CompleteCheckoutAsync: %v", err)
+	}
+	if len(received) != 1 {
+		t.Fatalf("received %d events, want 1", len(received))
+	}
+	completed := received[0].Data.Object
+	if received[0].Type != "checkout.session.completed" || completed.PaymentStatus != "unpaid" || completed.Mode != "payment" ||
+		completed.AmountTotal != 1500 || completed.Metadata["project_id"] != "p1" || completed.PaymentIntent == "" {
+		t.Errorf("checkout session event = %+v", received[0])
+	}
+	if pi, _ := srv.PaymentIntent(completed.PaymentIntent); pi.Status != "requires_action" {
+		t.Errorf("payment intent status = %q before payment", pi.Status)
+	}
+
+	if err := srv.SettleAsyncPayment(ctx, session.ID, true); err != nil {
+		t.Fatalf("SettleAsyncPayment: %v", err)
+	}
+	if len(received) != 3 || received[1].Type != "payment_intent.succeeded" || received[2].Type != "checkout.session.async_payment_succeeded" ||
+		received[2].Data.Object.PaymentStatus != "paid" {
+		t.Errorf("events = %+v", received)
+	}
+	if len(srv.BalanceTransactions("")) != 1 {
+		t.Error("the konbini payment should be charged once it is paid")
+	}
+	if err := srv.SettleAsyncPayment(ctx, session.ID, false); err == nil {
+		t.Error("a settled payment should not settle again")
+	}
+}
+
+func TestServer_SettleAsyncPayment_Failed(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+
+	var types []string
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		event, _ := client.ParseWebhookEvent(payload)
+		types = append(types, event.Type)
+	}))
+	defer hook.Close()
+	srv.WebhookURL = hook.URL
+
+	params := checkoutParams(false)
+	params.PaymentMethodTypes = []string{"card", "customer_balance"}
+	session, err := client.CreateCheckoutSession(ctx, params)
+	if err != nil {
+		t.Fatalf("CreateCheckoutSession: %v", err)
+	}
+	if err := srv.CompleteCheckoutAsync(ctx, session.ID, "konbini"); err == nil {
+		t.Error("konbini was not offered")
+	}
+	if err := srv.CompleteCheckoutAsync(ctx, session.ID, "customer_balance"); err != nil {
+		t.Fatalf("CompleteCheckoutAsync: %v", err)
+	}
+	if err := srv.SettleAsyncPayment(ctx, session.ID, false); err != nil {
+		t.Fatalf("SettleAsyncPayment: %v", err)
+	}
+	want := []string{"checkout.session.completed", "payment_intent.payment_failed", "checkout.session.async_payment_failed"}
+	if strings.Join(types, ",") != strings.Join(want, ",") {
+		t.Errorf("events = %v, want %v", types, want)
+	}
+	if len(srv.BalanceTransactions("")) != 0 {
+		t.Error("an unpaid bank transfer should not be charged")
+	}
+}
+
+func TestServer_SendEvent_ReportsRejection(t *testing.T) {
+	srv := newTestServer(t)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer hook.Close()
+	srv.WebhookURL = hook.URL
+
+	if err := srv.SendEvent(context.Background(), "payment_intent.succeeded", map[string]any{"id": "pi_1"}); err == nil {
+		t.Error("expected error for non-2xx response")
+	}
+}
+
+func TestServer_FailNext_RetriedWithoutDuplicates(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+
+	srv.FailNext(http.StatusTooManyRequests, 1)
+	srv.FailNext(http.StatusInternalServerError, 1)
+	accountID, err := client.CreateConnectedAccount(ctx, stripe.CreateAccountParams{Email: "owner@example.com"})
+	if err != nil {
+		t.Fatalf("CreateConnectedAccount: %v", err)
+	}
+	if srv.Requests() != 3 {
+		t.Errorf("requests = %d, want 3", srv.Requests())
+	}
+	accounts, err := client.ListConnectedAccounts(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 1 || accounts[0].ID != accountID {
+		t.Errorf("accounts = %+v, want only %s", accounts, accountID)
+	}
+}
+
+func TestServer_IdempotencyKeyReplaysFirstResponse(t *testing.T) {
+	srv := newTestServer(t)
+
+	post := func() string {
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+"/v1/checkout/sessions", strings.NewReader(
+			"mode=payment&line_items[0][price_data][currency]=jpy&line_items[0][price_data][unit_amount]=500"+
+				"&line_items[0][price_data][product_data][name]=x&line_items[0][quantity]=1&success_url=a&cancel_url=b"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Idempotency-Key", "key-1")
+		req.SetBasicAuth(srv.SecretKey, "")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	first, second := post(), post()
+	if first != second {
+		t.Errorf("replayed response differs:\n%s\n%s", first, second)
+	}
+	if _, ok := srv.CheckoutSession("cs_test00000002"); ok {
+		t.Error("second request with the same key must not create a session")
+	}
+}
+
+func TestServer_TypedErrors(t *testing.T) {
+	srv := newTestServer(t)
+	params := checkoutParams(false)
+	params.Amount = 0
+
+	_, err := srv.Client().CreateCheckoutSession(context.Background(), params)
+	se, ok := stripe.AsError(err)
+	if !ok || se.HTTPStatus != http.StatusBadRequest || se.Type != stripe.ErrorTypeInvalidRequest {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestServer_RestrictAccount_SendsAccountUpdated(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+
+	var received []stripe.WebhookEvent
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		event, _ := client.ParseWebhookEvent(payload)
+		received = append(received, event)
+	}))
+	defer hook.Close()
+	srv.WebhookURL = hook.URL
+
+	accountID, err := client.CreateConnectedAccount(ctx, stripe.CreateAccountParams{Email: "owner@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.CompleteOnboarding(accountID); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.RestrictAccount(ctx, accountID, "requirements.past_due", "individual.verification.document"); err != nil {
+		t.Fatalf("RestrictAccount: %v", err)
+	}
+
+	if len(received) != 1 || received[0].Type != "account.updated" || received[0].Account != accountID {
+		t.Fatalf("received = %+v", received)
+	}
+	if st := received[0].Data.Object.AccountStatus(); st.ChargesEnabled || st.DisabledReason != "requirements.past_due" || len(st.Requirements) != 1 {
+		t.Errorf("event status = %+v", st)
+	}
+	st, err := client.GetAccountStatus(ctx, accountID)
+	if err != nil || st.ChargesEnabled || st.PayoutsEnabled {
+		t.Errorf("GetAccountStatus = %+v, %v", st, err)
+	}
+
+	if err := srv.RestoreAccount(ctx, accountID); err != nil {
+		t.Fatalf("RestoreAccount: %v", err)
+	}
+	if st := received[len(received)-1].Data.Object.AccountStatus(); !st.ChargesEnabled || !st.PayoutsEnabled || len(st.Requirements) != 0 {
+		t.Errorf("restored status = %+v", st)
+	}
+}
+
+func TestServer_BalanceTransactionsAndPayouts(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+
+	accountID, err := client.CreateConnectedAccount(ctx, stripe.CreateAccountParams{Email: "owner@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := checkoutParams(false)
+	params.StripeAccountID = accountID
+	cs, err := client.CreateCheckoutSession(ctx, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.CompleteCheckout(ctx, cs.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srv.CreatePayout(accountID, 1446, "jpy", time.Now().Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	txs, err := client.ListBalanceTransactions(ctx, accountID, time.Time{})
+	if err != nil {
+		t.Fatalf("ListBalanceTransactions: %v", err)
+	}
+	session, _ := srv.CheckoutSession(cs.ID)
+	if len(txs) != 2 || !txs[0].IsPayment() || txs[0].Fee != 54 || txs[0].Net != 1446 || txs[0].PaymentIntent != session.PaymentIntentID {
+		t.Fatalf("txs = %+v", txs)
+	}
+	if txs[1].Type != "payout" || txs[1].Amount != -1446 {
+		t.Errorf("txs[1] = %+v", txs[1])
+	}
+	// プラットフォームの残高には連結アカウントの決済は含まれない
+	if platform, err := client.ListBalanceTransactions(ctx, "", time.Time{}); err != nil || len(platform) != 0 {
+		t.Errorf("platform txs = %+v, %v", platform, err)
+	}
+
+	payouts, err := client.ListPayouts(ctx, accountID, time.Time{})
+	if err != nil {
+		t.Fatalf("ListPayouts: %v", err)
+	}
+	if len(payouts) != 1 || payouts[0].Amount != 1446 || payouts[0].Status != "paid" {
+		t.Errorf("payouts = %+v", payouts)
+	}
+}
+
+func TestServer_CustomerCheckoutAndPortal(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+
+	customerID, err := client.CreateCustomer(ctx, stripe.CustomerParams{Email: "donor@example.com", Name: "Donor", UserID: "user-1"})
+	if err != nil {
+		t.Fatalf("CreateCustomer: %v", err)
+	}
+	if c, _ := srv.Customer(customerID); c.Email != "donor@example.com" || c.Metadata["user_id"] != "user-1" || c.StripeAccount != "" {
+		t.Errorf("customer = %+v", c)
+	}
+
+	var received []stripe.WebhookEvent
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		event, err := client.ParseWebhookEvent(payload)
+		if err != nil {
+			t.Errorf("ParseWebhookEvent: %v", err)
+		}
+		received = append(received, event)
+	}))
+	defer hook.Close()
+	srv.WebhookURL = hook.URL
+
+	params := checkoutParams(true)
+	params.CustomerID = customerID
+	session, err := client.CreateCheckoutSession(ctx, params)
+	if err != nil {
+		t.Fatalf("CreateCheckoutSession: %v", err)
+	}
+	if err := srv.CompleteCheckout(ctx, session.ID); err != nil {
+		t.Fatalf("CompleteCheckout: %v", err)
+	}
+	cs, _ := srv.CheckoutSession(session.ID)
+	if sub, _ := srv.Subscription(cs.SubscriptionID); sub.Customer != customerID {
+		t.Errorf("subscription customer = %q, want %q", sub.Customer, customerID)
+	}
+
+	// 別アカウントの Customer は使えない
+	acct, err := client.CreateConnectedAccount(ctx, stripe.CreateAccountParams{Email: "owner@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	connected := checkoutParams(true)
+	connected.StripeAccountID = acct
+	connected.CustomerID = customerID
+	if _, err := client.CreateCheckoutSession(ctx, connected); err == nil {
+		t.Error("a platform customer should be rejected on a connected account")
+	}
+	if _, err := client.CreateBillingPortalSession(ctx, "", "cus_unknown", "https://example.com/me"); err == nil {
+		t.Error("an unknown customer should be rejected")
+	}
+	portalURL, err := client.CreateBillingPortalSession(ctx, "", customerID, "https://example.com/me")
+	if err != nil || !strings.HasPrefix(portalURL, srv.URL) {
+		t.Fatalf("CreateBillingPortalSession = %q, %v", portalURL, err)
+	}
+
+	// 連結アカウントには同じユーザーの Customer を別に作り、ポータルもそのアカウントで開く
+	connectedCustomer, err := client.CreateCustomer(ctx, stripe.CustomerParams{StripeAccountID: acct, Email: "donor@example.com", UserID: "user-1"})
+	if err != nil {
+		t.Fatalf("CreateCustomer(connected): %v", err)
+	}
+	if c, _ := srv.Customer(connectedCustomer); c.StripeAccount != acct {
+		t.Errorf("connected customer = %+v", c)
+	}
+	connected.CustomerID = connectedCustomer
+	if _, err := client.CreateCheckoutSession(ctx, connected); err != nil {
+		t.Errorf("CreateCheckoutSession with the connected customer: %v", err)
+	}
+	if _, err := client.CreateBillingPortalSession(ctx, "", connectedCustomer, "https://example.com/me"); err == nil {
+		t.Error("a connected customer's portal should be rejected on the platform account")
+	}
+	if _, err := client.CreateBillingPortalSession(ctx, acct, connectedCustomer, "https://example.com/me"); err != nil {
+		t.Errorf("CreateBillingPortalSession(connected): %v", err)
+	}
+
+	// ポータルでの変更は customer.subscription.updated で届く
+	cancelAt := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+	received = nil
+	if err := srv.UpdateSubscription(ctx, cs.SubscriptionID, func(sub *Subscription) {
+		sub.Amount = 2500
+		sub.CancelAt = cancelAt
+	}); err != nil {
+		t.Fatalf("UpdateSubscription: %v", err)
+	}
+	if len(received) != 1 || received[0].Type != "customer.subscription.updated" {
+		t.Fatalf("events = %+v", received)
+	}
+	if obj := received[0].Data.Object; obj.SubscriptionCharge() != 2500 || obj.CancelAt != cancelAt.Unix() || obj.PauseCollection != nil {
+		t.Errorf("subscription event = %+v", obj)
+	}
+}
+
+func TestServer_StripeSideSubscriptionChanges(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+
+	session, err := client.CreateCheckoutSession(ctx, checkoutParams(true))
+	if err != nil {
+		t.Fatalf("CreateCheckoutSession: %v", err)
+	}
+	if err := srv.CompleteCheckout(ctx, session.ID); err != nil {
+		t.Fatalf("CompleteCheckout: %v", err)
+	}
+	cs, _ := srv.CheckoutSession(session.ID)
+
+	var received []stripe.WebhookEvent
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		event, err := client.ParseWebhookEvent(payload)
+		if err != nil {
+			t.Errorf("ParseWebhookEvent: %v", err)
+		}
+		received = append(received, event)
+	}))
+	defer hook.Close()
+	srv.WebhookURL = hook.URL
+
+	if err := srv.UpdateSubscription(ctx, cs.SubscriptionID, func(sub *Subscription) { sub.Paused = true }); err != nil {
+		t.Fatalf("UpdateSubscription: %v", err)
+	}
+	if err := srv.EndPause(ctx, cs.SubscriptionID); err != nil {
+		t.Fatalf("EndPause: %v", err)
+	}
+	for _, status := range []string{"paused", "active", "past_due"} {
+		if err := srv.SetSubscriptionStatus(ctx, cs.SubscriptionID, status); err != nil {
+			t.Fatalf("SetSubscriptionStatus(%s): %v", status, err)
+		}
+	}
+	if err := srv.EndPause(ctx, "sub_unknown"); err == nil {
+		t.Error("an unknown subscription should be rejected")
+	}
+
+	want := []struct {
+		eventType, status string
+		automatic, paused bool
+	}{
+		{"customer.subscription.updated", "active", false, true},
+		{"customer.subscription.updated", "active", true, false},
+		{"customer.subscription.paused", "paused", true, false},
+		{"customer.subscription.resumed", "active", true, false},
+		{"customer.subscription.updated", "past_due", true, false},
+	}
+	if len(received) != len(want) {
+		t.Fatalf("events = %+v", received)
+	}
+	for i, w := range want {
+		e := received[i]
+		if e.Type != w.eventType || e.Automatic() != w.automatic || e.Data.Object.Status != w.status || (e.Data.Object.PauseCollection != nil) != w.paused {
+			t.Errorf("event %d = %s automatic=%v %+v, want %+v", i, e.Type, e.Automatic(), e.Data.Object, w)
+		}
+	}
+}
+
+func TestServer_ApplicationFeesAndTransfers(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+
+	var received []stripe.WebhookEvent
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		event, _ := client.ParseWebhookEvent(payload)
+		received = append(received, event)
+	}))
+	defer hook.Close()
+	srv.WebhookURL = hook.URL
+
+	downstream, _ := client.CreateConnectedAccount(ctx, stripe.CreateAccountParams{Email: "down@example.com"})
+	upstream, _ := client.CreateConnectedAccount(ctx, stripe.CreateAccountParams{Email: "up@example.com"})
+	for _, id := range []string{downstream, upstream} {
+		if err := srv.CompleteOnboarding(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// プラットフォームでの決済には application fee を付けられない
+	params := checkoutParams(false)
+	params.ApplicationFeeAmount = 300
+	if _, err := client.CreateCheckoutSession(ctx, params); err == nil {
+		t.Error("expected an error for an application fee on a platform charge")
+	}
+
+	params.StripeAccountID = downstream
+	session, err := client.CreateCheckoutSession(ctx, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.CompleteCheckout(ctx, session.ID); err != nil {
+		t.Fatal(err)
+	}
+	recurring := checkoutParams(true)
+	recurring.StripeAccountID = downstream
+	recurring.ApplicationFeePercent = 10
+	session, err = client.CreateCheckoutSession(ctx, recurring)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.CompleteCheckout(ctx, session.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	fees := map[string]int{}
+	for _, e := range received {
+		if e.Account != downstream {
+			t.Errorf("%s: account = %q, want %q", e.Type, e.Account, downstream)
+		}
+		fees[e.Type] = e.Data.Object.ApplicationFeeAmount
+	}
+	if fees["payment_intent.succeeded"] != 300 || fees["invoice.payment_succeeded"] != 150 {
+		t.Errorf("application fees = %v", fees)
+	}
+
+	id, err := client.CreateTransfer(ctx, stripe.TransferParams{
+		Destination: upstream, Amount: 300, Currency: "jpy", TransferGroup: "payment-1", IdempotencyKey: "pass-through-1",
+	})
+	if err != nil {
+		t.Fatalf("CreateTransfer: %v", err)
+	}
+	// 同じキーの再送は最初の送金を返す
+	again, err := client.CreateTransfer(ctx, stripe.TransferParams{
+		Destination: upstream, Amount: 300, Currency: "jpy", TransferGroup: "payment-1", IdempotencyKey: "pass-through-1",
+	})
+	if err != nil || again != id {
+		t.Errorf("replayed transfer = %q, %v; want %q", again, err, id)
+	}
+	if list := srv.Transfers(); len(list) != 1 || list[0].Destination != upstream || list[0].Amount != 300 || list[0].TransferGroup != "payment-1" {
+		t.Errorf("transfers = %+v", list)
+	}
+
+	if err := srv.RestrictAccount(ctx, upstream, "requirements.past_due"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.CreateTransfer(ctx, stripe.TransferParams{
+		Destination: upstream, Amount: 100, Currency: "jpy", IdempotencyKey: "pass-through-2",
+	})
+	var stripeErr *stripe.Error
+	if !errors.As(err, &stripeErr) || stripeErr.HTTPStatus != http.StatusBadRequest {
+		t.Errorf("transfer to a restricted account: err = %v", err)
+	}
+}
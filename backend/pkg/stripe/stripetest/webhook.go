@@ -0,0 +1,318 @@
+package stripetest
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// SignatureHeader は Stripe と同じ方式（t=タイムスタンプ,v1=HMAC-SHA256）で Stripe-Signature ヘッダーを生成する
+func SignatureHeader(secret string, payload []byte, t time.Time) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + "." + string(payload)))
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SendEvent は署名付きの Webhook イベントを WebhookURL に POST する。
+// object はイベントの data.object として JSON エンコードされる。2xx 以外はエラーを返す。
+func (s *Server) SendEvent(ctx context.Context, eventType string, object any) error {
+	return s.sendEvent(ctx, eventType, "", "", object)
+}
+
+// sendEvent は SendEvent と同じだが、account が空でなければ Connect イベントとして account を付ける。
+// requestID はイベントを起こした API リクエスト（ダッシュボードや Customer Portal での操作）で、
+// 空なら Stripe 自身による変更として request.id を null にする
+func (s *Server) sendEvent(ctx context.Context, eventType, account, requestID string, object any) error {
+	if s.WebhookURL == "" {
+		return errors.New("stripetest: WebhookURL is not set")
+	}
+
+	s.mu.Lock()
+	eventID := s.newID("evt")
+	s.mu.Unlock()
+
+	event := map[string]any{
+		"id":      eventID,
+		"object":  "event",
+		"type":    eventType,
+		"created": time.Now().Unix(),
+		"data":    map[string]any{"object": object},
+		"request": map[string]any{"id": nil, "idempotency_key": nil},
+	}
+	if requestID != "" {
+		event["request"] = map[string]any{"id": requestID, "idempotency_key": nil}
+	}
+	if account != "" {
+		event["account"] = account
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.WebhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Stripe-Signature", SignatureHeader(s.WebhookSecret, payload, time.Now()))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("stripetest: webhook %s returned %d: %s", eventType, resp.StatusCode, body)
+	}
+	return nil
+}
+
+// CompleteCheckout は Checkout Session の支払いが完了したものとして扱い、
+// PaymentIntent またはサブスクリプションを作成する。WebhookURL が設定されていれば
+// Stripe が送るのと同じイベント（payment モード: payment_intent.succeeded、
+// subscription モード: customer.subscription.created と初回の invoice.payment_succeeded、
+// 最後に checkout.session.completed）を送信する。連結アカウントの Session のイベントには account が付き、
+// application fee を指定した決済・請求には application_fee_amount が載る。
+func (s *Server) CompleteCheckout(ctx context.Context, sessionID string) error {
+	s.mu.Lock()
+	cs, ok := s.sessions[sessionID]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("stripetest: no such checkout session %s", sessionID)
+	}
+	if cs.Completed || cs.Expired {
+		s.mu.Unlock()
+		return fmt.Errorf("stripetest: checkout session %s is no longer open", sessionID)
+	}
+	cs.Completed = true
+	now := time.Now()
+
+	var events []event
+
+	if cs.Mode == "payment" {
+		pi := &PaymentIntent{
+			ID:                   s.newID("pi"),
+			Amount:               cs.Amount,
+			Currency:             cs.Currency,
+			Status:               "succeeded",
+			Metadata:             copyMetadata(cs.Metadata),
+			ApplicationFeeAmount: cs.ApplicationFeeAmount,
+			Created:              now,
+			StripeAccount:        cs.StripeAccount,
+		}
+		s.paymentIntents[pi.ID] = pi
+		s.recordCharge(cs.StripeAccount, pi)
+		cs.PaymentIntentID = pi.ID
+		events = append(events, event{"payment_intent.succeeded", paymentIntentJSON(pi)})
+	} else {
+		customer := cs.Customer
+		if customer == "" {
+			customer = s.newID("cus")
+			s.customers[customer] = &Customer{ID: customer, StripeAccount: cs.StripeAccount}
+		}
+		sub := &Subscription{
+			ID:            s.newID("sub"),
+			Customer:      customer,
+			Status:        "active",
+			ItemID:        s.newID("si"),
+			Amount:        cs.Amount,
+			Currency:      cs.Currency,
+			Interval:      cs.Interval,
+			IntervalCount: cs.IntervalCount,
+			Metadata:      copyMetadata(cs.Metadata),
+			Created:       now,
+			// 請求ごとの application fee は請求額 × 割合（Stripe と同じく四捨五入）
+			ApplicationFeePercent: cs.ApplicationFeePercent,
+			StripeAccount:         cs.StripeAccount,
+		}
+		s.subscriptions[sub.ID] = sub
+		cs.SubscriptionID = sub.ID
+
+		// 請求分の PaymentIntent は metadata を持たない（Stripe と同じ）
+		fee := (cs.Amount*cs.ApplicationFeePercent + 50) / 100
+		pi := &PaymentIntent{
+			ID:                   s.newID("pi"),
+			Amount:               cs.Amount,
+			Currency:             cs.Currency,
+			Status:               "succeeded",
+			Metadata:             map[string]string{},
+			ApplicationFeeAmount: fee,
+			Created:              now,
+			StripeAccount:        cs.StripeAccount,
+		}
+		s.paymentIntents[pi.ID] = pi
+		s.recordCharge(cs.StripeAccount, pi)
+		events = append(events,
+			event{"customer.subscription.created", subscriptionJSON(sub)},
+			event{"invoice.payment_succeeded", map[string]any{
+				"id":             s.newID("in"),
+				"object":         "invoice",
+				"subscription":   sub.ID,
+				"customer":       customer,
+				"payment_intent": pi.ID,
+				"amount_paid":    cs.Amount,
+				"currency":       cs.Currency,
+				"metadata":       map[string]string{},
+				// 0 のときは Stripe と同じく null
+				"application_fee_amount": nullIfZero(fee),
+			}},
+		)
+	}
+	events = append(events, event{"checkout.session.completed", checkoutSessionJSON(cs, "complete")})
+	account := cs.StripeAccount
+	s.mu.Unlock()
+
+	return s.sendEvents(ctx, account, events)
+}
+
+// CompleteCheckoutAsync は寄付者がコンビニ・銀行振込（method）を選んで Checkout を終えたものとして扱う。
+// 入金はまだなので PaymentIntent は requires_action のまま、WebhookURL が設定されていれば
+// payment_status が "unpaid" の checkout.session.completed だけを送信する。
+// 入金（または期限切れ）は SettleAsyncPayment で起こす。
+func (s *Server) CompleteCheckoutAsync(ctx context.Context, sessionID, method string) error {
+	s.mu.Lock()
+	cs, ok := s.sessions[sessionID]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("stripetest: no such checkout session %s", sessionID)
+	}
+	if cs.Completed || cs.Expired {
+		s.mu.Unlock()
+		return fmt.Errorf("stripetest: checkout session %s is no longer open", sessionID)
+	}
+	if (method != "konbini" && method != "customer_balance") || !slices.Contains(cs.PaymentMethodTypes, method) {
+		s.mu.Unlock()
+		return fmt.Errorf("stripetest: checkout session %s does not offer %s", sessionID, method)
+	}
+	pi := &PaymentIntent{
+		ID:                   s.newID("pi"),
+		Amount:               cs.Amount,
+		Currency:             cs.Currency,
+		Status:               "requires_action",
+		Metadata:             copyMetadata(cs.Metadata),
+		ApplicationFeeAmount: cs.ApplicationFeeAmount,
+		Created:              time.Now(),
+		StripeAccount:        cs.StripeAccount,
+	}
+	s.paymentIntents[pi.ID] = pi
+	cs.Completed = true
+	cs.AsyncPending = true
+	cs.PaymentIntentID = pi.ID
+	object := checkoutSessionJSON(cs, "complete")
+	account := cs.StripeAccount
+	s.mu.Unlock()
+
+	return s.sendEvents(ctx, account, []event{{"checkout.session.completed", object}})
+}
+
+// SettleAsyncPayment は CompleteCheckoutAsync で入金待ちになった支払いを確定させる。
+// paid なら入金されたものとして payment_intent.succeeded と checkout.session.async_payment_succeeded、
+// そうでなければ期限切れとして payment_intent.payment_failed と checkout.session.async_payment_failed を送信する。
+func (s *Server) SettleAsyncPayment(ctx context.Context, sessionID string, paid bool) error {
+	s.mu.Lock()
+	cs, ok := s.sessions[sessionID]
+	if !ok || !cs.AsyncPending {
+		s.mu.Unlock()
+		return fmt.Errorf("stripetest: checkout session %s is not awaiting payment", sessionID)
+	}
+	cs.AsyncPending = false
+	pi := s.paymentIntents[cs.PaymentIntentID]
+	var events []event
+	if paid {
+		pi.Status = "succeeded"
+		s.recordCharge(cs.StripeAccount, pi)
+		events = []event{
+			{"payment_intent.succeeded", paymentIntentJSON(pi)},
+			{"checkout.session.async_payment_succeeded", checkoutSessionJSON(cs, "complete")},
+		}
+	} else {
+		cs.AsyncFailed = true
+		pi.Status = "requires_payment_method"
+		events = []event{
+			{"payment_intent.payment_failed", paymentIntentJSON(pi)},
+			{"checkout.session.async_payment_failed", checkoutSessionJSON(cs, "complete")},
+		}
+	}
+	account := cs.StripeAccount
+	s.mu.Unlock()
+
+	return s.sendEvents(ctx, account, events)
+}
+
+// event は送信待ちの Webhook イベント
+type event struct {
+	eventType string
+	object    any
+}
+
+// sendEvents は WebhookURL が設定されていれば events を順に送信する。
+// account が空でなければ連結アカウントで起きたイベントとして account を付ける
+func (s *Server) sendEvents(ctx context.Context, account string, events []event) error {
+	if s.WebhookURL == "" {
+		return nil
+	}
+	for _, e := range events {
+		if err := s.sendEvent(ctx, e.eventType, account, "", e.object); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExpireCheckout は支払われないまま期限切れになった Checkout Session として扱い、
+// WebhookURL が設定されていれば checkout.session.expired を送信する。
+func (s *Server) ExpireCheckout(ctx context.Context, sessionID string) error {
+	s.mu.Lock()
+	cs, ok := s.sessions[sessionID]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("stripetest: no such checkout session %s", sessionID)
+	}
+	if cs.Completed || cs.Expired {
+		s.mu.Unlock()
+		return fmt.Errorf("stripetest: checkout session %s is no longer open", sessionID)
+	}
+	cs.Expired = true
+	object := checkoutSessionJSON(cs, "expired")
+	account := cs.StripeAccount
+	s.mu.Unlock()
+
+	return s.sendEvents(ctx, account, []event{{"checkout.session.expired", object}})
+}
+
+// checkoutSessionJSON は checkout.session イベントの data.object（呼び出し側で s.mu を保持すること）
+func checkoutSessionJSON(cs *CheckoutSession, status string) map[string]any {
+	obj := map[string]any{
+		"id":             cs.ID,
+		"object":         "checkout.session",
+		"mode":           cs.Mode,
+		"status":         status,
+		"payment_status": "unpaid",
+		"amount_total":   cs.Amount,
+		"currency":       cs.Currency,
+		"metadata":       copyMetadata(cs.SessionMetadata),
+	}
+	if status == "complete" && !cs.AsyncPending && !cs.AsyncFailed {
+		obj["payment_status"] = "paid"
+	}
+	if cs.PaymentIntentID != "" {
+		obj["payment_intent"] = cs.PaymentIntentID
+	}
+	if cs.SubscriptionID != "" {
+		obj["subscription"] = cs.SubscriptionID
+	}
+	return obj
+}
@@ -102,6 +102,61 @@ function ActivityLine({
               )}
         </span>
       );
+    case 'manual_donation': {
+      // 銀行振込・現金など、オーナーまたはホストが手動で記録した寄付
+      const method = t(locale, `feed.paymentMethods.${item.message ?? 'other'}`);
+      return (
+        <span className="activity-item">
+          {item.actor_name
+            ? renderWithProjectLink(
+                t(locale, 'feed.manualDonationBy', { actor: item.actor_name, amount: amountStr, method }),
+                item.project_name,
+                item.project_id,
+                {}
+              )
+            : renderWithProjectLink(
+                t(locale, 'feed.manualDonationAnonymous', { amount: amountStr, method }),
+                item.project_name,
+                item.project_id,
+                {}
+              )}
+        </span>
+      );
+    }
+    case 'matching':
+      // マッチング寄付キャンペーンの上乗せ（message はスポンサー名）
+      return (
+        <span className="activity-item">
+          {renderWithProjectLink(
+            t(locale, 'feed.matched', { sponsor: item.message ?? '', amount: amountStr }),
+            item.project_name,
+            item.project_id,
+            {}
+          )}
+        </span>
+      );
+    case 'refund':
+      return (
+        <span className="activity-item">
+          {renderWithProjectLink(
+            t(locale, 'feed.refunded', { amount: amountStr }),
+            item.project_name,
+            item.project_id,
+            {}
+          )}
+        </span>
+      );
+    case 'dispute':
+      return (
+        <span className="activity-item">
+          {renderWithProjectLink(
+            t(locale, 'feed.disputed', { amount: amountStr, status: item.message ?? '' }),
+            item.project_name,
+            item.project_id,
+            {}
+          )}
+        </span>
+      );
     case 'milestone':
       return (
         <span className="activity-item">
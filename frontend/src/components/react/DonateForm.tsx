@@ -1,9 +1,26 @@
 import { useState } from "react";
 import { t, type Locale } from "../../lib/i18n";
-import { createCheckout, type User } from "../../lib/api";
+import {
+  createCheckout,
+  type DonationInterval,
+  type DonationTier,
+  type User,
+} from "../../lib/api";
 
 type DonationType = "one_time" | "monthly";
 
+/** 定期寄付の請求間隔と 1 回の請求がカバーする月数 */
+const intervalOptions: {
+  value: DonationInterval;
+  months: number;
+  labelKey: string;
+}[] = [
+  { value: "month", months: 1, labelKey: "projects.intervalMonth" },
+  { value: "quarter", months: 3, labelKey: "projects.intervalQuarter" },
+  { value: "half_year", months: 6, labelKey: "projects.intervalHalfYear" },
+  { value: "year", months: 12, labelKey: "projects.intervalYear" },
+];
+
 interface Props {
   locale: Locale;
   projectId: string;
@@ -25,6 +42,8 @@ interface Props {
   user?: User | null;
   /** 凍結・削除プロジェクトのときは寄付不可。メッセージを表示する */
   projectStatus?: string;
+  /** オーナーが設定した寄付の目安額 */
+  tiers?: DonationTier[];
 }
 
 export default function DonateForm({
@@ -46,14 +65,19 @@ export default function DonateForm({
   donationTypeLabel = "寄付の種類",
   user,
   projectStatus,
+  tiers = [],
 }: Props) {
   const isLoggedIn = !!user && !user.suspended;
   const [donationType, setDonationType] = useState<DonationType>("one_time");
+  const [billingInterval, setBillingInterval] =
+    useState<DonationInterval>("month");
   const [selectedAmount, setSelectedAmount] = useState<
     number | "custom" | null
   >(null);
   const [customAmount, setCustomAmount] = useState("");
+  const [selectedTier, setSelectedTier] = useState<DonationTier | null>(null);
   const [message, setMessage] = useState("");
+  const [coverFees, setCoverFees] = useState(false);
   const [submitting, setSubmitting] = useState(false);
   const [error, setError] = useState<string | null>(null);
 
@@ -92,6 +116,14 @@ export default function DonateForm({
     );
   }
 
+  /** 目安額を選ぶと、金額・種類・請求間隔は目安額の設定になる */
+  const selectTier = (tier: DonationTier) => {
+    setSelectedTier(tier);
+    setSelectedAmount(tier.amount);
+    setDonationType(tier.is_recurring ? "monthly" : "one_time");
+    setBillingInterval(tier.interval ?? "month");
+  };
+
   const handleSubmit = async (e: React.FormEvent) => {
     e.preventDefault();
     const amount =
@@ -99,6 +131,7 @@ export default function DonateForm({
         ? parseInt(customAmount.replace(/\D/g, ""), 10) || 0
         : (selectedAmount ?? 0);
     if (amount <= 0) return;
+    const isRecurring = donationType === "monthly";
 
     setSubmitting(true);
     setError(null);
@@ -107,7 +140,10 @@ export default function DonateForm({
         project_id: projectId,
         amount,
         currency: "jpy",
-        is_recurring: donationType === "monthly",
+        is_recurring: isRecurring,
+        interval: isRecurring ? billingInterval : undefined,
+        tier_id: selectedTier?.id,
+        cover_fees: coverFees || undefined,
         message: message || undefined,
         locale: locale === "en" ? "en" : "ja",
       });
@@ -155,7 +191,10 @@ export default function DonateForm({
               type="radio"
               name="donationType"
               checked={donationType === "one_time"}
-              onChange={() => setDonationType("one_time")}
+              onChange={() => {
+                setDonationType("one_time");
+                setSelectedTier(null);
+              }}
             />
             <span>{oneTimeLabel}</span>
           </label>
@@ -172,7 +211,10 @@ export default function DonateForm({
               type="radio"
               name="donationType"
               checked={donationType === "monthly"}
-              onChange={() => setDonationType("monthly")}
+              onChange={() => {
+                setDonationType("monthly");
+                setSelectedTier(null);
+              }}
               disabled={!isLoggedIn}
             />
             <span>{monthlyLabel}</span>
@@ -189,7 +231,119 @@ export default function DonateForm({
             {t(locale, "errors.recurringRequiresLogin")}
           </p>
         )}
+        {donationType === "monthly" && (
+          <label
+            style={{
+              display: "flex",
+              alignItems: "center",
+              gap: "0.5rem",
+              marginTop: "0.75rem",
+              fontSize: "0.95rem",
+            }}
+          >
+            <span>{t(locale, "projects.billingInterval")}</span>
+            <select
+              value={billingInterval}
+              onChange={(e) => {
+                setBillingInterval(e.target.value as DonationInterval);
+                setSelectedTier(null);
+              }}
+              style={{
+                padding: "0.35rem 0.5rem",
+                border: "1px solid var(--color-border)",
+                borderRadius: "6px",
+              }}
+            >
+              {intervalOptions.map((o) => (
+                <option key={o.value} value={o.value}>
+                  {t(locale, o.labelKey)}
+                </option>
+              ))}
+            </select>
+          </label>
+        )}
+        {donationType === "monthly" &&
+          billingInterval !== "month" &&
+          typeof selectedAmount === "number" && (
+            <p
+              style={{
+                margin: "0.25rem 0 0",
+                fontSize: "0.85rem",
+                color: "var(--color-text-muted)",
+              }}
+            >
+              {t(locale, "projects.monthlyEquivalent", {
+                amount: Math.floor(
+                  selectedAmount /
+                    (intervalOptions.find((o) => o.value === billingInterval)
+                      ?.months ?? 1),
+                ).toLocaleString(),
+              })}
+            </p>
+          )}
       </div>
+      {tiers.length > 0 && (
+        <div style={{ marginBottom: "1rem" }}>
+          <p
+            style={{
+              margin: "0 0 0.5rem",
+              fontSize: "0.95rem",
+              fontWeight: 500,
+            }}
+          >
+            {t(locale, "projects.donationTiers")}
+          </p>
+          <div style={{ display: "grid", gap: "0.5rem" }}>
+            {tiers.map((tier) => {
+              const selected = selectedTier?.id === tier.id;
+              const disabled = tier.is_recurring && !isLoggedIn;
+              return (
+                <button
+                  key={tier.id}
+                  type="button"
+                  className={`btn ${selected ? "btn-primary" : "btn-outline"}`}
+                  disabled={disabled}
+                  onClick={() => selectTier(tier)}
+                  style={{
+                    display: "block",
+                    textAlign: "left",
+                    opacity: disabled ? 0.5 : 1,
+                    ...(!selected && {
+                      backgroundColor: "transparent",
+                      border: "1px solid var(--color-primary)",
+                      color: "var(--color-primary)",
+                    }),
+                  }}
+                >
+                  <strong>{tier.label}</strong>{" "}
+                  <span>
+                    ¥{tier.amount.toLocaleString()}
+                    {tier.is_recurring &&
+                      " / " +
+                        t(
+                          locale,
+                          intervalOptions.find(
+                            (o) => o.value === (tier.interval ?? "month"),
+                          )?.labelKey ?? "projects.intervalMonth",
+                        )}
+                  </span>
+                  {tier.description && (
+                    <span
+                      style={{
+                        display: "block",
+                        fontSize: "0.85rem",
+                        fontWeight: "normal",
+                      }}
+                    >
+                      {tier.description}
+                    </span>
+                  )}
+                </button>
+              );
+            })}
+          </div>
+        </div>
+      )}
       <div style={{ marginBottom: "1rem" }}>
         <p
           style={{ margin: "0 0 0.5rem", fontSize: "0.95rem", fontWeight: 500 }}
@@ -201,15 +355,18 @@ export default function DonateForm({
             <button
               key={amount}
               type="button"
-              className={`btn ${selectedAmount === amount ? "btn-primary" : "btn-outline"}`}
+              className={`btn ${!selectedTier && selectedAmount === amount ? "btn-primary" : "btn-outline"}`}
               style={{
-                ...(selectedAmount !== amount && {
+                ...((selectedTier || selectedAmount !== amount) && {
                   backgroundColor: "transparent",
                   border: "1px solid var(--color-primary)",
                   color: "var(--color-primary)",
                 }),
               }}
-              onClick={() => setSelectedAmount(amount)}
+              onClick={() => {
+                setSelectedAmount(amount);
+                setSelectedTier(null);
+              }}
             >
               ¥{amount.toLocaleString()}
             </button>
@@ -224,7 +381,10 @@ export default function DonateForm({
                 color: "var(--color-primary)",
               }),
             }}
-            onClick={() => setSelectedAmount("custom")}
+            onClick={() => {
+              setSelectedAmount("custom");
+              setSelectedTier(null);
+            }}
           >
             {customAmountLabel}
           </button>
@@ -247,6 +407,32 @@ export default function DonateForm({
           </div>
         )}
       </div>
+      <div style={{ marginBottom: "1rem" }}>
+        <label
+          style={{
+            display: "flex",
+            alignItems: "center",
+            gap: "0.5rem",
+            cursor: "pointer",
+          }}
+        >
+          <input
+            type="checkbox"
+            checked={coverFees}
+            onChange={(e) => setCoverFees(e.target.checked)}
+          />
+          <span>{t(locale, "projects.coverFees")}</span>
+        </label>
+        <p
+          style={{
+            margin: "0.25rem 0 0",
+            fontSize: "0.85rem",
+            color: "var(--color-text-muted)",
+          }}
+        >
+          {t(locale, "projects.coverFeesHint")}
+        </p>
+      </div>
       <div style={{ marginBottom: "1rem" }}>
         <label
           htmlFor="donate-message"
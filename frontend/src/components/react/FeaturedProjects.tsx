@@ -4,6 +4,7 @@ import {
   getNewProjects,
   getHotProjects,
   PLATFORM_PROJECT_ID,
+  projectPathSegment,
 } from "../../lib/api";
 import type { Locale } from "../../lib/i18n";
 import LoadingSkeleton from "./LoadingSkeleton";
@@ -45,7 +46,7 @@ function ProjectCard({
   const rate = achievementRate(project);
   return (
     <a
-      href={`${basePath}/projects/${project.id}`}
+      href={`${basePath}/projects/${projectPathSegment(project)}`}
       className="card project-card"
       style={{ display: "block", textDecoration: "none", color: "inherit" }}
     >
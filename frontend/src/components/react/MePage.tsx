@@ -11,6 +11,8 @@ import {
   pauseRecurringDonation,
   resumeRecurringDonation,
   deleteRecurringDonation,
+  createPaymentMethodSession,
+  projectPathSegment,
   type User,
   type Donation,
   type RecurringDonation,
@@ -35,6 +37,9 @@ interface Props {
   resumeRecurringLabel: string;
   deleteRecurringLabel: string;
   pausedLabel: string;
+  paymentPastDueLabel: string;
+  paymentUnpaidLabel: string;
+  updatePaymentMethodLabel: string;
   intervalMonthlyLabel: string;
   intervalYearlyLabel: string;
   intervalLabel: string;
@@ -53,6 +58,8 @@ interface Props {
   statusActiveLabel: string;
   statusFrozenLabel: string;
   statusDeletedLabel: string;
+  stripeActionRequiredLabel: string;
+  stripeResumeOnboardingLabel: string;
   noProjectsLabel: string;
   loginPromptLabel: string;
   loadingLabel: string;
@@ -93,6 +100,9 @@ export default function MePage({
   resumeRecurringLabel,
   deleteRecurringLabel,
   pausedLabel,
+  paymentPastDueLabel,
+  paymentUnpaidLabel,
+  updatePaymentMethodLabel,
   intervalMonthlyLabel,
   intervalYearlyLabel,
   intervalLabel,
@@ -111,6 +121,8 @@ export default function MePage({
   statusActiveLabel,
   statusFrozenLabel,
   statusDeletedLabel,
+  stripeActionRequiredLabel,
+  stripeResumeOnboardingLabel,
   noProjectsLabel,
   loginPromptLabel,
   loadingLabel,
@@ -197,7 +209,7 @@ export default function MePage({
   const handleStartEditRecurring = (r: RecurringDonation) => {
     setEditingRecurringId(r.id);
     setEditRecurringAmount(r.amount);
-    setEditRecurringInterval(r.interval ?? "monthly");
+    setEditRecurringInterval(r.interval === "yearly" ? "yearly" : "monthly");
     setEditRecurringNextMsg(r.next_billing_message ?? "");
   };
 
@@ -233,6 +245,15 @@ export default function MePage({
     }
   };
 
+  const handleUpdatePaymentMethod = async (id: string) => {
+    try {
+      const { url } = await createPaymentMethodSession(id);
+      window.location.href = url;
+    } catch (e) {
+      // ignore
+    }
+  };
+
   const handleConfirmDeleteRecurring = async () => {
     const id = deleteConfirmRecurringId;
     if (!id) return;
@@ -269,8 +290,13 @@ export default function MePage({
   }
 
   const formatRecurringAmount = (r: RecurringDonation) => {
-    const interval = r.interval ?? "monthly";
-    const suffix = interval === "yearly" ? "/年" : "/月";
+    const suffixes = {
+      monthly: "/月",
+      quarterly: "/3か月",
+      half_yearly: "/半年",
+      yearly: "/年",
+    };
+    const suffix = suffixes[r.interval ?? "monthly"];
     return `¥${r.amount.toLocaleString()}${suffix}`;
   };
 
@@ -577,6 +603,21 @@ export default function MePage({
                                 ({pausedLabel})
                               </span>
                             )}
+                            {(r.payment_status === "past_due" ||
+                              r.payment_status === "unpaid") && (
+                              <span
+                                style={{
+                                  marginLeft: "0.5rem",
+                                  color: "var(--color-danger)",
+                                }}
+                              >
+                                (
+                                {r.payment_status === "past_due"
+                                  ? paymentPastDueLabel
+                                  : paymentUnpaidLabel}
+                                )
+                              </span>
+                            )}
                           </span>
                           {r.status !== "cancelled" && (
                             <span
@@ -586,6 +627,17 @@ export default function MePage({
                                 flexWrap: "wrap",
                               }}
                             >
+                              {(r.payment_status === "past_due" ||
+                                r.payment_status === "unpaid") && (
+                                <button
+                                  type="button"
+                                  className="btn btn-primary"
+                                  style={{ fontSize: "0.8rem" }}
+                                  onClick={() => handleUpdatePaymentMethod(r.id)}
+                                >
+                                  {updatePaymentMethodLabel}
+                                </button>
+                              )}
                               <button
                                 type="button"
                                 className="btn"
@@ -661,7 +713,7 @@ export default function MePage({
                   >
                     <div style={{ flex: 1, minWidth: 0 }}>
                       <a
-                        href={`${basePath}/projects/${p.id}`}
+                        href={`${basePath}/projects/${projectPathSegment(p)}`}
                         style={{ fontWeight: 600 }}
                       >
                         {p.name}
@@ -690,6 +742,23 @@ export default function MePage({
                         {!["active", "frozen", "deleted"].includes(p.status) &&
                           p.status}
                       </span>
+                      {p.stripe_connect_url && (
+                        <div
+                          style={{
+                            marginTop: "0.25rem",
+                            fontSize: "0.85rem",
+                            color: "var(--color-danger)",
+                          }}
+                        >
+                          {stripeActionRequiredLabel}
+                          {p.stripe_account &&
+                            p.stripe_account.requirements.length > 0 &&
+                            ` (${p.stripe_account.requirements.join(", ")})`}{" "}
+                          <a href={p.stripe_connect_url}>
+                            {stripeResumeOnboardingLabel}
+                          </a>
+                        </div>
+                      )}
                     </div>
                     <div
                       style={{
@@ -757,7 +826,7 @@ export default function MePage({
                     }}
                   >
                     <a
-                      href={`${basePath}/projects/${p.id}`}
+                      href={`${basePath}/projects/${projectPathSegment(p)}`}
                       style={{ fontWeight: 500 }}
                     >
                       {p.name}
@@ -7,6 +7,9 @@ import type {
   CostItem,
   RecurringDonation,
   DonationMessage,
+  DonationTierStat,
+  CheckoutStats,
+  MonthlyFinance,
 } from "../../lib/api";
 import {
   getProject,
@@ -16,6 +19,9 @@ import {
   getWatchedProjects,
   getMyRecurringDonations,
   getProjectMessages,
+  getCheckoutSession,
+  getCheckoutStats,
+  getProjectFinances,
   watchProject,
   unwatchProject,
   updateProject,
@@ -24,6 +30,7 @@ import {
   uploadProjectImage,
   deleteProjectImage,
   PLATFORM_PROJECT_ID,
+  projectPathSegment,
 } from "../../lib/api";
 import DonateForm from "./DonateForm";
 import SubscriptionManageForm from "./SubscriptionManageForm";
@@ -195,6 +202,10 @@ export default function ProjectDetail({
   // Messages state (owner-only)
   const [messages, setMessages] = useState<DonationMessage[]>([]);
   const [messagesTotal, setMessagesTotal] = useState(0);
+  const [tierStats, setTierStats] = useState<DonationTierStat[]>([]);
+  const [checkoutStats, setCheckoutStats] = useState<CheckoutStats | null>(
+    null,
+  );
   const [messagesLoading, setMessagesLoading] = useState(false);
   const [messagesDonorFilter, setMessagesDonorFilter] = useState("");
   const [messagesOffset, setMessagesOffset] = useState(0);
@@ -210,8 +221,19 @@ export default function ProjectDetail({
   const [ownerWantDraft, setOwnerWantDraft] = useState(0);
   const [savingOwnerWant, setSavingOwnerWant] = useState(false);
 
+  // Donation status after returning from Stripe checkout
+  const [checkoutState, setCheckoutState] = useState<
+    | "processing"
+    | "recorded"
+    | "expired"
+    | "delayed"
+    | "awaitingPayment"
+    | null
+  >(null);
+
   // Refresh key for chart re-fetch
   const [refreshKey, setRefreshKey] = useState(0);
+  const [finances, setFinances] = useState<MonthlyFinance[]>([]);
 
   // Image upload state (owner-only)
   const [showImageDropZone, setShowImageDropZone] = useState(false);
@@ -253,24 +275,60 @@ export default function ProjectDetail({
       .catch(() => setActiveSubscription(null));
   }, [me?.id, project?.id]);
 
-  // Auto-refresh after returning from Stripe checkout
+  // Auto-refresh after returning from Stripe checkout.
+  // With a session_id, poll until the webhook has recorded the donation.
   useEffect(() => {
     const params = new URLSearchParams(window.location.search);
-    if (params.has("donated") || params.has("session_id")) {
-      const timer = setTimeout(() => {
-        getProject(id)
-          .then((p) => {
-            setProject(p);
-            setRefreshKey((k) => k + 1);
-          })
-          .catch(() => {});
-      }, 1000);
-      const url = new URL(window.location.href);
-      url.searchParams.delete("donated");
-      url.searchParams.delete("session_id");
-      window.history.replaceState({}, "", url.toString());
+    if (!params.has("donated") && !params.has("session_id")) return;
+    const sessionId = params.get("session_id");
+    const url = new URL(window.location.href);
+    url.searchParams.delete("donated");
+    url.searchParams.delete("session_id");
+    window.history.replaceState({}, "", url.toString());
+
+    const refresh = () =>
+      getProject(id)
+        .then((p) => {
+          setProject(p);
+          setRefreshKey((k) => k + 1);
+        })
+        .catch(() => {});
+    if (!sessionId) {
+      const timer = setTimeout(refresh, 1000);
       return () => clearTimeout(timer);
     }
+
+    const POLL_INTERVAL_MS = 2000;
+    const MAX_POLLS = 15;
+    let polls = 0;
+    let timer: ReturnType<typeof setTimeout> | undefined;
+    setCheckoutState("processing");
+    const poll = () => {
+      polls++;
+      getCheckoutSession(sessionId)
+        .then((cs) => {
+          if (cs.status === "expired") {
+            setCheckoutState("expired");
+          } else if (cs.donation_recorded) {
+            setCheckoutState("recorded");
+            refresh();
+          } else if (cs.payment_status === "pending") {
+            // コンビニ払い・銀行振込: 入金されるまで寄付額には反映されない
+            setCheckoutState("awaitingPayment");
+          } else if (polls >= MAX_POLLS) {
+            setCheckoutState("delayed");
+          } else {
+            timer = setTimeout(poll, POLL_INTERVAL_MS);
+          }
+        })
+        .catch(() => {
+          // 未記録の Session など: 従来どおりプロジェクトだけ再取得する
+          setCheckoutState(null);
+          refresh();
+        });
+    };
+    poll();
+    return () => clearTimeout(timer);
   }, [id]);
 
   useEffect(() => {
@@ -313,14 +371,32 @@ export default function ProjectDetail({
       .then((res) => {
         setMessages(res.messages);
         setMessagesTotal(res.total);
+        setTierStats(res.tiers ?? []);
       })
       .catch(() => {
         setMessages([]);
         setMessagesTotal(0);
+        setTierStats([]);
       })
       .finally(() => setMessagesLoading(false));
   }, [isOwner, activeTab, project?.id, messagesOffset, messagesDonorFilter]);
 
+  // Fetch Stripe fees and payouts per month for the support tab
+  useEffect(() => {
+    if (activeTab !== "support" || !project) return;
+    getProjectFinances(project.id)
+      .then(setFinances)
+      .catch(() => setFinances([]));
+  }, [activeTab, project?.id, refreshKey]);
+
+  // Fetch checkout abandonment stats when owner views the messages tab
+  useEffect(() => {
+    if (!isOwner || activeTab !== "messages" || !project) return;
+    getCheckoutStats(project.id)
+      .then(setCheckoutStats)
+      .catch(() => setCheckoutStats(null));
+  }, [isOwner, activeTab, project?.id]);
+
   useEffect(() => {
     if (project && !editingOverview) {
       setOverviewDraft(project.overview ?? project.description ?? "");
@@ -1447,14 +1523,99 @@ export default function ProjectDetail({
                   rate: String(achievementRate),
                 })}
               </p>
+              {project.matching_campaigns?.map((c) => (
+                <div
+                  key={c.id}
+                  className="matching-campaign"
+                  style={{
+                    margin: "0.75rem 0",
+                    padding: "0.5rem 0.75rem",
+                    borderLeft: "3px solid #e0a030",
+                    fontSize: "0.9rem",
+                  }}
+                >
+                  <strong>{t(locale, "projects.matchingTitle")}</strong>
+                  <p style={{ margin: "0.25rem 0 0" }}>
+                    {t(locale, "projects.matchingDetail", {
+                      sponsor: c.sponsor_name,
+                      ratio: String(c.ratio_percent),
+                      until: new Date(c.ends_at).toLocaleDateString(
+                        locale === "ja" ? "ja-JP" : "en-US",
+                      ),
+                      cap: c.cap_amount.toLocaleString(),
+                      remaining: Math.max(
+                        c.cap_amount - c.matched_amount,
+                        0,
+                      ).toLocaleString(),
+                    })}
+                  </p>
+                </div>
+              ))}
               <ProjectChart
                 projectId={project.id}
                 minAmountLabel={chartMinAmountLabel}
                 targetAmountLabel={chartTargetAmountLabel}
                 actualAmountLabel={chartActualAmountLabel}
+                matchedAmountLabel={t(locale, "projects.chartMatchedAmount")}
                 noDataLabel={chartNoDataLabel}
                 refreshKey={refreshKey}
               />
+              {finances.length > 0 && (
+                <div style={{ marginTop: "1.5rem", overflowX: "auto" }}>
+                  <h3 style={{ fontSize: "1rem", margin: "0 0 0.5rem" }}>
+                    {t(locale, "projects.financesTitle")}
+                  </h3>
+                  <table
+                    style={{
+                      width: "100%",
+                      borderCollapse: "collapse",
+                      fontSize: "0.85rem",
+                      textAlign: "right",
+                    }}
+                  >
+                    <thead>
+                      <tr>
+                        <th style={{ textAlign: "left" }}>
+                          {t(locale, "projects.financesMonth")}
+                        </th>
+                        <th>{t(locale, "projects.financesGross")}</th>
+                        <th>{t(locale, "projects.financesFees")}</th>
+                        <th>{t(locale, "projects.financesNet")}</th>
+                        <th>{t(locale, "projects.financesPayouts")}</th>
+                      </tr>
+                    </thead>
+                    <tbody>
+                      {finances.map((f) => (
+                        <tr key={`${f.month}-${f.currency}`}>
+                          <td style={{ textAlign: "left" }}>
+                            {f.month}
+                            {f.currency !== "jpy" &&
+                              ` (${f.currency.toUpperCase()})`}
+                          </td>
+                          <td>{f.gross.toLocaleString()}</td>
+                          <td>
+                            {f.fees.toLocaleString()}
+                            {f.unsynced_payments > 0 && "*"}
+                          </td>
+                          <td>{f.net.toLocaleString()}</td>
+                          <td>{f.payouts.toLocaleString()}</td>
+                        </tr>
+                      ))}
+                    </tbody>
+                  </table>
+                  {finances.some((f) => f.unsynced_payments > 0) && (
+                    <p
+                      style={{
+                        fontSize: "0.8rem",
+                        color: "var(--color-text-muted)",
+                        margin: "0.25rem 0 0",
+                      }}
+                    >
+                      {t(locale, "projects.financesUnsynced")}
+                    </p>
+                  )}
+                </div>
+              )}
             </div>
 
             {project.recent_supporters &&
@@ -1481,6 +1642,25 @@ export default function ProjectDetail({
 
             <div ref={donationSectionRef} className="card accent-line">
               <h2 style={{ marginTop: 0 }}>{supportTitle}</h2>
+              {checkoutState && (
+                <p
+                  role="status"
+                  style={{
+                    margin: "0 0 1rem",
+                    padding: "0.75rem 1rem",
+                    borderRadius: "8px",
+                    fontSize: "0.95rem",
+                    background: "var(--color-bg-subtle)",
+                    border: `1px solid ${
+                      checkoutState === "recorded"
+                        ? "var(--color-primary)"
+                        : "var(--color-border)"
+                    }`,
+                  }}
+                >
+                  {t(locale, `projects.checkoutStatus.${checkoutState}`)}
+                </p>
+              )}
               {activeSubscription ? (
                 <SubscriptionManageForm
                   locale={locale}
@@ -1508,6 +1688,7 @@ export default function ProjectDetail({
                   donationTypeLabel={donationTypeLabel}
                   user={me}
                   projectStatus={project.status}
+                  tiers={project.donation_tiers ?? []}
                 />
               )}
             </div>
@@ -1756,6 +1937,91 @@ export default function ProjectDetail({
 
         {activeTab === "messages" && isOwner && (
           <div className="card" style={{ padding: "1.5rem" }}>
+            {checkoutStats && checkoutStats.started > 0 && (
+              <div style={{ marginBottom: "1.5rem" }}>
+                <h3 style={{ marginTop: 0, fontSize: "1rem" }}>
+                  {t(locale, "projects.checkoutStats")}
+                </h3>
+                <p style={{ margin: 0, fontSize: "0.9rem" }}>
+                  {t(locale, "projects.checkoutStatsSummary", {
+                    started: String(checkoutStats.started),
+                    completed: String(checkoutStats.completed),
+                    expired: String(checkoutStats.expired),
+                    open: String(checkoutStats.open),
+                  })}
+                </p>
+                <p style={{ margin: "0.25rem 0 0", fontSize: "0.9rem" }}>
+                  {t(locale, "projects.checkoutAbandonmentRate", {
+                    rate: String(
+                      Math.round(checkoutStats.abandonment_rate * 100),
+                    ),
+                    amount: checkoutStats.abandoned_amount.toLocaleString(),
+                  })}
+                </p>
+              </div>
+            )}
+            {tierStats.length > 0 && (
+              <div style={{ marginBottom: "1.5rem" }}>
+                <h3 style={{ marginTop: 0, fontSize: "1rem" }}>
+                  {t(locale, "projects.tierBreakdown")}
+                </h3>
+                <table
+                  style={{
+                    width: "100%",
+                    borderCollapse: "collapse",
+                    fontSize: "0.9rem",
+                  }}
+                >
+                  <thead>
+                    <tr
+                      style={{
+                        borderBottom: "2px solid var(--color-border)",
+                        textAlign: "left",
+                      }}
+                    >
+                      <th style={{ padding: "0.5rem" }}>
+                        {t(locale, "projects.donationTierLabel")}
+                      </th>
+                      <th style={{ padding: "0.5rem" }}>
+                        {t(locale, "projects.tierDonations")}
+                      </th>
+                      <th style={{ padding: "0.5rem" }}>
+                        {t(locale, "projects.tierActiveRecurring")}
+                      </th>
+                      <th style={{ padding: "0.5rem" }}>
+                        {t(locale, "projects.tierPaidAmount")}
+                      </th>
+                    </tr>
+                  </thead>
+                  <tbody>
+                    {tierStats.map((st) => (
+                      <tr
+                        key={st.tier_id}
+                        style={{
+                          borderBottom: "1px solid var(--color-border-light)",
+                          color: st.removed
+                            ? "var(--color-text-muted)"
+                            : undefined,
+                        }}
+                      >
+                        <td style={{ padding: "0.5rem" }}>
+                          {st.removed
+                            ? t(locale, "projects.tierRemoved")
+                            : `${st.label}（¥${(st.amount ?? 0).toLocaleString()}）`}
+                        </td>
+                        <td style={{ padding: "0.5rem" }}>{st.donations}</td>
+                        <td style={{ padding: "0.5rem" }}>
+                          {st.is_recurring ? st.active_recurring : "—"}
+                        </td>
+                        <td style={{ padding: "0.5rem" }}>
+                          ¥{st.paid_amount.toLocaleString()}
+                        </td>
+                      </tr>
+                    ))}
+                  </tbody>
+                </table>
+              </div>
+            )}
             <div style={{ marginBottom: "1rem" }}>
               <input
                 type="text"
@@ -1830,6 +2096,65 @@ export default function ProjectDetail({
                         </td>
                         <td style={{ padding: "0.5rem" }}>
                           ¥{msg.amount.toLocaleString()}
+                          {msg.refunded_amount > 0 && (
+                            <div
+                              style={{
+                                fontSize: "0.75rem",
+                                color: "var(--color-danger)",
+                              }}
+                            >
+                              {t(locale, "projects.messagesRefunded", {
+                                amount: `¥${msg.refunded_amount.toLocaleString()}`,
+                              })}
+                            </div>
+                          )}
+                          {(msg.payment_status === "past_due" ||
+                            msg.payment_status === "unpaid") && (
+                            <div
+                              style={{
+                                fontSize: "0.75rem",
+                                color: "var(--color-danger)",
+                              }}
+                            >
+                              {t(
+                                locale,
+                                msg.payment_status === "past_due"
+                                  ? "projects.messagesPaymentPastDue"
+                                  : "projects.messagesPaymentUnpaid",
+                              )}
+                            </div>
+                          )}
+                          {(msg.payment_status === "pending" ||
+                            msg.payment_status === "failed") && (
+                            <div
+                              style={{
+                                fontSize: "0.75rem",
+                                color:
+                                  msg.payment_status === "failed"
+                                    ? "var(--color-danger)"
+                                    : "var(--color-text-muted)",
+                              }}
+                            >
+                              {t(
+                                locale,
+                                msg.payment_status === "pending"
+                                  ? "projects.messagesPaymentPending"
+                                  : "projects.messagesPaymentFailed",
+                              )}
+                            </div>
+                          )}
+                          {msg.dispute_status && (
+                            <div
+                              style={{
+                                fontSize: "0.75rem",
+                                color: "var(--color-danger)",
+                              }}
+                            >
+                              {t(locale, "projects.messagesDisputed", {
+                                status: msg.dispute_status,
+                              })}
+                            </div>
+                          )}
                         </td>
                         <td
                           style={{
@@ -1925,7 +2250,7 @@ export default function ProjectDetail({
               return (
                 <li key={p.id}>
                   <a
-                    href={`${basePath}/projects/${p.id}`}
+                    href={`${basePath}/projects/${projectPathSegment(p)}`}
                     style={{
                       display: "block",
                       padding: "0.5rem 0",
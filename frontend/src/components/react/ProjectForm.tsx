@@ -1,5 +1,12 @@
 import { useState, useRef } from "react";
-import type { Project, CostItem, ProjectAlerts } from "../../lib/api";
+import type {
+  Project,
+  CostItem,
+  ProjectAlerts,
+  DonationTier,
+  DonationInterval,
+  CheckoutMethod,
+} from "../../lib/api";
 import {
   createProject,
   updateProject,
@@ -40,6 +47,22 @@ function initCostItems(project?: Project | null): CostItem[] {
   return [emptyCostItem()];
 }
 
+const emptyDonationTier = (): DonationTier => ({
+  label: "",
+  amount: 0,
+  is_recurring: false,
+});
+
+/** 目安額の種類（単発 or 定期の請求間隔）のセレクト値 */
+const tierKinds: { value: "one_time" | DonationInterval; labelKey: string }[] =
+  [
+    { value: "one_time", labelKey: "projects.donationTierOneTime" },
+    { value: "month", labelKey: "projects.intervalMonth" },
+    { value: "quarter", labelKey: "projects.intervalQuarter" },
+    { value: "half_year", labelKey: "projects.intervalHalfYear" },
+    { value: "year", labelKey: "projects.intervalYear" },
+  ];
+
 export default function ProjectForm({ locale, project, redirectPath }: Props) {
   const isEdit = !!project;
 
@@ -66,6 +89,20 @@ export default function ProjectForm({ locale, project, redirectPath }: Props) {
     project?.alerts ?? defaultAlerts,
   );
 
+  // 寄付の目安額（id は既存のものを送り返して寄付との対応を保つ）
+  const [donationTiers, setDonationTiers] = useState<DonationTier[]>(
+    project?.donation_tiers ?? [],
+  );
+
+  // カード以外の支払い方法（コンビニ払い・銀行振込。単発の円建て寄付のみ）
+  const [paymentMethods, setPaymentMethods] = useState<CheckoutMethod[]>(
+    (project?.payment_method_types ?? []).filter((m) => m !== "card"),
+  );
+  const togglePaymentMethod = (method: CheckoutMethod, enabled: boolean) =>
+    setPaymentMethods((prev) =>
+      enabled ? [...prev, method] : prev.filter((m) => m !== method),
+    );
+
   // シェアメッセージ
   const [shareMessage, setShareMessage] = useState(
     project?.share_message ?? "",
@@ -100,6 +137,13 @@ export default function ProjectForm({ locale, project, redirectPath }: Props) {
     });
   };
 
+  // --- donation_tiers 操作 ---
+  const updateDonationTier = (idx: number, patch: Partial<DonationTier>) => {
+    setDonationTiers((prev) =>
+      prev.map((tier, i) => (i === idx ? { ...tier, ...patch } : tier)),
+    );
+  };
+
   const handleImageSelect = (file: File) => {
     setImageError(null);
     if (!ALLOWED_IMAGE_TYPES.includes(file.type)) {
@@ -143,6 +187,10 @@ export default function ProjectForm({ locale, project, redirectPath }: Props) {
         owner_want_monthly: ownerWant > 0 ? ownerWant : null,
         cost_items: validItems.length > 0 ? validItems : null,
         alerts: alertsEnabled ? alerts : null,
+        donation_tiers: donationTiers.filter(
+          (tier) => tier.label.trim() !== "" || tier.amount > 0,
+        ),
+        payment_method_types: paymentMethods,
       };
       if (isEdit) {
         await updateProject(project!.id, payload);
@@ -585,6 +633,171 @@ export default function ProjectForm({ locale, project, redirectPath }: Props) {
         </div>
       </div>
 
+      {/* 寄付の目安額 */}
+      <div
+        style={{
+          marginBottom: "1rem",
+          padding: "1rem",
+          border: "1px solid var(--color-border)",
+          borderRadius: "4px",
+        }}
+      >
+        <h3 style={{ marginTop: 0 }}>{t(locale, "projects.donationTiers")}</h3>
+        <small
+          style={{
+            display: "block",
+            marginBottom: "0.5rem",
+            color: "var(--color-text-muted)",
+          }}
+        >
+          {t(locale, "projects.donationTiersHint")}
+        </small>
+        {donationTiers.map((tier, idx) => (
+          <div
+            key={tier.id ?? `new-${idx}`}
+            style={{
+              marginBottom: "0.75rem",
+              paddingBottom: "0.75rem",
+              borderBottom: "1px solid var(--color-border)",
+            }}
+          >
+            <div style={{ display: "flex", gap: "0.5rem" }}>
+              <input
+                type="text"
+                value={tier.label}
+                maxLength={50}
+                onChange={(e) =>
+                  updateDonationTier(idx, { label: e.target.value })
+                }
+                placeholder={t(locale, "projects.donationTierLabel")}
+                style={{ flex: 2, padding: "0.5rem" }}
+              />
+              <input
+                type="number"
+                min={1}
+                value={tier.amount || ""}
+                onChange={(e) =>
+                  updateDonationTier(idx, {
+                    amount: parseInt(e.target.value, 10) || 0,
+                  })
+                }
+                placeholder="¥"
+                style={{ flex: 1, padding: "0.5rem" }}
+              />
+              <select
+                value={
+                  tier.is_recurring ? (tier.interval ?? "month") : "one_time"
+                }
+                onChange={(e) => {
+                  const v = e.target.value;
+                  updateDonationTier(
+                    idx,
+                    v === "one_time"
+                      ? { is_recurring: false, interval: undefined }
+                      : {
+                          is_recurring: true,
+                          interval: v as DonationInterval,
+                        },
+                  );
+                }}
+                style={{ padding: "0.5rem" }}
+              >
+                {tierKinds.map((k) => (
+                  <option key={k.value} value={k.value}>
+                    {t(locale, k.labelKey)}
+                  </option>
+                ))}
+              </select>
+              <button
+                type="button"
+                onClick={() =>
+                  setDonationTiers((prev) => prev.filter((_, i) => i !== idx))
+                }
+                style={{
+                  padding: "0.5rem",
+                  background: "none",
+                  border: "1px solid var(--color-border)",
+                  borderRadius: "4px",
+                  cursor: "pointer",
+                  lineHeight: 1,
+                }}
+                title="削除"
+              >
+                ✕
+              </button>
+            </div>
+            <input
+              type="text"
+              value={tier.description ?? ""}
+              maxLength={200}
+              onChange={(e) =>
+                updateDonationTier(idx, { description: e.target.value })
+              }
+              placeholder={t(locale, "projects.donationTierDescription")}
+              style={{ width: "100%", padding: "0.5rem", marginTop: "0.5rem" }}
+            />
+          </div>
+        ))}
+        {donationTiers.length < 10 && (
+          <button
+            type="button"
+            onClick={() =>
+              setDonationTiers((prev) => [...prev, emptyDonationTier()])
+            }
+            style={{
+              padding: "0.4rem 0.75rem",
+              background: "none",
+              border: "1px dashed var(--color-border)",
+              borderRadius: "4px",
+              cursor: "pointer",
+              fontSize: "0.9rem",
+            }}
+          >
+            {t(locale, "projects.donationTierAdd")}
+          </button>
+        )}
+      </div>
+
+      {/* 支払い方法 */}
+      <fieldset
+        style={{
+          marginBottom: "1rem",
+          border: "1px solid var(--color-border)",
+          padding: "1rem",
+          borderRadius: "4px",
+        }}
+      >
+        <legend>{t(locale, "projects.paymentMethods")}</legend>
+        <small
+          style={{
+            display: "block",
+            marginBottom: "0.5rem",
+            color: "var(--color-text-muted)",
+          }}
+        >
+          {t(locale, "projects.paymentMethodsHint")}
+        </small>
+        {(
+          [
+            ["konbini", "projects.paymentMethodKonbini"],
+            ["customer_balance", "projects.paymentMethodBankTransfer"],
+          ] as const
+        ).map(([method, labelKey]) => (
+          <label
+            key={method}
+            style={{ display: "block", marginBottom: "0.25rem" }}
+          >
+            <input
+              type="checkbox"
+              checked={paymentMethods.includes(method)}
+              onChange={(e) => togglePaymentMethod(method, e.target.checked)}
+              style={{ marginRight: "0.5rem" }}
+            />
+            {t(locale, labelKey)}
+          </label>
+        ))}
+      </fieldset>
+
       {/* アラート閾値 */}
       <fieldset
         style={{
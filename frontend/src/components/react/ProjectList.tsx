@@ -1,6 +1,10 @@
 import { useEffect, useState } from "react";
 import type { Project } from "../../lib/api";
-import { getProjects, PLATFORM_PROJECT_ID } from "../../lib/api";
+import {
+  getProjects,
+  PLATFORM_PROJECT_ID,
+  projectPathSegment,
+} from "../../lib/api";
 import type { Locale } from "../../lib/i18n";
 import LoadingSkeleton from "./LoadingSkeleton";
 
@@ -50,7 +54,7 @@ export default function ProjectList({ locale, platformBadge }: Props) {
         return (
           <a
             key={project.id}
-            href={`${basePath}/projects/${project.id}`}
+            href={`${basePath}/projects/${projectPathSegment(project)}`}
             className="card project-card"
           >
             <div
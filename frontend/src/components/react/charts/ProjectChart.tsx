@@ -16,6 +16,8 @@ interface Props {
   minAmountLabel: string;
   targetAmountLabel: string;
   actualAmountLabel: string;
+  /** 指定時はマッチング寄付の上乗せ額も表示する */
+  matchedAmountLabel?: string;
   noDataLabel: string;
   refreshKey?: number;
 }
@@ -24,6 +26,7 @@ const COLORS = {
   min: "#d9534f", // 赤に近いオレンジ
   target: "#4a7bc8", // 青系
   actual: "var(--color-primary-light)", // 緑のまま
+  matched: "#e0a030", // 黄系
 };
 
 function formatYAxis(value: number): string {
@@ -40,6 +43,7 @@ export default function ProjectChart({
   minAmountLabel,
   targetAmountLabel,
   actualAmountLabel,
+  matchedAmountLabel,
   noDataLabel,
   refreshKey,
 }: Props) {
@@ -70,6 +74,9 @@ export default function ProjectChart({
     );
   }
 
+  const showMatched =
+    matchedAmountLabel != null && data.some((d) => (d.matchedAmount ?? 0) > 0);
+
   return (
     <div style={{ width: "100%", height: 280, marginTop: "1rem" }}>
       <ResponsiveContainer width="100%" height="100%">
@@ -94,7 +101,9 @@ export default function ProjectChart({
                   ? minAmountLabel
                   : name === "targetAmount"
                     ? targetAmountLabel
-                    : actualAmountLabel;
+                    : name === "matchedAmount"
+                      ? (matchedAmountLabel ?? name)
+                      : actualAmountLabel;
               return [formatTooltip(Number(value)), label];
             }}
             labelFormatter={(label: string) => label.replace("-", "/")}
@@ -110,6 +119,7 @@ export default function ProjectChart({
               if (value === "minAmount") return minAmountLabel;
               if (value === "targetAmount") return targetAmountLabel;
               if (value === "actualAmount") return actualAmountLabel;
+              if (value === "matchedAmount") return matchedAmountLabel ?? value;
               return value;
             }}
           />
@@ -143,6 +153,19 @@ export default function ProjectChart({
             dot={{ r: 4, fill: COLORS.actual }}
             connectNulls
           />
+          {/* マッチング寄付の上乗せ額: 破線（キャンペーンがあった場合のみ） */}
+          {showMatched && (
+            <Line
+              type="monotone"
+              dataKey="matchedAmount"
+              name="matchedAmount"
+              stroke={COLORS.matched}
+              strokeWidth={2}
+              strokeDasharray="4 3"
+              dot={{ r: 3, fill: COLORS.matched }}
+              connectNulls
+            />
+          )}
         </ComposedChart>
       </ResponsiveContainer>
     </div>
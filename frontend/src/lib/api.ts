@@ -324,12 +324,82 @@ export interface Project {
   image_url?: string | null;
   /** プロジェクト概要・詳細説明（2000文字程度、モック/Phase5以降） */
   overview?: string | null;
-  /** Stripe Connect URL（プロジェクト作成直後のみ、一時的） */
+  /** Stripe Connect URL（プロジェクト作成直後、または /api/me/projects で Stripe の手続きが必要な場合。一時的） */
   stripe_connect_url?: string;
+  /** 連結アカウントの状態（/api/me/projects のみ、未同期なら undefined） */
+  stripe_account?: StripeAccountStatus;
   /** 月額目標（cost_items の合計） */
   monthly_target?: number;
   /** SNS シェア時のデフォルトメッセージ */
   share_message?: string;
+  /** 実施中のマッチング寄付キャンペーン（GET /api/projects/:id のみ） */
+  matching_campaigns?: MatchingCampaign[];
+  /** オーナーが設定した寄付の目安額 */
+  donation_tiers?: DonationTier[];
+  /** Checkout で受け付ける支払い方法（省略時はカードのみ） */
+  payment_method_types?: CheckoutMethod[];
+  /** カテゴリの slug（未分類は undefined） */
+  category?: string;
+  /** 正規化済みのタグ（小文字、空白はハイフン） */
+  tags?: string[];
+  /** メンバー（GET /api/projects/:id のみ。作成者を含む） */
+  members?: ProjectMember[];
+  /** URL 用のスラッグ（未設定は undefined） */
+  slug?: string;
+}
+
+/** プロジェクトページの URL に使う値（スラッグがあればスラッグ、なければ ID） */
+export function projectPathSegment(project: Pick<Project, "id" | "slug">): string {
+  return project.slug || project.id;
+}
+
+/** card: カード / konbini: コンビニ払い / customer_balance: 銀行振込 */
+export type CheckoutMethod = "card" | "konbini" | "customer_balance";
+
+/** 寄付の目安額。id は保存時にサーバーが振る（既存のものは送り返す） */
+export interface DonationTier {
+  id?: string;
+  label: string;
+  amount: number;
+  is_recurring: boolean;
+  /** 定期の目安額の請求間隔（省略時は毎月） */
+  interval?: DonationInterval;
+  description?: string;
+}
+
+/** 目安額ごとの寄付数（オーナー向け） */
+export interface DonationTierStat {
+  tier_id: string;
+  /** 削除済みの目安額では空 */
+  label: string;
+  amount?: number;
+  is_recurring: boolean;
+  removed: boolean;
+  donations: number;
+  active_recurring: number;
+  paid_amount: number;
+}
+
+/** スポンサーが期間中の寄付に比率で上乗せするキャンペーン */
+export interface MatchingCampaign {
+  id: string;
+  project_id: string;
+  sponsor_name: string;
+  ratio_percent: number;
+  cap_amount: number;
+  currency: string;
+  starts_at: string;
+  ends_at: string;
+  matched_amount: number;
+}
+
+/** Stripe 連結アカウントの決済・入金可否と未提出の要件 */
+export interface StripeAccountStatus {
+  charges_enabled: boolean;
+  payouts_enabled: boolean;
+  requirements: string[];
+  disabled_reason?: string;
+  updated_at?: string;
 }
 
 /** プロジェクトオーナーからのアップデート（モック/Phase5以降） */
@@ -350,8 +420,55 @@ export interface ProjectListResult {
   next_cursor: string;
 }
 
+/** ホストが管理するプロジェクトのカテゴリ */
+export interface ProjectCategory {
+  slug: string;
+  name: string;
+  sort_order: number;
+}
+
+/** ファセットの値ごとの件数（name はカテゴリのみ） */
+export interface ProjectFacetCount {
+  value: string;
+  name?: string;
+  count: number;
+}
+
+export interface ProjectFacets {
+  categories: ProjectFacetCount[];
+  tags: ProjectFacetCount[];
+}
+
+/** 一覧・ファセットの絞り込み条件（tags はすべてを持つもの） */
+export interface ProjectFilter {
+  category?: string;
+  tags?: string[];
+}
+
+function appendProjectFilter(params: URLSearchParams, filter: ProjectFilter) {
+  if (filter.category) params.set("category", filter.category);
+  for (const tag of filter.tags ?? []) params.append("tag", tag);
+}
+
+export async function getProjectCategories(): Promise<ProjectCategory[]> {
+  if (MOCK_MODE) return [];
+  const res = await fetchApi<{ categories: ProjectCategory[] }>(
+    "/api/project-categories",
+  );
+  return res.categories ?? [];
+}
+
+export async function getProjectFacets(
+  filter: ProjectFilter = {},
+): Promise<ProjectFacets> {
+  if (MOCK_MODE) return { categories: [], tags: [] };
+  const params = new URLSearchParams();
+  appendProjectFilter(params, filter);
+  return fetchApi<ProjectFacets>(`/api/projects/facets?${params}`);
+}
+
 export async function getProjects(
-  options: { limit?: number; cursor?: string; sort?: string } = {},
+  options: { limit?: number; cursor?: string; sort?: string } & ProjectFilter = {},
 ): Promise<ProjectListResult> {
   const { limit = 20, cursor, sort } = options;
   if (MOCK_MODE) {
@@ -364,10 +481,53 @@ export async function getProjects(
   params.set("limit", String(limit));
   if (cursor) params.set("cursor", cursor);
   if (sort) params.set("sort", sort);
+  appendProjectFilter(params, options);
   const res = await fetchApi<ProjectListResult>(`/api/projects?${params}`);
   return { ...res, projects: res.projects ?? [] };
 }
 
+/** 検索結果のプロジェクト。highlight は HTML エスケープ済みで一致箇所を <mark> で囲む */
+export interface ProjectSearchHit extends Project {
+  rank: number;
+  highlight?: { name: string; snippet: string };
+}
+
+export interface ProjectSearchResult {
+  projects: ProjectSearchHit[];
+  next_cursor: string;
+}
+
+export async function searchProjects(
+  q: string,
+  options: { limit?: number; cursor?: string; sort?: "relevance" | "new" | "hot" } = {},
+): Promise<ProjectSearchResult> {
+  const { limit = 20, cursor, sort } = options;
+  if (MOCK_MODE) {
+    const needle = q.trim().toLowerCase();
+    const projects = await (
+      await import("./mock-api")
+    ).mockApi.getProjects(100, 0);
+    const hits = projects
+      .filter((p) =>
+        [p.name, p.overview, p.description].some((t) =>
+          (t ?? "").toLowerCase().includes(needle),
+        ),
+      )
+      .slice(0, limit)
+      .map((p) => ({ ...p, rank: 0 }));
+    return { projects: hits, next_cursor: "" };
+  }
+  const params = new URLSearchParams();
+  params.set("q", q);
+  params.set("limit", String(limit));
+  if (cursor) params.set("cursor", cursor);
+  if (sort) params.set("sort", sort);
+  const res = await fetchApi<ProjectSearchResult>(
+    `/api/projects/search?${params}`,
+  );
+  return { ...res, projects: res.projects ?? [] };
+}
+
 export async function getProject(id: string): Promise<Project> {
   if (MOCK_MODE) return (await import("./mock-api")).mockApi.getProject(id);
   return fetchApi<Project>(`/api/projects/${id}`);
@@ -378,13 +538,171 @@ export async function getMyProjects(): Promise<Project[]> {
   return fetchApi<Project[]>("/api/me/projects");
 }
 
+// --- プロジェクトメンバー ---
+
+/** owner: メンバー管理・削除まで / maintainer: 運営全般 / editor: アップデートと画像のみ */
+export type ProjectRole = "owner" | "maintainer" | "editor";
+
+export interface ProjectMember {
+  project_id: string;
+  user_id: string;
+  user_name: string;
+  role: ProjectRole;
+  created_at: string;
+}
+
+export interface ProjectInvitation {
+  id: string;
+  project_id: string;
+  project_name: string;
+  /** email と user_id のどちらか一方 */
+  email?: string;
+  user_id?: string;
+  role: ProjectRole;
+  invited_by?: string;
+  status: "pending" | "accepted" | "declined" | "revoked";
+  created_at: string;
+  responded_at?: string;
+}
+
+/** メンバー一覧（メンバー・ホスト限定）。保留中の招待は owner とホストにのみ返る */
+export async function getProjectMembers(projectId: string): Promise<{
+  members: ProjectMember[];
+  invitations?: ProjectInvitation[];
+}> {
+  if (MOCK_MODE) return { members: [] };
+  return fetchApi(`/api/projects/${projectId}/members`);
+}
+
+/** メンバーに招待する（owner 限定） */
+export async function inviteProjectMember(
+  projectId: string,
+  input: { email?: string; user_id?: string; role: ProjectRole },
+): Promise<ProjectInvitation> {
+  return fetchApi<ProjectInvitation>(
+    `/api/projects/${projectId}/invitations`,
+    {
+      method: "POST",
+      body: JSON.stringify(input),
+    },
+  );
+}
+
+export async function revokeProjectInvitation(
+  projectId: string,
+  invitationId: string,
+): Promise<void> {
+  await fetchApi(`/api/projects/${projectId}/invitations/${invitationId}`, {
+    method: "DELETE",
+  });
+}
+
+export async function updateProjectMemberRole(
+  projectId: string,
+  userId: string,
+  role: ProjectRole,
+): Promise<void> {
+  await fetchApi(`/api/projects/${projectId}/members/${userId}`, {
+    method: "PATCH",
+    body: JSON.stringify({ role }),
+  });
+}
+
+/** メンバーから外す（owner 限定。自分の user_id なら自分で抜ける） */
+export async function removeProjectMember(
+  projectId: string,
+  userId: string,
+): Promise<void> {
+  await fetchApi(`/api/projects/${projectId}/members/${userId}`, {
+    method: "DELETE",
+  });
+}
+
+/** 更新履歴の 1 項目の変更前後の値（cost_items などは JSON のまま） */
+export interface ProjectFieldChange {
+  from: unknown;
+  to: unknown;
+}
+
+/** プロジェクトの更新履歴（作成時、PUT /api/projects/:id または復元のたびに 1 件） */
+export interface ProjectRevision {
+  id: string;
+  project_id: string;
+  /** 更新したユーザー（退会済みなら undefined） */
+  actor_id?: string;
+  actor_name?: string;
+  /** create = 作成時の内容（changes は空。復元すると作成時の予算に戻る） */
+  action: "create" | "update" | "restore";
+  /** 変更した項目（キーはプロジェクトの JSON のフィールド名） */
+  changes: Record<string, ProjectFieldChange>;
+  /** 復元元の履歴 ID（action が restore の場合） */
+  restored_from?: string;
+  created_at: string;
+}
+
+/** 更新履歴（新しい順。認証不要） */
+export async function getProjectRevisions(
+  projectId: string,
+): Promise<ProjectRevision[]> {
+  if (MOCK_MODE) return [];
+  const data = await fetchApi<{ revisions: ProjectRevision[] }>(
+    `/api/projects/${projectId}/revisions`,
+  );
+  return data.revisions;
+}
+
+/** プロジェクトを履歴の更新後の内容に戻す（owner・ホスト限定） */
+export async function restoreProjectRevision(
+  projectId: string,
+  revisionId: string,
+): Promise<Project> {
+  return fetchApi<Project>(
+    `/api/projects/${projectId}/revisions/${revisionId}/restore`,
+    { method: "POST" },
+  );
+}
+
+/** 自分宛ての保留中の招待 */
+export async function getMyProjectInvitations(): Promise<ProjectInvitation[]> {
+  if (MOCK_MODE) return [];
+  const data = await fetchApi<{ invitations: ProjectInvitation[] }>(
+    "/api/me/project-invitations",
+  );
+  return data.invitations;
+}
+
+export async function acceptProjectInvitation(
+  invitationId: string,
+): Promise<void> {
+  await fetchApi(`/api/me/project-invitations/${invitationId}/accept`, {
+    method: "POST",
+  });
+}
+
+export async function declineProjectInvitation(
+  invitationId: string,
+): Promise<void> {
+  await fetchApi(`/api/me/project-invitations/${invitationId}/decline`, {
+    method: "POST",
+  });
+}
+
 // --- Checkout (Stripe 決済) ---
 
+/** 定期寄付の請求間隔（バックエンドの値） */
+export type DonationInterval = "month" | "quarter" | "half_year" | "year";
+
 export interface CheckoutRequest {
   project_id: string;
   amount: number;
   currency?: string;
   is_recurring: boolean;
+  /** 定期寄付の請求間隔（省略時は毎月） */
+  interval?: DonationInterval;
+  /** 目安額の ID（指定時は amount / is_recurring / interval より優先） */
+  tier_id?: string;
+  /** 寄付者が決済手数料を負担する（請求額に上乗せ） */
+  cover_fees?: boolean;
   message?: string;
   locale?: string;
   donor_token?: string;
@@ -395,7 +713,9 @@ export async function createCheckout(
 ): Promise<{ checkout_url: string }> {
   if (MOCK_MODE) {
     // モック: フェイク URL を返す
-    return { checkout_url: `/projects/${req.project_id}?donated=1` };
+    return {
+      checkout_url: `/projects/${req.project_id}?donated=1&session_id=cs_mock`,
+    };
   }
   return fetchApi<{ checkout_url: string }>("/api/donations/checkout", {
     method: "POST",
@@ -403,6 +723,81 @@ export async function createCheckout(
   });
 }
 
+/** Checkout Session の状態（決済後のポーリング用） */
+export interface CheckoutSessionStatus {
+  id: string;
+  project_id: string;
+  amount: number;
+  currency: string;
+  is_recurring: boolean;
+  interval: DonationInterval | "";
+  tier_id?: string;
+  /** open: 支払い待ち / complete: 支払い完了 / expired: 期限切れ */
+  status: "open" | "complete" | "expired";
+  /** 寄付が記録済みか（complete でも少し遅れることがある。入金待ちは false） */
+  donation_recorded: boolean;
+  /** 記録された寄付の決済状態。コンビニ払い・銀行振込は入金まで pending */
+  payment_status?: PaymentStatus;
+  created_at: string;
+  completed_at?: string;
+  expired_at?: string;
+}
+
+export async function getCheckoutSession(
+  sessionId: string,
+): Promise<CheckoutSessionStatus> {
+  if (MOCK_MODE) {
+    return {
+      id: sessionId,
+      project_id: "",
+      amount: 0,
+      currency: "jpy",
+      is_recurring: false,
+      interval: "",
+      status: "complete",
+      donation_recorded: true,
+      created_at: new Date().toISOString(),
+    };
+  }
+  return fetchApi<CheckoutSessionStatus>(
+    `/api/donations/checkout/${encodeURIComponent(sessionId)}`,
+  );
+}
+
+/** Checkout の完了・放棄の集計（オーナー向け） */
+export interface CheckoutStats {
+  project_id: string;
+  since: string;
+  started: number;
+  completed: number;
+  expired: number;
+  open: number;
+  abandoned_amount: number;
+  /** 期限切れ / (完了 + 期限切れ) */
+  abandonment_rate: number;
+}
+
+export async function getCheckoutStats(
+  projectId: string,
+  days = 30,
+): Promise<CheckoutStats> {
+  if (MOCK_MODE) {
+    return {
+      project_id: projectId,
+      since: new Date(Date.now() - days * 86400000).toISOString(),
+      started: 0,
+      completed: 0,
+      expired: 0,
+      open: 0,
+      abandoned_amount: 0,
+      abandonment_rate: 0,
+    };
+  }
+  return fetchApi<CheckoutStats>(
+    `/api/projects/${projectId}/checkout-stats?days=${days}`,
+  );
+}
+
 // --- Donations (マイページ用、モック時のみ) ---
 
 /** 寄付履歴 */
@@ -426,11 +821,22 @@ export interface RecurringDonation {
   created_at: string;
   status: "active" | "paused" | "cancelled";
   /** 寄付タイミング（月額/年額など） */
-  interval?: "monthly" | "yearly";
+  interval?: "monthly" | "quarterly" | "half_yearly" | "yearly";
   /** 次回決済時にアクティビティに記録されるメッセージ */
   next_billing_message?: string;
+  /** 決済状態（カード期限切れなどで past_due / unpaid になる） */
+  payment_status?: PaymentStatus;
+  /** 解約予定日時（Stripe のカスタマーポータルで解約を予約した場合） */
+  cancel_at?: string;
 }
 
+export type PaymentStatus =
+  | "active"
+  | "past_due"
+  | "unpaid"
+  | "pending"
+  | "failed";
+
 /** バックエンドの Donation レスポンス型 */
 interface BackendDonation {
   id: string;
@@ -441,12 +847,22 @@ interface BackendDonation {
   currency: string;
   message?: string;
   is_recurring: boolean;
+  interval?: DonationInterval;
   paused: boolean;
   next_billing_message?: string;
+  cancel_at?: string;
+  payment_status: PaymentStatus;
   created_at: string;
   updated_at: string;
 }
 
+const recurringIntervals = {
+  month: "monthly",
+  quarter: "quarterly",
+  half_year: "half_yearly",
+  year: "yearly",
+} as const;
+
 export async function getMyDonations(): Promise<Donation[]> {
   if (MOCK_MODE) return (await import("./mock-api")).mockApi.getMyDonations();
   const res = await fetchApi<{ donations: BackendDonation[] }>(
@@ -481,11 +897,76 @@ export async function getMyRecurringDonations(): Promise<RecurringDonation[]> {
       amount: d.amount,
       created_at: d.created_at,
       status: d.paused ? ("paused" as const) : ("active" as const),
-      interval: "monthly" as const,
+      interval: recurringIntervals[d.interval ?? "month"] ?? "monthly",
       next_billing_message: d.next_billing_message,
+      payment_status: d.payment_status,
+      cancel_at: d.cancel_at,
     }));
 }
 
+/** 定期寄付の支払い方法を更新する Stripe のページ URL を取得 */
+export async function createPaymentMethodSession(
+  id: string,
+): Promise<{ url: string }> {
+  if (MOCK_MODE) {
+    // モック: マイページに戻るだけ
+    return { url: "/me" };
+  }
+  return fetchApi<{ url: string }>(`/api/me/donations/${id}/payment-method`, {
+    method: "POST",
+  });
+}
+
+/**
+ * 定期寄付・カードを管理する Stripe カスタマーポータルの URL を取得（404 no_billing_account ならまだ寄付していない）。
+ * ポータルは Stripe アカウントごとなので、projectId を指定するとそのプロジェクトの決済アカウントのポータルを開く
+ */
+export async function createBillingPortalSession(projectId?: string): Promise<{ url: string }> {
+  if (MOCK_MODE) {
+    // モック: マイページに戻るだけ
+    return { url: "/me" };
+  }
+  const query = projectId ? `?project_id=${encodeURIComponent(projectId)}` : "";
+  return fetchApi<{ url: string }>(`/api/me/billing-portal${query}`, {
+    method: "POST",
+  });
+}
+
+/** 定期寄付の変更履歴 */
+export interface DonationChange {
+  id: string;
+  donation_id: string;
+  /** donor = 寄付者がこのサービスで変更 / stripe = ポータル・ダッシュボードで変更 / stripe_automatic = Stripe が自動で変更 */
+  source: "donor" | "stripe" | "stripe_automatic";
+  event_type?: string;
+  changes: Record<string, { from: unknown; to: unknown }>;
+  created_at: string;
+}
+
+/** 自分の定期寄付の変更履歴（古い順） */
+export async function getDonationHistory(
+  id: string,
+): Promise<DonationChange[]> {
+  if (MOCK_MODE) return [];
+  const res = await fetchApi<{ changes: DonationChange[] }>(
+    `/api/me/donations/${id}/history`,
+  );
+  return res.changes;
+}
+
+/** プロジェクトの定期寄付の変更履歴（新しい順、オーナー向け） */
+export async function getProjectDonationChanges(
+  projectId: string,
+  limit = 50,
+  offset = 0,
+): Promise<DonationChange[]> {
+  if (MOCK_MODE) return [];
+  const res = await fetchApi<{ changes: DonationChange[] }>(
+    `/api/projects/${projectId}/donation-changes?limit=${limit}&offset=${offset}`,
+  );
+  return res.changes;
+}
+
 export async function cancelRecurringDonation(id: string): Promise<void> {
   if (MOCK_MODE)
     return (await import("./mock-api")).mockApi.cancelRecurringDonation(id);
@@ -548,11 +1029,17 @@ export interface DonationMessage {
   message: string;
   created_at: string;
   is_recurring: boolean;
+  refunded_amount: number;
+  dispute_status?: string;
+  payment_status?: PaymentStatus;
+  tier_id?: string;
 }
 
 export interface DonationMessageResult {
   messages: DonationMessage[];
   total: number;
+  /** 目安額ごとの内訳 */
+  tiers?: DonationTierStat[];
 }
 
 export async function getProjectMessages(
@@ -644,7 +1131,13 @@ export type ActivityType =
   | "project_created"
   | "project_updated"
   | "donation"
-  | "milestone";
+  | "manual_donation"
+  | "matching"
+  | "pass_through_in"
+  | "pass_through_out"
+  | "milestone"
+  | "refund"
+  | "dispute";
 
 export interface ActivityItem {
   id: string;
@@ -655,6 +1148,7 @@ export interface ActivityItem {
   actor_name: string | null;
   amount?: number;
   rate?: number;
+  message?: string;
 }
 
 export async function getActivityFeed(limit = 10): Promise<ActivityItem[]> {
@@ -673,6 +1167,12 @@ export interface ChartDataPoint {
   minAmount: number;
   targetAmount: number;
   actualAmount: number;
+  /** マッチング寄付キャンペーンによる上乗せ額（actualAmount には含まない） */
+  matchedAmount?: number;
+  /** 下流プロジェクトから分配された支援（actualAmount には含まない） */
+  passThroughInAmount?: number;
+  /** 上流プロジェクトに分配した額（actualAmount の内数） */
+  passThroughOutAmount?: number;
 }
 
 export async function getProjectChart(
@@ -686,6 +1186,99 @@ export async function getProjectChart(
   return data.chart;
 }
 
+// --- Upstream Projects ---
+
+/** 寄付の一部を分配する上流プロジェクト */
+export interface ProjectUpstream {
+  project_id: string;
+  upstream_project_id: string;
+  upstream_project_name: string;
+  share_percent: number;
+  created_at: string;
+}
+
+/** 入金から上流プロジェクトに分配した支援（passed-through support） */
+export interface PassThrough {
+  id: string;
+  payment_id?: string;
+  from_project_id: string;
+  from_project_name: string;
+  to_project_id: string;
+  to_project_name: string;
+  amount: number;
+  currency: string;
+  share_percent: number;
+  status: "pending" | "transferred" | "failed";
+  last_error?: string;
+  paid_at: string;
+  transferred_at?: string;
+  created_at: string;
+}
+
+export async function getProjectUpstreams(
+  projectId: string,
+): Promise<ProjectUpstream[]> {
+  if (MOCK_MODE) return [];
+  const data = await fetchApi<{ upstreams: ProjectUpstream[] }>(
+    `/api/projects/${projectId}/upstreams`,
+  );
+  return data.upstreams;
+}
+
+/** 上流プロジェクトの一覧を置き換える（メンテナー以上・ホスト限定。空配列で分配をやめる） */
+export async function updateProjectUpstreams(
+  projectId: string,
+  upstreams: { upstream_project_id: string; share_percent: number }[],
+): Promise<ProjectUpstream[]> {
+  if (MOCK_MODE) return [];
+  const data = await fetchApi<{ upstreams: ProjectUpstream[] }>(
+    `/api/projects/${projectId}/upstreams`,
+    {
+      method: "PUT",
+      body: JSON.stringify({ upstreams }),
+    },
+  );
+  return data.upstreams;
+}
+
+/** 分配した・受けた支援の一覧（メンテナー以上・ホスト限定、新しい順） */
+export async function getProjectPassThroughs(
+  projectId: string,
+  limit = 50,
+  offset = 0,
+): Promise<PassThrough[]> {
+  if (MOCK_MODE) return [];
+  const data = await fetchApi<{ pass_throughs: PassThrough[] }>(
+    `/api/projects/${projectId}/pass-throughs?limit=${limit}&offset=${offset}`,
+  );
+  return data.pass_throughs;
+}
+
+/** 月別の寄付総額・Stripe 手数料・純額・入金額 */
+export interface MonthlyFinance {
+  month: string;
+  currency: string;
+  gross: number;
+  fees: number;
+  /** gross - fees */
+  net: number;
+  refunded: number;
+  /** その月に銀行口座に着金した入金の合計 */
+  payouts: number;
+  /** 手数料が未取得の決済の件数（0 でなければ fees は実際より少ない） */
+  unsynced_payments: number;
+}
+
+export async function getProjectFinances(
+  projectId: string,
+): Promise<MonthlyFinance[]> {
+  if (MOCK_MODE) return [];
+  const data = await fetchApi<{ finances: MonthlyFinance[] }>(
+    `/api/projects/${projectId}/finances`,
+  );
+  return data.finances;
+}
+
 /** プロジェクトオーナーからのアップデート */
 export async function getProjectUpdates(
   projectId: string,
@@ -769,6 +1362,11 @@ export interface CreateProjectInput {
   owner_want_monthly?: number | null;
   cost_items?: CostItem[] | null;
   alerts?: ProjectAlerts | null;
+  donation_tiers?: DonationTier[];
+  payment_method_types?: CheckoutMethod[];
+  category?: string | null;
+  tags?: string[];
+  slug?: string | null;
 }
 
 export async function createProject(
@@ -792,6 +1390,12 @@ export interface UpdateProjectInput {
   owner_want_monthly?: number | null;
   cost_items?: CostItem[] | null;
   alerts?: ProjectAlerts | null;
+  donation_tiers?: DonationTier[];
+  payment_method_types?: CheckoutMethod[];
+  category?: string | null;
+  tags?: string[];
+  /** null または空文字でスラッグを外す */
+  slug?: string | null;
 }
 
 export async function updateProject(
@@ -315,6 +315,8 @@ export const mockApi = {
       message: string;
       created_at: string;
       is_recurring: boolean;
+      refunded_amount: number;
+      dispute_status?: string;
     }[];
     total: number;
   }> {
@@ -327,6 +329,7 @@ export const mockApi = {
           message: "応援しています！",
           created_at: new Date().toISOString(),
           is_recurring: false,
+          refunded_amount: 0,
         },
         {
           donor_name: "Anonymous",
@@ -334,6 +337,7 @@ export const mockApi = {
           message: "頑張ってください",
           created_at: new Date().toISOString(),
           is_recurring: true,
+          refunded_amount: 0,
         },
       ],
       total: 2,